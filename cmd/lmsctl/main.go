@@ -0,0 +1,169 @@
+// lmsctl là công cụ dòng lệnh cho các tác vụ vận hành, dùng chung cấu hình (.env) với API.
+//
+//	lmsctl export -course <id> -out <file.zip>
+//	lmsctl import -file <file.zip> -instructor <id> [-category <id>] [-dry-run]
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"lms/src/config"
+	"lms/src/db"
+	"lms/src/dto"
+	"lms/src/repository"
+	"lms/src/service"
+	"lms/src/storage"
+	"os"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "export":
+		err = runExport(os.Args[2:])
+	case "import":
+		err = runImport(os.Args[2:])
+	default:
+		usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage:")
+	fmt.Fprintln(os.Stderr, "  lmsctl export -course <id> -out <file.zip>")
+	fmt.Fprintln(os.Stderr, "  lmsctl import -file <file.zip> -instructor <id> [-category <id>] [-dry-run]")
+}
+
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	courseId := fs.Uint("course", 0, "course id")
+	out := fs.String("out", "", "output file (default: <slug>.zip)")
+	fs.Parse(args)
+
+	if *courseId == 0 {
+		return fmt.Errorf("-course is required")
+	}
+
+	packageService, instructorRepo, _, err := initCoursePackageService()
+	if err != nil {
+		return err
+	}
+
+	// Export theo quyền của instructor sở hữu course
+	course, err := instructorRepo.FindCourseById(*courseId)
+	if err != nil {
+		return fmt.Errorf("course %d not found", *courseId)
+	}
+
+	path := *out
+	if path == "" {
+		path = course.Slug + ".zip"
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if _, err := packageService.ExportCourse(course.InstructorId, course.Id, file); err != nil {
+		file.Close()
+		os.Remove(path)
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Exported course %d (%s) to %s\n", course.Id, course.Slug, path)
+	return nil
+}
+
+func runImport(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	path := fs.String("file", "", "course package (.zip)")
+	instructorId := fs.Uint("instructor", 0, "id of the instructor who will own the imported course")
+	categoryId := fs.Uint("category", 0, "category id (default: category in the package, matched by slug)")
+	dryRun := fs.Bool("dry-run", false, "validate the package and report conflicts without importing")
+	fs.Parse(args)
+
+	if *path == "" || *instructorId == 0 {
+		return fmt.Errorf("-file and -instructor are required")
+	}
+
+	packageService, _, userRepo, err := initCoursePackageService()
+	if err != nil {
+		return err
+	}
+
+	user, err := userRepo.FindById(*instructorId)
+	if err != nil {
+		return fmt.Errorf("user %d not found", *instructorId)
+	}
+	if user.Role != "instructor" && user.Role != "admin" {
+		return fmt.Errorf("user %d is not an instructor", *instructorId)
+	}
+
+	file, err := os.Open(*path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	req := &dto.ImportCoursePackageRequest{CategoryId: *categoryId, DryRun: *dryRun}
+	response, err := packageService.ImportCourse(*instructorId, req, file, info.Size())
+	if err != nil {
+		return err
+	}
+
+	output, _ := json.MarshalIndent(response, "", "  ")
+	fmt.Println(string(output))
+
+	if !response.Imported && !response.DryRun {
+		return fmt.Errorf("%s", response.Message)
+	}
+	return nil
+}
+
+func initCoursePackageService() (service.CoursePackageService, repository.InstructorRepository, repository.UserRepository, error) {
+	config.LoadEnv()
+
+	if err := db.InitDB(); err != nil {
+		return nil, nil, nil, err
+	}
+
+	mediaStorage, err := storage.NewBackendFromEnv()
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("unable to init media storage: %w", err)
+	}
+
+	instructorRepo := repository.NewDBInstructorRepository(db.DB)
+	packageService := service.NewCoursePackageService(
+		instructorRepo,
+		repository.NewDBCategoryRepository(db.DB),
+		repository.NewDBCourseRevisionRepository(db.DB),
+		repository.NewDBQuizRepository(db.DB),
+		repository.NewDBAssignmentRepository(db.DB),
+		repository.NewDBLessonResourceRepository(db.DB),
+		repository.NewDBScormRepository(db.DB),
+		mediaStorage,
+	)
+
+	return packageService, instructorRepo, repository.NewDBUserRepository(db.DB), nil
+}
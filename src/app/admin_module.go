@@ -20,20 +20,28 @@ func NewAdminModule() *AdminModule {
 	couponRepo := repository.NewDBCouponRepository(db.DB)
 	enrollmentRepo := repository.NewDBEnrollmentRepository(db.DB)
 	adminAnalyticsRepo := repository.NewDBAdminAnalyticsRepository(db.DB)
+	instructorRepo := repository.NewDBInstructorRepository(db.DB)
+	moderationRepo := repository.NewDBCourseModerationRepository(db.DB)
+	prerequisiteRepo := repository.NewDBCoursePrerequisiteRepository(db.DB)
+	certificateRepo := repository.NewDBCertificateRepository(db.DB)
 
 	// Tạo service chứa business logic
-	adminService := service.NewAdminService(userRepo, courseRepo)
-	orderService := service.NewOrderService(orderRepo, courseRepo, couponRepo, enrollmentRepo)
+	adminService := service.NewAdminService(userRepo, courseRepo, certificateRepo)
+	orderService := service.NewOrderService(orderRepo, courseRepo, couponRepo, enrollmentRepo, prerequisiteRepo)
 	couponService := service.NewCouponService(couponRepo, courseRepo)
 	adminAnalyticsService := service.NewAdminAnalyticsService(adminAnalyticsRepo)
+	moderationService := service.NewCourseModerationService(moderationRepo, instructorRepo, service.NewEmailService())
+	enrollmentManagementService := service.NewEnrollmentManagementService(enrollmentRepo, userRepo, courseRepo, instructorRepo)
 
 	// Tạo handler xử lý HTTP requests
 	adminHandler := handler.NewAdminHandler(adminService, orderService)
 	couponHandler := handler.NewCouponHandler(couponService)
 	adminAnalyticsHandler := handler.NewAdminAnalyticsHandler(adminAnalyticsService)
+	moderationHandler := handler.NewCourseModerationHandler(moderationService)
+	enrollmentManagementHandler := handler.NewEnrollmentManagementHandler(enrollmentManagementService)
 
 	// Tạo routes định nghĩa các endpoint
-	adminRoutes := routes.NewAdminRoutes(adminHandler, couponHandler, adminAnalyticsHandler)
+	adminRoutes := routes.NewAdminRoutes(adminHandler, couponHandler, adminAnalyticsHandler, moderationHandler, enrollmentManagementHandler)
 
 	return &AdminModule{routes: adminRoutes}
 }
@@ -1,10 +1,12 @@
 package app
 
 import (
+	"context"
 	"lms/src/cache"
 	"lms/src/config"
 	"lms/src/db"
 	"lms/src/routes"
+	"lms/src/scheduler"
 	"lms/src/validation"
 	"log"
 
@@ -15,10 +17,16 @@ type Module interface {
 	Routes() routes.Route
 }
 
+// JobModule được implement bởi các module có tác vụ nền chạy định kỳ
+type JobModule interface {
+	Jobs() []scheduler.Job
+}
+
 type Application struct {
-	config  *config.ServerConfig
-	router  *gin.Engine
-	modules []Module // Ds các module
+	config    *config.ServerConfig
+	router    *gin.Engine
+	modules   []Module // Ds các module
+	scheduler *scheduler.Scheduler
 }
 
 func NewApplication(cfg *config.ServerConfig) *Application {
@@ -59,20 +67,38 @@ func NewApplication(cfg *config.ServerConfig) *Application {
 		NewOrderModule(),
 		NewCouponModule(),
 		NewPaymentModule(),
+		NewLearningPathModule(),
+		NewQuizModule(),
+		NewAssignmentModule(),
+		NewLessonResourceModule(),
+		NewMediaModule(),
+		NewLessonSubtitleModule(),
+		NewScormModule(),
+		NewCertificateModule(),
 	}
 
 	// Đăng ký routes cho tất cả modules
 	routes.RegisterRoutes(r, getModuleRoutes(modules)...)
 
+	// Đăng ký background jobs
+	s := scheduler.NewScheduler()
+	s.Register(getModuleJobs(modules)...)
+
 	// Trả về Application instance
 	return &Application{
-		config:  cfg,
-		router:  r,
-		modules: modules,
+		config:    cfg,
+		router:    r,
+		modules:   modules,
+		scheduler: s,
 	}
 }
 
 func (a *Application) Run() error { // a chính là &Application{config: cfg, router: r,}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	a.scheduler.Start(ctx)
+
 	return a.router.Run(a.config.ServerAddress) // Hàm Run này là của Gin
 }
 
@@ -84,3 +110,14 @@ func getModuleRoutes(modules []Module) []routes.Route {
 
 	return routeList
 }
+
+func getModuleJobs(modules []Module) []scheduler.Job {
+	var jobs []scheduler.Job
+	for _, module := range modules {
+		if jobModule, ok := module.(JobModule); ok {
+			jobs = append(jobs, jobModule.Jobs()...)
+		}
+	}
+
+	return jobs
+}
@@ -0,0 +1,46 @@
+package app
+
+import (
+	"lms/src/db"
+	"lms/src/handler"
+	"lms/src/repository"
+	"lms/src/routes"
+	"lms/src/service"
+	"lms/src/storage"
+	"log"
+)
+
+type AssignmentModule struct {
+	routes routes.Route
+}
+
+func NewAssignmentModule() *AssignmentModule {
+	assignmentRepo := repository.NewDBAssignmentRepository(db.DB)
+	scormRepo := repository.NewDBScormRepository(db.DB)
+	certificateRepo := repository.NewDBCertificateRepository(db.DB)
+	activityRepo := repository.NewDBLearningActivityRepository(db.DB)
+	quizRepo := repository.NewDBQuizRepository(db.DB)
+	instructorRepo := repository.NewDBInstructorRepository(db.DB)
+	lessonRepo := repository.NewDBLessonRepository(db.DB)
+	enrollmentRepo := repository.NewDBEnrollmentRepository(db.DB)
+	progressRepo := repository.NewDBProgressRepository(db.DB)
+	courseRepo := repository.NewDBCourseRepository(db.DB)
+
+	submissionStorage, err := storage.NewBackendFromEnv()
+	if err != nil {
+		log.Fatalf("unable to init submission storage: %v", err)
+	}
+
+	progressService := service.NewProgressService(progressRepo, enrollmentRepo, courseRepo, lessonRepo, quizRepo, assignmentRepo, scormRepo, certificateRepo, activityRepo)
+	assignmentService := service.NewAssignmentService(assignmentRepo, instructorRepo, lessonRepo, enrollmentRepo, progressService, submissionStorage)
+
+	assignmentHandler := handler.NewAssignmentHandler(assignmentService)
+
+	assignmentRoutes := routes.NewAssignmentRoutes(assignmentHandler)
+
+	return &AssignmentModule{routes: assignmentRoutes}
+}
+
+func (am *AssignmentModule) Routes() routes.Route {
+	return am.routes
+}
@@ -0,0 +1,27 @@
+package app
+
+import (
+	"lms/src/db"
+	"lms/src/handler"
+	"lms/src/repository"
+	"lms/src/routes"
+	"lms/src/service"
+)
+
+type CertificateModule struct {
+	routes routes.Route
+}
+
+func NewCertificateModule() *CertificateModule {
+	certificateRepo := repository.NewDBCertificateRepository(db.DB)
+
+	certificateService := service.NewCertificateService(certificateRepo)
+	certificateHandler := handler.NewCertificateHandler(certificateService)
+	certificateRoutes := routes.NewCertificateRoutes(certificateHandler)
+
+	return &CertificateModule{routes: certificateRoutes}
+}
+
+func (cm *CertificateModule) Routes() routes.Route {
+	return cm.routes
+}
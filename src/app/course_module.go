@@ -5,11 +5,15 @@ import (
 	"lms/src/handler"
 	"lms/src/repository"
 	"lms/src/routes"
+	"lms/src/scheduler"
 	"lms/src/service"
+	"lms/src/utils"
+	"time"
 )
 
 type CourseModule struct {
-	routes routes.Route
+	routes          routes.Route
+	scheduleService service.PublishScheduleService
 }
 
 func NewCourseModule() *CourseModule {
@@ -17,16 +21,31 @@ func NewCourseModule() *CourseModule {
 	reviewRepo := repository.NewDBReviewRepository(db.DB)
 	enrollmentRepo := repository.NewDBEnrollmentRepository(db.DB)
 
-	courseService := service.NewCourseService(courseRepo)
+	prerequisiteRepo := repository.NewDBCoursePrerequisiteRepository(db.DB)
+
+	courseService := service.NewCourseService(courseRepo, prerequisiteRepo)
 	reviewService := service.NewReviewService(reviewRepo, courseRepo, enrollmentRepo)
+	scheduleService := service.NewPublishScheduleService(repository.NewDBPublishScheduleRepository(db.DB), repository.NewDBProgressRecalculationRepository(db.DB))
 
 	courseHandler := handler.NewCourseHandler(courseService, reviewService)
 
 	courseRoutes := routes.NewCourseRoutes(courseHandler)
 
-	return &CourseModule{routes: courseRoutes}
+	return &CourseModule{routes: courseRoutes, scheduleService: scheduleService}
 }
 
 func (cm *CourseModule) Routes() routes.Route {
 	return cm.routes
 }
+
+func (cm *CourseModule) Jobs() []scheduler.Job {
+	interval := time.Duration(utils.GetEnvInt("PUBLISH_SCHEDULER_INTERVAL_SECONDS", 60)) * time.Second
+
+	return []scheduler.Job{
+		{
+			Name:     "scheduled-publishing",
+			Interval: interval,
+			Run:      cm.scheduleService.RunDueSchedules,
+		},
+	}
+}
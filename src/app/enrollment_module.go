@@ -5,11 +5,15 @@ import (
 	"lms/src/handler"
 	"lms/src/repository"
 	"lms/src/routes"
+	"lms/src/scheduler"
 	"lms/src/service"
+	"lms/src/utils"
+	"time"
 )
 
 type EnrollmentModule struct {
-	routes routes.Route
+	routes            routes.Route
+	enrollmentService service.EnrollmentService
 }
 
 func NewEnrollmentModule() *EnrollmentModule {
@@ -18,16 +22,29 @@ func NewEnrollmentModule() *EnrollmentModule {
 	courseRepo := repository.NewDBCourseRepository(db.DB)
 	couponRepo := repository.NewDBCouponRepository(db.DB)
 	progressRepo := repository.NewDBProgressRepository(db.DB)
+	prerequisiteRepo := repository.NewDBCoursePrerequisiteRepository(db.DB)
 
-	enrollmentService := service.NewEnrollmentService(enrollmentRepo, orderRepo, courseRepo, couponRepo, progressRepo)
+	enrollmentService := service.NewEnrollmentService(enrollmentRepo, orderRepo, courseRepo, couponRepo, progressRepo, prerequisiteRepo)
 
 	enrollmentHandler := handler.NewEnrollmentHandler(enrollmentService)
 
 	enrollmentRoutes := routes.NewEnrollmentRoutes(enrollmentHandler)
 
-	return &EnrollmentModule{routes: enrollmentRoutes}
+	return &EnrollmentModule{routes: enrollmentRoutes, enrollmentService: enrollmentService}
 }
 
 func (em *EnrollmentModule) Routes() routes.Route {
 	return em.routes
 }
+
+func (em *EnrollmentModule) Jobs() []scheduler.Job {
+	interval := time.Duration(utils.GetEnvInt("ENROLLMENT_EXPIRY_INTERVAL_SECONDS", 300)) * time.Second
+
+	return []scheduler.Job{
+		{
+			Name:     "enrollment-expiry",
+			Interval: interval,
+			Run:      em.enrollmentService.ExpireEnrollments,
+		},
+	}
+}
@@ -5,29 +5,106 @@ import (
 	"lms/src/handler"
 	"lms/src/repository"
 	"lms/src/routes"
+	"lms/src/scheduler"
 	"lms/src/service"
+	"lms/src/storage"
+	"lms/src/utils"
+	"log"
+	"time"
 )
 
 type InstructorModule struct {
-	routes routes.Route
+	routes             routes.Route
+	videoUploadService service.VideoUploadService
 }
 
 func NewInstructorModule() *InstructorModule {
 	instructorRepo := repository.NewDBInstructorRepository(db.DB)
 	categoryRepo := repository.NewDBCategoryRepository(db.DB)
 	analyticsRepo := repository.NewDBAnalyticsRepository(db.DB)
+	revisionRepo := repository.NewDBCourseRevisionRepository(db.DB)
+	moderationRepo := repository.NewDBCourseModerationRepository(db.DB)
+	prerequisiteRepo := repository.NewDBCoursePrerequisiteRepository(db.DB)
+	courseRepo := repository.NewDBCourseRepository(db.DB)
+	quizRepo := repository.NewDBQuizRepository(db.DB)
+	assignmentRepo := repository.NewDBAssignmentRepository(db.DB)
+	scormRepo := repository.NewDBScormRepository(db.DB)
+	certificateRepo := repository.NewDBCertificateRepository(db.DB)
+	activityRepo := repository.NewDBLearningActivityRepository(db.DB)
+	resourceRepo := repository.NewDBLessonResourceRepository(db.DB)
+	videoUploadRepo := repository.NewDBVideoUploadRepository(db.DB)
+	subtitleRepo := repository.NewDBLessonSubtitleRepository(db.DB)
+	recalcRepo := repository.NewDBProgressRecalculationRepository(db.DB)
 
-	instructorService := service.NewInstructorService(instructorRepo, categoryRepo)
-	analyticsService := service.NewAnalyticsService(analyticsRepo)
+	mediaStorage, err := storage.NewBackendFromEnv()
+	if err != nil {
+		log.Fatalf("unable to init media storage: %v", err)
+	}
+	lessonRepo := repository.NewDBLessonRepository(db.DB)
+	enrollmentRepo := repository.NewDBEnrollmentRepository(db.DB)
+	progressRepo := repository.NewDBProgressRepository(db.DB)
+	userRepo := repository.NewDBUserRepository(db.DB)
+
+	instructorService := service.NewInstructorService(instructorRepo, categoryRepo, revisionRepo, recalcRepo, mediaStorage)
+	analyticsService := service.NewAnalyticsService(analyticsRepo, instructorRepo, progressRepo)
+	revisionService := service.NewCourseRevisionService(revisionRepo, instructorRepo, categoryRepo)
+	moderationService := service.NewCourseModerationService(moderationRepo, instructorRepo, service.NewEmailService())
+	prerequisiteService := service.NewCoursePrerequisiteService(prerequisiteRepo, instructorRepo, courseRepo)
+	progressService := service.NewProgressService(progressRepo, enrollmentRepo, courseRepo, lessonRepo, quizRepo, assignmentRepo, scormRepo, certificateRepo, activityRepo)
+	quizService := service.NewQuizService(quizRepo, instructorRepo, lessonRepo, enrollmentRepo, progressService)
+	assignmentService := service.NewAssignmentService(assignmentRepo, instructorRepo, lessonRepo, enrollmentRepo, progressService, mediaStorage)
+	resourceService := service.NewLessonResourceService(resourceRepo, instructorRepo, revisionRepo, lessonRepo, enrollmentRepo, mediaStorage)
+	videoUploadService := service.NewVideoUploadService(videoUploadRepo, instructorRepo, revisionRepo, mediaStorage)
+	subtitleService := service.NewLessonSubtitleService(subtitleRepo, instructorRepo, revisionRepo, lessonRepo, enrollmentRepo, mediaStorage)
+	packageService := service.NewCoursePackageService(instructorRepo, categoryRepo, revisionRepo, quizRepo, assignmentRepo, resourceRepo, scormRepo, mediaStorage)
+	enrollmentManagementService := service.NewEnrollmentManagementService(enrollmentRepo, userRepo, courseRepo, instructorRepo)
+	scormService := service.NewScormService(scormRepo, instructorRepo, revisionRepo, lessonRepo, enrollmentRepo, userRepo, progressService, mediaStorage)
 
 	instructorHandler := handler.NewInstructorHandler(instructorService)
 	analyticsHandler := handler.NewAnalyticsHandler(analyticsService)
+	revisionHandler := handler.NewCourseRevisionHandler(revisionService)
+	moderationHandler := handler.NewCourseModerationHandler(moderationService)
+	prerequisiteHandler := handler.NewCoursePrerequisiteHandler(prerequisiteService)
+	quizHandler := handler.NewQuizHandler(quizService)
+	assignmentHandler := handler.NewAssignmentHandler(assignmentService)
+	resourceHandler := handler.NewLessonResourceHandler(resourceService)
+	videoUploadHandler := handler.NewVideoUploadHandler(videoUploadService)
+	subtitleHandler := handler.NewLessonSubtitleHandler(subtitleService)
+	packageHandler := handler.NewCoursePackageHandler(packageService)
+	scormHandler := handler.NewScormHandler(scormService)
+	enrollmentManagementHandler := handler.NewEnrollmentManagementHandler(enrollmentManagementService)
 
-	instructorRoutes := routes.NewInstructorRoutes(instructorHandler, analyticsHandler)
+	instructorRoutes := routes.NewInstructorRoutes(
+		instructorHandler,
+		analyticsHandler,
+		revisionHandler,
+		moderationHandler,
+		prerequisiteHandler,
+		quizHandler,
+		assignmentHandler,
+		resourceHandler,
+		videoUploadHandler,
+		subtitleHandler,
+		packageHandler,
+		scormHandler,
+		enrollmentManagementHandler,
+	)
 
-	return &InstructorModule{routes: instructorRoutes}
+	return &InstructorModule{routes: instructorRoutes, videoUploadService: videoUploadService}
 }
 
 func (im *InstructorModule) Routes() routes.Route {
 	return im.routes
 }
+
+func (im *InstructorModule) Jobs() []scheduler.Job {
+	interval := time.Duration(utils.GetEnvInt("VIDEO_UPLOAD_CLEANUP_INTERVAL_MINUTES", 30)) * time.Minute
+
+	return []scheduler.Job{
+		{
+			Name:     "video-upload-cleanup",
+			Interval: interval,
+			Run:      im.videoUploadService.CleanupAbandonedUploads,
+		},
+	}
+}
@@ -0,0 +1,30 @@
+package app
+
+import (
+	"lms/src/db"
+	"lms/src/handler"
+	"lms/src/repository"
+	"lms/src/routes"
+	"lms/src/service"
+)
+
+type LearningPathModule struct {
+	routes routes.Route
+}
+
+func NewLearningPathModule() *LearningPathModule {
+	learningPathRepo := repository.NewDBLearningPathRepository(db.DB)
+	courseRepo := repository.NewDBCourseRepository(db.DB)
+
+	learningPathService := service.NewLearningPathService(learningPathRepo, courseRepo)
+
+	learningPathHandler := handler.NewLearningPathHandler(learningPathService)
+
+	learningPathRoutes := routes.NewLearningPathRoutes(learningPathHandler)
+
+	return &LearningPathModule{routes: learningPathRoutes}
+}
+
+func (lm *LearningPathModule) Routes() routes.Route {
+	return lm.routes
+}
@@ -15,12 +15,17 @@ type LessonModule struct {
 func NewLessonModule() *LessonModule {
 	lessonRepo := repository.NewDBLessonRepository(db.DB)
 	courseRepo := repository.NewDBCourseRepository(db.DB)
+	enrollmentRepo := repository.NewDBEnrollmentRepository(db.DB)
+	subtitleRepo := repository.NewDBLessonSubtitleRepository(db.DB)
+	noteRepo := repository.NewDBLessonNoteRepository(db.DB)
 
-	lessonService := service.NewLessonService(lessonRepo, courseRepo)
+	lessonService := service.NewLessonService(lessonRepo, courseRepo, enrollmentRepo, subtitleRepo, noteRepo)
+	noteService := service.NewLessonNoteService(noteRepo, lessonRepo, courseRepo, enrollmentRepo)
 
 	lessonHandler := handler.NewLessonHandler(lessonService)
+	noteHandler := handler.NewLessonNoteHandler(noteService)
 
-	lessonRoutes := routes.NewLessonRoutes(lessonHandler)
+	lessonRoutes := routes.NewLessonRoutes(lessonHandler, noteHandler)
 
 	return &LessonModule{routes: lessonRoutes}
 }
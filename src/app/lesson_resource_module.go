@@ -0,0 +1,38 @@
+package app
+
+import (
+	"lms/src/db"
+	"lms/src/handler"
+	"lms/src/repository"
+	"lms/src/routes"
+	"lms/src/service"
+	"lms/src/storage"
+	"log"
+)
+
+type LessonResourceModule struct {
+	routes routes.Route
+}
+
+func NewLessonResourceModule() *LessonResourceModule {
+	resourceRepo := repository.NewDBLessonResourceRepository(db.DB)
+	instructorRepo := repository.NewDBInstructorRepository(db.DB)
+	revisionRepo := repository.NewDBCourseRevisionRepository(db.DB)
+	lessonRepo := repository.NewDBLessonRepository(db.DB)
+	enrollmentRepo := repository.NewDBEnrollmentRepository(db.DB)
+
+	resourceStorage, err := storage.NewBackendFromEnv()
+	if err != nil {
+		log.Fatalf("unable to init resource storage: %v", err)
+	}
+
+	resourceService := service.NewLessonResourceService(resourceRepo, instructorRepo, revisionRepo, lessonRepo, enrollmentRepo, resourceStorage)
+	resourceHandler := handler.NewLessonResourceHandler(resourceService)
+	resourceRoutes := routes.NewLessonResourceRoutes(resourceHandler)
+
+	return &LessonResourceModule{routes: resourceRoutes}
+}
+
+func (rm *LessonResourceModule) Routes() routes.Route {
+	return rm.routes
+}
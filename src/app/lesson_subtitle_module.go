@@ -0,0 +1,38 @@
+package app
+
+import (
+	"lms/src/db"
+	"lms/src/handler"
+	"lms/src/repository"
+	"lms/src/routes"
+	"lms/src/service"
+	"lms/src/storage"
+	"log"
+)
+
+type LessonSubtitleModule struct {
+	routes routes.Route
+}
+
+func NewLessonSubtitleModule() *LessonSubtitleModule {
+	subtitleRepo := repository.NewDBLessonSubtitleRepository(db.DB)
+	instructorRepo := repository.NewDBInstructorRepository(db.DB)
+	revisionRepo := repository.NewDBCourseRevisionRepository(db.DB)
+	lessonRepo := repository.NewDBLessonRepository(db.DB)
+	enrollmentRepo := repository.NewDBEnrollmentRepository(db.DB)
+
+	subtitleStorage, err := storage.NewBackendFromEnv()
+	if err != nil {
+		log.Fatalf("unable to init subtitle storage: %v", err)
+	}
+
+	subtitleService := service.NewLessonSubtitleService(subtitleRepo, instructorRepo, revisionRepo, lessonRepo, enrollmentRepo, subtitleStorage)
+	subtitleHandler := handler.NewLessonSubtitleHandler(subtitleService)
+	subtitleRoutes := routes.NewLessonSubtitleRoutes(subtitleHandler)
+
+	return &LessonSubtitleModule{routes: subtitleRoutes}
+}
+
+func (sm *LessonSubtitleModule) Routes() routes.Route {
+	return sm.routes
+}
@@ -0,0 +1,35 @@
+package app
+
+import (
+	"lms/src/db"
+	"lms/src/handler"
+	"lms/src/repository"
+	"lms/src/routes"
+	"lms/src/service"
+	"lms/src/storage"
+	"log"
+)
+
+type MediaModule struct {
+	routes routes.Route
+}
+
+func NewMediaModule() *MediaModule {
+	lessonRepo := repository.NewDBLessonRepository(db.DB)
+	enrollmentRepo := repository.NewDBEnrollmentRepository(db.DB)
+
+	mediaStorage, err := storage.NewBackendFromEnv()
+	if err != nil {
+		log.Fatalf("unable to init media storage: %v", err)
+	}
+
+	mediaService := service.NewMediaService(lessonRepo, enrollmentRepo, mediaStorage)
+	mediaHandler := handler.NewMediaHandler(mediaService)
+	mediaRoutes := routes.NewMediaRoutes(mediaHandler)
+
+	return &MediaModule{routes: mediaRoutes}
+}
+
+func (mm *MediaModule) Routes() routes.Route {
+	return mm.routes
+}
@@ -17,8 +17,9 @@ func NewOrderModule() *OrderModule {
 	courseRepo := repository.NewDBCourseRepository(db.DB)
 	couponRepo := repository.NewDBCouponRepository(db.DB)
 	enrollmentRepo := repository.NewDBEnrollmentRepository(db.DB)
+	prerequisiteRepo := repository.NewDBCoursePrerequisiteRepository(db.DB)
 
-	orderService := service.NewOrderService(orderRepo, courseRepo, couponRepo, enrollmentRepo)
+	orderService := service.NewOrderService(orderRepo, courseRepo, couponRepo, enrollmentRepo, prerequisiteRepo)
 	couponService := service.NewCouponService(couponRepo, courseRepo)
 
 	orderHandler := handler.NewOrderHandler(orderService, couponService)
@@ -5,11 +5,15 @@ import (
 	"lms/src/handler"
 	"lms/src/repository"
 	"lms/src/routes"
+	"lms/src/scheduler"
 	"lms/src/service"
+	"lms/src/utils"
+	"time"
 )
 
 type ProgressModule struct {
-	routes routes.Route
+	routes        routes.Route
+	recalcService service.ProgressRecalculationService
 }
 
 func NewProgressModule() *ProgressModule {
@@ -17,14 +21,33 @@ func NewProgressModule() *ProgressModule {
 	enrollmentRepo := repository.NewDBEnrollmentRepository(db.DB)
 	courseRepo := repository.NewDBCourseRepository(db.DB)
 	lessonRepo := repository.NewDBLessonRepository(db.DB)
+	quizRepo := repository.NewDBQuizRepository(db.DB)
+	assignmentRepo := repository.NewDBAssignmentRepository(db.DB)
+	scormRepo := repository.NewDBScormRepository(db.DB)
+	certificateRepo := repository.NewDBCertificateRepository(db.DB)
+	activityRepo := repository.NewDBLearningActivityRepository(db.DB)
+	recalcRepo := repository.NewDBProgressRecalculationRepository(db.DB)
 
-	progressService := service.NewProgressService(progressRepo, enrollmentRepo, courseRepo, lessonRepo)
+	progressService := service.NewProgressService(progressRepo, enrollmentRepo, courseRepo, lessonRepo, quizRepo, assignmentRepo, scormRepo, certificateRepo, activityRepo)
+	recalcService := service.NewProgressRecalculationService(recalcRepo, certificateRepo)
 	progressHandler := handler.NewProgressHandler(progressService)
 	progressRoutes := routes.NewProgressRoutes(progressHandler)
 
-	return &ProgressModule{routes: progressRoutes}
+	return &ProgressModule{routes: progressRoutes, recalcService: recalcService}
 }
 
 func (pm *ProgressModule) Routes() routes.Route {
 	return pm.routes
 }
+
+func (pm *ProgressModule) Jobs() []scheduler.Job {
+	interval := time.Duration(utils.GetEnvInt("PROGRESS_RECALC_INTERVAL_SECONDS", 30)) * time.Second
+
+	return []scheduler.Job{
+		{
+			Name:     "progress-recalculation",
+			Interval: interval,
+			Run:      pm.recalcService.RunPending,
+		},
+	}
+}
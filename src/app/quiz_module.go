@@ -0,0 +1,39 @@
+package app
+
+import (
+	"lms/src/db"
+	"lms/src/handler"
+	"lms/src/repository"
+	"lms/src/routes"
+	"lms/src/service"
+)
+
+type QuizModule struct {
+	routes routes.Route
+}
+
+func NewQuizModule() *QuizModule {
+	quizRepo := repository.NewDBQuizRepository(db.DB)
+	assignmentRepo := repository.NewDBAssignmentRepository(db.DB)
+	scormRepo := repository.NewDBScormRepository(db.DB)
+	certificateRepo := repository.NewDBCertificateRepository(db.DB)
+	activityRepo := repository.NewDBLearningActivityRepository(db.DB)
+	instructorRepo := repository.NewDBInstructorRepository(db.DB)
+	lessonRepo := repository.NewDBLessonRepository(db.DB)
+	enrollmentRepo := repository.NewDBEnrollmentRepository(db.DB)
+	progressRepo := repository.NewDBProgressRepository(db.DB)
+	courseRepo := repository.NewDBCourseRepository(db.DB)
+
+	progressService := service.NewProgressService(progressRepo, enrollmentRepo, courseRepo, lessonRepo, quizRepo, assignmentRepo, scormRepo, certificateRepo, activityRepo)
+	quizService := service.NewQuizService(quizRepo, instructorRepo, lessonRepo, enrollmentRepo, progressService)
+
+	quizHandler := handler.NewQuizHandler(quizService)
+
+	quizRoutes := routes.NewQuizRoutes(quizHandler)
+
+	return &QuizModule{routes: quizRoutes}
+}
+
+func (qm *QuizModule) Routes() routes.Route {
+	return qm.routes
+}
@@ -0,0 +1,51 @@
+package app
+
+import (
+	"lms/src/db"
+	"lms/src/handler"
+	"lms/src/repository"
+	"lms/src/routes"
+	"lms/src/service"
+	"lms/src/storage"
+	"lms/src/utils"
+	"log"
+)
+
+type ScormModule struct {
+	routes routes.Route
+}
+
+func NewScormModule() *ScormModule {
+	scormRepo := repository.NewDBScormRepository(db.DB)
+	certificateRepo := repository.NewDBCertificateRepository(db.DB)
+	activityRepo := repository.NewDBLearningActivityRepository(db.DB)
+	instructorRepo := repository.NewDBInstructorRepository(db.DB)
+	revisionRepo := repository.NewDBCourseRevisionRepository(db.DB)
+	lessonRepo := repository.NewDBLessonRepository(db.DB)
+	enrollmentRepo := repository.NewDBEnrollmentRepository(db.DB)
+	userRepo := repository.NewDBUserRepository(db.DB)
+	progressRepo := repository.NewDBProgressRepository(db.DB)
+	courseRepo := repository.NewDBCourseRepository(db.DB)
+	quizRepo := repository.NewDBQuizRepository(db.DB)
+	assignmentRepo := repository.NewDBAssignmentRepository(db.DB)
+
+	scormStorage, err := storage.NewBackendFromEnv()
+	if err != nil {
+		log.Fatalf("unable to init SCORM storage: %v", err)
+	}
+
+	if _, ok := utils.ScormContentOrigin(); !ok {
+		log.Println("⚠️ Warning: SCORM_CONTENT_ORIGIN is not set, SCORM packages run on the API origin")
+	}
+
+	progressService := service.NewProgressService(progressRepo, enrollmentRepo, courseRepo, lessonRepo, quizRepo, assignmentRepo, scormRepo, certificateRepo, activityRepo)
+	scormService := service.NewScormService(scormRepo, instructorRepo, revisionRepo, lessonRepo, enrollmentRepo, userRepo, progressService, scormStorage)
+	scormHandler := handler.NewScormHandler(scormService)
+	scormRoutes := routes.NewScormRoutes(scormHandler)
+
+	return &ScormModule{routes: scormRoutes}
+}
+
+func (sm *ScormModule) Routes() routes.Route {
+	return sm.routes
+}
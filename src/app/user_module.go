@@ -5,25 +5,52 @@ import (
 	"lms/src/handler"
 	"lms/src/repository"
 	"lms/src/routes"
+	"lms/src/scheduler"
 	"lms/src/service"
+	"lms/src/storage"
+	"lms/src/utils"
+	"log"
+	"time"
 )
 
 type UserModule struct {
-	routes routes.Route
+	routes          routes.Route
+	learningService service.LearningStatsService
 }
 
 func NewUserModule() *UserModule {
 	userRepo := repository.NewDBUserRepository(db.DB)
+	certificateRepo := repository.NewDBCertificateRepository(db.DB)
+	activityRepo := repository.NewDBLearningActivityRepository(db.DB)
 
-	userService := service.NewUserService(userRepo)
+	fileStorage, err := storage.NewBackendFromEnv()
+	if err != nil {
+		log.Fatalf("unable to init file storage: %v", err)
+	}
+
+	userService := service.NewUserService(userRepo, certificateRepo, fileStorage)
+	learningService := service.NewLearningStatsService(activityRepo, service.NewEmailService())
 
 	userHandler := handler.NewUserHandler(userService)
+	learningHandler := handler.NewLearningStatsHandler(learningService)
 
-	userRoutes := routes.NewUserRoutes(userHandler)
+	userRoutes := routes.NewUserRoutes(userHandler, learningHandler)
 
-	return &UserModule{routes: userRoutes}
+	return &UserModule{routes: userRoutes, learningService: learningService}
 }
 
 func (um *UserModule) Routes() routes.Route {
 	return um.routes
 }
+
+func (um *UserModule) Jobs() []scheduler.Job {
+	interval := time.Duration(utils.GetEnvInt("LEARNING_REMINDER_INTERVAL_SECONDS", 3600)) * time.Second
+
+	return []scheduler.Job{
+		{
+			Name:     "learning-reminders",
+			Interval: interval,
+			Run:      um.learningService.SendInactivityReminders,
+		},
+	}
+}
@@ -0,0 +1,125 @@
+package certificate
+
+import (
+	_ "embed"
+	"encoding/binary"
+	"math"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// fonts/ chứa DejaVu Sans đã subset sẵn các khối Latin, dấu kết hợp, Latin mở rộng (tiếng Việt) và dấu câu.
+// License Bitstream Vera cho phép nhúng và phân phối lại, xem fonts/LICENSE
+var (
+	//go:embed fonts/DejaVuSans.ttf
+	dejaVuSans []byte
+
+	//go:embed fonts/DejaVuSans-Bold.ttf
+	dejaVuSansBold []byte
+)
+
+// font là font TrueType được nhúng (subset theo các glyph đã dùng) vào PDF dạng Type0/Identity-H,
+// chuỗi được ghi bằng glyph id nên in được mọi ký tự font có (tiếng Việt có dấu)
+type font struct {
+	resource string // Tên resource trong content stream (/F1, /F2)
+	baseFont string
+	ttf      *trueTypeFont
+}
+
+var (
+	sans     = mustLoadFont("F1", "DejaVuSans", dejaVuSans)
+	sansBold = mustLoadFont("F2", "DejaVuSans-Bold", dejaVuSansBold)
+)
+
+func mustLoadFont(resource, baseFont string, data []byte) *font {
+	ttf, err := parseTrueType(data)
+	if err != nil {
+		panic("certificate: load font " + baseFont + ": " + err.Error())
+	}
+	return &font{resource: resource, baseFont: baseFont, ttf: ttf}
+}
+
+// glyph là một glyph kèm ký tự Unicode nó thể hiện (để ghi ToUnicode, copy/tìm kiếm chữ trong PDF)
+type glyph struct {
+	id   uint16
+	char rune
+}
+
+// shape chuyển chuỗi (chuẩn hóa NFC để dùng glyph dựng sẵn của chữ có dấu) thành danh sách glyph.
+// Ký tự font không có được thay bằng chữ cái gốc không dấu, không có chữ gốc thì thành '?'
+func (f *font) shape(s string) []glyph {
+	glyphs := make([]glyph, 0, len(s))
+	for _, r := range norm.NFC.String(s) {
+		switch {
+		case unicode.IsSpace(r):
+			r = ' '
+		case unicode.IsControl(r):
+			continue // Bỏ ký tự điều khiển
+		}
+
+		id, ok := f.ttf.cmap[r]
+		if !ok {
+			r = foldRune(r)
+			if id, ok = f.ttf.cmap[r]; !ok {
+				r = '?'
+				id = f.ttf.cmap[r]
+			}
+		}
+		glyphs = append(glyphs, glyph{id: id, char: r})
+	}
+	return glyphs
+}
+
+// foldRune thay ký tự bằng chữ cái gốc không dấu: 'ễ' -> 'e', 'Đ' -> 'D', ký tự không có chữ gốc -> '?'
+func foldRune(r rune) rune {
+	switch r {
+	case 'đ':
+		return 'd'
+	case 'Đ':
+		return 'D'
+	}
+
+	for _, base := range norm.NFD.String(string(r)) {
+		if !unicode.Is(unicode.Mn, base) && base < 0x100 {
+			return base
+		}
+	}
+	return '?'
+}
+
+// glyphWidth - độ rộng glyph theo đơn vị 1/1000 em (đơn vị của PDF)
+func (f *font) glyphWidth(id uint16) int {
+	return f.scale(f.ttf.advances[id])
+}
+
+// scale đổi đơn vị font (unitsPerEm) sang 1/1000 em
+func (f *font) scale(v int) int {
+	return int(math.Round(float64(v) * 1000 / float64(f.ttf.unitsPerEm)))
+}
+
+// textWidth tính độ rộng (point) của chuỗi ở cỡ chữ size
+func (f *font) textWidth(text string, size float64) float64 {
+	total := 0
+	for _, g := range f.shape(text) {
+		total += f.glyphWidth(g.id)
+	}
+	return float64(total) * size / 1000
+}
+
+// metrics trả về FontBBox, Ascent, Descent và CapHeight (1/1000 em) cho font descriptor
+func (f *font) metrics() (bbox [4]int, ascent, descent, capHeight int) {
+	head, hhea := f.ttf.tables["head"], f.ttf.tables["hhea"]
+	for i := range bbox {
+		bbox[i] = f.scale(int(int16(binary.BigEndian.Uint16(head[36+i*2:]))))
+	}
+	ascent = f.scale(int(int16(binary.BigEndian.Uint16(hhea[4:]))))
+	descent = f.scale(int(int16(binary.BigEndian.Uint16(hhea[6:]))))
+
+	// sCapHeight có từ OS/2 version 2
+	capHeight = ascent
+	if os2 := f.ttf.tables["OS/2"]; len(os2) >= 90 && binary.BigEndian.Uint16(os2) >= 2 {
+		capHeight = f.scale(int(int16(binary.BigEndian.Uint16(os2[88:]))))
+	}
+	return bbox, ascent, descent, capHeight
+}
@@ -0,0 +1,234 @@
+package certificate
+
+import (
+	"bytes"
+	"compress/zlib"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// page là content stream của một trang PDF, toạ độ tính bằng point với gốc ở góc dưới bên trái
+type page struct {
+	width, height float64
+	content       bytes.Buffer
+	used          map[*font]map[uint16]rune // Glyph đã dùng theo font -> ký tự Unicode, để subset font và ghi ToUnicode
+}
+
+func newPage(width, height float64) *page {
+	return &page{width: width, height: height, used: make(map[*font]map[uint16]rune)}
+}
+
+func (p *page) setFillColor(r, g, b float64) {
+	fmt.Fprintf(&p.content, "%s %s %s rg\n", num(r), num(g), num(b))
+}
+
+func (p *page) setStrokeColor(r, g, b float64) {
+	fmt.Fprintf(&p.content, "%s %s %s RG\n", num(r), num(g), num(b))
+}
+
+func (p *page) strokeRect(x, y, w, h, lineWidth float64) {
+	fmt.Fprintf(&p.content, "%s w %s %s %s %s re S\n", num(lineWidth), num(x), num(y), num(w), num(h))
+}
+
+func (p *page) line(x1, y1, x2, y2, lineWidth float64) {
+	fmt.Fprintf(&p.content, "%s w %s %s m %s %s l S\n", num(lineWidth), num(x1), num(y1), num(x2), num(y2))
+}
+
+// centerText vẽ một dòng chữ căn giữa theo chiều ngang tại baseline y
+func (p *page) centerText(f *font, size, y float64, text string) {
+	glyphs := f.shape(text)
+	if p.used[f] == nil {
+		p.used[f] = make(map[uint16]rune)
+	}
+
+	// Identity-H: mỗi glyph là 2 byte glyph id
+	var hex strings.Builder
+	width := 0
+	for _, g := range glyphs {
+		p.used[f][g.id] = g.char
+		width += f.glyphWidth(g.id)
+		fmt.Fprintf(&hex, "%04X", g.id)
+	}
+
+	x := (p.width - float64(width)*size/1000) / 2
+	fmt.Fprintf(&p.content, "BT /%s %s Tf %s %s Td <%s> Tj ET\n", f.resource, num(size), num(x), num(y), hex.String())
+}
+
+// writePDF ghi tài liệu PDF 1.4 một trang, nhúng subset của hai font DejaVu Sans và DejaVu Sans Bold
+func writePDF(w io.Writer, p *page, title string) error {
+	content, err := deflate(p.content.Bytes())
+	if err != nil {
+		return err
+	}
+
+	// 1. Catalog, trang và content stream: object 1..4, font từ object 5 (mỗi font 5 object), Info ở cuối
+	fonts := []*font{sans, sansBold}
+	fontRefs := make([]string, len(fonts))
+	for i, f := range fonts {
+		fontRefs[i] = fmt.Sprintf("/%s %d 0 R", f.resource, 5+i*5)
+	}
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << %s >> >> /Contents 4 0 R >>",
+			num(p.width), num(p.height), strings.Join(fontRefs, " ")),
+		stream("", content),
+	}
+
+	// 2. Font nhúng
+	for _, f := range fonts {
+		fontObjects, err := embedFont(f, p.used[f], len(objects)+1)
+		if err != nil {
+			return err
+		}
+		objects = append(objects, fontObjects...)
+	}
+
+	objects = append(objects, fmt.Sprintf("<< /Title %s /Producer (LMS) >>", textString(title)))
+
+	// 3. Ghi file: header, các object, bảng xref và trailer
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n%\xE2\xE3\xCF\xD3\n")
+
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+
+	xrefOffset := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, len(objects), xrefOffset)
+
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// embedFont tạo 5 object (bắt đầu từ số first) cho một font: Type0, CIDFontType2, FontDescriptor, FontFile2 và ToUnicode.
+// Font được subset giữ nguyên glyph id nên CID = glyph id (CIDToGIDMap /Identity)
+func embedFont(f *font, used map[uint16]rune, first int) ([]string, error) {
+	ids := make([]int, 0, len(used))
+	glyphs := make(map[uint16]bool, len(used))
+	for id := range used {
+		ids = append(ids, int(id))
+		glyphs[id] = true
+	}
+	sort.Ints(ids)
+
+	fontFile, _ := f.ttf.subset(glyphs, false)
+	compressed, err := deflate(fontFile)
+	if err != nil {
+		return nil, err
+	}
+	toUnicode, err := deflate([]byte(toUnicodeCMap(ids, used)))
+	if err != nil {
+		return nil, err
+	}
+
+	// Font subset phải có tag 6 chữ in hoa trước tên, sinh từ tập glyph để cùng nội dung cho cùng kết quả
+	baseFont := subsetTag(ids) + "+" + f.baseFont
+
+	var widths strings.Builder
+	for _, id := range ids {
+		fmt.Fprintf(&widths, "%d [%d] ", id, f.glyphWidth(uint16(id)))
+	}
+
+	bbox, ascent, descent, capHeight := f.metrics()
+
+	return []string{
+		fmt.Sprintf("<< /Type /Font /Subtype /Type0 /BaseFont /%s /Encoding /Identity-H /DescendantFonts [%d 0 R] /ToUnicode %d 0 R >>",
+			baseFont, first+1, first+4),
+		fmt.Sprintf("<< /Type /Font /Subtype /CIDFontType2 /BaseFont /%s /CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> /FontDescriptor %d 0 R /DW 1000 /W [%s] /CIDToGIDMap /Identity >>",
+			baseFont, first+2, strings.TrimSpace(widths.String())),
+		fmt.Sprintf("<< /Type /FontDescriptor /FontName /%s /Flags 32 /FontBBox [%d %d %d %d] /ItalicAngle 0 /Ascent %d /Descent %d /CapHeight %d /StemV 80 /FontFile2 %d 0 R >>",
+			baseFont, bbox[0], bbox[1], bbox[2], bbox[3], ascent, descent, capHeight, first+3),
+		stream(fmt.Sprintf("/Length1 %d", len(fontFile)), compressed),
+		stream("", toUnicode),
+	}, nil
+}
+
+// toUnicodeCMap map glyph id -> Unicode (UTF-16BE) để trình đọc PDF copy và tìm kiếm được chữ
+func toUnicodeCMap(ids []int, used map[uint16]rune) string {
+	var cmap strings.Builder
+	cmap.WriteString("/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n" +
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n" +
+		"/CMapName /Adobe-Identity-UCS def\n/CMapType 2 def\n" +
+		"1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+
+	// Mỗi khối bfchar tối đa 100 dòng
+	for start := 0; start < len(ids); start += 100 {
+		end := min(start+100, len(ids))
+		fmt.Fprintf(&cmap, "%d beginbfchar\n", end-start)
+		for _, id := range ids[start:end] {
+			fmt.Fprintf(&cmap, "<%04X> <%s>\n", id, utf16Hex(string(used[uint16(id)])))
+		}
+		cmap.WriteString("endbfchar\n")
+	}
+
+	cmap.WriteString("endcmap\nCMapName currentdict /CMap defineresource pop\nend\nend\n")
+	return cmap.String()
+}
+
+func subsetTag(ids []int) string {
+	hash := sha256.New()
+	for _, id := range ids {
+		fmt.Fprintf(hash, "%d,", id)
+	}
+	sum := hash.Sum(nil)
+
+	tag := make([]byte, 6)
+	for i := range tag {
+		tag[i] = 'A' + sum[i]%26
+	}
+	return string(tag)
+}
+
+// stream tạo stream object, extra là các key thêm vào dictionary
+func stream(extra string, data []byte) string {
+	dict := fmt.Sprintf("/Length %d /Filter /FlateDecode", len(data))
+	if extra != "" {
+		dict += " " + extra
+	}
+	return fmt.Sprintf("<< %s >>\nstream\n%s\nendstream", dict, data)
+}
+
+func deflate(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := zlib.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// textString tạo PDF text string dạng UTF-16BE (có BOM) cho metadata như Title
+func textString(text string) string {
+	return "<FEFF" + utf16Hex(text) + ">"
+}
+
+func utf16Hex(text string) string {
+	var hex strings.Builder
+	for _, unit := range utf16.Encode([]rune(text)) {
+		fmt.Fprintf(&hex, "%04X", unit)
+	}
+	return hex.String()
+}
+
+// num định dạng số với tối đa 2 chữ số thập phân
+func num(v float64) string {
+	s := strconv.FormatFloat(v, 'f', 2, 64)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
@@ -0,0 +1,138 @@
+package certificate
+
+import (
+	"crypto/rand"
+	"io"
+	"strings"
+	"time"
+)
+
+// Kích thước trang A4 nằm ngang (point)
+const (
+	pageWidth  = 842
+	pageHeight = 595
+)
+
+// Data là nội dung in trên chứng chỉ
+type Data struct {
+	Serial         string
+	LearnerName    string
+	CourseTitle    string
+	InstructorName string
+	CompletedAt    time.Time
+	VerifyURL      string // Trang xác thực công khai, in ở chân chứng chỉ
+}
+
+// Render ghi chứng chỉ hoàn thành khóa học dạng PDF (một trang A4 ngang)
+func Render(w io.Writer, data Data) error {
+	p := newPage(pageWidth, pageHeight)
+
+	// Khung viền đôi
+	p.setStrokeColor(0.15, 0.27, 0.47)
+	p.strokeRect(24, 24, pageWidth-48, pageHeight-48, 3)
+	p.strokeRect(34, 34, pageWidth-68, pageHeight-68, 0.75)
+
+	p.setFillColor(0.15, 0.27, 0.47)
+	p.centerText(sansBold, 34, 480, "CERTIFICATE OF COMPLETION")
+
+	p.setFillColor(0.3, 0.3, 0.3)
+	p.centerText(sans, 14, 430, "This is to certify that")
+
+	p.setFillColor(0, 0, 0)
+	nameSize := fitSize(sansBold, data.LearnerName, 30, 14, pageWidth-160)
+	p.centerText(sansBold, nameSize, 385, data.LearnerName)
+	p.setStrokeColor(0.6, 0.6, 0.6)
+	p.line(pageWidth/2-220, 372, pageWidth/2+220, 372, 0.75)
+
+	p.setFillColor(0.3, 0.3, 0.3)
+	p.centerText(sans, 14, 340, "has successfully completed the course")
+
+	// Tên khóa học dài được ngắt tối đa 2 dòng
+	p.setFillColor(0, 0, 0)
+	titleLines := wrapText(sansBold, data.CourseTitle, 22, pageWidth-200, 2)
+	y := 300.0
+	for _, line := range titleLines {
+		p.centerText(sansBold, 22, y, line)
+		y -= 28
+	}
+
+	p.setFillColor(0.2, 0.2, 0.2)
+	if data.InstructorName != "" {
+		p.centerText(sans, 13, 210, "Instructor: "+data.InstructorName)
+	}
+	p.centerText(sans, 13, 188, "Date of completion: "+data.CompletedAt.Format("January 2, 2006"))
+
+	p.setFillColor(0.4, 0.4, 0.4)
+	p.centerText(sans, 10, 80, "Certificate No. "+data.Serial)
+	if data.VerifyURL != "" {
+		p.centerText(sans, 10, 64, "Verify at "+data.VerifyURL)
+	}
+
+	return writePDF(w, p, "Certificate of Completion - "+data.CourseTitle)
+}
+
+// fitSize giảm cỡ chữ (tối thiểu minSize) để dòng chữ vừa maxWidth
+func fitSize(f *font, text string, size, minSize, maxWidth float64) float64 {
+	for size > minSize && f.textWidth(text, size) > maxWidth {
+		size--
+	}
+	return size
+}
+
+// wrapText ngắt chữ theo từ thành tối đa maxLines dòng, dòng cuối bị cắt thêm "..." nếu vẫn không vừa
+func wrapText(f *font, text string, size, maxWidth float64, maxLines int) []string {
+	words := strings.Fields(text)
+	var lines []string
+	current := ""
+	for i, word := range words {
+		candidate := strings.TrimSpace(current + " " + word)
+		if current == "" || f.textWidth(candidate, size) <= maxWidth {
+			current = candidate
+			continue
+		}
+
+		if len(lines) == maxLines-1 {
+			current = strings.Join(append([]string{current}, words[i:]...), " ")
+			break
+		}
+		lines = append(lines, current)
+		current = word
+	}
+	if current != "" {
+		lines = append(lines, truncate(f, current, size, maxWidth))
+	}
+	return lines
+}
+
+func truncate(f *font, text string, size, maxWidth float64) string {
+	if f.textWidth(text, size) <= maxWidth {
+		return text
+	}
+
+	runes := []rune(text)
+	for len(runes) > 0 && f.textWidth(string(runes)+"...", size) > maxWidth {
+		runes = runes[:len(runes)-1]
+	}
+	return strings.TrimSpace(string(runes)) + "..."
+}
+
+// serialAlphabet bỏ các ký tự dễ nhầm (0/O, 1/I/L)
+const serialAlphabet = "23456789ABCDEFGHJKMNPQRSTUVWXYZ"
+
+// NewSerial sinh số hiệu chứng chỉ ngẫu nhiên dạng LMS-XXXX-XXXX-XXXX
+func NewSerial() (string, error) {
+	raw := make([]byte, 12)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	sb.WriteString("LMS")
+	for i, b := range raw {
+		if i%4 == 0 {
+			sb.WriteByte('-')
+		}
+		sb.WriteByte(serialAlphabet[int(b)%len(serialAlphabet)])
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,192 @@
+package certificate
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// pdfObjects tách các object của file PDF, kiểm tra bảng xref trỏ đúng vị trí từng object
+func pdfObjects(t *testing.T, pdf []byte) map[int][]byte {
+	t.Helper()
+
+	match := regexp.MustCompile(`startxref\n(\d+)\n%%EOF\n$`).FindSubmatch(pdf)
+	if match == nil {
+		t.Fatal("missing startxref")
+	}
+	xrefOffset, _ := strconv.Atoi(string(match[1]))
+	if !bytes.HasPrefix(pdf[xrefOffset:], []byte("xref\n")) {
+		t.Fatal("startxref does not point to xref table")
+	}
+
+	lines := strings.Split(string(pdf[xrefOffset:]), "\n")
+	count, _ := strconv.Atoi(strings.Fields(lines[1])[1])
+
+	objects := make(map[int][]byte)
+	for i := 1; i < count; i++ {
+		offset, _ := strconv.Atoi(strings.Fields(lines[2+i])[0])
+		header := fmt.Sprintf("%d 0 obj\n", i)
+		if !bytes.HasPrefix(pdf[offset:], []byte(header)) {
+			t.Fatalf("xref entry %d does not point to its object", i)
+		}
+		body := pdf[offset+len(header):]
+		objects[i] = body[:bytes.Index(body, []byte("\nendobj\n"))]
+	}
+	return objects
+}
+
+func streamData(t *testing.T, object []byte) []byte {
+	t.Helper()
+
+	start := bytes.Index(object, []byte("stream\n")) + len("stream\n")
+	end := bytes.LastIndex(object, []byte("\nendstream"))
+	zr, err := zlib.NewReader(bytes.NewReader(object[start:end]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestRenderVietnameseText(t *testing.T) {
+	var buf bytes.Buffer
+	err := Render(&buf, Data{
+		Serial:         "LMS-AAAA-BBBB-CCCC",
+		LearnerName:    "Nguyễn Thị Đặng Hương",
+		CourseTitle:    "Lập trình Go từ cơ bản đến nâng cao",
+		InstructorName: "Phạm Hiếu",
+		CompletedAt:    time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC),
+		VerifyURL:      "https://lms.example.com/certificates/LMS-AAAA-BBBB-CCCC",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	objects := pdfObjects(t, buf.Bytes())
+	content := string(streamData(t, objects[4]))
+
+	// Tên học viên được ghi bằng glyph id của chữ có dấu, không bị bỏ dấu
+	var name strings.Builder
+	for _, r := range "Nguyễn Thị Đặng Hương" {
+		fmt.Fprintf(&name, "%04X", sansBold.ttf.cmap[r])
+	}
+	if !strings.Contains(content, "<"+name.String()+"> Tj") {
+		t.Error("learner name is not written with Vietnamese glyphs")
+	}
+
+	// Font nhúng (object 5..9 là font thường, 10..14 là font đậm) chứa outline của glyph đã dùng
+	fontFile := streamData(t, objects[13])
+	embedded, err := parseTrueType(fontFile)
+	if err != nil {
+		t.Fatalf("embedded font: %v", err)
+	}
+	for _, r := range "ễịĐặươ" {
+		id := sansBold.ttf.cmap[r]
+		if len(embedded.glyph(id)) == 0 || !bytes.Equal(embedded.glyph(id), sansBold.ttf.glyph(id)) {
+			t.Errorf("glyph for %c missing from embedded font", r)
+		}
+	}
+	if len(embedded.glyph(sansBold.ttf.cmap['Z'])) != 0 {
+		t.Error("unused glyph should not be embedded")
+	}
+
+	// ToUnicode để copy/tìm kiếm được chữ có dấu
+	toUnicode := string(streamData(t, objects[14]))
+	if !strings.Contains(toUnicode, fmt.Sprintf("<%04X> <1EC5>", sansBold.ttf.cmap['ễ'])) {
+		t.Error("ToUnicode CMap missing mapping for ễ")
+	}
+
+	if !bytes.Contains(objects[15], []byte("/Title <FEFF")) {
+		t.Error("title should be a UTF-16 text string")
+	}
+}
+
+func TestShapeFallback(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"Nguyễn", "Nguyễn"},
+		{"Nguye\u0302\u0303n", "Nguyễn"}, // Dạng tổ hợp (NFD) được chuẩn hóa về chữ dựng sẵn
+		{"a\tb\nc", "a b c"},
+		{"Tiếng Việt 日本", "Tiếng Việt ??"},
+	}
+
+	for _, tt := range tests {
+		var got strings.Builder
+		for _, g := range sans.shape(tt.text) {
+			got.WriteRune(g.char)
+			if sans.ttf.cmap[g.char] != g.id {
+				t.Errorf("%q: glyph id of %c does not match cmap", tt.text, g.char)
+			}
+		}
+		if got.String() != tt.want {
+			t.Errorf("shape(%q) = %q, want %q", tt.text, got.String(), tt.want)
+		}
+	}
+}
+
+func TestSubsetRoundTrip(t *testing.T) {
+	glyphs := map[uint16]bool{}
+	for _, r := range "Ắ ệ" {
+		glyphs[sans.ttf.cmap[r]] = true
+	}
+
+	for _, renumber := range []bool{true, false} {
+		data, gidMap := sans.ttf.subset(glyphs, renumber)
+		if tableChecksum(data) != 0xB1B0AFBA {
+			t.Errorf("renumber=%v: invalid checkSumAdjustment", renumber)
+		}
+
+		subset, err := parseTrueType(data)
+		if err != nil {
+			t.Fatalf("renumber=%v: %v", renumber, err)
+		}
+		for _, r := range "Ắ ệ" {
+			old := sans.ttf.cmap[r]
+			id, ok := subset.cmap[r]
+			if !ok || id != gidMap[old] {
+				t.Errorf("renumber=%v: cmap of %c = %d, want %d", renumber, r, id, gidMap[old])
+			}
+			if subset.advances[id] != sans.ttf.advances[old] {
+				t.Errorf("renumber=%v: advance of %c changed", renumber, r)
+			}
+			// Component của composite glyph cũng phải có trong subset
+			glyph := subset.glyph(id)
+			for _, offset := range components(glyph) {
+				component := uint16(glyph[offset])<<8 | uint16(glyph[offset+1])
+				if len(subset.glyph(component)) == 0 {
+					t.Errorf("renumber=%v: component %d of %c missing", renumber, component, r)
+				}
+			}
+		}
+		if _, ok := subset.cmap['Z']; ok {
+			t.Errorf("renumber=%v: unused character in cmap", renumber)
+		}
+	}
+}
+
+func TestParseTrueTypeRejectsMalformed(t *testing.T) {
+	valid, _ := sans.ttf.subset(map[uint16]bool{sans.ttf.cmap['A']: true}, true)
+
+	tests := map[string][]byte{
+		"empty":        nil,
+		"bad version":  append([]byte("OTTO"), valid[4:]...),
+		"truncated":    valid[:40],
+		"table beyond": append(append([]byte(nil), valid[:12]...), bytes.Repeat([]byte{0xFF}, 16)...),
+	}
+	for name, data := range tests {
+		if _, err := parseTrueType(data); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
@@ -0,0 +1,497 @@
+package certificate
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+var errInvalidFont = errors.New("certificate: invalid TrueType font")
+
+// trueTypeFont là font TrueType (outline glyf) đã đọc các bảng cần cho việc đo chữ và subset
+type trueTypeFont struct {
+	tables     map[string][]byte
+	unitsPerEm int
+	numGlyphs  int
+	longLoca   bool
+	advances   []int // Advance width theo glyph id, đơn vị font
+	cmap       map[rune]uint16
+}
+
+func parseTrueType(data []byte) (*trueTypeFont, error) {
+	if len(data) < 12 {
+		return nil, errInvalidFont
+	}
+	if v := binary.BigEndian.Uint32(data); v != 0x00010000 && v != 0x74727565 { // 1.0 hoặc "true"
+		return nil, errInvalidFont
+	}
+
+	// 1. Bảng thư mục
+	f := &trueTypeFont{tables: make(map[string][]byte)}
+	numTables := int(binary.BigEndian.Uint16(data[4:]))
+	if len(data) < 12+numTables*16 {
+		return nil, errInvalidFont
+	}
+	for i := 0; i < numTables; i++ {
+		record := data[12+i*16:]
+		tag := string(record[:4])
+		offset := int(binary.BigEndian.Uint32(record[8:]))
+		length := int(binary.BigEndian.Uint32(record[12:]))
+		if offset < 0 || length < 0 || offset+length > len(data) {
+			return nil, errInvalidFont
+		}
+		f.tables[tag] = data[offset : offset+length]
+	}
+
+	for _, tag := range []string{"head", "hhea", "hmtx", "maxp", "loca", "glyf", "cmap"} {
+		if f.tables[tag] == nil {
+			return nil, fmt.Errorf("%w: missing %s table", errInvalidFont, tag)
+		}
+	}
+
+	head, hhea, maxp := f.tables["head"], f.tables["hhea"], f.tables["maxp"]
+	if len(head) < 54 || len(hhea) < 36 || len(maxp) < 6 {
+		return nil, errInvalidFont
+	}
+	f.unitsPerEm = int(binary.BigEndian.Uint16(head[18:]))
+	f.longLoca = binary.BigEndian.Uint16(head[50:]) == 1
+	f.numGlyphs = int(binary.BigEndian.Uint16(maxp[4:]))
+	if f.unitsPerEm == 0 || f.numGlyphs == 0 {
+		return nil, errInvalidFont
+	}
+
+	locaEntries := len(f.tables["loca"]) / 2
+	if f.longLoca {
+		locaEntries = len(f.tables["loca"]) / 4
+	}
+	if locaEntries < f.numGlyphs+1 {
+		return nil, errInvalidFont
+	}
+
+	// 2. Advance width: numberOfHMetrics cặp (advance, lsb), các glyph sau dùng advance cuối cùng
+	numHMetrics := int(binary.BigEndian.Uint16(hhea[34:]))
+	hmtx := f.tables["hmtx"]
+	if numHMetrics == 0 || numHMetrics > f.numGlyphs || len(hmtx) < numHMetrics*4 {
+		return nil, errInvalidFont
+	}
+	f.advances = make([]int, f.numGlyphs)
+	for gid := range f.advances {
+		metric := min(gid, numHMetrics-1)
+		f.advances[gid] = int(binary.BigEndian.Uint16(hmtx[metric*4:]))
+	}
+
+	// 3. Bảng cmap Unicode
+	cmap, err := parseCmap(f.tables["cmap"], f.numGlyphs)
+	if err != nil {
+		return nil, err
+	}
+	f.cmap = cmap
+
+	return f, nil
+}
+
+// parseCmap đọc subtable Unicode format 12 (toàn bộ Unicode) hoặc format 4 (BMP)
+func parseCmap(table []byte, numGlyphs int) (map[rune]uint16, error) {
+	if len(table) < 4 {
+		return nil, errInvalidFont
+	}
+
+	var format4, format12 []byte
+	numSubtables := int(binary.BigEndian.Uint16(table[2:]))
+	for i := 0; i < numSubtables; i++ {
+		if 4+i*8+8 > len(table) {
+			return nil, errInvalidFont
+		}
+		record := table[4+i*8:]
+		platform := binary.BigEndian.Uint16(record)
+		encoding := binary.BigEndian.Uint16(record[2:])
+		offset := int(binary.BigEndian.Uint32(record[4:]))
+		if offset+4 > len(table) {
+			return nil, errInvalidFont
+		}
+		subtable := table[offset:]
+
+		unicode := platform == 0 || (platform == 3 && (encoding == 1 || encoding == 10))
+		switch format := binary.BigEndian.Uint16(subtable); {
+		case unicode && format == 4:
+			format4 = subtable
+		case unicode && format == 12:
+			format12 = subtable
+		}
+	}
+
+	cmap := make(map[rune]uint16)
+	add := func(r rune, gid int) {
+		if gid > 0 && gid < numGlyphs {
+			cmap[r] = uint16(gid)
+		}
+	}
+
+	switch {
+	case format12 != nil:
+		if len(format12) < 16 {
+			return nil, errInvalidFont
+		}
+		numGroups := int(binary.BigEndian.Uint32(format12[12:]))
+		if len(format12) < 16+numGroups*12 {
+			return nil, errInvalidFont
+		}
+		for i := 0; i < numGroups; i++ {
+			group := format12[16+i*12:]
+			start := binary.BigEndian.Uint32(group)
+			end := binary.BigEndian.Uint32(group[4:])
+			startGlyph := binary.BigEndian.Uint32(group[8:])
+			if end < start || end > unicodeMax {
+				return nil, errInvalidFont
+			}
+			for c := start; c <= end; c++ {
+				add(rune(c), int(startGlyph+c-start))
+			}
+		}
+
+	case format4 != nil:
+		if len(format4) < 14 {
+			return nil, errInvalidFont
+		}
+		segCount := int(binary.BigEndian.Uint16(format4[6:])) / 2
+		if len(format4) < 16+segCount*8 {
+			return nil, errInvalidFont
+		}
+		endCodes := format4[14:]
+		startCodes := format4[16+segCount*2:]
+		idDeltas := format4[16+segCount*4:]
+		idRangeOffsets := format4[16+segCount*6:]
+
+		for i := 0; i < segCount; i++ {
+			end := int(binary.BigEndian.Uint16(endCodes[i*2:]))
+			start := int(binary.BigEndian.Uint16(startCodes[i*2:]))
+			delta := int(binary.BigEndian.Uint16(idDeltas[i*2:]))
+			rangeOffset := int(binary.BigEndian.Uint16(idRangeOffsets[i*2:]))
+
+			for c := start; c <= end && c != 0xFFFF; c++ {
+				if rangeOffset == 0 {
+					add(rune(c), (c+delta)&0xFFFF)
+					continue
+				}
+				// idRangeOffset tính từ chính vị trí của nó trong mảng
+				position := 16 + segCount*6 + i*2 + rangeOffset + (c-start)*2
+				if position+2 > len(format4) {
+					return nil, errInvalidFont
+				}
+				if gid := int(binary.BigEndian.Uint16(format4[position:])); gid != 0 {
+					add(rune(c), (gid+delta)&0xFFFF)
+				}
+			}
+		}
+
+	default:
+		return nil, fmt.Errorf("%w: no Unicode cmap", errInvalidFont)
+	}
+
+	return cmap, nil
+}
+
+const unicodeMax = 0x10FFFF
+
+// glyph trả về dữ liệu outline của glyph (rỗng với glyph không có nét như dấu cách)
+func (f *trueTypeFont) glyph(gid uint16) []byte {
+	loca, glyf := f.tables["loca"], f.tables["glyf"]
+
+	var start, end int
+	if f.longLoca {
+		start = int(binary.BigEndian.Uint32(loca[int(gid)*4:]))
+		end = int(binary.BigEndian.Uint32(loca[int(gid)*4+4:]))
+	} else {
+		start = int(binary.BigEndian.Uint16(loca[int(gid)*2:])) * 2
+		end = int(binary.BigEndian.Uint16(loca[int(gid)*2+2:])) * 2
+	}
+	if start >= end || end > len(glyf) {
+		return nil
+	}
+	return glyf[start:end]
+}
+
+// Cờ của component trong composite glyph
+const (
+	compositeArgWords    = 0x0001
+	compositeScale       = 0x0008
+	compositeMore        = 0x0020
+	compositeXYScale     = 0x0040
+	compositeTwoByTwo    = 0x0080
+	compositeHeaderBytes = 10
+)
+
+// components trả về offset (trong dữ liệu glyph) của glyph index từng component, nil nếu không phải composite glyph
+func components(glyph []byte) []int {
+	if len(glyph) < compositeHeaderBytes || int16(binary.BigEndian.Uint16(glyph)) >= 0 {
+		return nil
+	}
+
+	var offsets []int
+	offset := compositeHeaderBytes
+	for offset+4 <= len(glyph) {
+		flags := binary.BigEndian.Uint16(glyph[offset:])
+		offsets = append(offsets, offset+2)
+
+		offset += 4
+		if flags&compositeArgWords != 0 {
+			offset += 4
+		} else {
+			offset += 2
+		}
+		switch {
+		case flags&compositeScale != 0:
+			offset += 2
+		case flags&compositeXYScale != 0:
+			offset += 4
+		case flags&compositeTwoByTwo != 0:
+			offset += 8
+		}
+
+		if flags&compositeMore == 0 {
+			break
+		}
+	}
+	return offsets
+}
+
+// subset tạo font TrueType chỉ chứa các glyph trong glyphs (kèm .notdef và component của composite glyph).
+// renumber = false giữ nguyên glyph id (glyph không dùng để rỗng) để content stream dùng trực tiếp glyph id của font gốc.
+// Trả về dữ liệu font và map glyph id cũ -> mới
+func (f *trueTypeFont) subset(glyphs map[uint16]bool, renumber bool) ([]byte, map[uint16]uint16) {
+	// 1. Thêm .notdef và component của composite glyph
+	keep := make(map[uint16]bool)
+	var visit func(gid uint16)
+	visit = func(gid uint16) {
+		if keep[gid] || int(gid) >= f.numGlyphs {
+			return
+		}
+		keep[gid] = true
+		glyph := f.glyph(gid)
+		for _, offset := range components(glyph) {
+			visit(binary.BigEndian.Uint16(glyph[offset:]))
+		}
+	}
+	visit(0)
+	for gid := range glyphs {
+		visit(gid)
+	}
+
+	ids := make([]uint16, 0, len(keep))
+	for gid := range keep {
+		ids = append(ids, gid)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	// 2. Glyph id mới
+	gidMap := make(map[uint16]uint16, len(ids))
+	numGlyphs := len(ids)
+	for i, gid := range ids {
+		if renumber {
+			gidMap[gid] = uint16(i)
+		} else {
+			gidMap[gid] = gid
+		}
+	}
+	if !renumber {
+		numGlyphs = int(ids[len(ids)-1]) + 1
+	}
+	oldIds := make([]int, numGlyphs) // Glyph id mới -> cũ, -1 = glyph rỗng
+	for i := range oldIds {
+		oldIds[i] = -1
+	}
+	for old, gid := range gidMap {
+		oldIds[gid] = int(old)
+	}
+
+	// 3. glyf, loca (dạng long) và hmtx
+	var glyf []byte
+	loca := make([]byte, 0, (numGlyphs+1)*4)
+	hmtx := make([]byte, 0, numGlyphs*4)
+	for _, old := range oldIds {
+		loca = binary.BigEndian.AppendUint32(loca, uint32(len(glyf)))
+		if old < 0 {
+			hmtx = append(hmtx, 0, 0, 0, 0)
+			continue
+		}
+
+		glyph := append([]byte(nil), f.glyph(uint16(old))...)
+		for _, offset := range components(glyph) {
+			component := binary.BigEndian.Uint16(glyph[offset:])
+			binary.BigEndian.PutUint16(glyph[offset:], gidMap[component])
+		}
+		glyf = append(glyf, glyph...)
+		for len(glyf)%4 != 0 {
+			glyf = append(glyf, 0)
+		}
+
+		hmtx = binary.BigEndian.AppendUint16(hmtx, uint16(f.advances[old]))
+		hmtx = binary.BigEndian.AppendUint16(hmtx, f.leftSideBearing(old))
+	}
+	loca = binary.BigEndian.AppendUint32(loca, uint32(len(glyf)))
+
+	// 4. Các bảng header cập nhật số glyph, định dạng loca
+	head := append([]byte(nil), f.tables["head"]...)
+	binary.BigEndian.PutUint32(head[8:], 0) // checkSumAdjustment tính lại sau
+	binary.BigEndian.PutUint16(head[50:], 1)
+
+	hhea := append([]byte(nil), f.tables["hhea"]...)
+	binary.BigEndian.PutUint16(hhea[34:], uint16(numGlyphs))
+
+	maxp := append([]byte(nil), f.tables["maxp"]...)
+	binary.BigEndian.PutUint16(maxp[4:], uint16(numGlyphs))
+
+	// 5. cmap chỉ gồm các ký tự có glyph trong subset
+	runes := make(map[rune]uint16)
+	for r, old := range f.cmap {
+		if gid, ok := gidMap[old]; ok && r <= 0xFFFF {
+			runes[r] = gid
+		}
+	}
+
+	tables := map[string][]byte{
+		"head": head,
+		"hhea": hhea,
+		"maxp": maxp,
+		"hmtx": hmtx,
+		"loca": loca,
+		"glyf": glyf,
+		"cmap": buildCmap(runes),
+		"post": f.postV3(),
+	}
+	// Bảng không phụ thuộc glyph id được giữ nguyên (name chứa copyright/license của font)
+	for _, tag := range []string{"OS/2", "name", "cvt ", "fpgm", "prep", "gasp"} {
+		if table, ok := f.tables[tag]; ok {
+			tables[tag] = table
+		}
+	}
+
+	return writeTrueType(tables), gidMap
+}
+
+func (f *trueTypeFont) leftSideBearing(gid int) uint16 {
+	hmtx := f.tables["hmtx"]
+	numHMetrics := int(binary.BigEndian.Uint16(f.tables["hhea"][34:]))
+	if gid < numHMetrics {
+		return binary.BigEndian.Uint16(hmtx[gid*4+2:])
+	}
+	offset := numHMetrics*4 + (gid-numHMetrics)*2
+	if offset+2 > len(hmtx) {
+		return 0
+	}
+	return binary.BigEndian.Uint16(hmtx[offset:])
+}
+
+// postV3 - bảng post version 3 (không có tên glyph), giữ italicAngle và thông số gạch chân
+func (f *trueTypeFont) postV3() []byte {
+	post := make([]byte, 32)
+	binary.BigEndian.PutUint32(post, 0x00030000)
+	if original := f.tables["post"]; len(original) >= 32 {
+		copy(post[4:], original[4:32])
+	}
+	return post
+}
+
+// buildCmap tạo bảng cmap với một subtable format 4 (Windows, Unicode BMP)
+func buildCmap(runes map[rune]uint16) []byte {
+	codes := make([]int, 0, len(runes))
+	for r := range runes {
+		codes = append(codes, int(r))
+	}
+	sort.Ints(codes)
+
+	// Gom các ký tự liên tiếp có glyph id liên tiếp thành một segment (idDelta)
+	type segment struct{ start, end, delta int }
+	var segments []segment
+	for _, c := range codes {
+		delta := (int(runes[rune(c)]) - c) & 0xFFFF
+		if n := len(segments); n > 0 && segments[n-1].end == c-1 && segments[n-1].delta == delta {
+			segments[n-1].end = c
+			continue
+		}
+		segments = append(segments, segment{start: c, end: c, delta: delta})
+	}
+	segments = append(segments, segment{start: 0xFFFF, end: 0xFFFF, delta: 1})
+
+	segCount := len(segments)
+	searchRange := 2 * (1 << int(math.Floor(math.Log2(float64(segCount)))))
+
+	subtable := make([]byte, 0, 16+segCount*8)
+	subtable = binary.BigEndian.AppendUint16(subtable, 4)
+	subtable = binary.BigEndian.AppendUint16(subtable, uint16(16+segCount*8))
+	subtable = binary.BigEndian.AppendUint16(subtable, 0) // language
+	subtable = binary.BigEndian.AppendUint16(subtable, uint16(segCount*2))
+	subtable = binary.BigEndian.AppendUint16(subtable, uint16(searchRange))
+	subtable = binary.BigEndian.AppendUint16(subtable, uint16(math.Log2(float64(searchRange/2))))
+	subtable = binary.BigEndian.AppendUint16(subtable, uint16(segCount*2-searchRange))
+	for _, s := range segments {
+		subtable = binary.BigEndian.AppendUint16(subtable, uint16(s.end))
+	}
+	subtable = binary.BigEndian.AppendUint16(subtable, 0) // reservedPad
+	for _, s := range segments {
+		subtable = binary.BigEndian.AppendUint16(subtable, uint16(s.start))
+	}
+	for _, s := range segments {
+		subtable = binary.BigEndian.AppendUint16(subtable, uint16(s.delta))
+	}
+	for range segments {
+		subtable = binary.BigEndian.AppendUint16(subtable, 0) // idRangeOffset
+	}
+
+	table := []byte{0, 0, 0, 1, 0, 3, 0, 1, 0, 0, 0, 12} // version, 1 subtable (3, 1) ở offset 12
+	return append(table, subtable...)
+}
+
+// writeTrueType ghi các bảng thành file font, tính checksum từng bảng và checkSumAdjustment của head
+func writeTrueType(tables map[string][]byte) []byte {
+	tags := make([]string, 0, len(tables))
+	for tag := range tables {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	numTables := len(tags)
+	entrySelector := int(math.Floor(math.Log2(float64(numTables))))
+	searchRange := (1 << entrySelector) * 16
+
+	out := make([]byte, 12+numTables*16)
+	binary.BigEndian.PutUint32(out, 0x00010000)
+	binary.BigEndian.PutUint16(out[4:], uint16(numTables))
+	binary.BigEndian.PutUint16(out[6:], uint16(searchRange))
+	binary.BigEndian.PutUint16(out[8:], uint16(entrySelector))
+	binary.BigEndian.PutUint16(out[10:], uint16(numTables*16-searchRange))
+
+	headOffset := 0
+	for i, tag := range tags {
+		table := tables[tag]
+		if tag == "head" {
+			headOffset = len(out)
+		}
+
+		record := out[12+i*16:]
+		copy(record, tag)
+		binary.BigEndian.PutUint32(record[4:], tableChecksum(table))
+		binary.BigEndian.PutUint32(record[8:], uint32(len(out)))
+		binary.BigEndian.PutUint32(record[12:], uint32(len(table)))
+
+		out = append(out, table...)
+		for len(out)%4 != 0 {
+			out = append(out, 0)
+		}
+	}
+
+	binary.BigEndian.PutUint32(out[headOffset+8:], 0xB1B0AFBA-tableChecksum(out))
+	return out
+}
+
+func tableChecksum(data []byte) uint32 {
+	var sum uint32
+	for i := 0; i < len(data); i += 4 {
+		var word [4]byte
+		copy(word[:], data[i:])
+		sum += binary.BigEndian.Uint32(word[:])
+	}
+	return sum
+}
@@ -0,0 +1,130 @@
+package coursepkg
+
+import "time"
+
+// FormatVersion là version của định dạng package do code hiện tại ghi ra.
+// Tăng version khi thay đổi không tương thích, Open từ chối package có version mới hơn.
+const FormatVersion = 1
+
+// ManifestName là tên file JSON mô tả course ở gốc ZIP
+const ManifestName = "manifest.json"
+
+// Manifest mô tả toàn bộ course. File đính kèm (thumbnail, resources) nằm trong ZIP
+// và được tham chiếu bằng đường dẫn tương đối. Video không được đóng gói, video_url giữ nguyên.
+// Course không có section, thứ tự lesson nằm ở lesson_order.
+type Manifest struct {
+	FormatVersion int       `json:"format_version"`
+	ExportedAt    time.Time `json:"exported_at"`
+	Course        Course    `json:"course"`
+	Lessons       []Lesson  `json:"lessons"`
+}
+
+type Course struct {
+	Title           string   `json:"title"`
+	Slug            string   `json:"slug"`
+	Description     string   `json:"description"` // Markdown
+	ShortDesc       string   `json:"short_description"`
+	Category        Category `json:"category"`
+	Level           string   `json:"level"`
+	Language        string   `json:"language"`
+	Price           float64  `json:"price"`
+	DiscountPrice   *float64 `json:"discount_price,omitempty"`
+	DurationHours   int      `json:"duration_hours"`
+	Requirements    string   `json:"requirements"`
+	WhatYouLearn    string   `json:"what_you_learn"`
+	VideoPreviewURL string   `json:"video_preview_url,omitempty"`
+	ThumbnailURL    string   `json:"thumbnail_url,omitempty"`  // Thumbnail ở URL ngoài (không đóng gói)
+	ThumbnailFile   string   `json:"thumbnail_file,omitempty"` // Thumbnail đóng gói trong ZIP
+}
+
+// Category được tham chiếu bằng slug (và name) vì id khác nhau giữa các môi trường
+type Category struct {
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+type Lesson struct {
+	Title         string `json:"title"`
+	Slug          string `json:"slug"`
+	Description   string `json:"description"`
+	Content       string `json:"content"` // Markdown
+	VideoURL      string `json:"video_url,omitempty"`
+	VideoDuration int    `json:"video_duration"`
+	LessonType    string `json:"lesson_type"` // video, quiz, assignment, scorm
+	LessonOrder   int    `json:"lesson_order"`
+	IsPreview     bool   `json:"is_preview"`
+	IsPublished   bool   `json:"is_published"`
+
+	UnlockAfterDays           int  `json:"unlock_after_days"`
+	RequirePreviousCompletion bool `json:"require_previous_completion"`
+
+	Quiz       *Quiz       `json:"quiz,omitempty"`
+	Assignment *Assignment `json:"assignment,omitempty"`
+	Resources  []Resource  `json:"resources,omitempty"` // Theo thứ tự hiển thị
+	Scorm      *Scorm      `json:"scorm,omitempty"`
+}
+
+type Quiz struct {
+	TimeLimitSeconds    int        `json:"time_limit_seconds"`
+	MaxAttempts         int        `json:"max_attempts"`
+	PassPercentage      float64    `json:"pass_percentage"`
+	QuestionsPerAttempt int        `json:"questions_per_attempt"`
+	ShuffleQuestions    bool       `json:"shuffle_questions"`
+	ShuffleOptions      bool       `json:"shuffle_options"`
+	ShowCorrectAnswers  bool       `json:"show_correct_answers"`
+	Questions           []Question `json:"questions"` // Theo thứ tự trong question bank
+}
+
+type Question struct {
+	QuestionType    string   `json:"question_type"` // single_choice, multiple_choice, true_false, short_answer
+	Text            string   `json:"text"`
+	Explanation     string   `json:"explanation"`
+	Points          float64  `json:"points"`
+	Options         []Option `json:"options,omitempty"`
+	AcceptedAnswers []string `json:"accepted_answers,omitempty"`
+	CaseSensitive   bool     `json:"case_sensitive"`
+}
+
+type Option struct {
+	Key       string `json:"key"`
+	Text      string `json:"text"`
+	IsCorrect bool   `json:"is_correct"`
+}
+
+type Assignment struct {
+	Instructions           string     `json:"instructions"`
+	DueAt                  *time.Time `json:"due_at,omitempty"`
+	DueDaysAfterEnrollment *int       `json:"due_days_after_enrollment,omitempty"`
+
+	MaxPoints      float64           `json:"max_points"`
+	PassPercentage float64           `json:"pass_percentage"`
+	Rubric         []RubricCriterion `json:"rubric,omitempty"`
+
+	AllowResubmission bool `json:"allow_resubmission"`
+	MaxSubmissions    int  `json:"max_submissions"`
+	MaxFiles          int  `json:"max_files"`
+
+	AllowLateSubmission   bool    `json:"allow_late_submission"`
+	LatePenaltyPercent    float64 `json:"late_penalty_percent"`
+	MaxLatePenaltyPercent float64 `json:"max_late_penalty_percent"`
+}
+
+type RubricCriterion struct {
+	Key         string  `json:"key"`
+	Title       string  `json:"title"`
+	Description string  `json:"description"`
+	MaxPoints   float64 `json:"max_points"`
+}
+
+// Scorm tham chiếu package SCORM 1.2 của lesson, lưu nguyên dạng ZIP lồng trong package
+type Scorm struct {
+	File string `json:"file"` // Đường dẫn trong ZIP
+}
+
+type Resource struct {
+	Title        string `json:"title"`
+	Description  string `json:"description"`
+	OriginalName string `json:"original_name"`
+	MimeType     string `json:"mime_type"`
+	File         string `json:"file"` // Đường dẫn trong ZIP
+}
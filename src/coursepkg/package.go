@@ -0,0 +1,234 @@
+package coursepkg
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidPackage     = errors.New("invalid course package")
+	ErrUnsupportedVersion = errors.New("unsupported course package version")
+	ErrPackageTooLarge    = errors.New("course package is too large")
+)
+
+// Limits giới hạn package khi đọc để chặn ZIP bomb
+type Limits struct {
+	MaxFiles     int   // Số file tối đa trong ZIP
+	MaxFileSize  int64 // Dung lượng tối đa của một file sau giải nén
+	MaxTotalSize int64 // Tổng dung lượng tối đa sau giải nén
+}
+
+// ---------------- Writer ----------------
+
+// Writer ghi package ZIP: các file đính kèm trước, manifest sau cùng trong Close
+type Writer struct {
+	zw    *zip.Writer
+	files map[string]bool
+}
+
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{zw: zip.NewWriter(w), files: make(map[string]bool)}
+}
+
+// AddFile ghi một file đính kèm với đường dẫn name trong ZIP
+func (pw *Writer) AddFile(name string, r io.Reader) error {
+	if !validEntryName(name) || name == ManifestName {
+		return fmt.Errorf("invalid package entry name: %q", name)
+	}
+	if pw.files[name] {
+		return fmt.Errorf("duplicate package entry: %q", name)
+	}
+
+	w, err := pw.zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: time.Now()})
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(w, r); err != nil {
+		return err
+	}
+
+	pw.files[name] = true
+	return nil
+}
+
+// Close ghi manifest và đóng ZIP, manifest chỉ được tham chiếu các file đã ghi qua AddFile
+func (pw *Writer) Close(manifest *Manifest) error {
+	for _, name := range manifest.referencedFiles() {
+		if !pw.files[name] {
+			return fmt.Errorf("manifest references missing file: %q", name)
+		}
+	}
+
+	manifest.FormatVersion = FormatVersion
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	w, err := pw.zw.CreateHeader(&zip.FileHeader{Name: ManifestName, Method: zip.Deflate, Modified: time.Now()})
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	return pw.zw.Close()
+}
+
+// ThumbnailPath trả về đường dẫn của thumbnail trong ZIP
+func ThumbnailPath(ext string) string {
+	return "thumbnail" + strings.ToLower(ext)
+}
+
+// ResourcePath trả về đường dẫn của resource thứ resourceIndex thuộc lesson thứ lessonIndex
+func ResourcePath(lessonIndex, resourceIndex int, originalName string) string {
+	return fmt.Sprintf("resources/%03d/%02d_%s", lessonIndex+1, resourceIndex+1, safeFileName(originalName))
+}
+
+// ScormPath trả về đường dẫn của package SCORM thuộc lesson thứ lessonIndex
+func ScormPath(lessonIndex int) string {
+	return fmt.Sprintf("scorm/%03d.zip", lessonIndex+1)
+}
+
+var unsafeFileNameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+func safeFileName(name string) string {
+	name = unsafeFileNameChars.ReplaceAllString(path.Base(strings.ReplaceAll(name, `\`, "/")), "_")
+	name = strings.Trim(name, "._")
+	if name == "" {
+		return "file"
+	}
+	return name
+}
+
+// ---------------- Reader ----------------
+
+// Package là package đã mở và đọc manifest
+type Package struct {
+	Manifest *Manifest
+	files    map[string]*zip.File
+}
+
+// Open đọc ZIP và manifest, kiểm tra version và giới hạn dung lượng.
+// Nội dung manifest chưa được kiểm tra, gọi Validate để lấy danh sách lỗi.
+func Open(r io.ReaderAt, size int64, limits Limits) (*Package, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPackage, err)
+	}
+
+	if limits.MaxFiles > 0 && len(zr.File) > limits.MaxFiles {
+		return nil, fmt.Errorf("%w: more than %d files", ErrPackageTooLarge, limits.MaxFiles)
+	}
+
+	pkg := &Package{files: make(map[string]*zip.File, len(zr.File))}
+	var total uint64
+	for _, file := range zr.File {
+		if strings.HasSuffix(file.Name, "/") {
+			continue // Thư mục
+		}
+		if !validEntryName(file.Name) {
+			return nil, fmt.Errorf("%w: invalid entry name %q", ErrInvalidPackage, file.Name)
+		}
+		if pkg.files[file.Name] != nil {
+			return nil, fmt.Errorf("%w: duplicate entry %q", ErrInvalidPackage, file.Name)
+		}
+
+		// Kích thước khai báo trong ZIP, archive/zip trả lỗi nếu dữ liệu thật vượt quá khi đọc
+		if limits.MaxFileSize > 0 && file.UncompressedSize64 > uint64(limits.MaxFileSize) {
+			return nil, fmt.Errorf("%w: %q exceeds %d bytes", ErrPackageTooLarge, file.Name, limits.MaxFileSize)
+		}
+		total += file.UncompressedSize64
+		if limits.MaxTotalSize > 0 && total > uint64(limits.MaxTotalSize) {
+			return nil, fmt.Errorf("%w: total size exceeds %d bytes", ErrPackageTooLarge, limits.MaxTotalSize)
+		}
+
+		pkg.files[file.Name] = file
+	}
+
+	manifestFile := pkg.files[ManifestName]
+	if manifestFile == nil {
+		return nil, fmt.Errorf("%w: %s not found", ErrInvalidPackage, ManifestName)
+	}
+
+	rc, err := manifestFile.Open()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPackage, err)
+	}
+	defer rc.Close()
+
+	var manifest Manifest
+	if err := json.NewDecoder(rc).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("%w: invalid manifest: %v", ErrInvalidPackage, err)
+	}
+
+	if manifest.FormatVersion < 1 || manifest.FormatVersion > FormatVersion {
+		return nil, fmt.Errorf("%w: %d (supported: 1-%d)", ErrUnsupportedVersion, manifest.FormatVersion, FormatVersion)
+	}
+
+	pkg.Manifest = &manifest
+	return pkg, nil
+}
+
+// OpenFile mở file đính kèm theo đường dẫn trong manifest
+func (p *Package) OpenFile(name string) (io.ReadCloser, int64, error) {
+	file := p.files[name]
+	if file == nil || name == ManifestName {
+		return nil, 0, fmt.Errorf("%w: file %q not found", ErrInvalidPackage, name)
+	}
+
+	rc, err := file.Open()
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: %v", ErrInvalidPackage, err)
+	}
+	return rc, int64(file.UncompressedSize64), nil
+}
+
+// HasFile kiểm tra package có file đính kèm name không
+func (p *Package) HasFile(name string) bool {
+	return name != ManifestName && p.files[name] != nil
+}
+
+// validEntryName chỉ chấp nhận đường dẫn tương đối, không có "..", "\" hay ký tự điều khiển
+func validEntryName(name string) bool {
+	if name == "" || len(name) > 255 || strings.HasPrefix(name, "/") || strings.Contains(name, `\`) {
+		return false
+	}
+	if path.Clean(name) != name {
+		return false
+	}
+	for _, part := range strings.Split(name, "/") {
+		if part == ".." || part == "." {
+			return false
+		}
+	}
+	for _, c := range name {
+		if c < 0x20 || c == 0x7f {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *Manifest) referencedFiles() []string {
+	var files []string
+	if m.Course.ThumbnailFile != "" {
+		files = append(files, m.Course.ThumbnailFile)
+	}
+	for _, lesson := range m.Lessons {
+		for _, resource := range lesson.Resources {
+			files = append(files, resource.File)
+		}
+		if lesson.Scorm != nil {
+			files = append(files, lesson.Scorm.File)
+		}
+	}
+	return files
+}
@@ -0,0 +1,249 @@
+package coursepkg
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"hash/crc32"
+	"io"
+	"strings"
+	"testing"
+)
+
+var testLimits = Limits{MaxFiles: 10, MaxFileSize: 8 << 10, MaxTotalSize: 32 << 10}
+
+func validManifest() *Manifest {
+	return &Manifest{
+		Course: Course{
+			Title:         "Lập trình Go",
+			Description:   "Khóa học Go từ cơ bản đến nâng cao",
+			ShortDesc:     "Học Go cơ bản",
+			Category:      Category{Slug: "programming"},
+			Level:         "beginner",
+			Language:      "vi",
+			Price:         100,
+			ThumbnailFile: ThumbnailPath(".PNG"),
+		},
+		Lessons: []Lesson{
+			{
+				Title:       "Giới thiệu",
+				LessonType:  "video",
+				LessonOrder: 1,
+				Resources:   []Resource{{Title: "Slide", OriginalName: "../../slide 1.pdf", File: ResourcePath(0, 0, "../../slide 1.pdf")}},
+			},
+			{
+				Title:       "Kiểm tra",
+				LessonType:  "quiz",
+				LessonOrder: 2,
+				Quiz: &Quiz{PassPercentage: 80, Questions: []Question{
+					{QuestionType: "true_false", Text: "Go có GC?", Points: 1, Options: []Option{{Key: "true", IsCorrect: true}, {Key: "false"}}},
+				}},
+			},
+		},
+	}
+}
+
+func writePackage(t *testing.T, manifest *Manifest, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	for name, content := range files {
+		if err := w.AddFile(name, strings.NewReader(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(manifest); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// rawZip ghi ZIP trực tiếp bằng archive/zip để tạo các package mà Writer không cho phép
+func rawZip(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		io.WriteString(w, content)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func mustJSON(t *testing.T, v interface{}) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestPackageRoundTrip(t *testing.T) {
+	manifest := validManifest()
+	data := writePackage(t, manifest, map[string]string{
+		"thumbnail.png":                "png",
+		"resources/001/01_slide_1.pdf": "pdf",
+	})
+
+	pkg, err := Open(bytes.NewReader(data), int64(len(data)), testLimits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pkg.Manifest.FormatVersion != FormatVersion || pkg.Manifest.Course.Title != manifest.Course.Title {
+		t.Errorf("manifest %+v", pkg.Manifest)
+	}
+	if problems := pkg.Validate(); len(problems) != 0 {
+		t.Errorf("unexpected problems %+v", problems)
+	}
+
+	rc, size, err := pkg.OpenFile("resources/001/01_slide_1.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+	if content, _ := io.ReadAll(rc); string(content) != "pdf" || size != 3 {
+		t.Errorf("got %q (%d bytes)", content, size)
+	}
+
+	if pkg.HasFile(ManifestName) {
+		t.Error("manifest must not be exposed as an attachment")
+	}
+	if _, _, err := pkg.OpenFile(ManifestName); err == nil {
+		t.Error("opening the manifest as an attachment should fail")
+	}
+}
+
+func TestWriterRejectsInvalidEntries(t *testing.T) {
+	for _, name := range []string{"", "../evil", "/abs", `a\b`, "a/../b", "./a", "a//b", ManifestName, "a\x00b", strings.Repeat("a", 256)} {
+		w := NewWriter(io.Discard)
+		if err := w.AddFile(name, strings.NewReader("x")); err == nil {
+			t.Errorf("AddFile(%q) should fail", name)
+		}
+	}
+
+	w := NewWriter(io.Discard)
+	w.AddFile("a.txt", strings.NewReader("x"))
+	if err := w.AddFile("a.txt", strings.NewReader("x")); err == nil {
+		t.Error("duplicate entry should fail")
+	}
+
+	// Manifest tham chiếu file chưa được ghi
+	if err := NewWriter(io.Discard).Close(validManifest()); err == nil {
+		t.Error("missing referenced files should fail")
+	}
+}
+
+func TestSafeFileName(t *testing.T) {
+	tests := map[string]string{
+		"../../etc/passwd": "passwd",
+		`..\..\win.ini`:    "win.ini",
+		"Bài giảng 1.pdf":  "B_i_gi_ng_1.pdf",
+		"..":               "file",
+		".hidden":          "hidden",
+		"":                 "file",
+	}
+	for input, want := range tests {
+		if got := safeFileName(input); got != want {
+			t.Errorf("safeFileName(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestOpenRejectsMalformedPackages(t *testing.T) {
+	manifest := `{"format_version":1,"course":{"title":"x"}}`
+	big := strings.Repeat("a", int(testLimits.MaxFileSize)+1)
+
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr error
+	}{
+		{"not a zip", []byte("PK\x03\x04 not really"), ErrInvalidPackage},
+		{"missing manifest", rawZip(t, map[string]string{"a.txt": "x"}), ErrInvalidPackage},
+		{"invalid manifest", rawZip(t, map[string]string{ManifestName: "{"}), ErrInvalidPackage},
+		{"future version", rawZip(t, map[string]string{ManifestName: `{"format_version":99}`}), ErrUnsupportedVersion},
+		{"missing version", rawZip(t, map[string]string{ManifestName: `{}`}), ErrUnsupportedVersion},
+		{"path traversal", rawZip(t, map[string]string{ManifestName: manifest, "../../evil.sh": "x"}), ErrInvalidPackage},
+		{"absolute path", rawZip(t, map[string]string{ManifestName: manifest, "/etc/cron.d/x": "x"}), ErrInvalidPackage},
+		{"backslash path", rawZip(t, map[string]string{ManifestName: manifest, `..\evil`: "x"}), ErrInvalidPackage},
+		{"file too large", rawZip(t, map[string]string{ManifestName: manifest, "big.bin": big}), ErrPackageTooLarge},
+		{"total too large", rawZip(t, map[string]string{ManifestName: manifest, "1": big[:7000], "2": big[:7000], "3": big[:7000], "4": big[:7000], "5": big[:7000]}), ErrPackageTooLarge},
+		{"too many files", rawZip(t, map[string]string{ManifestName: manifest, "1": "", "2": "", "3": "", "4": "", "5": "", "6": "", "7": "", "8": "", "9": "", "10": ""}), ErrPackageTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Open(bytes.NewReader(tt.data), int64(len(tt.data)), testLimits)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("error %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// File khai báo kích thước nhỏ nhưng dữ liệu thật lớn hơn phải bị lỗi khi đọc
+func TestOpenFileRejectsUnderstatedSize(t *testing.T) {
+	content := []byte(strings.Repeat("a", 4096))
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, _ := zw.Create(ManifestName)
+	io.WriteString(w, `{"format_version":1}`)
+	raw, err := zw.CreateRaw(&zip.FileHeader{Name: "bomb.bin", Method: zip.Store, CRC32: crc32.ChecksumIEEE(content), CompressedSize64: uint64(len(content)), UncompressedSize64: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw.Write(content)
+	zw.Close()
+
+	pkg, err := Open(bytes.NewReader(buf.Bytes()), int64(buf.Len()), testLimits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rc, _, err := pkg.OpenFile("bomb.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+	if n, err := io.Copy(io.Discard, rc); err == nil || n > 10 {
+		t.Errorf("read %d bytes with error %v, want an error after at most 10 bytes", n, err)
+	}
+}
+
+func TestValidateReportsProblems(t *testing.T) {
+	manifest := validManifest()
+	manifest.Course.Level = "expert"
+	manifest.Lessons[1].LessonOrder = 1
+	manifest.Lessons[1].Quiz.Questions[0].Options[1].IsCorrect = true
+	manifest.Lessons[0].Resources[0].File = "../../etc/passwd"
+	manifest.FormatVersion = FormatVersion
+
+	data := rawZip(t, map[string]string{ManifestName: mustJSON(t, manifest)})
+	pkg, err := Open(bytes.NewReader(data), int64(len(data)), testLimits)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[string]bool)
+	for _, problem := range pkg.Validate() {
+		got[problem.Field] = true
+	}
+	for _, field := range []string{
+		"course.level",
+		"course.thumbnail_file",
+		"lessons[1].lesson_order",
+		"lessons[1].quiz.questions[0].options",
+		"lessons[0].resources[0].file",
+	} {
+		if !got[field] {
+			t.Errorf("missing problem for %s (got %v)", field, got)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package coursepkg
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Problem là một lỗi nội dung của manifest, Field là đường dẫn dạng "lessons[2].quiz.questions[0].options"
+type Problem struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// maxMarkdownLength là độ dài tối đa của nội dung Markdown (mô tả course, nội dung lesson), giống validator của API
+const maxMarkdownLength = 100000
+
+// Các giá trị hợp lệ theo cùng quy tắc với API tạo course/lesson/quiz
+var (
+	validLevels      = map[string]bool{"beginner": true, "intermediate": true, "advanced": true}
+	validLanguages   = map[string]bool{"vi": true, "en": true}
+	validLessonTypes = map[string]bool{"video": true, "quiz": true, "assignment": true, "scorm": true}
+)
+
+// Validate kiểm tra nội dung manifest và các file được tham chiếu, trả về toàn bộ lỗi tìm thấy
+func (p *Package) Validate() []Problem {
+	v := &validator{pkg: p}
+	m := p.Manifest
+
+	// 1. Course
+	c := m.Course
+	v.length("course.title", c.Title, 5, 200)
+	v.length("course.description", c.Description, 20, maxMarkdownLength)
+	v.length("course.short_description", c.ShortDesc, 10, 500)
+	if !validLevels[c.Level] {
+		v.add("course.level", "must be one of beginner, intermediate, advanced")
+	}
+	if !validLanguages[c.Language] {
+		v.add("course.language", "must be one of vi, en")
+	}
+	if c.Category.Slug == "" && c.Category.Name == "" {
+		v.add("course.category", "category slug or name is required")
+	}
+	if c.Price < 0 {
+		v.add("course.price", "must not be negative")
+	}
+	if c.DiscountPrice != nil && (*c.DiscountPrice < 0 || *c.DiscountPrice >= c.Price) {
+		v.add("course.discount_price", "must be less than price")
+	}
+	if c.ThumbnailFile != "" {
+		v.file("course.thumbnail_file", c.ThumbnailFile)
+	}
+
+	// 2. Lessons
+	slugs := make(map[string]bool)
+	orders := make(map[int]bool)
+	for i, lesson := range m.Lessons {
+		field := fmt.Sprintf("lessons[%d]", i)
+
+		v.length(field+".title", lesson.Title, 1, 200)
+		v.length(field+".content", lesson.Content, 0, maxMarkdownLength)
+		if lesson.Slug != "" {
+			if slugs[lesson.Slug] {
+				v.add(field+".slug", "duplicate lesson slug "+lesson.Slug)
+			}
+			slugs[lesson.Slug] = true
+		}
+		if lesson.LessonOrder < 1 {
+			v.add(field+".lesson_order", "must be at least 1")
+		} else if orders[lesson.LessonOrder] {
+			v.add(field+".lesson_order", fmt.Sprintf("duplicate lesson order %d", lesson.LessonOrder))
+		}
+		orders[lesson.LessonOrder] = true
+
+		if !validLessonTypes[lesson.LessonType] {
+			v.add(field+".lesson_type", "must be one of video, quiz, assignment, scorm")
+		}
+		if lesson.Quiz != nil && lesson.LessonType != "quiz" {
+			v.add(field+".quiz", "only quiz lessons can have a quiz")
+		}
+		if lesson.Assignment != nil && lesson.LessonType != "assignment" {
+			v.add(field+".assignment", "only assignment lessons can have an assignment")
+		}
+		if lesson.Scorm != nil && lesson.LessonType != "scorm" {
+			v.add(field+".scorm", "only scorm lessons can have a SCORM package")
+		}
+		if lesson.VideoDuration < 0 || lesson.UnlockAfterDays < 0 {
+			v.add(field, "video_duration and unlock_after_days must not be negative")
+		}
+
+		if lesson.Quiz != nil {
+			v.quiz(field+".quiz", lesson.Quiz)
+		}
+		if lesson.Assignment != nil {
+			v.assignment(field+".assignment", lesson.Assignment)
+		}
+		for j, resource := range lesson.Resources {
+			resourceField := fmt.Sprintf("%s.resources[%d]", field, j)
+			v.length(resourceField+".title", resource.Title, 1, 200)
+			v.length(resourceField+".original_name", resource.OriginalName, 1, 255)
+			v.file(resourceField+".file", resource.File)
+		}
+		if lesson.Scorm != nil {
+			v.file(field+".scorm.file", lesson.Scorm.File)
+		}
+	}
+
+	return v.problems
+}
+
+type validator struct {
+	pkg      *Package
+	problems []Problem
+}
+
+func (v *validator) add(field, message string) {
+	v.problems = append(v.problems, Problem{Field: field, Message: message})
+}
+
+// length kiểm tra độ dài theo ký tự, max = 0 nghĩa là không giới hạn
+func (v *validator) length(field, value string, min, max int) {
+	n := utf8.RuneCountInString(strings.TrimSpace(value))
+	switch {
+	case n < min && min == 1:
+		v.add(field, "is required")
+	case n < min:
+		v.add(field, fmt.Sprintf("must be at least %d characters", min))
+	case max > 0 && n > max:
+		v.add(field, fmt.Sprintf("must be at most %d characters", max))
+	}
+}
+
+func (v *validator) file(field, name string) {
+	if !v.pkg.HasFile(name) {
+		v.add(field, fmt.Sprintf("file %q not found in package", name))
+	}
+}
+
+func (v *validator) quiz(field string, quiz *Quiz) {
+	if quiz.PassPercentage <= 0 || quiz.PassPercentage > 100 {
+		v.add(field+".pass_percentage", "must be greater than 0 and at most 100")
+	}
+	if quiz.TimeLimitSeconds < 0 || quiz.MaxAttempts < 0 || quiz.QuestionsPerAttempt < 0 {
+		v.add(field, "time_limit_seconds, max_attempts and questions_per_attempt must not be negative")
+	}
+
+	for i, question := range quiz.Questions {
+		questionField := fmt.Sprintf("%s.questions[%d]", field, i)
+		if strings.TrimSpace(question.Text) == "" {
+			v.add(questionField+".text", "is required")
+		}
+		if question.Points <= 0 {
+			v.add(questionField+".points", "must be greater than 0")
+		}
+
+		correct := 0
+		keys := make(map[string]bool)
+		for _, option := range question.Options {
+			if option.Key == "" || keys[option.Key] {
+				v.add(questionField+".options", "option keys must be unique and not empty")
+				break
+			}
+			keys[option.Key] = true
+			if option.IsCorrect {
+				correct++
+			}
+		}
+
+		switch question.QuestionType {
+		case "single_choice":
+			if len(question.Options) < 2 || correct != 1 {
+				v.add(questionField+".options", "single choice questions need at least 2 options and exactly 1 correct option")
+			}
+		case "multiple_choice":
+			if len(question.Options) < 2 || correct == 0 {
+				v.add(questionField+".options", "multiple choice questions need at least 2 options and at least 1 correct option")
+			}
+		case "true_false":
+			if len(question.Options) != 2 || !keys["true"] || !keys["false"] || correct != 1 {
+				v.add(questionField+".options", "true/false questions need options \"true\" and \"false\" with exactly 1 correct")
+			}
+		case "short_answer":
+			if len(question.AcceptedAnswers) == 0 {
+				v.add(questionField+".accepted_answers", "short answer questions need at least 1 accepted answer")
+			}
+		default:
+			v.add(questionField+".question_type", "must be one of single_choice, multiple_choice, true_false, short_answer")
+		}
+	}
+}
+
+func (v *validator) assignment(field string, assignment *Assignment) {
+	if assignment.DueAt != nil && assignment.DueDaysAfterEnrollment != nil {
+		v.add(field, "due_at and due_days_after_enrollment cannot both be set")
+	}
+	if assignment.MaxPoints <= 0 {
+		v.add(field+".max_points", "must be greater than 0")
+	}
+	if assignment.PassPercentage < 0 || assignment.PassPercentage > 100 {
+		v.add(field+".pass_percentage", "must be between 0 and 100")
+	}
+	if assignment.LatePenaltyPercent < 0 || assignment.LatePenaltyPercent > 100 ||
+		assignment.MaxLatePenaltyPercent < 0 || assignment.MaxLatePenaltyPercent > 100 {
+		v.add(field, "late penalty percentages must be between 0 and 100")
+	}
+
+	keys := make(map[string]bool)
+	for i, criterion := range assignment.Rubric {
+		criterionField := fmt.Sprintf("%s.rubric[%d]", field, i)
+		if criterion.Key == "" || keys[criterion.Key] {
+			v.add(criterionField+".key", "rubric keys must be unique and not empty")
+		}
+		keys[criterion.Key] = true
+		if criterion.MaxPoints <= 0 {
+			v.add(criterionField+".max_points", "must be greater than 0")
+		}
+	}
+}
@@ -65,6 +65,31 @@ func InitDB() error {
 		&models.Review{},
 		&models.Coupon{},
 		&models.Order{},
+		&models.CourseRevision{},
+		&models.CourseModeration{},
+		&models.CoursePrerequisite{},
+		&models.LearningPath{},
+		&models.LearningPathCourse{},
+		&models.UserLearningPath{},
+		&models.Quiz{},
+		&models.QuizQuestion{},
+		&models.QuizAttempt{},
+		&models.Assignment{},
+		&models.AssignmentSubmission{},
+		&models.LessonResource{},
+		&models.VideoUpload{},
+		&models.LessonSubtitle{},
+		&models.LessonSubtitleCue{},
+		&models.ScormPackage{},
+		&models.ScormAttempt{},
+		&models.Certificate{},
+		&models.ProgressSyncEvent{},
+		&models.ProgressRecalculation{},
+		&models.LearningActivity{},
+		&models.LearningGoal{},
+		&models.NotificationPreference{},
+		&models.LessonNote{},
+		&models.LessonBookmark{},
 	)
 
 	if err != nil {
@@ -99,7 +99,7 @@ type AdminCoursesAnalyticsRequest struct {
 	StartDate  string `form:"start_date" binding:"omitempty"`
 	EndDate    string `form:"end_date" binding:"omitempty"`
 	CategoryId uint   `form:"category_id" binding:"omitempty"`
-	Status     string `form:"status" binding:"omitempty,oneof=draft published archived"`
+	Status     string `form:"status" binding:"omitempty,course_status"`
 }
 
 // Admin Courses Analytics Response
@@ -87,3 +87,27 @@ type StudentCourseItem struct {
 	AverageProgress   float64 `json:"average_progress"`
 	CompletionRate    float64 `json:"completion_rate"`
 }
+
+// LessonEngagementResponse - mức độ xem video của một lesson, tính từ các đoạn học viên đã thực sự xem
+type LessonEngagementResponse struct {
+	LessonId              uint                   `json:"lesson_id"`
+	LessonTitle           string                 `json:"lesson_title"`
+	VideoDuration         int                    `json:"video_duration"`
+	EnrolledStudents      int                    `json:"enrolled_students"`
+	Viewers               int                    `json:"viewers"` // Học viên đã xem ít nhất một đoạn
+	CompletedStudents     int                    `json:"completed_students"`
+	CompletionRate        float64                `json:"completion_rate"` // % trên số học viên đã enroll
+	RequiredWatchPercent  int                    `json:"required_watch_percent"`
+	ReachedRequiredCount  int                    `json:"reached_required_count"` // Học viên đã xem đủ % yêu cầu
+	AverageWatchedSeconds float64                `json:"average_watched_seconds"`
+	AverageWatchedPercent float64                `json:"average_watched_percent"`
+	Retention             []LessonRetentionPoint `json:"retention"`
+}
+
+// LessonRetentionPoint - số học viên đã xem (ít nhất một nửa) đoạn [start, end) của video
+type LessonRetentionPoint struct {
+	Start   int     `json:"start"`
+	End     int     `json:"end"`
+	Viewers int     `json:"viewers"`
+	Percent float64 `json:"percent"` // % trên tổng số viewers
+}
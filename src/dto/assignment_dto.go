@@ -0,0 +1,203 @@
+package dto
+
+import "time"
+
+// ---------------- Instructor: cấu hình assignment ----------------
+
+type RubricCriterionInput struct {
+	Title       string  `json:"title" binding:"required,max=200"`
+	Description string  `json:"description" binding:"omitempty,max=1000"`
+	MaxPoints   float64 `json:"max_points" binding:"required,gt=0,max=1000"`
+}
+
+// PUT /api/v1/instructor/courses/:course_id/lessons/:id/assignment - Request body
+type UpsertAssignmentRequest struct {
+	Instructions           *string    `json:"instructions" binding:"omitempty,max=50000"`
+	DueAt                  *time.Time `json:"due_at" binding:"omitempty"`
+	DueDaysAfterEnrollment *int       `json:"due_days_after_enrollment" binding:"omitempty,min=1,max=3650"`
+	ClearDueDate           bool       `json:"clear_due_date"` // Bỏ hạn nộp
+
+	MaxPoints      *float64               `json:"max_points" binding:"omitempty,gt=0,max=1000"` // Bị bỏ qua khi có rubric
+	PassPercentage *float64               `json:"pass_percentage" binding:"omitempty,min=0,max=100"`
+	Rubric         []RubricCriterionInput `json:"rubric" binding:"omitempty,max=20,dive"`
+	ClearRubric    bool                   `json:"clear_rubric"`
+
+	AllowResubmission *bool `json:"allow_resubmission"`
+	MaxSubmissions    *int  `json:"max_submissions" binding:"omitempty,min=0,max=100"`
+	MaxFiles          *int  `json:"max_files" binding:"omitempty,min=0,max=20"`
+
+	AllowLateSubmission   *bool    `json:"allow_late_submission"`
+	LatePenaltyPercent    *float64 `json:"late_penalty_percent" binding:"omitempty,min=0,max=100"`
+	MaxLatePenaltyPercent *float64 `json:"max_late_penalty_percent" binding:"omitempty,min=0,max=100"`
+}
+
+type RubricCriterionItem struct {
+	Key         string  `json:"key"`
+	Title       string  `json:"title"`
+	Description string  `json:"description"`
+	MaxPoints   float64 `json:"max_points"`
+}
+
+type InstructorAssignmentResponse struct {
+	Id                     uint                  `json:"id"`
+	LessonId               uint                  `json:"lesson_id"`
+	CourseId               uint                  `json:"course_id"`
+	Instructions           string                `json:"instructions"`
+	DueAt                  *time.Time            `json:"due_at"`
+	DueDaysAfterEnrollment *int                  `json:"due_days_after_enrollment"`
+	MaxPoints              float64               `json:"max_points"`
+	PassPercentage         float64               `json:"pass_percentage"`
+	Rubric                 []RubricCriterionItem `json:"rubric"`
+	AllowResubmission      bool                  `json:"allow_resubmission"`
+	MaxSubmissions         int                   `json:"max_submissions"`
+	MaxFiles               int                   `json:"max_files"`
+	AllowLateSubmission    bool                  `json:"allow_late_submission"`
+	LatePenaltyPercent     float64               `json:"late_penalty_percent"`
+	MaxLatePenaltyPercent  float64               `json:"max_late_penalty_percent"`
+	UpdatedAt              time.Time             `json:"updated_at"`
+}
+
+// ---------------- Instructor: hàng đợi chấm bài ----------------
+
+// GET /api/v1/instructor/assignments/submissions - Query parameters
+type GetGradingQueueQueryRequest struct {
+	Page     int    `form:"page" binding:"omitempty,min=1"`
+	Limit    int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	CourseId uint   `form:"course_id" binding:"omitempty,min=1"`
+	LessonId uint   `form:"lesson_id" binding:"omitempty,min=1"`
+	Status   string `form:"status" binding:"omitempty,oneof=submitted graded superseded all"` // Mặc định: submitted
+}
+
+type GradingQueueItem struct {
+	SubmissionId       uint      `json:"submission_id"`
+	CourseId           uint      `json:"course_id"`
+	CourseTitle        string    `json:"course_title"`
+	LessonId           uint      `json:"lesson_id"`
+	LessonTitle        string    `json:"lesson_title"`
+	StudentId          uint      `json:"student_id"`
+	StudentName        string    `json:"student_name"`
+	StudentEmail       string    `json:"student_email"`
+	SubmissionNumber   int       `json:"submission_number"`
+	Status             string    `json:"status"`
+	SubmittedAt        time.Time `json:"submitted_at"`
+	IsLate             bool      `json:"is_late"`
+	LateDays           int       `json:"late_days"`
+	LatePenaltyPercent float64   `json:"late_penalty_percent"`
+	TotalFiles         int       `json:"total_files"`
+	FinalScore         *float64  `json:"final_score"`
+	MaxPoints          float64   `json:"max_points"`
+}
+
+type GetGradingQueueResponse struct {
+	Submissions []GradingQueueItem `json:"submissions"`
+	Pagination  PaginationInfo     `json:"pagination"`
+}
+
+type RubricScoreInput struct {
+	CriterionKey string  `json:"criterion_key" binding:"required"`
+	Points       float64 `json:"points" binding:"min=0"`
+	Comment      string  `json:"comment" binding:"omitempty,max=2000"`
+}
+
+// POST /api/v1/instructor/assignments/submissions/:submission_id/grade - Request body
+// Assignment có rubric thì chấm theo rubric_scores, không có thì dùng score
+type GradeSubmissionRequest struct {
+	RubricScores []RubricScoreInput `json:"rubric_scores" binding:"omitempty,max=20,dive"`
+	Score        *float64           `json:"score" binding:"omitempty,min=0"`
+	Feedback     string             `json:"feedback" binding:"omitempty,max=10000"`
+}
+
+// ---------------- Student: nộp bài ----------------
+
+type SubmissionFileItem struct {
+	Id           string `json:"id"`
+	OriginalName string `json:"original_name"`
+	Size         int64  `json:"size"`
+	MimeType     string `json:"mime_type"`
+}
+
+type RubricScoreItem struct {
+	CriterionKey string  `json:"criterion_key"`
+	Title        string  `json:"title"`
+	Points       float64 `json:"points"`
+	MaxPoints    float64 `json:"max_points"`
+	Comment      string  `json:"comment"`
+}
+
+type AssignmentSubmissionSummary struct {
+	SubmissionId     uint       `json:"submission_id"`
+	SubmissionNumber int        `json:"submission_number"`
+	Status           string     `json:"status"`
+	SubmittedAt      time.Time  `json:"submitted_at"`
+	IsLate           bool       `json:"is_late"`
+	FinalScore       *float64   `json:"final_score"`
+	MaxPoints        float64    `json:"max_points"`
+	Passed           bool       `json:"passed"`
+	GradedAt         *time.Time `json:"graded_at"`
+}
+
+type AssignmentSubmissionDetail struct {
+	SubmissionId       uint                  `json:"submission_id"`
+	AssignmentId       uint                  `json:"assignment_id"`
+	CourseId           uint                  `json:"course_id"`
+	CourseTitle        string                `json:"course_title"`
+	LessonId           uint                  `json:"lesson_id"`
+	LessonTitle        string                `json:"lesson_title"`
+	StudentId          uint                  `json:"student_id"`
+	StudentName        string                `json:"student_name"`
+	SubmissionNumber   int                   `json:"submission_number"`
+	Status             string                `json:"status"`
+	TextContent        string                `json:"text_content"`
+	Files              []SubmissionFileItem  `json:"files"`
+	SubmittedAt        time.Time             `json:"submitted_at"`
+	DueAt              *time.Time            `json:"due_at"`
+	IsLate             bool                  `json:"is_late"`
+	LateDays           int                   `json:"late_days"`
+	LatePenaltyPercent float64               `json:"late_penalty_percent"`
+	Rubric             []RubricCriterionItem `json:"rubric"`
+	RubricScores       []RubricScoreItem     `json:"rubric_scores"`
+	RawScore           *float64              `json:"raw_score"`
+	FinalScore         *float64              `json:"final_score"`
+	MaxPoints          float64               `json:"max_points"`
+	Passed             bool                  `json:"passed"`
+	Feedback           string                `json:"feedback"`
+	GradedAt           *time.Time            `json:"graded_at"`
+	LessonCompleted    bool                  `json:"lesson_completed"`
+}
+
+// GET /api/v1/assignments/lessons/:lesson_id - Response
+type AssignmentInfoResponse struct {
+	AssignmentId          uint                         `json:"assignment_id"`
+	LessonId              uint                         `json:"lesson_id"`
+	LessonTitle           string                       `json:"lesson_title"`
+	CourseId              uint                         `json:"course_id"`
+	Instructions          string                       `json:"instructions"`
+	DueAt                 *time.Time                   `json:"due_at"` // Hạn nộp thực tế của user (đã tính theo ngày enroll)
+	MaxPoints             float64                      `json:"max_points"`
+	PassPercentage        float64                      `json:"pass_percentage"`
+	Rubric                []RubricCriterionItem        `json:"rubric"`
+	AllowResubmission     bool                         `json:"allow_resubmission"`
+	MaxSubmissions        int                          `json:"max_submissions"`
+	MaxFiles              int                          `json:"max_files"`
+	AllowLateSubmission   bool                         `json:"allow_late_submission"`
+	LatePenaltyPercent    float64                      `json:"late_penalty_percent"`
+	MaxLatePenaltyPercent float64                      `json:"max_late_penalty_percent"`
+	SubmissionsUsed       int                          `json:"submissions_used"`
+	CanSubmit             bool                         `json:"can_submit"`
+	CannotSubmitReason    string                       `json:"cannot_submit_reason,omitempty"`
+	Passed                bool                         `json:"passed"`
+	LatestSubmission      *AssignmentSubmissionSummary `json:"latest_submission"`
+}
+
+// POST /api/v1/assignments/lessons/:lesson_id/submissions - Form data (kèm files[])
+type SubmitAssignmentRequest struct {
+	TextContent string `form:"text_content" binding:"omitempty,max=50000"`
+}
+
+// GET /api/v1/assignments/lessons/:lesson_id/submissions - Response
+type GetMySubmissionsResponse struct {
+	AssignmentId uint                          `json:"assignment_id"`
+	LessonId     uint                          `json:"lesson_id"`
+	Submissions  []AssignmentSubmissionSummary `json:"submissions"`
+	Total        int                           `json:"total"`
+}
@@ -40,15 +40,17 @@ type ApiResponse struct {
 }
 
 type UserProfile struct {
-	Id            uint      `json:"id"`
-	Username      string    `json:"username"`
-	Email         string    `json:"email"`
-	FullName      string    `json:"full_name"`
-	Phone         string    `json:"phone"`
-	Role          string    `json:"role"` // admin,
-	Status        string    `json:"status"`
-	EmailVerified bool      `json:"email_verified"`
-	CreatedAt     time.Time `json:"created_at"`
+	Id             uint              `json:"id"`
+	Username       string            `json:"username"`
+	Email          string            `json:"email"`
+	FullName       string            `json:"full_name"`
+	Phone          string            `json:"phone"`
+	AvatarURL      string            `json:"avatar_url"`
+	AvatarVariants map[string]string `json:"avatar_variants,omitempty"`
+	Role           string            `json:"role"` // admin,
+	Status         string            `json:"status"`
+	EmailVerified  bool              `json:"email_verified"`
+	CreatedAt      time.Time         `json:"created_at"`
 }
 
 type ForgotPasswordRequest struct {
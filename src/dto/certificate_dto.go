@@ -0,0 +1,35 @@
+package dto
+
+import "time"
+
+type CertificateItem struct {
+	Id             uint       `json:"id"`
+	Serial         string     `json:"serial"`
+	CourseId       uint       `json:"course_id"`
+	CourseTitle    string     `json:"course_title"`
+	LearnerName    string     `json:"learner_name"`
+	InstructorName string     `json:"instructor_name"`
+	CompletedAt    time.Time  `json:"completed_at"`
+	IssuedAt       time.Time  `json:"issued_at"`
+	Revision       int        `json:"revision"`
+	ReissuedAt     *time.Time `json:"reissued_at"`
+	VerifyURL      string     `json:"verify_url"`
+}
+
+type GetMyCertificatesResponse struct {
+	Certificates []CertificateItem `json:"certificates"`
+}
+
+// CertificateVerificationResponse - thông tin công khai để bên thứ ba đối chiếu với bản PDF học viên gửi
+type CertificateVerificationResponse struct {
+	Valid          bool       `json:"valid"`
+	InvalidReason  string     `json:"invalid_reason,omitempty"` // revoked, refunded khi Valid = false
+	Serial         string     `json:"serial"`
+	LearnerName    string     `json:"learner_name"`
+	CourseTitle    string     `json:"course_title"`
+	InstructorName string     `json:"instructor_name"`
+	CompletedAt    time.Time  `json:"completed_at"`
+	IssuedAt       time.Time  `json:"issued_at"`
+	Revision       int        `json:"revision"` // Bản PDF có revision cũ hơn là bản đã được cấp lại
+	ReissuedAt     *time.Time `json:"reissued_at"`
+}
@@ -3,27 +3,28 @@ package dto
 import "time"
 
 type CourseItem struct {
-	Id             uint      `json:"id"`
-	Title          string    `json:"title"`
-	Slug           string    `json:"slug"`
-	ShortDesc      string    `json:"short_description"`
-	ThumbnailURL   string    `json:"thumbnail_url"`
-	Price          float64   `json:"price"`
-	DiscountPrice  *float64  `json:"discount_price"`
-	InstructorId   uint      `json:"instructor_id"`
-	InstructorName string    `json:"instructor_name"`
-	CategoryId     uint      `json:"category_id"`
-	CategoryName   string    `json:"category_name"`
-	Level          string    `json:"level"`
-	DurationHours  int       `json:"duration_hours"`
-	TotalLessons   int       `json:"total_lessons"`
-	Language       string    `json:"language"`
-	Status         string    `json:"status"`
-	IsFeatured     bool      `json:"is_featured"`
-	RatingAvg      float32   `json:"rating_avg"`
-	RatingCount    int       `json:"rating_count"`
-	EnrolledCount  int       `json:"enrolled_count"`
-	CreatedAt      time.Time `json:"created_at"`
+	Id                uint              `json:"id"`
+	Title             string            `json:"title"`
+	Slug              string            `json:"slug"`
+	ShortDesc         string            `json:"short_description"`
+	ThumbnailURL      string            `json:"thumbnail_url"`
+	ThumbnailVariants map[string]string `json:"thumbnail_variants,omitempty"`
+	Price             float64           `json:"price"`
+	DiscountPrice     *float64          `json:"discount_price"`
+	InstructorId      uint              `json:"instructor_id"`
+	InstructorName    string            `json:"instructor_name"`
+	CategoryId        uint              `json:"category_id"`
+	CategoryName      string            `json:"category_name"`
+	Level             string            `json:"level"`
+	DurationHours     int               `json:"duration_hours"`
+	TotalLessons      int               `json:"total_lessons"`
+	Language          string            `json:"language"`
+	Status            string            `json:"status"`
+	IsFeatured        bool              `json:"is_featured"`
+	RatingAvg         float32           `json:"rating_avg"`
+	RatingCount       int               `json:"rating_count"`
+	EnrolledCount     int               `json:"enrolled_count"`
+	CreatedAt         time.Time         `json:"created_at"`
 }
 
 type GetCoursesQueryRequest struct {
@@ -93,33 +94,39 @@ type GetFeaturedCoursesResponse struct {
 }
 
 type CourseDetail struct {
-	Id              uint      `json:"id"`
-	Title           string    `json:"title"`
-	Slug            string    `json:"slug"`
-	Description     string    `json:"description"`
-	ShortDesc       string    `json:"short_description"`
-	ThumbnailURL    string    `json:"thumbnail_url"`
-	VideoPreviewURL string    `json:"video_preview_url"`
-	Price           float64   `json:"price"`
-	DiscountPrice   *float64  `json:"discount_price"`
-	InstructorId    uint      `json:"instructor_id"`
-	InstructorName  string    `json:"instructor_name"`
-	InstructorBio   string    `json:"instructor_bio"`
-	CategoryId      uint      `json:"category_id"`
-	CategoryName    string    `json:"category_name"`
-	Level           string    `json:"level"`
-	DurationHours   int       `json:"duration_hours"`
-	TotalLessons    int       `json:"total_lessons"`
-	Language        string    `json:"language"`
-	Requirements    string    `json:"requirements"`
-	WhatYouLearn    string    `json:"what_you_learn"`
-	Status          string    `json:"status"`
-	IsFeatured      bool      `json:"is_featured"`
-	RatingAvg       float32   `json:"rating_avg"`
-	RatingCount     int       `json:"rating_count"`
-	EnrolledCount   int       `json:"enrolled_count"`
-	CreatedAt       time.Time `json:"created_at"`
-	UpdatedAt       time.Time `json:"updated_at"`
+	Id                 uint              `json:"id"`
+	Title              string            `json:"title"`
+	Slug               string            `json:"slug"`
+	Description        string            `json:"description"`
+	DescriptionHTML    string            `json:"description_html"`
+	ShortDesc          string            `json:"short_description"`
+	ThumbnailURL       string            `json:"thumbnail_url"`
+	ThumbnailVariants  map[string]string `json:"thumbnail_variants,omitempty"`
+	VideoPreviewURL    string            `json:"video_preview_url"`
+	Price              float64           `json:"price"`
+	DiscountPrice      *float64          `json:"discount_price"`
+	InstructorId       uint              `json:"instructor_id"`
+	InstructorName     string            `json:"instructor_name"`
+	InstructorBio      string            `json:"instructor_bio"`
+	CategoryId         uint              `json:"category_id"`
+	CategoryName       string            `json:"category_name"`
+	Level              string            `json:"level"`
+	DurationHours      int               `json:"duration_hours"`
+	AccessDurationDays int               `json:"access_duration_days"` // 0 = truy cập trọn đời
+	TotalLessons       int               `json:"total_lessons"`
+	Language           string            `json:"language"`
+	Requirements       string            `json:"requirements"`
+	WhatYouLearn       string            `json:"what_you_learn"`
+	Status             string            `json:"status"`
+	IsFeatured         bool              `json:"is_featured"`
+	RatingAvg          float32           `json:"rating_avg"`
+	RatingCount        int               `json:"rating_count"`
+	EnrolledCount      int               `json:"enrolled_count"`
+	CreatedAt          time.Time         `json:"created_at"`
+	UpdatedAt          time.Time         `json:"updated_at"`
+
+	// Các course cần hoàn thành trước khi enroll
+	Prerequisites []CoursePrerequisiteItem `json:"prerequisites"`
 }
 
 type ReviewItem struct {
@@ -0,0 +1,78 @@
+package dto
+
+import "time"
+
+type ModerationChecklist struct {
+	HasThumbnail         bool `json:"has_thumbnail"`
+	LessonCount          int  `json:"lesson_count"`
+	MinLessons           int  `json:"min_lessons"`
+	HasMinLessons        bool `json:"has_min_lessons"`
+	HasPreviewLesson     bool `json:"has_preview_lesson"`
+	DescriptionLength    int  `json:"description_length"`
+	MinDescriptionLength int  `json:"min_description_length"`
+	HasDescription       bool `json:"has_description"`
+	Passed               bool `json:"passed"`
+}
+
+type CourseModerationItem struct {
+	Id             uint                `json:"id"`
+	CourseId       uint                `json:"course_id"`
+	CourseTitle    string              `json:"course_title"`
+	CourseSlug     string              `json:"course_slug"`
+	InstructorId   uint                `json:"instructor_id"`
+	InstructorName string              `json:"instructor_name"`
+	Status         string              `json:"status"`
+	Checklist      ModerationChecklist `json:"checklist"`
+	SubmitNote     string              `json:"submit_note"`
+	ReviewerId     *uint               `json:"reviewer_id"`
+	ReviewComment  string              `json:"review_comment"`
+	SubmittedAt    time.Time           `json:"submitted_at"`
+	ReviewedAt     *time.Time          `json:"reviewed_at"`
+}
+
+// POST /api/v1/instructor/courses/:course_id/submit-review
+type SubmitCourseReviewRequest struct {
+	Note string `json:"note" binding:"omitempty,max=1000"`
+}
+
+type SubmitCourseReviewResponse struct {
+	Message      string               `json:"message"`
+	CourseStatus string               `json:"course_status"`
+	Moderation   CourseModerationItem `json:"moderation"`
+}
+
+// GET /api/v1/instructor/courses/:course_id/reviews
+type GetCourseModerationsResponse struct {
+	CourseId     uint                   `json:"course_id"`
+	CourseStatus string                 `json:"course_status"`
+	Moderations  []CourseModerationItem `json:"moderations"`
+}
+
+// GET /api/v1/admin/moderation/courses - Query parameters
+type GetModerationQueueQueryRequest struct {
+	Page         int    `form:"page" binding:"omitempty,min=1"`
+	Limit        int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Status       string `form:"status" binding:"omitempty,oneof=pending approved rejected"`
+	InstructorId uint   `form:"instructor_id" binding:"omitempty"`
+}
+
+type GetModerationQueueResponse struct {
+	Moderations []CourseModerationItem `json:"moderations"`
+	Pagination  PaginationInfo         `json:"pagination"`
+}
+
+type ApproveCourseRequest struct {
+	Comment string `json:"comment" binding:"omitempty,max=1000"`
+}
+
+type RejectCourseRequest struct {
+	Comment string `json:"comment" binding:"required,min=10,max=1000"`
+}
+
+type ReviewCourseResponse struct {
+	Message      string `json:"message"`
+	ModerationId uint   `json:"moderation_id"`
+	CourseId     uint   `json:"course_id"`
+	Status       string `json:"status"`
+	CourseStatus string `json:"course_status"`
+}
@@ -0,0 +1,32 @@
+package dto
+
+// POST /api/v1/instructor/courses/import (multipart, field "package")
+type ImportCoursePackageRequest struct {
+	CategoryId uint `form:"category_id"` // Dùng category này thay cho category trong package
+	DryRun     bool `form:"dry_run"`     // Chỉ kiểm tra package và báo conflict, không tạo course
+}
+
+type CoursePackageConflict struct {
+	Type     string `json:"type"` // invalid_package, invalid_file, missing_category, inactive_category, slug_collision
+	Field    string `json:"field"`
+	Message  string `json:"message"`
+	Blocking bool   `json:"blocking"` // false: conflict đã được tự xử lý (vd: slug được đổi)
+}
+
+type ImportCoursePackageResponse struct {
+	Message          string                  `json:"message"`
+	DryRun           bool                    `json:"dry_run"`
+	Imported         bool                    `json:"imported"`
+	FormatVersion    int                     `json:"format_version"`
+	CourseId         uint                    `json:"course_id,omitempty"`
+	Title            string                  `json:"title"`
+	Slug             string                  `json:"slug"` // Slug sẽ dùng (đã đổi nếu trùng)
+	Status           string                  `json:"status"`
+	CategoryId       uint                    `json:"category_id,omitempty"`
+	TotalLessons     int                     `json:"total_lessons"`
+	TotalQuizzes     int                     `json:"total_quizzes"`
+	TotalQuestions   int                     `json:"total_questions"`
+	TotalAssignments int                     `json:"total_assignments"`
+	TotalResources   int                     `json:"total_resources"`
+	Conflicts        []CoursePackageConflict `json:"conflicts"`
+}
@@ -0,0 +1,21 @@
+package dto
+
+type CoursePrerequisiteItem struct {
+	CourseId     uint   `json:"course_id"`
+	Title        string `json:"title"`
+	Slug         string `json:"slug"`
+	ThumbnailURL string `json:"thumbnail_url"`
+	Level        string `json:"level"`
+}
+
+// GET /api/v1/instructor/courses/:course_id/prerequisites - Response
+type GetCoursePrerequisitesResponse struct {
+	CourseId      uint                     `json:"course_id"`
+	Prerequisites []CoursePrerequisiteItem `json:"prerequisites"`
+}
+
+// PUT /api/v1/instructor/courses/:course_id/prerequisites - Request body
+// Gửi danh sách rỗng để xóa toàn bộ prerequisites
+type UpdateCoursePrerequisitesRequest struct {
+	PrerequisiteCourseIds []uint `json:"prerequisite_course_ids" binding:"max=20,dive,min=1"`
+}
@@ -0,0 +1,143 @@
+package dto
+
+import "time"
+
+// Nội dung của một revision (metadata course + danh sách lessons)
+type RevisionSnapshot struct {
+	Title           string                   `json:"title"`
+	Description     string                   `json:"description"`
+	ShortDesc       string                   `json:"short_description"`
+	ThumbnailURL    string                   `json:"thumbnail_url"`
+	VideoPreviewURL string                   `json:"video_preview_url"`
+	Price           float64                  `json:"price"`
+	DiscountPrice   *float64                 `json:"discount_price"`
+	CategoryId      uint                     `json:"category_id"`
+	Level           string                   `json:"level"`
+	Language        string                   `json:"language"`
+	Requirements    string                   `json:"requirements"`
+	WhatYouLearn    string                   `json:"what_you_learn"`
+	DurationHours   int                      `json:"duration_hours"`
+	Lessons         []RevisionLessonSnapshot `json:"lessons"`
+}
+
+type RevisionLessonSnapshot struct {
+	LessonId      uint   `json:"lesson_id"`
+	Title         string `json:"title"`
+	Description   string `json:"description"`
+	Content       string `json:"content"`
+	VideoURL      string `json:"video_url"`
+	VideoDuration int    `json:"video_duration"`
+	LessonOrder   int    `json:"lesson_order"`
+	IsPreview     bool   `json:"is_preview"`
+	IsPublished   bool   `json:"is_published"`
+
+	UnlockAfterDays           int  `json:"unlock_after_days"`
+	RequirePreviousCompletion bool `json:"require_previous_completion"`
+}
+
+type CourseRevisionItem struct {
+	Id             uint       `json:"id"`
+	CourseId       uint       `json:"course_id"`
+	RevisionNumber int        `json:"revision_number"`
+	Status         string     `json:"status"`
+	Note           string     `json:"note"`
+	CreatedBy      uint       `json:"created_by"`
+	TotalLessons   int        `json:"total_lessons"`
+	PublishedAt    *time.Time `json:"published_at"`
+	RolledBackFrom *uint      `json:"rolled_back_from"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+type CourseRevisionDetail struct {
+	CourseRevisionItem
+	Snapshot RevisionSnapshot `json:"snapshot"`
+}
+
+// GET /api/v1/instructor/courses/:course_id/revisions - Query parameters
+type GetCourseRevisionsQueryRequest struct {
+	Page  int `form:"page" binding:"omitempty,min=1"`
+	Limit int `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+type GetCourseRevisionsResponse struct {
+	CourseId   uint                 `json:"course_id"`
+	Revisions  []CourseRevisionItem `json:"revisions"`
+	Pagination PaginationInfo       `json:"pagination"`
+}
+
+type CreateCourseRevisionRequest struct {
+	Note string `json:"note" binding:"omitempty,max=500"`
+}
+
+// PUT /api/v1/instructor/courses/:course_id/revisions/draft
+// Lessons nếu được gửi sẽ thay thế toàn bộ danh sách lessons của draft
+type UpdateCourseRevisionRequest struct {
+	Title           *string                     `json:"title" binding:"omitempty,min=5,max=200"`
+	Description     *string                     `json:"description" binding:"omitempty,min=20,max=100000"`
+	ShortDesc       *string                     `json:"short_description" binding:"omitempty,min=10,max=500"`
+	ThumbnailURL    *string                     `json:"thumbnail_url" binding:"omitempty,url"`
+	VideoPreviewURL *string                     `json:"video_preview_url" binding:"omitempty,url"`
+	Price           *float64                    `json:"price" binding:"omitempty,positive_float"`
+	DiscountPrice   *float64                    `json:"discount_price" binding:"omitempty,positive_float"`
+	CategoryId      *uint                       `json:"category_id" binding:"omitempty"`
+	Level           *string                     `json:"level" binding:"omitempty,course_level"`
+	Language        *string                     `json:"language" binding:"omitempty,language_code"`
+	Requirements    *string                     `json:"requirements"`
+	WhatYouLearn    *string                     `json:"what_you_learn"`
+	DurationHours   *int                        `json:"duration_hours" binding:"omitempty,min_int=0"`
+	Note            *string                     `json:"note" binding:"omitempty,max=500"`
+	Lessons         *[]UpdateRevisionLessonItem `json:"lessons" binding:"omitempty,dive"`
+}
+
+type UpdateRevisionLessonItem struct {
+	LessonId      uint   `json:"lesson_id"`
+	Title         string `json:"title" binding:"required,min=3,max=200"`
+	Description   string `json:"description" binding:"required,min=10"`
+	Content       string `json:"content" binding:"omitempty,max=100000"`
+	VideoURL      string `json:"video_url" binding:"omitempty,url"`
+	VideoDuration int    `json:"video_duration" binding:"omitempty,min=0"`
+	LessonOrder   int    `json:"lesson_order" binding:"required,min=1"`
+	IsPreview     bool   `json:"is_preview"`
+	IsPublished   bool   `json:"is_published"`
+
+	UnlockAfterDays           int  `json:"unlock_after_days" binding:"omitempty,min=0,max=3650"`
+	RequirePreviousCompletion bool `json:"require_previous_completion"`
+}
+
+type DeleteCourseRevisionResponse struct {
+	Message    string `json:"message"`
+	RevisionId uint   `json:"revision_id"`
+}
+
+type RevisionFieldChange struct {
+	Field string      `json:"field"`
+	Live  interface{} `json:"live"`
+	Draft interface{} `json:"draft"`
+}
+
+type RevisionLessonChange struct {
+	LessonId uint                  `json:"lesson_id"`
+	Title    string                `json:"title"`
+	Changes  []RevisionFieldChange `json:"changes"`
+}
+
+type CourseRevisionDiffResponse struct {
+	CourseId       uint                     `json:"course_id"`
+	RevisionId     uint                     `json:"revision_id"`
+	RevisionNumber int                      `json:"revision_number"`
+	HasChanges     bool                     `json:"has_changes"`
+	CourseChanges  []RevisionFieldChange    `json:"course_changes"`
+	LessonsAdded   []RevisionLessonSnapshot `json:"lessons_added"`
+	LessonsRemoved []RevisionLessonSnapshot `json:"lessons_removed"`
+	LessonsChanged []RevisionLessonChange   `json:"lessons_changed"`
+	OrderChanged   bool                     `json:"order_changed"`
+}
+
+type PublishCourseRevisionResponse struct {
+	Message        string `json:"message"`
+	CourseId       uint   `json:"course_id"`
+	RevisionId     uint   `json:"revision_id"`
+	RevisionNumber int    `json:"revision_number"`
+	TotalLessons   int    `json:"total_lessons"`
+}
@@ -10,47 +10,54 @@ type EnrollCourseRequest struct {
 
 // EnrollCourseResponse - Response sau khi enroll thành công
 type EnrollCourseResponse struct {
-	EnrollmentId   uint      `json:"enrollment_id"`
-	OrderId        uint      `json:"order_id"`
-	OrderCode      string    `json:"order_code"`
-	CourseId       uint      `json:"course_id"`
-	CourseTitle    string    `json:"course_title"`
-	OriginalPrice  float64   `json:"original_price"`
-	DiscountAmount float64   `json:"discount_amount"`
-	FinalPrice     float64   `json:"final_price"`
-	PaymentMethod  string    `json:"payment_method"`
-	PaymentStatus  string    `json:"payment_status"`
-	EnrolledAt     time.Time `json:"enrolled_at"`
-	Message        string    `json:"message"`
+	EnrollmentId   uint       `json:"enrollment_id,omitempty"` // Chỉ có khi order đã thanh toán (course free)
+	OrderId        uint       `json:"order_id"`
+	OrderCode      string     `json:"order_code"`
+	CourseId       uint       `json:"course_id"`
+	CourseTitle    string     `json:"course_title"`
+	OriginalPrice  float64    `json:"original_price"`
+	DiscountAmount float64    `json:"discount_amount"`
+	FinalPrice     float64    `json:"final_price"`
+	PaymentMethod  string     `json:"payment_method"`
+	PaymentStatus  string     `json:"payment_status"`
+	EnrolledAt     *time.Time `json:"enrolled_at,omitempty"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
+	Message        string     `json:"message"`
 }
 
 // CheckEnrollmentResponse - Kiểm tra user đã enroll chưa
 type CheckEnrollmentResponse struct {
-	IsEnrolled      bool       `json:"is_enrolled"`
+	IsEnrolled      bool       `json:"is_enrolled"` // Còn quyền truy cập course
 	EnrollmentId    uint       `json:"enrollment_id,omitempty"`
 	EnrolledAt      *time.Time `json:"enrolled_at,omitempty"`
 	ProgressPercent float64    `json:"progress_percent,omitempty"`
+	Status          string     `json:"status,omitempty"`
+	ExpiresAt       *time.Time `json:"expires_at,omitempty"`
+	CanRenew        bool       `json:"can_renew"` // Được enroll lại/gia hạn (giữ progress cũ)
 }
 
 // GetMyEnrollmentsQueryRequest - Query parameters cho danh sách enrollments
 type GetMyEnrollmentsQueryRequest struct {
 	Page   int    `form:"page" binding:"omitempty,min=1"`
 	Limit  int    `form:"limit" binding:"omitempty,min=1,max=50"`
-	Status string `form:"status" binding:"omitempty,oneof=active completed dropped"`
+	Status string `form:"status" binding:"omitempty,oneof=active completed dropped expired revoked"`
 }
 
 // EnrollmentItem - Thông tin enrollment
 type EnrollmentItem struct {
-	Id                 uint      `json:"id"`
-	CourseId           uint      `json:"course_id"`
-	CourseTitle        string    `json:"course_title"`
-	CourseThumbnail    string    `json:"course_thumbnail"`
-	InstructorName     string    `json:"instructor_name"`
-	EnrolledAt         time.Time `json:"enrolled_at"`
-	ProgressPercentage float64   `json:"progress_percentage"`
-	Status             string    `json:"status"`
-	TotalLessons       int       `json:"total_lessons"`
-	CompletedLessons   int       `json:"completed_lessons"`
+	Id                 uint       `json:"id"`
+	CourseId           uint       `json:"course_id"`
+	CourseTitle        string     `json:"course_title"`
+	CourseThumbnail    string     `json:"course_thumbnail"`
+	InstructorName     string     `json:"instructor_name"`
+	EnrolledAt         time.Time  `json:"enrolled_at"`
+	ProgressPercentage float64    `json:"progress_percentage"`
+	Status             string     `json:"status"`
+	ExpiresAt          *time.Time `json:"expires_at"`
+	EnrollmentSource   string     `json:"enrollment_source"`
+	DroppedAt          *time.Time `json:"dropped_at,omitempty"`
+	TotalLessons       int        `json:"total_lessons"`
+	CompletedLessons   int        `json:"completed_lessons"`
 }
 
 // GetMyEnrollmentsResponse - Response danh sách enrollments
@@ -58,3 +65,12 @@ type GetMyEnrollmentsResponse struct {
 	Enrollments []EnrollmentItem `json:"enrollments"`
 	Pagination  PaginationInfo   `json:"pagination"`
 }
+
+// DropEnrollmentResponse - Response sau khi học viên tự hủy enrollment
+type DropEnrollmentResponse struct {
+	Message      string    `json:"message"`
+	EnrollmentId uint      `json:"enrollment_id"`
+	CourseId     uint      `json:"course_id"`
+	Status       string    `json:"status"`
+	DroppedAt    time.Time `json:"dropped_at"`
+}
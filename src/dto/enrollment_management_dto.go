@@ -0,0 +1,88 @@
+package dto
+
+import "time"
+
+// ManagedEnrollmentItem - enrollment trong các thao tác cấp/thu hồi quyền học thủ công
+type ManagedEnrollmentItem struct {
+	Id                uint       `json:"id"`
+	UserId            uint       `json:"user_id"`
+	UserEmail         string     `json:"user_email"`
+	UserFullName      string     `json:"user_full_name"`
+	CourseId          uint       `json:"course_id"`
+	CourseTitle       string     `json:"course_title"`
+	Status            string     `json:"status"`
+	EnrollmentSource  string     `json:"enrollment_source"`
+	EnrolledAt        time.Time  `json:"enrolled_at"`
+	ExpiresAt         *time.Time `json:"expires_at"`
+	GrantedBy         *uint      `json:"granted_by"`
+	TransferredFromId *uint      `json:"transferred_from_id,omitempty"`
+	RevokedAt         *time.Time `json:"revoked_at,omitempty"`
+	RevokeReason      string     `json:"revoke_reason,omitempty"`
+}
+
+// AdminEnrollUserRequest - Admin cấp quyền học cho một user (theo user_id hoặc email)
+type AdminEnrollUserRequest struct {
+	CourseId           uint   `json:"course_id" binding:"required"`
+	UserId             uint   `json:"user_id"` // Một trong user_id hoặc email
+	Email              string `json:"email" binding:"omitempty,email"`
+	Source             string `json:"enrollment_source" binding:"omitempty,oneof=admin_grant org_seat"` // Mặc định admin_grant
+	AccessDurationDays *int   `json:"access_duration_days" binding:"omitempty,min=0,max=3650"`          // Mặc định theo course, 0 = trọn đời
+}
+
+// BulkEnrollRequest - form fields đi kèm file CSV danh sách email
+type BulkEnrollRequest struct {
+	Source             string `form:"enrollment_source" binding:"omitempty,oneof=admin_grant org_seat"`
+	AccessDurationDays *int   `form:"access_duration_days" binding:"omitempty,min=0,max=3650"`
+}
+
+type BulkEnrollResult struct {
+	Row          int    `json:"row"`
+	Email        string `json:"email"`
+	Status       string `json:"status"` // enrolled, renewed, skipped, failed
+	Message      string `json:"message,omitempty"`
+	EnrollmentId uint   `json:"enrollment_id,omitempty"`
+}
+
+type BulkEnrollResponse struct {
+	CourseId uint               `json:"course_id"`
+	Total    int                `json:"total"`
+	Enrolled int                `json:"enrolled"`
+	Renewed  int                `json:"renewed"`
+	Skipped  int                `json:"skipped"`
+	Failed   int                `json:"failed"`
+	Results  []BulkEnrollResult `json:"results"`
+}
+
+type RevokeEnrollmentRequest struct {
+	Reason string `json:"reason" binding:"required,min=3,max=500"`
+}
+
+// TransferEnrollmentRequest - chuyển quyền học sang user khác (theo user_id hoặc email)
+type TransferEnrollmentRequest struct {
+	ToUserId uint   `json:"to_user_id"` // Một trong to_user_id hoặc to_email
+	ToEmail  string `json:"to_email" binding:"omitempty,email"`
+	Reason   string `json:"reason" binding:"required,min=3,max=500"`
+}
+
+type TransferEnrollmentResponse struct {
+	Message string                `json:"message"`
+	From    ManagedEnrollmentItem `json:"from"`
+	To      ManagedEnrollmentItem `json:"to"`
+}
+
+// GrantComplimentaryAccessRequest - Giảng viên tặng quyền học cho học viên
+type GrantComplimentaryAccessRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type RevokeComplimentaryAccessRequest struct {
+	Reason string `json:"reason" binding:"omitempty,max=500"`
+}
+
+type ComplimentaryAccessResponse struct {
+	CourseId  uint                    `json:"course_id"`
+	Quota     int                     `json:"quota"`
+	Used      int                     `json:"used"`
+	Remaining int                     `json:"remaining"`
+	Grants    []ManagedEnrollmentItem `json:"grants"`
+}
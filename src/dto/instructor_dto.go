@@ -7,7 +7,7 @@ import "time"
 type GetInstructorCoursesQueryRequest struct {
 	Page    int    `form:"page" binding:"omitempty,min=1"`
 	Limit   int    `form:"limit" binding:"omitempty,min=1,max=100"`
-	Status  string `form:"status" binding:"omitempty,oneof=draft published archived"`
+	Status  string `form:"status" binding:"omitempty,course_status"`
 	Search  string `form:"search" binding:"omitempty,search"`
 	OrderBy string `form:"order_by" binding:"omitempty,oneof=created_at updated_at title enrolled_count rating_avg"`
 	SortBy  string `form:"sort_by" binding:"omitempty,oneof=asc desc"`
@@ -15,24 +15,25 @@ type GetInstructorCoursesQueryRequest struct {
 
 // Response item cho mỗi course
 type InstructorCourseItem struct {
-	Id            uint      `json:"id"`
-	Title         string    `json:"title"`
-	Slug          string    `json:"slug"`
-	ThumbnailURL  string    `json:"thumbnail_url"`
-	Price         float64   `json:"price"`
-	DiscountPrice *float64  `json:"discount_price"`
-	CategoryId    uint      `json:"category_id"`
-	CategoryName  string    `json:"category_name"`
-	Level         string    `json:"level"`
-	Status        string    `json:"status"`
-	TotalLessons  int       `json:"total_lessons"`
-	DurationHours int       `json:"duration_hours"`
-	EnrolledCount int       `json:"enrolled_count"`
-	RatingAvg     float32   `json:"rating_avg"`
-	RatingCount   int       `json:"rating_count"`
-	IsFeatured    bool      `json:"is_featured"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	Id                uint              `json:"id"`
+	Title             string            `json:"title"`
+	Slug              string            `json:"slug"`
+	ThumbnailURL      string            `json:"thumbnail_url"`
+	ThumbnailVariants map[string]string `json:"thumbnail_variants,omitempty"`
+	Price             float64           `json:"price"`
+	DiscountPrice     *float64          `json:"discount_price"`
+	CategoryId        uint              `json:"category_id"`
+	CategoryName      string            `json:"category_name"`
+	Level             string            `json:"level"`
+	Status            string            `json:"status"`
+	TotalLessons      int               `json:"total_lessons"`
+	DurationHours     int               `json:"duration_hours"`
+	EnrolledCount     int               `json:"enrolled_count"`
+	RatingAvg         float32           `json:"rating_avg"`
+	RatingCount       int               `json:"rating_count"`
+	IsFeatured        bool              `json:"is_featured"`
+	CreatedAt         time.Time         `json:"created_at"`
+	UpdatedAt         time.Time         `json:"updated_at"`
 }
 
 // Response cho danh sách courses
@@ -42,89 +43,118 @@ type GetInstructorCoursesResponse struct {
 }
 
 type CreateCourseRequest struct {
-	Title         string   `json:"title" binding:"required,min=5,max=200"`
-	Description   string   `json:"description" binding:"required,min=20"`
-	ShortDesc     string   `json:"short_description" binding:"required,min=10,max=500"`
-	CategoryId    uint     `json:"category_id" binding:"required"`
-	Level         string   `json:"level" binding:"required,course_level"`
-	Language      string   `json:"language" binding:"required,language_code"`
-	Price         float64  `json:"price" binding:"required,positive_float"`
-	DiscountPrice *float64 `json:"discount_price" binding:"omitempty,positive_float"`
-	Requirements  string   `json:"requirements" binding:"omitempty"`
-	WhatYouLearn  string   `json:"what_you_learn" binding:"omitempty"`
-	DurationHours int      `json:"duration_hours" binding:"omitempty,min_int=0"`
+	Title              string     `json:"title" binding:"required,min=5,max=200"`
+	Description        string     `json:"description" binding:"required,min=20,max=100000"`
+	ShortDesc          string     `json:"short_description" binding:"required,min=10,max=500"`
+	CategoryId         uint       `json:"category_id" binding:"required"`
+	Level              string     `json:"level" binding:"required,course_level"`
+	Language           string     `json:"language" binding:"required,language_code"`
+	Price              float64    `json:"price" binding:"required,positive_float"`
+	DiscountPrice      *float64   `json:"discount_price" binding:"omitempty,positive_float"`
+	Requirements       string     `json:"requirements" binding:"omitempty"`
+	WhatYouLearn       string     `json:"what_you_learn" binding:"omitempty"`
+	DurationHours      int        `json:"duration_hours" binding:"omitempty,min_int=0"`
+	AccessDurationDays int        `json:"access_duration_days" binding:"omitempty,min=0,max=3650"` // 0 = truy cập trọn đời
+	PublishAt          *time.Time `json:"publish_at" binding:"omitempty"`
+	UnpublishAt        *time.Time `json:"unpublish_at" binding:"omitempty"`
 }
 
 type CreateCourseResponse struct {
-	Id              uint      `json:"id"`
-	Title           string    `json:"title"`
-	Slug            string    `json:"slug"`
-	Description     string    `json:"description"`
-	ShortDesc       string    `json:"short_description"`
-	ThumbnailURL    string    `json:"thumbnail_url"`
-	VideoPreviewURL string    `json:"video_preview_url"`
-	Price           float64   `json:"price"`
-	DiscountPrice   *float64  `json:"discount_price"`
-	InstructorId    uint      `json:"instructor_id"`
-	CategoryId      uint      `json:"category_id"`
-	CategoryName    string    `json:"category_name"`
-	Level           string    `json:"level"`
-	DurationHours   int       `json:"duration_hours"`
-	TotalLessons    int       `json:"total_lessons"`
-	Language        string    `json:"language"`
-	Requirements    string    `json:"requirements"`
-	WhatYouLearn    string    `json:"what_you_learn"`
-	Status          string    `json:"status"`
-	IsFeatured      bool      `json:"is_featured"`
-	RatingAvg       float32   `json:"rating_avg"`
-	RatingCount     int       `json:"rating_count"`
-	EnrolledCount   int       `json:"enrolled_count"`
-	CreatedAt       time.Time `json:"created_at"`
-	UpdatedAt       time.Time `json:"updated_at"`
+	Id                 uint              `json:"id"`
+	Title              string            `json:"title"`
+	Slug               string            `json:"slug"`
+	Description        string            `json:"description"`
+	DescriptionHTML    string            `json:"description_html"`
+	ShortDesc          string            `json:"short_description"`
+	ThumbnailURL       string            `json:"thumbnail_url"`
+	ThumbnailVariants  map[string]string `json:"thumbnail_variants,omitempty"`
+	VideoPreviewURL    string            `json:"video_preview_url"`
+	Price              float64           `json:"price"`
+	DiscountPrice      *float64          `json:"discount_price"`
+	InstructorId       uint              `json:"instructor_id"`
+	CategoryId         uint              `json:"category_id"`
+	CategoryName       string            `json:"category_name"`
+	Level              string            `json:"level"`
+	DurationHours      int               `json:"duration_hours"`
+	AccessDurationDays int               `json:"access_duration_days"`
+	TotalLessons       int               `json:"total_lessons"`
+	Language           string            `json:"language"`
+	Requirements       string            `json:"requirements"`
+	WhatYouLearn       string            `json:"what_you_learn"`
+	Status             string            `json:"status"`
+	IsFeatured         bool              `json:"is_featured"`
+	RatingAvg          float32           `json:"rating_avg"`
+	RatingCount        int               `json:"rating_count"`
+	EnrolledCount      int               `json:"enrolled_count"`
+	PublishAt          *time.Time        `json:"publish_at"`
+	UnpublishAt        *time.Time        `json:"unpublish_at"`
+	CreatedAt          time.Time         `json:"created_at"`
+	UpdatedAt          time.Time         `json:"updated_at"`
 }
 
 type UpdateCourseRequest struct {
-	Title         string   `json:"title" binding:"omitempty,min=5,max=200"`
-	Description   string   `json:"description" binding:"omitempty,min=20"`
-	ShortDesc     string   `json:"short_description" binding:"omitempty,min=10,max=500"`
-	CategoryId    uint     `json:"category_id" binding:"omitempty"`
-	Level         string   `json:"level" binding:"omitempty,course_level"`
-	Language      string   `json:"language" binding:"omitempty,language_code"`
-	Price         float64  `json:"price" binding:"omitempty,positive_float"`
-	DiscountPrice *float64 `json:"discount_price" binding:"omitempty,positive_float"`
-	Requirements  string   `json:"requirements"`
-	WhatYouLearn  string   `json:"what_you_learn"`
-	DurationHours int      `json:"duration_hours" binding:"omitempty,min_int=0"`
-	Status        string   `json:"status" binding:"omitempty,course_status"`
-	IsFeatured    *bool    `json:"is_featured"`
+	Title              string     `json:"title" binding:"omitempty,min=5,max=200"`
+	Description        string     `json:"description" binding:"omitempty,min=20,max=100000"`
+	ShortDesc          string     `json:"short_description" binding:"omitempty,min=10,max=500"`
+	CategoryId         uint       `json:"category_id" binding:"omitempty"`
+	Level              string     `json:"level" binding:"omitempty,course_level"`
+	Language           string     `json:"language" binding:"omitempty,language_code"`
+	Price              float64    `json:"price" binding:"omitempty,positive_float"`
+	DiscountPrice      *float64   `json:"discount_price" binding:"omitempty,positive_float"`
+	Requirements       string     `json:"requirements"`
+	WhatYouLearn       string     `json:"what_you_learn"`
+	DurationHours      int        `json:"duration_hours" binding:"omitempty,min_int=0"`
+	AccessDurationDays *int       `json:"access_duration_days" binding:"omitempty,min=0,max=3650"` // 0 = truy cập trọn đời
+	Status             string     `json:"status" binding:"omitempty,course_status"`
+	IsFeatured         *bool      `json:"is_featured"`
+	PublishAt          *time.Time `json:"publish_at" binding:"omitempty"`
+	UnpublishAt        *time.Time `json:"unpublish_at" binding:"omitempty"`
+	ClearSchedule      bool       `json:"clear_schedule"` // Xóa publish_at/unpublish_at đã đặt
 }
 
 type UpdateCourseResponse struct {
-	Id              uint      `json:"id"`
-	Title           string    `json:"title"`
-	Slug            string    `json:"slug"`
-	Description     string    `json:"description"`
-	ShortDesc       string    `json:"short_description"`
-	ThumbnailURL    string    `json:"thumbnail_url"`
-	VideoPreviewURL string    `json:"video_preview_url"`
-	Price           float64   `json:"price"`
-	DiscountPrice   *float64  `json:"discount_price"`
-	InstructorId    uint      `json:"instructor_id"`
-	CategoryId      uint      `json:"category_id"`
-	CategoryName    string    `json:"category_name"`
-	Level           string    `json:"level"`
-	DurationHours   int       `json:"duration_hours"`
-	TotalLessons    int       `json:"total_lessons"`
-	Language        string    `json:"language"`
-	Requirements    string    `json:"requirements"`
-	WhatYouLearn    string    `json:"what_you_learn"`
-	Status          string    `json:"status"`
-	IsFeatured      bool      `json:"is_featured"`
-	RatingAvg       float32   `json:"rating_avg"`
-	RatingCount     int       `json:"rating_count"`
-	EnrolledCount   int       `json:"enrolled_count"`
-	CreatedAt       time.Time `json:"created_at"`
-	UpdatedAt       time.Time `json:"updated_at"`
+	Id                 uint              `json:"id"`
+	Title              string            `json:"title"`
+	Slug               string            `json:"slug"`
+	Description        string            `json:"description"`
+	DescriptionHTML    string            `json:"description_html"`
+	ShortDesc          string            `json:"short_description"`
+	ThumbnailURL       string            `json:"thumbnail_url"`
+	ThumbnailVariants  map[string]string `json:"thumbnail_variants,omitempty"`
+	VideoPreviewURL    string            `json:"video_preview_url"`
+	Price              float64           `json:"price"`
+	DiscountPrice      *float64          `json:"discount_price"`
+	InstructorId       uint              `json:"instructor_id"`
+	CategoryId         uint              `json:"category_id"`
+	CategoryName       string            `json:"category_name"`
+	Level              string            `json:"level"`
+	DurationHours      int               `json:"duration_hours"`
+	AccessDurationDays int               `json:"access_duration_days"`
+	TotalLessons       int               `json:"total_lessons"`
+	Language           string            `json:"language"`
+	Requirements       string            `json:"requirements"`
+	WhatYouLearn       string            `json:"what_you_learn"`
+	Status             string            `json:"status"`
+	IsFeatured         bool              `json:"is_featured"`
+	RatingAvg          float32           `json:"rating_avg"`
+	RatingCount        int               `json:"rating_count"`
+	EnrolledCount      int               `json:"enrolled_count"`
+	PublishAt          *time.Time        `json:"publish_at"`
+	UnpublishAt        *time.Time        `json:"unpublish_at"`
+	CreatedAt          time.Time         `json:"created_at"`
+	UpdatedAt          time.Time         `json:"updated_at"`
+}
+
+type UploadCourseThumbnailResponse struct {
+	Message           string            `json:"message"`
+	CourseId          uint              `json:"course_id"`
+	ThumbnailURL      string            `json:"thumbnail_url"`
+	ThumbnailVariants map[string]string `json:"thumbnail_variants"`
+}
+
+type UploadContentImageResponse struct {
+	URL      string `json:"url"`
+	Markdown string `json:"markdown"` // Snippet để chèn vào nội dung lesson/description
 }
 
 type DeleteCourseResponse struct {
@@ -132,10 +162,29 @@ type DeleteCourseResponse struct {
 	CourseId uint   `json:"course_id"`
 }
 
+// POST /api/v1/instructor/courses/:course_id/clone
+type CloneCourseRequest struct {
+	Title string `json:"title" binding:"omitempty,min=5,max=200"` // Mặc định: "<title gốc> (Copy)"
+}
+
+type CloneCourseResponse struct {
+	Message          string `json:"message"`
+	SourceCourseId   uint   `json:"source_course_id"`
+	CourseId         uint   `json:"course_id"`
+	Title            string `json:"title"`
+	Slug             string `json:"slug"`
+	Status           string `json:"status"`
+	TotalLessons     int    `json:"total_lessons"`
+	TotalQuizzes     int    `json:"total_quizzes"`
+	TotalQuestions   int    `json:"total_questions"`
+	TotalAssignments int    `json:"total_assignments"`
+	TotalResources   int    `json:"total_resources"`
+}
+
 type GetCourseStudentsQueryRequest struct {
 	Page    int    `form:"page" binding:"omitempty,min=1"`
 	Limit   int    `form:"limit" binding:"omitempty,min=1,max=100"`
-	Status  string `form:"status" binding:"omitempty,oneof=active completed dropped"`
+	Status  string `form:"status" binding:"omitempty,oneof=active completed dropped expired revoked"`
 	Search  string `form:"search" binding:"omitempty,search"`
 	OrderBy string `form:"order_by" binding:"omitempty,oneof=enrolled_at completed_at progress_percentage last_accessed_at"`
 	SortBy  string `form:"sort_by" binding:"omitempty,oneof=asc desc"`
@@ -152,6 +201,8 @@ type CourseStudentItem struct {
 	ProgressPercentage float64    `json:"progress_percentage"`
 	LastAccessedAt     *time.Time `json:"last_accessed_at"`
 	Status             string     `json:"status"`
+	ExpiresAt          *time.Time `json:"expires_at"`
+	EnrollmentSource   string     `json:"enrollment_source"` // purchase, free, admin_grant, invite, org_seat
 }
 
 type GetCourseStudentsResponse struct {
@@ -171,55 +222,84 @@ type StudentStatistics struct {
 }
 
 type CreateLessonRequest struct {
-	Title         string `json:"title" binding:"required,min=3,max=200"`
-	Description   string `json:"description" binding:"required,min=10"`
-	Content       string `json:"content" binding:"omitempty"`
-	VideoURL      string `json:"video_url" binding:"omitempty,url"`
-	VideoDuration int    `json:"video_duration" binding:"omitempty,min=0"`
-	LessonOrder   int    `json:"lesson_order" binding:"required,min=1"`
-	IsPreview     bool   `json:"is_preview" binding:"omitempty"`
-	IsPublished   bool   `json:"is_published" binding:"omitempty"`
+	Title         string     `json:"title" binding:"required,min=3,max=200"`
+	Description   string     `json:"description" binding:"required,min=10"`
+	Content       string     `json:"content" binding:"omitempty,max=100000"`
+	VideoURL      string     `json:"video_url" binding:"omitempty,url"`
+	VideoDuration int        `json:"video_duration" binding:"omitempty,min=0"`
+	LessonType    string     `json:"lesson_type" binding:"omitempty,oneof=video quiz assignment scorm"`
+	LessonOrder   int        `json:"lesson_order" binding:"required,min=1"`
+	IsPreview     bool       `json:"is_preview" binding:"omitempty"`
+	IsPublished   bool       `json:"is_published" binding:"omitempty"`
+	PublishAt     *time.Time `json:"publish_at" binding:"omitempty"`
+	UnpublishAt   *time.Time `json:"unpublish_at" binding:"omitempty"`
+
+	UnlockAfterDays           int  `json:"unlock_after_days" binding:"omitempty,min=0,max=3650"`
+	RequirePreviousCompletion bool `json:"require_previous_completion"`
 }
 
 type CreateLessonResponse struct {
-	Id            uint   `json:"id"`
-	CourseId      uint   `json:"course_id"`
-	Title         string `json:"title"`
-	Slug          string `json:"slug"`
-	Description   string `json:"description"`
-	Content       string `json:"content"`
-	VideoURL      string `json:"video_url"`
-	VideoDuration int    `json:"video_duration"`
-	LessonOrder   int    `json:"lesson_order"`
-	IsPreview     bool   `json:"is_preview"`
-	IsPublished   bool   `json:"is_published"`
-	CreatedAt     string `json:"created_at"`
+	Id            uint       `json:"id"`
+	CourseId      uint       `json:"course_id"`
+	Title         string     `json:"title"`
+	Slug          string     `json:"slug"`
+	Description   string     `json:"description"`
+	Content       string     `json:"content"`
+	ContentHTML   string     `json:"content_html"`
+	VideoURL      string     `json:"video_url"`
+	VideoDuration int        `json:"video_duration"`
+	LessonType    string     `json:"lesson_type"`
+	LessonOrder   int        `json:"lesson_order"`
+	IsPreview     bool       `json:"is_preview"`
+	IsPublished   bool       `json:"is_published"`
+	PublishAt     *time.Time `json:"publish_at"`
+	UnpublishAt   *time.Time `json:"unpublish_at"`
+
+	UnlockAfterDays           int  `json:"unlock_after_days"`
+	RequirePreviousCompletion bool `json:"require_previous_completion"`
+
+	CreatedAt string `json:"created_at"`
 }
 
 type UpdateLessonRequest struct {
-	Title         *string `json:"title" binding:"omitempty,min=3,max=200"`
-	Description   *string `json:"description" binding:"omitempty,min=10"`
-	Content       *string `json:"content" binding:"omitempty"`
-	VideoURL      *string `json:"video_url" binding:"omitempty,url"`
-	VideoDuration *int    `json:"video_duration" binding:"omitempty,min=0"`
-	LessonOrder   *int    `json:"lesson_order" binding:"omitempty,min=1"`
-	IsPreview     *bool   `json:"is_preview" binding:"omitempty"`
-	IsPublished   *bool   `json:"is_published" binding:"omitempty"`
+	Title         *string    `json:"title" binding:"omitempty,min=3,max=200"`
+	Description   *string    `json:"description" binding:"omitempty,min=10"`
+	Content       *string    `json:"content" binding:"omitempty,max=100000"`
+	VideoURL      *string    `json:"video_url" binding:"omitempty,url"`
+	VideoDuration *int       `json:"video_duration" binding:"omitempty,min=0"`
+	LessonType    *string    `json:"lesson_type" binding:"omitempty,oneof=video quiz assignment scorm"`
+	LessonOrder   *int       `json:"lesson_order" binding:"omitempty,min=1"`
+	IsPreview     *bool      `json:"is_preview" binding:"omitempty"`
+	IsPublished   *bool      `json:"is_published" binding:"omitempty"`
+	PublishAt     *time.Time `json:"publish_at" binding:"omitempty"`
+	UnpublishAt   *time.Time `json:"unpublish_at" binding:"omitempty"`
+	ClearSchedule bool       `json:"clear_schedule"` // Xóa publish_at/unpublish_at đã đặt
+
+	UnlockAfterDays           *int  `json:"unlock_after_days" binding:"omitempty,min=0,max=3650"`
+	RequirePreviousCompletion *bool `json:"require_previous_completion"`
 }
 
 type UpdateLessonResponse struct {
-	Id            uint   `json:"id"`
-	CourseId      uint   `json:"course_id"`
-	Title         string `json:"title"`
-	Slug          string `json:"slug"`
-	Description   string `json:"description"`
-	Content       string `json:"content"`
-	VideoURL      string `json:"video_url"`
-	VideoDuration int    `json:"video_duration"`
-	LessonOrder   int    `json:"lesson_order"`
-	IsPreview     bool   `json:"is_preview"`
-	IsPublished   bool   `json:"is_published"`
-	UpdatedAt     string `json:"updated_at"`
+	Id            uint       `json:"id"`
+	CourseId      uint       `json:"course_id"`
+	Title         string     `json:"title"`
+	Slug          string     `json:"slug"`
+	Description   string     `json:"description"`
+	Content       string     `json:"content"`
+	ContentHTML   string     `json:"content_html"`
+	VideoURL      string     `json:"video_url"`
+	VideoDuration int        `json:"video_duration"`
+	LessonType    string     `json:"lesson_type"`
+	LessonOrder   int        `json:"lesson_order"`
+	IsPreview     bool       `json:"is_preview"`
+	IsPublished   bool       `json:"is_published"`
+	PublishAt     *time.Time `json:"publish_at"`
+	UnpublishAt   *time.Time `json:"unpublish_at"`
+
+	UnlockAfterDays           int  `json:"unlock_after_days"`
+	RequirePreviousCompletion bool `json:"require_previous_completion"`
+
+	UpdatedAt string `json:"updated_at"`
 }
 
 type DeleteLessonResponse struct {
@@ -227,6 +307,20 @@ type DeleteLessonResponse struct {
 	Id      uint   `json:"id"`
 }
 
+type DuplicateLessonResponse struct {
+	Message          string `json:"message"`
+	SourceLessonId   uint   `json:"source_lesson_id"`
+	Id               uint   `json:"id"`
+	CourseId         uint   `json:"course_id"`
+	Title            string `json:"title"`
+	Slug             string `json:"slug"`
+	LessonOrder      int    `json:"lesson_order"`
+	IsPublished      bool   `json:"is_published"`
+	TotalQuestions   int    `json:"total_questions"`
+	TotalResources   int    `json:"total_resources"`
+	AssignmentCopied bool   `json:"assignment_copied"`
+}
+
 type ReorderLessonsRequest struct {
 	Lessons []LessonOrderItem `json:"lessons" binding:"required,min=1,dive"`
 }
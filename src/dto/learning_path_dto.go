@@ -0,0 +1,125 @@
+package dto
+
+import "time"
+
+// GET /api/v1/learning-paths - Query parameters
+type GetLearningPathsQueryRequest struct {
+	Page   int    `form:"page" binding:"omitempty,min=1"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Search string `form:"search" binding:"omitempty,max=100"`
+}
+
+// GET /api/v1/admin/learning-paths - Query parameters
+type GetAdminLearningPathsQueryRequest struct {
+	Page        int    `form:"page" binding:"omitempty,min=1"`
+	Limit       int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Search      string `form:"search" binding:"omitempty,max=100"`
+	IsPublished *bool  `form:"is_published" binding:"omitempty"`
+}
+
+type LearningPathItem struct {
+	Id           uint      `json:"id"`
+	Title        string    `json:"title"`
+	Slug         string    `json:"slug"`
+	ShortDesc    string    `json:"short_description"`
+	ThumbnailURL string    `json:"thumbnail_url"`
+	IsPublished  bool      `json:"is_published"`
+	TotalCourses int       `json:"total_courses"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+type GetLearningPathsResponse struct {
+	LearningPaths []LearningPathItem `json:"learning_paths"`
+	Pagination    PaginationInfo     `json:"pagination"`
+}
+
+type LearningPathCourseItem struct {
+	CourseId       uint     `json:"course_id"`
+	CourseOrder    int      `json:"course_order"`
+	Title          string   `json:"title"`
+	Slug           string   `json:"slug"`
+	ShortDesc      string   `json:"short_description"`
+	ThumbnailURL   string   `json:"thumbnail_url"`
+	Level          string   `json:"level"`
+	Price          float64  `json:"price"`
+	DiscountPrice  *float64 `json:"discount_price"`
+	DurationHours  int      `json:"duration_hours"`
+	InstructorName string   `json:"instructor_name"`
+	Status         string   `json:"status"`
+}
+
+type LearningPathDetail struct {
+	LearningPathItem
+	Description        string                   `json:"description"`
+	TotalDurationHours int                      `json:"total_duration_hours"`
+	Courses            []LearningPathCourseItem `json:"courses"`
+}
+
+// POST /api/v1/admin/learning-paths - Request body
+type CreateLearningPathRequest struct {
+	Title        string `json:"title" binding:"required,min=3,max=200"`
+	Description  string `json:"description" binding:"omitempty"`
+	ShortDesc    string `json:"short_description" binding:"omitempty,max=500"`
+	ThumbnailURL string `json:"thumbnail_url" binding:"omitempty,url,max=255"`
+	IsPublished  bool   `json:"is_published"`
+	CourseIds    []uint `json:"course_ids" binding:"omitempty,max=50,dive,min=1"`
+}
+
+// PUT /api/v1/admin/learning-paths/:id - Request body
+type UpdateLearningPathRequest struct {
+	Title        *string `json:"title" binding:"omitempty,min=3,max=200"`
+	Description  *string `json:"description" binding:"omitempty"`
+	ShortDesc    *string `json:"short_description" binding:"omitempty,max=500"`
+	ThumbnailURL *string `json:"thumbnail_url" binding:"omitempty,url,max=255"`
+	IsPublished  *bool   `json:"is_published" binding:"omitempty"`
+}
+
+// PUT /api/v1/admin/learning-paths/:id/courses - Request body, course_ids theo đúng thứ tự học
+type UpdateLearningPathCoursesRequest struct {
+	CourseIds []uint `json:"course_ids" binding:"required,min=1,max=50,dive,min=1"`
+}
+
+type DeleteLearningPathResponse struct {
+	Message        string `json:"message"`
+	LearningPathId uint   `json:"learning_path_id"`
+}
+
+// Tiến độ của user trên từng course trong learning path
+type LearningPathCourseProgress struct {
+	CourseId           uint       `json:"course_id"`
+	CourseOrder        int        `json:"course_order"`
+	Title              string     `json:"title"`
+	Slug               string     `json:"slug"`
+	ThumbnailURL       string     `json:"thumbnail_url"`
+	IsEnrolled         bool       `json:"is_enrolled"`
+	EnrollmentStatus   string     `json:"enrollment_status,omitempty"`
+	ProgressPercentage float64    `json:"progress_percentage"`
+	CompletedAt        *time.Time `json:"completed_at"`
+}
+
+// GET /api/v1/learning-paths/:slug/progress - Response
+type LearningPathProgressResponse struct {
+	LearningPathId     uint                         `json:"learning_path_id"`
+	Title              string                       `json:"title"`
+	Slug               string                       `json:"slug"`
+	IsFollowing        bool                         `json:"is_following"`
+	StartedAt          *time.Time                   `json:"started_at"`
+	CompletedAt        *time.Time                   `json:"completed_at"`
+	TotalCourses       int                          `json:"total_courses"`
+	CompletedCourses   int                          `json:"completed_courses"`
+	ProgressPercentage float64                      `json:"progress_percentage"`
+	NextCourse         *LearningPathCourseProgress  `json:"next_course,omitempty"`
+	Courses            []LearningPathCourseProgress `json:"courses"`
+}
+
+// GET /api/v1/users/learning-paths - Response
+type GetMyLearningPathsResponse struct {
+	LearningPaths []LearningPathProgressResponse `json:"learning_paths"`
+	Total         int                            `json:"total"`
+}
+
+type UnfollowLearningPathResponse struct {
+	Message        string `json:"message"`
+	LearningPathId uint   `json:"learning_path_id"`
+}
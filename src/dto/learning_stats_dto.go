@@ -0,0 +1,59 @@
+package dto
+
+import "time"
+
+type GetLearningStatsQueryRequest struct {
+	Days int `form:"days" binding:"omitempty,min=1,max=365"` // Số ngày trong daily_activity, mặc định 30
+}
+
+type DailyLearningActivity struct {
+	Date             string `json:"date"` // YYYY-MM-DD
+	Minutes          int    `json:"minutes"`
+	LessonsCompleted int    `json:"lessons_completed"`
+}
+
+type WeeklyGoalProgress struct {
+	GoalType   string  `json:"goal_type"` // minutes, lessons
+	Target     int     `json:"target"`
+	Progress   int     `json:"progress"`
+	Percentage float64 `json:"percentage"`
+	Achieved   bool    `json:"achieved"`
+	WeekStart  string  `json:"week_start"`
+	WeekEnd    string  `json:"week_end"`
+}
+
+type LearningStatsResponse struct {
+	Timezone              string                  `json:"timezone"`
+	CurrentStreak         int                     `json:"current_streak"`
+	LongestStreak         int                     `json:"longest_streak"`
+	LastActiveDate        *string                 `json:"last_active_date"`
+	ActiveDays            int                     `json:"active_days"`
+	TotalMinutes          int                     `json:"total_minutes"`
+	TotalLessonsCompleted int                     `json:"total_lessons_completed"`
+	WeeklyGoal            *WeeklyGoalProgress     `json:"weekly_goal"`
+	DailyActivity         []DailyLearningActivity `json:"daily_activity"`
+}
+
+type UpdateLearningGoalRequest struct {
+	GoalType string `json:"goal_type" binding:"required,oneof=minutes lessons"`
+	Target   int    `json:"target" binding:"required,min=1,max=10080"`
+}
+
+type LearningGoalResponse struct {
+	GoalType  string    `json:"goal_type"`
+	Target    int       `json:"target"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type DeleteLearningGoalResponse struct {
+	Message string `json:"message"`
+}
+
+type NotificationPreferencesResponse struct {
+	LearningReminders bool `json:"learning_reminders"`
+	ReminderAfterDays int  `json:"reminder_after_days"` // Số ngày không học trước khi gửi email nhắc
+}
+
+type UpdateNotificationPreferencesRequest struct {
+	LearningReminders *bool `json:"learning_reminders" binding:"required"`
+}
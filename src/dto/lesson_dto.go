@@ -3,17 +3,21 @@ package dto
 import "time"
 
 type LessonItem struct {
-	Id            uint      `json:"id"`
-	CourseId      uint      `json:"course_id"`
-	Title         string    `json:"title"`
-	Slug          string    `json:"slug"`
-	Description   string    `json:"description"`
-	VideoURL      string    `json:"video_url"`
-	VideoDuration int       `json:"video_duration"`
-	LessonOrder   int       `json:"lesson_order"`
-	IsPreview     bool      `json:"is_preview"`
-	IsCompleted   bool      `json:"is_completed"` // Trạng thái hoàn thành của student
-	CreatedAt     time.Time `json:"created_at"`
+	Id            uint       `json:"id"`
+	CourseId      uint       `json:"course_id"`
+	Title         string     `json:"title"`
+	Slug          string     `json:"slug"`
+	Description   string     `json:"description"`
+	VideoURL      string     `json:"video_url"`
+	VideoDuration int        `json:"video_duration"`
+	LessonType    string     `json:"lesson_type"`
+	LessonOrder   int        `json:"lesson_order"`
+	IsPreview     bool       `json:"is_preview"`
+	IsCompleted   bool       `json:"is_completed"` // Trạng thái hoàn thành của student
+	IsLocked      bool       `json:"is_locked"`
+	UnlockAt      *time.Time `json:"unlock_at,omitempty"`
+	LockReason    string     `json:"lock_reason,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
 }
 
 type GetCourseLessonsResponse struct {
@@ -32,8 +36,10 @@ type LessonDetail struct {
 	Slug          string    `json:"slug"`
 	Description   string    `json:"description"`
 	Content       string    `json:"content"`
+	ContentHTML   string    `json:"content_html"`
 	VideoURL      string    `json:"video_url"`
 	VideoDuration int       `json:"video_duration"`
+	LessonType    string    `json:"lesson_type"`
 	LessonOrder   int       `json:"lesson_order"`
 	IsPreview     bool      `json:"is_preview"`
 	IsCompleted   bool      `json:"is_completed"`
@@ -42,6 +48,18 @@ type LessonDetail struct {
 	CreatedAt     time.Time `json:"created_at"`
 	UpdatedAt     time.Time `json:"updated_at"`
 
+	// Ghi chú riêng và bookmark của học viên
+	NoteCount    int  `json:"note_count"`
+	IsBookmarked bool `json:"is_bookmarked"`
+
+	// Phụ đề cho player, để trống khi lesson bị khóa
+	Subtitles []SubtitleTrack `json:"subtitles"`
+
+	// Drip content - khi bị khóa, content và video_url để trống
+	IsLocked   bool       `json:"is_locked"`
+	UnlockAt   *time.Time `json:"unlock_at,omitempty"`
+	LockReason string     `json:"lock_reason,omitempty"`
+
 	// Navigation
 	PreviousLesson *LessonNavigation `json:"previous_lesson,omitempty"`
 	NextLesson     *LessonNavigation `json:"next_lesson,omitempty"`
@@ -0,0 +1,74 @@
+package dto
+
+import "time"
+
+type CreateLessonNoteRequest struct {
+	Content        string `json:"content" binding:"required,min=1,max=10000"`
+	VideoTimestamp *int   `json:"video_timestamp" binding:"omitempty,min=0"` // Giây trong video
+}
+
+type UpdateLessonNoteRequest struct {
+	Content        string `json:"content" binding:"omitempty,min=1,max=10000"`
+	VideoTimestamp *int   `json:"video_timestamp" binding:"omitempty,min=0"`
+	ClearTimestamp bool   `json:"clear_timestamp"` // Bỏ gắn timestamp
+}
+
+type LessonNoteItem struct {
+	Id             uint      `json:"id"`
+	CourseId       uint      `json:"course_id"`
+	CourseTitle    string    `json:"course_title,omitempty"`
+	LessonId       uint      `json:"lesson_id"`
+	LessonTitle    string    `json:"lesson_title,omitempty"`
+	LessonSlug     string    `json:"lesson_slug,omitempty"`
+	Content        string    `json:"content"`
+	VideoTimestamp *int      `json:"video_timestamp"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type GetLessonNotesResponse struct {
+	LessonId     uint             `json:"lesson_id"`
+	IsBookmarked bool             `json:"is_bookmarked"`
+	Notes        []LessonNoteItem `json:"notes"`
+}
+
+type DeleteLessonNoteResponse struct {
+	Message string `json:"message"`
+}
+
+type SearchNotesQueryRequest struct {
+	Page     int    `form:"page" binding:"omitempty,min=1"`
+	Limit    int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Search   string `form:"search" binding:"omitempty,max=200"`
+	CourseId uint   `form:"course_id" binding:"omitempty"`
+	LessonId uint   `form:"lesson_id" binding:"omitempty"`
+}
+
+type SearchNotesResponse struct {
+	Notes      []LessonNoteItem `json:"notes"`
+	Pagination PaginationInfo   `json:"pagination"`
+}
+
+type LessonBookmarkResponse struct {
+	LessonId     uint   `json:"lesson_id"`
+	IsBookmarked bool   `json:"is_bookmarked"`
+	Message      string `json:"message"`
+}
+
+type GetBookmarksQueryRequest struct {
+	CourseId uint `form:"course_id" binding:"omitempty"`
+}
+
+type LessonBookmarkItem struct {
+	LessonId    uint      `json:"lesson_id"`
+	LessonTitle string    `json:"lesson_title"`
+	LessonSlug  string    `json:"lesson_slug"`
+	LessonOrder int       `json:"lesson_order"`
+	CourseId    uint      `json:"course_id"`
+	CourseTitle string    `json:"course_title"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type GetBookmarksResponse struct {
+	Bookmarks []LessonBookmarkItem `json:"bookmarks"`
+}
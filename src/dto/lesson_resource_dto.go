@@ -0,0 +1,39 @@
+package dto
+
+import "time"
+
+// Upload dạng multipart: field "file" + các field dưới đây
+type UploadLessonResourceRequest struct {
+	Title       string `form:"title" binding:"omitempty,max=200"` // Mặc định là tên file
+	Description string `form:"description" binding:"omitempty,max=1000"`
+}
+
+type UpdateLessonResourceRequest struct {
+	Title         *string `json:"title" binding:"omitempty,min=1,max=200"`
+	Description   *string `json:"description" binding:"omitempty,max=1000"`
+	ResourceOrder *int    `json:"resource_order" binding:"omitempty,min=0"`
+}
+
+type LessonResourceItem struct {
+	Id            uint      `json:"id"`
+	LessonId      uint      `json:"lesson_id"`
+	Title         string    `json:"title"`
+	Description   string    `json:"description"`
+	FileName      string    `json:"file_name"`
+	Size          int64     `json:"size"`
+	MimeType      string    `json:"mime_type"`
+	ResourceOrder int       `json:"resource_order"`
+	DownloadCount int       `json:"download_count,omitempty"` // Chỉ hiển thị cho instructor
+	DownloadURL   string    `json:"download_url"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type GetLessonResourcesResponse struct {
+	LessonId  uint                 `json:"lesson_id"`
+	Resources []LessonResourceItem `json:"resources"`
+	Total     int                  `json:"total"`
+}
+
+type DeleteLessonResourceResponse struct {
+	Message string `json:"message"`
+}
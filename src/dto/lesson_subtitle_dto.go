@@ -0,0 +1,76 @@
+package dto
+
+import "time"
+
+// Upload dạng multipart: field "file" (.srt hoặc .vtt) + các field dưới đây.
+// Upload lại cùng ngôn ngữ sẽ thay thế track cũ.
+type UploadSubtitleRequest struct {
+	Language  string `form:"language" binding:"required,language_code"`
+	Label     string `form:"label" binding:"omitempty,max=100"` // Mặc định theo ngôn ngữ (Tiếng Việt, English)
+	IsDefault bool   `form:"is_default"`
+}
+
+type UpdateSubtitleRequest struct {
+	Label     *string `json:"label" binding:"omitempty,min=1,max=100"`
+	IsDefault *bool   `json:"is_default"`
+}
+
+type SubtitleItem struct {
+	Id           uint      `json:"id"`
+	LessonId     uint      `json:"lesson_id"`
+	Language     string    `json:"language"`
+	Label        string    `json:"label"`
+	SourceFormat string    `json:"source_format"`
+	CueCount     int       `json:"cue_count"`
+	IsDefault    bool      `json:"is_default"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+type GetSubtitlesResponse struct {
+	LessonId  uint           `json:"lesson_id"`
+	Subtitles []SubtitleItem `json:"subtitles"`
+}
+
+type DeleteSubtitleResponse struct {
+	Message string `json:"message"`
+}
+
+// SubtitleTrack dùng cho thẻ <track> của player, URL file WebVTT có ký như video
+type SubtitleTrack struct {
+	Id        uint       `json:"id"`
+	Language  string     `json:"language"`
+	Label     string     `json:"label"`
+	IsDefault bool       `json:"is_default"`
+	URL       string     `json:"url"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"` // nil với preview lesson
+}
+
+type GetSubtitleTracksResponse struct {
+	LessonId uint            `json:"lesson_id"`
+	Tracks   []SubtitleTrack `json:"tracks"`
+}
+
+type TranscriptQueryRequest struct {
+	Language string `form:"language" binding:"omitempty,language_code"` // Mặc định: track default
+	Query    string `form:"q" binding:"omitempty,max=200"`
+	Page     int    `form:"page" binding:"omitempty,min=1"`
+	Limit    int    `form:"limit" binding:"omitempty,min=1,max=500"`
+}
+
+type TranscriptCue struct {
+	Index          int     `json:"index"`
+	Start          float64 `json:"start"` // Giây, dùng để seek player
+	End            float64 `json:"end"`
+	StartTimestamp string  `json:"start_timestamp"` // hh:mm:ss.mmm
+	Text           string  `json:"text"`
+}
+
+type TranscriptResponse struct {
+	LessonId   uint            `json:"lesson_id"`
+	SubtitleId uint            `json:"subtitle_id"`
+	Language   string          `json:"language"`
+	Query      string          `json:"query,omitempty"`
+	Cues       []TranscriptCue `json:"cues"`
+	Pagination PaginationInfo  `json:"pagination"`
+}
@@ -0,0 +1,17 @@
+package dto
+
+import "time"
+
+type LessonMediaResponse struct {
+	LessonId   uint       `json:"lesson_id"`
+	URL        string     `json:"url"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"` // nil với preview lesson hoặc video bên ngoài
+	IsExternal bool       `json:"is_external"`          // Video host bên ngoài (YouTube, Vimeo...) được trả nguyên bản
+}
+
+// Query của signed URL: /api/v1/media/lessons/:lesson_id/stream?uid=&exp=&sig=
+type MediaStreamQuery struct {
+	UserId    uint   `form:"uid"`
+	ExpiresAt int64  `form:"exp"`
+	Signature string `form:"sig"`
+}
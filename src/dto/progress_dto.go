@@ -20,21 +20,33 @@ type GetCourseProgressResponse struct {
 
 // LessonProgressItem - Progress của từng lesson
 type LessonProgressItem struct {
-	LessonId        uint       `json:"lesson_id"`
-	Title           string     `json:"title"`
-	Slug            string     `json:"slug"`
-	LessonOrder     int        `json:"lesson_order"`
-	VideoDuration   int        `json:"video_duration"` // Tổng thời lượng video (giây)
-	IsCompleted     bool       `json:"is_completed"`
-	CompletedAt     *time.Time `json:"completed_at,omitempty"`
-	WatchDuration   int        `json:"watch_duration"`   // Đã xem (giây)
-	LastPosition    int        `json:"last_position"`    // Vị trí cuối cùng (giây)
-	ProgressPercent float64    `json:"progress_percent"` // % hoàn thành lesson này
-}
-
-// Request để đánh dấu lesson hoàn thành
+	LessonId        uint         `json:"lesson_id"`
+	Title           string       `json:"title"`
+	Slug            string       `json:"slug"`
+	LessonOrder     int          `json:"lesson_order"`
+	VideoDuration   int          `json:"video_duration"` // Tổng thời lượng video (giây)
+	IsCompleted     bool         `json:"is_completed"`
+	CompletedAt     *time.Time   `json:"completed_at,omitempty"`
+	WatchDuration   int          `json:"watch_duration"`   // Đã xem (giây)
+	LastPosition    int          `json:"last_position"`    // Vị trí cuối cùng (giây)
+	ProgressPercent float64      `json:"progress_percent"` // % hoàn thành lesson này
+	LessonType      string       `json:"lesson_type"`
+	Grade           *LessonGrade `json:"grade,omitempty"` // Điểm của quiz/assignment
+}
+
+// LessonGrade - Điểm của lesson dạng quiz (lần cao nhất) hoặc assignment (bài nộp mới nhất)
+type LessonGrade struct {
+	Status     string   `json:"status"` // pending, graded
+	Score      *float64 `json:"score"`
+	MaxScore   float64  `json:"max_score"`
+	Percentage *float64 `json:"percentage"`
+	Passed     bool     `json:"passed"`
+}
+
+// Request để đánh dấu lesson hoàn thành.
+// Với lesson video, WatchDuration bị bỏ qua: thời lượng xem được tính từ heartbeat
 type CompleteLessonRequest struct {
-	WatchDuration int `json:"watch_duration" binding:"required,min=0"`
+	WatchDuration int `json:"watch_duration" binding:"omitempty,min=0"`
 }
 
 type CompleteLessonResponse struct {
@@ -46,10 +58,10 @@ type CompleteLessonResponse struct {
 	Message       string    `json:"message"`
 }
 
-// Request để cập nhật vị trí video
+// Request để cập nhật vị trí video (resume), WatchDuration giữ lại cho client cũ nhưng không còn được ghi nhận
 type UpdateLessonPositionRequest struct {
 	LastPosition  int `json:"last_position" binding:"required,min=0"`
-	WatchDuration int `json:"watch_duration" binding:"required,min=0"`
+	WatchDuration int `json:"watch_duration" binding:"omitempty,min=0"`
 }
 
 type UpdateLessonPositionResponse struct {
@@ -58,3 +70,65 @@ type UpdateLessonPositionResponse struct {
 	WatchDuration int    `json:"watch_duration"`
 	Message       string `json:"message"`
 }
+
+// WatchHeartbeatRequest - đoạn video [from, to] (giây) vừa phát kể từ heartbeat trước
+type WatchHeartbeatRequest struct {
+	From int `json:"from" binding:"min=0"`
+	To   int `json:"to" binding:"min=0"`
+}
+
+type WatchHeartbeatResponse struct {
+	LessonId        uint    `json:"lesson_id"`
+	LastPosition    int     `json:"last_position"`
+	VideoDuration   int     `json:"video_duration"`
+	WatchedSeconds  int     `json:"watched_seconds"` // Tổng số giây đã xem (không tính đoạn xem lại)
+	WatchedPercent  float64 `json:"watched_percent"`
+	RecordedSeconds int     `json:"recorded_seconds"` // Số giây của heartbeat này được ghi nhận
+	RequiredPercent int     `json:"required_percent"` // % phải xem trước khi hoàn thành lesson
+	CanComplete     bool    `json:"can_complete"`
+}
+
+// SyncProgressRequest - batch sự kiện học offline, mỗi sự kiện có idempotency key do client sinh để gửi lại an toàn
+type SyncProgressRequest struct {
+	Items []SyncProgressItem `json:"items" binding:"required,min=1,max=200,dive"`
+}
+
+// SyncProgressItem - type position: vị trí video (kèm đoạn đã xem watched_from..watched_to nếu có); type complete: hoàn thành lesson
+type SyncProgressItem struct {
+	IdempotencyKey string    `json:"idempotency_key" binding:"required,max=100"`
+	LessonId       uint      `json:"lesson_id" binding:"required"`
+	Type           string    `json:"type" binding:"required,oneof=position complete"`
+	Position       int       `json:"position" binding:"min=0"`
+	WatchedFrom    *int      `json:"watched_from" binding:"omitempty,min=0"`
+	WatchedTo      *int      `json:"watched_to" binding:"omitempty,min=0"`
+	OccurredAt     time.Time `json:"occurred_at" binding:"required"` // Thời điểm trên thiết bị
+}
+
+type SyncProgressResponse struct {
+	Results     []SyncProgressItemResult   `json:"results"`
+	Lessons     []SyncedLessonProgress     `json:"lessons"`     // Trạng thái server sau khi gộp, cho mọi lesson trong batch
+	Enrollments []SyncedEnrollmentProgress `json:"enrollments"` // Progress khóa học đã tính lại
+}
+
+type SyncProgressItemResult struct {
+	IdempotencyKey string `json:"idempotency_key"`
+	LessonId       uint   `json:"lesson_id"`
+	Status         string `json:"status"` // applied, duplicate, rejected
+	Error          string `json:"error,omitempty"`
+}
+
+type SyncedLessonProgress struct {
+	LessonId      uint       `json:"lesson_id"`
+	CourseId      uint       `json:"course_id"`
+	IsCompleted   bool       `json:"is_completed"`
+	CompletedAt   *time.Time `json:"completed_at"`
+	LastPosition  int        `json:"last_position"`
+	WatchDuration int        `json:"watch_duration"`
+}
+
+type SyncedEnrollmentProgress struct {
+	CourseId           uint       `json:"course_id"`
+	ProgressPercentage float64    `json:"progress_percentage"`
+	Status             string     `json:"status"`
+	CompletedAt        *time.Time `json:"completed_at"`
+}
@@ -0,0 +1,185 @@
+package dto
+
+import "time"
+
+// ---------------- Instructor: quản lý quiz ----------------
+
+// PUT /api/v1/instructor/courses/:course_id/lessons/:id/quiz - Request body
+type UpsertQuizRequest struct {
+	TimeLimitSeconds    *int     `json:"time_limit_seconds" binding:"omitempty,min=0,max=86400"`
+	MaxAttempts         *int     `json:"max_attempts" binding:"omitempty,min=0,max=100"`
+	PassPercentage      *float64 `json:"pass_percentage" binding:"omitempty,gt=0,max=100"`
+	QuestionsPerAttempt *int     `json:"questions_per_attempt" binding:"omitempty,min=0,max=500"`
+	ShuffleQuestions    *bool    `json:"shuffle_questions"`
+	ShuffleOptions      *bool    `json:"shuffle_options"`
+	ShowCorrectAnswers  *bool    `json:"show_correct_answers"`
+}
+
+type QuizOptionInput struct {
+	Key       string `json:"key" binding:"omitempty,max=36"` // Key của option hiện có (khi update), bỏ trống với option mới
+	Text      string `json:"text" binding:"required,max=1000"`
+	IsCorrect bool   `json:"is_correct"`
+}
+
+// POST/PUT /api/v1/instructor/courses/:course_id/lessons/:id/quiz/questions - Request body
+// PUT thay toàn bộ nội dung question
+type SaveQuizQuestionRequest struct {
+	QuestionType    string            `json:"question_type" binding:"required,oneof=single_choice multiple_choice true_false short_answer"`
+	Text            string            `json:"text" binding:"required,max=5000"`
+	Explanation     string            `json:"explanation" binding:"omitempty,max=5000"`
+	Points          float64           `json:"points" binding:"omitempty,gt=0,max=100"`
+	QuestionOrder   int               `json:"question_order" binding:"omitempty,min=1"`
+	Options         []QuizOptionInput `json:"options" binding:"omitempty,max=10,dive"`
+	CorrectAnswer   *bool             `json:"correct_answer"` // Cho true_false
+	AcceptedAnswers []string          `json:"accepted_answers" binding:"omitempty,max=20,dive,required,max=255"`
+	CaseSensitive   bool              `json:"case_sensitive"`
+}
+
+type QuizOptionItem struct {
+	Key       string `json:"key"`
+	Text      string `json:"text"`
+	IsCorrect bool   `json:"is_correct"`
+}
+
+type InstructorQuizQuestion struct {
+	Id              uint             `json:"id"`
+	QuestionType    string           `json:"question_type"`
+	Text            string           `json:"text"`
+	Explanation     string           `json:"explanation"`
+	Points          float64          `json:"points"`
+	QuestionOrder   int              `json:"question_order"`
+	Options         []QuizOptionItem `json:"options"`
+	AcceptedAnswers []string         `json:"accepted_answers"`
+	CaseSensitive   bool             `json:"case_sensitive"`
+}
+
+type InstructorQuizResponse struct {
+	Id                  uint                     `json:"id"`
+	LessonId            uint                     `json:"lesson_id"`
+	CourseId            uint                     `json:"course_id"`
+	TimeLimitSeconds    int                      `json:"time_limit_seconds"`
+	MaxAttempts         int                      `json:"max_attempts"`
+	PassPercentage      float64                  `json:"pass_percentage"`
+	QuestionsPerAttempt int                      `json:"questions_per_attempt"`
+	ShuffleQuestions    bool                     `json:"shuffle_questions"`
+	ShuffleOptions      bool                     `json:"shuffle_options"`
+	ShowCorrectAnswers  bool                     `json:"show_correct_answers"`
+	TotalQuestions      int                      `json:"total_questions"`
+	TotalPoints         float64                  `json:"total_points"`
+	Questions           []InstructorQuizQuestion `json:"questions"`
+	UpdatedAt           time.Time                `json:"updated_at"`
+}
+
+type DeleteQuizQuestionResponse struct {
+	Message string `json:"message"`
+	Id      uint   `json:"id"`
+}
+
+// ---------------- Student: làm quiz ----------------
+
+// GET /api/v1/quizzes/lessons/:lesson_id - Response
+type QuizInfoResponse struct {
+	QuizId              uint     `json:"quiz_id"`
+	LessonId            uint     `json:"lesson_id"`
+	LessonTitle         string   `json:"lesson_title"`
+	CourseId            uint     `json:"course_id"`
+	TimeLimitSeconds    int      `json:"time_limit_seconds"`
+	MaxAttempts         int      `json:"max_attempts"`
+	PassPercentage      float64  `json:"pass_percentage"`
+	TotalQuestions      int      `json:"total_questions"` // Số câu mỗi lần làm
+	AttemptsUsed        int      `json:"attempts_used"`
+	AttemptsRemaining   *int     `json:"attempts_remaining"` // nil = không giới hạn
+	BestScorePercentage *float64 `json:"best_score_percentage"`
+	Passed              bool     `json:"passed"`
+	InProgressAttemptId *uint    `json:"in_progress_attempt_id"`
+}
+
+type QuizAttemptOption struct {
+	Key  string `json:"key"`
+	Text string `json:"text"`
+}
+
+type QuizAttemptQuestion struct {
+	QuestionId   uint                `json:"question_id"`
+	QuestionType string              `json:"question_type"`
+	Text         string              `json:"text"`
+	Points       float64             `json:"points"`
+	Options      []QuizAttemptOption `json:"options"`
+}
+
+// POST /api/v1/quizzes/lessons/:lesson_id/attempts - Response
+type StartQuizAttemptResponse struct {
+	AttemptId        uint                  `json:"attempt_id"`
+	QuizId           uint                  `json:"quiz_id"`
+	LessonId         uint                  `json:"lesson_id"`
+	AttemptNumber    int                   `json:"attempt_number"`
+	TimeLimitSeconds int                   `json:"time_limit_seconds"`
+	StartedAt        time.Time             `json:"started_at"`
+	ExpiresAt        *time.Time            `json:"expires_at"`
+	Questions        []QuizAttemptQuestion `json:"questions"`
+}
+
+type QuizAnswerInput struct {
+	QuestionId      uint     `json:"question_id" binding:"required,min=1"`
+	SelectedOptions []string `json:"selected_options" binding:"omitempty,max=10"`
+	TextAnswer      string   `json:"text_answer" binding:"omitempty,max=1000"`
+}
+
+// POST /api/v1/quizzes/attempts/:attempt_id/submit - Request body
+type SubmitQuizAttemptRequest struct {
+	Answers []QuizAnswerInput `json:"answers" binding:"omitempty,max=500,dive"`
+}
+
+type QuizResultQuestion struct {
+	QuestionId      uint                `json:"question_id"`
+	QuestionType    string              `json:"question_type"`
+	Text            string              `json:"text"`
+	Points          float64             `json:"points"`
+	PointsAwarded   float64             `json:"points_awarded"`
+	IsCorrect       bool                `json:"is_correct"`
+	Options         []QuizAttemptOption `json:"options"`
+	SelectedOptions []string            `json:"selected_options"`
+	TextAnswer      string              `json:"text_answer"`
+
+	// Chỉ trả về khi quiz bật show_correct_answers
+	CorrectOptions  []string `json:"correct_options,omitempty"`
+	AcceptedAnswers []string `json:"accepted_answers,omitempty"`
+	Explanation     string   `json:"explanation,omitempty"`
+}
+
+type QuizAttemptResult struct {
+	AttemptId       uint                 `json:"attempt_id"`
+	QuizId          uint                 `json:"quiz_id"`
+	LessonId        uint                 `json:"lesson_id"`
+	AttemptNumber   int                  `json:"attempt_number"`
+	Status          string               `json:"status"`
+	Score           float64              `json:"score"`
+	MaxScore        float64              `json:"max_score"`
+	ScorePercentage float64              `json:"score_percentage"`
+	PassPercentage  float64              `json:"pass_percentage"`
+	Passed          bool                 `json:"passed"`
+	LessonCompleted bool                 `json:"lesson_completed"`
+	StartedAt       time.Time            `json:"started_at"`
+	SubmittedAt     *time.Time           `json:"submitted_at"`
+	Questions       []QuizResultQuestion `json:"questions"`
+}
+
+type QuizAttemptSummary struct {
+	AttemptId       uint       `json:"attempt_id"`
+	AttemptNumber   int        `json:"attempt_number"`
+	Status          string     `json:"status"`
+	Score           float64    `json:"score"`
+	MaxScore        float64    `json:"max_score"`
+	ScorePercentage float64    `json:"score_percentage"`
+	Passed          bool       `json:"passed"`
+	StartedAt       time.Time  `json:"started_at"`
+	SubmittedAt     *time.Time `json:"submitted_at"`
+}
+
+// GET /api/v1/quizzes/lessons/:lesson_id/attempts - Response
+type GetQuizAttemptsResponse struct {
+	QuizId   uint                 `json:"quiz_id"`
+	LessonId uint                 `json:"lesson_id"`
+	Attempts []QuizAttemptSummary `json:"attempts"`
+	Total    int                  `json:"total"`
+}
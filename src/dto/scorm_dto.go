@@ -0,0 +1,51 @@
+package dto
+
+import "time"
+
+type ScormPackageResponse struct {
+	Id           uint      `json:"id"`
+	LessonId     uint      `json:"lesson_id"`
+	Version      string    `json:"version"`
+	Identifier   string    `json:"identifier"`
+	Title        string    `json:"title"`
+	LaunchPath   string    `json:"launch_path"`
+	MasteryScore *float64  `json:"mastery_score"`
+	FileCount    int       `json:"file_count"`
+	TotalSize    int64     `json:"total_size"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+type DeleteScormPackageResponse struct {
+	Message string `json:"message"`
+}
+
+// ScormLaunchResponse - PlayerURL là trang player (chứa API adapter SCORM 1.2) để nhúng vào iframe,
+// nội dung và runtime API nằm cùng origin dưới URL đã ký của phiên học
+type ScormLaunchResponse struct {
+	LessonId     uint      `json:"lesson_id"`
+	Title        string    `json:"title"`
+	Version      string    `json:"version"`
+	PlayerURL    string    `json:"player_url"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	LessonStatus string    `json:"lesson_status"`
+}
+
+// ScormPlayer là dữ liệu render trang player, LaunchURL tương đối so với URL phiên học
+type ScormPlayer struct {
+	Title     string
+	LaunchURL string
+}
+
+// ScormRuntimeResponse - Values là data model cmi.* trả cho LMSGetValue
+type ScormRuntimeResponse struct {
+	LessonId        uint              `json:"lesson_id"`
+	Values          map[string]string `json:"values"`
+	LessonCompleted bool              `json:"lesson_completed"`
+}
+
+// CommitScormRuntimeRequest gửi khi LMSCommit/LMSFinish, Values chỉ gồm các element nội dung đã LMSSetValue
+type CommitScormRuntimeRequest struct {
+	Values map[string]string `json:"values"`
+	Finish bool              `json:"finish"` // LMSFinish: cộng session_time vào total_time và kết thúc phiên
+}
@@ -35,6 +35,7 @@ type ChangePasswordResponse struct {
 }
 
 type UploadAvatarResponse struct {
-	Message   string `json:"message"`
-	AvatarURL string `json:"avatar_url"`
+	Message        string            `json:"message"`
+	AvatarURL      string            `json:"avatar_url"`
+	AvatarVariants map[string]string `json:"avatar_variants"`
 }
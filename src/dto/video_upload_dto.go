@@ -0,0 +1,43 @@
+package dto
+
+import "time"
+
+type CreateVideoUploadRequest struct {
+	FileName    string `json:"file_name" binding:"required,max=255"`
+	FileSize    int64  `json:"file_size" binding:"required,min=1"`
+	ContentType string `json:"content_type" binding:"omitempty,max=100"`
+	Checksum    string `json:"checksum" binding:"omitempty,len=64,hexadecimal"` // SHA-256 (hex) của toàn bộ file
+}
+
+// Chunk được gửi bằng PATCH với body là raw bytes (tối thiểu min_chunk_size, trừ chunk cuối) và các header:
+//   - Upload-Offset: vị trí byte bắt đầu của chunk (phải bằng offset hiện tại)
+//   - Upload-Checksum: "sha256 <base64>" của chunk (không bắt buộc)
+type UploadVideoChunkRequest struct {
+	Offset   int64
+	Checksum string
+}
+
+type VideoUploadResponse struct {
+	UploadId        string     `json:"upload_id"`
+	LessonId        uint       `json:"lesson_id"`
+	CourseId        uint       `json:"course_id"`
+	FileName        string     `json:"file_name"`
+	FileSize        int64      `json:"file_size"`
+	Offset          int64      `json:"offset"`
+	ProgressPercent float64    `json:"progress_percent"`
+	Status          string     `json:"status"`
+	MinChunkSize    int64      `json:"min_chunk_size"`
+	MaxChunkSize    int64      `json:"max_chunk_size"`
+	VideoURL        string     `json:"video_url,omitempty"`
+	VideoDuration   int        `json:"video_duration"`
+	ErrorMessage    string     `json:"error_message,omitempty"`
+	ExpiresAt       time.Time  `json:"expires_at"`
+	CompletedAt     *time.Time `json:"completed_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+type GetLessonVideoUploadsResponse struct {
+	LessonId uint                  `json:"lesson_id"`
+	Uploads  []VideoUploadResponse `json:"uploads"`
+	Total    int                   `json:"total"`
+}
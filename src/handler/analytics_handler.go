@@ -91,3 +91,25 @@ func (ah *AnalyticsHandler) GetStudentAnalytics(ctx *gin.Context) {
 
 	utils.ResponseSuccess(ctx, http.StatusOK, response)
 }
+
+// GET /api/v1/instructor/analytics/courses/:course_id/lessons/:id/engagement - Thời lượng xem và retention của lesson video
+func (ah *AnalyticsHandler) GetLessonEngagement(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	courseId, lessonId, ok := parseCourseLessonIds(ctx)
+	if !ok {
+		return
+	}
+
+	response, err := ah.service.GetLessonEngagement(userId.(uint), courseId, lessonId)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
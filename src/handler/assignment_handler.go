@@ -0,0 +1,301 @@
+package handler
+
+import (
+	"lms/src/dto"
+	"lms/src/service"
+	"lms/src/utils"
+	"lms/src/validation"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AssignmentHandler struct {
+	service service.AssignmentService
+}
+
+func NewAssignmentHandler(service service.AssignmentService) *AssignmentHandler {
+	return &AssignmentHandler{
+		service: service,
+	}
+}
+
+// GET /api/v1/instructor/courses/:course_id/lessons/:id/assignment - Xem cấu hình assignment của lesson
+func (ah *AssignmentHandler) GetLessonAssignment(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	courseId, lessonId, ok := parseCourseLessonIds(ctx)
+	if !ok {
+		return
+	}
+
+	response, err := ah.service.GetLessonAssignment(userId.(uint), courseId, lessonId)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// PUT /api/v1/instructor/courses/:course_id/lessons/:id/assignment - Tạo/cập nhật assignment (hạn nộp, rubric, quy tắc nộp lại, phạt trễ)
+func (ah *AssignmentHandler) UpsertLessonAssignment(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	courseId, lessonId, ok := parseCourseLessonIds(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.UpsertAssignmentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := ah.service.UpsertLessonAssignment(userId.(uint), courseId, lessonId, &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/instructor/assignments/submissions - Hàng đợi chấm bài của instructor
+func (ah *AssignmentHandler) GetGradingQueue(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	var req dto.GetGradingQueueQueryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := ah.service.GetGradingQueue(userId.(uint), &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/instructor/assignments/submissions/:submission_id - Chi tiết bài nộp để chấm
+func (ah *AssignmentHandler) GetSubmissionForGrading(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	submissionId, ok := parseSubmissionId(ctx)
+	if !ok {
+		return
+	}
+
+	response, err := ah.service.GetSubmissionForGrading(userId.(uint), submissionId)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// POST /api/v1/instructor/assignments/submissions/:submission_id/grade - Chấm điểm theo rubric và gửi feedback
+func (ah *AssignmentHandler) GradeSubmission(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	submissionId, ok := parseSubmissionId(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.GradeSubmissionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := ah.service.GradeSubmission(userId.(uint), submissionId, &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/instructor/assignments/submissions/:submission_id/files/:file_id - Tải file bài nộp
+func (ah *AssignmentHandler) DownloadInstructorSubmissionFile(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	submissionId, ok := parseSubmissionId(ctx)
+	if !ok {
+		return
+	}
+
+	file, err := ah.service.GetInstructorSubmissionFile(userId.(uint), submissionId, ctx.Param("file_id"))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	servePrivateFile(ctx, file)
+}
+
+// GET /api/v1/assignments/lessons/:lesson_id - Thông tin assignment, hạn nộp và trạng thái nộp bài của user
+func (ah *AssignmentHandler) GetAssignmentInfo(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	lessonId, err := strconv.ParseUint(ctx.Param("lesson_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid lesson Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	response, err := ah.service.GetAssignmentInfo(userId.(uint), uint(lessonId))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// POST /api/v1/assignments/lessons/:lesson_id/submissions - Nộp bài (multipart: text_content + files)
+func (ah *AssignmentHandler) SubmitAssignment(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	lessonId, err := strconv.ParseUint(ctx.Param("lesson_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid lesson Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	var req dto.SubmitAssignmentRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	var files []*multipart.FileHeader
+	if form, err := ctx.MultipartForm(); err == nil {
+		files = form.File["files"]
+	}
+
+	response, err := ah.service.SubmitAssignment(userId.(uint), uint(lessonId), &req, files)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusCreated, response)
+}
+
+// GET /api/v1/assignments/lessons/:lesson_id/submissions - Lịch sử nộp bài của user
+func (ah *AssignmentHandler) GetMySubmissions(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	lessonId, err := strconv.ParseUint(ctx.Param("lesson_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid lesson Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	response, err := ah.service.GetMySubmissions(userId.(uint), uint(lessonId))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/assignments/submissions/:submission_id - Chi tiết bài nộp, điểm và feedback
+func (ah *AssignmentHandler) GetMySubmission(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	submissionId, ok := parseSubmissionId(ctx)
+	if !ok {
+		return
+	}
+
+	response, err := ah.service.GetMySubmission(userId.(uint), submissionId)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/assignments/submissions/:submission_id/files/:file_id - Tải lại file đã nộp
+func (ah *AssignmentHandler) DownloadMySubmissionFile(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	submissionId, ok := parseSubmissionId(ctx)
+	if !ok {
+		return
+	}
+
+	file, err := ah.service.GetMySubmissionFile(userId.(uint), submissionId, ctx.Param("file_id"))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	servePrivateFile(ctx, file)
+}
+
+func parseSubmissionId(ctx *gin.Context) (uint, bool) {
+	submissionId, err := strconv.ParseUint(ctx.Param("submission_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid submission Id format", utils.ErrCodeBadRequest))
+		return 0, false
+	}
+	return uint(submissionId), true
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"bytes"
+	"fmt"
+	"lms/src/service"
+	"lms/src/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CertificateHandler struct {
+	service service.CertificateService
+}
+
+func NewCertificateHandler(service service.CertificateService) *CertificateHandler {
+	return &CertificateHandler{
+		service: service,
+	}
+}
+
+// GET /api/v1/certificates/:serial - Xác thực chứng chỉ công khai theo số hiệu
+func (ch *CertificateHandler) VerifyCertificate(ctx *gin.Context) {
+	response, err := ch.service.VerifyCertificate(ctx.Param("serial"))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/certificates/my - Danh sách chứng chỉ của user đang đăng nhập
+func (ch *CertificateHandler) GetMyCertificates(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	response, err := ch.service.GetMyCertificates(userId.(uint))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/certificates/:serial/download - Tải PDF chứng chỉ (chỉ chủ sở hữu)
+func (ch *CertificateHandler) DownloadCertificate(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	var buf bytes.Buffer
+	fileName, err := ch.service.RenderCertificate(userId.(uint), ctx.Param("serial"), &buf)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, fileName))
+	ctx.Header("Cache-Control", "private, max-age=0")
+	ctx.Data(http.StatusOK, "application/pdf", buf.Bytes())
+}
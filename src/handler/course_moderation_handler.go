@@ -0,0 +1,195 @@
+package handler
+
+import (
+	"lms/src/dto"
+	"lms/src/service"
+	"lms/src/utils"
+	"lms/src/validation"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CourseModerationHandler struct {
+	service service.CourseModerationService
+}
+
+func NewCourseModerationHandler(service service.CourseModerationService) *CourseModerationHandler {
+	return &CourseModerationHandler{
+		service: service,
+	}
+}
+
+// POST /api/v1/instructor/courses/:course_id/submit-review - Gửi course cho admin review
+func (mh *CourseModerationHandler) SubmitForReview(ctx *gin.Context) {
+	// Lấy instructor ID từ context
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	// Lấy course ID từ URL parameter
+	courseIdParam := ctx.Param("course_id")
+	if courseIdParam == "" {
+		utils.ResponseError(ctx, utils.NewError("Course Id is required", utils.ErrCodeBadRequest))
+		return
+	}
+
+	courseId, err := strconv.ParseUint(courseIdParam, 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid course Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	var req dto.SubmitCourseReviewRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := mh.service.SubmitForReview(userId.(uint), uint(courseId), &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusCreated, response)
+}
+
+// GET /api/v1/instructor/courses/:course_id/reviews - Lịch sử review của course
+func (mh *CourseModerationHandler) GetCourseModerations(ctx *gin.Context) {
+	// Lấy instructor ID từ context
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	// Lấy course ID từ URL parameter
+	courseIdParam := ctx.Param("course_id")
+	if courseIdParam == "" {
+		utils.ResponseError(ctx, utils.NewError("Course Id is required", utils.ErrCodeBadRequest))
+		return
+	}
+
+	courseId, err := strconv.ParseUint(courseIdParam, 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid course Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	response, err := mh.service.GetCourseModerations(userId.(uint), uint(courseId))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/admin/moderation/courses - Hàng đợi review course (Admin)
+func (mh *CourseModerationHandler) GetModerationQueue(ctx *gin.Context) {
+	var req dto.GetModerationQueueQueryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := mh.service.GetModerationQueue(&req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/admin/moderation/courses/:id - Chi tiết yêu cầu review (Admin)
+func (mh *CourseModerationHandler) GetModerationDetail(ctx *gin.Context) {
+	moderationId, ok := parseModerationId(ctx)
+	if !ok {
+		return
+	}
+
+	response, err := mh.service.GetModerationDetail(moderationId)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// POST /api/v1/admin/moderation/courses/:id/approve - Duyệt và publish course (Admin)
+func (mh *CourseModerationHandler) ApproveCourse(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	moderationId, ok := parseModerationId(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.ApproveCourseRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := mh.service.ApproveCourse(userId.(uint), moderationId, &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// POST /api/v1/admin/moderation/courses/:id/reject - Từ chối course kèm nhận xét (Admin)
+func (mh *CourseModerationHandler) RejectCourse(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	moderationId, ok := parseModerationId(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.RejectCourseRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := mh.service.RejectCourse(userId.(uint), moderationId, &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+func parseModerationId(ctx *gin.Context) (uint, bool) {
+	moderationIdParam := ctx.Param("id")
+	if moderationIdParam == "" {
+		utils.ResponseError(ctx, utils.NewError("Review request Id is required", utils.ErrCodeBadRequest))
+		return 0, false
+	}
+
+	moderationId, err := strconv.ParseUint(moderationIdParam, 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid review request Id format", utils.ErrCodeBadRequest))
+		return 0, false
+	}
+
+	return uint(moderationId), true
+}
@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"lms/src/dto"
+	"lms/src/service"
+	"lms/src/utils"
+	"lms/src/validation"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CoursePackageHandler struct {
+	service service.CoursePackageService
+}
+
+func NewCoursePackageHandler(service service.CoursePackageService) *CoursePackageHandler {
+	return &CoursePackageHandler{
+		service: service,
+	}
+}
+
+// GET /api/v1/instructor/courses/:course_id/export - Tải course dưới dạng package ZIP (manifest.json + file đính kèm)
+func (ph *CoursePackageHandler) ExportCourse(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	courseId, err := strconv.ParseUint(ctx.Param("course_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid course Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	// Ghi ra file tạm trước để lỗi giữa chừng vẫn trả được JSON thay vì file ZIP hỏng
+	tmp, err := os.CreateTemp("", "course-export-*.zip")
+	if err != nil {
+		utils.ResponseError(ctx, utils.WrapError(err, "Failed to create export file", utils.ErrCodeInternal))
+		return
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	fileName, err := ph.service.ExportCourse(userId.(uint), uint(courseId), tmp)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	ctx.FileAttachment(tmp.Name(), fileName)
+}
+
+// POST /api/v1/instructor/courses/import - Import package ZIP thành course draft (multipart: package, category_id, dry_run)
+func (ph *CoursePackageHandler) ImportCourse(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	var req dto.ImportCoursePackageRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	// Lấy file từ form data
+	fileHeader, err := ctx.FormFile("package")
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Course package file is required", utils.ErrCodeBadRequest))
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		utils.ResponseError(ctx, utils.WrapError(err, "Failed to read course package", utils.ErrCodeBadRequest))
+		return
+	}
+	defer file.Close()
+
+	response, err := ph.service.ImportCourse(userId.(uint), &req, file, fileHeader.Size)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	switch {
+	case response.Imported:
+		utils.ResponseSuccess(ctx, http.StatusCreated, response)
+	case req.DryRun:
+		utils.ResponseSuccess(ctx, http.StatusOK, response)
+	default:
+		// Có conflict chặn import, trả kèm danh sách để instructor xử lý
+		ctx.JSON(http.StatusConflict, gin.H{
+			"error":     response.Message,
+			"code":      utils.ErrCodeConflict,
+			"conflicts": response.Conflicts,
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"lms/src/dto"
+	"lms/src/service"
+	"lms/src/utils"
+	"lms/src/validation"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CoursePrerequisiteHandler struct {
+	service service.CoursePrerequisiteService
+}
+
+func NewCoursePrerequisiteHandler(service service.CoursePrerequisiteService) *CoursePrerequisiteHandler {
+	return &CoursePrerequisiteHandler{
+		service: service,
+	}
+}
+
+// GET /api/v1/instructor/courses/:course_id/prerequisites - Danh sách course prerequisites
+func (ph *CoursePrerequisiteHandler) GetCoursePrerequisites(ctx *gin.Context) {
+	// Lấy instructor ID từ context
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	// Lấy course ID từ URL parameter
+	courseId, err := strconv.ParseUint(ctx.Param("course_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid course Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	response, err := ph.service.GetCoursePrerequisites(userId.(uint), uint(courseId))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// PUT /api/v1/instructor/courses/:course_id/prerequisites - Cập nhật course prerequisites
+func (ph *CoursePrerequisiteHandler) UpdateCoursePrerequisites(ctx *gin.Context) {
+	// Lấy instructor ID từ context
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	// Lấy course ID từ URL parameter
+	courseId, err := strconv.ParseUint(ctx.Param("course_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid course Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	var req dto.UpdateCoursePrerequisitesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := ph.service.UpdateCoursePrerequisites(userId.(uint), uint(courseId), &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
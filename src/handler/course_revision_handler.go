@@ -0,0 +1,301 @@
+package handler
+
+import (
+	"lms/src/dto"
+	"lms/src/service"
+	"lms/src/utils"
+	"lms/src/validation"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CourseRevisionHandler struct {
+	service service.CourseRevisionService
+}
+
+func NewCourseRevisionHandler(service service.CourseRevisionService) *CourseRevisionHandler {
+	return &CourseRevisionHandler{
+		service: service,
+	}
+}
+
+// GET /api/v1/instructor/courses/:course_id/revisions - Lịch sử revisions của course
+func (rh *CourseRevisionHandler) GetCourseRevisions(ctx *gin.Context) {
+	// Lấy instructor ID từ context
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	// Lấy course ID từ URL parameter
+	courseIdParam := ctx.Param("course_id")
+	if courseIdParam == "" {
+		utils.ResponseError(ctx, utils.NewError("Course Id is required", utils.ErrCodeBadRequest))
+		return
+	}
+
+	courseId, err := strconv.ParseUint(courseIdParam, 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid course Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	var req dto.GetCourseRevisionsQueryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := rh.service.GetCourseRevisions(userId.(uint), uint(courseId), &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// POST /api/v1/instructor/courses/:course_id/revisions - Tạo draft revision từ bản live
+func (rh *CourseRevisionHandler) CreateDraft(ctx *gin.Context) {
+	// Lấy instructor ID từ context
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	// Lấy course ID từ URL parameter
+	courseIdParam := ctx.Param("course_id")
+	if courseIdParam == "" {
+		utils.ResponseError(ctx, utils.NewError("Course Id is required", utils.ErrCodeBadRequest))
+		return
+	}
+
+	courseId, err := strconv.ParseUint(courseIdParam, 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid course Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	var req dto.CreateCourseRevisionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := rh.service.CreateDraft(userId.(uint), uint(courseId), &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusCreated, response)
+}
+
+// GET /api/v1/instructor/courses/:course_id/revisions/draft - Xem draft revision
+func (rh *CourseRevisionHandler) GetDraft(ctx *gin.Context) {
+	// Lấy instructor ID từ context
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	// Lấy course ID từ URL parameter
+	courseIdParam := ctx.Param("course_id")
+	if courseIdParam == "" {
+		utils.ResponseError(ctx, utils.NewError("Course Id is required", utils.ErrCodeBadRequest))
+		return
+	}
+
+	courseId, err := strconv.ParseUint(courseIdParam, 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid course Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	response, err := rh.service.GetDraft(userId.(uint), uint(courseId))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// PUT /api/v1/instructor/courses/:course_id/revisions/draft - Cập nhật draft revision
+func (rh *CourseRevisionHandler) UpdateDraft(ctx *gin.Context) {
+	// Lấy instructor ID từ context
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	// Lấy course ID từ URL parameter
+	courseIdParam := ctx.Param("course_id")
+	if courseIdParam == "" {
+		utils.ResponseError(ctx, utils.NewError("Course Id is required", utils.ErrCodeBadRequest))
+		return
+	}
+
+	courseId, err := strconv.ParseUint(courseIdParam, 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid course Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	var req dto.UpdateCourseRevisionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := rh.service.UpdateDraft(userId.(uint), uint(courseId), &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// DELETE /api/v1/instructor/courses/:course_id/revisions/draft - Hủy draft revision
+func (rh *CourseRevisionHandler) DiscardDraft(ctx *gin.Context) {
+	// Lấy instructor ID từ context
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	// Lấy course ID từ URL parameter
+	courseIdParam := ctx.Param("course_id")
+	if courseIdParam == "" {
+		utils.ResponseError(ctx, utils.NewError("Course Id is required", utils.ErrCodeBadRequest))
+		return
+	}
+
+	courseId, err := strconv.ParseUint(courseIdParam, 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid course Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	response, err := rh.service.DiscardDraft(userId.(uint), uint(courseId))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/instructor/courses/:course_id/revisions/draft/diff - So sánh draft với bản live
+func (rh *CourseRevisionHandler) GetDraftDiff(ctx *gin.Context) {
+	// Lấy instructor ID từ context
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	// Lấy course ID từ URL parameter
+	courseIdParam := ctx.Param("course_id")
+	if courseIdParam == "" {
+		utils.ResponseError(ctx, utils.NewError("Course Id is required", utils.ErrCodeBadRequest))
+		return
+	}
+
+	courseId, err := strconv.ParseUint(courseIdParam, 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid course Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	response, err := rh.service.GetDraftDiff(userId.(uint), uint(courseId))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// POST /api/v1/instructor/courses/:course_id/revisions/draft/publish - Publish draft thay cho bản live
+func (rh *CourseRevisionHandler) PublishDraft(ctx *gin.Context) {
+	// Lấy instructor ID từ context
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	// Lấy course ID từ URL parameter
+	courseIdParam := ctx.Param("course_id")
+	if courseIdParam == "" {
+		utils.ResponseError(ctx, utils.NewError("Course Id is required", utils.ErrCodeBadRequest))
+		return
+	}
+
+	courseId, err := strconv.ParseUint(courseIdParam, 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid course Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	response, err := rh.service.PublishDraft(userId.(uint), uint(courseId))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// POST /api/v1/instructor/courses/:course_id/revisions/:revision_id/rollback - Rollback course về revision cũ
+func (rh *CourseRevisionHandler) RollbackToRevision(ctx *gin.Context) {
+	// Lấy instructor ID từ context
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	// Lấy course ID từ URL parameter
+	courseIdParam := ctx.Param("course_id")
+	if courseIdParam == "" {
+		utils.ResponseError(ctx, utils.NewError("Course Id is required", utils.ErrCodeBadRequest))
+		return
+	}
+
+	courseId, err := strconv.ParseUint(courseIdParam, 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid course Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	// Lấy revision ID từ URL parameter
+	revisionIdParam := ctx.Param("revision_id")
+	if revisionIdParam == "" {
+		utils.ResponseError(ctx, utils.NewError("Revision Id is required", utils.ErrCodeBadRequest))
+		return
+	}
+
+	revisionId, err := strconv.ParseUint(revisionIdParam, 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid revision Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	response, err := rh.service.RollbackToRevision(userId.(uint), uint(courseId), uint(revisionId))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
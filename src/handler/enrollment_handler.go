@@ -94,6 +94,31 @@ func (eh *EnrollmentHandler) CheckEnrollment(ctx *gin.Context) {
 	utils.ResponseSuccess(ctx, http.StatusOK, response)
 }
 
+// POST /api/v1/courses/:course_id/unenroll - Học viên tự hủy enrollment
+func (eh *EnrollmentHandler) DropEnrollment(ctx *gin.Context) {
+	// Lấy course ID từ URL parameter
+	courseId, err := strconv.ParseUint(ctx.Param("course_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid course Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	// Lấy user ID từ context
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	response, err := eh.service.DropEnrollment(userId.(uint), uint(courseId))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
 // GET /api/v1/enrollments/my - Lấy danh sách enrollments của user
 func (eh *EnrollmentHandler) GetMyEnrollments(ctx *gin.Context) {
 	// Lấy user ID từ context
@@ -0,0 +1,229 @@
+package handler
+
+import (
+	"lms/src/dto"
+	"lms/src/service"
+	"lms/src/utils"
+	"lms/src/validation"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type EnrollmentManagementHandler struct {
+	service service.EnrollmentManagementService
+}
+
+func NewEnrollmentManagementHandler(service service.EnrollmentManagementService) *EnrollmentManagementHandler {
+	return &EnrollmentManagementHandler{
+		service: service,
+	}
+}
+
+// POST /api/v1/admin/enrollments - Admin cấp quyền học cho một user
+func (mh *EnrollmentManagementHandler) AdminEnrollUser(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	var req dto.AdminEnrollUserRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := mh.service.AdminEnrollUser(userId.(uint), &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusCreated, response)
+}
+
+// POST /api/v1/admin/courses/:course_id/enrollments/bulk - Cấp quyền học hàng loạt từ file CSV email
+func (mh *EnrollmentManagementHandler) BulkEnrollUsers(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	courseId, err := strconv.ParseUint(ctx.Param("course_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid course Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	var req dto.BulkEnrollRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	// Lấy file từ form data
+	file, err := ctx.FormFile("file")
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("CSV file is required", utils.ErrCodeBadRequest))
+		return
+	}
+
+	response, err := mh.service.BulkEnrollUsers(userId.(uint), uint(courseId), &req, file)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// POST /api/v1/admin/enrollments/:id/revoke - Thu hồi quyền học
+func (mh *EnrollmentManagementHandler) RevokeEnrollment(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	enrollmentId, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid enrollment Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	var req dto.RevokeEnrollmentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := mh.service.RevokeEnrollment(userId.(uint), uint(enrollmentId), &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// POST /api/v1/admin/enrollments/:id/transfer - Chuyển quyền học sang user khác
+func (mh *EnrollmentManagementHandler) TransferEnrollment(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	enrollmentId, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid enrollment Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	var req dto.TransferEnrollmentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := mh.service.TransferEnrollment(userId.(uint), uint(enrollmentId), &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/instructor/courses/:course_id/complimentary-access - Danh sách học viên được tặng quyền học và quota
+func (mh *EnrollmentManagementHandler) GetComplimentaryAccess(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	courseId, err := strconv.ParseUint(ctx.Param("course_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid course Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	response, err := mh.service.GetComplimentaryAccess(userId.(uint), uint(courseId))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// POST /api/v1/instructor/courses/:course_id/complimentary-access - Tặng quyền học cho học viên
+func (mh *EnrollmentManagementHandler) GrantComplimentaryAccess(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	courseId, err := strconv.ParseUint(ctx.Param("course_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid course Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	var req dto.GrantComplimentaryAccessRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := mh.service.GrantComplimentaryAccess(userId.(uint), uint(courseId), &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusCreated, response)
+}
+
+// POST /api/v1/instructor/courses/:course_id/complimentary-access/:enrollment_id/revoke - Thu hồi quyền học đã tặng
+func (mh *EnrollmentManagementHandler) RevokeComplimentaryAccess(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	courseId, err := strconv.ParseUint(ctx.Param("course_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid course Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	enrollmentId, err := strconv.ParseUint(ctx.Param("enrollment_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid enrollment Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	// Body (reason) không bắt buộc
+	var req dto.RevokeComplimentaryAccessRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+			return
+		}
+	}
+
+	response, err := mh.service.RevokeComplimentaryAccess(userId.(uint), uint(courseId), uint(enrollmentId), &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
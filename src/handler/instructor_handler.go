@@ -113,6 +113,71 @@ func (ih *InstructorHandler) UpdateCourse(ctx *gin.Context) {
 	utils.ResponseSuccess(ctx, http.StatusOK, response)
 }
 
+// POST /api/v1/instructor/courses/:course_id/thumbnail - Upload thumbnail cho course
+func (ih *InstructorHandler) UploadCourseThumbnail(ctx *gin.Context) {
+	// Lấy instructor ID từ context
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	// Lấy course ID từ URL parameter
+	courseId, err := strconv.ParseUint(ctx.Param("course_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid course Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	// Lấy file từ form-data
+	file, err := ctx.FormFile("thumbnail")
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Thumbnail file is required", utils.ErrCodeBadRequest))
+		return
+	}
+
+	// Gọi service để xử lý và lưu thumbnail
+	response, err := ih.service.UploadCourseThumbnail(userId.(uint), uint(courseId), file)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// POST /api/v1/instructor/courses/:course_id/images - Upload ảnh để nhúng vào nội dung Markdown
+func (ih *InstructorHandler) UploadContentImage(ctx *gin.Context) {
+	// Lấy instructor ID từ context
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	// Lấy course ID từ URL parameter
+	courseId, err := strconv.ParseUint(ctx.Param("course_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid course Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	// Lấy file từ form-data
+	file, err := ctx.FormFile("image")
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Image file is required", utils.ErrCodeBadRequest))
+		return
+	}
+
+	response, err := ih.service.UploadContentImage(userId.(uint), uint(courseId), file)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusCreated, response)
+}
+
 // DELETE /api/v1/instructor/courses/:user_id - Xóa course
 func (ih *InstructorHandler) DeleteCourse(ctx *gin.Context) {
 	// Lấy instructor ID từ context
@@ -361,3 +426,62 @@ func (ih *InstructorHandler) ReorderLessons(ctx *gin.Context) {
 
 	utils.ResponseSuccess(ctx, http.StatusOK, response)
 }
+
+// POST /api/v1/instructor/courses/:course_id/clone - Sao chép course (lessons, quiz, assignment, resources) thành draft mới
+func (ih *InstructorHandler) CloneCourse(ctx *gin.Context) {
+	// Lấy instructor ID từ context
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	// Lấy course ID từ URL parameter
+	courseId, err := strconv.ParseUint(ctx.Param("course_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid course Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	// Body không bắt buộc (chỉ dùng để đặt title mới)
+	var req dto.CloneCourseRequest
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+			return
+		}
+	}
+
+	// Gọi service để clone course
+	response, err := ih.service.CloneCourse(userId.(uint), uint(courseId), &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusCreated, response)
+}
+
+// POST /api/v1/instructor/courses/:course_id/lessons/:id/duplicate - Nhân bản lesson, đặt ở cuối course
+func (ih *InstructorHandler) DuplicateLesson(ctx *gin.Context) {
+	courseId, lessonId, ok := parseCourseLessonIds(ctx)
+	if !ok {
+		return
+	}
+
+	// Lấy instructor ID từ context
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	// Gọi service để duplicate lesson
+	response, err := ih.service.DuplicateLesson(userId.(uint), courseId, lessonId)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusCreated, response)
+}
@@ -0,0 +1,256 @@
+package handler
+
+import (
+	"lms/src/dto"
+	"lms/src/service"
+	"lms/src/utils"
+	"lms/src/validation"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LearningPathHandler struct {
+	service service.LearningPathService
+}
+
+func NewLearningPathHandler(service service.LearningPathService) *LearningPathHandler {
+	return &LearningPathHandler{
+		service: service,
+	}
+}
+
+// GET /api/v1/learning-paths - Danh sách learning paths đã published
+func (lh *LearningPathHandler) GetLearningPaths(ctx *gin.Context) {
+	var req dto.GetLearningPathsQueryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := lh.service.GetLearningPaths(&req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/learning-paths/:slug - Chi tiết learning path kèm danh sách courses theo thứ tự
+func (lh *LearningPathHandler) GetLearningPathBySlug(ctx *gin.Context) {
+	slug := ctx.Param("slug")
+	if slug == "" {
+		utils.ResponseError(ctx, utils.NewError("Slug is required", utils.ErrCodeBadRequest))
+		return
+	}
+
+	response, err := lh.service.GetLearningPathBySlug(slug)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// POST /api/v1/learning-paths/:slug/follow - Bắt đầu theo learning path
+func (lh *LearningPathHandler) FollowLearningPath(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	response, err := lh.service.FollowLearningPath(userId.(uint), ctx.Param("slug"))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusCreated, response)
+}
+
+// DELETE /api/v1/learning-paths/:slug/follow - Bỏ theo learning path
+func (lh *LearningPathHandler) UnfollowLearningPath(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	response, err := lh.service.UnfollowLearningPath(userId.(uint), ctx.Param("slug"))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/learning-paths/:slug/progress - Tiến độ của user trên learning path
+func (lh *LearningPathHandler) GetLearningPathProgress(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	response, err := lh.service.GetLearningPathProgress(userId.(uint), ctx.Param("slug"))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/users/learning-paths - Các learning paths user đang theo kèm tiến độ
+func (lh *LearningPathHandler) GetMyLearningPaths(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	response, err := lh.service.GetMyLearningPaths(userId.(uint))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/admin/learning-paths - Danh sách learning paths (Admin)
+func (lh *LearningPathHandler) GetAdminLearningPaths(ctx *gin.Context) {
+	var req dto.GetAdminLearningPathsQueryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := lh.service.GetAdminLearningPaths(&req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/admin/learning-paths/:id - Chi tiết learning path (Admin)
+func (lh *LearningPathHandler) GetAdminLearningPathDetail(ctx *gin.Context) {
+	pathId, ok := parseLearningPathId(ctx)
+	if !ok {
+		return
+	}
+
+	response, err := lh.service.GetAdminLearningPathDetail(pathId)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// POST /api/v1/admin/learning-paths - Tạo learning path (Admin)
+func (lh *LearningPathHandler) CreateLearningPath(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	var req dto.CreateLearningPathRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := lh.service.CreateLearningPath(userId.(uint), &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusCreated, response)
+}
+
+// PUT /api/v1/admin/learning-paths/:id - Cập nhật learning path (Admin)
+func (lh *LearningPathHandler) UpdateLearningPath(ctx *gin.Context) {
+	pathId, ok := parseLearningPathId(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateLearningPathRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := lh.service.UpdateLearningPath(pathId, &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// PUT /api/v1/admin/learning-paths/:id/courses - Sắp xếp lại courses trong learning path (Admin)
+func (lh *LearningPathHandler) UpdateLearningPathCourses(ctx *gin.Context) {
+	pathId, ok := parseLearningPathId(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateLearningPathCoursesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := lh.service.UpdateLearningPathCourses(pathId, &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// DELETE /api/v1/admin/learning-paths/:id - Xóa learning path (Admin)
+func (lh *LearningPathHandler) DeleteLearningPath(ctx *gin.Context) {
+	pathId, ok := parseLearningPathId(ctx)
+	if !ok {
+		return
+	}
+
+	response, err := lh.service.DeleteLearningPath(pathId)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+func parseLearningPathId(ctx *gin.Context) (uint, bool) {
+	pathIdParam := ctx.Param("id")
+	if pathIdParam == "" {
+		utils.ResponseError(ctx, utils.NewError("Learning path Id is required", utils.ErrCodeBadRequest))
+		return 0, false
+	}
+
+	pathId, err := strconv.ParseUint(pathIdParam, 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid learning path Id format", utils.ErrCodeBadRequest))
+		return 0, false
+	}
+
+	return uint(pathId), true
+}
@@ -51,10 +51,10 @@ func (lh *LessonHandler) GetCourseLessons(ctx *gin.Context) {
 	utils.ResponseSuccess(ctx, http.StatusOK, response)
 }
 
-// GET /api/v1/courses/id/:id/lessons/:slug - Lấy lesson detail (enrolled only)
+// GET /api/v1/courses/course_id/:course_id/lessons/:slug - Lấy lesson detail (enrolled only)
 func (lh *LessonHandler) GetLessonDetail(ctx *gin.Context) {
 	// Lấy course ID từ URL parameter
-	courseIdParam := ctx.Param("id")
+	courseIdParam := ctx.Param("course_id")
 	if courseIdParam == "" {
 		utils.ResponseError(ctx, utils.NewError("Course Id is required", utils.ErrCodeBadRequest))
 		return
@@ -0,0 +1,256 @@
+package handler
+
+import (
+	"fmt"
+	"lms/src/dto"
+	"lms/src/service"
+	"lms/src/utils"
+	"lms/src/validation"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LessonNoteHandler struct {
+	service service.LessonNoteService
+}
+
+func NewLessonNoteHandler(service service.LessonNoteService) *LessonNoteHandler {
+	return &LessonNoteHandler{
+		service: service,
+	}
+}
+
+// GET /api/v1/notes/lessons/:lesson_id - Ghi chú của user trong lesson
+func (nh *LessonNoteHandler) GetLessonNotes(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	lessonId, ok := parseNoteLessonId(ctx)
+	if !ok {
+		return
+	}
+
+	response, err := nh.service.GetLessonNotes(userId.(uint), lessonId)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// POST /api/v1/notes/lessons/:lesson_id - Tạo ghi chú (có thể gắn timestamp video)
+func (nh *LessonNoteHandler) CreateNote(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	lessonId, ok := parseNoteLessonId(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.CreateLessonNoteRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := nh.service.CreateNote(userId.(uint), lessonId, &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusCreated, response)
+}
+
+// PUT /api/v1/notes/:note_id - Cập nhật ghi chú
+func (nh *LessonNoteHandler) UpdateNote(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	noteId, ok := parseNoteId(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateLessonNoteRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := nh.service.UpdateNote(userId.(uint), noteId, &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// DELETE /api/v1/notes/:note_id - Xóa ghi chú
+func (nh *LessonNoteHandler) DeleteNote(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	noteId, ok := parseNoteId(ctx)
+	if !ok {
+		return
+	}
+
+	response, err := nh.service.DeleteNote(userId.(uint), noteId)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/notes/courses/:course_id/export - Tải toàn bộ ghi chú của course dạng Markdown
+func (nh *LessonNoteHandler) ExportCourseNotes(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	courseId, err := strconv.ParseUint(ctx.Param("course_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid course Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	fileName, content, err := nh.service.ExportCourseNotes(userId.(uint), uint(courseId))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, fileName))
+	ctx.Header("Cache-Control", "private, max-age=0")
+	ctx.Data(http.StatusOK, "text/markdown; charset=utf-8", content)
+}
+
+// GET /api/v1/users/me/notes - Tìm ghi chú trên mọi course (search, course_id, lesson_id)
+func (nh *LessonNoteHandler) SearchNotes(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	var req dto.SearchNotesQueryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := nh.service.SearchNotes(userId.(uint), &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// POST /api/v1/bookmarks/lessons/:lesson_id - Bookmark lesson
+func (nh *LessonNoteHandler) AddBookmark(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	lessonId, ok := parseNoteLessonId(ctx)
+	if !ok {
+		return
+	}
+
+	response, err := nh.service.AddBookmark(userId.(uint), lessonId)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// DELETE /api/v1/bookmarks/lessons/:lesson_id - Bỏ bookmark lesson
+func (nh *LessonNoteHandler) RemoveBookmark(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	lessonId, ok := parseNoteLessonId(ctx)
+	if !ok {
+		return
+	}
+
+	response, err := nh.service.RemoveBookmark(userId.(uint), lessonId)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/users/me/bookmarks - Danh sách lesson đã bookmark (lọc theo course_id)
+func (nh *LessonNoteHandler) GetBookmarks(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	var req dto.GetBookmarksQueryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := nh.service.GetBookmarks(userId.(uint), &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+func parseNoteLessonId(ctx *gin.Context) (uint, bool) {
+	lessonId, err := strconv.ParseUint(ctx.Param("lesson_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid lesson Id format", utils.ErrCodeBadRequest))
+		return 0, false
+	}
+	return uint(lessonId), true
+}
+
+func parseNoteId(ctx *gin.Context) (uint, bool) {
+	noteId, err := strconv.ParseUint(ctx.Param("note_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid note Id format", utils.ErrCodeBadRequest))
+		return 0, false
+	}
+	return uint(noteId), true
+}
@@ -0,0 +1,193 @@
+package handler
+
+import (
+	"lms/src/dto"
+	"lms/src/service"
+	"lms/src/utils"
+	"lms/src/validation"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LessonResourceHandler struct {
+	service service.LessonResourceService
+}
+
+func NewLessonResourceHandler(service service.LessonResourceService) *LessonResourceHandler {
+	return &LessonResourceHandler{
+		service: service,
+	}
+}
+
+// GET /api/v1/instructor/courses/:course_id/lessons/:id/resources - Danh sách tài liệu đính kèm của lesson
+func (rh *LessonResourceHandler) GetInstructorResources(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	courseId, lessonId, ok := parseCourseLessonIds(ctx)
+	if !ok {
+		return
+	}
+
+	response, err := rh.service.GetInstructorResources(userId.(uint), courseId, lessonId)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// POST /api/v1/instructor/courses/:course_id/lessons/:id/resources - Upload tài liệu (multipart: file, title, description)
+func (rh *LessonResourceHandler) UploadResource(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	courseId, lessonId, ok := parseCourseLessonIds(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.UploadLessonResourceRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	// Lấy file từ form data
+	file, err := ctx.FormFile("file")
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Resource file is required", utils.ErrCodeBadRequest))
+		return
+	}
+
+	response, err := rh.service.UploadResource(userId.(uint), courseId, lessonId, &req, file)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusCreated, response)
+}
+
+// PUT /api/v1/instructor/courses/:course_id/lessons/:id/resources/:resource_id - Cập nhật tiêu đề, mô tả, thứ tự
+func (rh *LessonResourceHandler) UpdateResource(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	courseId, lessonId, ok := parseCourseLessonIds(ctx)
+	if !ok {
+		return
+	}
+
+	resourceId, ok := parseResourceId(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateLessonResourceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := rh.service.UpdateResource(userId.(uint), courseId, lessonId, resourceId, &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// DELETE /api/v1/instructor/courses/:course_id/lessons/:id/resources/:resource_id - Xóa tài liệu
+func (rh *LessonResourceHandler) DeleteResource(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	courseId, lessonId, ok := parseCourseLessonIds(ctx)
+	if !ok {
+		return
+	}
+
+	resourceId, ok := parseResourceId(ctx)
+	if !ok {
+		return
+	}
+
+	response, err := rh.service.DeleteResource(userId.(uint), courseId, lessonId, resourceId)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/resources/lessons/:lesson_id - Danh sách tài liệu của lesson (chỉ học viên đã enroll)
+func (rh *LessonResourceHandler) GetLessonResources(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	lessonId, err := strconv.ParseUint(ctx.Param("lesson_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid lesson Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	response, err := rh.service.GetLessonResources(userId.(uint), uint(lessonId))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/resources/:resource_id/download - Tải tài liệu (kiểm tra enrollment)
+func (rh *LessonResourceHandler) DownloadResource(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	resourceId, ok := parseResourceId(ctx)
+	if !ok {
+		return
+	}
+
+	file, err := rh.service.GetResourceFile(userId.(uint), resourceId)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	servePrivateFile(ctx, file)
+}
+
+func parseResourceId(ctx *gin.Context) (uint, bool) {
+	resourceId, err := strconv.ParseUint(ctx.Param("resource_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid resource Id format", utils.ErrCodeBadRequest))
+		return 0, false
+	}
+	return uint(resourceId), true
+}
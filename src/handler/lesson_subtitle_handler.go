@@ -0,0 +1,224 @@
+package handler
+
+import (
+	"lms/src/dto"
+	"lms/src/service"
+	"lms/src/utils"
+	"lms/src/validation"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LessonSubtitleHandler struct {
+	service service.LessonSubtitleService
+}
+
+func NewLessonSubtitleHandler(service service.LessonSubtitleService) *LessonSubtitleHandler {
+	return &LessonSubtitleHandler{
+		service: service,
+	}
+}
+
+// GET /api/v1/instructor/courses/:course_id/lessons/:id/subtitles - Danh sách phụ đề của lesson
+func (sh *LessonSubtitleHandler) GetInstructorSubtitles(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	courseId, lessonId, ok := parseCourseLessonIds(ctx)
+	if !ok {
+		return
+	}
+
+	response, err := sh.service.GetInstructorSubtitles(userId.(uint), courseId, lessonId)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// POST /api/v1/instructor/courses/:course_id/lessons/:id/subtitles - Upload phụ đề (multipart: file, language, label, is_default)
+func (sh *LessonSubtitleHandler) UploadSubtitle(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	courseId, lessonId, ok := parseCourseLessonIds(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.UploadSubtitleRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	// Lấy file từ form data
+	file, err := ctx.FormFile("file")
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Subtitle file is required", utils.ErrCodeBadRequest))
+		return
+	}
+
+	response, err := sh.service.UploadSubtitle(userId.(uint), courseId, lessonId, &req, file)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusCreated, response)
+}
+
+// PUT /api/v1/instructor/courses/:course_id/lessons/:id/subtitles/:subtitle_id - Cập nhật nhãn, track mặc định
+func (sh *LessonSubtitleHandler) UpdateSubtitle(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	courseId, lessonId, ok := parseCourseLessonIds(ctx)
+	if !ok {
+		return
+	}
+
+	subtitleId, ok := parseSubtitleId(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateSubtitleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := sh.service.UpdateSubtitle(userId.(uint), courseId, lessonId, subtitleId, &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// DELETE /api/v1/instructor/courses/:course_id/lessons/:id/subtitles/:subtitle_id - Xóa phụ đề
+func (sh *LessonSubtitleHandler) DeleteSubtitle(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	courseId, lessonId, ok := parseCourseLessonIds(ctx)
+	if !ok {
+		return
+	}
+
+	subtitleId, ok := parseSubtitleId(ctx)
+	if !ok {
+		return
+	}
+
+	response, err := sh.service.DeleteSubtitle(userId.(uint), courseId, lessonId, subtitleId)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/subtitles/lessons/:lesson_id - Danh sách track phụ đề cho player
+func (sh *LessonSubtitleHandler) GetSubtitleTracks(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	lessonId, err := strconv.ParseUint(ctx.Param("lesson_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid lesson Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	response, err := sh.service.GetSubtitleTracks(userId.(uint), uint(lessonId))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/subtitles/lessons/:lesson_id/transcript?language=&q= - Transcript có timestamp, tìm kiếm theo text
+func (sh *LessonSubtitleHandler) GetTranscript(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	lessonId, err := strconv.ParseUint(ctx.Param("lesson_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid lesson Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	var req dto.TranscriptQueryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := sh.service.GetTranscript(userId.(uint), uint(lessonId), &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/subtitles/:subtitle_id/vtt?uid=&exp=&sig= - File WebVTT cho thẻ <track>
+func (sh *LessonSubtitleHandler) GetSubtitleFile(ctx *gin.Context) {
+	subtitleId, ok := parseSubtitleId(ctx)
+	if !ok {
+		return
+	}
+
+	var query dto.MediaStreamQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	data, err := sh.service.GetSubtitleFile(subtitleId, &query)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	// URL gắn với từng user nên không cho proxy/CDN cache chung
+	ctx.Header("Cache-Control", "private, max-age=0")
+	ctx.Data(http.StatusOK, "text/vtt; charset=utf-8", data)
+}
+
+func parseSubtitleId(ctx *gin.Context) (uint, bool) {
+	subtitleId, err := strconv.ParseUint(ctx.Param("subtitle_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid subtitle Id format", utils.ErrCodeBadRequest))
+		return 0, false
+	}
+	return uint(subtitleId), true
+}
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"lms/src/dto"
+	"lms/src/service"
+	"lms/src/utils"
+	"lms/src/validation"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MediaHandler struct {
+	service service.MediaService
+}
+
+func NewMediaHandler(service service.MediaService) *MediaHandler {
+	return &MediaHandler{
+		service: service,
+	}
+}
+
+// GET /api/v1/media/lessons/:lesson_id/url - Lấy signed URL (có thời hạn) để xem video của lesson
+func (mh *MediaHandler) GetLessonMediaURL(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	lessonId, err := strconv.ParseUint(ctx.Param("lesson_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid lesson Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	response, err := mh.service.GetLessonMediaURL(userId.(uint), uint(lessonId))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/media/lessons/:lesson_id/stream?uid=&exp=&sig= - Stream video (hỗ trợ HTTP Range)
+func (mh *MediaHandler) StreamLessonMedia(ctx *gin.Context) {
+	lessonId, err := strconv.ParseUint(ctx.Param("lesson_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid lesson Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	var query dto.MediaStreamQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	source, err := mh.service.ResolveLessonMedia(uint(lessonId), &query)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	// Không cho proxy/CDN dùng chung cache vì URL gắn với từng user
+	ctx.Header("Cache-Control", "private, max-age=0")
+
+	// Storage hỗ trợ signed URL (S3): client tải trực tiếp từ storage
+	if source.RedirectURL != "" {
+		ctx.Redirect(http.StatusFound, source.RedirectURL)
+		return
+	}
+	defer source.Content.Close()
+
+	// ServeContent xử lý Range, If-Range, If-Modified-Since và Content-Type
+	http.ServeContent(ctx.Writer, ctx.Request, source.Name, source.ModTime, source.Content)
+}
@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"io"
+	"lms/src/service"
+	"mime"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// servePrivateFile stream file private (resource, bài nộp) về client dạng attachment với tên file gốc
+func servePrivateFile(ctx *gin.Context, file *service.PrivateFile) {
+	defer file.Content.Close()
+
+	// FormatMediaType tự chuyển sang filename* (RFC 2231) khi tên file có ký tự ngoài ASCII
+	ctx.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": file.Name}))
+	ctx.Header("Content-Type", file.ContentType)
+	ctx.Header("Cache-Control", "private, max-age=0")
+
+	// Local storage trả về file seek được: ServeContent xử lý Range và If-Modified-Since
+	if seeker, ok := file.Content.(io.ReadSeeker); ok {
+		http.ServeContent(ctx.Writer, ctx.Request, file.Name, file.ModTime, seeker)
+		return
+	}
+
+	ctx.DataFromReader(http.StatusOK, file.Size, file.ContentType, file.Content, nil)
+}
@@ -131,3 +131,57 @@ func (ph *ProgressHandler) UpdateLessonPosition(ctx *gin.Context) {
 
 	utils.ResponseSuccess(ctx, http.StatusOK, response)
 }
+
+// POST /api/v1/progress/:lesson_id/heartbeat - Ghi nhận đoạn video vừa xem (player gửi định kỳ khi đang phát)
+func (ph *ProgressHandler) RecordHeartbeat(ctx *gin.Context) {
+	lessonId, err := strconv.ParseUint(ctx.Param("lesson_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid lesson Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	// Lấy user ID từ context
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	var req dto.WatchHeartbeatRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := ph.service.RecordHeartbeat(userId.(uint), uint(lessonId), &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// POST /api/v1/progress/sync - Đồng bộ batch vị trí/hoàn thành lesson ghi nhận offline
+func (ph *ProgressHandler) SyncProgress(ctx *gin.Context) {
+	// Lấy user ID từ context
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	var req dto.SyncProgressRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := ph.service.SyncProgress(userId.(uint), &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
@@ -0,0 +1,266 @@
+package handler
+
+import (
+	"io"
+	"lms/src/dto"
+	"lms/src/scorm"
+	"lms/src/service"
+	"lms/src/utils"
+	"lms/src/validation"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ScormHandler struct {
+	service service.ScormService
+}
+
+func NewScormHandler(service service.ScormService) *ScormHandler {
+	return &ScormHandler{
+		service: service,
+	}
+}
+
+// GET /api/v1/instructor/courses/:course_id/lessons/:id/scorm - Thông tin package SCORM của lesson
+func (sh *ScormHandler) GetInstructorPackage(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	courseId, lessonId, ok := parseCourseLessonIds(ctx)
+	if !ok {
+		return
+	}
+
+	response, err := sh.service.GetInstructorPackage(userId.(uint), courseId, lessonId)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// PUT /api/v1/instructor/courses/:course_id/lessons/:id/scorm - Upload (hoặc thay) package SCORM 1.2 (multipart: package)
+func (sh *ScormHandler) UploadPackage(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	courseId, lessonId, ok := parseCourseLessonIds(ctx)
+	if !ok {
+		return
+	}
+
+	// Lấy file từ form data
+	file, err := ctx.FormFile("package")
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("SCORM package file is required", utils.ErrCodeBadRequest))
+		return
+	}
+
+	response, err := sh.service.UploadPackage(userId.(uint), courseId, lessonId, file)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// DELETE /api/v1/instructor/courses/:course_id/lessons/:id/scorm - Xóa package SCORM của lesson
+func (sh *ScormHandler) DeletePackage(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	courseId, lessonId, ok := parseCourseLessonIds(ctx)
+	if !ok {
+		return
+	}
+
+	response, err := sh.service.DeletePackage(userId.(uint), courseId, lessonId)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// POST /api/v1/scorm/lessons/:lesson_id/launch - Tạo phiên học SCORM, trả về URL player đã ký để nhúng iframe
+func (sh *ScormHandler) LaunchLesson(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	lessonId, err := strconv.ParseUint(ctx.Param("lesson_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid lesson Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	response, err := sh.service.LaunchLesson(userId.(uint), uint(lessonId))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/scorm/sessions/:lesson_id/:uid/:exp/:sig/player - Trang player chứa API adapter SCORM 1.2 (window.API)
+func (sh *ScormHandler) GetPlayer(ctx *gin.Context) {
+	lessonId, query, ok := parseScormSession(ctx)
+	if !ok {
+		return
+	}
+
+	player, err := sh.service.GetPlayer(lessonId, query)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	setScormSessionHeaders(ctx)
+	ctx.Header("Content-Type", "text/html; charset=utf-8")
+	ctx.Status(http.StatusOK)
+	if err := scormPlayerTemplate.Execute(ctx.Writer, player); err != nil {
+		ctx.Error(err)
+	}
+}
+
+// GET /api/v1/scorm/sessions/:lesson_id/:uid/:exp/:sig/runtime - LMSInitialize: mở phiên và lấy data model cmi.*
+func (sh *ScormHandler) GetRuntime(ctx *gin.Context) {
+	lessonId, query, ok := parseScormSession(ctx)
+	if !ok {
+		return
+	}
+
+	response, err := sh.service.GetRuntime(lessonId, query)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	setScormSessionHeaders(ctx)
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// PUT /api/v1/scorm/sessions/:lesson_id/:uid/:exp/:sig/runtime - LMSCommit/LMSFinish: lưu các giá trị đã LMSSetValue
+func (sh *ScormHandler) CommitRuntime(ctx *gin.Context) {
+	lessonId, query, ok := parseScormSession(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.CommitScormRuntimeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := sh.service.CommitRuntime(lessonId, query, &req)
+	if err != nil {
+		// Lỗi data model trả kèm mã lỗi SCORM để adapter báo lại qua LMSGetLastError
+		if appErr, ok := err.(*utils.AppError); ok {
+			if scormErr, ok := appErr.Err.(*scorm.Error); ok {
+				ctx.JSON(http.StatusBadRequest, gin.H{
+					"error":            appErr.Message,
+					"code":             appErr.Code,
+					"detail":           scormErr.Error(),
+					"element":          scormErr.Element,
+					"scorm_error_code": scormErr.Code,
+				})
+				return
+			}
+		}
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	setScormSessionHeaders(ctx)
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/scorm/sessions/:lesson_id/:uid/:exp/:sig/content/*path - File nội dung của package
+func (sh *ScormHandler) GetContent(ctx *gin.Context) {
+	lessonId, query, ok := parseScormSession(ctx)
+	if !ok {
+		return
+	}
+
+	content, err := sh.service.ResolveContent(lessonId, query, ctx.Param("path"))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+	defer content.Content.Close()
+
+	setScormSessionHeaders(ctx)
+	ctx.Header("Content-Type", content.ContentType)
+	// Nội dung chỉ được nhúng trong player cùng origin, không gửi dữ liệu hay form ra ngoài origin của phiên học
+	ctx.Header("Content-Security-Policy", "frame-ancestors 'self'; connect-src 'self'; form-action 'self'")
+
+	// Local storage trả về file seek được: ServeContent xử lý Range và If-Modified-Since
+	if seeker, ok := content.Content.(io.ReadSeeker); ok {
+		http.ServeContent(ctx.Writer, ctx.Request, content.Name, content.ModTime, seeker)
+		return
+	}
+
+	ctx.DataFromReader(http.StatusOK, content.Size, content.ContentType, content.Content, nil)
+}
+
+// parseScormSession đọc lesson và chữ ký phiên học từ path (chữ ký nằm trong path để URL tương đối trong nội dung vẫn hợp lệ)
+func parseScormSession(ctx *gin.Context) (uint, *dto.MediaStreamQuery, bool) {
+	// Có origin riêng thì không phục vụ phiên học trên origin của API
+	if origin, ok := utils.ScormContentOrigin(); ok {
+		if parsed, err := url.Parse(origin); err != nil || !strings.EqualFold(parsed.Host, ctx.Request.Host) {
+			utils.ResponseError(ctx, utils.NewError("SCORM session not found", utils.ErrCodeNotFound))
+			return 0, nil, false
+		}
+	}
+
+	lessonId, err := strconv.ParseUint(ctx.Param("lesson_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid lesson Id format", utils.ErrCodeBadRequest))
+		return 0, nil, false
+	}
+
+	userId, err := strconv.ParseUint(ctx.Param("uid"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid or expired SCORM session", utils.ErrCodeForbidden))
+		return 0, nil, false
+	}
+
+	expiresAt, err := strconv.ParseInt(ctx.Param("exp"), 10, 64)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid or expired SCORM session", utils.ErrCodeForbidden))
+		return 0, nil, false
+	}
+
+	return uint(lessonId), &dto.MediaStreamQuery{
+		UserId:    uint(userId),
+		ExpiresAt: expiresAt,
+		Signature: ctx.Param("sig"),
+	}, true
+}
+
+// setScormSessionHeaders - URL gắn với từng user: không cho proxy/CDN cache và không lộ qua Referer khi nội dung gọi ra ngoài
+func setScormSessionHeaders(ctx *gin.Context) {
+	ctx.Header("Cache-Control", "private, max-age=0")
+	ctx.Header("Referrer-Policy", "no-referrer")
+	ctx.Header("X-Content-Type-Options", "nosniff")
+}
@@ -0,0 +1,141 @@
+package handler
+
+import "html/template"
+
+// scormPlayerTemplate là trang player của phiên học SCORM 1.2: khai báo window.API cho nội dung trong iframe.
+// LMSGetValue/LMSSetValue đọc ghi trên bản sao trong bộ nhớ, LMSInitialize/LMSCommit/LMSFinish gọi runtime API
+// bằng XHR đồng bộ vì API SCORM 1.2 là đồng bộ.
+var scormPlayerTemplate = template.Must(template.New("scorm_player").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>html, body, iframe { margin: 0; padding: 0; width: 100%; height: 100%; border: 0; overflow: hidden; }</style>
+<script>
+(function () {
+	var errorStrings = {
+		"0": "No error",
+		"101": "General exception",
+		"201": "Invalid argument error",
+		"202": "Element cannot have children",
+		"203": "Element not an array. Cannot have count",
+		"301": "Not initialized",
+		"401": "Not implemented error",
+		"402": "Invalid set value, element is a keyword",
+		"403": "Element is read only",
+		"404": "Element is write only",
+		"405": "Incorrect data type"
+	};
+	var writable = {
+		"cmi.core.lesson_status": true,
+		"cmi.core.lesson_location": true,
+		"cmi.core.score.raw": true,
+		"cmi.core.score.min": true,
+		"cmi.core.score.max": true,
+		"cmi.core.exit": true,
+		"cmi.core.session_time": true,
+		"cmi.suspend_data": true
+	};
+	var writeOnly = { "cmi.core.exit": true, "cmi.core.session_time": true };
+
+	var values = {}, changes = {}, lastError = "0", diagnostic = "";
+	var initialized = false, finished = false;
+
+	function fail(code, detail) {
+		lastError = String(code);
+		diagnostic = detail || "";
+		return "false";
+	}
+
+	function request(method, body) {
+		var xhr = new XMLHttpRequest();
+		try {
+			xhr.open(method, "runtime", false);
+			xhr.setRequestHeader("Content-Type", "application/json");
+			xhr.send(body ? JSON.stringify(body) : null);
+		} catch (e) {
+			return { ok: false, code: 101, detail: String(e) };
+		}
+
+		var data = null;
+		try { data = JSON.parse(xhr.responseText); } catch (e) {}
+		if (xhr.status >= 200 && xhr.status < 300 && data && data.data) {
+			return { ok: true, data: data.data };
+		}
+		return {
+			ok: false,
+			code: (data && data.scorm_error_code) || 101,
+			detail: (data && (data.detail || data.error)) || ("HTTP " + xhr.status)
+		};
+	}
+
+	function commit(finish) {
+		var result = request("PUT", { values: changes, finish: finish });
+		if (!result.ok) {
+			return fail(result.code, result.detail);
+		}
+		changes = {};
+		values = result.data.values;
+		lastError = "0";
+		return "true";
+	}
+
+	window.API = {
+		LMSInitialize: function (arg) {
+			if (arg !== "") return fail(201, "argument must be an empty string");
+			if (initialized) return fail(101, "already initialized");
+			var result = request("GET");
+			if (!result.ok) return fail(result.code, result.detail);
+			values = result.data.values;
+			initialized = true;
+			finished = false;
+			lastError = "0";
+			return "true";
+		},
+		LMSFinish: function (arg) {
+			if (arg !== "") return fail(201, "argument must be an empty string");
+			if (!initialized || finished) return fail(301);
+			var result = commit(true);
+			if (result === "true") finished = true;
+			return result;
+		},
+		LMSGetValue: function (element) {
+			if (!initialized || finished) { fail(301); return ""; }
+			if (writeOnly[element]) { fail(404); return ""; }
+			if (!Object.prototype.hasOwnProperty.call(values, element)) { fail(401, element); return ""; }
+			lastError = "0";
+			return values[element];
+		},
+		LMSSetValue: function (element, value) {
+			if (!initialized || finished) return fail(301);
+			if (!writable[element]) {
+				return fail(Object.prototype.hasOwnProperty.call(values, element) ? 403 : 401, element);
+			}
+			value = String(value);
+			changes[element] = value;
+			if (!writeOnly[element]) values[element] = value;
+			lastError = "0";
+			return "true";
+		},
+		LMSCommit: function (arg) {
+			if (arg !== "") return fail(201, "argument must be an empty string");
+			if (!initialized || finished) return fail(301);
+			return commit(false);
+		},
+		LMSGetLastError: function () { return lastError; },
+		LMSGetErrorString: function (code) { return errorStrings[String(code)] || ""; },
+		LMSGetDiagnostic: function (code) { return diagnostic; }
+	};
+
+	// Đóng tab khi nội dung chưa gọi LMSFinish: gửi nốt dữ liệu chưa commit
+	window.addEventListener("pagehide", function () {
+		if (initialized && !finished) commit(true);
+	});
+})();
+</script>
+</head>
+<body>
+<iframe src="{{.LaunchURL}}" title="{{.Title}}" allowfullscreen></iframe>
+</body>
+</html>
+`))
@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"lms/src/dto"
+	"lms/src/service"
+	"lms/src/utils"
+	"lms/src/validation"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type VideoUploadHandler struct {
+	service service.VideoUploadService
+}
+
+func NewVideoUploadHandler(service service.VideoUploadService) *VideoUploadHandler {
+	return &VideoUploadHandler{
+		service: service,
+	}
+}
+
+// POST /api/v1/instructor/courses/:course_id/lessons/:id/video-uploads - Bắt đầu upload video theo chunk
+func (vh *VideoUploadHandler) CreateUpload(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	courseId, lessonId, ok := parseCourseLessonIds(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.CreateVideoUploadRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := vh.service.CreateUpload(userId.(uint), courseId, lessonId, &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	ctx.Header("Upload-Offset", strconv.FormatInt(response.Offset, 10))
+	utils.ResponseSuccess(ctx, http.StatusCreated, response)
+}
+
+// GET /api/v1/instructor/courses/:course_id/lessons/:id/video-uploads - Trạng thái các lần upload video của lesson
+func (vh *VideoUploadHandler) GetLessonUploads(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	courseId, lessonId, ok := parseCourseLessonIds(ctx)
+	if !ok {
+		return
+	}
+
+	response, err := vh.service.GetLessonUploads(userId.(uint), courseId, lessonId)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/instructor/video-uploads/:upload_id - Lấy offset hiện tại để resume upload
+func (vh *VideoUploadHandler) GetUpload(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	response, err := vh.service.GetUpload(userId.(uint), ctx.Param("upload_id"))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	ctx.Header("Upload-Offset", strconv.FormatInt(response.Offset, 10))
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// PATCH /api/v1/instructor/video-uploads/:upload_id - Gửi một chunk (header Upload-Offset, Upload-Checksum)
+func (vh *VideoUploadHandler) UploadChunk(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	offset, err := strconv.ParseInt(ctx.GetHeader("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		utils.ResponseError(ctx, utils.NewError("Upload-Offset header is required", utils.ErrCodeBadRequest))
+		return
+	}
+
+	req := dto.UploadVideoChunkRequest{
+		Offset:   offset,
+		Checksum: ctx.GetHeader("Upload-Checksum"),
+	}
+
+	// Giới hạn kích thước chunk để không đọc body quá lớn vào bộ nhớ
+	body := http.MaxBytesReader(ctx.Writer, ctx.Request.Body, service.MaxVideoChunkSize())
+
+	response, err := vh.service.UploadChunk(userId.(uint), ctx.Param("upload_id"), &req, body)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	ctx.Header("Upload-Offset", strconv.FormatInt(response.Offset, 10))
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// DELETE /api/v1/instructor/video-uploads/:upload_id - Hủy upload và xóa phần đã tải lên
+func (vh *VideoUploadHandler) AbortUpload(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	response, err := vh.service.AbortUpload(userId.(uint), ctx.Param("upload_id"))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
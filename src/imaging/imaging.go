@@ -0,0 +1,253 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	_ "image/png" // Đăng ký decoder PNG cho image.Decode
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported image format")
+	ErrImageTooLarge     = errors.New("image dimensions are too large")
+	ErrAnimatedImage     = errors.New("animated images are not allowed")
+)
+
+// Limits chặn decompression bomb (file nhỏ nhưng kích thước ảnh rất lớn) và ảnh động
+type Limits struct {
+	MaxPixels     int
+	MaxDimension  int
+	AllowAnimated bool // true: dùng frame đầu tiên của ảnh động
+}
+
+// Spec là một kích thước output, Height = 0 nghĩa là giữ tỉ lệ theo Width (không phóng to)
+type Spec struct {
+	Name   string
+	Width  int
+	Height int
+}
+
+type Variant struct {
+	Name   string
+	Width  int
+	Height int
+	Data   []byte
+}
+
+// ContentType của các variant, thư viện chuẩn của Go chỉ encode được JPEG/PNG/GIF nên output là JPEG
+const ContentType = "image/jpeg"
+
+// Ext là extension của các variant
+const Ext = ".jpg"
+
+var (
+	// AvatarSpecs - avatar vuông
+	AvatarSpecs = []Spec{
+		{Name: "64", Width: 64, Height: 64},
+		{Name: "128", Width: 128, Height: 128},
+		{Name: "512", Width: 512, Height: 512},
+	}
+
+	// ThumbnailSpecs - thumbnail course tỉ lệ 16:9
+	ThumbnailSpecs = []Spec{
+		{Name: "320", Width: 320, Height: 180},
+		{Name: "640", Width: 640, Height: 360},
+		{Name: "1280", Width: 1280, Height: 720},
+	}
+
+	// ContentImageSpecs - ảnh nhúng trong nội dung Markdown, giữ tỉ lệ gốc
+	ContentImageSpecs = []Spec{
+		{Name: "1600", Width: 1600},
+	}
+)
+
+// Process decode ảnh, tự xoay theo EXIF, resize theo từng spec và encode lại thành JPEG.
+// Encode lại từ pixel nên toàn bộ metadata (EXIF, GPS...) của file gốc bị loại bỏ.
+func Process(data []byte, limits Limits, specs []Spec, quality int) ([]Variant, error) {
+	// 1. Đọc header để kiểm tra kích thước trước khi decode toàn bộ
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedFormat
+	}
+
+	if config.Width <= 0 || config.Height <= 0 ||
+		config.Width > limits.MaxDimension || config.Height > limits.MaxDimension ||
+		config.Width*config.Height > limits.MaxPixels {
+		return nil, ErrImageTooLarge
+	}
+
+	// 2. Decode (chặn ảnh động nếu không cho phép)
+	var img image.Image
+	switch format {
+	case "gif":
+		// Đếm frame trên dữ liệu thô, không decode toàn bộ animation (hàng nghìn frame full-size làm cạn bộ nhớ)
+		frames, err := countGIFFrames(data, limits)
+		if err != nil {
+			return nil, err
+		}
+		if frames > 1 && !limits.AllowAnimated {
+			return nil, ErrAnimatedImage
+		}
+		// gif.Decode chỉ decode frame đầu tiên
+		img, err = gif.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, ErrUnsupportedFormat
+		}
+	case "png":
+		if isAnimatedPNG(data) && !limits.AllowAnimated {
+			return nil, ErrAnimatedImage
+		}
+		fallthrough
+	default:
+		img, _, err = image.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, ErrUnsupportedFormat
+		}
+	}
+
+	// 3. Chuyển sang RGBA trên nền trắng (JPEG không có alpha) và xoay theo EXIF orientation
+	canvas := image.NewRGBA(image.Rect(0, 0, img.Bounds().Dx(), img.Bounds().Dy()))
+	draw.Draw(canvas, canvas.Bounds(), &image.Uniform{C: color.White}, image.Point{}, draw.Src)
+	draw.Draw(canvas, canvas.Bounds(), img, img.Bounds().Min, draw.Over)
+
+	if format == "jpeg" {
+		canvas = applyOrientation(canvas, readJPEGOrientation(data))
+	}
+
+	// 4. Resize và encode từng variant
+	variants := make([]Variant, 0, len(specs))
+	for _, spec := range specs {
+		resized := fit(canvas, spec)
+
+		var buffer bytes.Buffer
+		if err := jpeg.Encode(&buffer, resized, &jpeg.Options{Quality: quality}); err != nil {
+			return nil, err
+		}
+
+		variants = append(variants, Variant{
+			Name:   spec.Name,
+			Width:  resized.Bounds().Dx(),
+			Height: resized.Bounds().Dy(),
+			Data:   buffer.Bytes(),
+		})
+	}
+
+	return variants, nil
+}
+
+// fit crop giữa ảnh theo tỉ lệ của spec rồi resize, Height = 0 thì chỉ thu nhỏ theo chiều rộng
+func fit(src *image.RGBA, spec Spec) *image.RGBA {
+	srcWidth, srcHeight := src.Bounds().Dx(), src.Bounds().Dy()
+
+	if spec.Height == 0 {
+		if srcWidth <= spec.Width {
+			return src
+		}
+		height := max(1, srcHeight*spec.Width/srcWidth)
+		return resize(src, src.Bounds(), spec.Width, height)
+	}
+
+	// Crop vùng giữa có cùng tỉ lệ với spec
+	crop := src.Bounds()
+	if srcWidth*spec.Height > srcHeight*spec.Width {
+		cropWidth := srcHeight * spec.Width / spec.Height
+		crop.Min.X += (srcWidth - cropWidth) / 2
+		crop.Max.X = crop.Min.X + cropWidth
+	} else {
+		cropHeight := srcWidth * spec.Height / spec.Width
+		crop.Min.Y += (srcHeight - cropHeight) / 2
+		crop.Max.Y = crop.Min.Y + cropHeight
+	}
+
+	return resize(src, crop, spec.Width, spec.Height)
+}
+
+// isAnimatedPNG tìm chunk acTL (APNG) trước dữ liệu ảnh IDAT
+func isAnimatedPNG(data []byte) bool {
+	offset := 8 // Bỏ qua PNG signature
+	for offset+8 <= len(data) {
+		length := int(data[offset])<<24 | int(data[offset+1])<<16 | int(data[offset+2])<<8 | int(data[offset+3])
+		chunkType := string(data[offset+4 : offset+8])
+
+		switch chunkType {
+		case "acTL":
+			return true
+		case "IDAT":
+			return false
+		}
+
+		offset += 12 + length // length + type + data + crc
+	}
+	return false
+}
+
+// countGIFFrames duyệt các block của file GIF (bỏ qua dữ liệu LZW) để đếm số frame,
+// từng frame cũng phải nằm trong giới hạn kích thước
+func countGIFFrames(data []byte, limits Limits) (int, error) {
+	// Header (6) + logical screen descriptor (7)
+	if len(data) < 13 {
+		return 0, ErrUnsupportedFormat
+	}
+	offset := 13
+	if data[10]&0x80 != 0 {
+		offset += 3 << (data[10]&0x07 + 1) // Global color table
+	}
+
+	// skipSubBlocks bỏ qua chuỗi sub-block (size + data) kết thúc bằng block size 0
+	skipSubBlocks := func() bool {
+		for offset < len(data) {
+			size := int(data[offset])
+			offset++
+			if size == 0 {
+				return true
+			}
+			offset += size
+		}
+		return false
+	}
+
+	frames := 0
+	for offset < len(data) {
+		switch data[offset] {
+		case 0x21: // Extension: label + sub-blocks
+			offset += 2
+			if !skipSubBlocks() {
+				return 0, ErrUnsupportedFormat
+			}
+		case 0x2C: // Image descriptor
+			if offset+10 > len(data) {
+				return 0, ErrUnsupportedFormat
+			}
+			width := int(data[offset+5]) | int(data[offset+6])<<8
+			height := int(data[offset+7]) | int(data[offset+8])<<8
+			if width > limits.MaxDimension || height > limits.MaxDimension || width*height > limits.MaxPixels {
+				return 0, ErrImageTooLarge
+			}
+
+			flags := data[offset+9]
+			offset += 10
+			if flags&0x80 != 0 {
+				offset += 3 << (flags&0x07 + 1) // Local color table
+			}
+			offset++ // LZW minimum code size
+			if !skipSubBlocks() {
+				return 0, ErrUnsupportedFormat
+			}
+			frames++
+		case 0x3B: // Trailer
+			return frames, nil
+		default:
+			return 0, ErrUnsupportedFormat
+		}
+	}
+
+	// Thiếu trailer: gif.Decode vẫn đọc được nếu đã có frame
+	if frames == 0 {
+		return 0, ErrUnsupportedFormat
+	}
+	return frames, nil
+}
@@ -0,0 +1,92 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/gif"
+	"testing"
+)
+
+var testLimits = Limits{MaxPixels: 1_000_000, MaxDimension: 2000}
+
+func encodeGIF(t *testing.T, frames int, width, height int) []byte {
+	t.Helper()
+
+	palette := color.Palette{color.Black, color.White}
+	animation := &gif.GIF{}
+	for i := 0; i < frames; i++ {
+		animation.Image = append(animation.Image, image.NewPaletted(image.Rect(0, 0, width, height), palette))
+		animation.Delay = append(animation.Delay, 10)
+	}
+
+	var buffer bytes.Buffer
+	if err := gif.EncodeAll(&buffer, animation); err != nil {
+		t.Fatal(err)
+	}
+	return buffer.Bytes()
+}
+
+func TestCountGIFFrames(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		limits  Limits
+		want    int
+		wantErr error
+	}{
+		{"single frame", encodeGIF(t, 1, 10, 10), testLimits, 1, nil},
+		{"animated", encodeGIF(t, 3, 10, 10), testLimits, 3, nil},
+		{"many frames", encodeGIF(t, 500, 4, 4), testLimits, 500, nil},
+		{"frame too large", encodeGIF(t, 1, 100, 100), Limits{MaxPixels: 5000, MaxDimension: 2000}, 0, ErrImageTooLarge},
+		{"too short", []byte("GIF89a"), testLimits, 0, ErrUnsupportedFormat},
+		{"truncated image data", encodeGIF(t, 1, 10, 10)[:30], testLimits, 0, ErrUnsupportedFormat},
+		{"unknown block", append(encodeGIF(t, 1, 10, 10)[:13], 0x99), testLimits, 0, ErrUnsupportedFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := countGIFFrames(tt.data, tt.limits)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("frames = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProcessGIF(t *testing.T) {
+	specs := []Spec{{Name: "8", Width: 8, Height: 8}}
+
+	if _, err := Process(encodeGIF(t, 3, 20, 20), testLimits, specs, 80); !errors.Is(err, ErrAnimatedImage) {
+		t.Errorf("animated GIF: err = %v, want ErrAnimatedImage", err)
+	}
+
+	allowAnimated := testLimits
+	allowAnimated.AllowAnimated = true
+	variants, err := Process(encodeGIF(t, 3, 20, 20), allowAnimated, specs, 80)
+	if err != nil {
+		t.Fatalf("animated GIF allowed: %v", err)
+	}
+	if len(variants) != 1 || variants[0].Width != 8 || variants[0].Height != 8 {
+		t.Errorf("variants = %+v", variants)
+	}
+
+	if _, err := Process(encodeGIF(t, 1, 20, 20), testLimits, specs, 80); err != nil {
+		t.Errorf("static GIF: %v", err)
+	}
+}
+
+func TestProcessRejectsOversizedImage(t *testing.T) {
+	limits := Limits{MaxPixels: 100, MaxDimension: 2000}
+	if _, err := Process(encodeGIF(t, 1, 20, 20), limits, nil, 80); !errors.Is(err, ErrImageTooLarge) {
+		t.Errorf("err = %v, want ErrImageTooLarge", err)
+	}
+
+	if _, err := Process([]byte("not an image"), testLimits, nil, 80); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("err = %v, want ErrUnsupportedFormat", err)
+	}
+}
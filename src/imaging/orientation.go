@@ -0,0 +1,123 @@
+package imaging
+
+import (
+	"encoding/binary"
+	"image"
+)
+
+// readJPEGOrientation đọc tag Orientation (0x0112) trong EXIF của JPEG, trả về 1 nếu không có
+func readJPEGOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+
+	offset := 2
+	for offset+4 <= len(data) {
+		if data[offset] != 0xFF {
+			return 1
+		}
+		marker := data[offset+1]
+		length := int(binary.BigEndian.Uint16(data[offset+2:]))
+
+		// SOS: phần còn lại là dữ liệu ảnh
+		if marker == 0xDA {
+			return 1
+		}
+
+		segmentStart := offset + 4
+		segmentEnd := offset + 2 + length
+		if segmentEnd > len(data) {
+			return 1
+		}
+
+		// APP1 chứa "Exif\0\0" + TIFF header
+		if marker == 0xE1 && segmentEnd-segmentStart > 6 && string(data[segmentStart:segmentStart+6]) == "Exif\x00\x00" {
+			return parseTIFFOrientation(data[segmentStart+6 : segmentEnd])
+		}
+
+		offset = segmentEnd
+	}
+
+	return 1
+}
+
+func parseTIFFOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	ifdOffset := int(order.Uint32(tiff[4:]))
+	if ifdOffset+2 > len(tiff) {
+		return 1
+	}
+
+	entries := int(order.Uint16(tiff[ifdOffset:]))
+	for i := 0; i < entries; i++ {
+		entry := ifdOffset + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			orientation := int(order.Uint16(tiff[entry+8:]))
+			if orientation >= 1 && orientation <= 8 {
+				return orientation
+			}
+			return 1
+		}
+	}
+
+	return 1
+}
+
+// applyOrientation xoay/lật ảnh để hiển thị đúng chiều như camera đã ghi trong EXIF
+func applyOrientation(src *image.RGBA, orientation int) *image.RGBA {
+	if orientation <= 1 || orientation > 8 {
+		return src
+	}
+
+	width, height := src.Bounds().Dx(), src.Bounds().Dy()
+	dstWidth, dstHeight := width, height
+	if orientation >= 5 {
+		dstWidth, dstHeight = height, width
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2: // lật ngang
+				dx, dy = width-1-x, y
+			case 3: // xoay 180
+				dx, dy = width-1-x, height-1-y
+			case 4: // lật dọc
+				dx, dy = x, height-1-y
+			case 5: // transpose
+				dx, dy = y, x
+			case 6: // xoay 90 theo chiều kim đồng hồ
+				dx, dy = height-1-y, x
+			case 7: // transverse
+				dx, dy = height-1-y, width-1-x
+			case 8: // xoay 270 theo chiều kim đồng hồ
+				dx, dy = y, width-1-x
+			}
+
+			srcPixel := src.PixOffset(x, y)
+			dstPixel := dst.PixOffset(dx, dy)
+			copy(dst.Pix[dstPixel:dstPixel+4], src.Pix[srcPixel:srcPixel+4])
+		}
+	}
+
+	return dst
+}
@@ -0,0 +1,98 @@
+package imaging
+
+import (
+	"image"
+	"math"
+)
+
+type weight struct {
+	index  int
+	weight float32
+}
+
+// resize resample vùng rect của src sang width x height bằng bộ lọc tam giác (bilinear),
+// bán kính bộ lọc được nới theo tỉ lệ thu nhỏ nên chất lượng tốt cả khi thu nhỏ nhiều lần
+func resize(src *image.RGBA, rect image.Rectangle, width, height int) *image.RGBA {
+	srcWidth, srcHeight := rect.Dx(), rect.Dy()
+	xWeights := computeWeights(srcWidth, width)
+	yWeights := computeWeights(srcHeight, height)
+
+	// Pass 1: resize theo chiều ngang, giữ giá trị float để không mất độ chính xác giữa hai pass
+	tmp := make([]float32, width*srcHeight*3)
+	for y := 0; y < srcHeight; y++ {
+		rowOffset := src.PixOffset(rect.Min.X, rect.Min.Y+y)
+		for x, weights := range xWeights {
+			var r, g, b float32
+			for _, w := range weights {
+				pixel := rowOffset + w.index*4
+				r += float32(src.Pix[pixel]) * w.weight
+				g += float32(src.Pix[pixel+1]) * w.weight
+				b += float32(src.Pix[pixel+2]) * w.weight
+			}
+			offset := (y*width + x) * 3
+			tmp[offset], tmp[offset+1], tmp[offset+2] = r, g, b
+		}
+	}
+
+	// Pass 2: resize theo chiều dọc
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y, weights := range yWeights {
+		for x := 0; x < width; x++ {
+			var r, g, b float32
+			for _, w := range weights {
+				offset := (w.index*width + x) * 3
+				r += tmp[offset] * w.weight
+				g += tmp[offset+1] * w.weight
+				b += tmp[offset+2] * w.weight
+			}
+			pixel := dst.PixOffset(x, y)
+			dst.Pix[pixel] = clampUint8(r)
+			dst.Pix[pixel+1] = clampUint8(g)
+			dst.Pix[pixel+2] = clampUint8(b)
+			dst.Pix[pixel+3] = 255
+		}
+	}
+
+	return dst
+}
+
+func computeWeights(srcSize, dstSize int) [][]weight {
+	scale := float64(srcSize) / float64(dstSize)
+	support := math.Max(1, scale)
+
+	weights := make([][]weight, dstSize)
+	for i := range weights {
+		center := (float64(i) + 0.5) * scale
+		start := int(math.Floor(center - support))
+		end := int(math.Ceil(center + support))
+
+		var total float32
+		for j := start; j < end; j++ {
+			distance := math.Abs((float64(j) + 0.5 - center) / support)
+			if distance >= 1 {
+				continue
+			}
+
+			index := min(max(j, 0), srcSize-1)
+			w := float32(1 - distance)
+			weights[i] = append(weights[i], weight{index: index, weight: w})
+			total += w
+		}
+
+		for k := range weights[i] {
+			weights[i][k].weight /= total
+		}
+	}
+
+	return weights
+}
+
+func clampUint8(value float32) uint8 {
+	if value <= 0 {
+		return 0
+	}
+	if value >= 255 {
+		return 255
+	}
+	return uint8(value + 0.5)
+}
@@ -0,0 +1,451 @@
+package markdown
+
+import (
+	"html"
+	"strings"
+)
+
+// inlineState ghi nhớ các delimiter đã tìm không thấy điểm đóng trong chuỗi đang render,
+// tránh quét lại nhiều lần (input kiểu "*a *a *a ..." sẽ thành O(n^2))
+type inlineState struct {
+	noCloser map[string]bool
+	brackets map[int]int
+}
+
+// maxLinkParens giới hạn số ngoặc đơn lồng nhau trong destination (giống cmark),
+// để "[a](" lặp lại không khiến mỗi link quét tới cuối chuỗi
+const maxLinkParens = 32
+
+func (r *renderer) renderInline(b *strings.Builder, s string) {
+	state := &inlineState{noCloser: make(map[string]bool)}
+	i := 0
+	for i < len(s) {
+		c := s[i]
+		switch c {
+		case '\\':
+			if i+1 < len(s) && isASCIIPunct(s[i+1]) {
+				b.WriteString(html.EscapeString(s[i+1 : i+2]))
+				i += 2
+				continue
+			}
+			if i+1 < len(s) && s[i+1] == '\n' {
+				b.WriteString("<br>\n")
+				i += 2
+				continue
+			}
+
+		case '`':
+			if next, ok := r.renderCodeSpan(b, s, i); ok {
+				i = next
+				continue
+			}
+			// Chuỗi backtick không có điểm đóng được giữ nguyên
+			run := countPrefix(s[i:], '`')
+			b.WriteString(s[i : i+run])
+			i += run
+			continue
+
+		case '*', '_', '~':
+			if next, ok := r.renderEmphasis(b, s, i, state); ok {
+				i = next
+				continue
+			}
+			run := countPrefix(s[i:], c)
+			b.WriteString(s[i : i+run])
+			i += run
+			continue
+
+		case '!':
+			if i+1 < len(s) && s[i+1] == '[' {
+				if next, ok := r.renderImage(b, s, i, state); ok {
+					i = next
+					continue
+				}
+			}
+
+		case '[':
+			if next, ok := r.renderLink(b, s, i, state); ok {
+				i = next
+				continue
+			}
+
+		case '<':
+			if next, ok := r.renderAutolink(b, s, i); ok {
+				i = next
+				continue
+			}
+
+		case '\n':
+			// Hai dấu cách cuối dòng là hard line break
+			if i >= 2 && s[i-1] == ' ' && s[i-2] == ' ' {
+				b.WriteString("<br>\n")
+			} else {
+				b.WriteString("\n")
+			}
+			i++
+			continue
+		}
+
+		b.WriteString(html.EscapeString(s[i : i+1]))
+		i++
+	}
+}
+
+func (r *renderer) renderCodeSpan(b *strings.Builder, s string, start int) (int, bool) {
+	end, contentStart, contentEnd, ok := findCodeSpan(s, start)
+	if !ok {
+		return 0, false
+	}
+
+	code := strings.ReplaceAll(s[contentStart:contentEnd], "\n", " ")
+	if len(code) >= 2 && code[0] == ' ' && code[len(code)-1] == ' ' && strings.Trim(code, " ") != "" {
+		code = code[1 : len(code)-1]
+	}
+
+	b.WriteString("<code>")
+	b.WriteString(html.EscapeString(code))
+	b.WriteString("</code>")
+	return end, true
+}
+
+// findCodeSpan tìm chuỗi backtick đóng có cùng độ dài với chuỗi mở tại start
+func findCodeSpan(s string, start int) (end, contentStart, contentEnd int, ok bool) {
+	run := countPrefix(s[start:], '`')
+	i := start + run
+	for i < len(s) {
+		j := strings.IndexByte(s[i:], '`')
+		if j < 0 {
+			return 0, 0, 0, false
+		}
+		j += i
+		closing := countPrefix(s[j:], '`')
+		if closing == run {
+			return j + closing, start + run, j, true
+		}
+		i = j + closing
+	}
+	return 0, 0, 0, false
+}
+
+func (r *renderer) renderEmphasis(b *strings.Builder, s string, start int, state *inlineState) (int, bool) {
+	c := s[start]
+	run := countPrefix(s[start:], c)
+
+	// Delimiter mở phải đứng ngay trước ký tự không phải khoảng trắng
+	if start+run >= len(s) || isSpace(s[start+run]) {
+		return 0, false
+	}
+	// "_" nằm giữa từ (snake_case) không phải emphasis
+	if c == '_' && start > 0 && isAlphanumeric(s[start-1]) {
+		return 0, false
+	}
+
+	type emphasis struct {
+		delimiter string
+		tag       string
+	}
+	var candidates []emphasis
+	switch {
+	case c == '~':
+		if run >= 2 {
+			candidates = []emphasis{{"~~", "del"}}
+		}
+	case run >= 2:
+		candidates = []emphasis{{string([]byte{c, c}), "strong"}, {string(c), "em"}}
+	default:
+		candidates = []emphasis{{string(c), "em"}}
+	}
+
+	for _, candidate := range candidates {
+		open := start + run - len(candidate.delimiter)
+		if candidate.tag == "em" && run >= 2 {
+			// "**text*": dùng delimiter cuối cùng, phần thừa ghi nguyên văn
+			open = start + run - 1
+		}
+
+		closing := r.findCloser(s, start+run, candidate.delimiter, state)
+		if closing < 0 {
+			continue
+		}
+
+		b.WriteString(html.EscapeString(s[start:open]))
+		b.WriteString("<" + candidate.tag + ">")
+		r.renderInline(b, s[start+run:closing])
+		b.WriteString("</" + candidate.tag + ">")
+		return closing + len(candidate.delimiter), true
+	}
+
+	return 0, false
+}
+
+// findCloser tìm delimiter đóng, bỏ qua ký tự được escape và code span.
+// Khi tìm delimiter đơn, các chuỗi delimiter kép ("**") được coi là một khối để không cắt ngang strong lồng bên trong.
+func (r *renderer) findCloser(s string, from int, delimiter string, state *inlineState) int {
+	if state.noCloser[delimiter] {
+		return -1
+	}
+
+	c := delimiter[0]
+	i := from
+	for i < len(s) {
+		switch s[i] {
+		case '\\':
+			i += 2
+			continue
+		case '`':
+			if end, _, _, ok := findCodeSpan(s, i); ok {
+				i = end
+				continue
+			}
+			i += countPrefix(s[i:], '`')
+			continue
+		case c:
+			run := countPrefix(s[i:], c)
+			if run >= len(delimiter) && (len(delimiter) > 1 || run == 1) && i > from && !isSpace(s[i-1]) {
+				// "_" đóng không được nằm giữa từ
+				if c != '_' || i+len(delimiter) >= len(s) || !isAlphanumeric(s[i+len(delimiter)]) {
+					return i
+				}
+			}
+			i += run
+			continue
+		}
+		i++
+	}
+
+	state.noCloser[delimiter] = true
+	return -1
+}
+
+// matchBrackets ghép cặp '[' với ']' tương ứng (bỏ qua ký tự escape và code span),
+// tính một lần cho cả chuỗi thay vì quét lại từ mỗi '['
+func matchBrackets(s string) map[int]int {
+	matches := make(map[int]int)
+	var stack []int
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '`':
+			if codeEnd, _, _, found := findCodeSpan(s, i); found {
+				i = codeEnd - 1
+			} else {
+				i += countPrefix(s[i:], '`') - 1
+			}
+		case '[':
+			stack = append(stack, i)
+		case ']':
+			if len(stack) > 0 {
+				matches[stack[len(stack)-1]] = i
+				stack = stack[:len(stack)-1]
+			}
+		}
+	}
+	return matches
+}
+
+// parseLinkTail phân tích "[label](destination "title")" bắt đầu tại start (vị trí '[')
+func parseLinkTail(s string, start int, state *inlineState) (label, destination, title string, end int, ok bool) {
+	// 1. Tìm ']' khớp, cho phép ngoặc lồng nhau
+	if state.brackets == nil {
+		state.brackets = matchBrackets(s)
+	}
+	closeBracket, found := state.brackets[start]
+	if !found || closeBracket+1 >= len(s) || s[closeBracket+1] != '(' {
+		return "", "", "", 0, false
+	}
+	label = s[start+1 : closeBracket]
+
+	// 2. Destination: <...> hoặc chuỗi không có khoảng trắng, ngoặc đơn phải cân bằng
+	i := skipSpaces(s, closeBracket+2)
+	if i < len(s) && s[i] == '<' {
+		if state.noCloser[">"] {
+			return "", "", "", 0, false
+		}
+		closeAngle := strings.IndexAny(s[i+1:], ">\n")
+		if closeAngle < 0 {
+			state.noCloser[">"] = true
+			return "", "", "", 0, false
+		}
+		if s[i+1+closeAngle] != '>' {
+			return "", "", "", 0, false
+		}
+		destination = s[i+1 : i+1+closeAngle]
+		i += closeAngle + 2
+	} else {
+		destStart := i
+		parens := 0
+	destination:
+		for i < len(s) {
+			switch s[i] {
+			case '\\':
+				i++
+			case '(':
+				parens++
+				if parens > maxLinkParens {
+					return "", "", "", 0, false
+				}
+			case ')':
+				if parens == 0 {
+					break destination
+				}
+				parens--
+			case ' ', '\n':
+				break destination
+			}
+			i++
+		}
+		if i > len(s) {
+			i = len(s)
+		}
+		destination = s[destStart:i]
+	}
+
+	// 3. Title tùy chọn
+	i = skipSpaces(s, i)
+	if i < len(s) && (s[i] == '"' || s[i] == '\'') {
+		quote := s[i]
+		if state.noCloser[string(quote)+"title"] {
+			return "", "", "", 0, false
+		}
+		closeQuote := strings.IndexByte(s[i+1:], quote)
+		if closeQuote < 0 {
+			state.noCloser[string(quote)+"title"] = true
+			return "", "", "", 0, false
+		}
+		title = s[i+1 : i+1+closeQuote]
+		i = skipSpaces(s, i+closeQuote+2)
+	}
+
+	if i >= len(s) || s[i] != ')' {
+		return "", "", "", 0, false
+	}
+	return label, unescapeBackslashes(destination), title, i + 1, true
+}
+
+func (r *renderer) renderLink(b *strings.Builder, s string, start int, state *inlineState) (int, bool) {
+	label, destination, title, end, ok := parseLinkTail(s, start, state)
+	if !ok {
+		return 0, false
+	}
+
+	href, safe := safeURL(destination)
+	if !safe {
+		// URL nguy hiểm (javascript:, data:...) chỉ hiển thị phần text
+		r.renderInline(b, label)
+		return end, true
+	}
+
+	b.WriteString(`<a href="` + html.EscapeString(href) + `"`)
+	if title != "" {
+		b.WriteString(` title="` + html.EscapeString(title) + `"`)
+	}
+	b.WriteString(` rel="nofollow noopener noreferrer">`)
+	r.renderInline(b, label)
+	b.WriteString("</a>")
+	return end, true
+}
+
+func (r *renderer) renderImage(b *strings.Builder, s string, start int, state *inlineState) (int, bool) {
+	label, destination, title, end, ok := parseLinkTail(s, start+1, state)
+	if !ok {
+		return 0, false
+	}
+
+	alt := unescapeBackslashes(label)
+	src, safe := safeURL(destination)
+	if !safe || r.opts.AllowImage == nil || !r.opts.AllowImage(src) {
+		// Ảnh không nằm trên storage của hệ thống thì chỉ hiển thị alt text
+		b.WriteString(html.EscapeString(alt))
+		return end, true
+	}
+
+	b.WriteString(`<img src="` + html.EscapeString(src) + `" alt="` + html.EscapeString(alt) + `"`)
+	if title != "" {
+		b.WriteString(` title="` + html.EscapeString(title) + `"`)
+	}
+	b.WriteString(` loading="lazy">`)
+	return end, true
+}
+
+// renderAutolink xử lý <https://...> và <mailto:...>
+func (r *renderer) renderAutolink(b *strings.Builder, s string, start int) (int, bool) {
+	closeAngle := strings.IndexAny(s[start+1:], "> \n<")
+	if closeAngle < 0 || s[start+1+closeAngle] != '>' {
+		return 0, false
+	}
+
+	target := s[start+1 : start+1+closeAngle]
+	lower := strings.ToLower(target)
+	if !strings.HasPrefix(lower, "http://") && !strings.HasPrefix(lower, "https://") && !strings.HasPrefix(lower, "mailto:") {
+		return 0, false
+	}
+
+	href, safe := safeURL(target)
+	if !safe {
+		return 0, false
+	}
+
+	b.WriteString(`<a href="` + html.EscapeString(href) + `" rel="nofollow noopener noreferrer">`)
+	b.WriteString(html.EscapeString(target))
+	b.WriteString("</a>")
+	return start + closeAngle + 2, true
+}
+
+// safeURL chỉ chấp nhận http, https, mailto và URL tương đối
+func safeURL(raw string) (string, bool) {
+	// Trình duyệt bỏ qua ký tự điều khiển/khoảng trắng khi đọc scheme ("java\tscript:")
+	cleaned := strings.Map(func(r rune) rune {
+		if r <= ' ' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, raw)
+	if cleaned == "" {
+		return "", false
+	}
+
+	lower := strings.ToLower(cleaned)
+	if idx := strings.IndexAny(lower, ":/?#"); idx >= 0 && lower[idx] == ':' {
+		switch lower[:idx] {
+		case "http", "https", "mailto":
+		default:
+			return "", false
+		}
+	}
+
+	return cleaned, true
+}
+
+func unescapeBackslashes(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) && isASCIIPunct(s[i+1]) {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+func skipSpaces(s string, i int) int {
+	for i < len(s) && (s[i] == ' ' || s[i] == '\n') {
+		i++
+	}
+	return i
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\n'
+}
+
+func isAlphanumeric(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c >= 0x80
+}
+
+func isASCIIPunct(c byte) bool {
+	return strings.IndexByte("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", c) >= 0
+}
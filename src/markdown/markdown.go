@@ -0,0 +1,442 @@
+package markdown
+
+import (
+	"html"
+	"strconv"
+	"strings"
+)
+
+// Options cấu hình chính sách render
+type Options struct {
+	// AllowImage quyết định URL ảnh nào được nhúng, nil nghĩa là không cho nhúng ảnh (chỉ hiển thị alt text)
+	AllowImage func(url string) bool
+}
+
+// Render chuyển Markdown sang HTML an toàn.
+// Renderer không cho HTML thô đi qua: mọi ký tự đặc biệt trong nội dung đều được escape,
+// output chỉ gồm các thẻ trong allowlist (p, h1-h6, strong, em, del, code, pre, blockquote,
+// ul, ol, li, a, img, br, hr) và link chỉ chấp nhận http, https, mailto hoặc đường dẫn tương đối.
+func Render(source string, opts Options) string {
+	r := &renderer{opts: opts}
+
+	var b strings.Builder
+	r.renderBlocks(&b, splitLines(source), false)
+	return b.String()
+}
+
+// maxNestingDepth giới hạn số tầng list/blockquote lồng nhau, sâu hơn thì phần còn lại là text thường.
+// Mỗi tầng quét lại nội dung của tầng trong nên không giới hạn thì "- - - - ..." render mất O(n^2).
+const maxNestingDepth = 16
+
+type renderer struct {
+	opts  Options
+	depth int // Số tầng list/blockquote đang mở
+}
+
+func splitLines(source string) []string {
+	source = strings.ReplaceAll(source, "\r\n", "\n")
+	source = strings.ReplaceAll(source, "\r", "\n")
+	source = strings.ReplaceAll(source, "\x00", "�")
+	source = strings.ReplaceAll(source, "\t", "    ")
+	return strings.Split(source, "\n")
+}
+
+// renderBlocks render danh sách dòng thành các block, tight = true khi nằm trong list không có dòng trống
+// (paragraph không bọc <p>)
+func (r *renderer) renderBlocks(b *strings.Builder, lines []string, tight bool) {
+	i := 0
+	for i < len(lines) {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		indent := leadingSpaces(line)
+
+		switch {
+		case trimmed == "":
+			i++
+		case indent >= 4:
+			i = r.renderIndentedCode(b, lines, i)
+		case isFence(trimmed):
+			i = r.renderFencedCode(b, lines, i)
+		case headingLevel(trimmed) > 0:
+			r.renderHeading(b, trimmed)
+			i++
+		case isThematicBreak(trimmed):
+			b.WriteString("<hr>\n")
+			i++
+		case strings.HasPrefix(trimmed, ">") && r.depth < maxNestingDepth:
+			i = r.renderBlockquote(b, lines, i)
+		default:
+			if _, ok := parseListMarker(line); ok && r.depth < maxNestingDepth {
+				i = r.renderList(b, lines, i)
+				continue
+			}
+			i = r.renderParagraph(b, lines, i, tight)
+		}
+	}
+}
+
+func (r *renderer) renderIndentedCode(b *strings.Builder, lines []string, start int) int {
+	i := start
+	var code []string
+	for i < len(lines) && (leadingSpaces(lines[i]) >= 4 || strings.TrimSpace(lines[i]) == "") {
+		code = append(code, stripIndent(lines[i], 4))
+		i++
+	}
+
+	// Bỏ các dòng trống ở cuối
+	for len(code) > 0 && strings.TrimSpace(code[len(code)-1]) == "" {
+		code = code[:len(code)-1]
+	}
+
+	b.WriteString("<pre><code>")
+	b.WriteString(html.EscapeString(strings.Join(code, "\n")))
+	b.WriteString("\n</code></pre>\n")
+	return i
+}
+
+func (r *renderer) renderFencedCode(b *strings.Builder, lines []string, start int) int {
+	opening := lines[start]
+	indent := leadingSpaces(opening)
+	trimmed := strings.TrimSpace(opening)
+	fenceChar := trimmed[0]
+	fenceLength := countPrefix(trimmed, fenceChar)
+	language := sanitizeLanguage(strings.Fields(trimmed[fenceLength:]))
+
+	i := start + 1
+	var code []string
+	for i < len(lines) {
+		candidate := strings.TrimSpace(lines[i])
+		if countPrefix(candidate, fenceChar) >= fenceLength && strings.Trim(candidate, string(fenceChar)) == "" {
+			i++
+			break
+		}
+		code = append(code, stripIndent(lines[i], indent))
+		i++
+	}
+
+	if language != "" {
+		b.WriteString(`<pre><code class="language-` + language + `">`)
+	} else {
+		b.WriteString("<pre><code>")
+	}
+	if len(code) > 0 {
+		b.WriteString(html.EscapeString(strings.Join(code, "\n")))
+		b.WriteString("\n")
+	}
+	b.WriteString("</code></pre>\n")
+	return i
+}
+
+func (r *renderer) renderHeading(b *strings.Builder, trimmed string) {
+	level := headingLevel(trimmed)
+	text := strings.TrimSpace(trimmed[level:])
+
+	// Bỏ chuỗi # đóng ở cuối (vd: "## Title ##")
+	if closing := strings.TrimRight(text, "#"); closing != text && (closing == "" || strings.HasSuffix(closing, " ")) {
+		text = strings.TrimSpace(closing)
+	}
+
+	tag := "h" + strconv.Itoa(level)
+	b.WriteString("<" + tag + ">")
+	r.renderInline(b, text)
+	b.WriteString("</" + tag + ">\n")
+}
+
+func (r *renderer) renderBlockquote(b *strings.Builder, lines []string, start int) int {
+	i := start
+	var inner []string
+	for i < len(lines) {
+		trimmed := strings.TrimSpace(lines[i])
+		if !strings.HasPrefix(trimmed, ">") {
+			break
+		}
+		content := trimmed[1:]
+		content = strings.TrimPrefix(content, " ")
+		inner = append(inner, content)
+		i++
+	}
+
+	b.WriteString("<blockquote>\n")
+	r.depth++
+	r.renderBlocks(b, inner, false)
+	r.depth--
+	b.WriteString("</blockquote>\n")
+	return i
+}
+
+func (r *renderer) renderParagraph(b *strings.Builder, lines []string, start int, tight bool) int {
+	i := start
+	var text []string
+	for i < len(lines) {
+		line := lines[i]
+		if strings.TrimSpace(line) == "" || (i > start && interruptsParagraph(line)) {
+			break
+		}
+		text = append(text, strings.TrimLeft(line, " "))
+		i++
+	}
+
+	content := strings.TrimRight(strings.Join(text, "\n"), " ")
+	if tight {
+		r.renderInline(b, content)
+		b.WriteString("\n")
+		return i
+	}
+
+	b.WriteString("<p>")
+	r.renderInline(b, content)
+	b.WriteString("</p>\n")
+	return i
+}
+
+func (r *renderer) renderList(b *strings.Builder, lines []string, start int) int {
+	first, _ := parseListMarker(lines[start])
+
+	var items [][]string
+	var current []string
+	contentIndent := 0
+	loose := false
+	blank := false
+
+	i := start
+	for i < len(lines) {
+		line := lines[i]
+		if strings.TrimSpace(line) == "" {
+			if current != nil {
+				current = append(current, "")
+			}
+			blank = true
+			i++
+			continue
+		}
+
+		indent := leadingSpaces(line)
+
+		// Dòng thụt vào đủ sâu thuộc về item hiện tại (kể cả list lồng nhau)
+		if current != nil && indent >= contentIndent {
+			if blank && hasContent(current) {
+				loose = true
+			}
+			current = append(current, stripIndent(line, contentIndent))
+			blank = false
+			i++
+			continue
+		}
+
+		// Item mới cùng loại list
+		if marker, ok := parseListMarker(line); ok && marker.sameType(first) {
+			if current != nil {
+				if blank {
+					loose = true
+				}
+				items = append(items, current)
+			}
+			current = []string{marker.content}
+			contentIndent = marker.contentIndent
+			blank = false
+			i++
+			continue
+		}
+
+		// Lazy continuation của paragraph trong item
+		if !blank && current != nil && !interruptsParagraph(line) {
+			current = append(current, strings.TrimSpace(line))
+			i++
+			continue
+		}
+
+		break
+	}
+	if current != nil {
+		items = append(items, current)
+	}
+
+	if first.ordered {
+		if first.start != 1 {
+			b.WriteString(`<ol start="` + strconv.Itoa(first.start) + `">` + "\n")
+		} else {
+			b.WriteString("<ol>\n")
+		}
+	} else {
+		b.WriteString("<ul>\n")
+	}
+
+	r.depth++
+	for _, item := range items {
+		for len(item) > 0 && strings.TrimSpace(item[len(item)-1]) == "" {
+			item = item[:len(item)-1]
+		}
+		b.WriteString("<li>")
+		if loose {
+			b.WriteString("\n")
+		}
+		r.renderBlocks(b, item, !loose)
+		b.WriteString("</li>\n")
+	}
+	r.depth--
+
+	if first.ordered {
+		b.WriteString("</ol>\n")
+	} else {
+		b.WriteString("</ul>\n")
+	}
+	return i
+}
+
+type listMarker struct {
+	ordered       bool
+	delimiter     byte // '-', '*', '+' hoặc '.', ')'
+	start         int
+	contentIndent int
+	content       string
+}
+
+func (m listMarker) sameType(other listMarker) bool {
+	return m.ordered == other.ordered && m.delimiter == other.delimiter
+}
+
+func parseListMarker(line string) (listMarker, bool) {
+	indent := leadingSpaces(line)
+	if indent > 3 || isThematicBreak(strings.TrimSpace(line)) {
+		return listMarker{}, false
+	}
+
+	rest := line[indent:]
+	marker := listMarker{}
+	markerWidth := 0
+
+	switch {
+	case len(rest) > 0 && (rest[0] == '-' || rest[0] == '*' || rest[0] == '+'):
+		marker.delimiter = rest[0]
+		markerWidth = 1
+	default:
+		digits := 0
+		for digits < len(rest) && digits < 9 && rest[digits] >= '0' && rest[digits] <= '9' {
+			digits++
+		}
+		if digits == 0 || digits >= len(rest) || (rest[digits] != '.' && rest[digits] != ')') {
+			return listMarker{}, false
+		}
+		marker.ordered = true
+		marker.delimiter = rest[digits]
+		marker.start, _ = strconv.Atoi(rest[:digits])
+		markerWidth = digits + 1
+	}
+
+	after := rest[markerWidth:]
+	if after != "" && after[0] != ' ' {
+		return listMarker{}, false
+	}
+
+	spaces := leadingSpaces(after)
+	if spaces == 0 || spaces > 4 || strings.TrimSpace(after) == "" {
+		spaces = 1
+	}
+
+	marker.contentIndent = indent + markerWidth + spaces
+	if len(after) >= spaces {
+		marker.content = after[spaces:]
+	}
+	return marker, true
+}
+
+// interruptsParagraph kiểm tra dòng có bắt đầu block mới (kết thúc paragraph đang mở) không
+func interruptsParagraph(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	if leadingSpaces(line) >= 4 {
+		return false
+	}
+	if isFence(trimmed) || headingLevel(trimmed) > 0 || isThematicBreak(trimmed) || strings.HasPrefix(trimmed, ">") {
+		return true
+	}
+
+	// List có thứ tự chỉ ngắt paragraph khi bắt đầu từ 1 (tránh "2020. năm..." thành list)
+	marker, ok := parseListMarker(line)
+	return ok && strings.TrimSpace(marker.content) != "" && (!marker.ordered || marker.start == 1)
+}
+
+func isFence(trimmed string) bool {
+	if len(trimmed) < 3 || (trimmed[0] != '`' && trimmed[0] != '~') {
+		return false
+	}
+	length := countPrefix(trimmed, trimmed[0])
+	if length < 3 {
+		return false
+	}
+	// Info string của fence backtick không được chứa backtick
+	return trimmed[0] != '`' || !strings.Contains(trimmed[length:], "`")
+}
+
+func headingLevel(trimmed string) int {
+	level := countPrefix(trimmed, '#')
+	if level == 0 || level > 6 {
+		return 0
+	}
+	if len(trimmed) > level && trimmed[level] != ' ' {
+		return 0
+	}
+	return level
+}
+
+func isThematicBreak(trimmed string) bool {
+	if trimmed == "" || (trimmed[0] != '-' && trimmed[0] != '*' && trimmed[0] != '_') {
+		return false
+	}
+	count := 0
+	for i := 0; i < len(trimmed); i++ {
+		switch trimmed[i] {
+		case trimmed[0]:
+			count++
+		case ' ':
+		default:
+			return false
+		}
+	}
+	return count >= 3
+}
+
+func sanitizeLanguage(fields []string) string {
+	if len(fields) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	for _, c := range fields[0] {
+		if (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c == '-' || c == '_' || c == '+' {
+			b.WriteRune(c)
+		}
+	}
+	return b.String()
+}
+
+func hasContent(lines []string) bool {
+	for _, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func leadingSpaces(line string) int {
+	count := 0
+	for count < len(line) && line[count] == ' ' {
+		count++
+	}
+	return count
+}
+
+func stripIndent(line string, width int) string {
+	spaces := leadingSpaces(line)
+	if spaces > width {
+		spaces = width
+	}
+	return line[spaces:]
+}
+
+func countPrefix(s string, c byte) int {
+	count := 0
+	for count < len(s) && s[count] == c {
+		count++
+	}
+	return count
+}
@@ -0,0 +1,159 @@
+package markdown
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+var cdnImages = Options{AllowImage: func(url string) bool { return strings.HasPrefix(url, "https://cdn.example.com/") }}
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{"paragraph", "Xin chào", "<p>Xin chào</p>\n"},
+		{"heading with closing hashes", "## Bài 1 ##", "<h2>Bài 1</h2>\n"},
+		{"not a heading", "#hashtag", "<p>#hashtag</p>\n"},
+		{"inline styles", "**bold** *em* ~~del~~ `co<de>`", "<p><strong>bold</strong> <em>em</em> <del>del</del> <code>co&lt;de&gt;</code></p>\n"},
+		{"hard break", "a  \nb", "<p>a  <br>\nb</p>\n"},
+		{"thematic break", "- - -", "<hr>\n"},
+		{"blockquote", "> q\n> **b**", "<blockquote>\n<p>q\n<strong>b</strong></p>\n</blockquote>\n"},
+		{"nested list", "- a\n- b\n  - c", "<ul>\n<li>a\n</li>\n<li>b\n<ul>\n<li>c\n</li>\n</ul>\n</li>\n</ul>\n"},
+		{"ordered list start", "3. x\n4. y", "<ol start=\"3\">\n<li>x\n</li>\n<li>y\n</li>\n</ol>\n"},
+		{"year does not interrupt paragraph", "Năm\n2020. bắt đầu", "<p>Năm\n2020. bắt đầu</p>\n"},
+		{"fenced code", "```go\nif a < b {}\n```", "<pre><code class=\"language-go\">if a &lt; b {}\n</code></pre>\n"},
+		{"unclosed fence", "```\ncode", "<pre><code>code\n</code></pre>\n"},
+		{"indented code", "    <b>x</b>", "<pre><code>&lt;b&gt;x&lt;/b&gt;\n</code></pre>\n"},
+		{"link", "[Go](https://go.dev)", "<p><a href=\"https://go.dev\" rel=\"nofollow noopener noreferrer\">Go</a></p>\n"},
+		{"autolink", "<https://a.com>", "<p><a href=\"https://a.com\" rel=\"nofollow noopener noreferrer\">https://a.com</a></p>\n"},
+		{"allowed image", "![a](https://cdn.example.com/x.png)", "<p><img src=\"https://cdn.example.com/x.png\" alt=\"a\" loading=\"lazy\"></p>\n"},
+		{"image from other host", "![a](https://evil.com/x.png)", "<p>a</p>\n"},
+		{"escaped delimiter", `\*not em\*`, "<p>*not em*</p>\n"},
+		{"empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Render(tt.source, cdnImages); got != tt.want {
+				t.Errorf("Render(%q)\n got %q\nwant %q", tt.source, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderImagesDisabledByDefault(t *testing.T) {
+	if got := Render("![a](https://cdn.example.com/x.png)", Options{}); got != "<p>a</p>\n" {
+		t.Errorf("got %q", got)
+	}
+}
+
+// Nội dung do người dùng nhập không được tạo ra thẻ, thuộc tính hay URL nguy hiểm
+func TestRenderEscapesUnsafeInput(t *testing.T) {
+	inputs := []string{
+		"<script>alert(1)</script>",
+		"<img src=x onerror=alert(1)>",
+		"[x](javascript:alert(1))",
+		"[x](JaVaScRiPt:alert(1))",
+		"[x](vbscript:msgbox(1))",
+		"[x](data:text/html;base64,PHNjcmlwdD4=)",
+		"<javascript:alert(1)>",
+		"![a](javascript:alert(1))",
+		"[x](https://a.com/\"onmouseover=\"alert(1))",
+		"[x](https://a.com/ \"title\" onclick=\"alert(1)\")",
+		"```js\"><script>\nx\n```",
+		"**<iframe src=//evil.com>**",
+		"> <svg onload=alert(1)>",
+		"- [x](  javascript:alert(1)  )",
+		"\x00<script>",
+	}
+
+	for _, input := range inputs {
+		got := Render(input, cdnImages)
+		for _, tag := range htmlTag.FindAllStringSubmatch(got, -1) {
+			attrs, known := allowedTags[strings.ToLower(tag[1])]
+			if !known {
+				t.Errorf("Render(%q) = %q produced tag <%s>", input, got, tag[1])
+				continue
+			}
+			for _, attr := range htmlAttr.FindAllStringSubmatch(tag[2], -1) {
+				name := strings.ToLower(attr[1])
+				if !strings.Contains(attrs, " "+name+" ") {
+					t.Errorf("Render(%q) = %q produced attribute %s on <%s>", input, got, name, tag[1])
+				}
+				if name == "href" || name == "src" {
+					if _, safe := safeURL(html.UnescapeString(attr[2])); !safe {
+						t.Errorf("Render(%q) = %q produced unsafe URL %q", input, got, attr[2])
+					}
+				}
+			}
+			// Mọi ký tự trong thẻ phải thuộc về một thuộc tính có giá trị trong dấu nháy kép
+			if rest := strings.TrimSpace(htmlAttr.ReplaceAllString(tag[2], "")); rest != "" {
+				t.Errorf("Render(%q) = %q produced unquoted attribute text %q", input, got, rest)
+			}
+		}
+	}
+}
+
+var (
+	htmlTag  = regexp.MustCompile(`</?([a-zA-Z][a-zA-Z0-9]*)([^>]*)>`)
+	htmlAttr = regexp.MustCompile(`\s([a-zA-Z-]+)="([^"]*)"`)
+
+	// Thẻ renderer được phép sinh ra và thuộc tính của từng thẻ
+	allowedTags = map[string]string{
+		"p": " ", "h1": " ", "h2": " ", "h3": " ", "h4": " ", "h5": " ", "h6": " ",
+		"strong": " ", "em": " ", "del": " ", "br": " ", "hr": " ", "blockquote": " ",
+		"ul": " ", "ol": " start ", "li": " ", "pre": " ", "code": " class ",
+		"a":   " href title rel ",
+		"img": " src alt title loading ",
+	}
+)
+
+// Input lồng nhau hoặc delimiter không đóng phải render trong thời gian tuyến tính
+func TestRenderPathologicalInput(t *testing.T) {
+	inputs := map[string]string{
+		"unclosed emphasis": strings.Repeat("*a ", 50000),
+		"unclosed strong":   strings.Repeat("**a ", 50000),
+		"unclosed brackets": strings.Repeat("[", 50000) + "a",
+		"unclosed links":    strings.Repeat("[a](", 30000),
+		"unclosed angles":   strings.Repeat("[a](<", 30000),
+		"unclosed titles":   strings.Repeat("[a](x \"", 30000) + strings.Repeat("[a](x '", 30000),
+		"backticks":         strings.Repeat("`a ``", 30000),
+		"nested quotes":     strings.Repeat(">", 2000) + " a",
+		"nested lists":      nestedList(500),
+		"nested markers":    strings.Repeat("- ", 40000) + "x",
+		"nested mixed":      strings.Repeat("> 1. ", 20000) + "x",
+		"long line":         strings.Repeat("a", 1<<20),
+	}
+
+	for name, input := range inputs {
+		start := time.Now()
+		Render(input, cdnImages)
+		if elapsed := time.Since(start); elapsed > 2*time.Second {
+			t.Errorf("%s: took %v", name, elapsed)
+		}
+	}
+}
+
+func TestRenderCapsNestingDepth(t *testing.T) {
+	got := Render(strings.Repeat("- ", maxNestingDepth+2)+"x", Options{})
+	if n := strings.Count(got, "<ul>"); n != maxNestingDepth {
+		t.Errorf("got %d nested lists, want %d: %q", n, maxNestingDepth, got)
+	}
+	if !strings.Contains(got, "- - x") {
+		t.Errorf("markers beyond the limit should be text: %q", got)
+	}
+}
+
+func nestedList(depth int) string {
+	var b strings.Builder
+	for i := 0; i < depth; i++ {
+		b.WriteString(strings.Repeat("  ", i))
+		b.WriteString("- item\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,95 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ---------------- Assignments ----------------
+// Mỗi lesson có lesson_type = assignment gắn với một Assignment
+type Assignment struct {
+	Id           uint   `gorm:"primaryKey" json:"id"`
+	LessonId     uint   `gorm:"uniqueIndex;not null" json:"lesson_id"`
+	CourseId     uint   `gorm:"index;not null" json:"course_id"`
+	Instructions string `json:"instructions"`
+
+	// Hạn nộp: ngày cố định hoặc số ngày tính từ lúc enroll (chỉ dùng một trong hai)
+	DueAt                  *time.Time `json:"due_at"`
+	DueDaysAfterEnrollment *int       `json:"due_days_after_enrollment"`
+
+	MaxPoints      float64           `gorm:"default:100" json:"max_points"`
+	PassPercentage float64           `gorm:"default:50" json:"pass_percentage"`
+	Rubric         []RubricCriterion `gorm:"type:jsonb;serializer:json" json:"rubric"`
+
+	// Quy tắc nộp lại
+	AllowResubmission bool `gorm:"default:false" json:"allow_resubmission"`
+	MaxSubmissions    int  `gorm:"default:0" json:"max_submissions"` // 0 = không giới hạn
+	MaxFiles          int  `gorm:"default:5" json:"max_files"`
+
+	// Nộp trễ: trừ LatePenaltyPercent mỗi ngày, tối đa MaxLatePenaltyPercent
+	AllowLateSubmission   bool    `gorm:"default:false" json:"allow_late_submission"`
+	LatePenaltyPercent    float64 `gorm:"default:0" json:"late_penalty_percent"`
+	MaxLatePenaltyPercent float64 `gorm:"default:100" json:"max_late_penalty_percent"`
+
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+type RubricCriterion struct {
+	Key         string  `json:"key"`
+	Title       string  `json:"title"`
+	Description string  `json:"description"`
+	MaxPoints   float64 `json:"max_points"`
+}
+
+type AssignmentSubmission struct {
+	Id               uint             `gorm:"primaryKey" json:"id"`
+	AssignmentId     uint             `gorm:"index;not null" json:"assignment_id"`
+	Assignment       Assignment       `gorm:"foreignKey:AssignmentId" json:"assignment"`
+	LessonId         uint             `gorm:"index;not null" json:"lesson_id"`
+	Lesson           Lesson           `gorm:"foreignKey:LessonId" json:"lesson"`
+	CourseId         uint             `gorm:"index;not null" json:"course_id"`
+	Course           Course           `gorm:"foreignKey:CourseId" json:"course"`
+	UserId           uint             `gorm:"index;not null" json:"user_id"`
+	User             User             `gorm:"foreignKey:UserId" json:"user"`
+	SubmissionNumber int              `gorm:"not null" json:"submission_number"`
+	TextContent      string           `json:"text_content"`
+	Files            []SubmissionFile `gorm:"type:jsonb;serializer:json" json:"files"`
+	Status           string           `gorm:"size:20;default:submitted;index" json:"status"` // submitted, graded, superseded
+	SubmittedAt      time.Time        `json:"submitted_at"`
+
+	// Thông tin nộp trễ được chốt tại thời điểm nộp
+	DueAt              *time.Time `json:"due_at"`
+	IsLate             bool       `gorm:"default:false" json:"is_late"`
+	LateDays           int        `gorm:"default:0" json:"late_days"`
+	LatePenaltyPercent float64    `gorm:"default:0" json:"late_penalty_percent"`
+
+	// Kết quả chấm
+	RubricScores []RubricScore `gorm:"type:jsonb;serializer:json" json:"rubric_scores"`
+	RawScore     *float64      `json:"raw_score"`
+	FinalScore   *float64      `json:"final_score"` // Sau khi trừ điểm nộp trễ
+	MaxPoints    float64       `json:"max_points"`
+	Passed       bool          `gorm:"default:false" json:"passed"`
+	Feedback     string        `json:"feedback"`
+	GradedBy     *uint         `json:"graded_by"`
+	GradedAt     *time.Time    `json:"graded_at"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type SubmissionFile struct {
+	Id           string `json:"id"`
+	OriginalName string `json:"original_name"`
+	StoredName   string `json:"stored_name"`
+	Size         int64  `json:"size"`
+	MimeType     string `json:"mime_type"`
+}
+
+type RubricScore struct {
+	CriterionKey string  `json:"criterion_key"`
+	Points       float64 `json:"points"`
+	Comment      string  `json:"comment"`
+}
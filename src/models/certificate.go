@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// ---------------- Certificates ----------------
+// Certificate được cấp khi enrollment hoàn thành, PDF render lại từ các field này mỗi lần tải.
+// Tên học viên đổi thì chứng chỉ được cấp lại: cập nhật LearnerName, tăng Revision, giữ nguyên Serial
+type Certificate struct {
+	Id             uint       `gorm:"primaryKey" json:"id"`
+	Serial         string     `gorm:"size:20;uniqueIndex;not null" json:"serial"`
+	EnrollmentId   uint       `gorm:"uniqueIndex;not null" json:"enrollment_id"`
+	UserId         uint       `gorm:"index;not null" json:"user_id"`
+	CourseId       uint       `gorm:"index;not null" json:"course_id"`
+	LearnerName    string     `gorm:"size:100;not null" json:"learner_name"`
+	CourseTitle    string     `gorm:"size:200;not null" json:"course_title"`
+	InstructorName string     `gorm:"size:100" json:"instructor_name"`
+	CompletedAt    time.Time  `gorm:"not null" json:"completed_at"`
+	IssuedAt       time.Time  `gorm:"not null" json:"issued_at"`
+	Revision       int        `gorm:"not null;default:1" json:"revision"`
+	ReissuedAt     *time.Time `json:"reissued_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ---------------- Course Moderation ----------------
+type CourseModeration struct {
+	Id            uint                `gorm:"primaryKey" json:"id"`
+	CourseId      uint                `gorm:"index;not null" json:"course_id"`
+	Course        Course              `gorm:"foreignKey:CourseId" json:"course"`
+	InstructorId  uint                `gorm:"index;not null" json:"instructor_id"`
+	Status        string              `gorm:"size:20;default:pending" json:"status"` // pending, approved, rejected
+	Checklist     ModerationChecklist `gorm:"type:jsonb;serializer:json" json:"checklist"`
+	SubmitNote    string              `gorm:"size:1000" json:"submit_note"`
+	ReviewerId    *uint               `json:"reviewer_id"`
+	ReviewComment string              `json:"review_comment"`
+	SubmittedAt   time.Time           `json:"submitted_at"`
+	ReviewedAt    *time.Time          `json:"reviewed_at"`
+	CreatedAt     time.Time           `json:"created_at"`
+	UpdatedAt     time.Time           `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt      `gorm:"index" json:"-"`
+}
+
+// ModerationChecklist lưu kết quả kiểm tra tự động tại thời điểm submit
+type ModerationChecklist struct {
+	HasThumbnail         bool `json:"has_thumbnail"`
+	LessonCount          int  `json:"lesson_count"`
+	MinLessons           int  `json:"min_lessons"`
+	HasMinLessons        bool `json:"has_min_lessons"`
+	HasPreviewLesson     bool `json:"has_preview_lesson"`
+	DescriptionLength    int  `json:"description_length"`
+	MinDescriptionLength int  `json:"min_description_length"`
+	HasDescription       bool `json:"has_description"`
+	Passed               bool `json:"passed"`
+}
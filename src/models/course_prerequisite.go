@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// ---------------- Course Prerequisites ----------------
+// Course CourseId yêu cầu học viên đã hoàn thành PrerequisiteCourseId trước khi enroll/mua
+type CoursePrerequisite struct {
+	Id                   uint      `gorm:"primaryKey" json:"id"`
+	CourseId             uint      `gorm:"uniqueIndex:idx_course_prerequisite;not null" json:"course_id"`
+	PrerequisiteCourseId uint      `gorm:"uniqueIndex:idx_course_prerequisite;index;not null" json:"prerequisite_course_id"`
+	PrerequisiteCourse   Course    `gorm:"foreignKey:PrerequisiteCourseId" json:"prerequisite_course"`
+	CreatedAt            time.Time `json:"created_at"`
+}
@@ -0,0 +1,110 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ---------------- Course Revisions ----------------
+type CourseRevision struct {
+	Id             uint           `gorm:"primaryKey" json:"id"`
+	CourseId       uint           `gorm:"index;not null" json:"course_id"`
+	RevisionNumber int            `gorm:"not null" json:"revision_number"`
+	Status         string         `gorm:"size:20;default:draft" json:"status"` // draft, published, superseded
+	Snapshot       CourseSnapshot `gorm:"type:jsonb;serializer:json" json:"snapshot"`
+	Note           string         `gorm:"size:500" json:"note"`
+	CreatedBy      uint           `json:"created_by"`
+	PublishedAt    *time.Time     `json:"published_at"`
+	RolledBackFrom *uint          `json:"rolled_back_from"` // Revision gốc nếu được tạo từ rollback
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// CourseSnapshot lưu toàn bộ nội dung course (metadata + lessons) tại một thời điểm
+type CourseSnapshot struct {
+	Version         int              `json:"version"` // 0 = snapshot cũ, lesson chỉ có các field cơ bản
+	Title           string           `json:"title"`
+	Description     string           `json:"description"`
+	ShortDesc       string           `json:"short_description"`
+	ThumbnailURL    string           `json:"thumbnail_url"`
+	VideoPreviewURL string           `json:"video_preview_url"`
+	Price           float64          `json:"price"`
+	DiscountPrice   *float64         `json:"discount_price"`
+	CategoryId      uint             `json:"category_id"`
+	Level           string           `json:"level"`
+	Language        string           `json:"language"`
+	Requirements    string           `json:"requirements"`
+	WhatYouLearn    string           `json:"what_you_learn"`
+	DurationHours   int              `json:"duration_hours"`
+	Lessons         []LessonSnapshot `json:"lessons"`
+}
+
+type LessonSnapshot struct {
+	LessonId      uint   `json:"lesson_id"` // 0 = lesson mới, chưa có trên bản live
+	Title         string `json:"title"`
+	Description   string `json:"description"`
+	Content       string `json:"content"`
+	VideoURL      string `json:"video_url"`
+	VideoDuration int    `json:"video_duration"`
+	LessonOrder   int    `json:"lesson_order"`
+	IsPreview     bool   `json:"is_preview"`
+	IsPublished   bool   `json:"is_published"`
+
+	LessonType  string     `json:"lesson_type"`
+	PublishAt   *time.Time `json:"publish_at"`
+	UnpublishAt *time.Time `json:"unpublish_at"`
+
+	UnlockAfterDays           int  `json:"unlock_after_days"`
+	RequirePreviousCompletion bool `json:"require_previous_completion"`
+
+	// Nội dung gắn với lesson, được sửa trực tiếp trên bản live và chốt lại khi publish
+	Quiz         *Quiz                    `json:"quiz,omitempty"` // Kèm question bank
+	Assignment   *Assignment              `json:"assignment,omitempty"`
+	ScormPackage *ScormPackageSnapshot    `json:"scorm_package,omitempty"`
+	Resources    []LessonResourceSnapshot `json:"resources,omitempty"`
+	Subtitles    []LessonSubtitleSnapshot `json:"subtitles,omitempty"`
+}
+
+// Các bản snapshot dưới đây giữ cả field ẩn khỏi API (storage key, danh sách file) để khôi phục được record
+type ScormPackageSnapshot struct {
+	Id            uint      `json:"id"`
+	Version       string    `json:"version"`
+	Identifier    string    `json:"identifier"`
+	Title         string    `json:"title"`
+	LaunchPath    string    `json:"launch_path"`
+	LaunchQuery   string    `json:"launch_query"`
+	MasteryScore  *float64  `json:"mastery_score"`
+	StoragePrefix string    `json:"storage_prefix"`
+	Files         []string  `json:"files"`
+	FileCount     int       `json:"file_count"`
+	TotalSize     int64     `json:"total_size"`
+	UploadedBy    uint      `json:"uploaded_by"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type LessonResourceSnapshot struct {
+	Id            uint      `json:"id"`
+	Title         string    `json:"title"`
+	Description   string    `json:"description"`
+	OriginalName  string    `json:"original_name"`
+	StoredName    string    `json:"stored_name"`
+	Size          int64     `json:"size"`
+	MimeType      string    `json:"mime_type"`
+	ResourceOrder int       `json:"resource_order"`
+	UploadedBy    uint      `json:"uploaded_by"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type LessonSubtitleSnapshot struct {
+	Id           uint      `json:"id"`
+	Language     string    `json:"language"`
+	Label        string    `json:"label"`
+	SourceFormat string    `json:"source_format"`
+	StorageKey   string    `json:"storage_key"`
+	CueCount     int       `json:"cue_count"`
+	IsDefault    bool      `json:"is_default"`
+	UploadedBy   uint      `json:"uploaded_by"`
+	CreatedAt    time.Time `json:"created_at"`
+}
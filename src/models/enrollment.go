@@ -17,7 +17,16 @@ type Enrollment struct {
 	CompletedAt        *time.Time     `json:"completed_at"`
 	ProgressPercentage float64        `gorm:"default:0" json:"progress_percentage"`
 	LastAccessedAt     *time.Time     `json:"last_accessed_at"`
-	Status             string         `gorm:"size:20;default:active" json:"status"` // active, completed, dropped
+	LastReminderAt     *time.Time     `json:"-"`                                                       // Email nhắc học gần nhất
+	ExpiresAt          *time.Time     `gorm:"index" json:"expires_at"`                                 // Hết quyền truy cập, nil = trọn đời
+	DroppedAt          *time.Time     `json:"dropped_at"`                                              // Thời điểm học viên tự hủy
+	Status             string         `gorm:"size:20;default:active" json:"status"`                    // active, completed, dropped, expired, revoked
+	EnrollmentSource   string         `gorm:"size:20;default:purchase;index" json:"enrollment_source"` // purchase, free, admin_grant, invite, org_seat
+	GrantedBy          *uint          `json:"granted_by"`                                              // Admin/giảng viên cấp quyền thủ công
+	TransferredFromId  *uint          `json:"transferred_from_id"`                                     // Enrollment gốc khi được chuyển từ user khác
+	RevokedAt          *time.Time     `json:"revoked_at"`
+	RevokedBy          *uint          `json:"revoked_by"`
+	RevokeReason       string         `gorm:"size:500" json:"revoke_reason"`
 	CreatedAt          time.Time      `json:"created_at"`
 	UpdatedAt          time.Time      `json:"updated_at"`
 	DeletedAt          gorm.DeletedAt `gorm:"index" json:"-"`
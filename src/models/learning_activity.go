@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// ---------------- Learning Activity ----------------
+// LearningActivity - thời gian xem video và số lesson hoàn thành của user trong một ngày (theo LEARNING_TIMEZONE)
+type LearningActivity struct {
+	Id               uint      `gorm:"primaryKey" json:"id"`
+	UserId           uint      `gorm:"not null;uniqueIndex:idx_learning_activity_user_date" json:"user_id"`
+	ActivityDate     time.Time `gorm:"type:date;not null;uniqueIndex:idx_learning_activity_user_date" json:"activity_date"`
+	WatchSeconds     int       `gorm:"not null" json:"watch_seconds"`
+	LessonsCompleted int       `gorm:"not null" json:"lessons_completed"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// ---------------- Learning Goals ----------------
+// LearningGoal - mục tiêu học mỗi tuần của user
+type LearningGoal struct {
+	Id        uint      `gorm:"primaryKey" json:"id"`
+	UserId    uint      `gorm:"uniqueIndex;not null" json:"user_id"`
+	GoalType  string    `gorm:"size:20;not null" json:"goal_type"` // minutes, lessons
+	Target    int       `gorm:"not null" json:"target"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ---------------- Notification Preferences ----------------
+// NotificationPreference - tùy chọn nhận email của user, chưa có bản ghi nghĩa là nhận tất cả.
+// Không dùng default:true vì GORM bỏ qua giá trị false khi insert
+type NotificationPreference struct {
+	Id                uint      `gorm:"primaryKey" json:"id"`
+	UserId            uint      `gorm:"uniqueIndex;not null" json:"user_id"`
+	LearningReminders bool      `gorm:"not null" json:"learning_reminders"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ---------------- Learning Paths ----------------
+type LearningPath struct {
+	Id           uint                 `gorm:"primaryKey" json:"id"`
+	Title        string               `gorm:"size:200;not null" json:"title"`
+	Slug         string               `gorm:"uniqueIndex;size:200;not null" json:"slug"`
+	Description  string               `json:"description"`
+	ShortDesc    string               `gorm:"size:500" json:"short_description"`
+	ThumbnailURL string               `gorm:"size:255" json:"thumbnail_url"`
+	IsPublished  bool                 `gorm:"default:false" json:"is_published"`
+	CreatedBy    uint                 `json:"created_by"`
+	Courses      []LearningPathCourse `gorm:"foreignKey:LearningPathId" json:"courses"`
+	CreatedAt    time.Time            `json:"created_at"`
+	UpdatedAt    time.Time            `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt       `gorm:"index" json:"-"`
+}
+
+type LearningPathCourse struct {
+	Id             uint      `gorm:"primaryKey" json:"id"`
+	LearningPathId uint      `gorm:"index;not null" json:"learning_path_id"`
+	CourseId       uint      `gorm:"not null" json:"course_id"`
+	Course         Course    `gorm:"foreignKey:CourseId" json:"course"`
+	CourseOrder    int       `gorm:"not null" json:"course_order"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// UserLearningPath lưu learning path mà user đang theo
+type UserLearningPath struct {
+	Id             uint         `gorm:"primaryKey" json:"id"`
+	UserId         uint         `gorm:"uniqueIndex:idx_user_learning_path;not null" json:"user_id"`
+	LearningPathId uint         `gorm:"uniqueIndex:idx_user_learning_path;not null" json:"learning_path_id"`
+	LearningPath   LearningPath `gorm:"foreignKey:LearningPathId" json:"learning_path"`
+	StartedAt      time.Time    `json:"started_at"`
+	CompletedAt    *time.Time   `json:"completed_at"`
+	CreatedAt      time.Time    `json:"created_at"`
+	UpdatedAt      time.Time    `json:"updated_at"`
+}
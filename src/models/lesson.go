@@ -8,18 +8,26 @@ import (
 
 // ---------------- Lessons ----------------
 type Lesson struct {
-	Id            uint           `gorm:"primaryKey" json:"id"`
-	CourseId      uint           `json:"course_id"`
-	Title         string         `gorm:"size:200;not null" json:"title"`
-	Slug          string         `gorm:"size:200;not null" json:"slug"`
-	Description   string         `json:"description"`
-	Content       string         `json:"content"`
-	VideoURL      string         `gorm:"size:255" json:"video_url"`
-	VideoDuration int            `json:"video_duration"`
-	LessonOrder   int            `gorm:"not null" json:"lesson_order"`
-	IsPreview     bool           `gorm:"default:false" json:"is_preview"`
-	IsPublished   bool           `gorm:"default:true" json:"is_published"`
-	CreatedAt     time.Time      `json:"created_at"`
-	UpdatedAt     time.Time      `json:"updated_at"`
-	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
+	Id            uint       `gorm:"primaryKey" json:"id"`
+	CourseId      uint       `json:"course_id"`
+	Title         string     `gorm:"size:200;not null" json:"title"`
+	Slug          string     `gorm:"size:200;not null" json:"slug"`
+	Description   string     `json:"description"`
+	Content       string     `json:"content"`      // Markdown
+	ContentHTML   string     `json:"content_html"` // HTML đã sanitize, render từ Content khi lưu
+	VideoURL      string     `gorm:"size:255" json:"video_url"`
+	VideoDuration int        `json:"video_duration"`
+	LessonType    string     `gorm:"size:20;default:video" json:"lesson_type"` // video, quiz, assignment, scorm
+	LessonOrder   int        `gorm:"not null" json:"lesson_order"`
+	IsPreview     bool       `gorm:"default:false" json:"is_preview"`
+	IsPublished   bool       `gorm:"default:true" json:"is_published"`
+	PublishAt     *time.Time `gorm:"index" json:"publish_at"`   // Thời điểm tự động publish
+	UnpublishAt   *time.Time `gorm:"index" json:"unpublish_at"` // Thời điểm tự động ẩn
+
+	// Drip content: điều kiện mở khóa lesson cho từng học viên
+	UnlockAfterDays           int            `gorm:"default:0" json:"unlock_after_days"` // Số ngày sau EnrolledAt
+	RequirePreviousCompletion bool           `gorm:"default:false" json:"require_previous_completion"`
+	CreatedAt                 time.Time      `json:"created_at"`
+	UpdatedAt                 time.Time      `json:"updated_at"`
+	DeletedAt                 gorm.DeletedAt `gorm:"index" json:"-"`
 }
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ---------------- Lesson Notes ----------------
+// Ghi chú riêng tư của học viên, có thể gắn với một thời điểm trong video
+type LessonNote struct {
+	Id             uint           `gorm:"primaryKey" json:"id"`
+	UserId         uint           `gorm:"index:idx_lesson_notes_user_lesson;not null" json:"user_id"`
+	LessonId       uint           `gorm:"index:idx_lesson_notes_user_lesson;not null" json:"lesson_id"`
+	Lesson         Lesson         `gorm:"foreignKey:LessonId" json:"-"`
+	CourseId       uint           `gorm:"index;not null" json:"course_id"`
+	Course         Course         `gorm:"foreignKey:CourseId" json:"-"`
+	Content        string         `gorm:"type:text;not null" json:"content"`
+	VideoTimestamp *int           `json:"video_timestamp"` // Giây trong video, nil = ghi chú cho cả lesson
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// LessonBookmark đánh dấu lesson để xem lại, mỗi user chỉ bookmark một lesson một lần
+type LessonBookmark struct {
+	Id        uint      `gorm:"primaryKey" json:"id"`
+	UserId    uint      `gorm:"uniqueIndex:idx_lesson_bookmarks_user_lesson;not null" json:"user_id"`
+	LessonId  uint      `gorm:"uniqueIndex:idx_lesson_bookmarks_user_lesson;not null" json:"lesson_id"`
+	Lesson    Lesson    `gorm:"foreignKey:LessonId" json:"-"`
+	CourseId  uint      `gorm:"index;not null" json:"course_id"`
+	Course    Course    `gorm:"foreignKey:CourseId" json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ---------------- Lesson Resources ----------------
+// File đính kèm của lesson (slides, PDF, code ZIP, dataset), lưu trong storage dưới prefix "resources/" (không public)
+type LessonResource struct {
+	Id            uint           `gorm:"primaryKey" json:"id"`
+	LessonId      uint           `gorm:"index;not null" json:"lesson_id"`
+	CourseId      uint           `gorm:"index;not null" json:"course_id"`
+	Title         string         `gorm:"size:200;not null" json:"title"`
+	Description   string         `json:"description"`
+	OriginalName  string         `gorm:"size:255;not null" json:"original_name"`
+	StoredName    string         `gorm:"size:255;not null" json:"-"`
+	Size          int64          `json:"size"`
+	MimeType      string         `gorm:"size:100" json:"mime_type"`
+	ResourceOrder int            `gorm:"default:0" json:"resource_order"`
+	DownloadCount int            `gorm:"default:0" json:"download_count"`
+	UploadedBy    uint           `json:"uploaded_by"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
+}
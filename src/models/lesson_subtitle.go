@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ---------------- Lesson Subtitles ----------------
+// Mỗi lesson có tối đa một track cho mỗi ngôn ngữ, file luôn được lưu dạng WebVTT
+type LessonSubtitle struct {
+	Id           uint           `gorm:"primaryKey" json:"id"`
+	LessonId     uint           `gorm:"index;not null" json:"lesson_id"`
+	CourseId     uint           `gorm:"index;not null" json:"course_id"`
+	Language     string         `gorm:"size:10;not null" json:"language"`
+	Label        string         `gorm:"size:100;not null" json:"label"`
+	SourceFormat string         `gorm:"size:10;not null" json:"source_format"` // srt, vtt
+	StorageKey   string         `gorm:"size:255;not null" json:"-"`
+	CueCount     int            `gorm:"default:0" json:"cue_count"`
+	IsDefault    bool           `gorm:"default:false" json:"is_default"`
+	UploadedBy   uint           `json:"uploaded_by"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// LessonSubtitleCue lưu từng câu phụ đề (text đã bỏ định dạng) để tìm kiếm transcript
+type LessonSubtitleCue struct {
+	Id         uint   `gorm:"primaryKey" json:"id"`
+	SubtitleId uint   `gorm:"index;not null" json:"subtitle_id"`
+	CueIndex   int    `gorm:"not null" json:"cue_index"`
+	StartMs    int64  `gorm:"not null" json:"start_ms"`
+	EndMs      int64  `gorm:"not null" json:"end_ms"`
+	Text       string `gorm:"not null" json:"text"`
+}
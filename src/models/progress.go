@@ -8,15 +8,52 @@ import (
 
 // ---------------- Progress ----------------
 type Progress struct {
-	Id            uint           `gorm:"primaryKey" json:"id"`
-	UserId        uint           `json:"user_id"`
-	LessonId      uint           `json:"lesson_id"`
-	CourseId      uint           `json:"course_id"`
-	IsCompleted   bool           `gorm:"default:false" json:"is_completed"`
-	CompletedAt   *time.Time     `json:"completed_at"`
-	WatchDuration int            `gorm:"default:0" json:"watch_duration"`
-	LastPosition  int            `gorm:"default:0" json:"last_position"`
-	CreatedAt     time.Time      `json:"created_at"`
-	UpdatedAt     time.Time      `json:"updated_at"`
-	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
+	Id               uint            `gorm:"primaryKey" json:"id"`
+	UserId           uint            `json:"user_id"`
+	LessonId         uint            `json:"lesson_id"`
+	CourseId         uint            `json:"course_id"`
+	IsCompleted      bool            `gorm:"default:false" json:"is_completed"`
+	CompletedAt      *time.Time      `json:"completed_at"`
+	WatchDuration    int             `gorm:"default:0" json:"watch_duration"` // Số giây video thực sự đã xem, tính từ WatchedIntervals
+	LastPosition     int             `gorm:"default:0" json:"last_position"`
+	WatchedIntervals []WatchInterval `gorm:"type:jsonb;serializer:json" json:"watched_intervals"` // Các đoạn đã xem, đã gộp và sắp xếp
+	LastHeartbeatAt  *time.Time      `json:"last_heartbeat_at"`
+	CreatedAt        time.Time       `json:"created_at"`
+	UpdatedAt        time.Time       `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt  `gorm:"index" json:"-"`
+}
+
+// WatchInterval là đoạn video [Start, End) đã xem, tính bằng giây
+type WatchInterval struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// ProgressSyncEvent lưu idempotency key của sự kiện offline đã áp dụng, client gửi lại batch cũ không bị áp dụng hai lần
+type ProgressSyncEvent struct {
+	Id             uint      `gorm:"primaryKey" json:"id"`
+	UserId         uint      `gorm:"uniqueIndex:idx_progress_sync_user_key;not null" json:"user_id"`
+	IdempotencyKey string    `gorm:"size:100;uniqueIndex:idx_progress_sync_user_key;not null" json:"idempotency_key"`
+	LessonId       uint      `gorm:"index;not null" json:"lesson_id"`
+	EventType      string    `gorm:"size:20;not null" json:"event_type"` // position, complete
+	OccurredAt     time.Time `json:"occurred_at"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// ProgressRecalculation là job tính lại progress của mọi enrollment trong course khi nội dung course thay đổi.
+// Job được ghi vào DB cùng lúc với thay đổi nên không bị mất khi process restart, LastEnrollmentId là cursor để chạy tiếp theo batch
+type ProgressRecalculation struct {
+	Id               uint       `gorm:"primaryKey" json:"id"`
+	CourseId         uint       `gorm:"index;not null" json:"course_id"`
+	Reason           string     `gorm:"size:50" json:"reason"`                                // lesson_created, lesson_updated, lesson_deleted, lesson_duplicated, lesson_schedule, revision_published
+	Status           string     `gorm:"size:20;not null;default:pending;index" json:"status"` // pending, running, completed, failed
+	LastEnrollmentId uint       `gorm:"default:0" json:"last_enrollment_id"`
+	ProcessedCount   int        `gorm:"default:0" json:"processed_count"`
+	UpdatedCount     int        `gorm:"default:0" json:"updated_count"`
+	Attempts         int        `gorm:"default:0" json:"attempts"`
+	LastError        string     `gorm:"type:text" json:"last_error"`
+	StartedAt        *time.Time `json:"started_at"`
+	FinishedAt       *time.Time `json:"finished_at"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
 }
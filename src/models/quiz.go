@@ -0,0 +1,84 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ---------------- Quizzes ----------------
+// Mỗi lesson có lesson_type = quiz gắn với một Quiz
+type Quiz struct {
+	Id                  uint           `gorm:"primaryKey" json:"id"`
+	LessonId            uint           `gorm:"uniqueIndex;not null" json:"lesson_id"`
+	CourseId            uint           `gorm:"index;not null" json:"course_id"`
+	TimeLimitSeconds    int            `gorm:"default:0" json:"time_limit_seconds"`    // 0 = không giới hạn
+	MaxAttempts         int            `gorm:"default:0" json:"max_attempts"`          // 0 = không giới hạn
+	PassPercentage      float64        `gorm:"default:70" json:"pass_percentage"`      // Điểm tối thiểu (%) để pass
+	QuestionsPerAttempt int            `gorm:"default:0" json:"questions_per_attempt"` // 0 = dùng toàn bộ question bank
+	ShuffleQuestions    bool           `gorm:"default:false" json:"shuffle_questions"`
+	ShuffleOptions      bool           `gorm:"default:false" json:"shuffle_options"`
+	ShowCorrectAnswers  bool           `gorm:"default:false" json:"show_correct_answers"` // Hiện đáp án sau khi nộp bài
+	Questions           []QuizQuestion `gorm:"foreignKey:QuizId" json:"questions"`
+	CreatedAt           time.Time      `json:"created_at"`
+	UpdatedAt           time.Time      `json:"updated_at"`
+	DeletedAt           gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+type QuizQuestion struct {
+	Id              uint           `gorm:"primaryKey" json:"id"`
+	QuizId          uint           `gorm:"index;not null" json:"quiz_id"`
+	QuestionType    string         `gorm:"size:20;not null" json:"question_type"` // single_choice, multiple_choice, true_false, short_answer
+	Text            string         `gorm:"not null" json:"text"`
+	Explanation     string         `json:"explanation"`
+	Points          float64        `gorm:"default:1" json:"points"`
+	QuestionOrder   int            `gorm:"not null" json:"question_order"`
+	Options         []QuizOption   `gorm:"type:jsonb;serializer:json" json:"options"`
+	AcceptedAnswers []string       `gorm:"type:jsonb;serializer:json" json:"accepted_answers"` // Cho short_answer
+	CaseSensitive   bool           `gorm:"default:false" json:"case_sensitive"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+type QuizOption struct {
+	Key       string `json:"key"`
+	Text      string `json:"text"`
+	IsCorrect bool   `json:"is_correct"`
+}
+
+// QuizAttempt lưu đề đã phát cho học viên (thứ tự câu hỏi/đáp án) và kết quả chấm
+type QuizAttempt struct {
+	Id              uint                `gorm:"primaryKey" json:"id"`
+	QuizId          uint                `gorm:"index;not null" json:"quiz_id"`
+	LessonId        uint                `gorm:"index;not null" json:"lesson_id"`
+	CourseId        uint                `gorm:"not null" json:"course_id"`
+	UserId          uint                `gorm:"index;not null" json:"user_id"`
+	AttemptNumber   int                 `gorm:"not null" json:"attempt_number"`
+	Status          string              `gorm:"size:20;default:in_progress" json:"status"` // in_progress, submitted, expired
+	Questions       []QuizAttemptItem   `gorm:"type:jsonb;serializer:json" json:"questions"`
+	Answers         []QuizAttemptAnswer `gorm:"type:jsonb;serializer:json" json:"answers"`
+	Score           float64             `json:"score"`
+	MaxScore        float64             `json:"max_score"`
+	ScorePercentage float64             `json:"score_percentage"`
+	Passed          bool                `gorm:"default:false" json:"passed"`
+	StartedAt       time.Time           `json:"started_at"`
+	ExpiresAt       *time.Time          `json:"expires_at"`
+	SubmittedAt     *time.Time          `json:"submitted_at"`
+	CreatedAt       time.Time           `json:"created_at"`
+	UpdatedAt       time.Time           `json:"updated_at"`
+}
+
+// QuizAttemptItem là một câu hỏi trong đề, theo thứ tự đã phát
+type QuizAttemptItem struct {
+	QuestionId uint     `json:"question_id"`
+	OptionKeys []string `json:"option_keys"` // Thứ tự đáp án đã phát
+}
+
+type QuizAttemptAnswer struct {
+	QuestionId      uint     `json:"question_id"`
+	SelectedOptions []string `json:"selected_options"`
+	TextAnswer      string   `json:"text_answer"`
+	IsCorrect       bool     `json:"is_correct"`
+	PointsAwarded   float64  `json:"points_awarded"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// ---------------- SCORM ----------------
+// Mỗi lesson dạng scorm có một package (xóa hẳn khi thay), các file đã giải nén nằm trong storage dưới StoragePrefix
+type ScormPackage struct {
+	Id            uint      `gorm:"primaryKey" json:"id"`
+	LessonId      uint      `gorm:"uniqueIndex;not null" json:"lesson_id"`
+	CourseId      uint      `gorm:"index;not null" json:"course_id"`
+	Version       string    `gorm:"size:10;not null" json:"version"`
+	Identifier    string    `gorm:"size:255" json:"identifier"`
+	Title         string    `gorm:"size:255" json:"title"`
+	LaunchPath    string    `gorm:"size:255;not null" json:"launch_path"`
+	LaunchQuery   string    `gorm:"size:500" json:"launch_query"`
+	MasteryScore  *float64  `json:"mastery_score"`
+	StoragePrefix string    `gorm:"size:255;not null" json:"-"`
+	Files         []string  `gorm:"type:jsonb;serializer:json" json:"-"` // Đường dẫn tương đối so với StoragePrefix
+	FileCount     int       `json:"file_count"`
+	TotalSize     int64     `json:"total_size"`
+	UploadedBy    uint      `json:"uploaded_by"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// ScormAttempt lưu dữ liệu runtime (cmi.*) của học viên cho lesson SCORM, mỗi user một record mỗi lesson
+type ScormAttempt struct {
+	Id             uint       `gorm:"primaryKey" json:"id"`
+	UserId         uint       `gorm:"uniqueIndex:idx_scorm_attempt_user_lesson;not null" json:"user_id"`
+	LessonId       uint       `gorm:"uniqueIndex:idx_scorm_attempt_user_lesson;not null" json:"lesson_id"`
+	CourseId       uint       `gorm:"index;not null" json:"course_id"`
+	LessonStatus   string     `gorm:"size:20;not null;default:'not attempted'" json:"lesson_status"`
+	Exit           string     `gorm:"size:20" json:"exit"`
+	ScoreRaw       *float64   `json:"score_raw"`
+	ScoreMin       *float64   `json:"score_min"`
+	ScoreMax       *float64   `json:"score_max"`
+	LessonLocation string     `gorm:"size:255" json:"lesson_location"`
+	SuspendData    string     `gorm:"type:text" json:"suspend_data"`
+	TotalTimeMs    int64      `gorm:"default:0" json:"total_time_ms"`
+	SessionTimeMs  int64      `gorm:"default:0" json:"session_time_ms"` // session_time của phiên đang mở, cộng vào TotalTimeMs khi phiên kết thúc
+	SessionCount   int        `gorm:"default:0" json:"session_count"`
+	CompletedAt    *time.Time `json:"completed_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
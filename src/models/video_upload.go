@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// ---------------- Video Uploads ----------------
+// Trạng thái của một lần upload video theo từng chunk (có thể resume bằng upload_offset)
+type VideoUpload struct {
+	Id            string     `gorm:"primaryKey;size:36" json:"id"`
+	LessonId      uint       `gorm:"index;not null" json:"lesson_id"`
+	CourseId      uint       `gorm:"index;not null" json:"course_id"`
+	InstructorId  uint       `gorm:"index;not null" json:"instructor_id"`
+	FileName      string     `gorm:"size:255;not null" json:"file_name"`
+	FileSize      int64      `gorm:"not null" json:"file_size"`
+	ContentType   string     `gorm:"size:100" json:"content_type"`
+	Checksum      string     `gorm:"size:64" json:"checksum"`                       // SHA-256 (hex) của toàn bộ file, không bắt buộc
+	UploadOffset  int64      `gorm:"default:0" json:"upload_offset"`                // Số byte đã nhận
+	Status        string     `gorm:"size:20;default:uploading;index" json:"status"` // uploading, completed, failed, aborted, expired
+	StorageKey    string     `gorm:"size:500" json:"storage_key"`
+
+	// Các chunk được ghi thẳng vào storage qua multipart upload nên replica nào cũng nhận tiếp được
+	MultipartUploadId string            `gorm:"size:255" json:"-"`
+	Parts             []VideoUploadPart `gorm:"type:jsonb;serializer:json" json:"-"`
+	ChecksumState     []byte            `json:"-"` // Trạng thái SHA-256 sau chunk cuối cùng, tính checksum toàn file mà không đọc lại
+	VideoDuration int        `json:"video_duration"`
+	ErrorMessage  string     `json:"error_message"`
+	ExpiresAt     time.Time  `gorm:"index" json:"expires_at"` // Quá hạn mà chưa xong sẽ bị dọn dẹp
+	CompletedAt   *time.Time `json:"completed_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// VideoUploadPart là một chunk đã ghi vào storage (part của multipart upload)
+type VideoUploadPart struct {
+	PartNumber int    `json:"part_number"`
+	ETag       string `json:"etag"`
+}
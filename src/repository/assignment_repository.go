@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"lms/src/models"
+
+	"gorm.io/gorm"
+)
+
+type DBAssignmentRepository struct {
+	db *gorm.DB
+}
+
+func NewDBAssignmentRepository(db *gorm.DB) AssignmentRepository {
+	return &DBAssignmentRepository{
+		db: db,
+	}
+}
+
+// FindByLessonId trả về nil nếu lesson chưa có assignment
+func (ar *DBAssignmentRepository) FindByLessonId(lessonId uint) (*models.Assignment, error) {
+	var assignment models.Assignment
+	err := ar.db.Where("lesson_id = ?", lessonId).First(&assignment).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &assignment, nil
+}
+
+func (ar *DBAssignmentRepository) FindLessonById(lessonId uint) (*models.Lesson, error) {
+	var lesson models.Lesson
+	if err := ar.db.Where("id = ?", lessonId).First(&lesson).Error; err != nil {
+		return nil, err
+	}
+	return &lesson, nil
+}
+
+func (ar *DBAssignmentRepository) CreateAssignment(assignment *models.Assignment) error {
+	return ar.db.Create(assignment).Error
+}
+
+func (ar *DBAssignmentRepository) UpdateAssignment(assignmentId uint, updates map[string]interface{}) error {
+	return ar.db.Model(&models.Assignment{}).Where("id = ?", assignmentId).Updates(updates).Error
+}
+
+// UpdateRubric lưu rubric dạng jsonb (Updates bằng struct để serializer được áp dụng)
+func (ar *DBAssignmentRepository) UpdateRubric(assignment *models.Assignment) error {
+	return ar.db.Model(&models.Assignment{}).
+		Where("id = ?", assignment.Id).
+		Select("rubric", "max_points").
+		Updates(assignment).Error
+}
+
+func (ar *DBAssignmentRepository) CreateSubmission(submission *models.AssignmentSubmission) error {
+	return ar.db.Create(submission).Error
+}
+
+func (ar *DBAssignmentRepository) FindSubmissionById(submissionId uint) (*models.AssignmentSubmission, error) {
+	var submission models.AssignmentSubmission
+	err := ar.db.Preload("Assignment").
+		Preload("Lesson").
+		Preload("Course").
+		Preload("User").
+		Where("id = ?", submissionId).
+		First(&submission).Error
+	if err != nil {
+		return nil, err
+	}
+	return &submission, nil
+}
+
+func (ar *DBAssignmentRepository) GetUserSubmissions(userId, assignmentId uint) ([]models.AssignmentSubmission, error) {
+	var submissions []models.AssignmentSubmission
+	err := ar.db.Where("user_id = ? AND assignment_id = ?", userId, assignmentId).
+		Order("submission_number DESC").
+		Find(&submissions).Error
+	return submissions, err
+}
+
+func (ar *DBAssignmentRepository) CountUserSubmissions(userId, assignmentId uint) (int, error) {
+	var count int64
+	err := ar.db.Model(&models.AssignmentSubmission{}).
+		Where("user_id = ? AND assignment_id = ?", userId, assignmentId).
+		Count(&count).Error
+	return int(count), err
+}
+
+// SupersedePendingSubmissions đánh dấu các bài nộp chưa chấm là superseded khi học viên nộp lại
+func (ar *DBAssignmentRepository) SupersedePendingSubmissions(userId, assignmentId uint) error {
+	return ar.db.Model(&models.AssignmentSubmission{}).
+		Where("user_id = ? AND assignment_id = ? AND status = ?", userId, assignmentId, "submitted").
+		Update("status", "superseded").Error
+}
+
+// GetGradingQueue lấy bài nộp thuộc các course của instructor, cũ nhất trước
+func (ar *DBAssignmentRepository) GetGradingQueue(instructorId uint, offset, limit int, filters map[string]interface{}) ([]models.AssignmentSubmission, int, error) {
+	var submissions []models.AssignmentSubmission
+	var total int64
+
+	query := ar.db.Model(&models.AssignmentSubmission{}).
+		Joins("JOIN courses ON courses.id = assignment_submissions.course_id").
+		Where("courses.instructor_id = ? AND courses.deleted_at IS NULL", instructorId)
+
+	if courseId, ok := filters["course_id"]; ok {
+		query = query.Where("assignment_submissions.course_id = ?", courseId)
+	}
+
+	if lessonId, ok := filters["lesson_id"]; ok {
+		query = query.Where("assignment_submissions.lesson_id = ?", lessonId)
+	}
+
+	if status, ok := filters["status"]; ok {
+		query = query.Where("assignment_submissions.status = ?", status)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Preload("Lesson").
+		Preload("Course").
+		Preload("User").
+		Order("assignment_submissions.submitted_at ASC").
+		Offset(offset).
+		Limit(limit).
+		Find(&submissions).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return submissions, int(total), nil
+}
+
+// GradeSubmission lưu kết quả chấm (Select để ghi cả giá trị zero như passed = false)
+func (ar *DBAssignmentRepository) GradeSubmission(submission *models.AssignmentSubmission) error {
+	return ar.db.Model(&models.AssignmentSubmission{}).
+		Where("id = ?", submission.Id).
+		Select("status", "rubric_scores", "raw_score", "final_score", "max_points", "passed", "feedback", "graded_by", "graded_at").
+		Updates(submission).Error
+}
+
+func (ar *DBAssignmentRepository) HasPassedSubmission(userId, lessonId uint) (bool, error) {
+	var count int64
+	err := ar.db.Model(&models.AssignmentSubmission{}).
+		Where("user_id = ? AND lesson_id = ? AND status = ? AND passed = ?", userId, lessonId, "graded", true).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// GetCourseSubmissions lấy các bài nộp (trừ superseded) của user trong course, dùng cho course progress
+func (ar *DBAssignmentRepository) GetCourseSubmissions(userId, courseId uint) ([]models.AssignmentSubmission, error) {
+	var submissions []models.AssignmentSubmission
+	err := ar.db.Where("user_id = ? AND course_id = ? AND status <> ?", userId, courseId, "superseded").
+		Order("submission_number DESC").
+		Find(&submissions).Error
+	return submissions, err
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"errors"
+	"lms/src/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type DBCertificateRepository struct {
+	db *gorm.DB
+}
+
+func NewDBCertificateRepository(db *gorm.DB) CertificateRepository {
+	return &DBCertificateRepository{
+		db: db,
+	}
+}
+
+func (cr *DBCertificateRepository) Create(certificate *models.Certificate) error {
+	return cr.db.Create(certificate).Error
+}
+
+// FindBySerial trả về nil, nil nếu không có chứng chỉ với số hiệu này
+func (cr *DBCertificateRepository) FindBySerial(serial string) (*models.Certificate, error) {
+	var certificate models.Certificate
+	err := cr.db.Where("serial = ?", serial).First(&certificate).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &certificate, nil
+}
+
+// FindByEnrollmentId trả về nil, nil nếu enrollment chưa được cấp chứng chỉ
+func (cr *DBCertificateRepository) FindByEnrollmentId(enrollmentId uint) (*models.Certificate, error) {
+	var certificate models.Certificate
+	err := cr.db.Where("enrollment_id = ?", enrollmentId).First(&certificate).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &certificate, nil
+}
+
+// GetUserCertificates - chứng chỉ của học viên, bỏ qua chứng chỉ có enrollment đã bị thu hồi
+func (cr *DBCertificateRepository) GetUserCertificates(userId uint) ([]models.Certificate, error) {
+	var certificates []models.Certificate
+	err := cr.db.Where("user_id = ?", userId).
+		Where("NOT EXISTS (SELECT 1 FROM enrollments WHERE enrollments.id = certificates.enrollment_id AND enrollments.status = ?)", "revoked").
+		Order("issued_at DESC").
+		Find(&certificates).Error
+	return certificates, err
+}
+
+// FindEnrollmentForIssue lấy enrollment đã hoàn thành kèm học viên, khóa học và giảng viên để in lên chứng chỉ
+func (cr *DBCertificateRepository) FindEnrollmentForIssue(enrollmentId uint) (*models.Enrollment, error) {
+	var enrollment models.Enrollment
+	err := cr.db.
+		Preload("User").
+		Preload("Course").
+		Preload("Course.Instructor").
+		Where("id = ? AND status = ?", enrollmentId, "completed").
+		First(&enrollment).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &enrollment, nil
+}
+
+// GetUncertifiedEnrollmentIds - các enrollment đã hoàn thành nhưng chưa có chứng chỉ (hoàn thành trước khi có tính năng, hoặc cấp lỗi)
+func (cr *DBCertificateRepository) GetUncertifiedEnrollmentIds(userId uint) ([]uint, error) {
+	var ids []uint
+	err := cr.db.Model(&models.Enrollment{}).
+		Where("user_id = ? AND status = ?", userId, "completed").
+		Where("NOT EXISTS (SELECT 1 FROM certificates WHERE certificates.enrollment_id = enrollments.id)").
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// ReissueForLearner cập nhật tên trên các chứng chỉ của học viên, trả về số chứng chỉ được cấp lại
+func (cr *DBCertificateRepository) ReissueForLearner(userId uint, learnerName string) (int64, error) {
+	result := cr.db.Model(&models.Certificate{}).
+		Where("user_id = ? AND learner_name <> ?", userId, learnerName).
+		Updates(map[string]interface{}{
+			"learner_name": learnerName,
+			"revision":     gorm.Expr("revision + 1"),
+			"reissued_at":  time.Now(),
+		})
+	return result.RowsAffected, result.Error
+}
+
+// FindEnrollment lấy enrollment của chứng chỉ kể cả đã bị xóa mềm, trả về nil, nil nếu không còn
+func (cr *DBCertificateRepository) FindEnrollment(enrollmentId uint) (*models.Enrollment, error) {
+	var enrollment models.Enrollment
+	err := cr.db.Unscoped().Where("id = ?", enrollmentId).First(&enrollment).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &enrollment, nil
+}
+
+// IsPurchaseRefunded - order mua khóa học gần nhất (paid/refunded) của học viên đã bị hoàn tiền
+func (cr *DBCertificateRepository) IsPurchaseRefunded(userId, courseId uint) (bool, error) {
+	var order models.Order
+	err := cr.db.
+		Where("user_id = ? AND course_id = ? AND payment_status IN ?", userId, courseId, []string{"paid", "refunded"}).
+		Order("updated_at DESC").
+		First(&order).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return order.PaymentStatus == "refunded", nil
+}
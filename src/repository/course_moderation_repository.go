@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"lms/src/models"
+
+	"gorm.io/gorm"
+)
+
+type DBCourseModerationRepository struct {
+	db *gorm.DB
+}
+
+func NewDBCourseModerationRepository(db *gorm.DB) CourseModerationRepository {
+	return &DBCourseModerationRepository{
+		db: db,
+	}
+}
+
+func (mr *DBCourseModerationRepository) Create(moderation *models.CourseModeration) error {
+	return mr.db.Create(moderation).Error
+}
+
+func (mr *DBCourseModerationRepository) FindById(moderationId uint) (*models.CourseModeration, error) {
+	var moderation models.CourseModeration
+	if err := mr.db.Preload("Course").Preload("Course.Instructor").
+		Where("id = ?", moderationId).
+		First(&moderation).Error; err != nil {
+		return nil, err
+	}
+	return &moderation, nil
+}
+
+// FindPendingByCourse trả về yêu cầu review đang chờ của course, nil nếu không có
+func (mr *DBCourseModerationRepository) FindPendingByCourse(courseId uint) (*models.CourseModeration, error) {
+	var moderation models.CourseModeration
+	err := mr.db.Where("course_id = ? AND status = ?", courseId, "pending").
+		First(&moderation).Error
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &moderation, nil
+}
+
+func (mr *DBCourseModerationRepository) GetCourseModerations(courseId uint) ([]models.CourseModeration, error) {
+	var moderations []models.CourseModeration
+	err := mr.db.Where("course_id = ?", courseId).
+		Order("submitted_at DESC").
+		Find(&moderations).Error
+
+	return moderations, err
+}
+
+func (mr *DBCourseModerationRepository) GetModerationQueue(offset, limit int, filters map[string]interface{}) ([]models.CourseModeration, int, error) {
+	var moderations []models.CourseModeration
+	var total int64
+
+	query := mr.db.Model(&models.CourseModeration{})
+
+	if status, ok := filters["status"]; ok {
+		query = query.Where("status = ?", status)
+	}
+
+	if instructorId, ok := filters["instructor_id"]; ok {
+		query = query.Where("instructor_id = ?", instructorId)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Queue xử lý theo thứ tự submit (cũ nhất trước)
+	if err := query.Preload("Course").Preload("Course.Instructor").
+		Order("submitted_at ASC").
+		Offset(offset).
+		Limit(limit).
+		Find(&moderations).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return moderations, int(total), nil
+}
+
+func (mr *DBCourseModerationRepository) Update(moderationId uint, updates map[string]interface{}) error {
+	return mr.db.Model(&models.CourseModeration{}).
+		Where("id = ?", moderationId).
+		Updates(updates).Error
+}
+
+// GetCourseLessons lấy các lessons đang hoạt động để chạy checklist
+func (mr *DBCourseModerationRepository) GetCourseLessons(courseId uint) ([]models.Lesson, error) {
+	var lessons []models.Lesson
+	err := mr.db.Where("course_id = ?", courseId).
+		Order("lesson_order ASC").
+		Find(&lessons).Error
+
+	return lessons, err
+}
+
+func (mr *DBCourseModerationRepository) BeginTransaction() *gorm.DB {
+	return mr.db.Begin()
+}
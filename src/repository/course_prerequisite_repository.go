@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"lms/src/models"
+
+	"gorm.io/gorm"
+)
+
+type DBCoursePrerequisiteRepository struct {
+	db *gorm.DB
+}
+
+func NewDBCoursePrerequisiteRepository(db *gorm.DB) CoursePrerequisiteRepository {
+	return &DBCoursePrerequisiteRepository{
+		db: db,
+	}
+}
+
+func (pr *DBCoursePrerequisiteRepository) GetPrerequisites(courseId uint) ([]models.CoursePrerequisite, error) {
+	var prerequisites []models.CoursePrerequisite
+	err := pr.db.Preload("PrerequisiteCourse").
+		Where("course_id = ?", courseId).
+		Order("id ASC").
+		Find(&prerequisites).Error
+
+	return prerequisites, err
+}
+
+func (pr *DBCoursePrerequisiteRepository) GetPrerequisiteIds(courseId uint) ([]uint, error) {
+	var ids []uint
+	err := pr.db.Model(&models.CoursePrerequisite{}).
+		Where("course_id = ?", courseId).
+		Pluck("prerequisite_course_id", &ids).Error
+
+	return ids, err
+}
+
+// ReplacePrerequisites thay toàn bộ danh sách prerequisites của course trong một transaction
+func (pr *DBCoursePrerequisiteRepository) ReplacePrerequisites(courseId uint, prerequisiteIds []uint) error {
+	return pr.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("course_id = ?", courseId).Delete(&models.CoursePrerequisite{}).Error; err != nil {
+			return err
+		}
+
+		for _, prerequisiteId := range prerequisiteIds {
+			prerequisite := &models.CoursePrerequisite{
+				CourseId:             courseId,
+				PrerequisiteCourseId: prerequisiteId,
+			}
+			if err := tx.Create(prerequisite).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// GetCompletedCourseIds trả về các course trong courseIds mà user đã hoàn thành
+func (pr *DBCoursePrerequisiteRepository) GetCompletedCourseIds(userId uint, courseIds []uint) (map[uint]bool, error) {
+	completed := make(map[uint]bool)
+	if len(courseIds) == 0 {
+		return completed, nil
+	}
+
+	var ids []uint
+	err := pr.db.Model(&models.Enrollment{}).
+		Where("user_id = ? AND course_id IN ? AND status = ? AND deleted_at IS NULL", userId, courseIds, "completed").
+		Pluck("course_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range ids {
+		completed[id] = true
+	}
+
+	return completed, nil
+}
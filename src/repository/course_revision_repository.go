@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"lms/src/models"
+
+	"gorm.io/gorm"
+)
+
+type DBCourseRevisionRepository struct {
+	db *gorm.DB
+}
+
+func NewDBCourseRevisionRepository(db *gorm.DB) CourseRevisionRepository {
+	return &DBCourseRevisionRepository{
+		db: db,
+	}
+}
+
+func (rr *DBCourseRevisionRepository) Create(revision *models.CourseRevision) error {
+	return rr.db.Create(revision).Error
+}
+
+// FindDraftByCourse trả về draft đang mở của course, nil nếu chưa có
+func (rr *DBCourseRevisionRepository) FindDraftByCourse(courseId uint) (*models.CourseRevision, error) {
+	var revision models.CourseRevision
+	err := rr.db.Where("course_id = ? AND status = ?", courseId, "draft").
+		Order("revision_number DESC").
+		First(&revision).Error
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &revision, nil
+}
+
+func (rr *DBCourseRevisionRepository) FindByIdAndCourse(revisionId, courseId uint) (*models.CourseRevision, error) {
+	var revision models.CourseRevision
+	if err := rr.db.Where("id = ? AND course_id = ?", revisionId, courseId).
+		First(&revision).Error; err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+func (rr *DBCourseRevisionRepository) GetCourseRevisions(courseId uint, offset, limit int) ([]models.CourseRevision, int, error) {
+	var revisions []models.CourseRevision
+	var total int64
+
+	query := rr.db.Model(&models.CourseRevision{}).Where("course_id = ?", courseId)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Order("revision_number DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&revisions).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return revisions, int(total), nil
+}
+
+func (rr *DBCourseRevisionRepository) Update(revisionId uint, updates map[string]interface{}) error {
+	return rr.db.Model(&models.CourseRevision{}).
+		Where("id = ?", revisionId).
+		Updates(updates).Error
+}
+
+func (rr *DBCourseRevisionRepository) UpdateSnapshot(revisionId uint, snapshot models.CourseSnapshot) error {
+	return rr.db.Model(&models.CourseRevision{Id: revisionId}).
+		Select("snapshot").
+		Updates(&models.CourseRevision{Snapshot: snapshot}).Error
+}
+
+func (rr *DBCourseRevisionRepository) Delete(revisionId uint) error {
+	return rr.db.Delete(&models.CourseRevision{}, revisionId).Error
+}
+
+// GetAllCourseLessons lấy toàn bộ lessons (kể cả chưa publish) để dựng snapshot
+func (rr *DBCourseRevisionRepository) GetAllCourseLessons(courseId uint) ([]models.Lesson, error) {
+	var lessons []models.Lesson
+	err := rr.db.Where("course_id = ? AND deleted_at IS NULL", courseId).
+		Order("lesson_order ASC").
+		Find(&lessons).Error
+
+	return lessons, err
+}
+
+// IsStorageReferenced kiểm tra storage key (hoặc prefix của SCORM package) có nằm trong snapshot
+// của revision nào của course không. File còn được revision tham chiếu phải giữ lại để rollback
+func (rr *DBCourseRevisionRepository) IsStorageReferenced(courseId uint, key string) (bool, error) {
+	var count int64
+	err := rr.db.Model(&models.CourseRevision{}).
+		Where("course_id = ? AND snapshot::text LIKE ?", courseId, "%"+escapeLike(`"`+key+`"`)+"%").
+		Count(&count).Error
+	return count > 0, err
+}
@@ -3,8 +3,10 @@ package repository
 import (
 	"fmt"
 	"lms/src/models"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type DBEnrollmentRepository struct {
@@ -21,9 +23,28 @@ func (er *DBEnrollmentRepository) Create(enrollment *models.Enrollment) error {
 	return er.db.Create(enrollment).Error
 }
 
+// accessibleEnrollmentCondition - enrollment còn quyền truy cập: đang học hoặc đã hoàn thành và chưa hết hạn
+const accessibleEnrollmentCondition = "status IN ('active', 'completed') AND (expires_at IS NULL OR expires_at > NOW())"
+
+// CheckEnrollment lấy enrollment còn quyền truy cập course (dropped/expired coi như chưa enroll)
 func (er *DBEnrollmentRepository) CheckEnrollment(userId, courseId uint) (*models.Enrollment, bool) {
 	var enrollment models.Enrollment
 	err := er.db.Where("user_id = ? AND course_id = ? AND deleted_at IS NULL", userId, courseId).
+		Where(accessibleEnrollmentCondition).
+		First(&enrollment).Error
+
+	if err != nil {
+		return nil, false
+	}
+
+	return &enrollment, true
+}
+
+// FindEnrollment lấy enrollment ở mọi trạng thái (dùng khi enroll lại/gia hạn)
+func (er *DBEnrollmentRepository) FindEnrollment(userId, courseId uint) (*models.Enrollment, bool) {
+	var enrollment models.Enrollment
+	err := er.db.Where("user_id = ? AND course_id = ? AND deleted_at IS NULL", userId, courseId).
+		Order("id DESC").
 		First(&enrollment).Error
 
 	if err != nil {
@@ -86,8 +107,8 @@ func (er *DBEnrollmentRepository) UpdateEnrollmentProgress(enrollmentId uint, up
 func (er *DBEnrollmentRepository) CheckUserEnrollment(userId, courseId uint) (bool, error) {
 	var count int64
 	err := er.db.Model(&models.Enrollment{}).
-		Where("user_id = ? AND course_id = ? AND status = ? AND deleted_at IS NULL",
-			userId, courseId, "active").
+		Where("user_id = ? AND course_id = ? AND deleted_at IS NULL", userId, courseId).
+		Where(accessibleEnrollmentCondition).
 		Count(&count).Error
 
 	if err != nil {
@@ -96,3 +117,71 @@ func (er *DBEnrollmentRepository) CheckUserEnrollment(userId, courseId uint) (bo
 
 	return count > 0, nil
 }
+
+// ExpireEnrollments chuyển enrollment đang học đã quá hạn sang expired.
+// Enrollment completed giữ trạng thái (kết quả hoàn thành, chứng chỉ) nhưng vẫn mất quyền truy cập theo expires_at
+func (er *DBEnrollmentRepository) ExpireEnrollments(now time.Time) (int64, error) {
+	result := er.db.Model(&models.Enrollment{}).
+		Where("status = ? AND expires_at IS NOT NULL AND expires_at <= ? AND deleted_at IS NULL", "active", now).
+		Update("status", "expired")
+
+	return result.RowsAffected, result.Error
+}
+
+func (er *DBEnrollmentRepository) FindById(enrollmentId uint) (*models.Enrollment, error) {
+	var enrollment models.Enrollment
+	if err := er.db.Preload("User").Preload("Course").
+		Where("id = ?", enrollmentId).
+		First(&enrollment).Error; err != nil {
+		return nil, err
+	}
+
+	return &enrollment, nil
+}
+
+// CountGrants đếm enrollment cấp thủ công (chưa bị thu hồi) của một người cấp trong course, dùng cho quota
+func (er *DBEnrollmentRepository) CountGrants(courseId, grantedBy uint, source string) (int64, error) {
+	var count int64
+	err := er.db.Model(&models.Enrollment{}).
+		Where("course_id = ? AND granted_by = ? AND enrollment_source = ? AND status <> ?", courseId, grantedBy, source, "revoked").
+		Count(&count).Error
+	return count, err
+}
+
+func (er *DBEnrollmentRepository) GetGrants(courseId, grantedBy uint, source string) ([]models.Enrollment, error) {
+	var enrollments []models.Enrollment
+	err := er.db.Preload("User").
+		Where("course_id = ? AND granted_by = ? AND enrollment_source = ?", courseId, grantedBy, source).
+		Order("updated_at DESC").
+		Find(&enrollments).Error
+	return enrollments, err
+}
+
+// Transaction chạy fn với repository dùng chung một transaction, fn trả lỗi thì rollback toàn bộ
+func (er *DBEnrollmentRepository) Transaction(fn func(txRepo EnrollmentRepository) error) error {
+	return er.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&DBEnrollmentRepository{db: tx})
+	})
+}
+
+// LockCourse khóa dòng course (SELECT ... FOR UPDATE) đến hết transaction, các thao tác cấp quyền theo quota của course chạy tuần tự
+func (er *DBEnrollmentRepository) LockCourse(courseId uint) error {
+	var ids []uint
+	return er.db.Model(&models.Course{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", courseId).
+		Pluck("id", &ids).Error
+}
+
+// LockById giống FindById nhưng khóa dòng enrollment đến hết transaction
+func (er *DBEnrollmentRepository) LockById(enrollmentId uint) (*models.Enrollment, error) {
+	var enrollment models.Enrollment
+	if err := er.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("User").Preload("Course").
+		Where("id = ?", enrollmentId).
+		First(&enrollment).Error; err != nil {
+		return nil, err
+	}
+
+	return &enrollment, nil
+}
@@ -92,6 +92,12 @@ func (ir *DBInstructorRepository) UpdateCourse(courseId uint, updates map[string
 	return ir.db.Model(&models.Course{}).Where("id = ?", courseId).Updates(updates).Error
 }
 
+func (ir *DBInstructorRepository) UpdateCourseThumbnail(courseId uint, thumbnailURL string, variants map[string]string) error {
+	return ir.db.Model(&models.Course{}).Where("id = ?", courseId).
+		Select("thumbnail_url", "thumbnail_variants").
+		Updates(&models.Course{ThumbnailURL: thumbnailURL, ThumbnailVariants: variants}).Error
+}
+
 func (ir *DBInstructorRepository) DeleteCourse(courseId uint) error {
 	return ir.db.Delete(&models.Course{}, courseId).Error
 }
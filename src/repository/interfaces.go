@@ -3,6 +3,7 @@ package repository
 import (
 	"lms/src/dto"
 	"lms/src/models"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -15,7 +16,7 @@ type UserRepository interface {
 	UpdatePassword(userId uint, hashedPassword string) error
 	UpdateProfile(userId uint, updates map[string]interface{}) error
 	ChangePassword(userId uint, hashedPassword string) error
-	UpdateAvatar(userId uint, avatarURL string) error
+	UpdateAvatar(userId uint, avatarURL string, variants map[string]string) error
 	GetUsersWithPagination(offset, limit int, filters map[string]interface{}, orderBy, sortBy string) ([]models.User, int, error)
 	DeleteUser(userId uint) error
 }
@@ -99,10 +100,18 @@ type OrderRepository interface {
 type EnrollmentRepository interface {
 	Create(enrollment *models.Enrollment) error
 	CheckEnrollment(userId, courseId uint) (*models.Enrollment, bool)
+	FindEnrollment(userId, courseId uint) (*models.Enrollment, bool)
 	CheckUserEnrollment(userId, courseId uint) (bool, error)
 	GetUserEnrollments(userId uint, offset, limit int, filters map[string]interface{}) ([]models.Enrollment, int, error)
 	CompleteEnrollment(enrollmentId uint) error
 	UpdateEnrollmentProgress(enrollmentId uint, updates map[string]interface{}) error
+	ExpireEnrollments(now time.Time) (int64, error)
+	FindById(enrollmentId uint) (*models.Enrollment, error)
+	CountGrants(courseId, grantedBy uint, source string) (int64, error)
+	GetGrants(courseId, grantedBy uint, source string) ([]models.Enrollment, error)
+	Transaction(fn func(txRepo EnrollmentRepository) error) error
+	LockCourse(courseId uint) error
+	LockById(enrollmentId uint) (*models.Enrollment, error)
 }
 
 type InstructorRepository interface {
@@ -112,6 +121,7 @@ type InstructorRepository interface {
 	FindCourseById(courseId uint) (*models.Course, error)
 	FindCourseByIdAndInstructor(courseId, instructorId uint) (*models.Course, error)
 	UpdateCourse(courseId uint, updates map[string]interface{}) error
+	UpdateCourseThumbnail(courseId uint, thumbnailURL string, variants map[string]string) error
 	DeleteCourse(courseId uint) error
 	CountEnrollmentsByCourse(courseId uint) (int64, error)
 	GetCourseStudents(courseId uint, offset, limit int, filters map[string]interface{}, orderBy, sortBy string) ([]models.Enrollment, int, error)
@@ -128,11 +138,185 @@ type InstructorRepository interface {
 	BeginTransaction() *gorm.DB
 }
 
+type CourseRevisionRepository interface {
+	Create(revision *models.CourseRevision) error
+	FindDraftByCourse(courseId uint) (*models.CourseRevision, error)
+	FindByIdAndCourse(revisionId, courseId uint) (*models.CourseRevision, error)
+	GetCourseRevisions(courseId uint, offset, limit int) ([]models.CourseRevision, int, error)
+	Update(revisionId uint, updates map[string]interface{}) error
+	UpdateSnapshot(revisionId uint, snapshot models.CourseSnapshot) error
+	Delete(revisionId uint) error
+	GetAllCourseLessons(courseId uint) ([]models.Lesson, error)
+	IsStorageReferenced(courseId uint, key string) (bool, error)
+}
+
+type CourseModerationRepository interface {
+	Create(moderation *models.CourseModeration) error
+	FindById(moderationId uint) (*models.CourseModeration, error)
+	FindPendingByCourse(courseId uint) (*models.CourseModeration, error)
+	GetCourseModerations(courseId uint) ([]models.CourseModeration, error)
+	GetModerationQueue(offset, limit int, filters map[string]interface{}) ([]models.CourseModeration, int, error)
+	Update(moderationId uint, updates map[string]interface{}) error
+	GetCourseLessons(courseId uint) ([]models.Lesson, error)
+	BeginTransaction() *gorm.DB
+}
+
+type PublishScheduleRepository interface {
+	PublishDueCourses(now time.Time) (int64, error)
+	UnpublishDueCourses(now time.Time) (int64, error)
+	PublishDueLessons(now time.Time) (int64, error)
+	UnpublishDueLessons(now time.Time) (int64, error)
+	DueLessonCourseIds(now time.Time) ([]uint, error)
+}
+
+type CoursePrerequisiteRepository interface {
+	GetPrerequisites(courseId uint) ([]models.CoursePrerequisite, error)
+	GetPrerequisiteIds(courseId uint) ([]uint, error)
+	ReplacePrerequisites(courseId uint, prerequisiteIds []uint) error
+	GetCompletedCourseIds(userId uint, courseIds []uint) (map[uint]bool, error)
+}
+
+type LearningPathRepository interface {
+	GetLearningPaths(offset, limit int, filters map[string]interface{}) ([]models.LearningPath, int, error)
+	FindById(pathId uint) (*models.LearningPath, error)
+	FindBySlug(slug string) (*models.LearningPath, error)
+	ExistsBySlug(slug string, excludeId uint) bool
+	Create(path *models.LearningPath) error
+	Update(pathId uint, updates map[string]interface{}) error
+	Delete(pathId uint) error
+	ReplaceCourses(pathId uint, courseIds []uint) error
+	FindUserLearningPath(userId, pathId uint) (*models.UserLearningPath, error)
+	GetUserLearningPaths(userId uint) ([]models.UserLearningPath, error)
+	CreateUserLearningPath(userPath *models.UserLearningPath) error
+	UpdateUserLearningPath(userPathId uint, updates map[string]interface{}) error
+	DeleteUserLearningPath(userPathId uint) error
+	GetUserEnrollments(userId uint, courseIds []uint) ([]models.Enrollment, error)
+}
+
+type QuizRepository interface {
+	FindByLessonId(lessonId uint) (*models.Quiz, error)
+	FindLessonById(lessonId uint) (*models.Lesson, error)
+	CreateQuiz(quiz *models.Quiz) error
+	UpdateQuiz(quizId uint, updates map[string]interface{}) error
+	FindQuestionByIdAndQuiz(questionId, quizId uint) (*models.QuizQuestion, error)
+	GetMaxQuestionOrder(quizId uint) (int, error)
+	CreateQuestion(question *models.QuizQuestion) error
+	SaveQuestion(question *models.QuizQuestion) error
+	DeleteQuestion(questionId uint) error
+	CreateAttempt(attempt *models.QuizAttempt) error
+	FindAttemptById(attemptId uint) (*models.QuizAttempt, error)
+	FindInProgressAttempt(userId, quizId uint) (*models.QuizAttempt, error)
+	GetUserAttempts(userId, quizId uint) ([]models.QuizAttempt, error)
+	CountUserAttempts(userId, quizId uint) (int, error)
+	SubmitAttempt(attempt *models.QuizAttempt) (bool, error)
+	HasPassedAttempt(userId, lessonId uint) (bool, error)
+	GetCourseAttempts(userId, courseId uint) ([]models.QuizAttempt, error)
+}
+
+type AssignmentRepository interface {
+	FindByLessonId(lessonId uint) (*models.Assignment, error)
+	FindLessonById(lessonId uint) (*models.Lesson, error)
+	CreateAssignment(assignment *models.Assignment) error
+	UpdateAssignment(assignmentId uint, updates map[string]interface{}) error
+	UpdateRubric(assignment *models.Assignment) error
+	CreateSubmission(submission *models.AssignmentSubmission) error
+	FindSubmissionById(submissionId uint) (*models.AssignmentSubmission, error)
+	GetUserSubmissions(userId, assignmentId uint) ([]models.AssignmentSubmission, error)
+	CountUserSubmissions(userId, assignmentId uint) (int, error)
+	SupersedePendingSubmissions(userId, assignmentId uint) error
+	GetGradingQueue(instructorId uint, offset, limit int, filters map[string]interface{}) ([]models.AssignmentSubmission, int, error)
+	GradeSubmission(submission *models.AssignmentSubmission) error
+	HasPassedSubmission(userId, lessonId uint) (bool, error)
+	GetCourseSubmissions(userId, courseId uint) ([]models.AssignmentSubmission, error)
+}
+
+type LessonResourceRepository interface {
+	GetLessonResources(lessonId uint) ([]models.LessonResource, error)
+	FindById(resourceId uint) (*models.LessonResource, error)
+	FindLessonById(lessonId uint) (*models.Lesson, error)
+	GetMaxResourceOrder(lessonId uint) (int, error)
+	CreateResource(resource *models.LessonResource) error
+	UpdateResource(resourceId uint, updates map[string]interface{}) error
+	DeleteResource(resourceId uint) error
+	IncrementDownloadCount(resourceId uint) error
+}
+
+type VideoUploadRepository interface {
+	CreateUpload(upload *models.VideoUpload) error
+	FindById(uploadId string) (*models.VideoUpload, error)
+	GetLessonUploads(lessonId uint, limit int) ([]models.VideoUpload, error)
+	LockUpload(uploadId string) (*models.VideoUpload, error)
+	SaveUpload(upload *models.VideoUpload) error
+	GetAbandonedUploads(now time.Time, limit int) ([]models.VideoUpload, error)
+	Transaction(fn func(txRepo VideoUploadRepository) error) error
+}
+
+type LessonSubtitleRepository interface {
+	GetLessonSubtitles(lessonId uint) ([]models.LessonSubtitle, error)
+	FindById(subtitleId uint) (*models.LessonSubtitle, error)
+	FindByLessonAndLanguage(lessonId uint, language string) (*models.LessonSubtitle, error)
+	SaveSubtitle(subtitle *models.LessonSubtitle, cues []models.LessonSubtitleCue) error
+	UpdateSubtitle(subtitle *models.LessonSubtitle, updates map[string]interface{}) error
+	DeleteSubtitle(subtitleId uint) error
+	SearchCues(subtitleId uint, query string, offset, limit int) ([]models.LessonSubtitleCue, int, error)
+}
+
+type ScormRepository interface {
+	FindPackageByLessonId(lessonId uint) (*models.ScormPackage, error)
+	SavePackage(pkg *models.ScormPackage) error
+	DeletePackage(lessonId uint) error
+	FindAttempt(userId, lessonId uint) (*models.ScormAttempt, error)
+	SaveAttempt(attempt *models.ScormAttempt) error
+	ResetAttemptData(lessonId uint) error
+	HasCompletedAttempt(userId, lessonId uint) (bool, error)
+}
+
+type CertificateRepository interface {
+	Create(certificate *models.Certificate) error
+	FindBySerial(serial string) (*models.Certificate, error)
+	FindByEnrollmentId(enrollmentId uint) (*models.Certificate, error)
+	GetUserCertificates(userId uint) ([]models.Certificate, error)
+	FindEnrollmentForIssue(enrollmentId uint) (*models.Enrollment, error)
+	GetUncertifiedEnrollmentIds(userId uint) ([]uint, error)
+	ReissueForLearner(userId uint, learnerName string) (int64, error)
+	FindEnrollment(enrollmentId uint) (*models.Enrollment, error)
+	IsPurchaseRefunded(userId, courseId uint) (bool, error)
+}
+
 type ProgressRepository interface {
 	CountCompletedLessons(userId, courseId uint) (int, error)
 	GetCourseProgress(userId, courseId uint) ([]models.Progress, error)
 	UpdateProgress(progress *models.Progress) error
 	GetLessonProgress(userId, lessonId uint) (*models.Progress, error)
+	GetLessonProgressList(lessonId uint) ([]models.Progress, error)
+	FindSyncEventKeys(userId uint, keys []string) ([]string, error)
+	LockLessonProgress(tx *gorm.DB, userId, courseId, lessonId uint) (*models.Progress, error)
+	BeginTransaction() *gorm.DB
+}
+
+type LearningActivityRepository interface {
+	RecordActivity(userId uint, date time.Time, watchSeconds, lessonsCompleted int) error
+	GetActivities(userId uint, from, to time.Time) ([]models.LearningActivity, error)
+	GetActiveDates(userId uint) ([]time.Time, error)
+	GetTotals(userId uint) (int, int, error)
+	FindGoal(userId uint) (*models.LearningGoal, error)
+	SaveGoal(goal *models.LearningGoal) error
+	DeleteGoal(userId uint) (bool, error)
+	FindNotificationPreference(userId uint) (*models.NotificationPreference, error)
+	SaveNotificationPreference(preference *models.NotificationPreference) error
+	GetInactiveEnrollments(cutoff time.Time, limit int) ([]models.Enrollment, error)
+	ClaimReminders(enrollmentIds []uint, cutoff, now time.Time) ([]uint, error)
+}
+
+type ProgressRecalculationRepository interface {
+	Enqueue(courseId uint, reason string) error
+	ClaimNext(staleAfter time.Duration) (*models.ProgressRecalculation, error)
+	UpdateJob(jobId uint, updates map[string]interface{}) error
+	DeleteFinishedBefore(before time.Time) (int64, error)
+	CountCourseLessons(courseId uint) (int, error)
+	GetEnrollmentBatch(courseId, afterId uint, limit int) ([]models.Enrollment, error)
+	CountCompletedLessonsByUsers(courseId uint, userIds []uint) (map[uint]int, error)
+	UpdateEnrollment(enrollmentId uint, updates map[string]interface{}) error
 }
 
 type AnalyticsRepository interface {
@@ -148,3 +332,19 @@ type AdminAnalyticsRepository interface {
 	GetAdminUsersAnalytics(req *dto.AdminUsersAnalyticsRequest) (*dto.AdminUsersAnalyticsResponse, error)
 	GetAdminCoursesAnalytics(req *dto.AdminCoursesAnalyticsRequest) (*dto.AdminCoursesAnalyticsResponse, error)
 }
+
+type LessonNoteRepository interface {
+	FindLessonById(lessonId uint) (*models.Lesson, error)
+	CreateNote(note *models.LessonNote) error
+	FindNote(noteId, userId uint) (*models.LessonNote, error)
+	UpdateNote(noteId uint, updates map[string]interface{}) error
+	DeleteNote(noteId uint) error
+	GetLessonNotes(userId, lessonId uint) ([]models.LessonNote, error)
+	CountLessonNotes(userId, lessonId uint) (int, error)
+	SearchNotes(userId uint, query string, filters map[string]interface{}, offset, limit int) ([]models.LessonNote, int, error)
+	GetCourseNotes(userId, courseId uint) ([]models.LessonNote, error)
+	AddBookmark(bookmark *models.LessonBookmark) error
+	DeleteBookmark(userId, lessonId uint) (bool, error)
+	IsBookmarked(userId, lessonId uint) (bool, error)
+	GetBookmarks(userId uint, filters map[string]interface{}) ([]models.LessonBookmark, error)
+}
@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"errors"
+	"lms/src/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type DBLearningActivityRepository struct {
+	db *gorm.DB
+}
+
+func NewDBLearningActivityRepository(db *gorm.DB) LearningActivityRepository {
+	return &DBLearningActivityRepository{
+		db: db,
+	}
+}
+
+// RecordActivity cộng dồn hoạt động vào bản ghi của ngày (upsert theo user_id, activity_date)
+func (ar *DBLearningActivityRepository) RecordActivity(userId uint, date time.Time, watchSeconds, lessonsCompleted int) error {
+	activity := &models.LearningActivity{
+		UserId:           userId,
+		ActivityDate:     date,
+		WatchSeconds:     watchSeconds,
+		LessonsCompleted: lessonsCompleted,
+	}
+
+	return ar.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "activity_date"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"watch_seconds":     gorm.Expr("learning_activities.watch_seconds + EXCLUDED.watch_seconds"),
+			"lessons_completed": gorm.Expr("learning_activities.lessons_completed + EXCLUDED.lessons_completed"),
+			"updated_at":        time.Now(),
+		}),
+	}).Create(activity).Error
+}
+
+func (ar *DBLearningActivityRepository) GetActivities(userId uint, from, to time.Time) ([]models.LearningActivity, error) {
+	var activities []models.LearningActivity
+	err := ar.db.Where("user_id = ? AND activity_date BETWEEN ? AND ?", userId, from, to).
+		Order("activity_date ASC").
+		Find(&activities).Error
+	return activities, err
+}
+
+// GetActiveDates lấy các ngày có hoạt động (mới nhất trước) để tính streak
+func (ar *DBLearningActivityRepository) GetActiveDates(userId uint) ([]time.Time, error) {
+	var dates []time.Time
+	err := ar.db.Model(&models.LearningActivity{}).
+		Where("user_id = ? AND (watch_seconds > 0 OR lessons_completed > 0)", userId).
+		Order("activity_date DESC").
+		Pluck("activity_date", &dates).Error
+	return dates, err
+}
+
+func (ar *DBLearningActivityRepository) GetTotals(userId uint) (int, int, error) {
+	var totals struct {
+		WatchSeconds     int
+		LessonsCompleted int
+	}
+	err := ar.db.Model(&models.LearningActivity{}).
+		Select("COALESCE(SUM(watch_seconds), 0) AS watch_seconds, COALESCE(SUM(lessons_completed), 0) AS lessons_completed").
+		Where("user_id = ?", userId).
+		Scan(&totals).Error
+	return totals.WatchSeconds, totals.LessonsCompleted, err
+}
+
+func (ar *DBLearningActivityRepository) FindGoal(userId uint) (*models.LearningGoal, error) {
+	var goal models.LearningGoal
+	err := ar.db.Where("user_id = ?", userId).First(&goal).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &goal, nil
+}
+
+func (ar *DBLearningActivityRepository) SaveGoal(goal *models.LearningGoal) error {
+	return ar.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"goal_type", "target", "updated_at"}),
+	}).Create(goal).Error
+}
+
+func (ar *DBLearningActivityRepository) DeleteGoal(userId uint) (bool, error) {
+	result := ar.db.Where("user_id = ?", userId).Delete(&models.LearningGoal{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (ar *DBLearningActivityRepository) FindNotificationPreference(userId uint) (*models.NotificationPreference, error) {
+	var preference models.NotificationPreference
+	err := ar.db.Where("user_id = ?", userId).First(&preference).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &preference, nil
+}
+
+func (ar *DBLearningActivityRepository) SaveNotificationPreference(preference *models.NotificationPreference) error {
+	return ar.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"learning_reminders", "updated_at"}),
+	}).Create(preference).Error
+}
+
+// inactiveEnrollmentCondition - enrollment active không truy cập từ trước cutoff và chưa được nhắc trong đợt vắng mặt này
+const inactiveEnrollmentCondition = `enrollments.status = 'active' AND enrollments.deleted_at IS NULL
+	AND (enrollments.expires_at IS NULL OR enrollments.expires_at > NOW())
+	AND COALESCE(enrollments.last_accessed_at, enrollments.enrolled_at) <= ?
+	AND (enrollments.last_reminder_at IS NULL OR enrollments.last_reminder_at < COALESCE(enrollments.last_accessed_at, enrollments.enrolled_at))`
+
+// GetInactiveEnrollments lấy enrollment cần nhắc học của user đang hoạt động và không tắt email nhắc học
+func (ar *DBLearningActivityRepository) GetInactiveEnrollments(cutoff time.Time, limit int) ([]models.Enrollment, error) {
+	var enrollments []models.Enrollment
+	err := ar.db.
+		Joins("JOIN users ON users.id = enrollments.user_id AND users.status = ? AND users.deleted_at IS NULL", "active").
+		Joins("LEFT JOIN notification_preferences ON notification_preferences.user_id = enrollments.user_id").
+		Where(inactiveEnrollmentCondition, cutoff).
+		Where("notification_preferences.id IS NULL OR notification_preferences.learning_reminders = ?", true).
+		Preload("User").
+		Preload("Course").
+		Order("enrollments.user_id ASC, enrollments.id ASC").
+		Limit(limit).
+		Find(&enrollments).Error
+	return enrollments, err
+}
+
+// ClaimReminders đánh dấu đã nhắc, trả về các enrollment thực sự được đánh dấu (replica khác đã nhận thì bỏ qua)
+func (ar *DBLearningActivityRepository) ClaimReminders(enrollmentIds []uint, cutoff, now time.Time) ([]uint, error) {
+	var claimedIds []uint
+	err := ar.db.Raw(`
+		UPDATE enrollments SET last_reminder_at = ?
+		WHERE id IN ? AND `+inactiveEnrollmentCondition+`
+		RETURNING id`, now, enrollmentIds, cutoff).
+		Scan(&claimedIds).Error
+	return claimedIds, err
+}
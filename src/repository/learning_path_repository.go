@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"lms/src/models"
+
+	"gorm.io/gorm"
+)
+
+type DBLearningPathRepository struct {
+	db *gorm.DB
+}
+
+func NewDBLearningPathRepository(db *gorm.DB) LearningPathRepository {
+	return &DBLearningPathRepository{
+		db: db,
+	}
+}
+
+func (lr *DBLearningPathRepository) GetLearningPaths(offset, limit int, filters map[string]interface{}) ([]models.LearningPath, int, error) {
+	var paths []models.LearningPath
+	var total int64
+
+	query := lr.db.Model(&models.LearningPath{})
+
+	if isPublished, ok := filters["is_published"]; ok {
+		query = query.Where("is_published = ?", isPublished)
+	}
+
+	if search, ok := filters["search"]; ok {
+		searchPattern := "%" + search.(string) + "%"
+		query = query.Where("title ILIKE ? OR short_desc ILIKE ?", searchPattern, searchPattern)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Preload("Courses", func(db *gorm.DB) *gorm.DB {
+		return db.Order("course_order ASC")
+	}).
+		Order("created_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&paths).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return paths, int(total), nil
+}
+
+func (lr *DBLearningPathRepository) FindById(pathId uint) (*models.LearningPath, error) {
+	var path models.LearningPath
+	if err := lr.preloadCourses(lr.db).Where("id = ?", pathId).First(&path).Error; err != nil {
+		return nil, err
+	}
+	return &path, nil
+}
+
+func (lr *DBLearningPathRepository) FindBySlug(slug string) (*models.LearningPath, error) {
+	var path models.LearningPath
+	if err := lr.preloadCourses(lr.db).Where("slug = ?", slug).First(&path).Error; err != nil {
+		return nil, err
+	}
+	return &path, nil
+}
+
+func (lr *DBLearningPathRepository) ExistsBySlug(slug string, excludeId uint) bool {
+	var count int64
+	query := lr.db.Unscoped().Model(&models.LearningPath{}).Where("slug = ?", slug)
+	if excludeId != 0 {
+		query = query.Where("id <> ?", excludeId)
+	}
+	query.Count(&count)
+	return count > 0
+}
+
+func (lr *DBLearningPathRepository) Create(path *models.LearningPath) error {
+	return lr.db.Create(path).Error
+}
+
+func (lr *DBLearningPathRepository) Update(pathId uint, updates map[string]interface{}) error {
+	return lr.db.Model(&models.LearningPath{}).Where("id = ?", pathId).Updates(updates).Error
+}
+
+func (lr *DBLearningPathRepository) Delete(pathId uint) error {
+	return lr.db.Delete(&models.LearningPath{}, pathId).Error
+}
+
+// ReplaceCourses thay toàn bộ danh sách courses (theo thứ tự) của learning path
+func (lr *DBLearningPathRepository) ReplaceCourses(pathId uint, courseIds []uint) error {
+	return lr.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("learning_path_id = ?", pathId).Delete(&models.LearningPathCourse{}).Error; err != nil {
+			return err
+		}
+
+		for i, courseId := range courseIds {
+			pathCourse := &models.LearningPathCourse{
+				LearningPathId: pathId,
+				CourseId:       courseId,
+				CourseOrder:    i + 1,
+			}
+			if err := tx.Create(pathCourse).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// FindUserLearningPath trả về nil nếu user chưa theo learning path
+func (lr *DBLearningPathRepository) FindUserLearningPath(userId, pathId uint) (*models.UserLearningPath, error) {
+	var userPath models.UserLearningPath
+	err := lr.db.Where("user_id = ? AND learning_path_id = ?", userId, pathId).First(&userPath).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &userPath, nil
+}
+
+func (lr *DBLearningPathRepository) GetUserLearningPaths(userId uint) ([]models.UserLearningPath, error) {
+	var userPaths []models.UserLearningPath
+	err := lr.db.Preload("LearningPath").
+		Preload("LearningPath.Courses", func(db *gorm.DB) *gorm.DB {
+			return db.Order("course_order ASC")
+		}).
+		Preload("LearningPath.Courses.Course").
+		Where("user_id = ?", userId).
+		Order("started_at DESC").
+		Find(&userPaths).Error
+
+	return userPaths, err
+}
+
+func (lr *DBLearningPathRepository) CreateUserLearningPath(userPath *models.UserLearningPath) error {
+	return lr.db.Create(userPath).Error
+}
+
+func (lr *DBLearningPathRepository) UpdateUserLearningPath(userPathId uint, updates map[string]interface{}) error {
+	return lr.db.Model(&models.UserLearningPath{}).Where("id = ?", userPathId).Updates(updates).Error
+}
+
+func (lr *DBLearningPathRepository) DeleteUserLearningPath(userPathId uint) error {
+	return lr.db.Delete(&models.UserLearningPath{}, userPathId).Error
+}
+
+// GetUserEnrollments lấy enrollments của user cho các courses trong learning path
+func (lr *DBLearningPathRepository) GetUserEnrollments(userId uint, courseIds []uint) ([]models.Enrollment, error) {
+	var enrollments []models.Enrollment
+	if len(courseIds) == 0 {
+		return enrollments, nil
+	}
+
+	err := lr.db.Where("user_id = ? AND course_id IN ? AND deleted_at IS NULL", userId, courseIds).
+		Find(&enrollments).Error
+
+	return enrollments, err
+}
+
+func (lr *DBLearningPathRepository) preloadCourses(db *gorm.DB) *gorm.DB {
+	return db.Preload("Courses", func(db *gorm.DB) *gorm.DB {
+		return db.Order("course_order ASC")
+	}).Preload("Courses.Course").Preload("Courses.Course.Instructor")
+}
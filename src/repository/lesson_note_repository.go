@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"errors"
+	"lms/src/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type DBLessonNoteRepository struct {
+	db *gorm.DB
+}
+
+func NewDBLessonNoteRepository(db *gorm.DB) LessonNoteRepository {
+	return &DBLessonNoteRepository{
+		db: db,
+	}
+}
+
+func (nr *DBLessonNoteRepository) FindLessonById(lessonId uint) (*models.Lesson, error) {
+	var lesson models.Lesson
+	if err := nr.db.Where("id = ?", lessonId).First(&lesson).Error; err != nil {
+		return nil, err
+	}
+	return &lesson, nil
+}
+
+func (nr *DBLessonNoteRepository) CreateNote(note *models.LessonNote) error {
+	return nr.db.Create(note).Error
+}
+
+// FindNote lấy ghi chú của user, trả về nil nếu không tồn tại hoặc thuộc user khác
+func (nr *DBLessonNoteRepository) FindNote(noteId, userId uint) (*models.LessonNote, error) {
+	var note models.LessonNote
+	err := nr.db.Where("id = ? AND user_id = ?", noteId, userId).First(&note).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &note, nil
+}
+
+func (nr *DBLessonNoteRepository) UpdateNote(noteId uint, updates map[string]interface{}) error {
+	return nr.db.Model(&models.LessonNote{}).Where("id = ?", noteId).Updates(updates).Error
+}
+
+func (nr *DBLessonNoteRepository) DeleteNote(noteId uint) error {
+	return nr.db.Delete(&models.LessonNote{}, noteId).Error
+}
+
+// GetLessonNotes - ghi chú theo thứ tự trong video, ghi chú không gắn timestamp xếp cuối
+func (nr *DBLessonNoteRepository) GetLessonNotes(userId, lessonId uint) ([]models.LessonNote, error) {
+	var notes []models.LessonNote
+	err := nr.db.Where("user_id = ? AND lesson_id = ?", userId, lessonId).
+		Order("video_timestamp ASC NULLS LAST, created_at ASC").
+		Find(&notes).Error
+	return notes, err
+}
+
+func (nr *DBLessonNoteRepository) CountLessonNotes(userId, lessonId uint) (int, error) {
+	var count int64
+	err := nr.db.Model(&models.LessonNote{}).
+		Where("user_id = ? AND lesson_id = ?", userId, lessonId).
+		Count(&count).Error
+	return int(count), err
+}
+
+// SearchNotes tìm ghi chú của user trên mọi course (không phân biệt hoa thường), mới nhất trước
+func (nr *DBLessonNoteRepository) SearchNotes(userId uint, query string, filters map[string]interface{}, offset, limit int) ([]models.LessonNote, int, error) {
+	var notes []models.LessonNote
+	var total int64
+
+	db := nr.db.Model(&models.LessonNote{}).Where("user_id = ?", userId)
+	if query != "" {
+		db = db.Where("content ILIKE ?", "%"+escapeLike(query)+"%")
+	}
+	if courseId, ok := filters["course_id"]; ok {
+		db = db.Where("course_id = ?", courseId)
+	}
+	if lessonId, ok := filters["lesson_id"]; ok {
+		db = db.Where("lesson_id = ?", lessonId)
+	}
+
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Ghi chú vẫn thuộc về user khi lesson bị xóa nên preload cả lesson đã xóa
+	err := db.Preload("Lesson", unscopedPreload).Preload("Course").
+		Order("updated_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&notes).Error
+	return notes, int(total), err
+}
+
+// GetCourseNotes - toàn bộ ghi chú của user trong course, theo thứ tự lesson rồi thứ tự trong video (dùng để export)
+func (nr *DBLessonNoteRepository) GetCourseNotes(userId, courseId uint) ([]models.LessonNote, error) {
+	var notes []models.LessonNote
+	err := nr.db.Preload("Lesson", unscopedPreload).
+		Joins("JOIN lessons ON lessons.id = lesson_notes.lesson_id").
+		Where("lesson_notes.user_id = ? AND lesson_notes.course_id = ?", userId, courseId).
+		Order("lessons.lesson_order ASC, lessons.id ASC, lesson_notes.video_timestamp ASC NULLS LAST, lesson_notes.created_at ASC").
+		Find(&notes).Error
+	return notes, err
+}
+
+// AddBookmark - bookmark đã tồn tại thì bỏ qua
+func (nr *DBLessonNoteRepository) AddBookmark(bookmark *models.LessonBookmark) error {
+	return nr.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "lesson_id"}},
+		DoNothing: true,
+	}).Create(bookmark).Error
+}
+
+func (nr *DBLessonNoteRepository) DeleteBookmark(userId, lessonId uint) (bool, error) {
+	result := nr.db.Where("user_id = ? AND lesson_id = ?", userId, lessonId).Delete(&models.LessonBookmark{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (nr *DBLessonNoteRepository) IsBookmarked(userId, lessonId uint) (bool, error) {
+	var count int64
+	err := nr.db.Model(&models.LessonBookmark{}).
+		Where("user_id = ? AND lesson_id = ?", userId, lessonId).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// GetBookmarks - bookmark của lesson đã bị xóa không được trả về
+func (nr *DBLessonNoteRepository) GetBookmarks(userId uint, filters map[string]interface{}) ([]models.LessonBookmark, error) {
+	var bookmarks []models.LessonBookmark
+	db := nr.db.Joins("JOIN lessons ON lessons.id = lesson_bookmarks.lesson_id AND lessons.deleted_at IS NULL").
+		Where("lesson_bookmarks.user_id = ?", userId)
+	if courseId, ok := filters["course_id"]; ok {
+		db = db.Where("lesson_bookmarks.course_id = ?", courseId)
+	}
+
+	err := db.Preload("Lesson").Preload("Course").
+		Order("lesson_bookmarks.created_at DESC").
+		Find(&bookmarks).Error
+	return bookmarks, err
+}
+
+func unscopedPreload(db *gorm.DB) *gorm.DB {
+	return db.Unscoped()
+}
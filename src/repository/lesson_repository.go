@@ -30,12 +30,13 @@ func (lr *DBLessonRepository) GetCourseLessons(courseId uint) ([]models.Lesson,
 	return lessons, nil
 }
 
+// CheckUserEnrollment - user còn quyền xem lessons (enrollment active/completed chưa hết hạn)
 func (lr *DBLessonRepository) CheckUserEnrollment(userId, courseId uint) (bool, error) {
 	var count int64
 
 	err := lr.db.Model(&models.Enrollment{}).
-		Where("user_id = ? AND course_id = ? AND status = ? AND deleted_at IS NULL",
-			userId, courseId, "active").
+		Where("user_id = ? AND course_id = ? AND deleted_at IS NULL", userId, courseId).
+		Where(accessibleEnrollmentCondition).
 		Count(&count).Error
 
 	if err != nil {
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"lms/src/models"
+
+	"gorm.io/gorm"
+)
+
+type DBLessonResourceRepository struct {
+	db *gorm.DB
+}
+
+func NewDBLessonResourceRepository(db *gorm.DB) LessonResourceRepository {
+	return &DBLessonResourceRepository{
+		db: db,
+	}
+}
+
+func (rr *DBLessonResourceRepository) GetLessonResources(lessonId uint) ([]models.LessonResource, error) {
+	var resources []models.LessonResource
+	err := rr.db.Where("lesson_id = ?", lessonId).
+		Order("resource_order ASC, id ASC").
+		Find(&resources).Error
+	return resources, err
+}
+
+func (rr *DBLessonResourceRepository) FindById(resourceId uint) (*models.LessonResource, error) {
+	var resource models.LessonResource
+	if err := rr.db.Where("id = ?", resourceId).First(&resource).Error; err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
+func (rr *DBLessonResourceRepository) FindLessonById(lessonId uint) (*models.Lesson, error) {
+	var lesson models.Lesson
+	if err := rr.db.Where("id = ?", lessonId).First(&lesson).Error; err != nil {
+		return nil, err
+	}
+	return &lesson, nil
+}
+
+func (rr *DBLessonResourceRepository) GetMaxResourceOrder(lessonId uint) (int, error) {
+	var maxOrder int
+	err := rr.db.Model(&models.LessonResource{}).
+		Where("lesson_id = ?", lessonId).
+		Select("COALESCE(MAX(resource_order), 0)").
+		Scan(&maxOrder).Error
+	return maxOrder, err
+}
+
+func (rr *DBLessonResourceRepository) CreateResource(resource *models.LessonResource) error {
+	return rr.db.Create(resource).Error
+}
+
+func (rr *DBLessonResourceRepository) UpdateResource(resourceId uint, updates map[string]interface{}) error {
+	return rr.db.Model(&models.LessonResource{}).Where("id = ?", resourceId).Updates(updates).Error
+}
+
+func (rr *DBLessonResourceRepository) DeleteResource(resourceId uint) error {
+	return rr.db.Delete(&models.LessonResource{}, resourceId).Error
+}
+
+func (rr *DBLessonResourceRepository) IncrementDownloadCount(resourceId uint) error {
+	return rr.db.Model(&models.LessonResource{}).
+		Where("id = ?", resourceId).
+		UpdateColumn("download_count", gorm.Expr("download_count + 1")).Error
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"errors"
+	"lms/src/models"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+type DBLessonSubtitleRepository struct {
+	db *gorm.DB
+}
+
+func NewDBLessonSubtitleRepository(db *gorm.DB) LessonSubtitleRepository {
+	return &DBLessonSubtitleRepository{
+		db: db,
+	}
+}
+
+func (sr *DBLessonSubtitleRepository) GetLessonSubtitles(lessonId uint) ([]models.LessonSubtitle, error) {
+	var subtitles []models.LessonSubtitle
+	err := sr.db.Where("lesson_id = ?", lessonId).
+		Order("is_default DESC, language ASC").
+		Find(&subtitles).Error
+	return subtitles, err
+}
+
+func (sr *DBLessonSubtitleRepository) FindById(subtitleId uint) (*models.LessonSubtitle, error) {
+	var subtitle models.LessonSubtitle
+	if err := sr.db.Where("id = ?", subtitleId).First(&subtitle).Error; err != nil {
+		return nil, err
+	}
+	return &subtitle, nil
+}
+
+// FindByLessonAndLanguage trả về nil, nil nếu lesson chưa có track cho ngôn ngữ này
+func (sr *DBLessonSubtitleRepository) FindByLessonAndLanguage(lessonId uint, language string) (*models.LessonSubtitle, error) {
+	var subtitle models.LessonSubtitle
+	err := sr.db.Where("lesson_id = ? AND language = ?", lessonId, language).First(&subtitle).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &subtitle, nil
+}
+
+// SaveSubtitle tạo track mới cùng toàn bộ cue trong một transaction, track cũ cùng ngôn ngữ bị thay (soft delete).
+// Cue của track cũ được giữ lại để revision của course khôi phục được track
+func (sr *DBLessonSubtitleRepository) SaveSubtitle(subtitle *models.LessonSubtitle, cues []models.LessonSubtitleCue) error {
+	return sr.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("lesson_id = ? AND language = ?", subtitle.LessonId, subtitle.Language).
+			Delete(&models.LessonSubtitle{}).Error; err != nil {
+			return err
+		}
+
+		if subtitle.IsDefault {
+			if err := clearDefaultSubtitle(tx, subtitle.LessonId, subtitle.Id); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Create(subtitle).Error; err != nil {
+			return err
+		}
+
+		for i := range cues {
+			cues[i].SubtitleId = subtitle.Id
+		}
+		return tx.CreateInBatches(cues, 500).Error
+	})
+}
+
+func (sr *DBLessonSubtitleRepository) UpdateSubtitle(subtitle *models.LessonSubtitle, updates map[string]interface{}) error {
+	return sr.db.Transaction(func(tx *gorm.DB) error {
+		if isDefault, ok := updates["is_default"].(bool); ok && isDefault {
+			if err := clearDefaultSubtitle(tx, subtitle.LessonId, subtitle.Id); err != nil {
+				return err
+			}
+		}
+		return tx.Model(&models.LessonSubtitle{}).Where("id = ?", subtitle.Id).Updates(updates).Error
+	})
+}
+
+// DeleteSubtitle soft delete track, cue được giữ lại như khi thay track
+func (sr *DBLessonSubtitleRepository) DeleteSubtitle(subtitleId uint) error {
+	return sr.db.Delete(&models.LessonSubtitle{}, subtitleId).Error
+}
+
+// SearchCues tìm cue theo text (không phân biệt hoa thường), query rỗng thì trả về toàn bộ transcript
+func (sr *DBLessonSubtitleRepository) SearchCues(subtitleId uint, query string, offset, limit int) ([]models.LessonSubtitleCue, int, error) {
+	var cues []models.LessonSubtitleCue
+	var total int64
+
+	db := sr.db.Model(&models.LessonSubtitleCue{}).Where("subtitle_id = ?", subtitleId)
+	if query != "" {
+		db = db.Where("text ILIKE ?", "%"+escapeLike(query)+"%")
+	}
+
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := db.Order("cue_index ASC").Offset(offset).Limit(limit).Find(&cues).Error
+	return cues, int(total), err
+}
+
+func clearDefaultSubtitle(tx *gorm.DB, lessonId, exceptId uint) error {
+	return tx.Model(&models.LessonSubtitle{}).
+		Where("lesson_id = ? AND id <> ? AND is_default = ?", lessonId, exceptId, true).
+		Update("is_default", false).Error
+}
+
+// escapeLike escape ký tự đặc biệt của LIKE để tìm kiếm đúng nguyên văn
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"errors"
+	"lms/src/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type DBProgressRecalculationRepository struct {
+	db *gorm.DB
+}
+
+func NewDBProgressRecalculationRepository(db *gorm.DB) ProgressRecalculationRepository {
+	return &DBProgressRecalculationRepository{
+		db: db,
+	}
+}
+
+// Enqueue tạo job cho course, bỏ qua nếu course đã có job đang chờ (job đó sẽ đọc nội dung mới nhất khi chạy)
+func (rr *DBProgressRecalculationRepository) Enqueue(courseId uint, reason string) error {
+	var count int64
+	if err := rr.db.Model(&models.ProgressRecalculation{}).
+		Where("course_id = ? AND status = ? AND last_enrollment_id = 0", courseId, "pending").
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	return rr.db.Create(&models.ProgressRecalculation{
+		CourseId: courseId,
+		Reason:   reason,
+		Status:   "pending",
+	}).Error
+}
+
+// ClaimNext chuyển một job pending (hoặc running nhưng không cập nhật quá staleAfter do process chết) sang running.
+// FOR UPDATE SKIP LOCKED để nhiều replica không nhận cùng một job; trả về nil, nil nếu không có job
+func (rr *DBProgressRecalculationRepository) ClaimNext(staleAfter time.Duration) (*models.ProgressRecalculation, error) {
+	var job models.ProgressRecalculation
+	err := rr.db.Raw(`
+		UPDATE progress_recalculations
+		SET status = 'running', attempts = attempts + 1, started_at = COALESCE(started_at, NOW()), updated_at = NOW()
+		WHERE id = (
+			SELECT id FROM progress_recalculations
+			WHERE status = 'pending' OR (status = 'running' AND updated_at < ?)
+			ORDER BY id
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`, time.Now().Add(-staleAfter)).
+		Scan(&job).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if job.Id == 0 {
+		return nil, nil
+	}
+	return &job, nil
+}
+
+func (rr *DBProgressRecalculationRepository) UpdateJob(jobId uint, updates map[string]interface{}) error {
+	return rr.db.Model(&models.ProgressRecalculation{}).
+		Where("id = ?", jobId).
+		Updates(updates).Error
+}
+
+// DeleteFinishedBefore dọn các job đã xong, giữ job failed để điều tra
+func (rr *DBProgressRecalculationRepository) DeleteFinishedBefore(before time.Time) (int64, error) {
+	result := rr.db.Where("status = ? AND finished_at < ?", "completed", before).
+		Delete(&models.ProgressRecalculation{})
+	return result.RowsAffected, result.Error
+}
+
+// CountCourseLessons đếm lessons tính vào progress (cùng điều kiện với LessonRepository.GetCourseLessons)
+func (rr *DBProgressRecalculationRepository) CountCourseLessons(courseId uint) (int, error) {
+	var count int64
+	err := rr.db.Model(&models.Lesson{}).
+		Where("course_id = ? AND is_published = ? AND deleted_at IS NULL", courseId, true).
+		Count(&count).Error
+	return int(count), err
+}
+
+// GetEnrollmentBatch lấy enrollment đang học hoặc đã hoàn thành có id > afterId (dropped không tính lại)
+func (rr *DBProgressRecalculationRepository) GetEnrollmentBatch(courseId, afterId uint, limit int) ([]models.Enrollment, error) {
+	var enrollments []models.Enrollment
+	err := rr.db.
+		Where("course_id = ? AND id > ? AND status IN ? AND deleted_at IS NULL", courseId, afterId, []string{"active", "completed"}).
+		Order("id ASC").
+		Limit(limit).
+		Find(&enrollments).Error
+	return enrollments, err
+}
+
+// CountCompletedLessonsByUsers đếm lesson đã hoàn thành của từng user, chỉ tính lessons còn published
+func (rr *DBProgressRecalculationRepository) CountCompletedLessonsByUsers(courseId uint, userIds []uint) (map[uint]int, error) {
+	var rows []struct {
+		UserId uint
+		Count  int
+	}
+	err := rr.db.Model(&models.Progress{}).
+		Select("progresses.user_id, COUNT(*) AS count").
+		Joins("JOIN lessons ON lessons.id = progresses.lesson_id AND lessons.is_published = ? AND lessons.deleted_at IS NULL", true).
+		Where("progresses.course_id = ? AND progresses.user_id IN ? AND progresses.is_completed = ? AND progresses.deleted_at IS NULL", courseId, userIds, true).
+		Group("progresses.user_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uint]int, len(rows))
+	for _, row := range rows {
+		counts[row.UserId] = row.Count
+	}
+	return counts, nil
+}
+
+func (rr *DBProgressRecalculationRepository) UpdateEnrollment(enrollmentId uint, updates map[string]interface{}) error {
+	return rr.db.Model(&models.Enrollment{}).
+		Where("id = ?", enrollmentId).
+		Updates(updates).Error
+}
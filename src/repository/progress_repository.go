@@ -4,6 +4,7 @@ import (
 	"lms/src/models"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type DBProgressRepository struct {
@@ -16,11 +17,13 @@ func NewDBProgressRepository(db *gorm.DB) ProgressRepository {
 	}
 }
 
-// CountCompletedLessons đếm số bài học đã hoàn thành của user trong course
+// CountCompletedLessons đếm số bài học đã hoàn thành của user trong course.
+// Chỉ tính lessons còn published để khớp với tổng số lessons (lesson đã xóa/ẩn không làm progress vượt 100%)
 func (pr *DBProgressRepository) CountCompletedLessons(userId, courseId uint) (int, error) {
 	var count int64
 	err := pr.db.Model(&models.Progress{}).
-		Where("user_id = ? AND course_id = ? AND is_completed = ? AND deleted_at IS NULL", userId, courseId, true).
+		Joins("JOIN lessons ON lessons.id = progresses.lesson_id AND lessons.is_published = ? AND lessons.deleted_at IS NULL", true).
+		Where("progresses.user_id = ? AND progresses.course_id = ? AND progresses.is_completed = ? AND progresses.deleted_at IS NULL", userId, courseId, true).
 		Count(&count).Error
 
 	if err != nil {
@@ -60,3 +63,55 @@ func (pr *DBProgressRepository) GetLessonProgress(userId, lessonId uint) (*model
 
 	return &progress, nil
 }
+
+// GetLessonProgressList lấy progress của tất cả học viên trong một lesson (thống kê cho giảng viên)
+func (pr *DBProgressRepository) GetLessonProgressList(lessonId uint) ([]models.Progress, error) {
+	var progress []models.Progress
+	err := pr.db.Where("lesson_id = ? AND deleted_at IS NULL", lessonId).
+		Find(&progress).Error
+
+	return progress, err
+}
+
+// FindSyncEventKeys trả về các idempotency key trong keys đã được áp dụng trước đó
+func (pr *DBProgressRepository) FindSyncEventKeys(userId uint, keys []string) ([]string, error) {
+	var found []string
+	if len(keys) == 0 {
+		return found, nil
+	}
+
+	err := pr.db.Model(&models.ProgressSyncEvent{}).
+		Where("user_id = ? AND idempotency_key IN ?", userId, keys).
+		Pluck("idempotency_key", &found).Error
+
+	return found, err
+}
+
+// LockLessonProgress khóa enrollment của học viên (SELECT ... FOR UPDATE) trong transaction tx rồi đọc progress của lesson.
+// Khóa enrollment thay vì dòng progress vì progress có thể chưa được tạo; các request ghi progress của cùng học viên
+// trong khóa học chạy tuần tự. Trả về nil progress nếu chưa học lần nào
+func (pr *DBProgressRepository) LockLessonProgress(tx *gorm.DB, userId, courseId, lessonId uint) (*models.Progress, error) {
+	var enrollmentIds []uint
+	err := tx.Model(&models.Enrollment{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ? AND course_id = ?", userId, courseId).
+		Pluck("id", &enrollmentIds).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var progress models.Progress
+	err = tx.Where("user_id = ? AND lesson_id = ? AND deleted_at IS NULL", userId, lessonId).
+		First(&progress).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &progress, nil
+}
+
+func (pr *DBProgressRepository) BeginTransaction() *gorm.DB {
+	return pr.db.Begin()
+}
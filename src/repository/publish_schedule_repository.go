@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"lms/src/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DBPublishScheduleRepository thực hiện các thay đổi trạng thái theo lịch.
+// Mỗi method là một câu UPDATE có điều kiện nên chạy song song trên nhiều replica vẫn an toàn:
+// replica chạy sau sẽ không match được row nào.
+type DBPublishScheduleRepository struct {
+	db *gorm.DB
+}
+
+func NewDBPublishScheduleRepository(db *gorm.DB) PublishScheduleRepository {
+	return &DBPublishScheduleRepository{
+		db: db,
+	}
+}
+
+func (sr *DBPublishScheduleRepository) PublishDueCourses(now time.Time) (int64, error) {
+	result := sr.db.Model(&models.Course{}).
+		Where("status = ? AND publish_at IS NOT NULL AND publish_at <= ?", "scheduled", now).
+		Updates(map[string]interface{}{
+			"status":     "published",
+			"publish_at": nil,
+		})
+
+	return result.RowsAffected, result.Error
+}
+
+func (sr *DBPublishScheduleRepository) UnpublishDueCourses(now time.Time) (int64, error) {
+	result := sr.db.Model(&models.Course{}).
+		Where("status IN ? AND unpublish_at IS NOT NULL AND unpublish_at <= ?", []string{"published", "scheduled"}, now).
+		Updates(map[string]interface{}{
+			"status":       "archived",
+			"publish_at":   nil,
+			"unpublish_at": nil,
+		})
+
+	return result.RowsAffected, result.Error
+}
+
+func (sr *DBPublishScheduleRepository) PublishDueLessons(now time.Time) (int64, error) {
+	result := sr.db.Model(&models.Lesson{}).
+		Where("publish_at IS NOT NULL AND publish_at <= ?", now).
+		Updates(map[string]interface{}{
+			"is_published": true,
+			"publish_at":   nil,
+		})
+
+	return result.RowsAffected, result.Error
+}
+
+func (sr *DBPublishScheduleRepository) UnpublishDueLessons(now time.Time) (int64, error) {
+	result := sr.db.Model(&models.Lesson{}).
+		Where("unpublish_at IS NOT NULL AND unpublish_at <= ?", now).
+		Updates(map[string]interface{}{
+			"is_published": false,
+			"publish_at":   nil,
+			"unpublish_at": nil,
+		})
+
+	return result.RowsAffected, result.Error
+}
+
+// DueLessonCourseIds lấy các course có lesson sắp được publish/unpublish ở lần chạy này
+func (sr *DBPublishScheduleRepository) DueLessonCourseIds(now time.Time) ([]uint, error) {
+	var courseIds []uint
+	err := sr.db.Model(&models.Lesson{}).
+		Where("(publish_at IS NOT NULL AND publish_at <= ?) OR (unpublish_at IS NOT NULL AND unpublish_at <= ?)", now, now).
+		Distinct().
+		Pluck("course_id", &courseIds).Error
+
+	return courseIds, err
+}
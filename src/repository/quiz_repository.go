@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"lms/src/models"
+
+	"gorm.io/gorm"
+)
+
+type DBQuizRepository struct {
+	db *gorm.DB
+}
+
+func NewDBQuizRepository(db *gorm.DB) QuizRepository {
+	return &DBQuizRepository{
+		db: db,
+	}
+}
+
+// FindByLessonId trả về nil nếu lesson chưa có quiz
+func (qr *DBQuizRepository) FindByLessonId(lessonId uint) (*models.Quiz, error) {
+	var quiz models.Quiz
+	err := qr.db.Preload("Questions", func(db *gorm.DB) *gorm.DB {
+		return db.Order("question_order ASC, id ASC")
+	}).Where("lesson_id = ?", lessonId).First(&quiz).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &quiz, nil
+}
+
+func (qr *DBQuizRepository) FindLessonById(lessonId uint) (*models.Lesson, error) {
+	var lesson models.Lesson
+	if err := qr.db.Where("id = ?", lessonId).First(&lesson).Error; err != nil {
+		return nil, err
+	}
+	return &lesson, nil
+}
+
+func (qr *DBQuizRepository) CreateQuiz(quiz *models.Quiz) error {
+	return qr.db.Create(quiz).Error
+}
+
+func (qr *DBQuizRepository) UpdateQuiz(quizId uint, updates map[string]interface{}) error {
+	return qr.db.Model(&models.Quiz{}).Where("id = ?", quizId).Updates(updates).Error
+}
+
+func (qr *DBQuizRepository) FindQuestionByIdAndQuiz(questionId, quizId uint) (*models.QuizQuestion, error) {
+	var question models.QuizQuestion
+	if err := qr.db.Where("id = ? AND quiz_id = ?", questionId, quizId).First(&question).Error; err != nil {
+		return nil, err
+	}
+	return &question, nil
+}
+
+func (qr *DBQuizRepository) GetMaxQuestionOrder(quizId uint) (int, error) {
+	var maxOrder int
+	err := qr.db.Model(&models.QuizQuestion{}).
+		Where("quiz_id = ?", quizId).
+		Select("COALESCE(MAX(question_order), 0)").
+		Scan(&maxOrder).Error
+	return maxOrder, err
+}
+
+func (qr *DBQuizRepository) CreateQuestion(question *models.QuizQuestion) error {
+	return qr.db.Create(question).Error
+}
+
+// SaveQuestion lưu toàn bộ question (bao gồm options/accepted_answers dạng jsonb)
+func (qr *DBQuizRepository) SaveQuestion(question *models.QuizQuestion) error {
+	return qr.db.Save(question).Error
+}
+
+func (qr *DBQuizRepository) DeleteQuestion(questionId uint) error {
+	return qr.db.Delete(&models.QuizQuestion{}, questionId).Error
+}
+
+func (qr *DBQuizRepository) CreateAttempt(attempt *models.QuizAttempt) error {
+	return qr.db.Create(attempt).Error
+}
+
+func (qr *DBQuizRepository) FindAttemptById(attemptId uint) (*models.QuizAttempt, error) {
+	var attempt models.QuizAttempt
+	if err := qr.db.Where("id = ?", attemptId).First(&attempt).Error; err != nil {
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+// FindInProgressAttempt trả về nil nếu user không có attempt đang làm dở
+func (qr *DBQuizRepository) FindInProgressAttempt(userId, quizId uint) (*models.QuizAttempt, error) {
+	var attempt models.QuizAttempt
+	err := qr.db.Where("user_id = ? AND quiz_id = ? AND status = ?", userId, quizId, "in_progress").
+		Order("id DESC").
+		First(&attempt).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+func (qr *DBQuizRepository) GetUserAttempts(userId, quizId uint) ([]models.QuizAttempt, error) {
+	var attempts []models.QuizAttempt
+	err := qr.db.Where("user_id = ? AND quiz_id = ?", userId, quizId).
+		Order("attempt_number DESC").
+		Find(&attempts).Error
+	return attempts, err
+}
+
+func (qr *DBQuizRepository) CountUserAttempts(userId, quizId uint) (int, error) {
+	var count int64
+	err := qr.db.Model(&models.QuizAttempt{}).
+		Where("user_id = ? AND quiz_id = ?", userId, quizId).
+		Count(&count).Error
+	return int(count), err
+}
+
+// SubmitAttempt chỉ cập nhật khi attempt còn in_progress, tránh chấm 2 lần khi nộp đồng thời
+func (qr *DBQuizRepository) SubmitAttempt(attempt *models.QuizAttempt) (bool, error) {
+	// Updates bằng struct + Select để serializer jsonb được áp dụng và ghi cả giá trị zero (passed = false)
+	result := qr.db.Model(&models.QuizAttempt{}).
+		Where("id = ? AND status = ?", attempt.Id, "in_progress").
+		Select("status", "answers", "score", "max_score", "score_percentage", "passed", "submitted_at").
+		Updates(attempt)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (qr *DBQuizRepository) HasPassedAttempt(userId, lessonId uint) (bool, error) {
+	var count int64
+	err := qr.db.Model(&models.QuizAttempt{}).
+		Where("user_id = ? AND lesson_id = ? AND passed = ?", userId, lessonId, true).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// GetCourseAttempts lấy các attempt đã nộp của user trong course, dùng cho course progress
+func (qr *DBQuizRepository) GetCourseAttempts(userId, courseId uint) ([]models.QuizAttempt, error) {
+	var attempts []models.QuizAttempt
+	err := qr.db.Where("user_id = ? AND course_id = ? AND status = ?", userId, courseId, "submitted").
+		Order("attempt_number DESC").
+		Find(&attempts).Error
+	return attempts, err
+}
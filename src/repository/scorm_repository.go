@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"errors"
+	"lms/src/models"
+
+	"gorm.io/gorm"
+)
+
+type DBScormRepository struct {
+	db *gorm.DB
+}
+
+func NewDBScormRepository(db *gorm.DB) ScormRepository {
+	return &DBScormRepository{
+		db: db,
+	}
+}
+
+// FindPackageByLessonId trả về nil, nil nếu lesson chưa có package
+func (sr *DBScormRepository) FindPackageByLessonId(lessonId uint) (*models.ScormPackage, error) {
+	var pkg models.ScormPackage
+	err := sr.db.Where("lesson_id = ?", lessonId).First(&pkg).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &pkg, nil
+}
+
+func (sr *DBScormRepository) SavePackage(pkg *models.ScormPackage) error {
+	return sr.db.Save(pkg).Error
+}
+
+func (sr *DBScormRepository) DeletePackage(lessonId uint) error {
+	return sr.db.Where("lesson_id = ?", lessonId).Delete(&models.ScormPackage{}).Error
+}
+
+// FindAttempt trả về nil, nil nếu học viên chưa mở lesson lần nào
+func (sr *DBScormRepository) FindAttempt(userId, lessonId uint) (*models.ScormAttempt, error) {
+	var attempt models.ScormAttempt
+	err := sr.db.Where("user_id = ? AND lesson_id = ?", userId, lessonId).First(&attempt).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+func (sr *DBScormRepository) SaveAttempt(attempt *models.ScormAttempt) error {
+	return sr.db.Save(attempt).Error
+}
+
+// ResetAttemptData xóa vị trí và suspend_data khi package bị thay, giữ nguyên status và điểm đã đạt
+func (sr *DBScormRepository) ResetAttemptData(lessonId uint) error {
+	return sr.db.Model(&models.ScormAttempt{}).
+		Where("lesson_id = ?", lessonId).
+		Updates(map[string]interface{}{
+			"lesson_location": "",
+			"suspend_data":    "",
+			"exit":            "",
+		}).Error
+}
+
+func (sr *DBScormRepository) HasCompletedAttempt(userId, lessonId uint) (bool, error) {
+	var count int64
+	err := sr.db.Model(&models.ScormAttempt{}).
+		Where("user_id = ? AND lesson_id = ? AND lesson_status IN ?", userId, lessonId, []string{"passed", "completed"}).
+		Count(&count).Error
+	return count > 0, err
+}
@@ -61,8 +61,10 @@ func (ur *DBUserRepository) ChangePassword(userId uint, hashedPassword string) e
 	return ur.db.Model(&models.User{}).Where("id = ?", userId).Update("password", hashedPassword).Error
 }
 
-func (ur *DBUserRepository) UpdateAvatar(userId uint, avatarURL string) error {
-	return ur.db.Model(&models.User{}).Where("id = ?", userId).Update("avatar_url", avatarURL).Error
+func (ur *DBUserRepository) UpdateAvatar(userId uint, avatarURL string, variants map[string]string) error {
+	return ur.db.Model(&models.User{}).Where("id = ?", userId).
+		Select("avatar_url", "avatar_variants").
+		Updates(&models.User{AvatarURL: avatarURL, AvatarVariants: variants}).Error
 }
 
 func (ur *DBUserRepository) GetUsersWithPagination(offset, limit int, filters map[string]interface{}, orderBy, sortBy string) ([]models.User, int, error) {
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"lms/src/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type DBVideoUploadRepository struct {
+	db *gorm.DB
+}
+
+func NewDBVideoUploadRepository(db *gorm.DB) VideoUploadRepository {
+	return &DBVideoUploadRepository{
+		db: db,
+	}
+}
+
+func (vr *DBVideoUploadRepository) CreateUpload(upload *models.VideoUpload) error {
+	return vr.db.Create(upload).Error
+}
+
+func (vr *DBVideoUploadRepository) FindById(uploadId string) (*models.VideoUpload, error) {
+	var upload models.VideoUpload
+	if err := vr.db.Where("id = ?", uploadId).First(&upload).Error; err != nil {
+		return nil, err
+	}
+	return &upload, nil
+}
+
+func (vr *DBVideoUploadRepository) GetLessonUploads(lessonId uint, limit int) ([]models.VideoUpload, error) {
+	var uploads []models.VideoUpload
+	err := vr.db.Where("lesson_id = ?", lessonId).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&uploads).Error
+	return uploads, err
+}
+
+// LockUpload khóa dòng upload (SELECT ... FOR UPDATE) đến hết transaction,
+// các chunk của cùng upload được ghi tuần tự dù do replica nào nhận
+func (vr *DBVideoUploadRepository) LockUpload(uploadId string) (*models.VideoUpload, error) {
+	var upload models.VideoUpload
+	if err := vr.db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", uploadId).First(&upload).Error; err != nil {
+		return nil, err
+	}
+	return &upload, nil
+}
+
+func (vr *DBVideoUploadRepository) SaveUpload(upload *models.VideoUpload) error {
+	return vr.db.Save(upload).Error
+}
+
+// GetAbandonedUploads lấy các upload đang dở đã quá hạn
+func (vr *DBVideoUploadRepository) GetAbandonedUploads(now time.Time, limit int) ([]models.VideoUpload, error) {
+	var uploads []models.VideoUpload
+	err := vr.db.Where("status = ? AND expires_at <= ?", "uploading", now).
+		Order("expires_at ASC").
+		Limit(limit).
+		Find(&uploads).Error
+	return uploads, err
+}
+
+func (vr *DBVideoUploadRepository) Transaction(fn func(txRepo VideoUploadRepository) error) error {
+	return vr.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&DBVideoUploadRepository{db: tx})
+	})
+}
@@ -11,17 +11,23 @@ type AdminRoutes struct {
 	handler               *handler.AdminHandler
 	couponHandler         *handler.CouponHandler
 	adminAnalyticsHandler *handler.AdminAnalyticsHandler
+	moderationHandler     *handler.CourseModerationHandler
+	enrollmentHandler     *handler.EnrollmentManagementHandler
 }
 
 func NewAdminRoutes(
 	handler *handler.AdminHandler,
 	couponHandler *handler.CouponHandler,
 	adminAnalyticsHandler *handler.AdminAnalyticsHandler,
+	moderationHandler *handler.CourseModerationHandler,
+	enrollmentHandler *handler.EnrollmentManagementHandler,
 ) *AdminRoutes {
 	return &AdminRoutes{
 		handler:               handler,
 		couponHandler:         couponHandler,
 		adminAnalyticsHandler: adminAnalyticsHandler,
+		moderationHandler:     moderationHandler,
+		enrollmentHandler:     enrollmentHandler,
 	}
 }
 
@@ -43,6 +49,18 @@ func (ar *AdminRoutes) Register(r *gin.RouterGroup) {
 			admin.GET("/courses", ar.handler.GetCourses)
 			admin.PUT("/courses/:course_id/status", ar.handler.ChangeCourseStatus)
 
+			// Course moderation
+			admin.GET("/moderation/courses", ar.moderationHandler.GetModerationQueue)
+			admin.GET("/moderation/courses/:id", ar.moderationHandler.GetModerationDetail)
+			admin.POST("/moderation/courses/:id/approve", ar.moderationHandler.ApproveCourse)
+			admin.POST("/moderation/courses/:id/reject", ar.moderationHandler.RejectCourse)
+
+			// Enrollment management (cấp, thu hồi, chuyển quyền học thủ công)
+			admin.POST("/enrollments", ar.enrollmentHandler.AdminEnrollUser)
+			admin.POST("/courses/:course_id/enrollments/bulk", ar.enrollmentHandler.BulkEnrollUsers)
+			admin.POST("/enrollments/:id/revoke", ar.enrollmentHandler.RevokeEnrollment)
+			admin.POST("/enrollments/:id/transfer", ar.enrollmentHandler.TransferEnrollment)
+
 			// Order management
 			admin.GET("orders", ar.handler.GetAllOrders)
 			admin.PUT("orders/:id/status", ar.handler.UpdateOrderStatus)
@@ -0,0 +1,33 @@
+package routes
+
+import (
+	"lms/src/handler"
+	"lms/src/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AssignmentRoutes struct {
+	handler *handler.AssignmentHandler
+}
+
+func NewAssignmentRoutes(handler *handler.AssignmentHandler) *AssignmentRoutes {
+	return &AssignmentRoutes{
+		handler: handler,
+	}
+}
+
+func (ar *AssignmentRoutes) Register(r *gin.RouterGroup) {
+	assignments := r.Group("/assignments")
+	{
+		// Protected routes - cần authentication và đã enroll course
+		assignments.Use(middleware.AuthMiddleware())
+		{
+			assignments.GET("/lessons/:lesson_id", ar.handler.GetAssignmentInfo)
+			assignments.POST("/lessons/:lesson_id/submissions", ar.handler.SubmitAssignment)
+			assignments.GET("/lessons/:lesson_id/submissions", ar.handler.GetMySubmissions)
+			assignments.GET("/submissions/:submission_id", ar.handler.GetMySubmission)
+			assignments.GET("/submissions/:submission_id/files/:file_id", ar.handler.DownloadMySubmissionFile)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package routes
+
+import (
+	"lms/src/handler"
+	"lms/src/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CertificateRoutes struct {
+	handler *handler.CertificateHandler
+}
+
+func NewCertificateRoutes(handler *handler.CertificateHandler) *CertificateRoutes {
+	return &CertificateRoutes{
+		handler: handler,
+	}
+}
+
+func (cr *CertificateRoutes) Register(r *gin.RouterGroup) {
+	certificates := r.Group("/certificates")
+	{
+		// Public route - bên thứ ba xác thực chứng chỉ bằng số hiệu in trên PDF
+		certificates.GET("/:serial", cr.handler.VerifyCertificate)
+
+		// Protected routes - cần authentication
+		certificates.Use(middleware.AuthMiddleware())
+		{
+			certificates.GET("/my", cr.handler.GetMyCertificates)
+			certificates.GET("/:serial/download", cr.handler.DownloadCertificate)
+		}
+	}
+}
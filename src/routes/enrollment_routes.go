@@ -27,6 +27,9 @@ func (er *EnrollmentRoutes) Register(r *gin.RouterGroup) {
 			// Enroll vào course
 			courses.POST("/:course_id/enroll", er.handler.EnrollCourse)
 
+			// Tự hủy enrollment (progress được giữ lại khi enroll lại)
+			courses.POST("/:course_id/unenroll", er.handler.DropEnrollment)
+
 			// Kiểm tra enrollment status
 			courses.GET("/course_id/:course_id/check-enrollment", er.handler.CheckEnrollment)
 		}
@@ -8,14 +8,50 @@ import (
 )
 
 type InstructorRoutes struct {
-	handler          *handler.InstructorHandler
-	analyticsHandler *handler.AnalyticsHandler
+	handler             *handler.InstructorHandler
+	analyticsHandler    *handler.AnalyticsHandler
+	revisionHandler     *handler.CourseRevisionHandler
+	moderationHandler   *handler.CourseModerationHandler
+	prerequisiteHandler *handler.CoursePrerequisiteHandler
+	quizHandler         *handler.QuizHandler
+	assignmentHandler   *handler.AssignmentHandler
+	resourceHandler     *handler.LessonResourceHandler
+	videoUploadHandler  *handler.VideoUploadHandler
+	subtitleHandler     *handler.LessonSubtitleHandler
+	packageHandler      *handler.CoursePackageHandler
+	scormHandler        *handler.ScormHandler
+	enrollmentHandler   *handler.EnrollmentManagementHandler
 }
 
-func NewInstructorRoutes(handler *handler.InstructorHandler, analyticsHandler *handler.AnalyticsHandler) *InstructorRoutes {
+func NewInstructorRoutes(
+	handler *handler.InstructorHandler,
+	analyticsHandler *handler.AnalyticsHandler,
+	revisionHandler *handler.CourseRevisionHandler,
+	moderationHandler *handler.CourseModerationHandler,
+	prerequisiteHandler *handler.CoursePrerequisiteHandler,
+	quizHandler *handler.QuizHandler,
+	assignmentHandler *handler.AssignmentHandler,
+	resourceHandler *handler.LessonResourceHandler,
+	videoUploadHandler *handler.VideoUploadHandler,
+	subtitleHandler *handler.LessonSubtitleHandler,
+	packageHandler *handler.CoursePackageHandler,
+	scormHandler *handler.ScormHandler,
+	enrollmentHandler *handler.EnrollmentManagementHandler,
+) *InstructorRoutes {
 	return &InstructorRoutes{
-		handler:          handler,
-		analyticsHandler: analyticsHandler,
+		handler:             handler,
+		analyticsHandler:    analyticsHandler,
+		revisionHandler:     revisionHandler,
+		moderationHandler:   moderationHandler,
+		prerequisiteHandler: prerequisiteHandler,
+		quizHandler:         quizHandler,
+		assignmentHandler:   assignmentHandler,
+		resourceHandler:     resourceHandler,
+		videoUploadHandler:  videoUploadHandler,
+		subtitleHandler:     subtitleHandler,
+		packageHandler:      packageHandler,
+		scormHandler:        scormHandler,
+		enrollmentHandler:   enrollmentHandler,
 	}
 }
 
@@ -30,21 +66,92 @@ func (ir *InstructorRoutes) Register(r *gin.RouterGroup) {
 			instructor.GET("/courses", ir.handler.GetInstructorCourses)
 			instructor.POST("/courses", ir.handler.CreateCourse)
 			instructor.PUT("/courses/:course_id", ir.handler.UpdateCourse)
+			instructor.POST("/courses/:course_id/thumbnail", ir.handler.UploadCourseThumbnail)
+			instructor.POST("/courses/:course_id/images", ir.handler.UploadContentImage)
 			instructor.DELETE("/courses/:course_id", ir.handler.DeleteCourse)
+			instructor.POST("/courses/:course_id/clone", ir.handler.CloneCourse)
+
+			// Import/export course dạng package ZIP
+			instructor.GET("/courses/:course_id/export", ir.packageHandler.ExportCourse)
+			instructor.POST("/courses/import", ir.packageHandler.ImportCourse)
 			instructor.GET("/courses/:course_id/students", ir.handler.GetCourseStudents)
 
+			// Tặng quyền học (complimentary access) trong giới hạn quota
+			instructor.GET("/courses/:course_id/complimentary-access", ir.enrollmentHandler.GetComplimentaryAccess)
+			instructor.POST("/courses/:course_id/complimentary-access", ir.enrollmentHandler.GrantComplimentaryAccess)
+			instructor.POST("/courses/:course_id/complimentary-access/:enrollment_id/revoke", ir.enrollmentHandler.RevokeComplimentaryAccess)
+
+			// Course review (publish phải qua admin duyệt)
+			instructor.POST("/courses/:course_id/submit-review", ir.moderationHandler.SubmitForReview)
+			instructor.GET("/courses/:course_id/reviews", ir.moderationHandler.GetCourseModerations)
+
+			// Course prerequisites
+			instructor.GET("/courses/:course_id/prerequisites", ir.prerequisiteHandler.GetCoursePrerequisites)
+			instructor.PUT("/courses/:course_id/prerequisites", ir.prerequisiteHandler.UpdateCoursePrerequisites)
+
 			// Lesson management
 			instructor.POST("/courses/:course_id/lessons", ir.handler.CreateLesson)
 			instructor.PUT("/courses/:course_id/lessons/:id", ir.handler.UpdateLesson)
 			instructor.DELETE("/courses/:course_id/lessons/:id", ir.handler.DeleteLesson)
+			instructor.POST("/courses/:course_id/lessons/:id/duplicate", ir.handler.DuplicateLesson)
 			instructor.PUT("/lessons/:id/reorder", ir.handler.ReorderLessons)
 
+			// Video upload theo chunk, resume được
+			instructor.POST("/courses/:course_id/lessons/:id/video-uploads", ir.videoUploadHandler.CreateUpload)
+			instructor.GET("/courses/:course_id/lessons/:id/video-uploads", ir.videoUploadHandler.GetLessonUploads)
+			instructor.GET("/video-uploads/:upload_id", ir.videoUploadHandler.GetUpload)
+			instructor.PATCH("/video-uploads/:upload_id", ir.videoUploadHandler.UploadChunk)
+			instructor.DELETE("/video-uploads/:upload_id", ir.videoUploadHandler.AbortUpload)
+
+			// Phụ đề (SRT/WebVTT) theo ngôn ngữ
+			instructor.GET("/courses/:course_id/lessons/:id/subtitles", ir.subtitleHandler.GetInstructorSubtitles)
+			instructor.POST("/courses/:course_id/lessons/:id/subtitles", ir.subtitleHandler.UploadSubtitle)
+			instructor.PUT("/courses/:course_id/lessons/:id/subtitles/:subtitle_id", ir.subtitleHandler.UpdateSubtitle)
+			instructor.DELETE("/courses/:course_id/lessons/:id/subtitles/:subtitle_id", ir.subtitleHandler.DeleteSubtitle)
+
+			// SCORM 1.2 package (lesson dạng scorm)
+			instructor.GET("/courses/:course_id/lessons/:id/scorm", ir.scormHandler.GetInstructorPackage)
+			instructor.PUT("/courses/:course_id/lessons/:id/scorm", ir.scormHandler.UploadPackage)
+			instructor.DELETE("/courses/:course_id/lessons/:id/scorm", ir.scormHandler.DeletePackage)
+
+			// Lesson resources (tài liệu đính kèm)
+			instructor.GET("/courses/:course_id/lessons/:id/resources", ir.resourceHandler.GetInstructorResources)
+			instructor.POST("/courses/:course_id/lessons/:id/resources", ir.resourceHandler.UploadResource)
+			instructor.PUT("/courses/:course_id/lessons/:id/resources/:resource_id", ir.resourceHandler.UpdateResource)
+			instructor.DELETE("/courses/:course_id/lessons/:id/resources/:resource_id", ir.resourceHandler.DeleteResource)
+
+			// Quiz lessons
+			instructor.GET("/courses/:course_id/lessons/:id/quiz", ir.quizHandler.GetLessonQuiz)
+			instructor.PUT("/courses/:course_id/lessons/:id/quiz", ir.quizHandler.UpsertLessonQuiz)
+			instructor.POST("/courses/:course_id/lessons/:id/quiz/questions", ir.quizHandler.CreateQuestion)
+			instructor.PUT("/courses/:course_id/lessons/:id/quiz/questions/:question_id", ir.quizHandler.UpdateQuestion)
+			instructor.DELETE("/courses/:course_id/lessons/:id/quiz/questions/:question_id", ir.quizHandler.DeleteQuestion)
+
+			// Assignment lessons và grading queue
+			instructor.GET("/courses/:course_id/lessons/:id/assignment", ir.assignmentHandler.GetLessonAssignment)
+			instructor.PUT("/courses/:course_id/lessons/:id/assignment", ir.assignmentHandler.UpsertLessonAssignment)
+			instructor.GET("/assignments/submissions", ir.assignmentHandler.GetGradingQueue)
+			instructor.GET("/assignments/submissions/:submission_id", ir.assignmentHandler.GetSubmissionForGrading)
+			instructor.POST("/assignments/submissions/:submission_id/grade", ir.assignmentHandler.GradeSubmission)
+			instructor.GET("/assignments/submissions/:submission_id/files/:file_id", ir.assignmentHandler.DownloadInstructorSubmissionFile)
+
+			// Course revisions (draft edits cho course đã published)
+			instructor.GET("/courses/:course_id/revisions", ir.revisionHandler.GetCourseRevisions)
+			instructor.POST("/courses/:course_id/revisions", ir.revisionHandler.CreateDraft)
+			instructor.GET("/courses/:course_id/revisions/draft", ir.revisionHandler.GetDraft)
+			instructor.PUT("/courses/:course_id/revisions/draft", ir.revisionHandler.UpdateDraft)
+			instructor.DELETE("/courses/:course_id/revisions/draft", ir.revisionHandler.DiscardDraft)
+			instructor.GET("/courses/:course_id/revisions/draft/diff", ir.revisionHandler.GetDraftDiff)
+			instructor.POST("/courses/:course_id/revisions/draft/publish", ir.revisionHandler.PublishDraft)
+			instructor.POST("/courses/:course_id/revisions/:revision_id/rollback", ir.revisionHandler.RollbackToRevision)
+
 			// Analytics endpoints
 			analytics := instructor.Group("/analytics")
 			{
 				analytics.GET("/overview", ir.analyticsHandler.GetInstructorOverview)
 				analytics.GET("/revenue", ir.analyticsHandler.GetRevenueAnalytics)
 				analytics.GET("/students", ir.analyticsHandler.GetStudentAnalytics)
+				analytics.GET("/courses/:course_id/lessons/:id/engagement", ir.analyticsHandler.GetLessonEngagement)
 			}
 		}
 	}
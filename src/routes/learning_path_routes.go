@@ -0,0 +1,62 @@
+package routes
+
+import (
+	"lms/src/handler"
+	"lms/src/middleware"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LearningPathRoutes struct {
+	handler *handler.LearningPathHandler
+}
+
+func NewLearningPathRoutes(handler *handler.LearningPathHandler) *LearningPathRoutes {
+	return &LearningPathRoutes{
+		handler: handler,
+	}
+}
+
+func (lr *LearningPathRoutes) Register(r *gin.RouterGroup) {
+	learningPaths := r.Group("/learning-paths")
+	{
+		// Public routes
+		// Cache 15 phút cho danh sách learning paths
+		learningPaths.GET("/", middleware.CacheMiddleware(15*time.Minute), lr.handler.GetLearningPaths)
+
+		// Cache 30 phút cho learning path detail
+		learningPaths.GET("/:slug", middleware.CacheMiddleware(30*time.Minute), lr.handler.GetLearningPathBySlug)
+
+		// Protected routes - theo dõi tiến độ learning path
+		learningPaths.Use(middleware.AuthMiddleware())
+		{
+			learningPaths.POST("/:slug/follow", lr.handler.FollowLearningPath)
+			learningPaths.DELETE("/:slug/follow", lr.handler.UnfollowLearningPath)
+			learningPaths.GET("/:slug/progress", lr.handler.GetLearningPathProgress)
+		}
+	}
+
+	users := r.Group("/users")
+	{
+		users.Use(middleware.AuthMiddleware())
+		{
+			users.GET("/learning-paths", lr.handler.GetMyLearningPaths)
+		}
+	}
+
+	// Admin quản lý learning paths
+	admin := r.Group("/admin/learning-paths")
+	{
+		admin.Use(middleware.AuthMiddleware())
+		admin.Use(middleware.AdminMiddleware())
+		{
+			admin.GET("", lr.handler.GetAdminLearningPaths)
+			admin.POST("", lr.handler.CreateLearningPath)
+			admin.GET("/:id", lr.handler.GetAdminLearningPathDetail)
+			admin.PUT("/:id", lr.handler.UpdateLearningPath)
+			admin.DELETE("/:id", lr.handler.DeleteLearningPath)
+			admin.PUT("/:id/courses", lr.handler.UpdateLearningPathCourses)
+		}
+	}
+}
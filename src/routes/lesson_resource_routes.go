@@ -0,0 +1,30 @@
+package routes
+
+import (
+	"lms/src/handler"
+	"lms/src/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LessonResourceRoutes struct {
+	handler *handler.LessonResourceHandler
+}
+
+func NewLessonResourceRoutes(handler *handler.LessonResourceHandler) *LessonResourceRoutes {
+	return &LessonResourceRoutes{
+		handler: handler,
+	}
+}
+
+func (rr *LessonResourceRoutes) Register(r *gin.RouterGroup) {
+	resources := r.Group("/resources")
+	{
+		// Protected routes - cần authentication và đã enroll course
+		resources.Use(middleware.AuthMiddleware())
+		{
+			resources.GET("/lessons/:lesson_id", rr.handler.GetLessonResources)
+			resources.GET("/:resource_id/download", rr.handler.DownloadResource)
+		}
+	}
+}
@@ -8,12 +8,14 @@ import (
 )
 
 type LessonRoutes struct {
-	handler *handler.LessonHandler
+	handler     *handler.LessonHandler
+	noteHandler *handler.LessonNoteHandler
 }
 
-func NewLessonRoutes(handler *handler.LessonHandler) *LessonRoutes {
+func NewLessonRoutes(handler *handler.LessonHandler, noteHandler *handler.LessonNoteHandler) *LessonRoutes {
 	return &LessonRoutes{
-		handler: handler,
+		handler:     handler,
+		noteHandler: noteHandler,
 	}
 }
 
@@ -27,7 +29,38 @@ func (lr *LessonRoutes) Register(r *gin.RouterGroup) {
 			courses.GET("/course_id/:course_id/lessons", lr.handler.GetCourseLessons)
 
 			// Lấy lesson detail
-			courses.GET("/course_id/:course_id/lessons/:slug", lr.handler.GetCourseLessons)
+			courses.GET("/course_id/:course_id/lessons/:slug", lr.handler.GetLessonDetail)
+		}
+	}
+
+	// Ghi chú riêng và bookmark của học viên
+	notes := r.Group("/notes")
+	{
+		notes.Use(middleware.AuthMiddleware())
+		{
+			notes.GET("/lessons/:lesson_id", lr.noteHandler.GetLessonNotes)
+			notes.POST("/lessons/:lesson_id", lr.noteHandler.CreateNote)
+			notes.PUT("/:note_id", lr.noteHandler.UpdateNote)
+			notes.DELETE("/:note_id", lr.noteHandler.DeleteNote)
+			notes.GET("/courses/:course_id/export", lr.noteHandler.ExportCourseNotes)
+		}
+	}
+
+	bookmarks := r.Group("/bookmarks")
+	{
+		bookmarks.Use(middleware.AuthMiddleware())
+		{
+			bookmarks.POST("/lessons/:lesson_id", lr.noteHandler.AddBookmark)
+			bookmarks.DELETE("/lessons/:lesson_id", lr.noteHandler.RemoveBookmark)
+		}
+	}
+
+	users := r.Group("/users")
+	{
+		users.Use(middleware.AuthMiddleware())
+		{
+			users.GET("/me/notes", lr.noteHandler.SearchNotes)
+			users.GET("/me/bookmarks", lr.noteHandler.GetBookmarks)
 		}
 	}
 }
@@ -0,0 +1,33 @@
+package routes
+
+import (
+	"lms/src/handler"
+	"lms/src/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LessonSubtitleRoutes struct {
+	handler *handler.LessonSubtitleHandler
+}
+
+func NewLessonSubtitleRoutes(handler *handler.LessonSubtitleHandler) *LessonSubtitleRoutes {
+	return &LessonSubtitleRoutes{
+		handler: handler,
+	}
+}
+
+func (sr *LessonSubtitleRoutes) Register(r *gin.RouterGroup) {
+	subtitles := r.Group("/subtitles")
+	{
+		// Thẻ <track> không gửi được Authorization header, quyền truy cập xác thực bằng chữ ký trong URL
+		subtitles.GET("/:subtitle_id/vtt", sr.handler.GetSubtitleFile)
+
+		// Protected routes - cần authentication và đã enroll course
+		subtitles.Use(middleware.AuthMiddleware())
+		{
+			subtitles.GET("/lessons/:lesson_id", sr.handler.GetSubtitleTracks)
+			subtitles.GET("/lessons/:lesson_id/transcript", sr.handler.GetTranscript)
+		}
+	}
+}
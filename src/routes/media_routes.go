@@ -0,0 +1,33 @@
+package routes
+
+import (
+	"lms/src/handler"
+	"lms/src/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MediaRoutes struct {
+	handler *handler.MediaHandler
+}
+
+func NewMediaRoutes(handler *handler.MediaHandler) *MediaRoutes {
+	return &MediaRoutes{
+		handler: handler,
+	}
+}
+
+func (mr *MediaRoutes) Register(r *gin.RouterGroup) {
+	media := r.Group("/media")
+	{
+		// Stream không dùng Authorization header (thẻ <video> không gửi được),
+		// quyền truy cập được xác thực bằng chữ ký trong URL
+		media.GET("/lessons/:lesson_id/stream", mr.handler.StreamLessonMedia)
+
+		// Protected routes - cấp signed URL
+		media.Use(middleware.AuthMiddleware())
+		{
+			media.GET("/lessons/:lesson_id/url", mr.handler.GetLessonMediaURL)
+		}
+	}
+}
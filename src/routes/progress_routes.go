@@ -34,11 +34,17 @@ func (pr *ProgressRoutes) Register(r *gin.RouterGroup) {
 	{
 		progress.Use(middleware.AuthMiddleware())
 		{
+			// Đồng bộ batch progress từ client offline (mobile)
+			progress.POST("/sync", pr.handler.SyncProgress)
+
 			// Đánh dấu lesson hoàn thành
 			progress.POST("/:lesson_id/complete", pr.handler.CompleteLesson)
 
 			// Cập nhật vị trí video
 			progress.PUT("/:lesson_id/position", pr.handler.UpdateLessonPosition)
+
+			// Heartbeat ghi nhận thời lượng xem video thực tế
+			progress.POST("/:lesson_id/heartbeat", pr.handler.RecordHeartbeat)
 		}
 	}
 }
@@ -0,0 +1,33 @@
+package routes
+
+import (
+	"lms/src/handler"
+	"lms/src/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type QuizRoutes struct {
+	handler *handler.QuizHandler
+}
+
+func NewQuizRoutes(handler *handler.QuizHandler) *QuizRoutes {
+	return &QuizRoutes{
+		handler: handler,
+	}
+}
+
+func (qr *QuizRoutes) Register(r *gin.RouterGroup) {
+	quizzes := r.Group("/quizzes")
+	{
+		// Protected routes - cần authentication và đã enroll course
+		quizzes.Use(middleware.AuthMiddleware())
+		{
+			quizzes.GET("/lessons/:lesson_id", qr.handler.GetQuizInfo)
+			quizzes.POST("/lessons/:lesson_id/attempts", qr.handler.StartAttempt)
+			quizzes.GET("/lessons/:lesson_id/attempts", qr.handler.GetMyAttempts)
+			quizzes.GET("/attempts/:attempt_id", qr.handler.GetAttempt)
+			quizzes.POST("/attempts/:attempt_id/submit", qr.handler.SubmitAttempt)
+		}
+	}
+}
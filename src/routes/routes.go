@@ -1,6 +1,10 @@
 package routes
 
 import (
+	"lms/src/storage"
+	"lms/src/utils"
+	"path/filepath"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -15,8 +19,12 @@ func RegisterRoutes(r *gin.Engine, routes ...Route) {
 	// middleware.RateLimiterMiddleware(),
 	)
 
-	// Serve static files cho uploads
-	r.Static("/uploads", "./uploads")
+	// Chỉ public ảnh (avatars, thumbnails, content-images) khi dùng local storage,
+	// video khóa học được phục vụ qua media API có signed URL
+	localDir := utils.GetEnv("STORAGE_LOCAL_DIR", "./uploads")
+	for _, prefix := range storage.PublicPrefixes {
+		r.Static("/uploads/"+prefix, filepath.Join(localDir, prefix))
+	}
 
 	api := r.Group("/api/v1")
 
@@ -0,0 +1,39 @@
+package routes
+
+import (
+	"lms/src/handler"
+	"lms/src/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ScormRoutes struct {
+	handler *handler.ScormHandler
+}
+
+func NewScormRoutes(handler *handler.ScormHandler) *ScormRoutes {
+	return &ScormRoutes{
+		handler: handler,
+	}
+}
+
+func (sr *ScormRoutes) Register(r *gin.RouterGroup) {
+	scorm := r.Group("/scorm")
+	{
+		// Phiên học chạy trong iframe không gửi được Authorization header,
+		// quyền truy cập xác thực bằng chữ ký trong path (player, runtime API và nội dung cùng prefix)
+		session := scorm.Group("/sessions/:lesson_id/:uid/:exp/:sig")
+		{
+			session.GET("/player", sr.handler.GetPlayer)
+			session.GET("/runtime", sr.handler.GetRuntime)
+			session.PUT("/runtime", sr.handler.CommitRuntime)
+			session.GET("/content/*path", sr.handler.GetContent)
+		}
+
+		// Protected routes - cần authentication và đã enroll course
+		scorm.Use(middleware.AuthMiddleware())
+		{
+			scorm.POST("/lessons/:lesson_id/launch", sr.handler.LaunchLesson)
+		}
+	}
+}
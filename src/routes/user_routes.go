@@ -8,12 +8,14 @@ import (
 )
 
 type UserRoutes struct {
-	handler *handler.UserHandler
+	handler         *handler.UserHandler
+	learningHandler *handler.LearningStatsHandler
 }
 
-func NewUserRoutes(handler *handler.UserHandler) *UserRoutes {
+func NewUserRoutes(handler *handler.UserHandler, learningHandler *handler.LearningStatsHandler) *UserRoutes {
 	return &UserRoutes{
-		handler: handler,
+		handler:         handler,
+		learningHandler: learningHandler,
 	}
 }
 
@@ -27,6 +29,13 @@ func (ur *UserRoutes) Register(r *gin.RouterGroup) {
 			users.PUT("/profile", ur.handler.UpdateProfile)
 			users.PUT("/change-password", ur.handler.ChangePassword)
 			users.POST("/upload-avatar", ur.handler.UploadAvatar)
+
+			// Thống kê học tập, mục tiêu và tùy chọn email nhắc học
+			users.GET("/me/learning-stats", ur.learningHandler.GetLearningStats)
+			users.PUT("/me/learning-goal", ur.learningHandler.UpdateLearningGoal)
+			users.DELETE("/me/learning-goal", ur.learningHandler.DeleteLearningGoal)
+			users.GET("/me/notification-preferences", ur.learningHandler.GetNotificationPreferences)
+			users.PUT("/me/notification-preferences", ur.learningHandler.UpdateNotificationPreferences)
 		}
 	}
 }
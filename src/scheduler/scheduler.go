@@ -0,0 +1,81 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Job là một tác vụ chạy định kỳ trong process của app.
+// Run phải idempotent: nhiều replica có thể chạy cùng một job đồng thời,
+// và một lần chạy bị gián đoạn sẽ được lặp lại ở tick tiếp theo (at-least-once).
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+type Scheduler struct {
+	jobs []Job
+	wg   sync.WaitGroup
+}
+
+func NewScheduler() *Scheduler {
+	return &Scheduler{}
+}
+
+func (s *Scheduler) Register(jobs ...Job) {
+	s.jobs = append(s.jobs, jobs...)
+}
+
+// Start chạy mỗi job trong một goroutine riêng cho tới khi ctx bị hủy
+func (s *Scheduler) Start(ctx context.Context) {
+	for _, job := range s.jobs {
+		if job.Interval <= 0 || job.Run == nil {
+			log.Printf("⚠️ Scheduler: skip job %s (invalid config)", job.Name)
+			continue
+		}
+
+		s.wg.Add(1)
+		go s.loop(ctx, job)
+	}
+
+	log.Printf("⏰ Scheduler started with %d job(s)", len(s.jobs))
+}
+
+// Wait chờ tất cả job dừng sau khi ctx bị hủy
+func (s *Scheduler) Wait() {
+	s.wg.Wait()
+}
+
+func (s *Scheduler) loop(ctx context.Context, job Job) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	// Chạy ngay khi start để không phải chờ hết interval đầu tiên
+	s.runOnce(ctx, job)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.runOnce(ctx, job)
+		}
+	}
+}
+
+func (s *Scheduler) runOnce(ctx context.Context, job Job) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("❌ Scheduler: job %s panicked: %v", job.Name, r)
+		}
+	}()
+
+	if err := job.Run(ctx); err != nil {
+		log.Printf("❌ Scheduler: job %s failed: %v", job.Name, err)
+	}
+}
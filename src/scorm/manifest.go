@@ -0,0 +1,190 @@
+package scorm
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// ManifestName là file mô tả package theo IMS Content Packaging, luôn nằm ở gốc package
+const ManifestName = "imsmanifest.xml"
+
+// Version 1.2 là phiên bản SCORM duy nhất được hỗ trợ
+const Version = "1.2"
+
+// Manifest là thông tin cần cho LMS sau khi parse imsmanifest.xml.
+// Package nhiều SCO chỉ dùng SCO đầu tiên của organization mặc định làm nội dung của lesson.
+type Manifest struct {
+	Identifier   string
+	Version      string
+	Title        string
+	LaunchPath   string   // Đường dẫn file trong package, không có query
+	LaunchQuery  string   // Query/fragment nối vào launch URL (href + parameters của item)
+	MasteryScore *float64 // adlcp:masteryscore của item, nil nếu không khai báo
+}
+
+// Cấu trúc XML (chỉ các phần LMS cần), tag không có namespace nên khớp cả imscp:/adlcp:
+type xmlManifest struct {
+	Identifier    string           `xml:"identifier,attr"`
+	SchemaVersion string           `xml:"metadata>schemaversion"`
+	Organizations xmlOrganizations `xml:"organizations"`
+	Resources     xmlResources     `xml:"resources"`
+}
+
+type xmlOrganizations struct {
+	Default       string            `xml:"default,attr"`
+	Organizations []xmlOrganization `xml:"organization"`
+}
+
+type xmlOrganization struct {
+	Identifier string    `xml:"identifier,attr"`
+	Title      string    `xml:"title"`
+	Items      []xmlItem `xml:"item"`
+}
+
+type xmlItem struct {
+	Identifier    string    `xml:"identifier,attr"`
+	IdentifierRef string    `xml:"identifierref,attr"`
+	Parameters    string    `xml:"parameters,attr"`
+	Title         string    `xml:"title"`
+	MasteryScore  string    `xml:"masteryscore"`
+	Items         []xmlItem `xml:"item"`
+}
+
+type xmlResources struct {
+	Base      string        `xml:"base,attr"`
+	Resources []xmlResource `xml:"resource"`
+}
+
+type xmlResource struct {
+	Identifier string `xml:"identifier,attr"`
+	Href       string `xml:"href,attr"`
+	Base       string `xml:"base,attr"`
+}
+
+// ParseManifest đọc imsmanifest.xml và xác định file launch của SCO đầu tiên
+func ParseManifest(data []byte) (*Manifest, error) {
+	var doc xmlManifest
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = false
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("%w: invalid %s: %v", ErrInvalidPackage, ManifestName, err)
+	}
+
+	// 1. Phiên bản: 1.2 hoặc không khai báo (nhiều công cụ cũ bỏ trống), SCORM 2004 khai báo "2004 ..." hoặc "CAM 1.3"
+	version := strings.TrimSpace(doc.SchemaVersion)
+	if version != "" && version != Version {
+		return nil, fmt.Errorf("%w: schema version %q (supported: SCORM %s)", ErrUnsupportedVersion, version, Version)
+	}
+
+	// 2. Organization mặc định, không khai báo thì lấy organization đầu tiên
+	if len(doc.Organizations.Organizations) == 0 {
+		return nil, fmt.Errorf("%w: no organization in %s", ErrInvalidPackage, ManifestName)
+	}
+	org := &doc.Organizations.Organizations[0]
+	for i := range doc.Organizations.Organizations {
+		if doc.Organizations.Organizations[i].Identifier == doc.Organizations.Default {
+			org = &doc.Organizations.Organizations[i]
+			break
+		}
+	}
+
+	resources := make(map[string]*xmlResource, len(doc.Resources.Resources))
+	for i := range doc.Resources.Resources {
+		resources[doc.Resources.Resources[i].Identifier] = &doc.Resources.Resources[i]
+	}
+
+	// 3. Item đầu tiên (duyệt theo thứ tự cây) trỏ tới resource có href
+	item, resource := findLaunchItem(org.Items, resources)
+	if item == nil {
+		return nil, fmt.Errorf("%w: no launchable item in %s", ErrInvalidPackage, ManifestName)
+	}
+
+	launch := joinLaunchParameters(resource.Href, item.Parameters)
+
+	launchPath, launchQuery := launch, ""
+	if i := strings.IndexAny(launch, "?#"); i >= 0 {
+		launchPath, launchQuery = launch[:i], launch[i:]
+	}
+
+	if unescaped, err := url.PathUnescape(launchPath); err == nil {
+		launchPath = unescaped
+	}
+	if strings.Contains(launchPath, "://") || strings.HasPrefix(launchPath, "/") {
+		return nil, fmt.Errorf("%w: launch href %q must be a file inside the package", ErrInvalidPackage, launchPath)
+	}
+	launchPath = path.Join(strings.TrimPrefix(doc.Resources.Base, "/"), strings.TrimPrefix(resource.Base, "/"), launchPath)
+	if !ValidEntryName(launchPath) {
+		return nil, fmt.Errorf("%w: invalid launch href %q", ErrInvalidPackage, launchPath)
+	}
+
+	manifest := &Manifest{
+		Identifier:  doc.Identifier,
+		Version:     Version,
+		Title:       strings.TrimSpace(org.Title),
+		LaunchPath:  launchPath,
+		LaunchQuery: launchQuery,
+	}
+	if manifest.Title == "" {
+		manifest.Title = strings.TrimSpace(item.Title)
+	}
+
+	if score := strings.TrimSpace(item.MasteryScore); score != "" {
+		value, err := strconv.ParseFloat(score, 64)
+		if err != nil || value < 0 || value > 100 {
+			return nil, fmt.Errorf("%w: invalid masteryscore %q", ErrInvalidPackage, score)
+		}
+		manifest.MasteryScore = &value
+	}
+
+	return manifest, nil
+}
+
+// joinLaunchParameters nối parameters của item vào href của resource theo IMS CP Best Practice,
+// query luôn nằm trước fragment ("page.html#top" + "?lang=vi" thành "page.html?lang=vi#top")
+func joinLaunchParameters(href, parameters string) string {
+	fragment := ""
+	if i := strings.IndexByte(href, '#'); i >= 0 {
+		href, fragment = href[:i], href[i:]
+	}
+
+	parameters = strings.TrimSpace(parameters)
+	if i := strings.IndexByte(parameters, '#'); i >= 0 {
+		if fragment == "" {
+			fragment = parameters[i:]
+		}
+		parameters = parameters[:i]
+	}
+
+	hasQuery := strings.Contains(href, "?")
+	switch {
+	case parameters == "":
+	case parameters[0] == '?' || parameters[0] == '&':
+		if hasQuery {
+			href += "&" + parameters[1:]
+		} else {
+			href += "?" + parameters[1:]
+		}
+	case hasQuery:
+		href += "&" + parameters
+	default:
+		href += "?" + parameters
+	}
+	return href + fragment
+}
+
+func findLaunchItem(items []xmlItem, resources map[string]*xmlResource) (*xmlItem, *xmlResource) {
+	for i := range items {
+		if resource := resources[items[i].IdentifierRef]; resource != nil && resource.Href != "" {
+			return &items[i], resource
+		}
+		if item, resource := findLaunchItem(items[i].Items, resources); item != nil {
+			return item, resource
+		}
+	}
+	return nil, nil
+}
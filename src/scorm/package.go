@@ -0,0 +1,172 @@
+package scorm
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrInvalidPackage     = errors.New("invalid SCORM package")
+	ErrUnsupportedVersion = errors.New("unsupported SCORM version")
+	ErrPackageTooLarge    = errors.New("SCORM package is too large")
+)
+
+// Limits giới hạn package khi giải nén để chặn ZIP bomb
+type Limits struct {
+	MaxFiles     int   // Số file tối đa trong ZIP
+	MaxFileSize  int64 // Dung lượng tối đa của một file sau giải nén
+	MaxTotalSize int64 // Tổng dung lượng tối đa sau giải nén
+}
+
+// Package là package SCORM đã mở và parse manifest
+type Package struct {
+	Manifest *Manifest
+	files    map[string]*zip.File // Đường dẫn tương đối so với thư mục chứa imsmanifest.xml
+}
+
+// Open đọc ZIP, kiểm tra tên file, giới hạn dung lượng và parse imsmanifest.xml.
+// Nhiều công cụ nén cả thư mục gốc nên manifest được chấp nhận ở gốc ZIP hoặc trong một thư mục duy nhất.
+func Open(r io.ReaderAt, size int64, limits Limits) (*Package, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPackage, err)
+	}
+
+	if limits.MaxFiles > 0 && len(zr.File) > limits.MaxFiles {
+		return nil, fmt.Errorf("%w: more than %d files", ErrPackageTooLarge, limits.MaxFiles)
+	}
+
+	// 1. Kiểm tra tên và dung lượng từng file
+	entries := make(map[string]*zip.File, len(zr.File))
+	var total uint64
+	for _, file := range zr.File {
+		if strings.HasSuffix(file.Name, "/") {
+			continue // Thư mục
+		}
+		if !ValidEntryName(file.Name) {
+			return nil, fmt.Errorf("%w: invalid entry name %q", ErrInvalidPackage, file.Name)
+		}
+		if entries[file.Name] != nil {
+			return nil, fmt.Errorf("%w: duplicate entry %q", ErrInvalidPackage, file.Name)
+		}
+
+		if limits.MaxFileSize > 0 && file.UncompressedSize64 > uint64(limits.MaxFileSize) {
+			return nil, fmt.Errorf("%w: %q exceeds %d bytes", ErrPackageTooLarge, file.Name, limits.MaxFileSize)
+		}
+		total += file.UncompressedSize64
+		if limits.MaxTotalSize > 0 && total > uint64(limits.MaxTotalSize) {
+			return nil, fmt.Errorf("%w: total size exceeds %d bytes", ErrPackageTooLarge, limits.MaxTotalSize)
+		}
+
+		entries[file.Name] = file
+	}
+
+	// 2. Tìm imsmanifest.xml, bỏ thư mục bọc ngoài nếu có
+	root := ""
+	if entries[ManifestName] == nil {
+		for name := range entries {
+			dir, base := path.Split(name)
+			if base == ManifestName && strings.Count(dir, "/") == 1 {
+				if root != "" {
+					return nil, fmt.Errorf("%w: multiple %s found", ErrInvalidPackage, ManifestName)
+				}
+				root = dir
+			}
+		}
+		if root == "" {
+			return nil, fmt.Errorf("%w: %s not found", ErrInvalidPackage, ManifestName)
+		}
+	}
+
+	pkg := &Package{files: make(map[string]*zip.File, len(entries))}
+	for name, file := range entries {
+		if strings.HasPrefix(name, root) {
+			pkg.files[strings.TrimPrefix(name, root)] = file
+		}
+	}
+
+	// 3. Parse manifest, file launch phải có trong package
+	data, err := pkg.readFile(ManifestName, 1<<20)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, err := ParseManifest(data)
+	if err != nil {
+		return nil, err
+	}
+	if pkg.files[manifest.LaunchPath] == nil {
+		return nil, fmt.Errorf("%w: launch file %q not found", ErrInvalidPackage, manifest.LaunchPath)
+	}
+
+	pkg.Manifest = manifest
+	return pkg, nil
+}
+
+// Files trả về đường dẫn các file trong package theo thứ tự tên
+func (p *Package) Files() []string {
+	names := make([]string, 0, len(p.files))
+	for name := range p.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// OpenFile mở file trong package theo đường dẫn tương đối
+func (p *Package) OpenFile(name string) (io.ReadCloser, int64, error) {
+	file := p.files[name]
+	if file == nil {
+		return nil, 0, fmt.Errorf("%w: file %q not found", ErrInvalidPackage, name)
+	}
+
+	rc, err := file.Open()
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: %v", ErrInvalidPackage, err)
+	}
+	return rc, int64(file.UncompressedSize64), nil
+}
+
+func (p *Package) readFile(name string, maxSize int64) ([]byte, error) {
+	rc, size, err := p.OpenFile(name)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	if size > maxSize {
+		return nil, fmt.Errorf("%w: %q exceeds %d bytes", ErrPackageTooLarge, name, maxSize)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(rc, maxSize))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPackage, err)
+	}
+	return data, nil
+}
+
+// ValidEntryName chỉ chấp nhận đường dẫn tương đối, không có "..", "\" hay ký tự điều khiển
+func ValidEntryName(name string) bool {
+	if name == "" || len(name) > 255 || strings.HasPrefix(name, "/") || strings.Contains(name, `\`) {
+		return false
+	}
+	if path.Clean(name) != name {
+		return false
+	}
+	for _, part := range strings.Split(name, "/") {
+		if part == ".." || part == "." {
+			return false
+		}
+	}
+	for _, c := range name {
+		if c < 0x20 || c == 0x7f {
+			return false
+		}
+	}
+	return true
+}
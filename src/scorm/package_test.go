@@ -0,0 +1,203 @@
+package scorm
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var testLimits = Limits{MaxFiles: 10, MaxFileSize: 8 << 10, MaxTotalSize: 32 << 10}
+
+// manifestXML tạo imsmanifest.xml với một SCO trỏ tới href
+func manifestXML(version, href, extra string) string {
+	return fmt.Sprintf(`<?xml version="1.0"?>
+<manifest identifier="course-1" xmlns:adlcp="http://www.adlnet.org/xsd/adlcp_rootv1p2">
+  <metadata><schemaversion>%s</schemaversion></metadata>
+  <organizations default="org-2">
+    <organization identifier="org-1"><title>Khác</title><item identifier="i0" identifierref="r0"/></organization>
+    <organization identifier="org-2">
+      <title> Bài SCORM </title>
+      <item identifier="folder"><title>Chương 1</title>
+        <item identifier="i1" identifierref="r1" parameters="?lang=vi"><title>Bài 1</title>%s</item>
+      </item>
+    </organization>
+  </organizations>
+  <resources>
+    <resource identifier="r0" href="other.html"/>
+    <resource identifier="r1" adlcp:scormtype="sco" xml:base="content/" href="%s"/>
+  </resources>
+</manifest>`, version, extra, href)
+}
+
+func zipFiles(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		io.WriteString(w, content)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestParseManifest(t *testing.T) {
+	manifest, err := ParseManifest([]byte(manifestXML("1.2", "index%20page.html?mode=a#top", "<adlcp:masteryscore>80</adlcp:masteryscore>")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mastery := 80.0
+	want := &Manifest{
+		Identifier:   "course-1",
+		Version:      Version,
+		Title:        "Bài SCORM",
+		LaunchPath:   "content/index page.html",
+		LaunchQuery:  "?mode=a&lang=vi#top",
+		MasteryScore: &mastery,
+	}
+	if !reflect.DeepEqual(manifest, want) {
+		t.Errorf("got %+v, want %+v", manifest, want)
+	}
+}
+
+func TestParseManifestRejectsInvalidInput(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr error
+	}{
+		{"not xml", "<manifest", ErrInvalidPackage},
+		{"scorm 2004", manifestXML("2004 3rd Edition", "index.html", ""), ErrUnsupportedVersion},
+		{"no organization", `<manifest><organizations/></manifest>`, ErrInvalidPackage},
+		{"no launchable item", `<manifest><organizations><organization><item identifierref="missing"/></organization></organizations></manifest>`, ErrInvalidPackage},
+		{"parent traversal", manifestXML("1.2", "../../../etc/passwd", ""), ErrInvalidPackage},
+		{"encoded traversal", manifestXML("1.2", "%2e%2e/%2e%2e/secret.html", ""), ErrInvalidPackage},
+		{"absolute path", manifestXML("1.2", "/index.html", ""), ErrInvalidPackage},
+		{"external url", manifestXML("1.2", "https://evil.com/index.html", ""), ErrInvalidPackage},
+		{"backslash", manifestXML("1.2", `..\..\index.html`, ""), ErrInvalidPackage},
+		{"mastery score out of range", manifestXML("1.2", "index.html", "<adlcp:masteryscore>150</adlcp:masteryscore>"), ErrInvalidPackage},
+		{"mastery score not a number", manifestXML("1.2", "index.html", "<adlcp:masteryscore>high</adlcp:masteryscore>"), ErrInvalidPackage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manifest, err := ParseManifest([]byte(tt.data))
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("got %+v, error %v, want %v", manifest, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestOpenStripsWrapperDirectory(t *testing.T) {
+	data := zipFiles(t, map[string]string{
+		"course/" + ManifestName:          manifestXML("", "index.html", ""),
+		"course/content/index.html":       "<html></html>",
+		"course/content/assets/style.css": "body{}",
+		"outside.txt":                     "x",
+	})
+
+	pkg, err := Open(bytes.NewReader(data), int64(len(data)), testLimits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pkg.Manifest.LaunchPath != "content/index.html" {
+		t.Errorf("launch path %q", pkg.Manifest.LaunchPath)
+	}
+
+	want := []string{"content/assets/style.css", "content/index.html", ManifestName}
+	if got := pkg.Files(); !reflect.DeepEqual(got, want) {
+		t.Errorf("files %v, want %v", got, want)
+	}
+
+	rc, size, err := pkg.OpenFile("content/index.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+	if content, _ := io.ReadAll(rc); string(content) != "<html></html>" || size != 13 {
+		t.Errorf("got %q (%d bytes)", content, size)
+	}
+	if _, _, err := pkg.OpenFile("outside.txt"); err == nil {
+		t.Error("files outside the package root must not be served")
+	}
+}
+
+func TestOpenRejectsMalformedPackages(t *testing.T) {
+	manifest := manifestXML("1.2", "index.html", "")
+	big := strings.Repeat("a", int(testLimits.MaxFileSize)+1)
+
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr error
+	}{
+		{"not a zip", []byte("not a zip"), ErrInvalidPackage},
+		{"missing manifest", zipFiles(t, map[string]string{"content/index.html": "x"}), ErrInvalidPackage},
+		{"manifest nested too deep", zipFiles(t, map[string]string{"a/b/" + ManifestName: manifest, "a/b/content/index.html": "x"}), ErrInvalidPackage},
+		{"multiple wrapped manifests", zipFiles(t, map[string]string{"a/" + ManifestName: manifest, "b/" + ManifestName: manifest}), ErrInvalidPackage},
+		{"missing launch file", zipFiles(t, map[string]string{ManifestName: manifest}), ErrInvalidPackage},
+		{"path traversal entry", zipFiles(t, map[string]string{ManifestName: manifest, "content/index.html": "x", "../../evil.sh": "x"}), ErrInvalidPackage},
+		{"absolute entry", zipFiles(t, map[string]string{ManifestName: manifest, "content/index.html": "x", "/etc/passwd": "x"}), ErrInvalidPackage},
+		{"file too large", zipFiles(t, map[string]string{ManifestName: manifest, "content/index.html": big}), ErrPackageTooLarge},
+		{"total too large", zipFiles(t, map[string]string{ManifestName: manifest, "content/index.html": "x", "1": big[:7000], "2": big[:7000], "3": big[:7000], "4": big[:7000], "5": big[:7000]}), ErrPackageTooLarge},
+		{"too many files", zipFiles(t, map[string]string{ManifestName: manifest, "content/index.html": "x", "1": "", "2": "", "3": "", "4": "", "5": "", "6": "", "7": "", "8": "", "9": ""}), ErrPackageTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Open(bytes.NewReader(tt.data), int64(len(tt.data)), testLimits)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("error %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidEntryName(t *testing.T) {
+	valid := []string{"index.html", "content/a b.html", "Bài 1/index.html"}
+	invalid := []string{"", "/index.html", "../index.html", "a/../../b", "a/./b", "a//b", "a/", `a\b`, "a\x00b", "a\nb", strings.Repeat("a", 256)}
+
+	for _, name := range valid {
+		if !ValidEntryName(name) {
+			t.Errorf("ValidEntryName(%q) = false, want true", name)
+		}
+	}
+	for _, name := range invalid {
+		if ValidEntryName(name) {
+			t.Errorf("ValidEntryName(%q) = true, want false", name)
+		}
+	}
+}
+
+func TestJoinLaunchParameters(t *testing.T) {
+	tests := []struct {
+		href, parameters, want string
+	}{
+		{"index.html", "", "index.html"},
+		{"index.html", "?a=1", "index.html?a=1"},
+		{"index.html", "a=1", "index.html?a=1"},
+		{"index.html", "&a=1", "index.html?a=1"},
+		{"index.html?x=0", "?a=1", "index.html?x=0&a=1"},
+		{"index.html?x=0", "a=1", "index.html?x=0&a=1"},
+		{"index.html#top", "?a=1", "index.html?a=1#top"},
+		{"index.html", "#page2", "index.html#page2"},
+		{"index.html#top", "?a=1#page2", "index.html?a=1#top"},
+	}
+	for _, tt := range tests {
+		if got := joinLaunchParameters(tt.href, tt.parameters); got != tt.want {
+			t.Errorf("joinLaunchParameters(%q, %q) = %q, want %q", tt.href, tt.parameters, got, tt.want)
+		}
+	}
+}
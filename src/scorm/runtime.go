@@ -0,0 +1,176 @@
+package scorm
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+// Giá trị của cmi.core.lesson_status
+const (
+	StatusPassed       = "passed"
+	StatusCompleted    = "completed"
+	StatusFailed       = "failed"
+	StatusIncomplete   = "incomplete"
+	StatusBrowsed      = "browsed"
+	StatusNotAttempted = "not attempted"
+)
+
+// Giá trị của cmi.core.entry
+const (
+	EntryAbInitio = "ab-initio"
+	EntryResume   = "resume"
+)
+
+// Các element của data model SCORM 1.2 mà LMS lưu lại (RTE 3.4)
+const (
+	ElementLessonStatus   = "cmi.core.lesson_status"
+	ElementLessonLocation = "cmi.core.lesson_location"
+	ElementScoreRaw       = "cmi.core.score.raw"
+	ElementScoreMin       = "cmi.core.score.min"
+	ElementScoreMax       = "cmi.core.score.max"
+	ElementExit           = "cmi.core.exit"
+	ElementSessionTime    = "cmi.core.session_time"
+	ElementSuspendData    = "cmi.suspend_data"
+)
+
+// Mã lỗi của API SCORM 1.2 (LMSGetLastError)
+const (
+	ErrCodeInvalidArgument   = 201
+	ErrCodeNotImplemented    = 401
+	ErrCodeReadOnly          = 403
+	ErrCodeIncorrectDataType = 405
+)
+
+// Error là lỗi khi LMSSetValue, Code là mã lỗi SCORM trả về cho nội dung qua LMSGetLastError
+type Error struct {
+	Code    int
+	Element string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s (error %d)", e.Element, e.Message, e.Code)
+}
+
+var (
+	decimalPattern  = regexp.MustCompile(`^-?\d+(\.\d+)?$`)
+	timespanPattern = regexp.MustCompile(`^(\d{2,4}):(\d{2}):(\d{2})(\.\d{1,2})?$`)
+)
+
+// Element chỉ đọc (LMS cung cấp khi LMSInitialize), ghi vào trả lỗi 403
+var readOnlyElements = map[string]bool{
+	"cmi.core._children":             true,
+	"cmi.core.student_id":            true,
+	"cmi.core.student_name":          true,
+	"cmi.core.credit":                true,
+	"cmi.core.entry":                 true,
+	"cmi.core.total_time":            true,
+	"cmi.core.lesson_mode":           true,
+	"cmi.core.score._children":       true,
+	"cmi.launch_data":                true,
+	"cmi.student_data.mastery_score": true,
+}
+
+// ValidateValue kiểm tra giá trị LMSSetValue theo kiểu dữ liệu của element
+func ValidateValue(element, value string) error {
+	invalid := func(message string) error {
+		return &Error{Code: ErrCodeIncorrectDataType, Element: element, Message: message}
+	}
+
+	switch element {
+	case ElementLessonStatus:
+		switch value {
+		case StatusPassed, StatusCompleted, StatusFailed, StatusIncomplete, StatusBrowsed:
+			return nil
+		}
+		return invalid("must be one of passed, completed, failed, incomplete, browsed")
+	case ElementLessonLocation:
+		if utf8.RuneCountInString(value) > 255 {
+			return invalid("must be at most 255 characters")
+		}
+	case ElementSuspendData:
+		if utf8.RuneCountInString(value) > 4096 {
+			return invalid("must be at most 4096 characters")
+		}
+	case ElementScoreRaw, ElementScoreMin, ElementScoreMax:
+		if value == "" {
+			return nil
+		}
+		score, err := strconv.ParseFloat(value, 64)
+		if !decimalPattern.MatchString(value) || err != nil || score < 0 || score > 100 {
+			return invalid("must be a decimal between 0 and 100")
+		}
+	case ElementExit:
+		switch value {
+		case "", "time-out", "suspend", "logout":
+			return nil
+		}
+		return invalid("must be one of time-out, suspend, logout or empty")
+	case ElementSessionTime:
+		if _, err := ParseTimespan(value); err != nil {
+			return invalid("must be a CMITimespan (HHHH:MM:SS.SS)")
+		}
+	default:
+		if readOnlyElements[element] {
+			return &Error{Code: ErrCodeReadOnly, Element: element, Message: "element is read only"}
+		}
+		return &Error{Code: ErrCodeNotImplemented, Element: element, Message: "element is not supported"}
+	}
+	return nil
+}
+
+// ParseTimespan đọc CMITimespan dạng HHHH:MM:SS.SS (giờ 2-4 chữ số, phần trăm giây tùy chọn)
+func ParseTimespan(value string) (time.Duration, error) {
+	match := timespanPattern.FindStringSubmatch(value)
+	if match == nil {
+		return 0, fmt.Errorf("invalid timespan %q", value)
+	}
+
+	hours, _ := strconv.Atoi(match[1])
+	minutes, _ := strconv.Atoi(match[2])
+	seconds, _ := strconv.Atoi(match[3])
+	if minutes > 59 || seconds > 59 {
+		return 0, fmt.Errorf("invalid timespan %q", value)
+	}
+
+	duration := time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute + time.Duration(seconds)*time.Second
+	if match[4] != "" {
+		fraction, _ := strconv.ParseFloat("0"+match[4], 64)
+		duration += time.Duration(fraction * float64(time.Second))
+	}
+	return duration, nil
+}
+
+// FormatTimespan trả về CMITimespan dạng HHHH:MM:SS.SS cho cmi.core.total_time
+func FormatTimespan(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	centiseconds := int64(d / (10 * time.Millisecond))
+	return fmt.Sprintf("%04d:%02d:%02d.%02d",
+		centiseconds/360000, centiseconds/6000%60, centiseconds/100%60, centiseconds%100)
+}
+
+// IsCompleted - lesson_status passed hoặc completed được tính là hoàn thành lesson
+func IsCompleted(status string) bool {
+	return status == StatusPassed || status == StatusCompleted
+}
+
+// ApplyMasteryScore - khi package khai báo masteryscore và nội dung đã báo điểm,
+// LMS quyết định passed/failed theo điểm thay vì status nội dung gửi lên (RTE 3.4.4)
+func ApplyMasteryScore(status string, scoreRaw, masteryScore *float64) string {
+	if masteryScore == nil || scoreRaw == nil {
+		return status
+	}
+	switch status {
+	case StatusCompleted, StatusPassed, StatusFailed:
+		if *scoreRaw >= *masteryScore {
+			return StatusPassed
+		}
+		return StatusFailed
+	}
+	return status
+}
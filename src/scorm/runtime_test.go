@@ -0,0 +1,108 @@
+package scorm
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateValue(t *testing.T) {
+	tests := []struct {
+		element  string
+		value    string
+		wantCode int // 0 là hợp lệ
+	}{
+		{ElementLessonStatus, StatusPassed, 0},
+		{ElementLessonStatus, StatusNotAttempted, ErrCodeIncorrectDataType},
+		{ElementLessonStatus, "PASSED", ErrCodeIncorrectDataType},
+		{ElementScoreRaw, "", 0},
+		{ElementScoreRaw, "87.5", 0},
+		{ElementScoreRaw, "101", ErrCodeIncorrectDataType},
+		{ElementScoreRaw, "-1", ErrCodeIncorrectDataType},
+		{ElementScoreRaw, "1e2", ErrCodeIncorrectDataType},
+		{ElementScoreMax, "NaN", ErrCodeIncorrectDataType},
+		{ElementExit, "suspend", 0},
+		{ElementExit, "quit", ErrCodeIncorrectDataType},
+		{ElementSessionTime, "0001:30:05.25", 0},
+		{ElementSessionTime, "1:30:05", ErrCodeIncorrectDataType},
+		{ElementLessonLocation, strings.Repeat("ă", 255), 0},
+		{ElementLessonLocation, strings.Repeat("a", 256), ErrCodeIncorrectDataType},
+		{ElementSuspendData, strings.Repeat("a", 4097), ErrCodeIncorrectDataType},
+		{"cmi.core.student_id", "42", ErrCodeReadOnly},
+		{"cmi.core.total_time", "0000:00:00", ErrCodeReadOnly},
+		{"cmi.interactions.0.id", "q1", ErrCodeNotImplemented},
+	}
+
+	for _, tt := range tests {
+		err := ValidateValue(tt.element, tt.value)
+		if tt.wantCode == 0 {
+			if err != nil {
+				t.Errorf("ValidateValue(%s, %.20q) = %v, want nil", tt.element, tt.value, err)
+			}
+			continue
+		}
+
+		var scormErr *Error
+		if !errors.As(err, &scormErr) || scormErr.Code != tt.wantCode {
+			t.Errorf("ValidateValue(%s, %.20q) = %v, want code %d", tt.element, tt.value, err, tt.wantCode)
+		}
+	}
+}
+
+func TestTimespan(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"00:00:00", 0},
+		{"01:02:03.5", time.Hour + 2*time.Minute + 3500*time.Millisecond},
+		{"9999:59:59.99", 9999*time.Hour + 59*time.Minute + 59990*time.Millisecond},
+	}
+	for _, tt := range tests {
+		got, err := ParseTimespan(tt.value)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseTimespan(%q) = %v, %v, want %v", tt.value, got, err, tt.want)
+		}
+	}
+
+	for _, value := range []string{"", "00:60:00", "00:00:60", "00000:00:00", "00:00:00.123", "-1:00:00"} {
+		if _, err := ParseTimespan(value); err == nil {
+			t.Errorf("ParseTimespan(%q) should fail", value)
+		}
+	}
+
+	if got := FormatTimespan(26*time.Hour + 5*time.Second + 120*time.Millisecond); got != "0026:00:05.12" {
+		t.Errorf("FormatTimespan = %q", got)
+	}
+	if got := FormatTimespan(-time.Second); got != "0000:00:00.00" {
+		t.Errorf("FormatTimespan(negative) = %q", got)
+	}
+}
+
+func TestApplyMasteryScore(t *testing.T) {
+	score := func(v float64) *float64 { return &v }
+
+	tests := []struct {
+		status  string
+		raw     *float64
+		mastery *float64
+		want    string
+	}{
+		{StatusCompleted, score(85), score(80), StatusPassed},
+		{StatusPassed, score(70), score(80), StatusFailed},
+		{StatusFailed, score(80), score(80), StatusPassed},
+		{StatusIncomplete, score(90), score(80), StatusIncomplete},
+		{StatusCompleted, nil, score(80), StatusCompleted},
+		{StatusFailed, score(90), nil, StatusFailed},
+	}
+	for _, tt := range tests {
+		if got := ApplyMasteryScore(tt.status, tt.raw, tt.mastery); got != tt.want {
+			t.Errorf("ApplyMasteryScore(%q, %v, %v) = %q, want %q", tt.status, tt.raw, tt.mastery, got, tt.want)
+		}
+	}
+
+	if !IsCompleted(StatusPassed) || !IsCompleted(StatusCompleted) || IsCompleted(StatusFailed) {
+		t.Error("IsCompleted")
+	}
+}
@@ -11,14 +11,16 @@ import (
 )
 
 type adminService struct {
-	userRepo   repository.UserRepository
-	courseRepo repository.CourseRepository
+	userRepo        repository.UserRepository
+	courseRepo      repository.CourseRepository
+	certificateRepo repository.CertificateRepository
 }
 
-func NewAdminService(userRepo repository.UserRepository, courseRepo repository.CourseRepository) AdminService {
+func NewAdminService(userRepo repository.UserRepository, courseRepo repository.CourseRepository, certificateRepo repository.CertificateRepository) AdminService {
 	return &adminService{
-		userRepo:   userRepo,
-		courseRepo: courseRepo,
+		userRepo:        userRepo,
+		courseRepo:      courseRepo,
+		certificateRepo: certificateRepo,
 	}
 }
 
@@ -176,6 +178,11 @@ func (as *adminService) UpdateUser(userId uint, req *dto.UpdateUserRequest) (*dt
 		return nil, utils.WrapError(err, "Failed to get updated user", utils.ErrCodeInternal)
 	}
 
+	// 5. Đổi họ tên thì cấp lại chứng chỉ với tên mới
+	if updatedUser.FullName != existingUser.FullName {
+		reissueCertificates(as.certificateRepo, userId, updatedUser.FullName)
+	}
+
 	return &dto.UpdateUserResponse{
 		Id:            updatedUser.Id,
 		Username:      updatedUser.Username,
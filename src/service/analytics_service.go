@@ -7,12 +7,20 @@ import (
 )
 
 type analyticsService struct {
-	analyticsRepo repository.AnalyticsRepository
+	analyticsRepo  repository.AnalyticsRepository
+	instructorRepo repository.InstructorRepository
+	progressRepo   repository.ProgressRepository
 }
 
-func NewAnalyticsService(analyticsRepo repository.AnalyticsRepository) AnalyticsService {
+func NewAnalyticsService(
+	analyticsRepo repository.AnalyticsRepository,
+	instructorRepo repository.InstructorRepository,
+	progressRepo repository.ProgressRepository,
+) AnalyticsService {
 	return &analyticsService{
-		analyticsRepo: analyticsRepo,
+		analyticsRepo:  analyticsRepo,
+		instructorRepo: instructorRepo,
+		progressRepo:   progressRepo,
 	}
 }
 
@@ -42,3 +50,86 @@ func (as *analyticsService) GetStudentAnalytics(instructorId uint, req *dto.Stud
 
 	return analytics, nil
 }
+
+func (as *analyticsService) GetLessonEngagement(instructorId, courseId, lessonId uint) (*dto.LessonEngagementResponse, error) {
+	// 1. Kiểm tra quyền trên course và lesson
+	if _, err := as.instructorRepo.FindCourseByIdAndInstructor(courseId, instructorId); err != nil {
+		return nil, utils.NewError("Course not found or you don't have permission", utils.ErrCodeNotFound)
+	}
+	lesson, err := as.instructorRepo.FindLessonByIdAndCourse(lessonId, courseId)
+	if err != nil {
+		return nil, utils.NewError("Lesson not found", utils.ErrCodeNotFound)
+	}
+
+	// 2. Lấy số học viên và progress của lesson
+	enrolled, err := as.instructorRepo.CountEnrollmentsByCourse(courseId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to count enrollments", utils.ErrCodeInternal)
+	}
+	progressList, err := as.progressRepo.GetLessonProgressList(lessonId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get lesson progress", utils.ErrCodeInternal)
+	}
+
+	response := &dto.LessonEngagementResponse{
+		LessonId:         lesson.Id,
+		LessonTitle:      lesson.Title,
+		VideoDuration:    lesson.VideoDuration,
+		EnrolledStudents: int(enrolled),
+		Retention:        []dto.LessonRetentionPoint{},
+	}
+	required := requiredWatchSeconds(lesson)
+	if required > 0 {
+		response.RequiredWatchPercent = watchMinPercent()
+	}
+
+	// 3. Chia video thành các đoạn bằng nhau để tính retention
+	bucketCount := min(retentionBucketCount, lesson.VideoDuration)
+	for i := 0; i < bucketCount; i++ {
+		response.Retention = append(response.Retention, dto.LessonRetentionPoint{
+			Start: lesson.VideoDuration * i / bucketCount,
+			End:   lesson.VideoDuration * (i + 1) / bucketCount,
+		})
+	}
+
+	// 4. Tổng hợp từ các đoạn đã xem của từng học viên
+	totalWatched := 0
+	for _, p := range progressList {
+		if p.IsCompleted {
+			response.CompletedStudents++
+		}
+
+		watched := watchedSeconds(p.WatchedIntervals)
+		if watched == 0 {
+			continue
+		}
+		response.Viewers++
+		totalWatched += watched
+		if required > 0 && watched >= required {
+			response.ReachedRequiredCount++
+		}
+
+		// Học viên được tính cho một đoạn nếu đã xem ít nhất một nửa đoạn đó
+		for i := range response.Retention {
+			point := &response.Retention[i]
+			if coveredSeconds(p.WatchedIntervals, point.Start, point.End)*2 >= point.End-point.Start {
+				point.Viewers++
+			}
+		}
+	}
+
+	if enrolled > 0 {
+		response.CompletionRate = float64(response.CompletedStudents) / float64(enrolled) * 100
+	}
+	if response.Viewers > 0 {
+		response.AverageWatchedSeconds = float64(totalWatched) / float64(response.Viewers)
+		if lesson.VideoDuration > 0 {
+			response.AverageWatchedPercent = response.AverageWatchedSeconds / float64(lesson.VideoDuration) * 100
+		}
+		for i := range response.Retention {
+			response.Retention[i].Percent = float64(response.Retention[i].Viewers) / float64(response.Viewers) * 100
+		}
+	}
+
+	return response, nil
+}
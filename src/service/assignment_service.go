@@ -0,0 +1,819 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"lms/src/dto"
+	"lms/src/models"
+	"lms/src/repository"
+	"lms/src/storage"
+	"lms/src/utils"
+	"log"
+	"math"
+	"mime/multipart"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	lessonTypeAssignment = "assignment"
+
+	submissionStatusSubmitted  = "submitted"
+	submissionStatusGraded     = "graded"
+	submissionStatusSuperseded = "superseded"
+)
+
+type assignmentService struct {
+	assignmentRepo  repository.AssignmentRepository
+	instructorRepo  repository.InstructorRepository
+	lessonRepo      repository.LessonRepository
+	enrollmentRepo  repository.EnrollmentRepository
+	progressService ProgressService
+	storage         storage.Backend
+}
+
+func NewAssignmentService(
+	assignmentRepo repository.AssignmentRepository,
+	instructorRepo repository.InstructorRepository,
+	lessonRepo repository.LessonRepository,
+	enrollmentRepo repository.EnrollmentRepository,
+	progressService ProgressService,
+	storage storage.Backend,
+) AssignmentService {
+	return &assignmentService{
+		assignmentRepo:  assignmentRepo,
+		instructorRepo:  instructorRepo,
+		lessonRepo:      lessonRepo,
+		enrollmentRepo:  enrollmentRepo,
+		progressService: progressService,
+		storage:         storage,
+	}
+}
+
+func (as *assignmentService) GetLessonAssignment(instructorId, courseId, lessonId uint) (*dto.InstructorAssignmentResponse, error) {
+	// 1. Kiểm tra quyền trên course và lesson
+	if _, err := as.findInstructorLesson(instructorId, courseId, lessonId); err != nil {
+		return nil, err
+	}
+
+	// 2. Lấy assignment của lesson
+	assignment, err := as.assignmentRepo.FindByLessonId(lessonId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get assignment", utils.ErrCodeInternal)
+	}
+	if assignment == nil {
+		return nil, utils.NewError("This lesson has no assignment yet", utils.ErrCodeNotFound)
+	}
+
+	return toInstructorAssignmentResponse(assignment), nil
+}
+
+func (as *assignmentService) UpsertLessonAssignment(instructorId, courseId, lessonId uint, req *dto.UpsertAssignmentRequest) (*dto.InstructorAssignmentResponse, error) {
+	// 1. Kiểm tra quyền trên course và lesson
+	lesson, err := as.findInstructorLesson(instructorId, courseId, lessonId)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Hạn nộp chỉ dùng một trong hai: ngày cố định hoặc số ngày sau khi enroll
+	if req.DueAt != nil && req.DueDaysAfterEnrollment != nil {
+		return nil, utils.NewError("Use either due_at or due_days_after_enrollment, not both", utils.ErrCodeBadRequest)
+	}
+
+	// 3. Chưa có assignment thì tạo mới với giá trị mặc định
+	assignment, err := as.assignmentRepo.FindByLessonId(lessonId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get assignment", utils.ErrCodeInternal)
+	}
+	if assignment == nil {
+		assignment = &models.Assignment{
+			LessonId: lessonId,
+			CourseId: courseId,
+		}
+		if err := as.assignmentRepo.CreateAssignment(assignment); err != nil {
+			return nil, utils.WrapError(err, "Failed to create assignment", utils.ErrCodeInternal)
+		}
+	}
+
+	// 4. Chuẩn bị dữ liệu update
+	// (update riêng sau khi tạo vì các field có default trong gorm sẽ bỏ qua giá trị zero khi Create)
+	updates := make(map[string]interface{})
+
+	if req.Instructions != nil {
+		updates["instructions"] = *req.Instructions
+	}
+
+	if req.ClearDueDate {
+		updates["due_at"] = nil
+		updates["due_days_after_enrollment"] = nil
+	} else if req.DueAt != nil {
+		updates["due_at"] = *req.DueAt
+		updates["due_days_after_enrollment"] = nil
+	} else if req.DueDaysAfterEnrollment != nil {
+		updates["due_days_after_enrollment"] = *req.DueDaysAfterEnrollment
+		updates["due_at"] = nil
+	}
+
+	if req.PassPercentage != nil {
+		updates["pass_percentage"] = *req.PassPercentage
+	}
+	if req.AllowResubmission != nil {
+		updates["allow_resubmission"] = *req.AllowResubmission
+	}
+	if req.MaxSubmissions != nil {
+		updates["max_submissions"] = *req.MaxSubmissions
+	}
+	if req.MaxFiles != nil {
+		updates["max_files"] = *req.MaxFiles
+	}
+	if req.AllowLateSubmission != nil {
+		updates["allow_late_submission"] = *req.AllowLateSubmission
+	}
+	if req.LatePenaltyPercent != nil {
+		updates["late_penalty_percent"] = *req.LatePenaltyPercent
+	}
+	if req.MaxLatePenaltyPercent != nil {
+		updates["max_late_penalty_percent"] = *req.MaxLatePenaltyPercent
+	}
+
+	// max_points chỉ set trực tiếp khi không dùng rubric
+	hasRubric := len(assignment.Rubric) > 0
+	if req.ClearRubric {
+		hasRubric = false
+	} else if req.Rubric != nil {
+		hasRubric = len(req.Rubric) > 0
+	}
+	if req.MaxPoints != nil && !hasRubric {
+		updates["max_points"] = *req.MaxPoints
+	}
+
+	if len(updates) > 0 {
+		if err := as.assignmentRepo.UpdateAssignment(assignment.Id, updates); err != nil {
+			return nil, utils.WrapError(err, "Failed to update assignment", utils.ErrCodeInternal)
+		}
+	}
+
+	// 5. Cập nhật rubric, max_points = tổng điểm các tiêu chí
+	if req.ClearRubric {
+		assignment.Rubric = []models.RubricCriterion{}
+		if req.MaxPoints != nil {
+			assignment.MaxPoints = *req.MaxPoints
+		}
+		if err := as.assignmentRepo.UpdateRubric(assignment); err != nil {
+			return nil, utils.WrapError(err, "Failed to update rubric", utils.ErrCodeInternal)
+		}
+	} else if len(req.Rubric) > 0 {
+		rubric := make([]models.RubricCriterion, len(req.Rubric))
+		totalPoints := 0.0
+		for i, criterion := range req.Rubric {
+			rubric[i] = models.RubricCriterion{
+				Key:         uuid.NewString()[:8],
+				Title:       criterion.Title,
+				Description: criterion.Description,
+				MaxPoints:   criterion.MaxPoints,
+			}
+			totalPoints += criterion.MaxPoints
+		}
+
+		assignment.Rubric = rubric
+		assignment.MaxPoints = totalPoints
+		if err := as.assignmentRepo.UpdateRubric(assignment); err != nil {
+			return nil, utils.WrapError(err, "Failed to update rubric", utils.ErrCodeInternal)
+		}
+	}
+
+	// 6. Lesson có assignment thì chuyển sang lesson_type = assignment
+	if lesson.LessonType != lessonTypeAssignment {
+		if err := as.instructorRepo.UpdateLesson(lessonId, map[string]interface{}{"lesson_type": lessonTypeAssignment}); err != nil {
+			return nil, utils.WrapError(err, "Failed to update lesson type", utils.ErrCodeInternal)
+		}
+	}
+
+	return as.GetLessonAssignment(instructorId, courseId, lessonId)
+}
+
+func (as *assignmentService) GetGradingQueue(instructorId uint, req *dto.GetGradingQueueQueryRequest) (*dto.GetGradingQueueResponse, error) {
+	// Set default values
+	page := 1
+	if req.Page > 0 {
+		page = req.Page
+	}
+
+	limit := 20
+	if req.Limit > 0 {
+		limit = req.Limit
+	}
+
+	offset := (page - 1) * limit
+
+	// Mặc định chỉ hiển thị bài nộp chưa chấm
+	filters := make(map[string]interface{})
+	switch req.Status {
+	case "":
+		filters["status"] = submissionStatusSubmitted
+	case "all":
+	default:
+		filters["status"] = req.Status
+	}
+
+	if req.CourseId != 0 {
+		filters["course_id"] = req.CourseId
+	}
+	if req.LessonId != 0 {
+		filters["lesson_id"] = req.LessonId
+	}
+
+	submissions, total, err := as.assignmentRepo.GetGradingQueue(instructorId, offset, limit, filters)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get grading queue", utils.ErrCodeInternal)
+	}
+
+	items := make([]dto.GradingQueueItem, len(submissions))
+	for i, submission := range submissions {
+		items[i] = dto.GradingQueueItem{
+			SubmissionId:       submission.Id,
+			CourseId:           submission.CourseId,
+			CourseTitle:        submission.Course.Title,
+			LessonId:           submission.LessonId,
+			LessonTitle:        submission.Lesson.Title,
+			StudentId:          submission.UserId,
+			StudentName:        submission.User.FullName,
+			StudentEmail:       submission.User.Email,
+			SubmissionNumber:   submission.SubmissionNumber,
+			Status:             submission.Status,
+			SubmittedAt:        submission.SubmittedAt,
+			IsLate:             submission.IsLate,
+			LateDays:           submission.LateDays,
+			LatePenaltyPercent: submission.LatePenaltyPercent,
+			TotalFiles:         len(submission.Files),
+			FinalScore:         submission.FinalScore,
+			MaxPoints:          submission.MaxPoints,
+		}
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+
+	return &dto.GetGradingQueueResponse{
+		Submissions: items,
+		Pagination: dto.PaginationInfo{
+			Page:       page,
+			Limit:      limit,
+			Total:      total,
+			TotalPages: totalPages,
+			HasNext:    page < totalPages,
+			HasPrev:    page > 1,
+		},
+	}, nil
+}
+
+func (as *assignmentService) GetSubmissionForGrading(instructorId, submissionId uint) (*dto.AssignmentSubmissionDetail, error) {
+	submission, err := as.findInstructorSubmission(instructorId, submissionId)
+	if err != nil {
+		return nil, err
+	}
+
+	return toAssignmentSubmissionDetail(submission, &submission.Assignment), nil
+}
+
+func (as *assignmentService) GradeSubmission(instructorId, submissionId uint, req *dto.GradeSubmissionRequest) (*dto.AssignmentSubmissionDetail, error) {
+	// 1. Kiểm tra bài nộp thuộc course của instructor
+	submission, err := as.findInstructorSubmission(instructorId, submissionId)
+	if err != nil {
+		return nil, err
+	}
+
+	if submission.Status == submissionStatusSuperseded {
+		return nil, utils.NewError("This submission has been replaced by a newer one", utils.ErrCodeBadRequest)
+	}
+
+	assignment := &submission.Assignment
+
+	// 2. Tính điểm thô theo rubric hoặc điểm tổng
+	rawScore, rubricScores, err := scoreSubmission(assignment, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Trừ điểm nộp trễ đã chốt lúc nộp bài
+	finalScore := applyLatePenalty(rawScore, submission.LatePenaltyPercent)
+	passed := false
+	if assignment.MaxPoints > 0 {
+		passed = finalScore/assignment.MaxPoints*100 >= assignment.PassPercentage
+	}
+
+	// 4. Lưu kết quả chấm (cho phép chấm lại)
+	now := time.Now()
+	submission.Status = submissionStatusGraded
+	submission.RubricScores = rubricScores
+	submission.RawScore = &rawScore
+	submission.FinalScore = &finalScore
+	submission.MaxPoints = assignment.MaxPoints
+	submission.Passed = passed
+	submission.Feedback = req.Feedback
+	submission.GradedBy = &instructorId
+	submission.GradedAt = &now
+
+	if err := as.assignmentRepo.GradeSubmission(submission); err != nil {
+		return nil, utils.WrapError(err, "Failed to grade submission", utils.ErrCodeInternal)
+	}
+
+	// 5. Đạt điểm pass thì hoàn thành lesson cho học viên
+	lessonCompleted := false
+	if passed {
+		lessonCompleted = as.completeAssignmentLesson(submission.UserId, submission.LessonId)
+	}
+
+	detail := toAssignmentSubmissionDetail(submission, assignment)
+	detail.LessonCompleted = lessonCompleted
+
+	return detail, nil
+}
+
+func (as *assignmentService) GetInstructorSubmissionFile(instructorId, submissionId uint, fileId string) (*PrivateFile, error) {
+	submission, err := as.findInstructorSubmission(instructorId, submissionId)
+	if err != nil {
+		return nil, err
+	}
+
+	return as.openSubmissionFile(submission, fileId)
+}
+
+func (as *assignmentService) GetAssignmentInfo(userId, lessonId uint) (*dto.AssignmentInfoResponse, error) {
+	// 1. Kiểm tra lesson, enrollment và assignment
+	lesson, assignment, enrollment, err := as.findStudentAssignment(userId, lessonId)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Lấy các bài đã nộp để tính quyền nộp tiếp
+	submissions, err := as.assignmentRepo.GetUserSubmissions(userId, assignment.Id)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get submissions", utils.ErrCodeInternal)
+	}
+
+	dueAt := effectiveDueAt(assignment, enrollment.EnrolledAt)
+	reason := cannotSubmitReason(assignment, submissions, dueAt, time.Now())
+
+	response := &dto.AssignmentInfoResponse{
+		AssignmentId:          assignment.Id,
+		LessonId:              lesson.Id,
+		LessonTitle:           lesson.Title,
+		CourseId:              lesson.CourseId,
+		Instructions:          assignment.Instructions,
+		DueAt:                 dueAt,
+		MaxPoints:             assignment.MaxPoints,
+		PassPercentage:        assignment.PassPercentage,
+		Rubric:                toRubricCriterionItems(assignment.Rubric),
+		AllowResubmission:     assignment.AllowResubmission,
+		MaxSubmissions:        assignment.MaxSubmissions,
+		MaxFiles:              assignment.MaxFiles,
+		AllowLateSubmission:   assignment.AllowLateSubmission,
+		LatePenaltyPercent:    assignment.LatePenaltyPercent,
+		MaxLatePenaltyPercent: assignment.MaxLatePenaltyPercent,
+		SubmissionsUsed:       len(submissions),
+		CanSubmit:             reason == "",
+		CannotSubmitReason:    reason,
+	}
+
+	for _, submission := range submissions {
+		if submission.Status == submissionStatusGraded && submission.Passed {
+			response.Passed = true
+		}
+	}
+
+	if len(submissions) > 0 {
+		latest := toAssignmentSubmissionSummary(&submissions[0])
+		response.LatestSubmission = &latest
+	}
+
+	return response, nil
+}
+
+func (as *assignmentService) SubmitAssignment(userId, lessonId uint, req *dto.SubmitAssignmentRequest, files []*multipart.FileHeader) (*dto.AssignmentSubmissionDetail, error) {
+	// 1. Kiểm tra lesson, enrollment và assignment
+	_, assignment, enrollment, err := as.findStudentAssignment(userId, lessonId)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Validate nội dung bài nộp
+	textContent := strings.TrimSpace(req.TextContent)
+	if textContent == "" && len(files) == 0 {
+		return nil, utils.NewError("Submission must include text or at least one file", utils.ErrCodeBadRequest)
+	}
+
+	if len(files) > assignment.MaxFiles {
+		return nil, utils.NewError(fmt.Sprintf("You can upload at most %d file(s) for this assignment", assignment.MaxFiles), utils.ErrCodeBadRequest)
+	}
+
+	// 3. Kiểm tra quy tắc nộp lại và hạn nộp
+	submissions, err := as.assignmentRepo.GetUserSubmissions(userId, assignment.Id)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get submissions", utils.ErrCodeInternal)
+	}
+
+	now := time.Now()
+	dueAt := effectiveDueAt(assignment, enrollment.EnrolledAt)
+	if reason := cannotSubmitReason(assignment, submissions, dueAt, now); reason != "" {
+		return nil, utils.NewError(reason, utils.ErrCodeForbidden)
+	}
+
+	// 4. Validate và lưu files vào storage
+	ctx := context.Background()
+	keyPrefix := submissionFilePrefix(assignment.CourseId, assignment.Id)
+	savedFiles := make([]models.SubmissionFile, 0, len(files))
+	for _, file := range files {
+		storedName, mimeType, err := storeUploadedFile(ctx, as.storage, keyPrefix, file, utils.AssignmentFileRules)
+		if err != nil {
+			as.removeSubmissionFiles(ctx, keyPrefix, savedFiles)
+			return nil, utils.WrapError(err, "Invalid file "+filepath.Base(file.Filename), utils.ErrCodeBadRequest)
+		}
+
+		savedFiles = append(savedFiles, models.SubmissionFile{
+			Id:           uuid.NewString(),
+			OriginalName: filepath.Base(file.Filename),
+			StoredName:   storedName,
+			Size:         file.Size,
+			MimeType:     mimeType,
+		})
+	}
+
+	// 5. Tính thông tin nộp trễ
+	submission := &models.AssignmentSubmission{
+		AssignmentId:     assignment.Id,
+		LessonId:         assignment.LessonId,
+		CourseId:         assignment.CourseId,
+		UserId:           userId,
+		SubmissionNumber: len(submissions) + 1,
+		TextContent:      textContent,
+		Files:            savedFiles,
+		Status:           submissionStatusSubmitted,
+		SubmittedAt:      now,
+		DueAt:            dueAt,
+		MaxPoints:        assignment.MaxPoints,
+		RubricScores:     []models.RubricScore{},
+	}
+
+	submission.LateDays, submission.LatePenaltyPercent = latePenalty(assignment, dueAt, now)
+	submission.IsLate = submission.LateDays > 0
+
+	// 6. Bài nộp trước chưa chấm sẽ bị thay thế bởi bài mới
+	if err := as.assignmentRepo.SupersedePendingSubmissions(userId, assignment.Id); err != nil {
+		as.removeSubmissionFiles(ctx, keyPrefix, savedFiles)
+		return nil, utils.WrapError(err, "Failed to submit assignment", utils.ErrCodeInternal)
+	}
+
+	if err := as.assignmentRepo.CreateSubmission(submission); err != nil {
+		as.removeSubmissionFiles(ctx, keyPrefix, savedFiles)
+		return nil, utils.WrapError(err, "Failed to submit assignment", utils.ErrCodeInternal)
+	}
+
+	return as.GetMySubmission(userId, submission.Id)
+}
+
+func (as *assignmentService) GetMySubmissions(userId, lessonId uint) (*dto.GetMySubmissionsResponse, error) {
+	_, assignment, _, err := as.findStudentAssignment(userId, lessonId)
+	if err != nil {
+		return nil, err
+	}
+
+	submissions, err := as.assignmentRepo.GetUserSubmissions(userId, assignment.Id)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get submissions", utils.ErrCodeInternal)
+	}
+
+	items := make([]dto.AssignmentSubmissionSummary, len(submissions))
+	for i := range submissions {
+		items[i] = toAssignmentSubmissionSummary(&submissions[i])
+	}
+
+	return &dto.GetMySubmissionsResponse{
+		AssignmentId: assignment.Id,
+		LessonId:     lessonId,
+		Submissions:  items,
+		Total:        len(items),
+	}, nil
+}
+
+func (as *assignmentService) GetMySubmission(userId, submissionId uint) (*dto.AssignmentSubmissionDetail, error) {
+	submission, err := as.assignmentRepo.FindSubmissionById(submissionId)
+	if err != nil || submission.UserId != userId {
+		return nil, utils.NewError("Submission not found", utils.ErrCodeNotFound)
+	}
+
+	detail := toAssignmentSubmissionDetail(submission, &submission.Assignment)
+	if submission.Passed {
+		progress, err := as.lessonRepo.GetLessonProgressDetail(userId, submission.LessonId)
+		if err == nil {
+			detail.LessonCompleted = progress.IsCompleted
+		}
+	}
+
+	return detail, nil
+}
+
+func (as *assignmentService) GetMySubmissionFile(userId, submissionId uint, fileId string) (*PrivateFile, error) {
+	submission, err := as.assignmentRepo.FindSubmissionById(submissionId)
+	if err != nil || submission.UserId != userId {
+		return nil, utils.NewError("Submission not found", utils.ErrCodeNotFound)
+	}
+
+	return as.openSubmissionFile(submission, fileId)
+}
+
+func (as *assignmentService) findInstructorLesson(instructorId, courseId, lessonId uint) (*models.Lesson, error) {
+	if _, err := as.instructorRepo.FindCourseByIdAndInstructor(courseId, instructorId); err != nil {
+		return nil, utils.NewError("Course not found or you don't have permission", utils.ErrCodeNotFound)
+	}
+
+	lesson, err := as.instructorRepo.FindLessonByIdAndCourse(lessonId, courseId)
+	if err != nil {
+		return nil, utils.NewError("Lesson not found", utils.ErrCodeNotFound)
+	}
+
+	return lesson, nil
+}
+
+func (as *assignmentService) findInstructorSubmission(instructorId, submissionId uint) (*models.AssignmentSubmission, error) {
+	submission, err := as.assignmentRepo.FindSubmissionById(submissionId)
+	if err != nil || submission.Course.InstructorId != instructorId {
+		return nil, utils.NewError("Submission not found or you don't have permission", utils.ErrCodeNotFound)
+	}
+	return submission, nil
+}
+
+// findStudentAssignment kiểm tra lesson là assignment, user đã enroll và lesson không bị khóa
+func (as *assignmentService) findStudentAssignment(userId, lessonId uint) (*models.Lesson, *models.Assignment, *models.Enrollment, error) {
+	lesson, err := as.assignmentRepo.FindLessonById(lessonId)
+	if err != nil || !lesson.IsPublished {
+		return nil, nil, nil, utils.NewError("Lesson not found", utils.ErrCodeNotFound)
+	}
+
+	if lesson.LessonType != lessonTypeAssignment {
+		return nil, nil, nil, utils.NewError("This lesson is not an assignment", utils.ErrCodeBadRequest)
+	}
+
+	enrollment, isEnrolled := as.enrollmentRepo.CheckEnrollment(userId, lesson.CourseId)
+	if !isEnrolled {
+		return nil, nil, nil, utils.NewError("You must enroll in this course to access this assignment", utils.ErrCodeForbidden)
+	}
+
+	lock, err := resolveLessonLock(as.lessonRepo, userId, lesson, enrollment.EnrolledAt)
+	if err != nil {
+		return nil, nil, nil, utils.WrapError(err, "Failed to check lesson lock", utils.ErrCodeInternal)
+	}
+	if lock.IsLocked {
+		return nil, nil, nil, utils.NewError(lessonLockMessage(lock.Reason), utils.ErrCodeForbidden)
+	}
+
+	assignment, err := as.assignmentRepo.FindByLessonId(lessonId)
+	if err != nil {
+		return nil, nil, nil, utils.WrapError(err, "Failed to get assignment", utils.ErrCodeInternal)
+	}
+	if assignment == nil {
+		return nil, nil, nil, utils.NewError("Assignment not found", utils.ErrCodeNotFound)
+	}
+
+	return lesson, assignment, enrollment, nil
+}
+
+// completeAssignmentLesson đánh dấu lesson hoàn thành qua ProgressService, lỗi chỉ log vì điểm đã được lưu
+func (as *assignmentService) completeAssignmentLesson(userId, lessonId uint) bool {
+	progress, err := as.lessonRepo.GetLessonProgressDetail(userId, lessonId)
+	if err == nil && progress.IsCompleted {
+		return true
+	}
+
+	if _, err := as.progressService.CompleteLesson(userId, lessonId, &dto.CompleteLessonRequest{}); err != nil {
+		log.Printf("Failed to complete assignment lesson %d for user %d: %v", lessonId, userId, err)
+		return false
+	}
+	return true
+}
+
+// effectiveDueAt tính hạn nộp thực tế của user (ngày cố định hoặc tính từ ngày enroll)
+func effectiveDueAt(assignment *models.Assignment, enrolledAt time.Time) *time.Time {
+	if assignment.DueAt != nil {
+		return assignment.DueAt
+	}
+	if assignment.DueDaysAfterEnrollment != nil {
+		dueAt := enrolledAt.AddDate(0, 0, *assignment.DueDaysAfterEnrollment)
+		return &dueAt
+	}
+	return nil
+}
+
+// cannotSubmitReason trả về lý do không được nộp bài, rỗng nếu được phép nộp
+func cannotSubmitReason(assignment *models.Assignment, submissions []models.AssignmentSubmission, dueAt *time.Time, now time.Time) string {
+	for _, submission := range submissions {
+		if submission.Status == submissionStatusGraded && submission.Passed {
+			return "You have already passed this assignment"
+		}
+	}
+
+	if len(submissions) > 0 && !assignment.AllowResubmission {
+		return "Resubmission is not allowed for this assignment"
+	}
+
+	if assignment.MaxSubmissions > 0 && len(submissions) >= assignment.MaxSubmissions {
+		return "You have used all submissions for this assignment"
+	}
+
+	if dueAt != nil && now.After(*dueAt) && !assignment.AllowLateSubmission {
+		return "The due date for this assignment has passed"
+	}
+
+	return ""
+}
+
+// latePenalty trả về số ngày trễ (phần ngày lẻ tính là một ngày) và phần trăm điểm bị trừ
+func latePenalty(assignment *models.Assignment, dueAt *time.Time, submittedAt time.Time) (int, float64) {
+	if dueAt == nil || !submittedAt.After(*dueAt) {
+		return 0, 0
+	}
+
+	lateDays := int(math.Ceil(submittedAt.Sub(*dueAt).Hours() / 24))
+	return lateDays, math.Min(float64(lateDays)*assignment.LatePenaltyPercent, assignment.MaxLatePenaltyPercent)
+}
+
+// applyLatePenalty trừ phần trăm điểm nộp trễ, làm tròn 2 chữ số thập phân
+func applyLatePenalty(rawScore, penaltyPercent float64) float64 {
+	return math.Round(rawScore*(1-penaltyPercent/100)*100) / 100
+}
+
+// scoreSubmission tính điểm thô: theo rubric nếu assignment có rubric, ngược lại dùng score
+func scoreSubmission(assignment *models.Assignment, req *dto.GradeSubmissionRequest) (float64, []models.RubricScore, error) {
+	if len(assignment.Rubric) == 0 {
+		if req.Score == nil {
+			return 0, nil, utils.NewError("score is required for assignments without rubric", utils.ErrCodeBadRequest)
+		}
+		if *req.Score > assignment.MaxPoints {
+			return 0, nil, utils.NewError(fmt.Sprintf("score cannot exceed %.2f points", assignment.MaxPoints), utils.ErrCodeBadRequest)
+		}
+		return *req.Score, []models.RubricScore{}, nil
+	}
+
+	inputs := make(map[string]dto.RubricScoreInput)
+	for _, input := range req.RubricScores {
+		inputs[input.CriterionKey] = input
+	}
+
+	rawScore := 0.0
+	rubricScores := make([]models.RubricScore, len(assignment.Rubric))
+	for i, criterion := range assignment.Rubric {
+		input, ok := inputs[criterion.Key]
+		if !ok {
+			return 0, nil, utils.NewError("Missing score for rubric criterion \""+criterion.Title+"\"", utils.ErrCodeBadRequest)
+		}
+		if input.Points > criterion.MaxPoints {
+			return 0, nil, utils.NewError(fmt.Sprintf("Score for \"%s\" cannot exceed %.2f points", criterion.Title, criterion.MaxPoints), utils.ErrCodeBadRequest)
+		}
+
+		rubricScores[i] = models.RubricScore{
+			CriterionKey: criterion.Key,
+			Points:       input.Points,
+			Comment:      input.Comment,
+		}
+		rawScore += input.Points
+	}
+
+	if len(inputs) != len(assignment.Rubric) {
+		return 0, nil, utils.NewError("rubric_scores contains unknown criteria", utils.ErrCodeBadRequest)
+	}
+
+	return rawScore, rubricScores, nil
+}
+
+// submissionFilePrefix - file bài nộp nằm ngoài storage.PublicPrefixes, chỉ tải qua API có kiểm tra quyền
+func submissionFilePrefix(courseId, assignmentId uint) string {
+	return fmt.Sprintf("assignments/%d/%d/", courseId, assignmentId)
+}
+
+func (as *assignmentService) removeSubmissionFiles(ctx context.Context, keyPrefix string, files []models.SubmissionFile) {
+	keys := make([]string, len(files))
+	for i, file := range files {
+		keys[i] = keyPrefix + file.StoredName
+	}
+	deleteStorageKeys(ctx, as.storage, keys)
+}
+
+func (as *assignmentService) openSubmissionFile(submission *models.AssignmentSubmission, fileId string) (*PrivateFile, error) {
+	for _, file := range submission.Files {
+		if file.Id != fileId {
+			continue
+		}
+
+		key := submissionFilePrefix(submission.CourseId, submission.AssignmentId) + file.StoredName
+		return openPrivateFile(context.Background(), as.storage, key, file.OriginalName, file.MimeType)
+	}
+
+	return nil, utils.NewError("File not found", utils.ErrCodeNotFound)
+}
+
+func toRubricCriterionItems(rubric []models.RubricCriterion) []dto.RubricCriterionItem {
+	items := make([]dto.RubricCriterionItem, len(rubric))
+	for i, criterion := range rubric {
+		items[i] = dto.RubricCriterionItem{
+			Key:         criterion.Key,
+			Title:       criterion.Title,
+			Description: criterion.Description,
+			MaxPoints:   criterion.MaxPoints,
+		}
+	}
+	return items
+}
+
+func toInstructorAssignmentResponse(assignment *models.Assignment) *dto.InstructorAssignmentResponse {
+	return &dto.InstructorAssignmentResponse{
+		Id:                     assignment.Id,
+		LessonId:               assignment.LessonId,
+		CourseId:               assignment.CourseId,
+		Instructions:           assignment.Instructions,
+		DueAt:                  assignment.DueAt,
+		DueDaysAfterEnrollment: assignment.DueDaysAfterEnrollment,
+		MaxPoints:              assignment.MaxPoints,
+		PassPercentage:         assignment.PassPercentage,
+		Rubric:                 toRubricCriterionItems(assignment.Rubric),
+		AllowResubmission:      assignment.AllowResubmission,
+		MaxSubmissions:         assignment.MaxSubmissions,
+		MaxFiles:               assignment.MaxFiles,
+		AllowLateSubmission:    assignment.AllowLateSubmission,
+		LatePenaltyPercent:     assignment.LatePenaltyPercent,
+		MaxLatePenaltyPercent:  assignment.MaxLatePenaltyPercent,
+		UpdatedAt:              assignment.UpdatedAt,
+	}
+}
+
+func toAssignmentSubmissionSummary(submission *models.AssignmentSubmission) dto.AssignmentSubmissionSummary {
+	return dto.AssignmentSubmissionSummary{
+		SubmissionId:     submission.Id,
+		SubmissionNumber: submission.SubmissionNumber,
+		Status:           submission.Status,
+		SubmittedAt:      submission.SubmittedAt,
+		IsLate:           submission.IsLate,
+		FinalScore:       submission.FinalScore,
+		MaxPoints:        submission.MaxPoints,
+		Passed:           submission.Passed,
+		GradedAt:         submission.GradedAt,
+	}
+}
+
+func toAssignmentSubmissionDetail(submission *models.AssignmentSubmission, assignment *models.Assignment) *dto.AssignmentSubmissionDetail {
+	files := make([]dto.SubmissionFileItem, len(submission.Files))
+	for i, file := range submission.Files {
+		files[i] = dto.SubmissionFileItem{
+			Id:           file.Id,
+			OriginalName: file.OriginalName,
+			Size:         file.Size,
+			MimeType:     file.MimeType,
+		}
+	}
+
+	criteria := make(map[string]models.RubricCriterion)
+	for _, criterion := range assignment.Rubric {
+		criteria[criterion.Key] = criterion
+	}
+
+	rubricScores := make([]dto.RubricScoreItem, len(submission.RubricScores))
+	for i, score := range submission.RubricScores {
+		criterion := criteria[score.CriterionKey]
+		rubricScores[i] = dto.RubricScoreItem{
+			CriterionKey: score.CriterionKey,
+			Title:        criterion.Title,
+			Points:       score.Points,
+			MaxPoints:    criterion.MaxPoints,
+			Comment:      score.Comment,
+		}
+	}
+
+	return &dto.AssignmentSubmissionDetail{
+		SubmissionId:       submission.Id,
+		AssignmentId:       submission.AssignmentId,
+		CourseId:           submission.CourseId,
+		CourseTitle:        submission.Course.Title,
+		LessonId:           submission.LessonId,
+		LessonTitle:        submission.Lesson.Title,
+		StudentId:          submission.UserId,
+		StudentName:        submission.User.FullName,
+		SubmissionNumber:   submission.SubmissionNumber,
+		Status:             submission.Status,
+		TextContent:        submission.TextContent,
+		Files:              files,
+		SubmittedAt:        submission.SubmittedAt,
+		DueAt:              submission.DueAt,
+		IsLate:             submission.IsLate,
+		LateDays:           submission.LateDays,
+		LatePenaltyPercent: submission.LatePenaltyPercent,
+		Rubric:             toRubricCriterionItems(assignment.Rubric),
+		RubricScores:       rubricScores,
+		RawScore:           submission.RawScore,
+		FinalScore:         submission.FinalScore,
+		MaxPoints:          submission.MaxPoints,
+		Passed:             submission.Passed,
+		Feedback:           submission.Feedback,
+		GradedAt:           submission.GradedAt,
+	}
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"lms/src/dto"
+	"lms/src/models"
+	"lms/src/utils"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestEffectiveDueAt(t *testing.T) {
+	enrolledAt := time.Date(2024, 3, 10, 9, 30, 0, 0, time.UTC)
+	fixed := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	sevenDays := 7
+
+	tests := []struct {
+		name       string
+		assignment models.Assignment
+		want       *time.Time
+	}{
+		{"no due date", models.Assignment{}, nil},
+		{"fixed due date", models.Assignment{DueAt: &fixed}, &fixed},
+		{"days after enrollment", models.Assignment{DueDaysAfterEnrollment: &sevenDays}, ptrTime(enrolledAt.AddDate(0, 0, 7))},
+		{"fixed due date wins", models.Assignment{DueAt: &fixed, DueDaysAfterEnrollment: &sevenDays}, &fixed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := effectiveDueAt(&tt.assignment, enrolledAt)
+			if (got == nil) != (tt.want == nil) || (got != nil && !got.Equal(*tt.want)) {
+				t.Errorf("effectiveDueAt = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCannotSubmitReason(t *testing.T) {
+	now := time.Date(2024, 4, 10, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	pending := models.AssignmentSubmission{Status: submissionStatusSubmitted}
+	failed := models.AssignmentSubmission{Status: submissionStatusGraded, Passed: false}
+	passed := models.AssignmentSubmission{Status: submissionStatusGraded, Passed: true}
+
+	tests := []struct {
+		name        string
+		assignment  models.Assignment
+		submissions []models.AssignmentSubmission
+		dueAt       *time.Time
+		want        string
+	}{
+		{"first submission", models.Assignment{}, nil, nil, ""},
+		{"before due date", models.Assignment{}, nil, &future, ""},
+		{"already passed", models.Assignment{AllowResubmission: true}, []models.AssignmentSubmission{failed, passed}, nil, "You have already passed this assignment"},
+		{"resubmission not allowed", models.Assignment{}, []models.AssignmentSubmission{failed}, nil, "Resubmission is not allowed for this assignment"},
+		{"resubmission allowed", models.Assignment{AllowResubmission: true}, []models.AssignmentSubmission{failed, pending}, nil, ""},
+		{"max submissions reached", models.Assignment{AllowResubmission: true, MaxSubmissions: 2}, []models.AssignmentSubmission{failed, failed}, nil, "You have used all submissions for this assignment"},
+		{"below max submissions", models.Assignment{AllowResubmission: true, MaxSubmissions: 3}, []models.AssignmentSubmission{failed, failed}, nil, ""},
+		{"late not allowed", models.Assignment{}, nil, &past, "The due date for this assignment has passed"},
+		{"late allowed", models.Assignment{AllowLateSubmission: true}, nil, &past, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cannotSubmitReason(&tt.assignment, tt.submissions, tt.dueAt, now); got != tt.want {
+				t.Errorf("cannotSubmitReason = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLatePenalty(t *testing.T) {
+	dueAt := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	assignment := &models.Assignment{LatePenaltyPercent: 10, MaxLatePenaltyPercent: 30}
+
+	tests := []struct {
+		name         string
+		dueAt        *time.Time
+		submittedAt  time.Time
+		wantDays     int
+		wantPenalty  float64
+		rawScore     float64
+		wantFinalPts float64
+	}{
+		{"no due date", nil, dueAt.AddDate(0, 0, 5), 0, 0, 80, 80},
+		{"on time", &dueAt, dueAt, 0, 0, 80, 80},
+		{"one minute late counts as a day", &dueAt, dueAt.Add(time.Minute), 1, 10, 80, 72},
+		{"exactly two days late", &dueAt, dueAt.Add(48 * time.Hour), 2, 20, 80, 64},
+		{"penalty capped", &dueAt, dueAt.AddDate(0, 0, 10), 10, 30, 80, 56},
+		{"final score rounded", &dueAt, dueAt.Add(time.Hour), 1, 10, 33.33, 30},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			days, penalty := latePenalty(assignment, tt.dueAt, tt.submittedAt)
+			if days != tt.wantDays || penalty != tt.wantPenalty {
+				t.Errorf("latePenalty = %d days, %v%%; want %d days, %v%%", days, penalty, tt.wantDays, tt.wantPenalty)
+			}
+			if got := applyLatePenalty(tt.rawScore, penalty); got != tt.wantFinalPts {
+				t.Errorf("applyLatePenalty(%v, %v) = %v, want %v", tt.rawScore, penalty, got, tt.wantFinalPts)
+			}
+		})
+	}
+}
+
+func TestScoreSubmission(t *testing.T) {
+	withRubric := &models.Assignment{MaxPoints: 10, Rubric: []models.RubricCriterion{
+		{Key: "code", Title: "Code", MaxPoints: 6},
+		{Key: "docs", Title: "Docs", MaxPoints: 4},
+	}}
+	withoutRubric := &models.Assignment{MaxPoints: 100}
+
+	tests := []struct {
+		name       string
+		assignment *models.Assignment
+		req        dto.GradeSubmissionRequest
+		wantScore  float64
+		wantScores []models.RubricScore
+		wantCode   utils.ErrorCode
+	}{
+		{"total score", withoutRubric, dto.GradeSubmissionRequest{Score: ptrFloat(85)}, 85, []models.RubricScore{}, ""},
+		{"total score at maximum", withoutRubric, dto.GradeSubmissionRequest{Score: ptrFloat(100)}, 100, []models.RubricScore{}, ""},
+		{"total score missing", withoutRubric, dto.GradeSubmissionRequest{}, 0, nil, utils.ErrCodeBadRequest},
+		{"total score above maximum", withoutRubric, dto.GradeSubmissionRequest{Score: ptrFloat(101)}, 0, nil, utils.ErrCodeBadRequest},
+		{
+			"rubric scores summed in rubric order", withRubric,
+			dto.GradeSubmissionRequest{RubricScores: []dto.RubricScoreInput{{CriterionKey: "docs", Points: 3.5, Comment: "ok"}, {CriterionKey: "code", Points: 6}}},
+			9.5, []models.RubricScore{{CriterionKey: "code", Points: 6}, {CriterionKey: "docs", Points: 3.5, Comment: "ok"}}, "",
+		},
+		{
+			"rubric score above criterion maximum", withRubric,
+			dto.GradeSubmissionRequest{RubricScores: []dto.RubricScoreInput{{CriterionKey: "code", Points: 7}, {CriterionKey: "docs", Points: 0}}},
+			0, nil, utils.ErrCodeBadRequest,
+		},
+		{
+			"missing criterion", withRubric,
+			dto.GradeSubmissionRequest{RubricScores: []dto.RubricScoreInput{{CriterionKey: "code", Points: 5}}},
+			0, nil, utils.ErrCodeBadRequest,
+		},
+		{
+			"unknown criterion", withRubric,
+			dto.GradeSubmissionRequest{RubricScores: []dto.RubricScoreInput{{CriterionKey: "code", Points: 5}, {CriterionKey: "docs", Points: 1}, {CriterionKey: "style", Points: 1}}},
+			0, nil, utils.ErrCodeBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, rubricScores, err := scoreSubmission(tt.assignment, &tt.req)
+			if got := errorCode(err); got != tt.wantCode {
+				t.Fatalf("error code = %q, want %q (err: %v)", got, tt.wantCode, err)
+			}
+			if err != nil {
+				return
+			}
+			if score != tt.wantScore || !reflect.DeepEqual(rubricScores, tt.wantScores) {
+				t.Errorf("scoreSubmission = %v %+v, want %v %+v", score, rubricScores, tt.wantScore, tt.wantScores)
+			}
+		})
+	}
+}
+
+func ptrTime(t time.Time) *time.Time {
+	return &t
+}
+
+func ptrFloat(f float64) *float64 {
+	return &f
+}
@@ -70,15 +70,17 @@ func (as *authService) Register(req *dto.RegisterRequest) (*dto.AuthResponse, er
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
 		User: dto.UserProfile{
-			Id:            user.Id,
-			Username:      user.Username,
-			Email:         user.Email,
-			FullName:      user.FullName,
-			Phone:         user.Phone,
-			Role:          user.Role,
-			Status:        user.Status,
-			EmailVerified: user.EmailVerified,
-			CreatedAt:     user.CreatedAt,
+			Id:             user.Id,
+			Username:       user.Username,
+			Email:          user.Email,
+			FullName:       user.FullName,
+			Phone:          user.Phone,
+			AvatarURL:      user.AvatarURL,
+			AvatarVariants: user.AvatarVariants,
+			Role:           user.Role,
+			Status:         user.Status,
+			EmailVerified:  user.EmailVerified,
+			CreatedAt:      user.CreatedAt,
 		},
 	}, nil
 }
@@ -113,15 +115,17 @@ func (as *authService) Login(req *dto.LoginRequest) (*dto.AuthResponse, error) {
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
 		User: dto.UserProfile{
-			Id:            user.Id,
-			Username:      user.Username,
-			Email:         user.Email,
-			FullName:      user.FullName,
-			Phone:         user.Phone,
-			Role:          user.Role,
-			Status:        user.Status,
-			EmailVerified: user.EmailVerified,
-			CreatedAt:     user.CreatedAt,
+			Id:             user.Id,
+			Username:       user.Username,
+			Email:          user.Email,
+			FullName:       user.FullName,
+			Phone:          user.Phone,
+			AvatarURL:      user.AvatarURL,
+			AvatarVariants: user.AvatarVariants,
+			Role:           user.Role,
+			Status:         user.Status,
+			EmailVerified:  user.EmailVerified,
+			CreatedAt:      user.CreatedAt,
 		},
 	}, nil
 }
@@ -133,15 +137,17 @@ func (as *authService) GetProfile(userId uint) (*dto.UserProfile, error) {
 	}
 
 	return &dto.UserProfile{
-		Id:            user.Id,
-		Username:      user.Username,
-		Email:         user.Email,
-		FullName:      user.FullName,
-		Phone:         user.Phone,
-		Role:          user.Role,
-		Status:        user.Status,
-		EmailVerified: user.EmailVerified,
-		CreatedAt:     user.CreatedAt,
+		Id:             user.Id,
+		Username:       user.Username,
+		Email:          user.Email,
+		FullName:       user.FullName,
+		Phone:          user.Phone,
+		AvatarURL:      user.AvatarURL,
+		AvatarVariants: user.AvatarVariants,
+		Role:           user.Role,
+		Status:         user.Status,
+		EmailVerified:  user.EmailVerified,
+		CreatedAt:      user.CreatedAt,
 	}, nil
 }
 
@@ -0,0 +1,220 @@
+package service
+
+import (
+	"fmt"
+	"io"
+	"lms/src/certificate"
+	"lms/src/dto"
+	"lms/src/models"
+	"lms/src/repository"
+	"lms/src/utils"
+	"log"
+	"strings"
+	"time"
+)
+
+type certificateService struct {
+	certificateRepo repository.CertificateRepository
+}
+
+func NewCertificateService(certificateRepo repository.CertificateRepository) CertificateService {
+	return &certificateService{
+		certificateRepo: certificateRepo,
+	}
+}
+
+func (cs *certificateService) GetMyCertificates(userId uint) (*dto.GetMyCertificatesResponse, error) {
+	// 1. Cấp bù chứng chỉ cho các khóa đã hoàn thành nhưng chưa có (hoàn thành trước khi có tính năng, hoặc lần cấp trước bị lỗi)
+	enrollmentIds, err := cs.certificateRepo.GetUncertifiedEnrollmentIds(userId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get completed enrollments", utils.ErrCodeInternal)
+	}
+	for _, enrollmentId := range enrollmentIds {
+		if _, err := issueCertificate(cs.certificateRepo, enrollmentId); err != nil {
+			log.Printf("Failed to issue certificate for enrollment %d: %v", enrollmentId, err)
+		}
+	}
+
+	// 2. Lấy danh sách chứng chỉ
+	certificates, err := cs.certificateRepo.GetUserCertificates(userId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get certificates", utils.ErrCodeInternal)
+	}
+
+	items := make([]dto.CertificateItem, 0, len(certificates))
+	for _, c := range certificates {
+		items = append(items, dto.CertificateItem{
+			Id:             c.Id,
+			Serial:         c.Serial,
+			CourseId:       c.CourseId,
+			CourseTitle:    c.CourseTitle,
+			LearnerName:    c.LearnerName,
+			InstructorName: c.InstructorName,
+			CompletedAt:    c.CompletedAt,
+			IssuedAt:       c.IssuedAt,
+			Revision:       c.Revision,
+			ReissuedAt:     c.ReissuedAt,
+			VerifyURL:      certificateVerifyURL(c.Serial),
+		})
+	}
+
+	return &dto.GetMyCertificatesResponse{Certificates: items}, nil
+}
+
+// RenderCertificate ghi PDF chứng chỉ của học viên vào w, trả về tên file tải xuống
+func (cs *certificateService) RenderCertificate(userId uint, serial string, w io.Writer) (string, error) {
+	// 1. Chỉ chủ sở hữu tải được PDF, chứng chỉ của người khác trả về not found
+	c, err := cs.certificateRepo.FindBySerial(normalizeSerial(serial))
+	if err != nil {
+		return "", utils.WrapError(err, "Failed to get certificate", utils.ErrCodeInternal)
+	}
+	if c == nil || c.UserId != userId {
+		return "", utils.NewError("Certificate not found", utils.ErrCodeNotFound)
+	}
+
+	// 2. Chứng chỉ đã hết hiệu lực (quyền học bị thu hồi, hoàn tiền) không tải được
+	invalidReason, err := cs.certificateInvalidReason(c)
+	if err != nil {
+		return "", utils.WrapError(err, "Failed to verify certificate", utils.ErrCodeInternal)
+	}
+	if invalidReason != "" {
+		return "", utils.NewError("Certificate is no longer valid", utils.ErrCodeForbidden)
+	}
+
+	// 3. Render PDF từ dữ liệu hiện tại (tên đã cập nhật nếu chứng chỉ được cấp lại)
+	err = certificate.Render(w, certificate.Data{
+		Serial:         c.Serial,
+		LearnerName:    c.LearnerName,
+		CourseTitle:    c.CourseTitle,
+		InstructorName: c.InstructorName,
+		CompletedAt:    c.CompletedAt,
+		VerifyURL:      certificateVerifyURL(c.Serial),
+	})
+	if err != nil {
+		return "", utils.WrapError(err, "Failed to render certificate", utils.ErrCodeInternal)
+	}
+
+	return fmt.Sprintf("certificate-%s.pdf", c.Serial), nil
+}
+
+func (cs *certificateService) VerifyCertificate(serial string) (*dto.CertificateVerificationResponse, error) {
+	// 1. Tìm chứng chỉ
+	c, err := cs.certificateRepo.FindBySerial(normalizeSerial(serial))
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get certificate", utils.ErrCodeInternal)
+	}
+	if c == nil {
+		return nil, utils.NewError("Certificate not found", utils.ErrCodeNotFound)
+	}
+
+	// 2. Chứng chỉ chỉ còn hiệu lực khi quyền học của enrollment không bị thu hồi hay hoàn tiền
+	invalidReason, err := cs.certificateInvalidReason(c)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to verify certificate", utils.ErrCodeInternal)
+	}
+
+	return &dto.CertificateVerificationResponse{
+		Valid:          invalidReason == "",
+		InvalidReason:  invalidReason,
+		Serial:         c.Serial,
+		LearnerName:    c.LearnerName,
+		CourseTitle:    c.CourseTitle,
+		InstructorName: c.InstructorName,
+		CompletedAt:    c.CompletedAt,
+		IssuedAt:       c.IssuedAt,
+		Revision:       c.Revision,
+		ReissuedAt:     c.ReissuedAt,
+	}, nil
+}
+
+// certificateInvalidReason trả về lý do chứng chỉ hết hiệu lực, "" nếu còn hiệu lực.
+// Enrollment hết hạn hoặc học viên tự hủy sau khi hoàn thành vẫn giữ chứng chỉ
+func (cs *certificateService) certificateInvalidReason(c *models.Certificate) (string, error) {
+	enrollment, err := cs.certificateRepo.FindEnrollment(c.EnrollmentId)
+	if err != nil {
+		return "", err
+	}
+	if enrollment == nil || enrollment.DeletedAt.Valid {
+		return "revoked", nil
+	}
+
+	if enrollment.Status == "revoked" {
+		return "revoked", nil
+	}
+
+	if enrollment.EnrollmentSource == enrollmentSourcePurchase {
+		refunded, err := cs.certificateRepo.IsPurchaseRefunded(enrollment.UserId, enrollment.CourseId)
+		if err != nil {
+			return "", err
+		}
+		if refunded {
+			return "refunded", nil
+		}
+	}
+	return "", nil
+}
+
+// issueCertificate cấp chứng chỉ cho enrollment đã hoàn thành, gọi lại nhiều lần vẫn chỉ có một chứng chỉ
+func issueCertificate(certificateRepo repository.CertificateRepository, enrollmentId uint) (*models.Certificate, error) {
+	existing, err := certificateRepo.FindByEnrollmentId(enrollmentId)
+	if err != nil || existing != nil {
+		return existing, err
+	}
+
+	enrollment, err := certificateRepo.FindEnrollmentForIssue(enrollmentId)
+	if err != nil {
+		return nil, err
+	}
+	if enrollment == nil {
+		return nil, fmt.Errorf("enrollment %d is not completed", enrollmentId)
+	}
+
+	serial, err := certificate.NewSerial()
+	if err != nil {
+		return nil, err
+	}
+
+	completedAt := time.Now()
+	if enrollment.CompletedAt != nil {
+		completedAt = *enrollment.CompletedAt
+	}
+
+	c := &models.Certificate{
+		Serial:         serial,
+		EnrollmentId:   enrollment.Id,
+		UserId:         enrollment.UserId,
+		CourseId:       enrollment.CourseId,
+		LearnerName:    enrollment.User.FullName,
+		CourseTitle:    enrollment.Course.Title,
+		InstructorName: enrollment.Course.Instructor.FullName,
+		CompletedAt:    completedAt,
+		IssuedAt:       time.Now(),
+		Revision:       1,
+	}
+	if err := certificateRepo.Create(c); err != nil {
+		// Hai request hoàn thành lesson cuối cùng chạy song song: unique index trên enrollment_id chặn bản thứ hai
+		if existing, findErr := certificateRepo.FindByEnrollmentId(enrollmentId); findErr == nil && existing != nil {
+			return existing, nil
+		}
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// reissueCertificates cấp lại chứng chỉ với tên mới khi học viên đổi họ tên, lỗi chỉ ghi log để không chặn việc cập nhật profile
+func reissueCertificates(certificateRepo repository.CertificateRepository, userId uint, fullName string) {
+	if _, err := certificateRepo.ReissueForLearner(userId, fullName); err != nil {
+		log.Printf("Failed to reissue certificates for user %d: %v", userId, err)
+	}
+}
+
+// certificateVerifyURL - trang xác thực trên frontend, gọi GET /api/v1/certificates/:serial
+func certificateVerifyURL(serial string) string {
+	baseURL := strings.TrimSuffix(utils.GetEnv("FRONTEND_URL", "http://localhost:3000"), "/")
+	return fmt.Sprintf("%s/certificates/%s", baseURL, serial)
+}
+
+func normalizeSerial(serial string) string {
+	return strings.ToUpper(strings.TrimSpace(serial))
+}
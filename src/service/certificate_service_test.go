@@ -0,0 +1,78 @@
+package service
+
+import (
+	"lms/src/models"
+	"lms/src/repository"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type fakeCertificateRepo struct {
+	repository.CertificateRepository
+	certificate *models.Certificate
+	enrollment  *models.Enrollment
+	refunded    bool
+}
+
+func (r *fakeCertificateRepo) FindBySerial(serial string) (*models.Certificate, error) {
+	if r.certificate == nil || r.certificate.Serial != serial {
+		return nil, nil
+	}
+	return r.certificate, nil
+}
+
+func (r *fakeCertificateRepo) FindEnrollment(enrollmentId uint) (*models.Enrollment, error) {
+	if r.enrollment == nil || r.enrollment.Id != enrollmentId {
+		return nil, nil
+	}
+	return r.enrollment, nil
+}
+
+func (r *fakeCertificateRepo) IsPurchaseRefunded(userId, courseId uint) (bool, error) {
+	return r.refunded, nil
+}
+
+func TestVerifyCertificate(t *testing.T) {
+	tests := []struct {
+		name       string
+		enrollment *models.Enrollment
+		refunded   bool
+		wantValid  bool
+		wantReason string
+	}{
+		{"completed", &models.Enrollment{Status: "completed", EnrollmentSource: enrollmentSourcePurchase}, false, true, ""},
+		{"expired after completion", &models.Enrollment{Status: "expired", EnrollmentSource: enrollmentSourcePurchase}, false, true, ""},
+		{"revoked", &models.Enrollment{Status: "revoked", EnrollmentSource: enrollmentSourceInvite}, false, false, "revoked"},
+		{"refunded purchase", &models.Enrollment{Status: "completed", EnrollmentSource: enrollmentSourcePurchase}, true, false, "refunded"},
+		{"refund ignored for grant", &models.Enrollment{Status: "completed", EnrollmentSource: enrollmentSourceAdminGrant}, true, true, ""},
+		{"deleted enrollment", &models.Enrollment{Status: "completed", DeletedAt: gorm.DeletedAt{Time: time.Now(), Valid: true}}, false, false, "revoked"},
+		{"missing enrollment", nil, false, false, "revoked"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeCertificateRepo{
+				certificate: &models.Certificate{Serial: "LMS-AAAA-BBBB-CCCC", EnrollmentId: 7},
+				refunded:    tt.refunded,
+			}
+			if tt.enrollment != nil {
+				tt.enrollment.Id = 7
+				repo.enrollment = tt.enrollment
+			}
+
+			got, err := NewCertificateService(repo).VerifyCertificate(" lms-aaaa-bbbb-cccc ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Valid != tt.wantValid || got.InvalidReason != tt.wantReason {
+				t.Errorf("got valid=%v reason=%q, want valid=%v reason=%q", got.Valid, got.InvalidReason, tt.wantValid, tt.wantReason)
+			}
+		})
+	}
+
+	if _, err := NewCertificateService(&fakeCertificateRepo{}).VerifyCertificate("LMS-XXXX"); err == nil {
+		t.Error("expected not found for unknown serial")
+	}
+}
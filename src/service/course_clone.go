@@ -0,0 +1,424 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"lms/src/dto"
+	"lms/src/models"
+	"lms/src/storage"
+	"lms/src/utils"
+	"log"
+	"path"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// lessonCopyResult đếm nội dung đã sao chép kèm lesson
+type lessonCopyResult struct {
+	quizzes     int
+	questions   int
+	assignments int
+	resources   int
+
+	// Key các file resource, video và package SCORM đã copy, cần xóa nếu transaction lỗi
+	storageKeys   []string
+	scormPackages []*models.ScormPackage
+	storage       storage.Backend
+}
+
+func (r *lessonCopyResult) cleanup() {
+	deleteStorageKeys(context.Background(), r.storage, r.storageKeys)
+	for _, pkg := range r.scormPackages {
+		deleteScormFiles(context.Background(), r.storage, pkg)
+	}
+}
+
+func (is *instructorService) CloneCourse(instructorId, courseId uint, req *dto.CloneCourseRequest) (*dto.CloneCourseResponse, error) {
+	// 1. Kiểm tra course có tồn tại và thuộc về instructor không
+	source, err := is.instructorRepo.FindCourseByIdAndInstructor(courseId, instructorId)
+	if err != nil {
+		return nil, utils.NewError("Course not found or you don't have permission", utils.ErrCodeNotFound)
+	}
+
+	// 2. Title và slug mới cho bản sao
+	title := req.Title
+	if title == "" {
+		title = copyTitle(source.Title)
+	}
+	slug := utils.GenerateUniqueSlug(utils.GenerateSlug(title), func(slug string) bool {
+		_, exists := is.instructorRepo.FindCourseBySlug(slug)
+		return exists
+	})
+
+	// 3. Thumbnail được copy sang key mới, để thay thumbnail ở một bản không xóa ảnh của bản kia
+	ctx := context.Background()
+	thumbnailURL, thumbnailVariants, thumbnailKeys, err := copyImageVariants(ctx, is.storage, "thumbnails", source.ThumbnailURL, source.ThumbnailVariants)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to copy course thumbnail", utils.ErrCodeInternal)
+	}
+
+	result := &lessonCopyResult{storage: is.storage}
+	fail := func(tx *gorm.DB, err error) (*dto.CloneCourseResponse, error) {
+		tx.Rollback()
+		result.cleanup()
+		deleteStorageKeys(ctx, is.storage, thumbnailKeys)
+		return nil, err
+	}
+
+	// 4. Sao chép course, lessons, quiz, assignment và resources trong một transaction
+	tx := is.instructorRepo.BeginTransaction()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var lessons []models.Lesson
+	if err := tx.Where("course_id = ? AND deleted_at IS NULL", courseId).Order("lesson_order ASC").Find(&lessons).Error; err != nil {
+		return fail(tx, utils.WrapError(err, "Failed to get course lessons", utils.ErrCodeInternal))
+	}
+
+	course := newCourseCopy(source, instructorId, title, slug)
+	course.ThumbnailURL = thumbnailURL
+	course.ThumbnailVariants = thumbnailVariants
+	course.TotalLessons = len(lessons)
+	if err := tx.Create(course).Error; err != nil {
+		return fail(tx, utils.WrapError(err, "Failed to create course copy", utils.ErrCodeInternal))
+	}
+
+	for i := range lessons {
+		lesson := newLessonCopy(&lessons[i], course.Id)
+		lesson.Slug = lessons[i].Slug // Slug chỉ cần duy nhất trong course
+		lesson.LessonOrder = lessons[i].LessonOrder
+		lesson.PublishAt = nil
+		lesson.UnpublishAt = nil
+
+		if err := createLessonInTx(tx, lesson); err != nil {
+			return fail(tx, utils.WrapError(err, "Failed to copy lesson", utils.ErrCodeInternal))
+		}
+		if err := copyLessonContent(tx, &lessons[i], lesson, result); err != nil {
+			return fail(tx, utils.WrapError(err, "Failed to copy lesson content", utils.ErrCodeInternal))
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		result.cleanup()
+		deleteStorageKeys(ctx, is.storage, thumbnailKeys)
+		return nil, utils.WrapError(err, "Failed to commit transaction", utils.ErrCodeInternal)
+	}
+
+	// 5. Trả về response
+	return &dto.CloneCourseResponse{
+		Message:          "Course cloned successfully",
+		SourceCourseId:   source.Id,
+		CourseId:         course.Id,
+		Title:            course.Title,
+		Slug:             course.Slug,
+		Status:           course.Status,
+		TotalLessons:     len(lessons),
+		TotalQuizzes:     result.quizzes,
+		TotalQuestions:   result.questions,
+		TotalAssignments: result.assignments,
+		TotalResources:   result.resources,
+	}, nil
+}
+
+func (is *instructorService) DuplicateLesson(instructorId, courseId, lessonId uint) (*dto.DuplicateLessonResponse, error) {
+	// 1. Kiểm tra course có tồn tại và thuộc về instructor không
+	course, err := is.instructorRepo.FindCourseByIdAndInstructor(courseId, instructorId)
+	if err != nil {
+		return nil, utils.NewError("Course not found or you don't have permission", utils.ErrCodeNotFound)
+	}
+
+	// 2. Kiểm tra lesson có tồn tại và thuộc về course không
+	source, err := is.instructorRepo.FindLessonByIdAndCourse(lessonId, courseId)
+	if err != nil {
+		return nil, utils.NewError("Lesson not found", utils.ErrCodeNotFound)
+	}
+
+	// Lesson mới thay đổi bản live nên áp dụng cùng điều kiện với CreateLesson
+	if err := is.ensureLiveEditable(course); err != nil {
+		return nil, err
+	}
+
+	// 3. Title và slug mới (slug duy nhất trong course)
+	lesson := newLessonCopy(source, courseId)
+	lesson.Title = copyTitle(source.Title)
+	lesson.Slug = utils.GenerateUniqueSlug(utils.GenerateSlug(lesson.Title), func(slug string) bool {
+		_, exists := is.instructorRepo.FindLessonBySlug(slug, courseId)
+		return exists
+	})
+
+	result := &lessonCopyResult{storage: is.storage}
+
+	// 4. Tạo lesson ở cuối course và sao chép nội dung trong một transaction
+	tx := is.instructorRepo.BeginTransaction()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var maxOrder int
+	if err := tx.Model(&models.Lesson{}).
+		Where("course_id = ? AND deleted_at IS NULL", courseId).
+		Select("COALESCE(MAX(lesson_order), 0)").
+		Scan(&maxOrder).Error; err != nil {
+		tx.Rollback()
+		return nil, utils.WrapError(err, "Failed to get lesson order", utils.ErrCodeInternal)
+	}
+	lesson.LessonOrder = maxOrder + 1
+
+	if err := createLessonInTx(tx, lesson); err != nil {
+		tx.Rollback()
+		return nil, utils.WrapError(err, "Failed to duplicate lesson", utils.ErrCodeInternal)
+	}
+	if err := copyLessonContent(tx, source, lesson, result); err != nil {
+		tx.Rollback()
+		result.cleanup()
+		return nil, utils.WrapError(err, "Failed to copy lesson content", utils.ErrCodeInternal)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		result.cleanup()
+		return nil, utils.WrapError(err, "Failed to commit transaction", utils.ErrCodeInternal)
+	}
+
+	if lesson.IsPublished {
+		enqueueProgressRecalculation(is.recalcRepo, courseId, recalcReasonLessonDuplicated)
+	}
+
+	// 5. Trả về response
+	return &dto.DuplicateLessonResponse{
+		Message:          "Lesson duplicated successfully",
+		SourceLessonId:   source.Id,
+		Id:               lesson.Id,
+		CourseId:         lesson.CourseId,
+		Title:            lesson.Title,
+		Slug:             lesson.Slug,
+		LessonOrder:      lesson.LessonOrder,
+		IsPublished:      lesson.IsPublished,
+		TotalQuestions:   result.questions,
+		TotalResources:   result.resources,
+		AssignmentCopied: result.assignments > 0,
+	}, nil
+}
+
+// newLessonCopy sao chép nội dung lesson (chưa có slug và lesson_order) sang course đích
+func newLessonCopy(source *models.Lesson, courseId uint) *models.Lesson {
+	return &models.Lesson{
+		CourseId:      courseId,
+		Title:         source.Title,
+		Description:   source.Description,
+		Content:       source.Content,
+		ContentHTML:   source.ContentHTML,
+		VideoURL:      source.VideoURL,
+		VideoDuration: source.VideoDuration,
+		LessonType:    source.LessonType,
+		IsPreview:     source.IsPreview,
+		IsPublished:   source.IsPublished,
+		PublishAt:     source.PublishAt,
+		UnpublishAt:   source.UnpublishAt,
+
+		UnlockAfterDays:           source.UnlockAfterDays,
+		RequirePreviousCompletion: source.RequirePreviousCompletion,
+	}
+}
+
+// newCourseCopy sao chép thông tin course (chưa có thumbnail và tổng số lessons).
+// Bản sao luôn là draft mới: không giữ lịch publish, thống kê học viên, rating và featured
+func newCourseCopy(source *models.Course, instructorId uint, title, slug string) *models.Course {
+	return &models.Course{
+		Title:              title,
+		Slug:               slug,
+		Description:        source.Description,
+		DescriptionHTML:    source.DescriptionHTML,
+		ShortDesc:          source.ShortDesc,
+		VideoPreviewURL:    source.VideoPreviewURL,
+		Price:              source.Price,
+		DiscountPrice:      source.DiscountPrice,
+		InstructorId:       instructorId,
+		CategoryId:         source.CategoryId,
+		Level:              source.Level,
+		DurationHours:      source.DurationHours,
+		AccessDurationDays: source.AccessDurationDays,
+		Language:           source.Language,
+		Requirements:       source.Requirements,
+		WhatYouLearn:       source.WhatYouLearn,
+		Status:             "draft",
+	}
+}
+
+// newAssignmentCopy gắn assignment vào lesson đích, hạn nộp cố định đã qua thì bỏ để instructor đặt lại cho đợt mới
+func newAssignmentCopy(source models.Assignment, target *models.Lesson, now time.Time) models.Assignment {
+	assignment := source
+	assignment.Id = 0
+	assignment.LessonId = target.Id
+	assignment.CourseId = target.CourseId
+	assignment.CreatedAt = time.Time{}
+	assignment.UpdatedAt = time.Time{}
+	if assignment.DueAt != nil && assignment.DueAt.Before(now) {
+		assignment.DueAt = nil
+	}
+	return assignment
+}
+
+func createLessonInTx(tx *gorm.DB, lesson *models.Lesson) error {
+	if err := tx.Create(lesson).Error; err != nil {
+		return err
+	}
+
+	// is_published có default true nên giá trị false phải update riêng
+	if !lesson.IsPublished {
+		return tx.Model(lesson).Update("is_published", false).Error
+	}
+	return nil
+}
+
+// copyLessonContent sao chép quiz (kèm question bank), assignment, file resources, video và package SCORM của source sang target
+func copyLessonContent(tx *gorm.DB, source, target *models.Lesson, result *lessonCopyResult) error {
+	// 1. Quiz và question bank
+	var quizzes []models.Quiz
+	err := tx.Preload("Questions", func(db *gorm.DB) *gorm.DB {
+		return db.Order("question_order ASC")
+	}).Where("lesson_id = ?", source.Id).Limit(1).Find(&quizzes).Error
+	if err != nil {
+		return err
+	}
+
+	if len(quizzes) > 0 {
+		quiz := quizzes[0]
+		questions := quiz.Questions
+
+		quiz.Id = 0
+		quiz.LessonId = target.Id
+		quiz.CourseId = target.CourseId
+		quiz.Questions = nil
+		quiz.CreatedAt = time.Time{}
+		quiz.UpdatedAt = time.Time{}
+		if err := tx.Create(&quiz).Error; err != nil {
+			return err
+		}
+
+		for i := range questions {
+			questions[i].Id = 0
+			questions[i].QuizId = quiz.Id
+			questions[i].CreatedAt = time.Time{}
+			questions[i].UpdatedAt = time.Time{}
+		}
+		if len(questions) > 0 {
+			if err := tx.Create(&questions).Error; err != nil {
+				return err
+			}
+		}
+
+		result.quizzes++
+		result.questions += len(questions)
+	}
+
+	// 2. Assignment
+	var assignments []models.Assignment
+	if err := tx.Where("lesson_id = ?", source.Id).Limit(1).Find(&assignments).Error; err != nil {
+		return err
+	}
+
+	if len(assignments) > 0 {
+		assignment := newAssignmentCopy(assignments[0], target, time.Now())
+		if err := tx.Create(&assignment).Error; err != nil {
+			return err
+		}
+
+		result.assignments++
+	}
+
+	// 3. Resources: copy file sang prefix của lesson mới
+	var resources []models.LessonResource
+	if err := tx.Where("lesson_id = ?", source.Id).Order("resource_order ASC").Find(&resources).Error; err != nil {
+		return err
+	}
+
+	for _, resource := range resources {
+		storedName := uuid.NewString() + path.Ext(resource.StoredName)
+		newKey := lessonResourcePrefix(target.CourseId, target.Id) + storedName
+		if err := copyStorageObject(context.Background(), result.storage, lessonResourceKey(&resource), newKey); err != nil {
+			// File gốc đã mất thì bản gốc cũng không tải được, bỏ qua thay vì chặn cả việc sao chép
+			if errors.Is(err, storage.ErrObjectNotFound) {
+				log.Printf("Skip copying missing resource file %s: %v", resource.StoredName, err)
+				continue
+			}
+			return err
+		}
+		result.storageKeys = append(result.storageKeys, newKey)
+
+		resource.Id = 0
+		resource.LessonId = target.Id
+		resource.CourseId = target.CourseId
+		resource.StoredName = storedName
+		resource.DownloadCount = 0
+		resource.CreatedAt = time.Time{}
+		resource.UpdatedAt = time.Time{}
+		if err := tx.Create(&resource).Error; err != nil {
+			return err
+		}
+
+		result.resources++
+	}
+
+	// 4. Video trong storage: sang course khác thì copy sang prefix video của course đích
+	// (media stream chỉ phục vụ key thuộc chính course của lesson)
+	if key, isStored := lessonMediaKey(source); isStored && key != "" && source.CourseId != target.CourseId {
+		newKey := fmt.Sprintf("%s%d/%s", lessonVideoPrefix(target.CourseId), target.Id, path.Base(key))
+		if err := copyStorageObject(context.Background(), result.storage, key, newKey); err != nil {
+			if !errors.Is(err, storage.ErrObjectNotFound) {
+				return err
+			}
+			log.Printf("Skip copying missing video of lesson %d: %v", source.Id, err)
+		} else {
+			result.storageKeys = append(result.storageKeys, newKey)
+			target.VideoURL = newKey
+			if err := tx.Model(target).Update("video_url", newKey).Error; err != nil {
+				return err
+			}
+		}
+	}
+
+	// 5. Package SCORM: copy file sang prefix của lesson mới
+	var packages []models.ScormPackage
+	if err := tx.Where("lesson_id = ?", source.Id).Limit(1).Find(&packages).Error; err != nil {
+		return err
+	}
+
+	if len(packages) > 0 {
+		pkg, err := copyScormPackage(context.Background(), result.storage, &packages[0], target.CourseId, target.Id)
+		if err != nil {
+			if errors.Is(err, storage.ErrObjectNotFound) {
+				log.Printf("Skip copying SCORM package of lesson %d with missing files: %v", source.Id, err)
+				return nil
+			}
+			return err
+		}
+		result.scormPackages = append(result.scormPackages, pkg)
+
+		if err := tx.Create(pkg).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// copyTitle thêm hậu tố " (Copy)", cắt bớt title gốc để không vượt quá 200 ký tự
+func copyTitle(title string) string {
+	const suffix = " (Copy)"
+	const maxLength = 200
+
+	maxTitle := maxLength - utf8.RuneCountInString(suffix)
+	if utf8.RuneCountInString(title) > maxTitle {
+		title = string([]rune(title)[:maxTitle])
+	}
+	return title + suffix
+}
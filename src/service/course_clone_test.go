@@ -0,0 +1,194 @@
+package service
+
+import (
+	"lms/src/models"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestNewCourseCopy(t *testing.T) {
+	discount := 199000.0
+	publishAt := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	source := &models.Course{
+		Id:                 3,
+		Title:              "Go Backend",
+		Slug:               "go-backend",
+		Description:        "# Go",
+		DescriptionHTML:    "<h1>Go</h1>",
+		ShortDesc:          "Learn Go",
+		ThumbnailURL:       "thumbnails/a.webp",
+		ThumbnailVariants:  map[string]string{"320": "thumbnails/a-320.webp"},
+		VideoPreviewURL:    "https://youtube.com/watch?v=x",
+		Price:              299000,
+		DiscountPrice:      &discount,
+		InstructorId:       7,
+		CategoryId:         2,
+		Level:              "intermediate",
+		DurationHours:      12,
+		AccessDurationDays: 365,
+		TotalLessons:       20,
+		Language:           "en",
+		Requirements:       "Basic programming",
+		WhatYouLearn:       "REST APIs",
+		Status:             "published",
+		IsFeatured:         true,
+		PublishAt:          &publishAt,
+		UnpublishAt:        &publishAt,
+		RatingAvg:          4.8,
+		RatingCount:        120,
+		EnrolledCount:      900,
+	}
+
+	got := newCourseCopy(source, 8, "Go Backend (Copy)", "go-backend-copy")
+	want := &models.Course{
+		Title:              "Go Backend (Copy)",
+		Slug:               "go-backend-copy",
+		Description:        "# Go",
+		DescriptionHTML:    "<h1>Go</h1>",
+		ShortDesc:          "Learn Go",
+		VideoPreviewURL:    "https://youtube.com/watch?v=x",
+		Price:              299000,
+		DiscountPrice:      &discount,
+		InstructorId:       8,
+		CategoryId:         2,
+		Level:              "intermediate",
+		DurationHours:      12,
+		AccessDurationDays: 365,
+		Language:           "en",
+		Requirements:       "Basic programming",
+		WhatYouLearn:       "REST APIs",
+		Status:             "draft",
+	}
+
+	// Thumbnail (key mới) và tổng số lessons do CloneCourse gán sau
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("newCourseCopy =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestNewLessonCopy(t *testing.T) {
+	publishAt := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	source := &models.Lesson{
+		Id:                        11,
+		CourseId:                  3,
+		Title:                     "Goroutines",
+		Slug:                      "goroutines",
+		Description:               "Concurrency",
+		Content:                   "**go**",
+		ContentHTML:               "<strong>go</strong>",
+		VideoURL:                  "videos/3/11/a.mp4",
+		VideoDuration:             600,
+		LessonType:                "video",
+		LessonOrder:               4,
+		IsPreview:                 true,
+		IsPublished:               false,
+		PublishAt:                 &publishAt,
+		UnlockAfterDays:           7,
+		RequirePreviousCompletion: true,
+		CreatedAt:                 publishAt,
+	}
+
+	got := newLessonCopy(source, 9)
+	want := &models.Lesson{
+		CourseId:                  9,
+		Title:                     "Goroutines",
+		Description:               "Concurrency",
+		Content:                   "**go**",
+		ContentHTML:               "<strong>go</strong>",
+		VideoURL:                  "videos/3/11/a.mp4",
+		VideoDuration:             600,
+		LessonType:                "video",
+		IsPreview:                 true,
+		IsPublished:               false,
+		PublishAt:                 &publishAt,
+		UnlockAfterDays:           7,
+		RequirePreviousCompletion: true,
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("newLessonCopy =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestNewAssignmentCopy(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	past := now.AddDate(0, 0, -1)
+	future := now.AddDate(0, 1, 0)
+	sevenDays := 7
+	target := &models.Lesson{Id: 21, CourseId: 9}
+
+	tests := []struct {
+		name      string
+		dueAt     *time.Time
+		dueDays   *int
+		wantDueAt *time.Time
+	}{
+		{"no due date", nil, nil, nil},
+		{"future due date kept", &future, nil, &future},
+		{"past due date dropped", &past, nil, nil},
+		{"relative due date kept", nil, &sevenDays, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := models.Assignment{
+				Id:                     5,
+				LessonId:               11,
+				CourseId:               3,
+				Instructions:           "Build an API",
+				DueAt:                  tt.dueAt,
+				DueDaysAfterEnrollment: tt.dueDays,
+				MaxPoints:              10,
+				Rubric:                 []models.RubricCriterion{{Key: "code", Title: "Code", MaxPoints: 10}},
+				AllowResubmission:      true,
+				LatePenaltyPercent:     5,
+				CreatedAt:              past,
+				UpdatedAt:              past,
+			}
+
+			got := newAssignmentCopy(source, target, now)
+			if got.Id != 0 || got.LessonId != 21 || got.CourseId != 9 || !got.CreatedAt.IsZero() || !got.UpdatedAt.IsZero() {
+				t.Errorf("copy not attached to target lesson: %+v", got)
+			}
+			if got.Instructions != source.Instructions || got.MaxPoints != 10 || !got.AllowResubmission || got.LatePenaltyPercent != 5 ||
+				!reflect.DeepEqual(got.Rubric, source.Rubric) || got.DueDaysAfterEnrollment != tt.dueDays {
+				t.Errorf("grading rules not copied: %+v", got)
+			}
+			if (got.DueAt == nil) != (tt.wantDueAt == nil) || (got.DueAt != nil && !got.DueAt.Equal(*tt.wantDueAt)) {
+				t.Errorf("due at = %v, want %v", got.DueAt, tt.wantDueAt)
+			}
+			if source.LessonId != 11 {
+				t.Errorf("source assignment was modified: %+v", source)
+			}
+		})
+	}
+}
+
+func TestCopyTitle(t *testing.T) {
+	long := strings.Repeat("ắ", 250)
+
+	tests := []struct {
+		name  string
+		title string
+		want  string
+	}{
+		{"short title", "Go Backend", "Go Backend (Copy)"},
+		{"empty title", "", " (Copy)"},
+		{"long multibyte title truncated", long, strings.Repeat("ắ", 193) + " (Copy)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := copyTitle(tt.title)
+			if got != tt.want {
+				t.Errorf("copyTitle = %q, want %q", got, tt.want)
+			}
+			if n := utf8.RuneCountInString(got); n > 200 || !utf8.ValidString(got) {
+				t.Errorf("copyTitle produced %d runes or invalid UTF-8", n)
+			}
+		})
+	}
+}
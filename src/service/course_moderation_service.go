@@ -0,0 +1,351 @@
+package service
+
+import (
+	"context"
+	"lms/src/dto"
+	"lms/src/models"
+	"lms/src/repository"
+	"lms/src/utils"
+	"math"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+type courseModerationService struct {
+	moderationRepo repository.CourseModerationRepository
+	instructorRepo repository.InstructorRepository
+	emailService   EmailService
+}
+
+func NewCourseModerationService(
+	moderationRepo repository.CourseModerationRepository,
+	instructorRepo repository.InstructorRepository,
+	emailService EmailService,
+) CourseModerationService {
+	return &courseModerationService{
+		moderationRepo: moderationRepo,
+		instructorRepo: instructorRepo,
+		emailService:   emailService,
+	}
+}
+
+func (ms *courseModerationService) SubmitForReview(instructorId, courseId uint, req *dto.SubmitCourseReviewRequest) (*dto.SubmitCourseReviewResponse, error) {
+	// 1. Kiểm tra course có tồn tại và thuộc về instructor không
+	course, err := ms.instructorRepo.FindCourseByIdAndInstructor(courseId, instructorId)
+	if err != nil {
+		return nil, utils.NewError("Course not found or you don't have permission", utils.ErrCodeNotFound)
+	}
+
+	// 2. Chỉ course draft, rejected hoặc archived mới được gửi review
+	switch course.Status {
+	case "pending_review":
+		return nil, utils.NewError("Course is already waiting for review", utils.ErrCodeConflict)
+	case "published":
+		return nil, utils.NewError("Course is already published", utils.ErrCodeBadRequest)
+	}
+
+	pending, err := ms.moderationRepo.FindPendingByCourse(courseId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to check review request", utils.ErrCodeInternal)
+	}
+	if pending != nil {
+		return nil, utils.NewError("Course is already waiting for review", utils.ErrCodeConflict)
+	}
+
+	// 3. Chạy checklist để admin có thông tin khi review
+	checklist, err := ms.buildChecklist(course)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to check course content", utils.ErrCodeInternal)
+	}
+
+	moderation := &models.CourseModeration{
+		CourseId:     courseId,
+		InstructorId: instructorId,
+		Status:       "pending",
+		Checklist:    checklist,
+		SubmitNote:   req.Note,
+		SubmittedAt:  time.Now(),
+	}
+
+	// 4. Tạo yêu cầu review và chuyển course sang pending_review trong cùng transaction
+	tx := ms.moderationRepo.BeginTransaction()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Create(moderation).Error; err != nil {
+		tx.Rollback()
+		return nil, utils.WrapError(err, "Failed to submit course for review", utils.ErrCodeInternal)
+	}
+
+	if err := tx.Model(&models.Course{}).Where("id = ?", courseId).Update("status", "pending_review").Error; err != nil {
+		tx.Rollback()
+		return nil, utils.WrapError(err, "Failed to update course status", utils.ErrCodeInternal)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, utils.WrapError(err, "Failed to commit transaction", utils.ErrCodeInternal)
+	}
+
+	moderation.Course = *course
+
+	return &dto.SubmitCourseReviewResponse{
+		Message:      "Course submitted for review successfully",
+		CourseStatus: "pending_review",
+		Moderation:   toCourseModerationItem(moderation),
+	}, nil
+}
+
+func (ms *courseModerationService) GetCourseModerations(instructorId, courseId uint) (*dto.GetCourseModerationsResponse, error) {
+	// 1. Kiểm tra course có tồn tại và thuộc về instructor không
+	course, err := ms.instructorRepo.FindCourseByIdAndInstructor(courseId, instructorId)
+	if err != nil {
+		return nil, utils.NewError("Course not found or you don't have permission", utils.ErrCodeNotFound)
+	}
+
+	// 2. Lấy lịch sử review
+	moderations, err := ms.moderationRepo.GetCourseModerations(courseId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get course reviews", utils.ErrCodeInternal)
+	}
+
+	items := make([]dto.CourseModerationItem, len(moderations))
+	for i, moderation := range moderations {
+		moderation.Course = *course
+		items[i] = toCourseModerationItem(&moderation)
+	}
+
+	return &dto.GetCourseModerationsResponse{
+		CourseId:     courseId,
+		CourseStatus: course.Status,
+		Moderations:  items,
+	}, nil
+}
+
+func (ms *courseModerationService) GetModerationQueue(req *dto.GetModerationQueueQueryRequest) (*dto.GetModerationQueueResponse, error) {
+	// 1. Set default values
+	page := 1
+	if req.Page > 0 {
+		page = req.Page
+	}
+
+	limit := 20
+	if req.Limit > 0 {
+		limit = req.Limit
+	}
+
+	offset := (page - 1) * limit
+
+	// 2. Build filters - mặc định chỉ hiển thị các yêu cầu đang chờ
+	filters := make(map[string]interface{})
+	if req.Status != "" {
+		filters["status"] = req.Status
+	} else {
+		filters["status"] = "pending"
+	}
+	if req.InstructorId != 0 {
+		filters["instructor_id"] = req.InstructorId
+	}
+
+	// 3. Lấy queue
+	moderations, total, err := ms.moderationRepo.GetModerationQueue(offset, limit, filters)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get moderation queue", utils.ErrCodeInternal)
+	}
+
+	items := make([]dto.CourseModerationItem, len(moderations))
+	for i, moderation := range moderations {
+		items[i] = toCourseModerationItem(&moderation)
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+
+	return &dto.GetModerationQueueResponse{
+		Moderations: items,
+		Pagination: dto.PaginationInfo{
+			Page:       page,
+			Limit:      limit,
+			Total:      total,
+			TotalPages: totalPages,
+			HasNext:    page < totalPages,
+			HasPrev:    page > 1,
+		},
+	}, nil
+}
+
+func (ms *courseModerationService) GetModerationDetail(moderationId uint) (*dto.CourseModerationItem, error) {
+	moderation, err := ms.moderationRepo.FindById(moderationId)
+	if err != nil {
+		return nil, utils.NewError("Review request not found", utils.ErrCodeNotFound)
+	}
+
+	item := toCourseModerationItem(moderation)
+	return &item, nil
+}
+
+func (ms *courseModerationService) ApproveCourse(adminId, moderationId uint, req *dto.ApproveCourseRequest) (*dto.ReviewCourseResponse, error) {
+	return ms.reviewCourse(adminId, moderationId, true, req.Comment)
+}
+
+func (ms *courseModerationService) RejectCourse(adminId, moderationId uint, req *dto.RejectCourseRequest) (*dto.ReviewCourseResponse, error) {
+	return ms.reviewCourse(adminId, moderationId, false, req.Comment)
+}
+
+func (ms *courseModerationService) reviewCourse(adminId, moderationId uint, approved bool, comment string) (*dto.ReviewCourseResponse, error) {
+	// 1. Lấy yêu cầu review
+	moderation, err := ms.moderationRepo.FindById(moderationId)
+	if err != nil {
+		return nil, utils.NewError("Review request not found", utils.ErrCodeNotFound)
+	}
+
+	if moderation.Status != "pending" {
+		return nil, utils.NewError("Review request has already been processed", utils.ErrCodeConflict)
+	}
+
+	status := "rejected"
+	courseStatus := "rejected"
+	if approved {
+		status = "approved"
+		courseStatus = "published"
+		// Course có lịch publish trong tương lai sẽ chờ scheduler publish
+		if moderation.Course.PublishAt != nil && moderation.Course.PublishAt.After(time.Now()) {
+			courseStatus = "scheduled"
+		}
+	}
+
+	// 2. Cập nhật yêu cầu review và trạng thái course trong cùng transaction
+	tx := ms.moderationRepo.BeginTransaction()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	now := time.Now()
+	result := tx.Model(&models.CourseModeration{}).
+		Where("id = ? AND status = ?", moderationId, "pending").
+		Updates(map[string]interface{}{
+			"status":         status,
+			"reviewer_id":    adminId,
+			"review_comment": comment,
+			"reviewed_at":    now,
+		})
+	if result.Error != nil {
+		tx.Rollback()
+		return nil, utils.WrapError(result.Error, "Failed to update review request", utils.ErrCodeInternal)
+	}
+	// Admin khác đã xử lý yêu cầu này trước
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		return nil, utils.NewError("Review request has already been processed", utils.ErrCodeConflict)
+	}
+
+	if err := tx.Model(&models.Course{}).Where("id = ?", moderation.CourseId).Update("status", courseStatus).Error; err != nil {
+		tx.Rollback()
+		return nil, utils.WrapError(err, "Failed to update course status", utils.ErrCodeInternal)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, utils.WrapError(err, "Failed to commit transaction", utils.ErrCodeInternal)
+	}
+
+	if courseStatus == "published" {
+		invalidateCourseCache(context.Background())
+	}
+
+	// 3. Gửi thông báo cho instructor (không làm fail request nếu gửi lỗi)
+	instructor := moderation.Course.Instructor
+	if instructor.Email != "" {
+		_ = ms.emailService.SendCourseReviewResultEmail(instructor.Email, instructor.FullName, moderation.Course.Title, approved, comment)
+	}
+
+	message := "Course rejected"
+	if courseStatus == "published" {
+		message = "Course approved and published"
+	} else if courseStatus == "scheduled" {
+		message = "Course approved and scheduled for publishing"
+	}
+
+	return &dto.ReviewCourseResponse{
+		Message:      message,
+		ModerationId: moderationId,
+		CourseId:     moderation.CourseId,
+		Status:       status,
+		CourseStatus: courseStatus,
+	}, nil
+}
+
+// buildChecklist kiểm tra các điều kiện tối thiểu trước khi publish
+func (ms *courseModerationService) buildChecklist(course *models.Course) (models.ModerationChecklist, error) {
+	lessons, err := ms.moderationRepo.GetCourseLessons(course.Id)
+	if err != nil {
+		return models.ModerationChecklist{}, err
+	}
+
+	minLessons := utils.GetEnvInt("COURSE_REVIEW_MIN_LESSONS", 3)
+	minDescriptionLength := utils.GetEnvInt("COURSE_REVIEW_MIN_DESCRIPTION_LENGTH", 200)
+
+	lessonCount := 0
+	hasPreview := false
+	for _, lesson := range lessons {
+		if !lesson.IsPublished {
+			continue
+		}
+		lessonCount++
+		if lesson.IsPreview {
+			hasPreview = true
+		}
+	}
+
+	descriptionLength := utf8.RuneCountInString(strings.TrimSpace(course.Description))
+
+	checklist := models.ModerationChecklist{
+		HasThumbnail:         strings.TrimSpace(course.ThumbnailURL) != "",
+		LessonCount:          lessonCount,
+		MinLessons:           minLessons,
+		HasMinLessons:        lessonCount >= minLessons,
+		HasPreviewLesson:     hasPreview,
+		DescriptionLength:    descriptionLength,
+		MinDescriptionLength: minDescriptionLength,
+		HasDescription:       descriptionLength >= minDescriptionLength,
+	}
+	checklist.Passed = checklist.HasThumbnail &&
+		checklist.HasMinLessons &&
+		checklist.HasPreviewLesson &&
+		checklist.HasDescription
+
+	return checklist, nil
+}
+
+func toCourseModerationItem(moderation *models.CourseModeration) dto.CourseModerationItem {
+	checklist := moderation.Checklist
+
+	return dto.CourseModerationItem{
+		Id:             moderation.Id,
+		CourseId:       moderation.CourseId,
+		CourseTitle:    moderation.Course.Title,
+		CourseSlug:     moderation.Course.Slug,
+		InstructorId:   moderation.InstructorId,
+		InstructorName: moderation.Course.Instructor.FullName,
+		Status:         moderation.Status,
+		Checklist: dto.ModerationChecklist{
+			HasThumbnail:         checklist.HasThumbnail,
+			LessonCount:          checklist.LessonCount,
+			MinLessons:           checklist.MinLessons,
+			HasMinLessons:        checklist.HasMinLessons,
+			HasPreviewLesson:     checklist.HasPreviewLesson,
+			DescriptionLength:    checklist.DescriptionLength,
+			MinDescriptionLength: checklist.MinDescriptionLength,
+			HasDescription:       checklist.HasDescription,
+			Passed:               checklist.Passed,
+		},
+		SubmitNote:    moderation.SubmitNote,
+		ReviewerId:    moderation.ReviewerId,
+		ReviewComment: moderation.ReviewComment,
+		SubmittedAt:   moderation.SubmittedAt,
+		ReviewedAt:    moderation.ReviewedAt,
+	}
+}
@@ -0,0 +1,825 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"lms/src/coursepkg"
+	"lms/src/dto"
+	"lms/src/imaging"
+	"lms/src/models"
+	"lms/src/repository"
+	"lms/src/scorm"
+	"lms/src/storage"
+	"lms/src/utils"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type coursePackageService struct {
+	instructorRepo repository.InstructorRepository
+	categoryRepo   repository.CategoryRepository
+	revisionRepo   repository.CourseRevisionRepository
+	quizRepo       repository.QuizRepository
+	assignmentRepo repository.AssignmentRepository
+	resourceRepo   repository.LessonResourceRepository
+	scormRepo      repository.ScormRepository
+	storage        storage.Backend
+}
+
+func NewCoursePackageService(
+	instructorRepo repository.InstructorRepository,
+	categoryRepo repository.CategoryRepository,
+	revisionRepo repository.CourseRevisionRepository,
+	quizRepo repository.QuizRepository,
+	assignmentRepo repository.AssignmentRepository,
+	resourceRepo repository.LessonResourceRepository,
+	scormRepo repository.ScormRepository,
+	fileStorage storage.Backend,
+) CoursePackageService {
+	return &coursePackageService{
+		instructorRepo: instructorRepo,
+		categoryRepo:   categoryRepo,
+		revisionRepo:   revisionRepo,
+		quizRepo:       quizRepo,
+		assignmentRepo: assignmentRepo,
+		resourceRepo:   resourceRepo,
+		scormRepo:      scormRepo,
+		storage:        fileStorage,
+	}
+}
+
+// coursePackageLimits giới hạn package khi import (chặn ZIP bomb)
+func coursePackageLimits() coursepkg.Limits {
+	return coursepkg.Limits{
+		MaxFiles:     utils.GetEnvInt("COURSE_PACKAGE_MAX_FILES", 5000),
+		MaxFileSize:  max(utils.ResourceFileRules().MaxSize, utils.ScormPackageFileRules().MaxSize),
+		MaxTotalSize: int64(utils.GetEnvInt("COURSE_PACKAGE_MAX_UNCOMPRESSED_MB", 4096)) << 20,
+	}
+}
+
+func (ps *coursePackageService) ExportCourse(instructorId, courseId uint, w io.Writer) (string, error) {
+	// 1. Kiểm tra course có tồn tại và thuộc về instructor không
+	course, err := ps.instructorRepo.FindCourseByIdAndInstructor(courseId, instructorId)
+	if err != nil {
+		return "", utils.NewError("Course not found or you don't have permission", utils.ErrCodeNotFound)
+	}
+
+	lessons, err := ps.revisionRepo.GetAllCourseLessons(courseId)
+	if err != nil {
+		return "", utils.WrapError(err, "Failed to get course lessons", utils.ErrCodeInternal)
+	}
+
+	ctx := context.Background()
+	pw := coursepkg.NewWriter(w)
+
+	// 2. Thông tin course, thumbnail do storage quản lý được đóng gói, URL ngoài giữ nguyên
+	manifest := &coursepkg.Manifest{
+		ExportedAt: time.Now(),
+		Course: coursepkg.Course{
+			Title:           course.Title,
+			Slug:            course.Slug,
+			Description:     course.Description,
+			ShortDesc:       course.ShortDesc,
+			Category:        coursepkg.Category{Name: course.Category.Name, Slug: course.Category.Slug},
+			Level:           course.Level,
+			Language:        course.Language,
+			Price:           course.Price,
+			DiscountPrice:   course.DiscountPrice,
+			DurationHours:   course.DurationHours,
+			Requirements:    course.Requirements,
+			WhatYouLearn:    course.WhatYouLearn,
+			VideoPreviewURL: course.VideoPreviewURL,
+		},
+		Lessons: make([]coursepkg.Lesson, 0, len(lessons)),
+	}
+
+	if course.ThumbnailURL != "" {
+		if key, ok := storage.KeyFromPublicURL(course.ThumbnailURL); ok {
+			name := coursepkg.ThumbnailPath(path.Ext(key))
+			err := ps.exportStorageObject(ctx, pw, key, name)
+			switch {
+			case err == nil:
+				manifest.Course.ThumbnailFile = name
+			case errors.Is(err, storage.ErrObjectNotFound):
+				log.Printf("Skip exporting missing course thumbnail %s", key)
+			default:
+				return "", utils.WrapError(err, "Failed to export course thumbnail", utils.ErrCodeInternal)
+			}
+		} else {
+			manifest.Course.ThumbnailURL = course.ThumbnailURL
+		}
+	}
+
+	// 3. Lessons kèm quiz, assignment, resources và package SCORM
+	for i := range lessons {
+		lesson, err := ps.exportLesson(ctx, pw, i, &lessons[i])
+		if err != nil {
+			return "", err
+		}
+		manifest.Lessons = append(manifest.Lessons, *lesson)
+	}
+
+	// 4. Manifest được ghi sau cùng
+	if err := pw.Close(manifest); err != nil {
+		return "", utils.WrapError(err, "Failed to write course package", utils.ErrCodeInternal)
+	}
+
+	return course.Slug + ".zip", nil
+}
+
+func (ps *coursePackageService) exportLesson(ctx context.Context, pw *coursepkg.Writer, index int, lesson *models.Lesson) (*coursepkg.Lesson, error) {
+	item := &coursepkg.Lesson{
+		Title:         lesson.Title,
+		Slug:          lesson.Slug,
+		Description:   lesson.Description,
+		Content:       lesson.Content,
+		VideoURL:      lesson.VideoURL,
+		VideoDuration: lesson.VideoDuration,
+		LessonType:    lesson.LessonType,
+		LessonOrder:   lesson.LessonOrder,
+		IsPreview:     lesson.IsPreview,
+		IsPublished:   lesson.IsPublished,
+
+		UnlockAfterDays:           lesson.UnlockAfterDays,
+		RequirePreviousCompletion: lesson.RequirePreviousCompletion,
+	}
+
+	// Quiz và question bank
+	quiz, err := ps.quizRepo.FindByLessonId(lesson.Id)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get lesson quiz", utils.ErrCodeInternal)
+	}
+	if quiz != nil {
+		item.Quiz = &coursepkg.Quiz{
+			TimeLimitSeconds:    quiz.TimeLimitSeconds,
+			MaxAttempts:         quiz.MaxAttempts,
+			PassPercentage:      quiz.PassPercentage,
+			QuestionsPerAttempt: quiz.QuestionsPerAttempt,
+			ShuffleQuestions:    quiz.ShuffleQuestions,
+			ShuffleOptions:      quiz.ShuffleOptions,
+			ShowCorrectAnswers:  quiz.ShowCorrectAnswers,
+			Questions:           make([]coursepkg.Question, 0, len(quiz.Questions)),
+		}
+		for _, question := range quiz.Questions {
+			options := make([]coursepkg.Option, 0, len(question.Options))
+			for _, option := range question.Options {
+				options = append(options, coursepkg.Option{Key: option.Key, Text: option.Text, IsCorrect: option.IsCorrect})
+			}
+			item.Quiz.Questions = append(item.Quiz.Questions, coursepkg.Question{
+				QuestionType:    question.QuestionType,
+				Text:            question.Text,
+				Explanation:     question.Explanation,
+				Points:          question.Points,
+				Options:         options,
+				AcceptedAnswers: question.AcceptedAnswers,
+				CaseSensitive:   question.CaseSensitive,
+			})
+		}
+	}
+
+	// Assignment
+	assignment, err := ps.assignmentRepo.FindByLessonId(lesson.Id)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get lesson assignment", utils.ErrCodeInternal)
+	}
+	if assignment != nil {
+		rubric := make([]coursepkg.RubricCriterion, 0, len(assignment.Rubric))
+		for _, criterion := range assignment.Rubric {
+			rubric = append(rubric, coursepkg.RubricCriterion{
+				Key:         criterion.Key,
+				Title:       criterion.Title,
+				Description: criterion.Description,
+				MaxPoints:   criterion.MaxPoints,
+			})
+		}
+		item.Assignment = &coursepkg.Assignment{
+			Instructions:           assignment.Instructions,
+			DueAt:                  assignment.DueAt,
+			DueDaysAfterEnrollment: assignment.DueDaysAfterEnrollment,
+			MaxPoints:              assignment.MaxPoints,
+			PassPercentage:         assignment.PassPercentage,
+			Rubric:                 rubric,
+			AllowResubmission:      assignment.AllowResubmission,
+			MaxSubmissions:         assignment.MaxSubmissions,
+			MaxFiles:               assignment.MaxFiles,
+			AllowLateSubmission:    assignment.AllowLateSubmission,
+			LatePenaltyPercent:     assignment.LatePenaltyPercent,
+			MaxLatePenaltyPercent:  assignment.MaxLatePenaltyPercent,
+		}
+	}
+
+	// Resources (file lưu ở prefix private của storage)
+	resources, err := ps.resourceRepo.GetLessonResources(lesson.Id)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get lesson resources", utils.ErrCodeInternal)
+	}
+	for _, resource := range resources {
+		name := coursepkg.ResourcePath(index, len(item.Resources), resource.OriginalName)
+		err := ps.exportStorageObject(ctx, pw, lessonResourceKey(&resource), name)
+		if errors.Is(err, storage.ErrObjectNotFound) {
+			// File đã mất thì bản gốc cũng không tải được, bỏ qua thay vì chặn export
+			log.Printf("Skip exporting missing resource file %s: %v", resource.StoredName, err)
+			continue
+		}
+		if err != nil {
+			return nil, utils.WrapError(err, "Failed to write course package", utils.ErrCodeInternal)
+		}
+
+		item.Resources = append(item.Resources, coursepkg.Resource{
+			Title:        resource.Title,
+			Description:  resource.Description,
+			OriginalName: resource.OriginalName,
+			MimeType:     resource.MimeType,
+			File:         name,
+		})
+	}
+
+	// Package SCORM (file đã giải nén trong storage được nén lại thành ZIP)
+	scormPackage, err := ps.scormRepo.FindPackageByLessonId(lesson.Id)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get SCORM package", utils.ErrCodeInternal)
+	}
+	if scormPackage != nil {
+		name := coursepkg.ScormPath(index)
+		err := ps.exportScormPackage(ctx, pw, scormPackage, name)
+		switch {
+		case err == nil:
+			item.Scorm = &coursepkg.Scorm{File: name}
+		case errors.Is(err, storage.ErrObjectNotFound):
+			log.Printf("Skip exporting SCORM package of lesson %d with missing files: %v", lesson.Id, err)
+		default:
+			return nil, utils.WrapError(err, "Failed to export SCORM package", utils.ErrCodeInternal)
+		}
+	}
+
+	return item, nil
+}
+
+// exportScormPackage nén package ra file tạm trước, file thiếu giữa chừng không để lại entry hỏng trong package
+func (ps *coursePackageService) exportScormPackage(ctx context.Context, pw *coursepkg.Writer, record *models.ScormPackage, name string) error {
+	tmp, err := os.CreateTemp("", "scorm-export-*.zip")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	if err := writeScormArchive(ctx, ps.storage, record, tmp); err != nil {
+		return err
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	return pw.AddFile(name, tmp)
+}
+
+func (ps *coursePackageService) exportStorageObject(ctx context.Context, pw *coursepkg.Writer, key, name string) error {
+	src, err := ps.storage.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	return pw.AddFile(name, src)
+}
+
+// importPlan là kết quả kiểm tra package trước khi tạo course
+type importPlan struct {
+	pkg         *coursepkg.Package
+	categoryId  uint
+	slug        string
+	lessonSlugs []string
+	conflicts   []dto.CoursePackageConflict
+}
+
+func (p *importPlan) addConflict(conflictType, field, message string, blocking bool) {
+	p.conflicts = append(p.conflicts, dto.CoursePackageConflict{
+		Type:     conflictType,
+		Field:    field,
+		Message:  message,
+		Blocking: blocking,
+	})
+}
+
+func (p *importPlan) blocked() bool {
+	for _, conflict := range p.conflicts {
+		if conflict.Blocking {
+			return true
+		}
+	}
+	return false
+}
+
+func (ps *coursePackageService) ImportCourse(instructorId uint, req *dto.ImportCoursePackageRequest, r io.ReaderAt, size int64) (*dto.ImportCoursePackageResponse, error) {
+	// 1. Đọc package và manifest
+	if maxSize := utils.CoursePackageFileRules().MaxSize; size > maxSize {
+		return nil, utils.NewError(fmt.Sprintf("Course package is too large (max %dMB)", maxSize>>20), utils.ErrCodeBadRequest)
+	}
+
+	pkg, err := coursepkg.Open(r, size, coursePackageLimits())
+	if err != nil {
+		return nil, utils.WrapError(err, "Invalid course package", utils.ErrCodeBadRequest)
+	}
+
+	// 2. Kiểm tra nội dung, file đính kèm, category và slug
+	plan, err := ps.planImport(pkg, req)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := pkg.Manifest
+	response := &dto.ImportCoursePackageResponse{
+		DryRun:        req.DryRun,
+		FormatVersion: manifest.FormatVersion,
+		Title:         manifest.Course.Title,
+		Slug:          plan.slug,
+		Status:        "draft",
+		CategoryId:    plan.categoryId,
+		TotalLessons:  len(manifest.Lessons),
+		Conflicts:     plan.conflicts,
+	}
+	for _, lesson := range manifest.Lessons {
+		if lesson.Quiz != nil {
+			response.TotalQuizzes++
+			response.TotalQuestions += len(lesson.Quiz.Questions)
+		}
+		if lesson.Assignment != nil {
+			response.TotalAssignments++
+		}
+		response.TotalResources += len(lesson.Resources)
+	}
+
+	if plan.blocked() {
+		response.Message = "Course package has conflicts that must be resolved before importing"
+		return response, nil
+	}
+	if req.DryRun {
+		response.Message = "Course package is valid and can be imported"
+		return response, nil
+	}
+
+	// 3. Tạo course draft cho instructor
+	courseId, err := ps.createFromPackage(instructorId, plan)
+	if err != nil {
+		return nil, err
+	}
+
+	response.Message = "Course imported successfully"
+	response.Imported = true
+	response.CourseId = courseId
+	return response, nil
+}
+
+func (ps *coursePackageService) planImport(pkg *coursepkg.Package, req *dto.ImportCoursePackageRequest) (*importPlan, error) {
+	plan := &importPlan{pkg: pkg}
+	manifest := pkg.Manifest
+
+	// 1. Lỗi cấu trúc manifest
+	for _, problem := range pkg.Validate() {
+		plan.addConflict("invalid_package", problem.Field, problem.Message, true)
+	}
+
+	// 2. File đính kèm phải thỏa mãn cùng quy tắc với upload qua API
+	if name := manifest.Course.ThumbnailFile; name != "" {
+		if err := validatePackageFile(pkg, name, name, utils.ImageFileRules); err != nil {
+			plan.addConflict("invalid_file", "course.thumbnail_file", err.Error(), true)
+		}
+	}
+	resourceRules := utils.ResourceFileRules()
+	for i, lesson := range manifest.Lessons {
+		for j, resource := range lesson.Resources {
+			if err := validatePackageFile(pkg, resource.File, resource.OriginalName, resourceRules); err != nil {
+				plan.addConflict("invalid_file", fmt.Sprintf("lessons[%d].resources[%d]", i, j), err.Error(), true)
+			}
+		}
+		if lesson.Scorm != nil {
+			if err := validatePackageScorm(pkg, lesson.Scorm.File); err != nil {
+				plan.addConflict("invalid_file", fmt.Sprintf("lessons[%d].scorm", i), err.Error(), true)
+			}
+		}
+		if lesson.Assignment != nil && lesson.Assignment.DueAt != nil && lesson.Assignment.DueAt.Before(time.Now()) {
+			plan.addConflict("past_due_date", fmt.Sprintf("lessons[%d].assignment.due_at", i), "Due date has passed and will be cleared", false)
+		}
+	}
+
+	// 3. Category: category_id trong request, nếu không thì tìm theo slug trong package
+	if err := ps.resolveCategory(plan, req.CategoryId); err != nil {
+		return nil, err
+	}
+
+	// 4. Slug course trùng thì tự đổi sang slug khác
+	baseSlug := utils.GenerateSlug(manifest.Course.Slug)
+	if baseSlug == "" {
+		baseSlug = utils.GenerateSlug(manifest.Course.Title)
+	}
+	plan.slug = utils.GenerateUniqueSlug(baseSlug, func(slug string) bool {
+		_, exists := ps.instructorRepo.FindCourseBySlug(slug)
+		return exists
+	})
+	if plan.slug != baseSlug {
+		plan.addConflict("slug_collision", "course.slug", fmt.Sprintf("Slug %q is already in use, the course will be imported as %q", baseSlug, plan.slug), false)
+	}
+
+	// 5. Slug lesson chỉ cần duy nhất trong course mới
+	usedSlugs := make(map[string]bool)
+	for _, lesson := range manifest.Lessons {
+		base := utils.GenerateSlug(lesson.Slug)
+		if base == "" {
+			base = utils.GenerateSlug(lesson.Title)
+		}
+		slug := utils.GenerateUniqueSlug(base, func(slug string) bool { return usedSlugs[slug] })
+		usedSlugs[slug] = true
+		plan.lessonSlugs = append(plan.lessonSlugs, slug)
+	}
+
+	return plan, nil
+}
+
+func (ps *coursePackageService) resolveCategory(plan *importPlan, categoryId uint) error {
+	if categoryId != 0 {
+		category, err := ps.categoryRepo.FindById(categoryId)
+		if err != nil {
+			plan.addConflict("missing_category", "category_id", fmt.Sprintf("Category %d not found", categoryId), true)
+			return nil
+		}
+		if !category.IsActive {
+			plan.addConflict("inactive_category", "category_id", fmt.Sprintf("Category %q is not active", category.Name), true)
+			return nil
+		}
+		plan.categoryId = category.Id
+		return nil
+	}
+
+	ref := plan.pkg.Manifest.Course.Category
+	slug := ref.Slug
+	if slug == "" {
+		slug = utils.GenerateSlug(ref.Name)
+	}
+	if slug == "" {
+		return nil // Đã được báo bởi Validate
+	}
+
+	category, exists := ps.categoryRepo.FindBySlug(slug)
+	if !exists {
+		plan.addConflict("missing_category", "course.category", fmt.Sprintf("Category %q does not exist, create it or pass category_id", slug), true)
+		return nil
+	}
+	if !category.IsActive {
+		plan.addConflict("inactive_category", "course.category", fmt.Sprintf("Category %q is not active, activate it or pass category_id", slug), true)
+		return nil
+	}
+	plan.categoryId = category.Id
+	return nil
+}
+
+// validatePackageFile kiểm tra extension, dung lượng và MIME type của file trong package
+// (file không tồn tại đã được báo bởi Validate)
+func validatePackageFile(pkg *coursepkg.Package, name, originalName string, rules utils.FileRules) error {
+	if !pkg.HasFile(name) {
+		return nil
+	}
+
+	rc, size, err := pkg.OpenFile(name)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(rc, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return err
+	}
+
+	_, _, err = utils.ValidateFileContent(originalName, size, head[:n], rules)
+	return err
+}
+
+// validatePackageScorm kiểm tra package SCORM lồng trong package như khi upload (ZIP hợp lệ, imsmanifest.xml, file launch)
+func validatePackageScorm(pkg *coursepkg.Package, name string) error {
+	if !pkg.HasFile(name) {
+		return nil
+	}
+	if err := validatePackageFile(pkg, name, name, utils.ScormPackageFileRules()); err != nil {
+		return err
+	}
+
+	file, size, err := extractPackageTempFile(pkg, name)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	_, err = scorm.Open(file, size, scormPackageLimits())
+	return err
+}
+
+func (ps *coursePackageService) createFromPackage(instructorId uint, plan *importPlan) (uint, error) {
+	manifest := plan.pkg.Manifest
+	ctx := context.Background()
+
+	// 1. Thumbnail: xử lý lại thành các variant như upload qua API
+	thumbnailURL := manifest.Course.ThumbnailURL
+	var thumbnailVariants map[string]string
+	if name := manifest.Course.ThumbnailFile; name != "" {
+		data, err := readPackageFile(plan.pkg, name, utils.ImageFileRules.MaxSize)
+		if err != nil {
+			return 0, utils.WrapError(err, "Invalid course package", utils.ErrCodeBadRequest)
+		}
+		thumbnailVariants, err = storeImageData(ctx, ps.storage, "thumbnails", data, imaging.ThumbnailSpecs)
+		if err != nil {
+			return 0, err
+		}
+		thumbnailURL = largestImageVariant(thumbnailVariants, imaging.ThumbnailSpecs)
+	}
+
+	result := &lessonCopyResult{storage: ps.storage}
+	fail := func(tx *gorm.DB, err error) (uint, error) {
+		tx.Rollback()
+		result.cleanup()
+		deleteImageVariants(ctx, ps.storage, "", thumbnailVariants)
+		return 0, err
+	}
+
+	// 2. Tạo course, lessons, quiz, assignment và resources trong một transaction
+	tx := ps.instructorRepo.BeginTransaction()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	c := manifest.Course
+	course := &models.Course{
+		Title:             c.Title,
+		Slug:              plan.slug,
+		Description:       c.Description,
+		DescriptionHTML:   renderMarkdown(c.Description),
+		ShortDesc:         c.ShortDesc,
+		ThumbnailURL:      thumbnailURL,
+		ThumbnailVariants: thumbnailVariants,
+		VideoPreviewURL:   c.VideoPreviewURL,
+		Price:             c.Price,
+		DiscountPrice:     c.DiscountPrice,
+		InstructorId:      instructorId,
+		CategoryId:        plan.categoryId,
+		Level:             c.Level,
+		DurationHours:     c.DurationHours,
+		TotalLessons:      len(manifest.Lessons),
+		Language:          c.Language,
+		Requirements:      c.Requirements,
+		WhatYouLearn:      c.WhatYouLearn,
+		Status:            "draft",
+	}
+	if err := tx.Create(course).Error; err != nil {
+		return fail(tx, utils.WrapError(err, "Failed to create course", utils.ErrCodeInternal))
+	}
+
+	for i, item := range manifest.Lessons {
+		lesson := &models.Lesson{
+			CourseId:      course.Id,
+			Title:         item.Title,
+			Slug:          plan.lessonSlugs[i],
+			Description:   item.Description,
+			Content:       item.Content,
+			ContentHTML:   renderMarkdown(item.Content),
+			VideoURL:      item.VideoURL,
+			VideoDuration: item.VideoDuration,
+			LessonType:    item.LessonType,
+			LessonOrder:   item.LessonOrder,
+			IsPreview:     item.IsPreview,
+			IsPublished:   item.IsPublished,
+
+			UnlockAfterDays:           item.UnlockAfterDays,
+			RequirePreviousCompletion: item.RequirePreviousCompletion,
+		}
+		if err := createLessonInTx(tx, lesson); err != nil {
+			return fail(tx, utils.WrapError(err, "Failed to create lesson", utils.ErrCodeInternal))
+		}
+
+		if err := ps.importLessonContent(tx, plan.pkg, &item, lesson, instructorId, result); err != nil {
+			return fail(tx, err)
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		result.cleanup()
+		deleteImageVariants(ctx, ps.storage, "", thumbnailVariants)
+		return 0, utils.WrapError(err, "Failed to commit transaction", utils.ErrCodeInternal)
+	}
+
+	return course.Id, nil
+}
+
+func (ps *coursePackageService) importLessonContent(tx *gorm.DB, pkg *coursepkg.Package, item *coursepkg.Lesson, lesson *models.Lesson, instructorId uint, result *lessonCopyResult) error {
+	// 1. Quiz và question bank
+	if item.Quiz != nil {
+		quiz := &models.Quiz{
+			LessonId:            lesson.Id,
+			CourseId:            lesson.CourseId,
+			TimeLimitSeconds:    item.Quiz.TimeLimitSeconds,
+			MaxAttempts:         item.Quiz.MaxAttempts,
+			PassPercentage:      item.Quiz.PassPercentage,
+			QuestionsPerAttempt: item.Quiz.QuestionsPerAttempt,
+			ShuffleQuestions:    item.Quiz.ShuffleQuestions,
+			ShuffleOptions:      item.Quiz.ShuffleOptions,
+			ShowCorrectAnswers:  item.Quiz.ShowCorrectAnswers,
+		}
+		if err := tx.Create(quiz).Error; err != nil {
+			return utils.WrapError(err, "Failed to create quiz", utils.ErrCodeInternal)
+		}
+
+		questions := make([]models.QuizQuestion, 0, len(item.Quiz.Questions))
+		for i, question := range item.Quiz.Questions {
+			options := make([]models.QuizOption, 0, len(question.Options))
+			for _, option := range question.Options {
+				options = append(options, models.QuizOption{Key: option.Key, Text: option.Text, IsCorrect: option.IsCorrect})
+			}
+			acceptedAnswers := question.AcceptedAnswers
+			if acceptedAnswers == nil {
+				acceptedAnswers = []string{}
+			}
+			questions = append(questions, models.QuizQuestion{
+				QuizId:          quiz.Id,
+				QuestionType:    question.QuestionType,
+				Text:            question.Text,
+				Explanation:     question.Explanation,
+				Points:          question.Points,
+				QuestionOrder:   i + 1,
+				Options:         options,
+				AcceptedAnswers: acceptedAnswers,
+				CaseSensitive:   question.CaseSensitive,
+			})
+		}
+		if len(questions) > 0 {
+			if err := tx.Create(&questions).Error; err != nil {
+				return utils.WrapError(err, "Failed to create quiz questions", utils.ErrCodeInternal)
+			}
+		}
+
+		result.quizzes++
+		result.questions += len(questions)
+	}
+
+	// 2. Assignment (hạn nộp cố định đã qua thì bỏ)
+	if item.Assignment != nil {
+		a := item.Assignment
+		rubric := make([]models.RubricCriterion, 0, len(a.Rubric))
+		for _, criterion := range a.Rubric {
+			rubric = append(rubric, models.RubricCriterion{
+				Key:         criterion.Key,
+				Title:       criterion.Title,
+				Description: criterion.Description,
+				MaxPoints:   criterion.MaxPoints,
+			})
+		}
+
+		dueAt := a.DueAt
+		if dueAt != nil && dueAt.Before(time.Now()) {
+			dueAt = nil
+		}
+
+		assignment := &models.Assignment{
+			LessonId:               lesson.Id,
+			CourseId:               lesson.CourseId,
+			Instructions:           a.Instructions,
+			DueAt:                  dueAt,
+			DueDaysAfterEnrollment: a.DueDaysAfterEnrollment,
+			MaxPoints:              a.MaxPoints,
+			PassPercentage:         a.PassPercentage,
+			Rubric:                 rubric,
+			AllowResubmission:      a.AllowResubmission,
+			MaxSubmissions:         a.MaxSubmissions,
+			MaxFiles:               a.MaxFiles,
+			AllowLateSubmission:    a.AllowLateSubmission,
+			LatePenaltyPercent:     a.LatePenaltyPercent,
+			MaxLatePenaltyPercent:  a.MaxLatePenaltyPercent,
+		}
+		if err := tx.Create(assignment).Error; err != nil {
+			return utils.WrapError(err, "Failed to create assignment", utils.ErrCodeInternal)
+		}
+
+		result.assignments++
+	}
+
+	// 3. Resources: giải nén vào prefix private của lesson mới
+	keyPrefix := lessonResourcePrefix(lesson.CourseId, lesson.Id)
+	for i, resourceItem := range item.Resources {
+		storedName, size, mimeType, err := extractPackageResource(ps.storage, pkg, resourceItem, keyPrefix)
+		if err != nil {
+			return utils.WrapError(err, "Failed to import lesson resource", utils.ErrCodeInternal)
+		}
+		result.storageKeys = append(result.storageKeys, keyPrefix+storedName)
+
+		resource := &models.LessonResource{
+			LessonId:      lesson.Id,
+			CourseId:      lesson.CourseId,
+			Title:         strings.TrimSpace(resourceItem.Title),
+			Description:   resourceItem.Description,
+			OriginalName:  filepath.Base(resourceItem.OriginalName),
+			StoredName:    storedName,
+			Size:          size,
+			MimeType:      mimeType,
+			ResourceOrder: i + 1,
+			UploadedBy:    instructorId,
+		}
+		if err := tx.Create(resource).Error; err != nil {
+			return utils.WrapError(err, "Failed to create lesson resource", utils.ErrCodeInternal)
+		}
+
+		result.resources++
+	}
+
+	// 4. Package SCORM: giải nén vào storage dưới prefix của lesson mới
+	if item.Scorm != nil {
+		file, size, err := extractPackageTempFile(pkg, item.Scorm.File)
+		if err != nil {
+			return utils.WrapError(err, "Failed to import SCORM package", utils.ErrCodeInternal)
+		}
+		record, err := storeScormPackage(context.Background(), ps.storage, lesson.CourseId, lesson.Id, file, size)
+		file.Close()
+		os.Remove(file.Name())
+		if err != nil {
+			return utils.WrapError(err, "Failed to import SCORM package", utils.ErrCodeInternal)
+		}
+		result.scormPackages = append(result.scormPackages, record)
+
+		record.UploadedBy = instructorId
+		if err := tx.Create(record).Error; err != nil {
+			return utils.WrapError(err, "Failed to create SCORM package", utils.ErrCodeInternal)
+		}
+	}
+
+	return nil
+}
+
+// extractPackageResource ghi file resource từ package vào storage dưới keyPrefix, MIME type được nhận diện lại từ nội dung
+func extractPackageResource(backend storage.Backend, pkg *coursepkg.Package, item coursepkg.Resource, keyPrefix string) (string, int64, string, error) {
+	rc, size, err := pkg.OpenFile(item.File)
+	if err != nil {
+		return "", 0, "", err
+	}
+	defer rc.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(rc, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return "", 0, "", err
+	}
+
+	ext, mimeType, err := utils.ValidateFileContent(item.OriginalName, size, head[:n], utils.ResourceFileRules())
+	if err != nil {
+		return "", 0, "", err
+	}
+
+	storedName, err := storeFileContent(context.Background(), backend, keyPrefix, ext, io.MultiReader(bytes.NewReader(head[:n]), rc), size, mimeType)
+	if err != nil {
+		return "", 0, "", err
+	}
+	return storedName, size, mimeType, nil
+}
+
+// extractPackageTempFile ghi file trong package ra file tạm (cần io.ReaderAt để đọc ZIP lồng), caller đóng và xóa file
+func extractPackageTempFile(pkg *coursepkg.Package, name string) (*os.File, int64, error) {
+	rc, _, err := pkg.OpenFile(name)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rc.Close()
+
+	tmp, err := os.CreateTemp("", "course-import-*")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	size, err := io.Copy(tmp, rc)
+	if err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return nil, 0, err
+	}
+	return tmp, size, nil
+}
+
+func readPackageFile(pkg *coursepkg.Package, name string, maxSize int64) ([]byte, error) {
+	rc, size, err := pkg.OpenFile(name)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	if size > maxSize {
+		return nil, fmt.Errorf("file %q is too large", name)
+	}
+	return io.ReadAll(io.LimitReader(rc, maxSize+1))
+}
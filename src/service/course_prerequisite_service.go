@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"lms/src/dto"
+	"lms/src/models"
+	"lms/src/repository"
+	"lms/src/utils"
+	"strings"
+)
+
+type coursePrerequisiteService struct {
+	prerequisiteRepo repository.CoursePrerequisiteRepository
+	instructorRepo   repository.InstructorRepository
+	courseRepo       repository.CourseRepository
+}
+
+func NewCoursePrerequisiteService(
+	prerequisiteRepo repository.CoursePrerequisiteRepository,
+	instructorRepo repository.InstructorRepository,
+	courseRepo repository.CourseRepository,
+) CoursePrerequisiteService {
+	return &coursePrerequisiteService{
+		prerequisiteRepo: prerequisiteRepo,
+		instructorRepo:   instructorRepo,
+		courseRepo:       courseRepo,
+	}
+}
+
+func (ps *coursePrerequisiteService) GetCoursePrerequisites(instructorId, courseId uint) (*dto.GetCoursePrerequisitesResponse, error) {
+	// 1. Kiểm tra course có tồn tại và thuộc về instructor không
+	if _, err := ps.instructorRepo.FindCourseByIdAndInstructor(courseId, instructorId); err != nil {
+		return nil, utils.NewError("Course not found or you don't have permission", utils.ErrCodeNotFound)
+	}
+
+	// 2. Lấy danh sách prerequisites
+	prerequisites, err := ps.prerequisiteRepo.GetPrerequisites(courseId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get course prerequisites", utils.ErrCodeInternal)
+	}
+
+	return &dto.GetCoursePrerequisitesResponse{
+		CourseId:      courseId,
+		Prerequisites: toCoursePrerequisiteItems(prerequisites),
+	}, nil
+}
+
+func (ps *coursePrerequisiteService) UpdateCoursePrerequisites(instructorId, courseId uint, req *dto.UpdateCoursePrerequisitesRequest) (*dto.GetCoursePrerequisitesResponse, error) {
+	// 1. Kiểm tra course có tồn tại và thuộc về instructor không
+	if _, err := ps.instructorRepo.FindCourseByIdAndInstructor(courseId, instructorId); err != nil {
+		return nil, utils.NewError("Course not found or you don't have permission", utils.ErrCodeNotFound)
+	}
+
+	// 2. Validate từng prerequisite: không trùng, không phải chính nó, phải là course đã published
+	seen := make(map[uint]bool)
+	prerequisiteIds := make([]uint, 0, len(req.PrerequisiteCourseIds))
+	for _, prerequisiteId := range req.PrerequisiteCourseIds {
+		if seen[prerequisiteId] {
+			continue
+		}
+		seen[prerequisiteId] = true
+
+		if prerequisiteId == courseId {
+			return nil, utils.NewError("A course cannot be a prerequisite of itself", utils.ErrCodeBadRequest)
+		}
+
+		prerequisiteCourse, err := ps.courseRepo.FindById(prerequisiteId)
+		if err != nil {
+			return nil, utils.NewError("Prerequisite course not found", utils.ErrCodeNotFound)
+		}
+
+		if prerequisiteCourse.Status != "published" {
+			return nil, utils.NewError("Prerequisite course \""+prerequisiteCourse.Title+"\" is not published", utils.ErrCodeBadRequest)
+		}
+
+		prerequisiteIds = append(prerequisiteIds, prerequisiteId)
+	}
+
+	// 3. Không cho phép vòng lặp (A yêu cầu B, B yêu cầu A)
+	hasCycle, err := ps.createsCycle(courseId, prerequisiteIds)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to check prerequisite chain", utils.ErrCodeInternal)
+	}
+	if hasCycle {
+		return nil, utils.NewError("Prerequisites would create a circular dependency between courses", utils.ErrCodeBadRequest)
+	}
+
+	// 4. Lưu danh sách mới
+	if err := ps.prerequisiteRepo.ReplacePrerequisites(courseId, prerequisiteIds); err != nil {
+		return nil, utils.WrapError(err, "Failed to update course prerequisites", utils.ErrCodeInternal)
+	}
+
+	// 5. Course detail public có hiển thị prerequisites nên cần xóa cache
+	invalidateCourseCache(context.Background())
+
+	return ps.GetCoursePrerequisites(instructorId, courseId)
+}
+
+// createsCycle duyệt BFS chuỗi prerequisites của các course mới, nếu gặp lại courseId thì có vòng lặp
+func (ps *coursePrerequisiteService) createsCycle(courseId uint, prerequisiteIds []uint) (bool, error) {
+	visited := make(map[uint]bool)
+	queue := append([]uint{}, prerequisiteIds...)
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		if current == courseId {
+			return true, nil
+		}
+		if visited[current] {
+			continue
+		}
+		visited[current] = true
+
+		nextIds, err := ps.prerequisiteRepo.GetPrerequisiteIds(current)
+		if err != nil {
+			return false, err
+		}
+		queue = append(queue, nextIds...)
+	}
+
+	return false, nil
+}
+
+// checkCoursePrerequisites trả về lỗi liệt kê các course chưa hoàn thành nếu user chưa đủ điều kiện
+// Dùng chung cho EnrollCourse và CreateOrder
+func checkCoursePrerequisites(prerequisiteRepo repository.CoursePrerequisiteRepository, userId, courseId uint) error {
+	prerequisites, err := prerequisiteRepo.GetPrerequisites(courseId)
+	if err != nil {
+		return utils.WrapError(err, "Failed to check course prerequisites", utils.ErrCodeInternal)
+	}
+
+	if len(prerequisites) == 0 {
+		return nil
+	}
+
+	courseIds := make([]uint, len(prerequisites))
+	for i, prerequisite := range prerequisites {
+		courseIds[i] = prerequisite.PrerequisiteCourseId
+	}
+
+	completed, err := prerequisiteRepo.GetCompletedCourseIds(userId, courseIds)
+	if err != nil {
+		return utils.WrapError(err, "Failed to check course prerequisites", utils.ErrCodeInternal)
+	}
+
+	var missing []string
+	for _, prerequisite := range prerequisites {
+		if !completed[prerequisite.PrerequisiteCourseId] {
+			missing = append(missing, "\""+prerequisite.PrerequisiteCourse.Title+"\"")
+		}
+	}
+
+	if len(missing) > 0 {
+		return utils.NewError(
+			"You must complete the following prerequisite course(s) first: "+strings.Join(missing, ", "),
+			utils.ErrCodeForbidden,
+		)
+	}
+
+	return nil
+}
+
+func toCoursePrerequisiteItems(prerequisites []models.CoursePrerequisite) []dto.CoursePrerequisiteItem {
+	items := make([]dto.CoursePrerequisiteItem, len(prerequisites))
+	for i, prerequisite := range prerequisites {
+		items[i] = dto.CoursePrerequisiteItem{
+			CourseId:     prerequisite.PrerequisiteCourseId,
+			Title:        prerequisite.PrerequisiteCourse.Title,
+			Slug:         prerequisite.PrerequisiteCourse.Slug,
+			ThumbnailURL: prerequisite.PrerequisiteCourse.ThumbnailURL,
+			Level:        prerequisite.PrerequisiteCourse.Level,
+		}
+	}
+	return items
+}
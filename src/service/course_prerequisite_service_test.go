@@ -0,0 +1,113 @@
+package service
+
+import (
+	"lms/src/models"
+	"lms/src/repository"
+	"lms/src/utils"
+	"strings"
+	"testing"
+)
+
+// fakePrerequisiteRepo lưu đồ prerequisites và các course user đã hoàn thành trong bộ nhớ
+type fakePrerequisiteRepo struct {
+	repository.CoursePrerequisiteRepository
+	edges     map[uint][]uint // courseId -> prerequisite course ids
+	titles    map[uint]string
+	completed map[uint]bool
+}
+
+func (r *fakePrerequisiteRepo) GetPrerequisites(courseId uint) ([]models.CoursePrerequisite, error) {
+	var prerequisites []models.CoursePrerequisite
+	for _, id := range r.edges[courseId] {
+		prerequisites = append(prerequisites, models.CoursePrerequisite{
+			CourseId:             courseId,
+			PrerequisiteCourseId: id,
+			PrerequisiteCourse:   models.Course{Id: id, Title: r.titles[id]},
+		})
+	}
+	return prerequisites, nil
+}
+
+func (r *fakePrerequisiteRepo) GetPrerequisiteIds(courseId uint) ([]uint, error) {
+	return r.edges[courseId], nil
+}
+
+func (r *fakePrerequisiteRepo) GetCompletedCourseIds(userId uint, courseIds []uint) (map[uint]bool, error) {
+	completed := make(map[uint]bool)
+	for _, id := range courseIds {
+		if r.completed[id] {
+			completed[id] = true
+		}
+	}
+	return completed, nil
+}
+
+func TestCheckCoursePrerequisites(t *testing.T) {
+	edges := map[uint][]uint{3: {1, 2}, 4: {3}}
+	titles := map[uint]string{1: "Go Basics", 2: "SQL", 3: "Backend"}
+
+	tests := []struct {
+		name        string
+		courseId    uint
+		completed   map[uint]bool
+		wantCode    utils.ErrorCode
+		wantMissing []string
+	}{
+		{"no prerequisites", 1, nil, "", nil},
+		{"all completed", 3, map[uint]bool{1: true, 2: true}, "", nil},
+		{"none completed", 3, nil, utils.ErrCodeForbidden, []string{`"Go Basics"`, `"SQL"`}},
+		{"one missing", 3, map[uint]bool{1: true}, utils.ErrCodeForbidden, []string{`"SQL"`}},
+		{"only direct prerequisites checked", 4, map[uint]bool{3: true}, "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakePrerequisiteRepo{edges: edges, titles: titles, completed: tt.completed}
+			err := checkCoursePrerequisites(repo, 7, tt.courseId)
+			if got := errorCode(err); got != tt.wantCode {
+				t.Fatalf("error code = %q, want %q (err: %v)", got, tt.wantCode, err)
+			}
+			for _, title := range tt.wantMissing {
+				if !strings.Contains(err.Error(), title) {
+					t.Errorf("error %q does not mention %s", err.Error(), title)
+				}
+			}
+		})
+	}
+}
+
+func TestCreatesCycle(t *testing.T) {
+	// 2 cần 1, 3 cần 2, 5 cần 4 và 3
+	ps := &coursePrerequisiteService{prerequisiteRepo: &fakePrerequisiteRepo{edges: map[uint][]uint{
+		2: {1},
+		3: {2},
+		5: {4, 3},
+	}}}
+
+	tests := []struct {
+		name            string
+		courseId        uint
+		prerequisiteIds []uint
+		want            bool
+	}{
+		{"no prerequisites", 1, nil, false},
+		{"self reference", 1, []uint{1}, true},
+		{"unrelated course", 4, []uint{1}, false},
+		{"direct cycle", 1, []uint{2}, true},
+		{"transitive cycle", 1, []uint{5}, true},
+		{"diamond without cycle", 6, []uint{5, 3}, false},
+		{"extending the chain", 4, []uint{3}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ps.createsCycle(tt.courseId, tt.prerequisiteIds)
+			if err != nil {
+				t.Fatalf("createsCycle: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("createsCycle(%d, %v) = %v, want %v", tt.courseId, tt.prerequisiteIds, got, tt.want)
+			}
+		})
+	}
+}
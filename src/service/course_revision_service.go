@@ -0,0 +1,899 @@
+package service
+
+import (
+	"lms/src/dto"
+	"lms/src/models"
+	"lms/src/repository"
+	"lms/src/utils"
+	"log"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type courseRevisionService struct {
+	revisionRepo   repository.CourseRevisionRepository
+	instructorRepo repository.InstructorRepository
+	categoryRepo   repository.CategoryRepository
+}
+
+func NewCourseRevisionService(
+	revisionRepo repository.CourseRevisionRepository,
+	instructorRepo repository.InstructorRepository,
+	categoryRepo repository.CategoryRepository,
+) CourseRevisionService {
+	return &courseRevisionService{
+		revisionRepo:   revisionRepo,
+		instructorRepo: instructorRepo,
+		categoryRepo:   categoryRepo,
+	}
+}
+
+func (rs *courseRevisionService) GetCourseRevisions(instructorId, courseId uint, req *dto.GetCourseRevisionsQueryRequest) (*dto.GetCourseRevisionsResponse, error) {
+	// 1. Kiểm tra course có tồn tại và thuộc về instructor không
+	if _, err := rs.instructorRepo.FindCourseByIdAndInstructor(courseId, instructorId); err != nil {
+		return nil, utils.NewError("Course not found or you don't have permission", utils.ErrCodeNotFound)
+	}
+
+	// 2. Set default values
+	page := 1
+	if req.Page > 0 {
+		page = req.Page
+	}
+
+	limit := 20
+	if req.Limit > 0 {
+		limit = req.Limit
+	}
+
+	offset := (page - 1) * limit
+
+	// 3. Lấy lịch sử revisions
+	revisions, total, err := rs.revisionRepo.GetCourseRevisions(courseId, offset, limit)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get course revisions", utils.ErrCodeInternal)
+	}
+
+	items := make([]dto.CourseRevisionItem, len(revisions))
+	for i, revision := range revisions {
+		items[i] = toCourseRevisionItem(&revision)
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+
+	return &dto.GetCourseRevisionsResponse{
+		CourseId:  courseId,
+		Revisions: items,
+		Pagination: dto.PaginationInfo{
+			Page:       page,
+			Limit:      limit,
+			Total:      total,
+			TotalPages: totalPages,
+			HasNext:    page < totalPages,
+			HasPrev:    page > 1,
+		},
+	}, nil
+}
+
+func (rs *courseRevisionService) CreateDraft(instructorId, courseId uint, req *dto.CreateCourseRevisionRequest) (*dto.CourseRevisionDetail, error) {
+	// 1. Kiểm tra course có tồn tại và thuộc về instructor không
+	if _, err := rs.instructorRepo.FindCourseByIdAndInstructor(courseId, instructorId); err != nil {
+		return nil, utils.NewError("Course not found or you don't have permission", utils.ErrCodeNotFound)
+	}
+
+	// 2. Khóa course và đọc bản live trong cùng transaction để snapshot nhất quán
+	tx := rs.instructorRepo.BeginTransaction()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	course, existingDraft, err := lockCourseRevisions(tx, courseId)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Draft revision chỉ dùng cho course đã published, course draft được sửa trực tiếp
+	if course.Status != "published" {
+		tx.Rollback()
+		return nil, utils.NewError("Draft revisions are only available for published courses. Edit the course directly instead", utils.ErrCodeBadRequest)
+	}
+
+	// 3. Mỗi course chỉ có một draft đang mở
+	if existingDraft != nil {
+		tx.Rollback()
+		return nil, utils.NewError("This course already has an open draft revision", utils.ErrCodeConflict)
+	}
+
+	// 4. Dựng snapshot từ bản live
+	liveSnapshot, err := buildLiveSnapshot(tx, course)
+	if err != nil {
+		tx.Rollback()
+		return nil, utils.WrapError(err, "Failed to read course content", utils.ErrCodeInternal)
+	}
+
+	latestNumber, err := latestRevisionNumber(tx, courseId)
+	if err != nil {
+		tx.Rollback()
+		return nil, utils.WrapError(err, "Failed to get revision number", utils.ErrCodeInternal)
+	}
+
+	// 5. Lần đầu tạo draft: lưu bản live hiện tại làm revision gốc để có thể rollback về sau
+	if latestNumber == 0 {
+		now := time.Now()
+		baseline := &models.CourseRevision{
+			CourseId:       courseId,
+			RevisionNumber: 1,
+			Status:         "published",
+			Snapshot:       liveSnapshot,
+			Note:           "Initial published version",
+			CreatedBy:      instructorId,
+			PublishedAt:    &now,
+		}
+		if err := tx.Create(baseline).Error; err != nil {
+			tx.Rollback()
+			return nil, utils.WrapError(err, "Failed to create baseline revision", utils.ErrCodeInternal)
+		}
+		latestNumber = baseline.RevisionNumber
+	}
+
+	// 6. Tạo draft từ bản live
+	draft := &models.CourseRevision{
+		CourseId:       courseId,
+		RevisionNumber: latestNumber + 1,
+		Status:         "draft",
+		Snapshot:       liveSnapshot,
+		Note:           req.Note,
+		CreatedBy:      instructorId,
+	}
+
+	if err := tx.Create(draft).Error; err != nil {
+		tx.Rollback()
+		return nil, utils.WrapError(err, "Failed to create draft revision", utils.ErrCodeInternal)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, utils.WrapError(err, "Failed to commit transaction", utils.ErrCodeInternal)
+	}
+
+	detail := toCourseRevisionDetail(draft)
+	return &detail, nil
+}
+
+func (rs *courseRevisionService) GetDraft(instructorId, courseId uint) (*dto.CourseRevisionDetail, error) {
+	_, draft, err := rs.findCourseAndDraft(instructorId, courseId)
+	if err != nil {
+		return nil, err
+	}
+
+	detail := toCourseRevisionDetail(draft)
+	return &detail, nil
+}
+
+func (rs *courseRevisionService) UpdateDraft(instructorId, courseId uint, req *dto.UpdateCourseRevisionRequest) (*dto.CourseRevisionDetail, error) {
+	// 1. Lấy course và draft
+	_, draft, err := rs.findCourseAndDraft(instructorId, courseId)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := draft.Snapshot
+
+	// 2. Áp dụng các thay đổi metadata
+	if req.Title != nil {
+		snapshot.Title = *req.Title
+	}
+	if req.Description != nil {
+		snapshot.Description = *req.Description
+	}
+	if req.ShortDesc != nil {
+		snapshot.ShortDesc = *req.ShortDesc
+	}
+	if req.ThumbnailURL != nil {
+		snapshot.ThumbnailURL = *req.ThumbnailURL
+	}
+	if req.VideoPreviewURL != nil {
+		snapshot.VideoPreviewURL = *req.VideoPreviewURL
+	}
+	if req.Price != nil {
+		snapshot.Price = *req.Price
+	}
+	if req.DiscountPrice != nil {
+		snapshot.DiscountPrice = req.DiscountPrice
+	}
+	if req.Level != nil {
+		snapshot.Level = *req.Level
+	}
+	if req.Language != nil {
+		snapshot.Language = *req.Language
+	}
+	if req.Requirements != nil {
+		snapshot.Requirements = *req.Requirements
+	}
+	if req.WhatYouLearn != nil {
+		snapshot.WhatYouLearn = *req.WhatYouLearn
+	}
+	if req.DurationHours != nil {
+		snapshot.DurationHours = *req.DurationHours
+	}
+
+	// 3. Validate category nếu thay đổi
+	if req.CategoryId != nil && *req.CategoryId != snapshot.CategoryId {
+		category, err := rs.categoryRepo.FindById(*req.CategoryId)
+		if err != nil {
+			return nil, utils.NewError("category not found", utils.ErrCodeNotFound)
+		}
+		if !category.IsActive {
+			return nil, utils.NewError("category is not active", utils.ErrCodeBadRequest)
+		}
+		snapshot.CategoryId = *req.CategoryId
+	}
+
+	// 4. Validate discount price
+	if snapshot.DiscountPrice != nil && *snapshot.DiscountPrice >= snapshot.Price {
+		return nil, utils.NewError("discount price must be less than regular price", utils.ErrCodeBadRequest)
+	}
+
+	// 5. Thay thế danh sách lessons nếu được gửi lên
+	if req.Lessons != nil {
+		lessons, err := rs.buildDraftLessons(courseId, *req.Lessons)
+		if err != nil {
+			return nil, err
+		}
+		snapshot.Lessons = lessons
+	}
+
+	// 6. Lưu snapshot
+	if err := rs.revisionRepo.UpdateSnapshot(draft.Id, snapshot); err != nil {
+		return nil, utils.WrapError(err, "Failed to update draft revision", utils.ErrCodeInternal)
+	}
+
+	if req.Note != nil {
+		if err := rs.revisionRepo.Update(draft.Id, map[string]interface{}{"note": *req.Note}); err != nil {
+			return nil, utils.WrapError(err, "Failed to update draft revision", utils.ErrCodeInternal)
+		}
+	}
+
+	// 7. Lấy lại draft đã update
+	updatedDraft, err := rs.revisionRepo.FindByIdAndCourse(draft.Id, courseId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get updated draft revision", utils.ErrCodeInternal)
+	}
+
+	detail := toCourseRevisionDetail(updatedDraft)
+	return &detail, nil
+}
+
+func (rs *courseRevisionService) DiscardDraft(instructorId, courseId uint) (*dto.DeleteCourseRevisionResponse, error) {
+	_, draft, err := rs.findCourseAndDraft(instructorId, courseId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := rs.revisionRepo.Delete(draft.Id); err != nil {
+		return nil, utils.WrapError(err, "Failed to discard draft revision", utils.ErrCodeInternal)
+	}
+
+	return &dto.DeleteCourseRevisionResponse{
+		Message:    "Draft revision discarded successfully",
+		RevisionId: draft.Id,
+	}, nil
+}
+
+func (rs *courseRevisionService) GetDraftDiff(instructorId, courseId uint) (*dto.CourseRevisionDiffResponse, error) {
+	// 1. Lấy course và draft
+	course, draft, err := rs.findCourseAndDraft(instructorId, courseId)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Dựng snapshot của bản live để so sánh (diff chỉ gồm các field sửa được qua draft)
+	lessons, err := rs.revisionRepo.GetAllCourseLessons(courseId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to read course content", utils.ErrCodeInternal)
+	}
+
+	diff := diffCourseSnapshots(newCourseSnapshot(course, lessons), draft.Snapshot)
+	diff.CourseId = courseId
+	diff.RevisionId = draft.Id
+	diff.RevisionNumber = draft.RevisionNumber
+
+	return diff, nil
+}
+
+func (rs *courseRevisionService) PublishDraft(instructorId, courseId uint) (*dto.PublishCourseRevisionResponse, error) {
+	// 1. Kiểm tra course và draft
+	if _, _, err := rs.findCourseAndDraft(instructorId, courseId); err != nil {
+		return nil, err
+	}
+
+	// 2. Khóa course rồi đọc lại draft trong transaction (chặn publish/rollback chạy song song)
+	tx := rs.instructorRepo.BeginTransaction()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	course, draft, err := lockCourseRevisions(tx, courseId)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if draft == nil {
+		tx.Rollback()
+		return nil, utils.NewError("No draft revision found. Create one first", utils.ErrCodeNotFound)
+	}
+
+	if course.Status != "published" {
+		tx.Rollback()
+		return nil, utils.NewError("Only published courses can publish a draft revision", utils.ErrCodeBadRequest)
+	}
+
+	// 3. Quiz, assignment, SCORM, resources, subtitles và lịch publish được sửa trên bản live,
+	// chốt lại trạng thái hiện tại của chúng vào snapshot để revision ghi nhận đúng bản được publish
+	snapshot := draft.Snapshot
+	if err := refreshLessonContents(tx, course.Id, &snapshot); err != nil {
+		tx.Rollback()
+		return nil, utils.WrapError(err, "Failed to read lesson content", utils.ErrCodeInternal)
+	}
+
+	// 4. Thay bản live bằng draft
+	if err := rs.applySnapshot(tx, course, &snapshot); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// 5. Đánh dấu draft là bản published mới (lưu lại id của lessons mới tạo)
+	now := time.Now()
+	draft.Status = "published"
+	draft.Snapshot = snapshot
+	draft.PublishedAt = &now
+	if err := tx.Model(draft).Select("status", "snapshot", "published_at").Updates(draft).Error; err != nil {
+		tx.Rollback()
+		return nil, utils.WrapError(err, "Failed to publish draft revision", utils.ErrCodeInternal)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, utils.WrapError(err, "Failed to commit transaction", utils.ErrCodeInternal)
+	}
+
+	return &dto.PublishCourseRevisionResponse{
+		Message:        "Draft revision published successfully",
+		CourseId:       courseId,
+		RevisionId:     draft.Id,
+		RevisionNumber: draft.RevisionNumber,
+		TotalLessons:   len(snapshot.Lessons),
+	}, nil
+}
+
+func (rs *courseRevisionService) RollbackToRevision(instructorId, courseId, revisionId uint) (*dto.PublishCourseRevisionResponse, error) {
+	// 1. Kiểm tra course có tồn tại và thuộc về instructor không
+	if _, err := rs.instructorRepo.FindCourseByIdAndInstructor(courseId, instructorId); err != nil {
+		return nil, utils.NewError("Course not found or you don't have permission", utils.ErrCodeNotFound)
+	}
+
+	// 2. Chỉ rollback về revision đã từng được publish
+	target, err := rs.revisionRepo.FindByIdAndCourse(revisionId, courseId)
+	if err != nil {
+		return nil, utils.NewError("Revision not found", utils.ErrCodeNotFound)
+	}
+
+	if target.Status == "draft" {
+		return nil, utils.NewError("Cannot roll back to a draft revision. Publish it instead", utils.ErrCodeBadRequest)
+	}
+
+	// 3. Áp dụng snapshot cũ trong một transaction và ghi lại thành revision mới
+	tx := rs.instructorRepo.BeginTransaction()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	course, draft, err := lockCourseRevisions(tx, courseId)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Draft đang mở được dựng từ bản live hiện tại, publish sau rollback sẽ ghi đè bản vừa khôi phục
+	if draft != nil {
+		tx.Rollback()
+		return nil, utils.NewError("This course has an open draft revision. Publish or discard it before rolling back", utils.ErrCodeConflict)
+	}
+
+	latestNumber, err := latestRevisionNumber(tx, courseId)
+	if err != nil {
+		tx.Rollback()
+		return nil, utils.WrapError(err, "Failed to get revision number", utils.ErrCodeInternal)
+	}
+
+	snapshot := target.Snapshot
+	snapshot.Lessons = append([]models.LessonSnapshot(nil), target.Snapshot.Lessons...)
+
+	if err := rs.applySnapshot(tx, course, &snapshot); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	now := time.Now()
+	rollback := &models.CourseRevision{
+		CourseId:       courseId,
+		RevisionNumber: latestNumber + 1,
+		Status:         "published",
+		Snapshot:       snapshot,
+		Note:           "Rollback to revision " + strconv.Itoa(target.RevisionNumber),
+		CreatedBy:      instructorId,
+		PublishedAt:    &now,
+		RolledBackFrom: &target.Id,
+	}
+	if err := tx.Create(rollback).Error; err != nil {
+		tx.Rollback()
+		return nil, utils.WrapError(err, "Failed to record rollback revision", utils.ErrCodeInternal)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, utils.WrapError(err, "Failed to commit transaction", utils.ErrCodeInternal)
+	}
+
+	return &dto.PublishCourseRevisionResponse{
+		Message:        "Course rolled back to revision " + strconv.Itoa(target.RevisionNumber),
+		CourseId:       courseId,
+		RevisionId:     rollback.Id,
+		RevisionNumber: rollback.RevisionNumber,
+		TotalLessons:   len(snapshot.Lessons),
+	}, nil
+}
+
+func (rs *courseRevisionService) findCourseAndDraft(instructorId, courseId uint) (*models.Course, *models.CourseRevision, error) {
+	course, err := rs.instructorRepo.FindCourseByIdAndInstructor(courseId, instructorId)
+	if err != nil {
+		return nil, nil, utils.NewError("Course not found or you don't have permission", utils.ErrCodeNotFound)
+	}
+
+	draft, err := rs.revisionRepo.FindDraftByCourse(courseId)
+	if err != nil {
+		return nil, nil, utils.WrapError(err, "Failed to get draft revision", utils.ErrCodeInternal)
+	}
+	if draft == nil {
+		return nil, nil, utils.NewError("No draft revision found. Create one first", utils.ErrCodeNotFound)
+	}
+
+	return course, draft, nil
+}
+
+// buildDraftLessons validate danh sách lessons gửi lên cho draft
+func (rs *courseRevisionService) buildDraftLessons(courseId uint, items []dto.UpdateRevisionLessonItem) ([]models.LessonSnapshot, error) {
+	liveLessons, err := rs.revisionRepo.GetAllCourseLessons(courseId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get course lessons", utils.ErrCodeInternal)
+	}
+
+	liveIds := make(map[uint]bool, len(liveLessons))
+	for _, lesson := range liveLessons {
+		liveIds[lesson.Id] = true
+	}
+
+	seenIds := make(map[uint]bool)
+	seenOrders := make(map[int]bool)
+	lessons := make([]models.LessonSnapshot, len(items))
+
+	for i, item := range items {
+		if item.LessonId != 0 {
+			if !liveIds[item.LessonId] {
+				return nil, utils.NewError("Lesson "+strconv.Itoa(int(item.LessonId))+" does not belong to this course", utils.ErrCodeBadRequest)
+			}
+			if seenIds[item.LessonId] {
+				return nil, utils.NewError("Duplicate lesson ids found", utils.ErrCodeBadRequest)
+			}
+			seenIds[item.LessonId] = true
+		}
+
+		if seenOrders[item.LessonOrder] {
+			return nil, utils.NewError("Duplicate lesson orders found", utils.ErrCodeBadRequest)
+		}
+		seenOrders[item.LessonOrder] = true
+
+		lessons[i] = models.LessonSnapshot{
+			LessonId:      item.LessonId,
+			Title:         item.Title,
+			Description:   item.Description,
+			Content:       item.Content,
+			VideoURL:      item.VideoURL,
+			VideoDuration: item.VideoDuration,
+			LessonOrder:   item.LessonOrder,
+			IsPreview:     item.IsPreview,
+			IsPublished:   item.IsPublished,
+
+			UnlockAfterDays:           item.UnlockAfterDays,
+			RequirePreviousCompletion: item.RequirePreviousCompletion,
+		}
+	}
+
+	return lessons, nil
+}
+
+// applySnapshot ghi đè course và lessons live bằng snapshot trong transaction tx, course đã được khóa bởi lockCourseRevisions.
+// Lesson đã bị xóa nhưng còn trong snapshot được khôi phục (giữ id, progress và nội dung gắn với lesson),
+// lessons mới được tạo sẽ được gán lại LessonId trong snapshot.
+func (rs *courseRevisionService) applySnapshot(tx *gorm.DB, course *models.Course, snapshot *models.CourseSnapshot) error {
+	snapshotIds := make([]uint, 0, len(snapshot.Lessons))
+	for _, lesson := range snapshot.Lessons {
+		if lesson.LessonId != 0 {
+			snapshotIds = append(snapshotIds, lesson.LessonId)
+		}
+	}
+
+	// Đọc trong tx: lessons đang live và lessons đã xóa nhưng còn trong snapshot
+	query := tx.Unscoped().Where("course_id = ?", course.Id)
+	if len(snapshotIds) > 0 {
+		query = query.Where("(deleted_at IS NULL OR id IN ?)", snapshotIds)
+	} else {
+		query = query.Where("deleted_at IS NULL")
+	}
+
+	var lessons []models.Lesson
+	if err := query.Find(&lessons).Error; err != nil {
+		return utils.WrapError(err, "Failed to get course lessons", utils.ErrCodeInternal)
+	}
+
+	liveById := make(map[uint]models.Lesson, len(lessons))
+	for _, lesson := range lessons {
+		liveById[lesson.Id] = lesson
+	}
+
+	// 1. Lessons không còn trong snapshot sẽ bị xóa (soft delete)
+	keepIds := make(map[uint]bool)
+	for _, lesson := range snapshot.Lessons {
+		if _, exists := liveById[lesson.LessonId]; exists {
+			keepIds[lesson.LessonId] = true
+		}
+	}
+
+	removedIds := make([]uint, 0)
+	for _, lesson := range lessons {
+		if !keepIds[lesson.Id] && !lesson.DeletedAt.Valid {
+			removedIds = append(removedIds, lesson.Id)
+		}
+	}
+
+	if len(removedIds) > 0 {
+		if err := tx.Where("id IN ?", removedIds).Delete(&models.Lesson{}).Error; err != nil {
+			return utils.WrapError(err, "Failed to remove lessons", utils.ErrCodeInternal)
+		}
+	}
+
+	// 2. Giữ slug của các lesson không đổi title để tránh trùng khi sinh slug mới
+	// (lesson khôi phục có thể trùng slug với lesson tạo sau đó, khi đó sinh slug mới)
+	usedSlugs := make(map[string]bool)
+	keepSlug := make(map[uint]bool)
+	for _, lesson := range snapshot.Lessons {
+		if live, exists := liveById[lesson.LessonId]; exists && live.Title == lesson.Title && !usedSlugs[live.Slug] {
+			usedSlugs[live.Slug] = true
+			keepSlug[live.Id] = true
+		}
+	}
+
+	hasContents := snapshot.Version >= courseSnapshotVersion
+
+	// 3. Cập nhật (hoặc khôi phục) lessons cũ và tạo lessons mới
+	for i := range snapshot.Lessons {
+		item := &snapshot.Lessons[i]
+		live, exists := liveById[item.LessonId]
+
+		slug := live.Slug
+		if !keepSlug[item.LessonId] {
+			slug = nextLessonSlug(item.Title, usedSlugs)
+		}
+		usedSlugs[slug] = true
+
+		// Snapshot cũ không có lesson_type thì giữ loại hiện tại, lesson mới của draft mặc định là video
+		lessonType := item.LessonType
+		if lessonType == "" {
+			lessonType = live.LessonType
+		}
+		if lessonType == "" {
+			lessonType = lessonTypeVideo
+		}
+
+		if exists {
+			updates := map[string]interface{}{
+				"title":          item.Title,
+				"slug":           slug,
+				"description":    item.Description,
+				"content":        item.Content,
+				"content_html":   renderMarkdown(item.Content),
+				"video_url":      item.VideoURL,
+				"video_duration": item.VideoDuration,
+				"lesson_type":    lessonType,
+				"lesson_order":   item.LessonOrder,
+				"is_preview":     item.IsPreview,
+				"is_published":   item.IsPublished,
+				"deleted_at":     nil,
+
+				"unlock_after_days":           item.UnlockAfterDays,
+				"require_previous_completion": item.RequirePreviousCompletion,
+			}
+			if hasContents {
+				updates["publish_at"] = item.PublishAt
+				updates["unpublish_at"] = item.UnpublishAt
+			}
+			if err := tx.Unscoped().Model(&models.Lesson{}).Where("id = ?", item.LessonId).Updates(updates).Error; err != nil {
+				return utils.WrapError(err, "Failed to update lesson", utils.ErrCodeInternal)
+			}
+
+			if hasContents {
+				if err := restoreLessonContents(tx, course.Id, item); err != nil {
+					return utils.WrapError(err, "Failed to restore lesson content", utils.ErrCodeInternal)
+				}
+			}
+			continue
+		}
+
+		lesson := &models.Lesson{
+			CourseId:      course.Id,
+			Title:         item.Title,
+			Slug:          slug,
+			Description:   item.Description,
+			Content:       item.Content,
+			ContentHTML:   renderMarkdown(item.Content),
+			VideoURL:      item.VideoURL,
+			VideoDuration: item.VideoDuration,
+			LessonType:    lessonType,
+			LessonOrder:   item.LessonOrder,
+			IsPreview:     item.IsPreview,
+			IsPublished:   item.IsPublished,
+			PublishAt:     item.PublishAt,
+			UnpublishAt:   item.UnpublishAt,
+
+			UnlockAfterDays:           item.UnlockAfterDays,
+			RequirePreviousCompletion: item.RequirePreviousCompletion,
+		}
+		if err := tx.Create(lesson).Error; err != nil {
+			return utils.WrapError(err, "Failed to create lesson", utils.ErrCodeInternal)
+		}
+		// Gorm mặc định bỏ qua zero value khi tạo, ghi lại is_published = false nếu có
+		if !item.IsPublished {
+			if err := tx.Model(lesson).Update("is_published", false).Error; err != nil {
+				return utils.WrapError(err, "Failed to create lesson", utils.ErrCodeInternal)
+			}
+		}
+		item.LessonId = lesson.Id
+		item.LessonType = lessonType
+	}
+
+	// 4. Cập nhật metadata của course
+	updates := map[string]interface{}{
+		"title":             snapshot.Title,
+		"description":       snapshot.Description,
+		"description_html":  renderMarkdown(snapshot.Description),
+		"short_desc":        snapshot.ShortDesc,
+		"thumbnail_url":     snapshot.ThumbnailURL,
+		"video_preview_url": snapshot.VideoPreviewURL,
+		"price":             snapshot.Price,
+		"discount_price":    snapshot.DiscountPrice,
+		"category_id":       snapshot.CategoryId,
+		"level":             snapshot.Level,
+		"language":          snapshot.Language,
+		"requirements":      snapshot.Requirements,
+		"what_you_learn":    snapshot.WhatYouLearn,
+		"duration_hours":    snapshot.DurationHours,
+	}
+
+	// Variants chỉ đúng với thumbnail đã xử lý, draft đổi sang URL khác thì bỏ variants cũ
+	if snapshot.ThumbnailURL != course.ThumbnailURL {
+		updates["thumbnail_variants"] = nil
+	}
+
+	if snapshot.Title != course.Title {
+		baseSlug := utils.GenerateSlug(snapshot.Title)
+		updates["slug"] = utils.GenerateUniqueSlug(baseSlug, func(slug string) bool {
+			if slug == course.Slug {
+				return false // Cho phép giữ nguyên slug hiện tại
+			}
+			_, exists := rs.instructorRepo.FindCourseBySlug(slug)
+			return exists
+		})
+	}
+
+	if err := tx.Model(&models.Course{}).Where("id = ?", course.Id).Updates(updates).Error; err != nil {
+		return utils.WrapError(err, "Failed to update course", utils.ErrCodeInternal)
+	}
+
+	// 5. Revision đang published trước đó chuyển thành superseded
+	if err := tx.Model(&models.CourseRevision{}).
+		Where("course_id = ? AND status = ?", course.Id, "published").
+		Update("status", "superseded").Error; err != nil {
+		return utils.WrapError(err, "Failed to update revision history", utils.ErrCodeInternal)
+	}
+
+	// 6. Danh sách lessons thay đổi -> tính lại progress của học viên (job commit cùng transaction)
+	if err := tx.Create(newProgressRecalculation(course.Id, recalcReasonRevisionPublished)).Error; err != nil {
+		return utils.WrapError(err, "Failed to schedule progress recalculation", utils.ErrCodeInternal)
+	}
+
+	return nil
+}
+
+// isRetainedByRevision - file còn nằm trong snapshot của revision thì phải giữ để rollback khôi phục được.
+// Lỗi khi kiểm tra cũng giữ file (chỉ tốn dung lượng, không mất dữ liệu)
+func isRetainedByRevision(revisionRepo repository.CourseRevisionRepository, courseId uint, key string) bool {
+	referenced, err := revisionRepo.IsStorageReferenced(courseId, key)
+	if err != nil {
+		log.Printf("Failed to check revision references of %s: %v", key, err)
+		return true
+	}
+	return referenced
+}
+
+// nextLessonSlug sinh slug cho lesson, thêm hậu tố số nếu đã được dùng
+func nextLessonSlug(title string, usedSlugs map[string]bool) string {
+	baseSlug := utils.GenerateSlug(title)
+	slug := baseSlug
+	counter := 1
+	for usedSlugs[slug] {
+		slug = baseSlug + "-" + strconv.Itoa(counter)
+		counter++
+	}
+	return slug
+}
+
+func diffCourseSnapshots(live, draft models.CourseSnapshot) *dto.CourseRevisionDiffResponse {
+	diff := &dto.CourseRevisionDiffResponse{
+		CourseChanges:  []dto.RevisionFieldChange{},
+		LessonsAdded:   []dto.RevisionLessonSnapshot{},
+		LessonsRemoved: []dto.RevisionLessonSnapshot{},
+		LessonsChanged: []dto.RevisionLessonChange{},
+	}
+
+	// 1. So sánh metadata
+	changes := diff.CourseChanges
+	changes = appendFieldChange(changes, "title", live.Title, draft.Title)
+	changes = appendFieldChange(changes, "description", live.Description, draft.Description)
+	changes = appendFieldChange(changes, "short_description", live.ShortDesc, draft.ShortDesc)
+	changes = appendFieldChange(changes, "thumbnail_url", live.ThumbnailURL, draft.ThumbnailURL)
+	changes = appendFieldChange(changes, "video_preview_url", live.VideoPreviewURL, draft.VideoPreviewURL)
+	changes = appendFieldChange(changes, "price", live.Price, draft.Price)
+	changes = appendFieldChange(changes, "discount_price", live.DiscountPrice, draft.DiscountPrice)
+	changes = appendFieldChange(changes, "category_id", live.CategoryId, draft.CategoryId)
+	changes = appendFieldChange(changes, "level", live.Level, draft.Level)
+	changes = appendFieldChange(changes, "language", live.Language, draft.Language)
+	changes = appendFieldChange(changes, "requirements", live.Requirements, draft.Requirements)
+	changes = appendFieldChange(changes, "what_you_learn", live.WhatYouLearn, draft.WhatYouLearn)
+	changes = appendFieldChange(changes, "duration_hours", live.DurationHours, draft.DurationHours)
+	diff.CourseChanges = changes
+
+	// 2. So sánh lessons theo lesson_id
+	liveById := make(map[uint]models.LessonSnapshot, len(live.Lessons))
+	for _, lesson := range live.Lessons {
+		liveById[lesson.LessonId] = lesson
+	}
+
+	draftIds := make(map[uint]bool, len(draft.Lessons))
+	for _, lesson := range draft.Lessons {
+		liveLesson, exists := liveById[lesson.LessonId]
+		if lesson.LessonId == 0 || !exists {
+			diff.LessonsAdded = append(diff.LessonsAdded, toRevisionLessonSnapshot(lesson))
+			diff.OrderChanged = true
+			continue
+		}
+		draftIds[lesson.LessonId] = true
+
+		lessonChanges := []dto.RevisionFieldChange{}
+		lessonChanges = appendFieldChange(lessonChanges, "title", liveLesson.Title, lesson.Title)
+		lessonChanges = appendFieldChange(lessonChanges, "description", liveLesson.Description, lesson.Description)
+		lessonChanges = appendFieldChange(lessonChanges, "content", liveLesson.Content, lesson.Content)
+		lessonChanges = appendFieldChange(lessonChanges, "video_url", liveLesson.VideoURL, lesson.VideoURL)
+		lessonChanges = appendFieldChange(lessonChanges, "video_duration", liveLesson.VideoDuration, lesson.VideoDuration)
+		lessonChanges = appendFieldChange(lessonChanges, "lesson_order", liveLesson.LessonOrder, lesson.LessonOrder)
+		lessonChanges = appendFieldChange(lessonChanges, "is_preview", liveLesson.IsPreview, lesson.IsPreview)
+		lessonChanges = appendFieldChange(lessonChanges, "is_published", liveLesson.IsPublished, lesson.IsPublished)
+		lessonChanges = appendFieldChange(lessonChanges, "unlock_after_days", liveLesson.UnlockAfterDays, lesson.UnlockAfterDays)
+		lessonChanges = appendFieldChange(lessonChanges, "require_previous_completion", liveLesson.RequirePreviousCompletion, lesson.RequirePreviousCompletion)
+
+		if liveLesson.LessonOrder != lesson.LessonOrder {
+			diff.OrderChanged = true
+		}
+
+		if len(lessonChanges) > 0 {
+			diff.LessonsChanged = append(diff.LessonsChanged, dto.RevisionLessonChange{
+				LessonId: lesson.LessonId,
+				Title:    lesson.Title,
+				Changes:  lessonChanges,
+			})
+		}
+	}
+
+	for _, lesson := range live.Lessons {
+		if !draftIds[lesson.LessonId] {
+			diff.LessonsRemoved = append(diff.LessonsRemoved, toRevisionLessonSnapshot(lesson))
+			diff.OrderChanged = true
+		}
+	}
+
+	diff.HasChanges = len(diff.CourseChanges) > 0 ||
+		len(diff.LessonsAdded) > 0 ||
+		len(diff.LessonsRemoved) > 0 ||
+		len(diff.LessonsChanged) > 0
+
+	return diff
+}
+
+func appendFieldChange(changes []dto.RevisionFieldChange, field string, live, draft interface{}) []dto.RevisionFieldChange {
+	if reflect.DeepEqual(live, draft) {
+		return changes
+	}
+	return append(changes, dto.RevisionFieldChange{
+		Field: field,
+		Live:  live,
+		Draft: draft,
+	})
+}
+
+func toRevisionLessonSnapshot(lesson models.LessonSnapshot) dto.RevisionLessonSnapshot {
+	return dto.RevisionLessonSnapshot{
+		LessonId:      lesson.LessonId,
+		Title:         lesson.Title,
+		Description:   lesson.Description,
+		Content:       lesson.Content,
+		VideoURL:      lesson.VideoURL,
+		VideoDuration: lesson.VideoDuration,
+		LessonOrder:   lesson.LessonOrder,
+		IsPreview:     lesson.IsPreview,
+		IsPublished:   lesson.IsPublished,
+
+		UnlockAfterDays:           lesson.UnlockAfterDays,
+		RequirePreviousCompletion: lesson.RequirePreviousCompletion,
+	}
+}
+
+func toCourseRevisionItem(revision *models.CourseRevision) dto.CourseRevisionItem {
+	return dto.CourseRevisionItem{
+		Id:             revision.Id,
+		CourseId:       revision.CourseId,
+		RevisionNumber: revision.RevisionNumber,
+		Status:         revision.Status,
+		Note:           revision.Note,
+		CreatedBy:      revision.CreatedBy,
+		TotalLessons:   len(revision.Snapshot.Lessons),
+		PublishedAt:    revision.PublishedAt,
+		RolledBackFrom: revision.RolledBackFrom,
+		CreatedAt:      revision.CreatedAt,
+		UpdatedAt:      revision.UpdatedAt,
+	}
+}
+
+func toCourseRevisionDetail(revision *models.CourseRevision) dto.CourseRevisionDetail {
+	snapshot := revision.Snapshot
+
+	lessons := make([]dto.RevisionLessonSnapshot, len(snapshot.Lessons))
+	for i, lesson := range snapshot.Lessons {
+		lessons[i] = toRevisionLessonSnapshot(lesson)
+	}
+
+	return dto.CourseRevisionDetail{
+		CourseRevisionItem: toCourseRevisionItem(revision),
+		Snapshot: dto.RevisionSnapshot{
+			Title:           snapshot.Title,
+			Description:     snapshot.Description,
+			ShortDesc:       snapshot.ShortDesc,
+			ThumbnailURL:    snapshot.ThumbnailURL,
+			VideoPreviewURL: snapshot.VideoPreviewURL,
+			Price:           snapshot.Price,
+			DiscountPrice:   snapshot.DiscountPrice,
+			CategoryId:      snapshot.CategoryId,
+			Level:           snapshot.Level,
+			Language:        snapshot.Language,
+			Requirements:    snapshot.Requirements,
+			WhatYouLearn:    snapshot.WhatYouLearn,
+			DurationHours:   snapshot.DurationHours,
+			Lessons:         lessons,
+		},
+	}
+}
@@ -0,0 +1,435 @@
+package service
+
+import (
+	"lms/src/models"
+	"lms/src/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// courseSnapshotVersion - snapshot có đủ nội dung của lesson (loại lesson, lịch publish, quiz, assignment,
+// SCORM package, resources, subtitles). Snapshot cũ (version 0) chỉ khôi phục được các field cơ bản
+const courseSnapshotVersion = 1
+
+// lockCourseRevisions khóa dòng course (SELECT ... FOR UPDATE) đến hết transaction để tạo draft, publish và rollback
+// của cùng course chạy tuần tự, rồi đọc course và draft đang mở (nil nếu không có) trong transaction
+func lockCourseRevisions(tx *gorm.DB, courseId uint) (*models.Course, *models.CourseRevision, error) {
+	var course models.Course
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", courseId).First(&course).Error; err != nil {
+		return nil, nil, utils.WrapError(err, "Failed to lock course", utils.ErrCodeInternal)
+	}
+
+	var drafts []models.CourseRevision
+	if err := tx.Where("course_id = ? AND status = ?", courseId, "draft").
+		Order("revision_number DESC").
+		Limit(1).
+		Find(&drafts).Error; err != nil {
+		return nil, nil, utils.WrapError(err, "Failed to get draft revision", utils.ErrCodeInternal)
+	}
+
+	if len(drafts) == 0 {
+		return &course, nil, nil
+	}
+	return &course, &drafts[0], nil
+}
+
+// latestRevisionNumber tính cả revision đã bị xóa (draft bị hủy) để số revision không bị dùng lại
+func latestRevisionNumber(tx *gorm.DB, courseId uint) (int, error) {
+	var latest struct {
+		Max int
+	}
+	err := tx.Unscoped().Model(&models.CourseRevision{}).
+		Select("COALESCE(MAX(revision_number), 0) as max").
+		Where("course_id = ?", courseId).
+		Scan(&latest).Error
+
+	return latest.Max, err
+}
+
+// buildLiveSnapshot dựng snapshot đầy đủ (kể cả nội dung gắn với lesson) từ dữ liệu đang hiển thị cho học viên
+func buildLiveSnapshot(db *gorm.DB, course *models.Course) (models.CourseSnapshot, error) {
+	var lessons []models.Lesson
+	if err := db.Where("course_id = ?", course.Id).Order("lesson_order ASC").Find(&lessons).Error; err != nil {
+		return models.CourseSnapshot{}, err
+	}
+
+	snapshot := newCourseSnapshot(course, lessons)
+	if err := captureLessonContents(db, &snapshot); err != nil {
+		return models.CourseSnapshot{}, err
+	}
+	return snapshot, nil
+}
+
+// newCourseSnapshot dựng snapshot metadata và các field của lessons, chưa có nội dung gắn với lesson
+func newCourseSnapshot(course *models.Course, lessons []models.Lesson) models.CourseSnapshot {
+	snapshot := models.CourseSnapshot{
+		Title:           course.Title,
+		Description:     course.Description,
+		ShortDesc:       course.ShortDesc,
+		ThumbnailURL:    course.ThumbnailURL,
+		VideoPreviewURL: course.VideoPreviewURL,
+		Price:           course.Price,
+		DiscountPrice:   course.DiscountPrice,
+		CategoryId:      course.CategoryId,
+		Level:           course.Level,
+		Language:        course.Language,
+		Requirements:    course.Requirements,
+		WhatYouLearn:    course.WhatYouLearn,
+		DurationHours:   course.DurationHours,
+		Lessons:         make([]models.LessonSnapshot, len(lessons)),
+	}
+
+	for i, lesson := range lessons {
+		snapshot.Lessons[i] = models.LessonSnapshot{
+			LessonId:      lesson.Id,
+			Title:         lesson.Title,
+			Description:   lesson.Description,
+			Content:       lesson.Content,
+			VideoURL:      lesson.VideoURL,
+			VideoDuration: lesson.VideoDuration,
+			LessonOrder:   lesson.LessonOrder,
+			IsPreview:     lesson.IsPreview,
+			IsPublished:   lesson.IsPublished,
+			LessonType:    lesson.LessonType,
+			PublishAt:     lesson.PublishAt,
+			UnpublishAt:   lesson.UnpublishAt,
+
+			UnlockAfterDays:           lesson.UnlockAfterDays,
+			RequirePreviousCompletion: lesson.RequirePreviousCompletion,
+		}
+	}
+
+	return snapshot
+}
+
+// refreshLessonContents lấy loại lesson, lịch publish và nội dung gắn với lesson từ bản live cho các lesson của draft.
+// Draft chỉ sửa các field của course/lesson, phần còn lại được sửa trực tiếp trên bản live
+func refreshLessonContents(tx *gorm.DB, courseId uint, snapshot *models.CourseSnapshot) error {
+	var lessons []models.Lesson
+	if err := tx.Where("course_id = ?", courseId).Find(&lessons).Error; err != nil {
+		return err
+	}
+
+	liveById := make(map[uint]models.Lesson, len(lessons))
+	for _, lesson := range lessons {
+		liveById[lesson.Id] = lesson
+	}
+
+	for i := range snapshot.Lessons {
+		item := &snapshot.Lessons[i]
+		if live, exists := liveById[item.LessonId]; exists {
+			item.LessonType = live.LessonType
+			item.PublishAt = live.PublishAt
+			item.UnpublishAt = live.UnpublishAt
+		}
+	}
+
+	return captureLessonContents(tx, snapshot)
+}
+
+// captureLessonContents ghi quiz (kèm question bank), assignment, SCORM package, resources và subtitles
+// hiện tại của từng lesson vào snapshot
+func captureLessonContents(db *gorm.DB, snapshot *models.CourseSnapshot) error {
+	snapshot.Version = courseSnapshotVersion
+
+	indexById := make(map[uint]int, len(snapshot.Lessons))
+	lessonIds := make([]uint, 0, len(snapshot.Lessons))
+	for i := range snapshot.Lessons {
+		item := &snapshot.Lessons[i]
+		item.Quiz, item.Assignment, item.ScormPackage, item.Resources, item.Subtitles = nil, nil, nil, nil, nil
+
+		if item.LessonId != 0 {
+			indexById[item.LessonId] = i
+			lessonIds = append(lessonIds, item.LessonId)
+		}
+	}
+
+	if len(lessonIds) == 0 {
+		return nil
+	}
+
+	var quizzes []models.Quiz
+	if err := db.Preload("Questions", func(db *gorm.DB) *gorm.DB {
+		return db.Order("question_order ASC, id ASC")
+	}).Where("lesson_id IN ?", lessonIds).Find(&quizzes).Error; err != nil {
+		return err
+	}
+	for i := range quizzes {
+		snapshot.Lessons[indexById[quizzes[i].LessonId]].Quiz = &quizzes[i]
+	}
+
+	var assignments []models.Assignment
+	if err := db.Where("lesson_id IN ?", lessonIds).Find(&assignments).Error; err != nil {
+		return err
+	}
+	for i := range assignments {
+		snapshot.Lessons[indexById[assignments[i].LessonId]].Assignment = &assignments[i]
+	}
+
+	var packages []models.ScormPackage
+	if err := db.Where("lesson_id IN ?", lessonIds).Find(&packages).Error; err != nil {
+		return err
+	}
+	for _, pkg := range packages {
+		snapshot.Lessons[indexById[pkg.LessonId]].ScormPackage = &models.ScormPackageSnapshot{
+			Id:            pkg.Id,
+			Version:       pkg.Version,
+			Identifier:    pkg.Identifier,
+			Title:         pkg.Title,
+			LaunchPath:    pkg.LaunchPath,
+			LaunchQuery:   pkg.LaunchQuery,
+			MasteryScore:  pkg.MasteryScore,
+			StoragePrefix: pkg.StoragePrefix,
+			Files:         pkg.Files,
+			FileCount:     pkg.FileCount,
+			TotalSize:     pkg.TotalSize,
+			UploadedBy:    pkg.UploadedBy,
+			CreatedAt:     pkg.CreatedAt,
+		}
+	}
+
+	var resources []models.LessonResource
+	if err := db.Where("lesson_id IN ?", lessonIds).Order("resource_order ASC, id ASC").Find(&resources).Error; err != nil {
+		return err
+	}
+	for _, resource := range resources {
+		item := &snapshot.Lessons[indexById[resource.LessonId]]
+		item.Resources = append(item.Resources, models.LessonResourceSnapshot{
+			Id:            resource.Id,
+			Title:         resource.Title,
+			Description:   resource.Description,
+			OriginalName:  resource.OriginalName,
+			StoredName:    resource.StoredName,
+			Size:          resource.Size,
+			MimeType:      resource.MimeType,
+			ResourceOrder: resource.ResourceOrder,
+			UploadedBy:    resource.UploadedBy,
+			CreatedAt:     resource.CreatedAt,
+		})
+	}
+
+	var subtitles []models.LessonSubtitle
+	if err := db.Where("lesson_id IN ?", lessonIds).Order("language ASC").Find(&subtitles).Error; err != nil {
+		return err
+	}
+	for _, subtitle := range subtitles {
+		item := &snapshot.Lessons[indexById[subtitle.LessonId]]
+		item.Subtitles = append(item.Subtitles, models.LessonSubtitleSnapshot{
+			Id:           subtitle.Id,
+			Language:     subtitle.Language,
+			Label:        subtitle.Label,
+			SourceFormat: subtitle.SourceFormat,
+			StorageKey:   subtitle.StorageKey,
+			CueCount:     subtitle.CueCount,
+			IsDefault:    subtitle.IsDefault,
+			UploadedBy:   subtitle.UploadedBy,
+			CreatedAt:    subtitle.CreatedAt,
+		})
+	}
+
+	return nil
+}
+
+// restoreLessonContents đưa quiz, assignment, SCORM package, resources và subtitles của lesson về đúng như snapshot.
+// Record được khôi phục theo id (kể cả record đã soft delete) để attempt/submission cũ vẫn trỏ đúng câu hỏi, bài tập;
+// record không có trong snapshot bị xóa, file trong storage được giữ lại vì revision khác còn dùng
+func restoreLessonContents(tx *gorm.DB, courseId uint, item *models.LessonSnapshot) error {
+	if err := restoreQuiz(tx, courseId, item.LessonId, item.Quiz); err != nil {
+		return err
+	}
+	if err := restoreAssignment(tx, courseId, item.LessonId, item.Assignment); err != nil {
+		return err
+	}
+	if err := restoreScormPackage(tx, courseId, item.LessonId, item.ScormPackage); err != nil {
+		return err
+	}
+	if err := restoreLessonResources(tx, courseId, item.LessonId, item.Resources); err != nil {
+		return err
+	}
+	return restoreLessonSubtitles(tx, courseId, item.LessonId, item.Subtitles)
+}
+
+func restoreQuiz(tx *gorm.DB, courseId, lessonId uint, snapshot *models.Quiz) error {
+	var existing []models.Quiz
+	if err := tx.Unscoped().Where("lesson_id = ?", lessonId).Limit(1).Find(&existing).Error; err != nil {
+		return err
+	}
+
+	if snapshot == nil {
+		if len(existing) == 0 {
+			return nil
+		}
+		return tx.Delete(&existing[0]).Error
+	}
+
+	quiz := *snapshot
+	quiz.LessonId = lessonId
+	quiz.CourseId = courseId
+	quiz.Questions = nil
+	quiz.DeletedAt = gorm.DeletedAt{}
+	if len(existing) > 0 {
+		quiz.Id = existing[0].Id // lesson_id là unique, giữ record đang có
+	}
+	if err := tx.Unscoped().Save(&quiz).Error; err != nil {
+		return err
+	}
+
+	keepIds := make([]uint, len(snapshot.Questions))
+	for i, question := range snapshot.Questions {
+		question.QuizId = quiz.Id
+		question.DeletedAt = gorm.DeletedAt{}
+		if err := tx.Unscoped().Save(&question).Error; err != nil {
+			return err
+		}
+		keepIds[i] = question.Id
+	}
+
+	return deleteExcept(tx.Where("quiz_id = ?", quiz.Id), &models.QuizQuestion{}, keepIds)
+}
+
+func restoreAssignment(tx *gorm.DB, courseId, lessonId uint, snapshot *models.Assignment) error {
+	var existing []models.Assignment
+	if err := tx.Unscoped().Where("lesson_id = ?", lessonId).Limit(1).Find(&existing).Error; err != nil {
+		return err
+	}
+
+	if snapshot == nil {
+		if len(existing) == 0 {
+			return nil
+		}
+		return tx.Delete(&existing[0]).Error
+	}
+
+	assignment := *snapshot
+	assignment.LessonId = lessonId
+	assignment.CourseId = courseId
+	assignment.DeletedAt = gorm.DeletedAt{}
+	if len(existing) > 0 {
+		assignment.Id = existing[0].Id
+	}
+	return tx.Unscoped().Save(&assignment).Error
+}
+
+func restoreScormPackage(tx *gorm.DB, courseId, lessonId uint, snapshot *models.ScormPackageSnapshot) error {
+	var existing []models.ScormPackage
+	if err := tx.Where("lesson_id = ?", lessonId).Limit(1).Find(&existing).Error; err != nil {
+		return err
+	}
+
+	if snapshot == nil {
+		if len(existing) == 0 {
+			return nil
+		}
+		return tx.Delete(&existing[0]).Error
+	}
+
+	pkg := models.ScormPackage{
+		Id:            snapshot.Id,
+		LessonId:      lessonId,
+		CourseId:      courseId,
+		Version:       snapshot.Version,
+		Identifier:    snapshot.Identifier,
+		Title:         snapshot.Title,
+		LaunchPath:    snapshot.LaunchPath,
+		LaunchQuery:   snapshot.LaunchQuery,
+		MasteryScore:  snapshot.MasteryScore,
+		StoragePrefix: snapshot.StoragePrefix,
+		Files:         snapshot.Files,
+		FileCount:     snapshot.FileCount,
+		TotalSize:     snapshot.TotalSize,
+		UploadedBy:    snapshot.UploadedBy,
+		CreatedAt:     snapshot.CreatedAt,
+	}
+	contentChanged := len(existing) == 0 || existing[0].StoragePrefix != pkg.StoragePrefix
+	if len(existing) > 0 {
+		pkg.Id = existing[0].Id
+	}
+	if err := tx.Save(&pkg).Error; err != nil {
+		return err
+	}
+	if !contentChanged {
+		return nil
+	}
+
+	// Nội dung package đổi thì vị trí/suspend_data cũ không còn dùng được (giống khi upload lại package)
+	return tx.Model(&models.ScormAttempt{}).
+		Where("lesson_id = ?", lessonId).
+		Updates(map[string]interface{}{
+			"lesson_location": "",
+			"suspend_data":    "",
+			"exit":            "",
+		}).Error
+}
+
+func restoreLessonResources(tx *gorm.DB, courseId, lessonId uint, snapshots []models.LessonResourceSnapshot) error {
+	var existing []models.LessonResource
+	if err := tx.Unscoped().Where("lesson_id = ?", lessonId).Find(&existing).Error; err != nil {
+		return err
+	}
+
+	// Lượt tải là số liệu thống kê, không quay về theo snapshot
+	downloads := make(map[uint]int, len(existing))
+	for _, resource := range existing {
+		downloads[resource.Id] = resource.DownloadCount
+	}
+
+	keepIds := make([]uint, len(snapshots))
+	for i, snapshot := range snapshots {
+		resource := models.LessonResource{
+			Id:            snapshot.Id,
+			LessonId:      lessonId,
+			CourseId:      courseId,
+			Title:         snapshot.Title,
+			Description:   snapshot.Description,
+			OriginalName:  snapshot.OriginalName,
+			StoredName:    snapshot.StoredName,
+			Size:          snapshot.Size,
+			MimeType:      snapshot.MimeType,
+			ResourceOrder: snapshot.ResourceOrder,
+			DownloadCount: downloads[snapshot.Id],
+			UploadedBy:    snapshot.UploadedBy,
+			CreatedAt:     snapshot.CreatedAt,
+		}
+		if err := tx.Unscoped().Save(&resource).Error; err != nil {
+			return err
+		}
+		keepIds[i] = resource.Id
+	}
+
+	return deleteExcept(tx.Where("lesson_id = ?", lessonId), &models.LessonResource{}, keepIds)
+}
+
+// restoreLessonSubtitles khôi phục track theo id, cue của track cũ vẫn còn vì thay/xóa track chỉ soft delete
+func restoreLessonSubtitles(tx *gorm.DB, courseId, lessonId uint, snapshots []models.LessonSubtitleSnapshot) error {
+	keepIds := make([]uint, len(snapshots))
+	for i, snapshot := range snapshots {
+		subtitle := models.LessonSubtitle{
+			Id:           snapshot.Id,
+			LessonId:     lessonId,
+			CourseId:     courseId,
+			Language:     snapshot.Language,
+			Label:        snapshot.Label,
+			SourceFormat: snapshot.SourceFormat,
+			StorageKey:   snapshot.StorageKey,
+			CueCount:     snapshot.CueCount,
+			IsDefault:    snapshot.IsDefault,
+			UploadedBy:   snapshot.UploadedBy,
+			CreatedAt:    snapshot.CreatedAt,
+		}
+		if err := tx.Unscoped().Save(&subtitle).Error; err != nil {
+			return err
+		}
+		keepIds[i] = subtitle.Id
+	}
+
+	return deleteExcept(tx.Where("lesson_id = ?", lessonId), &models.LessonSubtitle{}, keepIds)
+}
+
+// deleteExcept soft delete các record trong scope không nằm trong keepIds
+func deleteExcept(scope *gorm.DB, model interface{}, keepIds []uint) error {
+	if len(keepIds) > 0 {
+		scope = scope.Where("id NOT IN ?", keepIds)
+	}
+	return scope.Delete(model).Error
+}
@@ -8,12 +8,14 @@ import (
 )
 
 type courseService struct {
-	courseRepo repository.CourseRepository
+	courseRepo       repository.CourseRepository
+	prerequisiteRepo repository.CoursePrerequisiteRepository
 }
 
-func NewCourseService(courseRepo repository.CourseRepository) CourseService {
+func NewCourseService(courseRepo repository.CourseRepository, prerequisiteRepo repository.CoursePrerequisiteRepository) CourseService {
 	return &courseService{
-		courseRepo: courseRepo,
+		courseRepo:       courseRepo,
+		prerequisiteRepo: prerequisiteRepo,
 	}
 }
 
@@ -102,27 +104,28 @@ func (cs *courseService) GetCourses(req *dto.GetCoursesQueryRequest) (*dto.GetCo
 		}
 
 		courseItems[i] = dto.CourseItem{
-			Id:             course.Id,
-			Title:          course.Title,
-			Slug:           course.Slug,
-			ShortDesc:      course.ShortDesc,
-			ThumbnailURL:   course.ThumbnailURL,
-			Price:          course.Price,
-			DiscountPrice:  course.DiscountPrice,
-			InstructorId:   course.InstructorId,
-			InstructorName: instructorName,
-			CategoryId:     course.CategoryId,
-			CategoryName:   categoryName,
-			Level:          course.Level,
-			DurationHours:  course.DurationHours,
-			TotalLessons:   course.TotalLessons,
-			Language:       course.Language,
-			Status:         course.Status,
-			IsFeatured:     course.IsFeatured,
-			RatingAvg:      course.RatingAvg,
-			RatingCount:    course.RatingCount,
-			EnrolledCount:  course.EnrolledCount,
-			CreatedAt:      course.CreatedAt,
+			Id:                course.Id,
+			Title:             course.Title,
+			Slug:              course.Slug,
+			ShortDesc:         course.ShortDesc,
+			ThumbnailURL:      course.ThumbnailURL,
+			ThumbnailVariants: course.ThumbnailVariants,
+			Price:             course.Price,
+			DiscountPrice:     course.DiscountPrice,
+			InstructorId:      course.InstructorId,
+			InstructorName:    instructorName,
+			CategoryId:        course.CategoryId,
+			CategoryName:      categoryName,
+			Level:             course.Level,
+			DurationHours:     course.DurationHours,
+			TotalLessons:      course.TotalLessons,
+			Language:          course.Language,
+			Status:            course.Status,
+			IsFeatured:        course.IsFeatured,
+			RatingAvg:         course.RatingAvg,
+			RatingCount:       course.RatingCount,
+			EnrolledCount:     course.EnrolledCount,
+			CreatedAt:         course.CreatedAt,
 		}
 	}
 
@@ -209,27 +212,28 @@ func (cs *courseService) SearchCourses(req *dto.SearchCoursesQueryRequest) (*dto
 		}
 
 		courseItems[i] = dto.CourseItem{
-			Id:             course.Id,
-			Title:          course.Title,
-			Slug:           course.Slug,
-			ShortDesc:      course.ShortDesc,
-			ThumbnailURL:   course.ThumbnailURL,
-			Price:          course.Price,
-			DiscountPrice:  course.DiscountPrice,
-			InstructorId:   course.InstructorId,
-			InstructorName: instructorName,
-			CategoryId:     course.CategoryId,
-			CategoryName:   categoryName,
-			Level:          course.Level,
-			DurationHours:  course.DurationHours,
-			TotalLessons:   course.TotalLessons,
-			Language:       course.Language,
-			Status:         course.Status,
-			IsFeatured:     course.IsFeatured,
-			RatingAvg:      course.RatingAvg,
-			RatingCount:    course.RatingCount,
-			EnrolledCount:  course.EnrolledCount,
-			CreatedAt:      course.CreatedAt,
+			Id:                course.Id,
+			Title:             course.Title,
+			Slug:              course.Slug,
+			ShortDesc:         course.ShortDesc,
+			ThumbnailURL:      course.ThumbnailURL,
+			ThumbnailVariants: course.ThumbnailVariants,
+			Price:             course.Price,
+			DiscountPrice:     course.DiscountPrice,
+			InstructorId:      course.InstructorId,
+			InstructorName:    instructorName,
+			CategoryId:        course.CategoryId,
+			CategoryName:      categoryName,
+			Level:             course.Level,
+			DurationHours:     course.DurationHours,
+			TotalLessons:      course.TotalLessons,
+			Language:          course.Language,
+			Status:            course.Status,
+			IsFeatured:        course.IsFeatured,
+			RatingAvg:         course.RatingAvg,
+			RatingCount:       course.RatingCount,
+			EnrolledCount:     course.EnrolledCount,
+			CreatedAt:         course.CreatedAt,
 		}
 	}
 
@@ -299,27 +303,28 @@ func (cs *courseService) GetFeaturedCourses(req *dto.GetFeaturedCoursesQueryRequ
 		}
 
 		courseItems[i] = dto.CourseItem{
-			Id:             course.Id,
-			Title:          course.Title,
-			Slug:           course.Slug,
-			ShortDesc:      course.ShortDesc,
-			ThumbnailURL:   course.ThumbnailURL,
-			Price:          course.Price,
-			DiscountPrice:  course.DiscountPrice,
-			InstructorId:   course.InstructorId,
-			InstructorName: instructorName,
-			CategoryId:     course.CategoryId,
-			CategoryName:   categoryName,
-			Level:          course.Level,
-			DurationHours:  course.DurationHours,
-			TotalLessons:   course.TotalLessons,
-			Language:       course.Language,
-			Status:         course.Status,
-			IsFeatured:     course.IsFeatured,
-			RatingAvg:      course.RatingAvg,
-			RatingCount:    course.RatingCount,
-			EnrolledCount:  course.EnrolledCount,
-			CreatedAt:      course.CreatedAt,
+			Id:                course.Id,
+			Title:             course.Title,
+			Slug:              course.Slug,
+			ShortDesc:         course.ShortDesc,
+			ThumbnailURL:      course.ThumbnailURL,
+			ThumbnailVariants: course.ThumbnailVariants,
+			Price:             course.Price,
+			DiscountPrice:     course.DiscountPrice,
+			InstructorId:      course.InstructorId,
+			InstructorName:    instructorName,
+			CategoryId:        course.CategoryId,
+			CategoryName:      categoryName,
+			Level:             course.Level,
+			DurationHours:     course.DurationHours,
+			TotalLessons:      course.TotalLessons,
+			Language:          course.Language,
+			Status:            course.Status,
+			IsFeatured:        course.IsFeatured,
+			RatingAvg:         course.RatingAvg,
+			RatingCount:       course.RatingCount,
+			EnrolledCount:     course.EnrolledCount,
+			CreatedAt:         course.CreatedAt,
 		}
 	}
 
@@ -356,33 +361,43 @@ func (cs *courseService) GetCourseBySlug(slug string) (*dto.CourseDetail, error)
 		categoryName = course.Category.Name
 	}
 
+	// Get prerequisites
+	prerequisites, err := cs.prerequisiteRepo.GetPrerequisites(course.Id)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get course prerequisites", utils.ErrCodeInternal)
+	}
+
 	return &dto.CourseDetail{
-		Id:              course.Id,
-		Title:           course.Title,
-		Slug:            course.Slug,
-		Description:     course.Description,
-		ShortDesc:       course.ShortDesc,
-		ThumbnailURL:    course.ThumbnailURL,
-		VideoPreviewURL: course.VideoPreviewURL,
-		Price:           course.Price,
-		DiscountPrice:   course.DiscountPrice,
-		InstructorId:    course.InstructorId,
-		InstructorName:  instructorName,
-		InstructorBio:   instructorBio,
-		CategoryId:      course.CategoryId,
-		CategoryName:    categoryName,
-		Level:           course.Level,
-		DurationHours:   course.DurationHours,
-		TotalLessons:    course.TotalLessons,
-		Language:        course.Language,
-		Requirements:    course.Requirements,
-		WhatYouLearn:    course.WhatYouLearn,
-		Status:          course.Status,
-		IsFeatured:      course.IsFeatured,
-		RatingAvg:       course.RatingAvg,
-		RatingCount:     course.RatingCount,
-		EnrolledCount:   course.EnrolledCount,
-		CreatedAt:       course.CreatedAt,
-		UpdatedAt:       course.UpdatedAt,
+		Id:                 course.Id,
+		Title:              course.Title,
+		Slug:               course.Slug,
+		Description:        course.Description,
+		DescriptionHTML:    renderedHTML(course.Description, course.DescriptionHTML),
+		ShortDesc:          course.ShortDesc,
+		ThumbnailURL:       course.ThumbnailURL,
+		ThumbnailVariants:  course.ThumbnailVariants,
+		VideoPreviewURL:    course.VideoPreviewURL,
+		Price:              course.Price,
+		DiscountPrice:      course.DiscountPrice,
+		InstructorId:       course.InstructorId,
+		InstructorName:     instructorName,
+		InstructorBio:      instructorBio,
+		CategoryId:         course.CategoryId,
+		CategoryName:       categoryName,
+		Level:              course.Level,
+		DurationHours:      course.DurationHours,
+		AccessDurationDays: course.AccessDurationDays,
+		TotalLessons:       course.TotalLessons,
+		Language:           course.Language,
+		Requirements:       course.Requirements,
+		WhatYouLearn:       course.WhatYouLearn,
+		Status:             course.Status,
+		IsFeatured:         course.IsFeatured,
+		RatingAvg:          course.RatingAvg,
+		RatingCount:        course.RatingCount,
+		EnrolledCount:      course.EnrolledCount,
+		Prerequisites:      toCoursePrerequisiteItems(prerequisites),
+		CreatedAt:          course.CreatedAt,
+		UpdatedAt:          course.UpdatedAt,
 	}, nil
 }
@@ -57,3 +57,65 @@ func (es *emailService) SendWelcomeEmail(email, fullName string) error {
 	fmt.Printf("====================\n")
 	return nil
 }
+
+func (es *emailService) SendCourseReviewResultEmail(email, fullName, courseTitle string, approved bool, comment string) error {
+	subject := fmt.Sprintf("Your course \"%s\" has been rejected", courseTitle)
+	result := "was not approved. Please review the comments below, update your course and submit it again."
+	if approved {
+		subject = fmt.Sprintf("Your course \"%s\" has been approved", courseTitle)
+		result = "has been approved and is now published."
+	}
+
+	body := fmt.Sprintf(`
+	Dear %s,
+
+	Your course "%s" %s
+`, fullName, courseTitle, result)
+
+	if comment != "" {
+		body += fmt.Sprintf(`
+	Reviewer comments:
+	%s
+`, comment)
+	}
+
+	fmt.Printf("=== COURSE REVIEW EMAIL ===\n")
+	fmt.Printf("To: %s\n", email)
+	fmt.Printf("Subject: %s\n", subject)
+	fmt.Printf("Body:\n%s\n", body)
+	fmt.Printf("===========================\n")
+
+	return nil
+}
+
+func (es *emailService) SendLearningReminderEmail(email, fullName string, courseTitles []string, inactiveDays int) error {
+	baseURL := utils.GetEnv("FRONTEND_URL", "http://localhost:3000")
+	subject := "Continue your learning"
+
+	courses := ""
+	for _, title := range courseTitles {
+		courses += fmt.Sprintf("	- %s\n", title)
+	}
+
+	body := fmt.Sprintf(`
+	Dear %s,
+
+	You haven't studied in the last %d days. Pick up where you left off:
+
+%s
+	%s/my-courses
+
+	You can turn off these reminders in your notification preferences.
+
+	Best regards,
+	LMS Team
+`, fullName, inactiveDays, courses, baseURL)
+
+	fmt.Printf("=== LEARNING REMINDER EMAIL ===\n")
+	fmt.Printf("To: %s\n", email)
+	fmt.Printf("Subject: %s\n", subject)
+	fmt.Printf("Body:\n%s\n", body)
+	fmt.Printf("===============================\n")
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"lms/src/models"
+	"lms/src/repository"
+	"time"
+)
+
+// Nguồn cấp quyền học (enrollment_source)
+const (
+	enrollmentSourcePurchase   = "purchase"
+	enrollmentSourceFree       = "free"
+	enrollmentSourceAdminGrant = "admin_grant"
+	enrollmentSourceInvite     = "invite" // Giảng viên tặng quyền học (complimentary)
+	enrollmentSourceOrgSeat    = "org_seat"
+)
+
+// canRenewEnrollment - enrollment đã drop/hết hạn/bị thu hồi được enroll lại, enrollment còn hạn có thời hạn được gia hạn.
+// Enrollment trọn đời còn hiệu lực thì không cần mua lại
+func canRenewEnrollment(enrollment *models.Enrollment) bool {
+	switch enrollment.Status {
+	case "dropped", "expired", "revoked":
+		return true
+	}
+	return enrollment.ExpiresAt != nil
+}
+
+// enrollmentExpiresAt - thời điểm hết hạn mới, gia hạn trước hạn thì cộng tiếp từ expires_at hiện tại. nil = trọn đời
+func enrollmentExpiresAt(current *time.Time, accessDurationDays int, now time.Time) *time.Time {
+	if accessDurationDays <= 0 {
+		return nil
+	}
+
+	start := now
+	if current != nil && current.After(now) {
+		start = *current
+	}
+	expiresAt := start.AddDate(0, 0, accessDurationDays)
+	return &expiresAt
+}
+
+// enrollmentGrant - thông tin cấp quyền học cho một user
+type enrollmentGrant struct {
+	UserId             uint
+	CourseId           uint
+	Source             string
+	AccessDurationDays int  // Cộng thêm vào thời hạn hiện tại, 0 = trọn đời
+	KeepExpiresAt      bool // Dùng đúng ExpiresAt thay vì tính từ AccessDurationDays (chuyển enrollment)
+	ExpiresAt          *time.Time
+	GrantedBy          *uint
+	TransferredFromId  *uint
+}
+
+// orderEnrollmentGrant - quyền học từ order đã thanh toán
+func orderEnrollmentGrant(order *models.Order) enrollmentGrant {
+	source := enrollmentSourcePurchase
+	if order.FinalPrice == 0 {
+		source = enrollmentSourceFree
+	}
+
+	return enrollmentGrant{
+		UserId:             order.UserId,
+		CourseId:           order.CourseId,
+		Source:             source,
+		AccessDurationDays: order.AccessDurationDays,
+	}
+}
+
+// hasEnrollmentAccess - enrollment đang học hoặc đã hoàn thành và chưa hết hạn
+func hasEnrollmentAccess(enrollment *models.Enrollment, now time.Time) bool {
+	return (enrollment.Status == "active" || enrollment.Status == "completed") &&
+		(enrollment.ExpiresAt == nil || enrollment.ExpiresAt.After(now))
+}
+
+// grantEnrollment cấp quyền học. Enrollment cũ (dropped, expired, revoked hoặc còn hạn) được kích hoạt lại thay vì
+// tạo mới nên progress, chứng chỉ và ngày enroll ban đầu (drip content) được giữ nguyên.
+// Enrollment còn quyền truy cập chỉ được gia hạn expires_at, nguồn cấp giữ nguyên để quyền đã mua không bị đổi
+// thành quyền được tặng (giảng viên thu hồi được); riêng order đã thanh toán đổi nguồn thành purchase
+func grantEnrollment(enrollmentRepo repository.EnrollmentRepository, grant enrollmentGrant, now time.Time) (*models.Enrollment, error) {
+	enrollment, exists := enrollmentRepo.FindEnrollment(grant.UserId, grant.CourseId)
+
+	var current *time.Time
+	if exists && (enrollment.Status == "active" || enrollment.Status == "completed" || enrollment.Status == "expired") {
+		current = enrollment.ExpiresAt // Drop/thu hồi là mất thời hạn còn lại
+	}
+	expiresAt := enrollmentExpiresAt(current, grant.AccessDurationDays, now)
+	if grant.KeepExpiresAt {
+		expiresAt = grant.ExpiresAt
+	}
+
+	if !exists {
+		enrollment = &models.Enrollment{
+			UserId:             grant.UserId,
+			CourseId:           grant.CourseId,
+			EnrolledAt:         now,
+			ProgressPercentage: 0,
+			Status:             "active",
+			ExpiresAt:          expiresAt,
+			EnrollmentSource:   grant.Source,
+			GrantedBy:          grant.GrantedBy,
+			TransferredFromId:  grant.TransferredFromId,
+		}
+		if err := enrollmentRepo.Create(enrollment); err != nil {
+			return nil, err
+		}
+		return enrollment, nil
+	}
+
+	// Hoàn thành trước đó thì giữ completed, còn lại học tiếp từ progress cũ
+	status := "active"
+	if enrollment.CompletedAt != nil && enrollment.ProgressPercentage >= 100 {
+		status = "completed"
+	}
+
+	if !hasEnrollmentAccess(enrollment, now) || grant.Source == enrollmentSourcePurchase {
+		enrollment.EnrollmentSource = grant.Source
+		enrollment.GrantedBy = grant.GrantedBy
+		enrollment.TransferredFromId = grant.TransferredFromId
+	}
+
+	enrollment.Status = status
+	enrollment.ExpiresAt = expiresAt
+	enrollment.DroppedAt = nil
+	enrollment.RevokedAt = nil
+	enrollment.RevokedBy = nil
+	enrollment.RevokeReason = ""
+
+	if err := enrollmentRepo.UpdateEnrollmentProgress(enrollment.Id, map[string]interface{}{
+		"status":              enrollment.Status,
+		"expires_at":          enrollment.ExpiresAt,
+		"dropped_at":          nil,
+		"enrollment_source":   enrollment.EnrollmentSource,
+		"granted_by":          enrollment.GrantedBy,
+		"transferred_from_id": enrollment.TransferredFromId,
+		"revoked_at":          nil,
+		"revoked_by":          nil,
+		"revoke_reason":       "",
+	}); err != nil {
+		return nil, err
+	}
+	return enrollment, nil
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"lms/src/models"
+	"testing"
+	"time"
+)
+
+// hasEnrollmentAccess phải khớp với accessibleEnrollmentCondition của repository
+func TestHasEnrollmentAccess(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Second)
+	future := now.Add(time.Second)
+
+	tests := []struct {
+		name      string
+		status    string
+		expiresAt *time.Time
+		want      bool
+	}{
+		{"active lifetime", "active", nil, true},
+		{"active not expired", "active", &future, true},
+		{"completed not expired", "completed", &future, true},
+		{"completed lifetime", "completed", nil, true},
+		{"active past expiry", "active", &past, false},
+		{"active expiring now", "active", &now, false},
+		{"expired status", "expired", &future, false},
+		{"dropped", "dropped", nil, false},
+		{"revoked", "revoked", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enrollment := &models.Enrollment{Status: tt.status, ExpiresAt: tt.expiresAt}
+			if got := hasEnrollmentAccess(enrollment, now); got != tt.want {
+				t.Errorf("hasEnrollmentAccess = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnrollmentExpiresAt(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	past := now.AddDate(0, 0, -5)
+	future := now.AddDate(0, 0, 5)
+
+	tests := []struct {
+		name    string
+		current *time.Time
+		days    int
+		want    *time.Time
+	}{
+		{"lifetime course", nil, 0, nil},
+		{"lifetime course drops current expiry", &future, 0, nil},
+		{"new enrollment", nil, 30, ptrTime(now.AddDate(0, 0, 30))},
+		{"renewal before expiry extends current", &future, 30, ptrTime(future.AddDate(0, 0, 30))},
+		{"renewal after expiry starts now", &past, 30, ptrTime(now.AddDate(0, 0, 30))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := enrollmentExpiresAt(tt.current, tt.days, now)
+			if (got == nil) != (tt.want == nil) || (got != nil && !got.Equal(*tt.want)) {
+				t.Errorf("enrollmentExpiresAt = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCanRenewEnrollment(t *testing.T) {
+	future := time.Now().AddDate(0, 0, 5)
+
+	tests := []struct {
+		name       string
+		enrollment models.Enrollment
+		want       bool
+	}{
+		{"active lifetime", models.Enrollment{Status: "active"}, false},
+		{"completed lifetime", models.Enrollment{Status: "completed"}, false},
+		{"active with expiry", models.Enrollment{Status: "active", ExpiresAt: &future}, true},
+		{"expired", models.Enrollment{Status: "expired"}, true},
+		{"dropped", models.Enrollment{Status: "dropped"}, true},
+		{"revoked", models.Enrollment{Status: "revoked"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := canRenewEnrollment(&tt.enrollment); got != tt.want {
+				t.Errorf("canRenewEnrollment = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGrantEnrollmentExpiry(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	enrolledAt := now.AddDate(0, -3, 0)
+	past := now.AddDate(0, 0, -5)
+	future := now.AddDate(0, 0, 5)
+	completedAt := now.AddDate(0, -1, 0)
+	transferExpiry := now.AddDate(0, 2, 0)
+
+	tests := []struct {
+		name           string
+		existing       *models.Enrollment
+		grant          enrollmentGrant
+		wantStatus     string
+		wantExpiresAt  *time.Time
+		wantEnrolledAt time.Time
+	}{
+		{"new lifetime enrollment", nil, enrollmentGrant{}, "active", nil, now},
+		{"new limited enrollment", nil, enrollmentGrant{AccessDurationDays: 30}, "active", ptrTime(now.AddDate(0, 0, 30)), now},
+		{
+			"renewal while active extends remaining time",
+			&models.Enrollment{Status: "active", ExpiresAt: &future},
+			enrollmentGrant{AccessDurationDays: 30}, "active", ptrTime(future.AddDate(0, 0, 30)), enrolledAt,
+		},
+		{
+			"re-enroll after expiry starts now",
+			&models.Enrollment{Status: "expired", ExpiresAt: &past},
+			enrollmentGrant{AccessDurationDays: 30}, "active", ptrTime(now.AddDate(0, 0, 30)), enrolledAt,
+		},
+		{
+			"dropped loses remaining time",
+			&models.Enrollment{Status: "dropped", ExpiresAt: &future},
+			enrollmentGrant{AccessDurationDays: 30}, "active", ptrTime(now.AddDate(0, 0, 30)), enrolledAt,
+		},
+		{
+			"revoked loses remaining time",
+			&models.Enrollment{Status: "revoked", ExpiresAt: &future},
+			enrollmentGrant{AccessDurationDays: 30}, "active", ptrTime(now.AddDate(0, 0, 30)), enrolledAt,
+		},
+		{
+			"completed course stays completed",
+			&models.Enrollment{Status: "expired", ExpiresAt: &past, CompletedAt: &completedAt, ProgressPercentage: 100},
+			enrollmentGrant{AccessDurationDays: 30}, "completed", ptrTime(now.AddDate(0, 0, 30)), enrolledAt,
+		},
+		{
+			"lifetime grant clears expiry",
+			&models.Enrollment{Status: "expired", ExpiresAt: &past},
+			enrollmentGrant{}, "active", nil, enrolledAt,
+		},
+		{
+			"transfer keeps given expiry",
+			nil,
+			enrollmentGrant{AccessDurationDays: 30, KeepExpiresAt: true, ExpiresAt: &transferExpiry}, "active", &transferExpiry, now,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeEnrollmentRepo()
+			if tt.existing != nil {
+				tt.existing.Id, tt.existing.UserId, tt.existing.CourseId, tt.existing.EnrolledAt = 1, 2, 3, enrolledAt
+				repo = newFakeEnrollmentRepo(*tt.existing)
+			}
+			tt.grant.UserId, tt.grant.CourseId, tt.grant.Source = 2, 3, enrollmentSourcePurchase
+
+			enrollment, err := grantEnrollment(repo, tt.grant, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			stored := repo.enrollments[enrollment.Id]
+			if enrollment.Status != tt.wantStatus || stored.Status != tt.wantStatus {
+				t.Errorf("status = %q (stored %q), want %q", enrollment.Status, stored.Status, tt.wantStatus)
+			}
+			for _, got := range []*time.Time{enrollment.ExpiresAt, stored.ExpiresAt} {
+				if (got == nil) != (tt.wantExpiresAt == nil) || (got != nil && !got.Equal(*tt.wantExpiresAt)) {
+					t.Errorf("expires_at = %v, want %v", got, tt.wantExpiresAt)
+				}
+			}
+			if !enrollment.EnrolledAt.Equal(tt.wantEnrolledAt) {
+				t.Errorf("enrolled_at = %v, want %v", enrollment.EnrolledAt, tt.wantEnrolledAt)
+			}
+			if !hasEnrollmentAccess(enrollment, now) {
+				t.Errorf("granted enrollment has no access: %+v", enrollment)
+			}
+		})
+	}
+}
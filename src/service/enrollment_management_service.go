@@ -0,0 +1,471 @@
+package service
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"lms/src/dto"
+	"lms/src/models"
+	"lms/src/repository"
+	"lms/src/utils"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+const (
+	bulkEnrollMaxRows     = 1000
+	bulkEnrollMaxFileSize = 1 << 20 // 1MB
+)
+
+type enrollmentManagementService struct {
+	enrollmentRepo repository.EnrollmentRepository
+	userRepo       repository.UserRepository
+	courseRepo     repository.CourseRepository
+	instructorRepo repository.InstructorRepository
+}
+
+func NewEnrollmentManagementService(
+	enrollmentRepo repository.EnrollmentRepository,
+	userRepo repository.UserRepository,
+	courseRepo repository.CourseRepository,
+	instructorRepo repository.InstructorRepository,
+) EnrollmentManagementService {
+	return &enrollmentManagementService{
+		enrollmentRepo: enrollmentRepo,
+		userRepo:       userRepo,
+		courseRepo:     courseRepo,
+		instructorRepo: instructorRepo,
+	}
+}
+
+// complimentaryQuota - số học viên mỗi giảng viên được tặng quyền học trên một course
+func complimentaryQuota() int {
+	return max(utils.GetEnvInt("INSTRUCTOR_COMPLIMENTARY_QUOTA", 10), 0)
+}
+
+func (ms *enrollmentManagementService) AdminEnrollUser(adminId uint, req *dto.AdminEnrollUserRequest) (*dto.ManagedEnrollmentItem, error) {
+	// 1. Kiểm tra course
+	course, err := ms.courseRepo.FindById(req.CourseId)
+	if err != nil {
+		return nil, utils.NewError("Course not found", utils.ErrCodeNotFound)
+	}
+
+	// 2. Tìm user theo user_id hoặc email
+	user, err := ms.resolveUser(req.UserId, req.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Cấp quyền học
+	enrollment, _, err := grantManualAccess(ms.enrollmentRepo, user, course, manualSource(req.Source), accessDurationDays(req.AccessDurationDays, course), adminId)
+	if err != nil {
+		return nil, err
+	}
+
+	item := managedEnrollmentItem(enrollment, user, course.Title)
+	return &item, nil
+}
+
+// BulkEnrollUsers cấp quyền học cho danh sách email trong file CSV (cột "email" hoặc cột đầu tiên).
+// Từng dòng được xử lý độc lập, dòng lỗi không làm hỏng cả file
+func (ms *enrollmentManagementService) BulkEnrollUsers(adminId, courseId uint, req *dto.BulkEnrollRequest, file *multipart.FileHeader) (*dto.BulkEnrollResponse, error) {
+	// 1. Kiểm tra course
+	course, err := ms.courseRepo.FindById(courseId)
+	if err != nil {
+		return nil, utils.NewError("Course not found", utils.ErrCodeNotFound)
+	}
+
+	// 2. Đọc file CSV
+	if file.Size > bulkEnrollMaxFileSize {
+		return nil, utils.NewError(fmt.Sprintf("CSV file must not exceed %d bytes", bulkEnrollMaxFileSize), utils.ErrCodeBadRequest)
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to read CSV file", utils.ErrCodeBadRequest)
+	}
+	defer src.Close()
+
+	reader := csv.NewReader(src)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, utils.WrapError(err, "Invalid CSV file", utils.ErrCodeBadRequest)
+	}
+
+	// Dòng đầu có cột "email" thì coi là header
+	emailColumn, start := 0, 0
+	if len(records) > 0 {
+		for i, cell := range records[0] {
+			if strings.EqualFold(strings.TrimSpace(strings.TrimPrefix(cell, "\ufeff")), "email") {
+				emailColumn, start = i, 1
+				break
+			}
+		}
+	}
+	if len(records)-start > bulkEnrollMaxRows {
+		return nil, utils.NewError(fmt.Sprintf("CSV file must not contain more than %d rows", bulkEnrollMaxRows), utils.ErrCodeBadRequest)
+	}
+
+	// 3. Cấp quyền học từng dòng
+	source := manualSource(req.Source)
+	days := accessDurationDays(req.AccessDurationDays, course)
+	response := &dto.BulkEnrollResponse{
+		CourseId: courseId,
+		Results:  []dto.BulkEnrollResult{},
+	}
+	seen := make(map[string]bool)
+
+	for i := start; i < len(records); i++ {
+		if emailColumn >= len(records[i]) {
+			continue
+		}
+		email := strings.TrimSpace(strings.TrimPrefix(records[i][emailColumn], "\ufeff"))
+		if email == "" {
+			continue
+		}
+
+		result := dto.BulkEnrollResult{Row: i + 1, Email: email}
+		switch {
+		case seen[strings.ToLower(email)]:
+			result.Status, result.Message = "skipped", "Duplicate email in file"
+		case !isValidEmail(email):
+			result.Status, result.Message = "failed", "Invalid email format"
+		default:
+			result.Status, result.Message, result.EnrollmentId = ms.bulkEnrollRow(email, course, source, days, adminId)
+		}
+		seen[strings.ToLower(email)] = true
+
+		switch result.Status {
+		case "enrolled":
+			response.Enrolled++
+		case "renewed":
+			response.Renewed++
+		case "skipped":
+			response.Skipped++
+		default:
+			response.Failed++
+		}
+		response.Results = append(response.Results, result)
+	}
+	response.Total = len(response.Results)
+
+	return response, nil
+}
+
+// bulkEnrollRow trả về status, message và enrollment id của một dòng CSV
+func (ms *enrollmentManagementService) bulkEnrollRow(email string, course *models.Course, source string, days int, adminId uint) (string, string, uint) {
+	user, exists := ms.userRepo.FindByEmail(email)
+	if !exists {
+		return "failed", "User not found", 0
+	}
+
+	enrollment, renewed, err := grantManualAccess(ms.enrollmentRepo, user, course, source, days, adminId)
+	if err != nil {
+		var appErr *utils.AppError
+		if errors.As(err, &appErr) && appErr.Code == utils.ErrCodeConflict {
+			return "skipped", err.Error(), 0
+		}
+		return "failed", err.Error(), 0
+	}
+
+	if renewed {
+		return "renewed", "", enrollment.Id
+	}
+	return "enrolled", "", enrollment.Id
+}
+
+// RevokeEnrollment thu hồi quyền học. Không hoàn tiền order, progress được giữ lại nếu user được cấp lại
+func (ms *enrollmentManagementService) RevokeEnrollment(adminId, enrollmentId uint, req *dto.RevokeEnrollmentRequest) (*dto.ManagedEnrollmentItem, error) {
+	enrollment, err := ms.enrollmentRepo.FindById(enrollmentId)
+	if err != nil {
+		return nil, utils.NewError("Enrollment not found", utils.ErrCodeNotFound)
+	}
+
+	if err := revokeEnrollment(ms.enrollmentRepo, enrollment, adminId, req.Reason, time.Now()); err != nil {
+		return nil, err
+	}
+
+	item := managedEnrollmentItem(enrollment, &enrollment.User, enrollment.Course.Title)
+	return &item, nil
+}
+
+// TransferEnrollment chuyển quyền học (cùng nguồn và thời hạn còn lại) sang user khác.
+// Progress thuộc về người học nên không được chuyển theo
+func (ms *enrollmentManagementService) TransferEnrollment(adminId, enrollmentId uint, req *dto.TransferEnrollmentRequest) (*dto.TransferEnrollmentResponse, error) {
+	now := time.Now()
+
+	// 1. Kiểm tra user nhận
+	toUser, err := ms.resolveUser(req.ToUserId, req.ToEmail)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Cấp quyền cho user nhận và thu hồi enrollment nguồn trong cùng transaction: lỗi ở bước nào cũng không
+	// để hai người cùng có quyền hoặc cùng mất quyền. Enrollment nguồn được khóa để hai lần chuyển song song không cùng chạy
+	var from, to *models.Enrollment
+	err = ms.enrollmentRepo.Transaction(func(txRepo repository.EnrollmentRepository) error {
+		var err error
+		from, err = txRepo.LockById(enrollmentId)
+		if err != nil {
+			return utils.NewError("Enrollment not found", utils.ErrCodeNotFound)
+		}
+		if !hasEnrollmentAccess(from, now) {
+			return utils.NewError("Only enrollments with active access can be transferred", utils.ErrCodeBadRequest)
+		}
+		if toUser.Id == from.UserId {
+			return utils.NewError("Cannot transfer an enrollment to the same user", utils.ErrCodeBadRequest)
+		}
+		if _, exists := txRepo.CheckEnrollment(toUser.Id, from.CourseId); exists {
+			return utils.NewError("Target user already has access to this course", utils.ErrCodeConflict)
+		}
+
+		to, err = grantEnrollment(txRepo, enrollmentGrant{
+			UserId:            toUser.Id,
+			CourseId:          from.CourseId,
+			Source:            from.EnrollmentSource,
+			KeepExpiresAt:     true,
+			ExpiresAt:         from.ExpiresAt,
+			GrantedBy:         &adminId,
+			TransferredFromId: &from.Id,
+		}, now)
+		if err != nil {
+			return utils.WrapError(err, "Failed to create enrollment", utils.ErrCodeInternal)
+		}
+
+		reason := fmt.Sprintf("Transferred to user #%d: %s", toUser.Id, req.Reason)
+		return revokeEnrollment(txRepo, from, adminId, reason, now)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.TransferEnrollmentResponse{
+		Message: "Enrollment transferred successfully",
+		From:    managedEnrollmentItem(from, &from.User, from.Course.Title),
+		To:      managedEnrollmentItem(to, toUser, from.Course.Title),
+	}, nil
+}
+
+func (ms *enrollmentManagementService) GetComplimentaryAccess(instructorId, courseId uint) (*dto.ComplimentaryAccessResponse, error) {
+	// 1. Kiểm tra quyền sở hữu course
+	course, err := ms.instructorRepo.FindCourseByIdAndInstructor(courseId, instructorId)
+	if err != nil {
+		return nil, utils.NewError("Course not found or you don't have permission", utils.ErrCodeNotFound)
+	}
+
+	// 2. Danh sách học viên đã được tặng
+	grants, err := ms.enrollmentRepo.GetGrants(courseId, instructorId, enrollmentSourceInvite)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get complimentary access", utils.ErrCodeInternal)
+	}
+
+	used := 0
+	items := make([]dto.ManagedEnrollmentItem, len(grants))
+	for i := range grants {
+		if grants[i].Status != "revoked" {
+			used++
+		}
+		items[i] = managedEnrollmentItem(&grants[i], &grants[i].User, course.Title)
+	}
+
+	quota := complimentaryQuota()
+	return &dto.ComplimentaryAccessResponse{
+		CourseId:  courseId,
+		Quota:     quota,
+		Used:      used,
+		Remaining: max(quota-used, 0),
+		Grants:    items,
+	}, nil
+}
+
+func (ms *enrollmentManagementService) GrantComplimentaryAccess(instructorId, courseId uint, req *dto.GrantComplimentaryAccessRequest) (*dto.ManagedEnrollmentItem, error) {
+	// 1. Kiểm tra quyền sở hữu course
+	course, err := ms.instructorRepo.FindCourseByIdAndInstructor(courseId, instructorId)
+	if err != nil {
+		return nil, utils.NewError("Course not found or you don't have permission", utils.ErrCodeNotFound)
+	}
+
+	// 2. Kiểm tra học viên
+	user, exists := ms.userRepo.FindByEmail(strings.TrimSpace(req.Email))
+	if !exists {
+		return nil, utils.NewError("User not found", utils.ErrCodeNotFound)
+	}
+	if user.Id == instructorId {
+		return nil, utils.NewError("You cannot grant access to yourself", utils.ErrCodeBadRequest)
+	}
+
+	// 3. Kiểm tra quota và cấp quyền học theo thời hạn của course trong cùng transaction, khóa course để các lần tặng
+	// song song không cùng vượt quota
+	var enrollment *models.Enrollment
+	err = ms.enrollmentRepo.Transaction(func(txRepo repository.EnrollmentRepository) error {
+		if err := txRepo.LockCourse(courseId); err != nil {
+			return utils.WrapError(err, "Failed to check complimentary quota", utils.ErrCodeInternal)
+		}
+
+		// Học viên đang có quyền học từ nguồn khác (đã mua, admin cấp...) thì không tặng, tránh đổi quyền đó thành quyền tặng
+		existing, exists := txRepo.FindEnrollment(user.Id, courseId)
+		isGrant := exists && existing.Status != "revoked" && existing.EnrollmentSource == enrollmentSourceInvite &&
+			existing.GrantedBy != nil && *existing.GrantedBy == instructorId
+		if exists && hasEnrollmentAccess(existing, time.Now()) && !isGrant {
+			return utils.NewError("User already has access to this course", utils.ErrCodeConflict)
+		}
+
+		// Enrollment bị thu hồi không tính, gia hạn quyền đã tặng không tốn thêm quota
+		if !isGrant {
+			quota := complimentaryQuota()
+			used, err := txRepo.CountGrants(courseId, instructorId, enrollmentSourceInvite)
+			if err != nil {
+				return utils.WrapError(err, "Failed to check complimentary quota", utils.ErrCodeInternal)
+			}
+			if int(used) >= quota {
+				return utils.NewError(fmt.Sprintf("Complimentary access quota reached (%d/%d)", used, quota), utils.ErrCodeForbidden)
+			}
+		}
+
+		var err error
+		enrollment, _, err = grantManualAccess(txRepo, user, course, enrollmentSourceInvite, course.AccessDurationDays, instructorId)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	item := managedEnrollmentItem(enrollment, user, course.Title)
+	return &item, nil
+}
+
+// RevokeComplimentaryAccess - giảng viên thu hồi quyền học đã tặng, quota được trả lại
+func (ms *enrollmentManagementService) RevokeComplimentaryAccess(instructorId, courseId, enrollmentId uint, req *dto.RevokeComplimentaryAccessRequest) (*dto.ManagedEnrollmentItem, error) {
+	// 1. Kiểm tra quyền sở hữu course
+	course, err := ms.instructorRepo.FindCourseByIdAndInstructor(courseId, instructorId)
+	if err != nil {
+		return nil, utils.NewError("Course not found or you don't have permission", utils.ErrCodeNotFound)
+	}
+
+	// 2. Chỉ thu hồi được enrollment do chính giảng viên tặng
+	enrollment, err := ms.enrollmentRepo.FindById(enrollmentId)
+	if err != nil || enrollment.CourseId != courseId || enrollment.EnrollmentSource != enrollmentSourceInvite ||
+		enrollment.GrantedBy == nil || *enrollment.GrantedBy != instructorId {
+		return nil, utils.NewError("Complimentary access not found", utils.ErrCodeNotFound)
+	}
+
+	reason := req.Reason
+	if reason == "" {
+		reason = "Revoked by instructor"
+	}
+	if err := revokeEnrollment(ms.enrollmentRepo, enrollment, instructorId, reason, time.Now()); err != nil {
+		return nil, err
+	}
+
+	item := managedEnrollmentItem(enrollment, &enrollment.User, course.Title)
+	return &item, nil
+}
+
+// grantManualAccess cấp quyền học thủ công, trả về true nếu kích hoạt lại/gia hạn enrollment cũ.
+// User đang có quyền học trọn đời thì trả về lỗi conflict
+func grantManualAccess(enrollmentRepo repository.EnrollmentRepository, user *models.User, course *models.Course, source string, days int, grantedBy uint) (*models.Enrollment, bool, error) {
+	existing, exists := enrollmentRepo.FindEnrollment(user.Id, course.Id)
+	if exists && !canRenewEnrollment(existing) {
+		return nil, false, utils.NewError("User already has lifetime access to this course", utils.ErrCodeConflict)
+	}
+
+	enrollment, err := grantEnrollment(enrollmentRepo, enrollmentGrant{
+		UserId:             user.Id,
+		CourseId:           course.Id,
+		Source:             source,
+		AccessDurationDays: days,
+		GrantedBy:          &grantedBy,
+	}, time.Now())
+	if err != nil {
+		return nil, false, utils.WrapError(err, "Failed to create enrollment", utils.ErrCodeInternal)
+	}
+
+	return enrollment, exists, nil
+}
+
+// revokeEnrollment thu hồi quyền học. Chứng chỉ của enrollment hết hiệu lực theo (xác thực trả về invalid,
+// không còn trong danh sách và không tải được) cho tới khi học viên được cấp lại quyền
+func revokeEnrollment(enrollmentRepo repository.EnrollmentRepository, enrollment *models.Enrollment, revokedBy uint, reason string, now time.Time) error {
+	if enrollment.Status == "revoked" {
+		return utils.NewError("Enrollment is already revoked", utils.ErrCodeBadRequest)
+	}
+
+	if err := enrollmentRepo.UpdateEnrollmentProgress(enrollment.Id, map[string]interface{}{
+		"status":        "revoked",
+		"revoked_at":    now,
+		"revoked_by":    revokedBy,
+		"revoke_reason": reason,
+	}); err != nil {
+		return utils.WrapError(err, "Failed to revoke enrollment", utils.ErrCodeInternal)
+	}
+
+	enrollment.Status = "revoked"
+	enrollment.RevokedAt = &now
+	enrollment.RevokedBy = &revokedBy
+	enrollment.RevokeReason = reason
+	return nil
+}
+
+func (ms *enrollmentManagementService) resolveUser(userId uint, email string) (*models.User, error) {
+	if userId != 0 {
+		user, err := ms.userRepo.FindById(userId)
+		if err != nil {
+			return nil, utils.NewError("User not found", utils.ErrCodeNotFound)
+		}
+		return user, nil
+	}
+
+	if email != "" {
+		user, exists := ms.userRepo.FindByEmail(strings.TrimSpace(email))
+		if !exists {
+			return nil, utils.NewError("User not found", utils.ErrCodeNotFound)
+		}
+		return user, nil
+	}
+
+	return nil, utils.NewError("Either user id or email is required", utils.ErrCodeBadRequest)
+}
+
+// manualSource - nguồn cấp quyền của admin, mặc định admin_grant
+func manualSource(source string) string {
+	if source == "" {
+		return enrollmentSourceAdminGrant
+	}
+	return source
+}
+
+// accessDurationDays - thời hạn admin chỉ định, không có thì lấy theo course
+func accessDurationDays(days *int, course *models.Course) int {
+	if days != nil {
+		return *days
+	}
+	return course.AccessDurationDays
+}
+
+func isValidEmail(email string) bool {
+	address, err := mail.ParseAddress(email)
+	return err == nil && address.Address == email
+}
+
+func managedEnrollmentItem(enrollment *models.Enrollment, user *models.User, courseTitle string) dto.ManagedEnrollmentItem {
+	return dto.ManagedEnrollmentItem{
+		Id:                enrollment.Id,
+		UserId:            enrollment.UserId,
+		UserEmail:         user.Email,
+		UserFullName:      user.FullName,
+		CourseId:          enrollment.CourseId,
+		CourseTitle:       courseTitle,
+		Status:            enrollment.Status,
+		EnrollmentSource:  enrollment.EnrollmentSource,
+		EnrolledAt:        enrollment.EnrolledAt,
+		ExpiresAt:         enrollment.ExpiresAt,
+		GrantedBy:         enrollment.GrantedBy,
+		TransferredFromId: enrollment.TransferredFromId,
+		RevokedAt:         enrollment.RevokedAt,
+		RevokeReason:      enrollment.RevokeReason,
+	}
+}
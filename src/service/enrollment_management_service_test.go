@@ -0,0 +1,305 @@
+package service
+
+import (
+	"errors"
+	"lms/src/dto"
+	"lms/src/models"
+	"lms/src/repository"
+	"lms/src/utils"
+	"testing"
+	"time"
+)
+
+// fakeEnrollmentRepo lưu enrollment trong bộ nhớ, Transaction khôi phục dữ liệu khi fn trả lỗi
+type fakeEnrollmentRepo struct {
+	repository.EnrollmentRepository
+	enrollments map[uint]*models.Enrollment
+	nextId      uint
+	failUpdate  uint // Enrollment id làm UpdateEnrollmentProgress trả lỗi
+}
+
+func newFakeEnrollmentRepo(enrollments ...models.Enrollment) *fakeEnrollmentRepo {
+	repo := &fakeEnrollmentRepo{enrollments: make(map[uint]*models.Enrollment), nextId: 100}
+	for i := range enrollments {
+		e := enrollments[i]
+		repo.enrollments[e.Id] = &e
+	}
+	return repo
+}
+
+func (r *fakeEnrollmentRepo) Create(enrollment *models.Enrollment) error {
+	r.nextId++
+	enrollment.Id = r.nextId
+	e := *enrollment
+	r.enrollments[e.Id] = &e
+	return nil
+}
+
+func (r *fakeEnrollmentRepo) FindEnrollment(userId, courseId uint) (*models.Enrollment, bool) {
+	var found *models.Enrollment
+	for _, e := range r.enrollments {
+		if e.UserId == userId && e.CourseId == courseId && (found == nil || e.Id > found.Id) {
+			found = e
+		}
+	}
+	if found == nil {
+		return nil, false
+	}
+	e := *found
+	return &e, true
+}
+
+func (r *fakeEnrollmentRepo) CheckEnrollment(userId, courseId uint) (*models.Enrollment, bool) {
+	e, exists := r.FindEnrollment(userId, courseId)
+	if !exists || !hasEnrollmentAccess(e, time.Now()) {
+		return nil, false
+	}
+	return e, true
+}
+
+func (r *fakeEnrollmentRepo) FindById(enrollmentId uint) (*models.Enrollment, error) {
+	e, ok := r.enrollments[enrollmentId]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	copied := *e
+	return &copied, nil
+}
+
+func (r *fakeEnrollmentRepo) LockById(enrollmentId uint) (*models.Enrollment, error) {
+	return r.FindById(enrollmentId)
+}
+
+func (r *fakeEnrollmentRepo) LockCourse(courseId uint) error {
+	return nil
+}
+
+func (r *fakeEnrollmentRepo) UpdateEnrollmentProgress(enrollmentId uint, updates map[string]interface{}) error {
+	if enrollmentId == r.failUpdate {
+		return errors.New("update failed")
+	}
+	e := r.enrollments[enrollmentId]
+	for key, value := range updates {
+		switch key {
+		case "status":
+			e.Status = value.(string)
+		case "expires_at":
+			e.ExpiresAt = value.(*time.Time)
+		case "enrollment_source":
+			e.EnrollmentSource = value.(string)
+		case "granted_by":
+			e.GrantedBy = value.(*uint)
+		}
+	}
+	return nil
+}
+
+func (r *fakeEnrollmentRepo) CountGrants(courseId, grantedBy uint, source string) (int64, error) {
+	var count int64
+	for _, e := range r.enrollments {
+		if e.CourseId == courseId && e.GrantedBy != nil && *e.GrantedBy == grantedBy && e.EnrollmentSource == source && e.Status != "revoked" {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *fakeEnrollmentRepo) Transaction(fn func(txRepo repository.EnrollmentRepository) error) error {
+	snapshot := make(map[uint]models.Enrollment, len(r.enrollments))
+	for id, e := range r.enrollments {
+		snapshot[id] = *e
+	}
+
+	err := fn(r)
+	if err != nil {
+		r.enrollments = make(map[uint]*models.Enrollment, len(snapshot))
+		for id := range snapshot {
+			e := snapshot[id]
+			r.enrollments[id] = &e
+		}
+	}
+	return err
+}
+
+type fakeEnrollmentUserRepo struct {
+	repository.UserRepository
+	users map[string]*models.User
+}
+
+func (r *fakeEnrollmentUserRepo) FindByEmail(email string) (*models.User, bool) {
+	user, ok := r.users[email]
+	return user, ok
+}
+
+func (r *fakeEnrollmentUserRepo) FindById(id uint) (*models.User, error) {
+	for _, user := range r.users {
+		if user.Id == id {
+			return user, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+type fakeEnrollmentInstructorRepo struct {
+	repository.InstructorRepository
+	course *models.Course
+}
+
+func (r *fakeEnrollmentInstructorRepo) FindCourseByIdAndInstructor(courseId, instructorId uint) (*models.Course, error) {
+	if r.course.Id != courseId || r.course.InstructorId != instructorId {
+		return nil, errors.New("record not found")
+	}
+	return r.course, nil
+}
+
+func errorCode(err error) utils.ErrorCode {
+	var appErr *utils.AppError
+	if errors.As(err, &appErr) {
+		return appErr.Code
+	}
+	return ""
+}
+
+func TestGrantEnrollmentKeepsSourceOnRenewal(t *testing.T) {
+	now := time.Now()
+	future := now.AddDate(0, 0, 10)
+	past := now.AddDate(0, 0, -1)
+	instructorId := uint(9)
+
+	tests := []struct {
+		name       string
+		existing   models.Enrollment
+		source     string
+		wantSource string
+	}{
+		{"admin renews purchase", models.Enrollment{Status: "active", EnrollmentSource: enrollmentSourcePurchase, ExpiresAt: &future}, enrollmentSourceAdminGrant, enrollmentSourcePurchase},
+		{"invite renews purchase", models.Enrollment{Status: "completed", EnrollmentSource: enrollmentSourcePurchase, ExpiresAt: &future}, enrollmentSourceInvite, enrollmentSourcePurchase},
+		{"purchase renews invite", models.Enrollment{Status: "active", EnrollmentSource: enrollmentSourceInvite, GrantedBy: &instructorId, ExpiresAt: &future}, enrollmentSourcePurchase, enrollmentSourcePurchase},
+		{"invite after expiry", models.Enrollment{Status: "expired", EnrollmentSource: enrollmentSourcePurchase, ExpiresAt: &past}, enrollmentSourceInvite, enrollmentSourceInvite},
+		{"invite after revoke", models.Enrollment{Status: "revoked", EnrollmentSource: enrollmentSourcePurchase}, enrollmentSourceInvite, enrollmentSourceInvite},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.existing.Id, tt.existing.UserId, tt.existing.CourseId = 1, 2, 3
+			repo := newFakeEnrollmentRepo(tt.existing)
+
+			var grantedBy *uint
+			if tt.source != enrollmentSourcePurchase {
+				grantedBy = &instructorId
+			}
+			enrollment, err := grantEnrollment(repo, enrollmentGrant{UserId: 2, CourseId: 3, Source: tt.source, AccessDurationDays: 30, GrantedBy: grantedBy}, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if enrollment.EnrollmentSource != tt.wantSource || repo.enrollments[1].EnrollmentSource != tt.wantSource {
+				t.Errorf("source %q (stored %q), want %q", enrollment.EnrollmentSource, repo.enrollments[1].EnrollmentSource, tt.wantSource)
+			}
+			if enrollment.ExpiresAt == nil || !enrollment.ExpiresAt.After(now.AddDate(0, 0, 29)) {
+				t.Errorf("expires_at %v was not extended", enrollment.ExpiresAt)
+			}
+		})
+	}
+}
+
+func TestGrantComplimentaryAccess(t *testing.T) {
+	t.Setenv("INSTRUCTOR_COMPLIMENTARY_QUOTA", "1")
+
+	instructorId := uint(9)
+	future := time.Now().AddDate(0, 0, 10)
+	course := &models.Course{Id: 3, InstructorId: instructorId, AccessDurationDays: 30}
+	users := &fakeEnrollmentUserRepo{users: map[string]*models.User{
+		"buyer@example.com":   {Id: 2, Email: "buyer@example.com"},
+		"invited@example.com": {Id: 4, Email: "invited@example.com"},
+		"new@example.com":     {Id: 5, Email: "new@example.com"},
+	}}
+	existing := []models.Enrollment{
+		{Id: 1, UserId: 2, CourseId: 3, Status: "active", EnrollmentSource: enrollmentSourcePurchase, ExpiresAt: &future},
+		{Id: 2, UserId: 4, CourseId: 3, Status: "active", EnrollmentSource: enrollmentSourceInvite, GrantedBy: &instructorId, ExpiresAt: &future},
+	}
+
+	tests := []struct {
+		name     string
+		email    string
+		wantCode utils.ErrorCode
+	}{
+		{"learner who bought the course", "buyer@example.com", utils.ErrCodeConflict},
+		{"quota reached", "new@example.com", utils.ErrCodeForbidden},
+		{"renew own grant at quota", "invited@example.com", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeEnrollmentRepo(existing...)
+			ms := NewEnrollmentManagementService(repo, users, nil, &fakeEnrollmentInstructorRepo{course: course})
+
+			item, err := ms.GrantComplimentaryAccess(instructorId, 3, &dto.GrantComplimentaryAccessRequest{Email: tt.email})
+			if errorCode(err) != tt.wantCode {
+				t.Fatalf("error %v, want code %q", err, tt.wantCode)
+			}
+			if err != nil {
+				if repo.enrollments[1].EnrollmentSource != enrollmentSourcePurchase || len(repo.enrollments) != len(existing) {
+					t.Error("failed grant must not change enrollments")
+				}
+				return
+			}
+			if item.EnrollmentSource != enrollmentSourceInvite || !item.ExpiresAt.After(future) {
+				t.Errorf("got %+v, want renewed invite", item)
+			}
+		})
+	}
+}
+
+func TestTransferEnrollmentIsAtomic(t *testing.T) {
+	adminId := uint(1)
+	users := &fakeEnrollmentUserRepo{users: map[string]*models.User{
+		"from@example.com": {Id: 2, Email: "from@example.com"},
+		"to@example.com":   {Id: 5, Email: "to@example.com"},
+	}}
+	source := models.Enrollment{Id: 1, UserId: 2, CourseId: 3, Status: "completed", EnrollmentSource: enrollmentSourcePurchase}
+	req := &dto.TransferEnrollmentRequest{ToEmail: "to@example.com", Reason: "Account merge"}
+
+	t.Run("grant and revoke together", func(t *testing.T) {
+		repo := newFakeEnrollmentRepo(source)
+		ms := NewEnrollmentManagementService(repo, users, nil, nil)
+
+		response, err := ms.TransferEnrollment(adminId, 1, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if repo.enrollments[1].Status != "revoked" {
+			t.Errorf("source status %q, want revoked", repo.enrollments[1].Status)
+		}
+		to := repo.enrollments[response.To.Id]
+		if to == nil || to.UserId != 5 || to.EnrollmentSource != enrollmentSourcePurchase || *to.TransferredFromId != 1 {
+			t.Errorf("target enrollment %+v", to)
+		}
+	})
+
+	t.Run("failed revoke rolls back the grant", func(t *testing.T) {
+		repo := newFakeEnrollmentRepo(source)
+		repo.failUpdate = 1
+		ms := NewEnrollmentManagementService(repo, users, nil, nil)
+
+		if _, err := ms.TransferEnrollment(adminId, 1, req); err == nil {
+			t.Fatal("expected error")
+		}
+		if _, exists := repo.FindEnrollment(5, 3); exists {
+			t.Error("target enrollment must be rolled back")
+		}
+		if repo.enrollments[1].Status != "completed" {
+			t.Errorf("source status %q, want completed", repo.enrollments[1].Status)
+		}
+	})
+
+	t.Run("source without access", func(t *testing.T) {
+		revoked := source
+		revoked.Status = "revoked"
+		ms := NewEnrollmentManagementService(newFakeEnrollmentRepo(revoked), users, nil, nil)
+
+		if _, err := ms.TransferEnrollment(adminId, 1, req); errorCode(err) != utils.ErrCodeBadRequest {
+			t.Errorf("error %v, want bad request", err)
+		}
+	})
+}
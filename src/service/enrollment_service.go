@@ -1,11 +1,13 @@
 package service
 
 import (
+	"context"
 	"fmt"
 	"lms/src/dto"
 	"lms/src/models"
 	"lms/src/repository"
 	"lms/src/utils"
+	"log"
 	"math"
 	"time"
 
@@ -13,11 +15,12 @@ import (
 )
 
 type enrollmentService struct {
-	enrollmentRepo repository.EnrollmentRepository
-	orderRepo      repository.OrderRepository
-	courseRepo     repository.CourseRepository
-	couponRepo     repository.CouponRepository
-	progressRepo   repository.ProgressRepository // Thêm để đếm completed lessons
+	enrollmentRepo   repository.EnrollmentRepository
+	orderRepo        repository.OrderRepository
+	courseRepo       repository.CourseRepository
+	couponRepo       repository.CouponRepository
+	progressRepo     repository.ProgressRepository // Thêm để đếm completed lessons
+	prerequisiteRepo repository.CoursePrerequisiteRepository
 }
 
 func NewEnrollmentService(
@@ -26,13 +29,15 @@ func NewEnrollmentService(
 	courseRepo repository.CourseRepository,
 	couponRepo repository.CouponRepository,
 	progressRepo repository.ProgressRepository,
+	prerequisiteRepo repository.CoursePrerequisiteRepository,
 ) EnrollmentService {
 	return &enrollmentService{
-		enrollmentRepo: enrollmentRepo,
-		orderRepo:      orderRepo,
-		courseRepo:     courseRepo,
-		couponRepo:     couponRepo,
-		progressRepo:   progressRepo,
+		enrollmentRepo:   enrollmentRepo,
+		orderRepo:        orderRepo,
+		courseRepo:       courseRepo,
+		couponRepo:       couponRepo,
+		progressRepo:     progressRepo,
+		prerequisiteRepo: prerequisiteRepo,
 	}
 }
 
@@ -48,11 +53,14 @@ func (es *enrollmentService) EnrollCourse(userId, courseId uint, req *dto.Enroll
 		return nil, utils.NewError("Course is not available for enrollment", utils.ErrCodeBadRequest)
 	}
 
-	// 3. Kiểm tra user đã enroll chưa
-	if existingEnrollment, exists := es.enrollmentRepo.CheckEnrollment(userId, courseId); exists {
-		if existingEnrollment.Status == "active" {
-			return nil, utils.NewError("You are already enrolled in this course", utils.ErrCodeConflict)
-		}
+	// 3. Kiểm tra user đã enroll chưa (enrollment đã drop/hết hạn hoặc có thời hạn thì được enroll lại để gia hạn)
+	if existingEnrollment, exists := es.enrollmentRepo.FindEnrollment(userId, courseId); exists && !canRenewEnrollment(existingEnrollment) {
+		return nil, utils.NewError("You are already enrolled in this course", utils.ErrCodeConflict)
+	}
+
+	// Kiểm tra user đã hoàn thành các course prerequisites chưa
+	if err := checkCoursePrerequisites(es.prerequisiteRepo, userId, courseId); err != nil {
+		return nil, err
 	}
 
 	// 4. Tính toán giá
@@ -109,15 +117,16 @@ func (es *enrollmentService) EnrollCourse(userId, courseId uint, req *dto.Enroll
 
 	// 8. Tạo order
 	order := &models.Order{
-		UserId:         userId,
-		CourseId:       courseId,
-		OrderCode:      orderCode,
-		OriginalPrice:  originalPrice,
-		DiscountAmount: discountAmount,
-		FinalPrice:     finalPrice,
-		CouponId:       couponId,
-		PaymentMethod:  req.PaymentMethod,
-		PaymentStatus:  "pending",
+		UserId:             userId,
+		CourseId:           courseId,
+		OrderCode:          orderCode,
+		OriginalPrice:      originalPrice,
+		DiscountAmount:     discountAmount,
+		FinalPrice:         finalPrice,
+		CouponId:           couponId,
+		PaymentMethod:      req.PaymentMethod,
+		PaymentStatus:      "pending",
+		AccessDurationDays: course.AccessDurationDays,
 	}
 
 	if err := es.orderRepo.Create(order); err != nil {
@@ -135,17 +144,28 @@ func (es *enrollmentService) EnrollCourse(userId, courseId uint, req *dto.Enroll
 		}
 	}
 
-	// 10. Tạo enrollment
-	enrollment := &models.Enrollment{
-		UserId:             userId,
-		CourseId:           courseId,
-		EnrolledAt:         time.Now(),
-		ProgressPercentage: 0,
-		Status:             "active",
+	// 10. Tạo enrollment (hoặc kích hoạt lại/gia hạn enrollment cũ, giữ progress).
+	// Course có phí: enrollment được cấp khi thanh toán xong để gia hạn không có hiệu lực trước khi trả tiền
+	response := &dto.EnrollCourseResponse{
+		OrderId:        order.Id,
+		OrderCode:      order.OrderCode,
+		CourseId:       course.Id,
+		CourseTitle:    course.Title,
+		OriginalPrice:  originalPrice,
+		DiscountAmount: discountAmount,
+		FinalPrice:     finalPrice,
+		PaymentMethod:  order.PaymentMethod,
+		PaymentStatus:  order.PaymentStatus,
+		Message:        getEnrollmentMessage(finalPrice, order.PaymentStatus),
 	}
-
-	if err := es.enrollmentRepo.Create(enrollment); err != nil {
-		return nil, utils.WrapError(err, "Failed to create enrollment", utils.ErrCodeInternal)
+	if order.PaymentStatus == "paid" {
+		enrollment, err := grantEnrollment(es.enrollmentRepo, orderEnrollmentGrant(order), time.Now())
+		if err != nil {
+			return nil, utils.WrapError(err, "Failed to create enrollment", utils.ErrCodeInternal)
+		}
+		response.EnrollmentId = enrollment.Id
+		response.EnrolledAt = &enrollment.EnrolledAt
+		response.ExpiresAt = enrollment.ExpiresAt
 	}
 
 	// 11. Update coupon used count nếu có
@@ -159,24 +179,12 @@ func (es *enrollmentService) EnrollCourse(userId, courseId uint, req *dto.Enroll
 	// 12. Update course enrolled count
 	// TODO: Implement UpdateEnrolledCount in CourseRepository
 
-	return &dto.EnrollCourseResponse{
-		EnrollmentId:   enrollment.Id,
-		OrderId:        order.Id,
-		OrderCode:      order.OrderCode,
-		CourseId:       course.Id,
-		CourseTitle:    course.Title,
-		OriginalPrice:  originalPrice,
-		DiscountAmount: discountAmount,
-		FinalPrice:     finalPrice,
-		PaymentMethod:  order.PaymentMethod,
-		PaymentStatus:  order.PaymentStatus,
-		EnrolledAt:     enrollment.EnrolledAt,
-		Message:        getEnrollmentMessage(finalPrice, order.PaymentStatus),
-	}, nil
+	return response, nil
 }
 
 func (es *enrollmentService) CheckEnrollment(userId, courseId uint) (*dto.CheckEnrollmentResponse, error) {
-	enrollment, exists := es.enrollmentRepo.CheckEnrollment(userId, courseId)
+	// Lấy enrollment mới nhất ở mọi trạng thái để client biết đã drop/hết hạn và có thể enroll lại
+	enrollment, exists := es.enrollmentRepo.FindEnrollment(userId, courseId)
 
 	if !exists {
 		return &dto.CheckEnrollmentResponse{
@@ -184,14 +192,63 @@ func (es *enrollmentService) CheckEnrollment(userId, courseId uint) (*dto.CheckE
 		}, nil
 	}
 
+	_, hasAccess := es.enrollmentRepo.CheckEnrollment(userId, courseId)
+
 	return &dto.CheckEnrollmentResponse{
-		IsEnrolled:      true,
+		IsEnrolled:      hasAccess,
 		EnrollmentId:    enrollment.Id,
 		EnrolledAt:      &enrollment.EnrolledAt,
 		ProgressPercent: enrollment.ProgressPercentage,
+		Status:          enrollment.Status,
+		ExpiresAt:       enrollment.ExpiresAt,
+		CanRenew:        canRenewEnrollment(enrollment),
 	}, nil
 }
 
+// DropEnrollment - học viên tự hủy enrollment. Progress được giữ lại để dùng khi enroll lại
+func (es *enrollmentService) DropEnrollment(userId, courseId uint) (*dto.DropEnrollmentResponse, error) {
+	// 1. Kiểm tra enrollment
+	enrollment, exists := es.enrollmentRepo.FindEnrollment(userId, courseId)
+	if !exists {
+		return nil, utils.NewError("Enrollment not found", utils.ErrCodeNotFound)
+	}
+
+	// 2. Chỉ enrollment đang học mới được hủy (đã hoàn thành thì giữ chứng chỉ và quyền truy cập)
+	if enrollment.Status != "active" {
+		return nil, utils.NewError(fmt.Sprintf("Cannot drop an enrollment with status '%s'", enrollment.Status), utils.ErrCodeBadRequest)
+	}
+
+	// 3. Cập nhật trạng thái
+	now := time.Now()
+	if err := es.enrollmentRepo.UpdateEnrollmentProgress(enrollment.Id, map[string]interface{}{
+		"status":     "dropped",
+		"dropped_at": now,
+	}); err != nil {
+		return nil, utils.WrapError(err, "Failed to drop enrollment", utils.ErrCodeInternal)
+	}
+
+	return &dto.DropEnrollmentResponse{
+		Message:      "You have been unenrolled from the course",
+		EnrollmentId: enrollment.Id,
+		CourseId:     courseId,
+		Status:       "dropped",
+		DroppedAt:    now,
+	}, nil
+}
+
+// ExpireEnrollments chuyển các enrollment đã quá expires_at sang expired
+func (es *enrollmentService) ExpireEnrollments(ctx context.Context) error {
+	expired, err := es.enrollmentRepo.ExpireEnrollments(time.Now())
+	if err != nil {
+		return err
+	}
+
+	if expired > 0 {
+		log.Printf("⏰ Expired %d enrollment(s)", expired)
+	}
+	return nil
+}
+
 func (es *enrollmentService) GetMyEnrollments(userId uint, req *dto.GetMyEnrollmentsQueryRequest) (*dto.GetMyEnrollmentsResponse, error) {
 	// Set defaults
 	page := 1
@@ -234,6 +291,9 @@ func (es *enrollmentService) GetMyEnrollments(userId uint, req *dto.GetMyEnrollm
 				EnrolledAt:         enrollment.EnrolledAt,
 				ProgressPercentage: enrollment.ProgressPercentage,
 				Status:             enrollment.Status,
+				ExpiresAt:          enrollment.ExpiresAt,
+				EnrollmentSource:   enrollment.EnrollmentSource,
+				DroppedAt:          enrollment.DroppedAt,
 				TotalLessons:       0,
 				CompletedLessons:   0,
 			}
@@ -255,6 +315,9 @@ func (es *enrollmentService) GetMyEnrollments(userId uint, req *dto.GetMyEnrollm
 			EnrolledAt:         enrollment.EnrolledAt,
 			ProgressPercentage: enrollment.ProgressPercentage,
 			Status:             enrollment.Status,
+			ExpiresAt:          enrollment.ExpiresAt,
+			EnrollmentSource:   enrollment.EnrollmentSource,
+			DroppedAt:          enrollment.DroppedAt,
 			TotalLessons:       course.TotalLessons,
 			CompletedLessons:   completedLessons,
 		}
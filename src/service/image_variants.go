@@ -0,0 +1,200 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"lms/src/imaging"
+	"lms/src/storage"
+	"lms/src/utils"
+	"log"
+	"mime/multipart"
+	"path"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// imageLimits giới hạn kích thước ảnh sau khi decode để chặn decompression bomb
+func imageLimits() imaging.Limits {
+	return imaging.Limits{
+		MaxPixels:    utils.GetEnvInt("IMAGE_MAX_PIXELS", 25_000_000),
+		MaxDimension: utils.GetEnvInt("IMAGE_MAX_DIMENSION", 10000),
+	}
+}
+
+// storeImageVariants validate, xử lý ảnh upload thành các variant và lưu lên storage.
+// Trả về map tên variant -> public URL, các key đã lưu được xóa nếu có lỗi giữa chừng.
+func storeImageVariants(ctx context.Context, backend storage.Backend, keyPrefix string, file *multipart.FileHeader, specs []imaging.Spec) (map[string]string, error) {
+	// 1. Validate extension, MIME type và dung lượng file
+	if _, _, err := utils.ValidateFile(file, utils.ImageFileRules); err != nil {
+		return nil, utils.WrapError(err, "Invalid image file", utils.ErrCodeBadRequest)
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to read image file", utils.ErrCodeBadRequest)
+	}
+	defer src.Close()
+
+	data, err := io.ReadAll(io.LimitReader(src, utils.ImageFileRules.MaxSize+1))
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to read image file", utils.ErrCodeBadRequest)
+	}
+
+	return storeImageData(ctx, backend, keyPrefix, data, specs)
+}
+
+// storeImageData xử lý ảnh đã đọc (và đã validate định dạng) thành các variant và lưu lên storage
+func storeImageData(ctx context.Context, backend storage.Backend, keyPrefix string, data []byte, specs []imaging.Spec) (map[string]string, error) {
+	// 2. Decode, xoay theo EXIF, resize và encode lại (metadata gốc bị loại bỏ)
+	quality := utils.GetEnvInt("IMAGE_JPEG_QUALITY", 85)
+	variants, err := imaging.Process(data, imageLimits(), specs, quality)
+	if err != nil {
+		switch {
+		case errors.Is(err, imaging.ErrImageTooLarge):
+			return nil, utils.NewError("Image dimensions are too large", utils.ErrCodeBadRequest)
+		case errors.Is(err, imaging.ErrAnimatedImage):
+			return nil, utils.NewError("Animated images are not allowed", utils.ErrCodeBadRequest)
+		case errors.Is(err, imaging.ErrUnsupportedFormat):
+			return nil, utils.NewError("Unsupported or corrupted image file", utils.ErrCodeBadRequest)
+		default:
+			return nil, utils.WrapError(err, "Failed to process image", utils.ErrCodeInternal)
+		}
+	}
+
+	// 3. Lưu từng variant, cùng một id để dễ nhận biết các variant của một lần upload
+	id := uuid.NewString()
+	urls := make(map[string]string, len(variants))
+	storedKeys := make([]string, 0, len(variants))
+	for _, variant := range variants {
+		key := fmt.Sprintf("%s/%s_%s%s", keyPrefix, id, variant.Name, imaging.Ext)
+		if err := backend.Put(ctx, key, bytes.NewReader(variant.Data), int64(len(variant.Data)), imaging.ContentType); err != nil {
+			for _, storedKey := range storedKeys {
+				backend.Delete(ctx, storedKey)
+			}
+			return nil, utils.WrapError(err, "Failed to store image", utils.ErrCodeInternal)
+		}
+
+		storedKeys = append(storedKeys, key)
+		urls[variant.Name] = storage.PublicURL(key)
+	}
+
+	return urls, nil
+}
+
+// largestImageVariant trả về URL của variant cuối cùng trong specs (lớn nhất), dùng làm URL mặc định
+func largestImageVariant(urls map[string]string, specs []imaging.Spec) string {
+	return urls[specs[len(specs)-1].Name]
+}
+
+// deleteImageVariants xóa các file ảnh cũ do storage quản lý (bỏ qua URL bên ngoài)
+func deleteImageVariants(ctx context.Context, backend storage.Backend, legacyURL string, variants map[string]string) {
+	urls := make(map[string]bool, len(variants)+1)
+	if legacyURL != "" {
+		urls[legacyURL] = true
+	}
+	for _, url := range variants {
+		urls[url] = true
+	}
+
+	for url := range urls {
+		key, ok := storage.KeyFromPublicURL(url)
+		if !ok {
+			continue
+		}
+		if err := backend.Delete(ctx, key); err != nil {
+			log.Printf("Failed to delete old image %s: %v", key, err)
+		}
+	}
+}
+
+// copyImageVariants sao chép các file ảnh do storage quản lý sang key mới (dùng khi clone course),
+// để việc thay/xóa ảnh ở bản sao không xóa mất ảnh của bản gốc. URL bên ngoài được giữ nguyên.
+// Trả về URL mới của legacyURL, các variant mới và danh sách key đã tạo (để dọn khi có lỗi).
+func copyImageVariants(ctx context.Context, backend storage.Backend, keyPrefix, legacyURL string, variants map[string]string) (string, map[string]string, []string, error) {
+	id := uuid.NewString()
+	copied := make(map[string]string, len(variants)+1)
+	var storedKeys []string
+
+	copyURL := func(url string) (string, error) {
+		if newURL, ok := copied[url]; ok {
+			return newURL, nil
+		}
+		key, ok := storage.KeyFromPublicURL(url)
+		if !ok {
+			copied[url] = url
+			return url, nil
+		}
+
+		newKey := fmt.Sprintf("%s/%s_%s", keyPrefix, id, imageVariantFileName(key))
+		if err := copyStorageObject(ctx, backend, key, newKey); err != nil {
+			return "", err
+		}
+		storedKeys = append(storedKeys, newKey)
+		copied[url] = storage.PublicURL(newKey)
+		return copied[url], nil
+	}
+
+	var newVariants map[string]string
+	if variants != nil {
+		newVariants = make(map[string]string, len(variants))
+		for name, url := range variants {
+			newURL, err := copyURL(url)
+			if err != nil {
+				deleteStorageKeys(ctx, backend, storedKeys)
+				return "", nil, nil, err
+			}
+			newVariants[name] = newURL
+		}
+	}
+
+	newLegacyURL := legacyURL
+	if legacyURL != "" {
+		newURL, err := copyURL(legacyURL)
+		if err != nil {
+			deleteStorageKeys(ctx, backend, storedKeys)
+			return "", nil, nil, err
+		}
+		newLegacyURL = newURL
+	}
+
+	return newLegacyURL, newVariants, storedKeys, nil
+}
+
+// imageVariantFileName bỏ id của lần upload cũ: "thumbnails/<uuid>_640.jpg" -> "640.jpg"
+func imageVariantFileName(key string) string {
+	name := path.Base(key)
+	if idx := strings.IndexByte(name, '_'); idx >= 0 {
+		return name[idx+1:]
+	}
+	return name
+}
+
+func copyStorageObject(ctx context.Context, backend storage.Backend, srcKey, dstKey string) error {
+	info, err := backend.Stat(ctx, srcKey)
+	if err != nil {
+		return fmt.Errorf("stat %s: %w", srcKey, err)
+	}
+
+	src, err := backend.Get(ctx, srcKey)
+	if err != nil {
+		return fmt.Errorf("read %s: %w", srcKey, err)
+	}
+	defer src.Close()
+
+	if err := backend.Put(ctx, dstKey, src, info.Size, info.ContentType); err != nil {
+		return fmt.Errorf("write %s: %w", dstKey, err)
+	}
+	return nil
+}
+
+func deleteStorageKeys(ctx context.Context, backend storage.Backend, keys []string) {
+	for _, key := range keys {
+		if err := backend.Delete(ctx, key); err != nil {
+			log.Printf("Failed to delete %s: %v", key, err)
+		}
+	}
+}
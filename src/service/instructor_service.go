@@ -1,23 +1,35 @@
 package service
 
 import (
+	"context"
+	"fmt"
 	"lms/src/dto"
+	"lms/src/imaging"
 	"lms/src/models"
 	"lms/src/repository"
+	"lms/src/storage"
 	"lms/src/utils"
 	"math"
+	"mime/multipart"
 	"strconv"
+	"time"
 )
 
 type instructorService struct {
 	instructorRepo repository.InstructorRepository
 	categoryRepo   repository.CategoryRepository
+	revisionRepo   repository.CourseRevisionRepository
+	recalcRepo     repository.ProgressRecalculationRepository
+	storage        storage.Backend
 }
 
-func NewInstructorService(instructorRepo repository.InstructorRepository, categoryRepo repository.CategoryRepository) InstructorService {
+func NewInstructorService(instructorRepo repository.InstructorRepository, categoryRepo repository.CategoryRepository, revisionRepo repository.CourseRevisionRepository, recalcRepo repository.ProgressRecalculationRepository, fileStorage storage.Backend) InstructorService {
 	return &instructorService{
 		instructorRepo: instructorRepo,
 		categoryRepo:   categoryRepo,
+		revisionRepo:   revisionRepo,
+		recalcRepo:     recalcRepo,
+		storage:        fileStorage,
 	}
 }
 
@@ -63,24 +75,25 @@ func (is *instructorService) GetInstructorCourses(instructorId uint, req *dto.Ge
 	courseItems := make([]dto.InstructorCourseItem, len(courses))
 	for i, course := range courses {
 		courseItems[i] = dto.InstructorCourseItem{
-			Id:            course.Id,
-			Title:         course.Title,
-			Slug:          course.Slug,
-			ThumbnailURL:  course.ThumbnailURL,
-			Price:         course.Price,
-			DiscountPrice: course.DiscountPrice,
-			CategoryId:    course.CategoryId,
-			CategoryName:  course.Category.Name,
-			Level:         course.Level,
-			Status:        course.Status,
-			TotalLessons:  course.TotalLessons,
-			DurationHours: course.DurationHours,
-			EnrolledCount: course.EnrolledCount,
-			RatingAvg:     course.RatingAvg,
-			RatingCount:   course.RatingCount,
-			IsFeatured:    course.IsFeatured,
-			CreatedAt:     course.CreatedAt,
-			UpdatedAt:     course.UpdatedAt,
+			Id:                course.Id,
+			Title:             course.Title,
+			Slug:              course.Slug,
+			ThumbnailURL:      course.ThumbnailURL,
+			ThumbnailVariants: course.ThumbnailVariants,
+			Price:             course.Price,
+			DiscountPrice:     course.DiscountPrice,
+			CategoryId:        course.CategoryId,
+			CategoryName:      course.Category.Name,
+			Level:             course.Level,
+			Status:            course.Status,
+			TotalLessons:      course.TotalLessons,
+			DurationHours:     course.DurationHours,
+			EnrolledCount:     course.EnrolledCount,
+			RatingAvg:         course.RatingAvg,
+			RatingCount:       course.RatingCount,
+			IsFeatured:        course.IsFeatured,
+			CreatedAt:         course.CreatedAt,
+			UpdatedAt:         course.UpdatedAt,
 		}
 	}
 
@@ -116,6 +129,11 @@ func (is *instructorService) CreateCourse(instructorId uint, req *dto.CreateCour
 		return nil, utils.NewError("discount price must be less than regular price", utils.ErrCodeBadRequest)
 	}
 
+	// Validate lịch publish/unpublish
+	if err := validateSchedule(req.PublishAt, req.UnpublishAt); err != nil {
+		return nil, err
+	}
+
 	// 3. Generate unique slug
 	baseSlug := utils.GenerateSlug(req.Title)
 	uniqueSlug := utils.GenerateUniqueSlug(baseSlug, func(slug string) bool {
@@ -125,25 +143,29 @@ func (is *instructorService) CreateCourse(instructorId uint, req *dto.CreateCour
 
 	// 4. Create course model
 	course := &models.Course{
-		Title:         req.Title,
-		Slug:          uniqueSlug,
-		Description:   req.Description,
-		ShortDesc:     req.ShortDesc,
-		Price:         req.Price,
-		DiscountPrice: req.DiscountPrice,
-		InstructorId:  instructorId,
-		CategoryId:    req.CategoryId,
-		Level:         req.Level,
-		Language:      req.Language,
-		Requirements:  req.Requirements,
-		WhatYouLearn:  req.WhatYouLearn,
-		DurationHours: req.DurationHours,
-		Status:        "draft", // Mặc định là draft
-		IsFeatured:    false,
-		TotalLessons:  0,
-		RatingAvg:     0,
-		RatingCount:   0,
-		EnrolledCount: 0,
+		Title:              req.Title,
+		Slug:               uniqueSlug,
+		Description:        req.Description,
+		DescriptionHTML:    renderMarkdown(req.Description),
+		ShortDesc:          req.ShortDesc,
+		Price:              req.Price,
+		DiscountPrice:      req.DiscountPrice,
+		InstructorId:       instructorId,
+		CategoryId:         req.CategoryId,
+		Level:              req.Level,
+		Language:           req.Language,
+		Requirements:       req.Requirements,
+		WhatYouLearn:       req.WhatYouLearn,
+		DurationHours:      req.DurationHours,
+		AccessDurationDays: req.AccessDurationDays,
+		PublishAt:          req.PublishAt,
+		UnpublishAt:        req.UnpublishAt,
+		Status:             "draft", // Mặc định là draft
+		IsFeatured:         false,
+		TotalLessons:       0,
+		RatingAvg:          0,
+		RatingCount:        0,
+		EnrolledCount:      0,
 	}
 
 	// 5. Save to database
@@ -153,31 +175,35 @@ func (is *instructorService) CreateCourse(instructorId uint, req *dto.CreateCour
 
 	// 6. Return response
 	return &dto.CreateCourseResponse{
-		Id:              course.Id,
-		Title:           course.Title,
-		Slug:            course.Slug,
-		Description:     course.Description,
-		ShortDesc:       course.ShortDesc,
-		ThumbnailURL:    course.ThumbnailURL,
-		VideoPreviewURL: course.VideoPreviewURL,
-		Price:           course.Price,
-		DiscountPrice:   course.DiscountPrice,
-		InstructorId:    course.InstructorId,
-		CategoryId:      course.CategoryId,
-		CategoryName:    category.Name,
-		Level:           course.Level,
-		DurationHours:   course.DurationHours,
-		TotalLessons:    course.TotalLessons,
-		Language:        course.Language,
-		Requirements:    course.Requirements,
-		WhatYouLearn:    course.WhatYouLearn,
-		Status:          course.Status,
-		IsFeatured:      course.IsFeatured,
-		RatingAvg:       course.RatingAvg,
-		RatingCount:     course.RatingCount,
-		EnrolledCount:   course.EnrolledCount,
-		CreatedAt:       course.CreatedAt,
-		UpdatedAt:       course.UpdatedAt,
+		Id:                course.Id,
+		Title:             course.Title,
+		Slug:              course.Slug,
+		Description:       course.Description,
+		DescriptionHTML:   course.DescriptionHTML,
+		ShortDesc:         course.ShortDesc,
+		ThumbnailURL:      course.ThumbnailURL,
+		ThumbnailVariants: course.ThumbnailVariants,
+		VideoPreviewURL:   course.VideoPreviewURL,
+		Price:             course.Price,
+		DiscountPrice:     course.DiscountPrice,
+		InstructorId:      course.InstructorId,
+		CategoryId:        course.CategoryId,
+		CategoryName:      category.Name,
+		Level:             course.Level,
+		DurationHours:     course.DurationHours,
+		TotalLessons:      course.TotalLessons,
+		Language:          course.Language,
+		Requirements:      course.Requirements,
+		WhatYouLearn:      course.WhatYouLearn,
+		Status:            course.Status,
+		IsFeatured:        course.IsFeatured,
+		RatingAvg:         course.RatingAvg,
+		RatingCount:       course.RatingCount,
+		EnrolledCount:     course.EnrolledCount,
+		PublishAt:         course.PublishAt,
+		UnpublishAt:       course.UnpublishAt,
+		CreatedAt:         course.CreatedAt,
+		UpdatedAt:         course.UpdatedAt,
 	}, nil
 }
 
@@ -188,6 +214,18 @@ func (is *instructorService) UpdateCourse(instructorId, courseId uint, req *dto.
 		return nil, utils.NewError("course not found or you don't have permission to update this course", utils.ErrCodeNotFound)
 	}
 
+	// Nội dung đang được admin review thì không được sửa
+	if course.Status == "pending_review" && hasCourseContentChanges(req) {
+		return nil, utils.NewError("course is waiting for review and cannot be edited", utils.ErrCodeBadRequest)
+	}
+
+	// Course đã published hoặc đang có draft revision thì nội dung phải được sửa qua draft
+	if hasCourseContentChanges(req) {
+		if err := is.ensureLiveEditable(course); err != nil {
+			return nil, err
+		}
+	}
+
 	// 2. Validate category nếu được cập nhật
 	var category *models.Category
 	if req.CategoryId != 0 && req.CategoryId != course.CategoryId {
@@ -231,6 +269,7 @@ func (is *instructorService) UpdateCourse(instructorId, courseId uint, req *dto.
 
 	if req.Description != "" {
 		updates["description"] = req.Description
+		updates["description_html"] = renderMarkdown(req.Description)
 	}
 
 	if req.ShortDesc != "" {
@@ -269,7 +308,19 @@ func (is *instructorService) UpdateCourse(instructorId, courseId uint, req *dto.
 		updates["duration_hours"] = req.DurationHours
 	}
 
+	// Thời hạn truy cập chỉ áp dụng cho các lần mua sau, enrollment hiện có giữ expires_at cũ
+	if req.AccessDurationDays != nil {
+		updates["access_duration_days"] = *req.AccessDurationDays
+	}
+
 	if req.Status != "" {
+		// Instructor không được tự publish, phải gửi review qua submit-review
+		if req.Status != "draft" && req.Status != "archived" {
+			return nil, utils.NewError("course can only be published through the review process", utils.ErrCodeBadRequest)
+		}
+		if course.Status == "pending_review" {
+			return nil, utils.NewError("cannot change status while course is waiting for review", utils.ErrCodeBadRequest)
+		}
 		// Không cho phép chuyển từ published về draft nếu đã có học viên
 		if course.Status == "published" && req.Status == "draft" {
 			enrollmentCount, _ := is.instructorRepo.CountEnrollmentsByCourse(courseId)
@@ -284,6 +335,36 @@ func (is *instructorService) UpdateCourse(instructorId, courseId uint, req *dto.
 		updates["is_featured"] = *req.IsFeatured
 	}
 
+	// Lịch publish/unpublish
+	if req.ClearSchedule {
+		updates["publish_at"] = nil
+		updates["unpublish_at"] = nil
+		// Course đã được duyệt và đang chờ tới giờ thì publish ngay
+		if course.Status == "scheduled" && req.Status == "" {
+			updates["status"] = "published"
+		}
+	} else if req.PublishAt != nil || req.UnpublishAt != nil {
+		if req.PublishAt != nil && course.Status == "published" {
+			return nil, utils.NewError("course is already published", utils.ErrCodeBadRequest)
+		}
+
+		// Giữ publish_at hiện tại nếu vẫn còn ở tương lai
+		publishAt := req.PublishAt
+		if publishAt == nil && course.PublishAt != nil && course.PublishAt.After(time.Now()) {
+			publishAt = course.PublishAt
+		}
+		if err := validateSchedule(publishAt, req.UnpublishAt); err != nil {
+			return nil, err
+		}
+
+		if req.PublishAt != nil {
+			updates["publish_at"] = req.PublishAt
+		}
+		if req.UnpublishAt != nil {
+			updates["unpublish_at"] = req.UnpublishAt
+		}
+	}
+
 	// 5. Kiểm tra có gì cần update không
 	if len(updates) == 0 {
 		return nil, utils.NewError("no fields to update", utils.ErrCodeBadRequest)
@@ -302,31 +383,98 @@ func (is *instructorService) UpdateCourse(instructorId, courseId uint, req *dto.
 
 	// 8. Return response
 	return &dto.UpdateCourseResponse{
-		Id:              updatedCourse.Id,
-		Title:           updatedCourse.Title,
-		Slug:            updatedCourse.Slug,
-		Description:     updatedCourse.Description,
-		ShortDesc:       updatedCourse.ShortDesc,
-		ThumbnailURL:    updatedCourse.ThumbnailURL,
-		VideoPreviewURL: updatedCourse.VideoPreviewURL,
-		Price:           updatedCourse.Price,
-		DiscountPrice:   updatedCourse.DiscountPrice,
-		InstructorId:    updatedCourse.InstructorId,
-		CategoryId:      updatedCourse.CategoryId,
-		CategoryName:    category.Name,
-		Level:           updatedCourse.Level,
-		DurationHours:   updatedCourse.DurationHours,
-		TotalLessons:    updatedCourse.TotalLessons,
-		Language:        updatedCourse.Language,
-		Requirements:    updatedCourse.Requirements,
-		WhatYouLearn:    updatedCourse.WhatYouLearn,
-		Status:          updatedCourse.Status,
-		IsFeatured:      updatedCourse.IsFeatured,
-		RatingAvg:       updatedCourse.RatingAvg,
-		RatingCount:     updatedCourse.RatingCount,
-		EnrolledCount:   updatedCourse.EnrolledCount,
-		CreatedAt:       updatedCourse.CreatedAt,
-		UpdatedAt:       updatedCourse.UpdatedAt,
+		Id:                 updatedCourse.Id,
+		Title:              updatedCourse.Title,
+		Slug:               updatedCourse.Slug,
+		Description:        updatedCourse.Description,
+		DescriptionHTML:    updatedCourse.DescriptionHTML,
+		ShortDesc:          updatedCourse.ShortDesc,
+		ThumbnailURL:       updatedCourse.ThumbnailURL,
+		ThumbnailVariants:  updatedCourse.ThumbnailVariants,
+		VideoPreviewURL:    updatedCourse.VideoPreviewURL,
+		Price:              updatedCourse.Price,
+		DiscountPrice:      updatedCourse.DiscountPrice,
+		InstructorId:       updatedCourse.InstructorId,
+		CategoryId:         updatedCourse.CategoryId,
+		CategoryName:       category.Name,
+		Level:              updatedCourse.Level,
+		DurationHours:      updatedCourse.DurationHours,
+		AccessDurationDays: updatedCourse.AccessDurationDays,
+		TotalLessons:       updatedCourse.TotalLessons,
+		Language:           updatedCourse.Language,
+		Requirements:       updatedCourse.Requirements,
+		WhatYouLearn:       updatedCourse.WhatYouLearn,
+		Status:             updatedCourse.Status,
+		IsFeatured:         updatedCourse.IsFeatured,
+		RatingAvg:          updatedCourse.RatingAvg,
+		RatingCount:        updatedCourse.RatingCount,
+		EnrolledCount:      updatedCourse.EnrolledCount,
+		PublishAt:          updatedCourse.PublishAt,
+		UnpublishAt:        updatedCourse.UnpublishAt,
+		CreatedAt:          updatedCourse.CreatedAt,
+		UpdatedAt:          updatedCourse.UpdatedAt,
+	}, nil
+}
+
+func (is *instructorService) UploadCourseThumbnail(instructorId, courseId uint, file *multipart.FileHeader) (*dto.UploadCourseThumbnailResponse, error) {
+	// 1. Kiểm tra course có tồn tại và thuộc về instructor này không
+	course, err := is.instructorRepo.FindCourseByIdAndInstructor(courseId, instructorId)
+	if err != nil {
+		return nil, utils.NewError("course not found or you don't have permission to update this course", utils.ErrCodeNotFound)
+	}
+
+	// 2. Thumbnail là nội dung course nên áp dụng cùng điều kiện với UpdateCourse
+	if course.Status == "pending_review" {
+		return nil, utils.NewError("course is waiting for review and cannot be edited", utils.ErrCodeBadRequest)
+	}
+	if err := is.ensureLiveEditable(course); err != nil {
+		return nil, err
+	}
+
+	// 3. Xử lý ảnh thành các variant 16:9 (320/640/1280px) và lưu lên storage
+	ctx := context.Background()
+	variants, err := storeImageVariants(ctx, is.storage, "thumbnails", file, imaging.ThumbnailSpecs)
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. thumbnail_url giữ variant lớn nhất để client cũ vẫn hoạt động
+	thumbnailURL := largestImageVariant(variants, imaging.ThumbnailSpecs)
+	if err := is.instructorRepo.UpdateCourseThumbnail(courseId, thumbnailURL, variants); err != nil {
+		deleteImageVariants(ctx, is.storage, "", variants)
+		return nil, utils.WrapError(err, "Failed to update course thumbnail", utils.ErrCodeInternal)
+	}
+
+	// 5. Xóa thumbnail cũ nếu do storage quản lý và không còn trong revision nào của course
+	if course.ThumbnailURL == "" || !isRetainedByRevision(is.revisionRepo, courseId, course.ThumbnailURL) {
+		deleteImageVariants(ctx, is.storage, course.ThumbnailURL, course.ThumbnailVariants)
+	}
+
+	return &dto.UploadCourseThumbnailResponse{
+		Message:           "Course thumbnail uploaded successfully",
+		CourseId:          courseId,
+		ThumbnailURL:      thumbnailURL,
+		ThumbnailVariants: variants,
+	}, nil
+}
+
+func (is *instructorService) UploadContentImage(instructorId, courseId uint, file *multipart.FileHeader) (*dto.UploadContentImageResponse, error) {
+	// 1. Kiểm tra course có tồn tại và thuộc về instructor này không
+	if _, err := is.instructorRepo.FindCourseByIdAndInstructor(courseId, instructorId); err != nil {
+		return nil, utils.NewError("course not found or you don't have permission to update this course", utils.ErrCodeNotFound)
+	}
+
+	// 2. Xử lý ảnh (bỏ metadata, giới hạn chiều rộng) và lưu lên storage
+	variants, err := storeImageVariants(context.Background(), is.storage, fmt.Sprintf("content-images/%d", courseId), file, imaging.ContentImageSpecs)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Chỉ ảnh trên storage của hệ thống mới được nhúng khi render Markdown
+	url := largestImageVariant(variants, imaging.ContentImageSpecs)
+	return &dto.UploadContentImageResponse{
+		URL:      url,
+		Markdown: fmt.Sprintf("![](%s)", url),
 	}, nil
 }
 
@@ -427,6 +575,8 @@ func (is *instructorService) GetCourseStudents(instructorId, courseId uint, req
 			ProgressPercentage: enrollment.ProgressPercentage,
 			LastAccessedAt:     enrollment.LastAccessedAt,
 			Status:             enrollment.Status,
+			ExpiresAt:          enrollment.ExpiresAt,
+			EnrollmentSource:   enrollment.EnrollmentSource,
 		}
 	}
 
@@ -451,11 +601,16 @@ func (is *instructorService) GetCourseStudents(instructorId, courseId uint, req
 
 func (is *instructorService) CreateLesson(instructorId, courseId uint, req *dto.CreateLessonRequest) (*dto.CreateLessonResponse, error) {
 	// 1. Kiểm tra course có tồn tại và thuộc về instructor không
-	_, err := is.instructorRepo.FindCourseByIdAndInstructor(courseId, instructorId)
+	course, err := is.instructorRepo.FindCourseByIdAndInstructor(courseId, instructorId)
 	if err != nil {
 		return nil, utils.NewError("Course not found or you don't have permission", utils.ErrCodeNotFound)
 	}
 
+	// Lesson của course đã published được thêm qua draft revision
+	if err := is.ensureLiveEditable(course); err != nil {
+		return nil, err
+	}
+
 	// 2. Generate slug từ title
 	baseSlug := utils.GenerateSlug(req.Title)
 
@@ -480,6 +635,16 @@ func (is *instructorService) CreateLesson(instructorId, courseId uint, req *dto.
 		return nil, utils.NewError("Lesson order already exists in this course", utils.ErrCodeConflict)
 	}
 
+	// Validate lịch publish/unpublish
+	if err := validateSchedule(req.PublishAt, req.UnpublishAt); err != nil {
+		return nil, err
+	}
+
+	lessonType := req.LessonType
+	if lessonType == "" {
+		lessonType = lessonTypeVideo
+	}
+
 	// 5. Tạo lesson mới
 	lesson := &models.Lesson{
 		CourseId:      courseId,
@@ -487,11 +652,18 @@ func (is *instructorService) CreateLesson(instructorId, courseId uint, req *dto.
 		Slug:          slug,
 		Description:   req.Description,
 		Content:       req.Content,
+		ContentHTML:   renderMarkdown(req.Content),
 		VideoURL:      req.VideoURL,
 		VideoDuration: req.VideoDuration,
+		LessonType:    lessonType,
 		LessonOrder:   req.LessonOrder,
 		IsPreview:     req.IsPreview,
 		IsPublished:   req.IsPublished,
+		PublishAt:     req.PublishAt,
+		UnpublishAt:   req.UnpublishAt,
+
+		UnlockAfterDays:           req.UnlockAfterDays,
+		RequirePreviousCompletion: req.RequirePreviousCompletion,
 	}
 
 	// 6. Lưu vào database
@@ -499,6 +671,20 @@ func (is *instructorService) CreateLesson(instructorId, courseId uint, req *dto.
 		return nil, utils.WrapError(err, "Failed to create lesson", utils.ErrCodeInternal)
 	}
 
+	// Lesson có lịch publish sẽ ẩn cho tới khi scheduler publish
+	// (is_published có default true nên phải update riêng)
+	if lesson.PublishAt != nil {
+		if err := is.instructorRepo.UpdateLesson(lesson.Id, map[string]interface{}{"is_published": false}); err != nil {
+			return nil, utils.WrapError(err, "Failed to create lesson", utils.ErrCodeInternal)
+		}
+		lesson.IsPublished = false
+	}
+
+	// Lesson mới làm giảm % của học viên đã học (lesson chưa publish chưa tính vào progress)
+	if lesson.IsPublished {
+		enqueueProgressRecalculation(is.recalcRepo, courseId, recalcReasonLessonCreated)
+	}
+
 	// 7. Trả về response
 	return &dto.CreateLessonResponse{
 		Id:            lesson.Id,
@@ -507,28 +693,40 @@ func (is *instructorService) CreateLesson(instructorId, courseId uint, req *dto.
 		Slug:          lesson.Slug,
 		Description:   lesson.Description,
 		Content:       lesson.Content,
+		ContentHTML:   lesson.ContentHTML,
 		VideoURL:      lesson.VideoURL,
 		VideoDuration: lesson.VideoDuration,
+		LessonType:    lesson.LessonType,
 		LessonOrder:   lesson.LessonOrder,
 		IsPreview:     lesson.IsPreview,
 		IsPublished:   lesson.IsPublished,
-		CreatedAt:     lesson.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		PublishAt:     lesson.PublishAt,
+		UnpublishAt:   lesson.UnpublishAt,
+
+		UnlockAfterDays:           lesson.UnlockAfterDays,
+		RequirePreviousCompletion: lesson.RequirePreviousCompletion,
+
+		CreatedAt: lesson.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 	}, nil
 }
 
 func (is *instructorService) UpdateLesson(instructorId, courseId, lessonId uint, req *dto.UpdateLessonRequest) (*dto.UpdateLessonResponse, error) {
 	// 1. Kiểm tra course có tồn tại và thuộc về instructor không
-	_, err := is.instructorRepo.FindCourseByIdAndInstructor(courseId, instructorId)
+	course, err := is.instructorRepo.FindCourseByIdAndInstructor(courseId, instructorId)
 	if err != nil {
 		return nil, utils.NewError("Course not found or you don't have permission", utils.ErrCodeNotFound)
 	}
 
 	// 2. Kiểm tra lesson có tồn tại và thuộc về course không
-	_, err = is.instructorRepo.FindLessonByIdAndCourse(lessonId, courseId)
+	lesson, err := is.instructorRepo.FindLessonByIdAndCourse(lessonId, courseId)
 	if err != nil {
 		return nil, utils.NewError("Lesson not found", utils.ErrCodeNotFound)
 	}
 
+	if err := is.ensureLiveEditable(course); err != nil {
+		return nil, err
+	}
+
 	// 3. Chuẩn bị updates map
 	updates := make(map[string]interface{})
 
@@ -555,6 +753,7 @@ func (is *instructorService) UpdateLesson(instructorId, courseId, lessonId uint,
 
 	if req.Content != nil {
 		updates["content"] = *req.Content
+		updates["content_html"] = renderMarkdown(*req.Content)
 	}
 
 	if req.VideoURL != nil {
@@ -565,6 +764,10 @@ func (is *instructorService) UpdateLesson(instructorId, courseId, lessonId uint,
 		updates["video_duration"] = *req.VideoDuration
 	}
 
+	if req.LessonType != nil {
+		updates["lesson_type"] = *req.LessonType
+	}
+
 	if req.LessonOrder != nil {
 		// Kiểm tra lesson_order mới có bị trùng không (trừ chính lesson này)
 		orderExists, err := is.instructorRepo.CheckLessonOrderExistsExcept(courseId, *req.LessonOrder, lessonId)
@@ -585,6 +788,38 @@ func (is *instructorService) UpdateLesson(instructorId, courseId, lessonId uint,
 		updates["is_published"] = *req.IsPublished
 	}
 
+	// Drip content
+	if req.UnlockAfterDays != nil {
+		updates["unlock_after_days"] = *req.UnlockAfterDays
+	}
+
+	if req.RequirePreviousCompletion != nil {
+		updates["require_previous_completion"] = *req.RequirePreviousCompletion
+	}
+
+	// Lịch publish/unpublish
+	if req.ClearSchedule {
+		updates["publish_at"] = nil
+		updates["unpublish_at"] = nil
+	} else if req.PublishAt != nil || req.UnpublishAt != nil {
+		// Giữ publish_at hiện tại nếu vẫn còn ở tương lai
+		publishAt := req.PublishAt
+		if publishAt == nil && lesson.PublishAt != nil && lesson.PublishAt.After(time.Now()) {
+			publishAt = lesson.PublishAt
+		}
+		if err := validateSchedule(publishAt, req.UnpublishAt); err != nil {
+			return nil, err
+		}
+
+		if req.PublishAt != nil {
+			updates["publish_at"] = req.PublishAt
+			updates["is_published"] = false // Ẩn cho tới giờ publish
+		}
+		if req.UnpublishAt != nil {
+			updates["unpublish_at"] = req.UnpublishAt
+		}
+	}
+
 	// 4. Nếu không có gì để update
 	if len(updates) == 0 {
 		return nil, utils.NewError("No fields to update", utils.ErrCodeBadRequest)
@@ -601,6 +836,11 @@ func (is *instructorService) UpdateLesson(instructorId, courseId, lessonId uint,
 		return nil, utils.WrapError(err, "Failed to get updated lesson", utils.ErrCodeInternal)
 	}
 
+	// Publish/unpublish lesson làm thay đổi tổng số lessons tính progress
+	if updatedLesson.IsPublished != lesson.IsPublished {
+		enqueueProgressRecalculation(is.recalcRepo, courseId, recalcReasonLessonUpdated)
+	}
+
 	// 7. Trả về response
 	return &dto.UpdateLessonResponse{
 		Id:            updatedLesson.Id,
@@ -609,33 +849,49 @@ func (is *instructorService) UpdateLesson(instructorId, courseId, lessonId uint,
 		Slug:          updatedLesson.Slug,
 		Description:   updatedLesson.Description,
 		Content:       updatedLesson.Content,
+		ContentHTML:   updatedLesson.ContentHTML,
 		VideoURL:      updatedLesson.VideoURL,
 		VideoDuration: updatedLesson.VideoDuration,
+		LessonType:    updatedLesson.LessonType,
 		LessonOrder:   updatedLesson.LessonOrder,
 		IsPreview:     updatedLesson.IsPreview,
 		IsPublished:   updatedLesson.IsPublished,
-		UpdatedAt:     updatedLesson.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		PublishAt:     updatedLesson.PublishAt,
+		UnpublishAt:   updatedLesson.UnpublishAt,
+
+		UnlockAfterDays:           updatedLesson.UnlockAfterDays,
+		RequirePreviousCompletion: updatedLesson.RequirePreviousCompletion,
+
+		UpdatedAt: updatedLesson.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
 	}, nil
 }
 
 func (is *instructorService) DeleteLesson(instructorId, courseId, lessonId uint) (*dto.DeleteLessonResponse, error) {
 	// 1. Kiểm tra course có tồn tại và thuộc về instructor không
-	_, err := is.instructorRepo.FindCourseByIdAndInstructor(courseId, instructorId)
+	course, err := is.instructorRepo.FindCourseByIdAndInstructor(courseId, instructorId)
 	if err != nil {
 		return nil, utils.NewError("Course not found or you don't have permission", utils.ErrCodeNotFound)
 	}
 
 	// 2. Kiểm tra lesson có tồn tại và thuộc về course không
-	_, err = is.instructorRepo.FindLessonByIdAndCourse(lessonId, courseId)
+	lesson, err := is.instructorRepo.FindLessonByIdAndCourse(lessonId, courseId)
 	if err != nil {
 		return nil, utils.NewError("Lesson not found", utils.ErrCodeNotFound)
 	}
 
+	if err := is.ensureLiveEditable(course); err != nil {
+		return nil, err
+	}
+
 	// 3. Delete lesson (soft delete)
-	if err := is.instructorRepo.DeleteCourse(lessonId); err != nil {
+	if err := is.instructorRepo.DeleteLesson(lessonId); err != nil {
 		return nil, utils.WrapError(err, "Failed to delete lesson", utils.ErrCodeInternal)
 	}
 
+	if lesson.IsPublished {
+		enqueueProgressRecalculation(is.recalcRepo, courseId, recalcReasonLessonDeleted)
+	}
+
 	// 4. Trả về response
 	return &dto.DeleteLessonResponse{
 		Message: "Lesson deleted successfully",
@@ -674,11 +930,15 @@ func (is *instructorService) ReorderLessons(instructorId, lessonId uint, req *dt
 	// firstLesson = lessons[0]
 
 	// 2. Kiểm tra course có tồn tại và thuộc về instructor không
-	_, err = is.instructorRepo.FindCourseByIdAndInstructor(courseId, instructorId)
+	course, err := is.instructorRepo.FindCourseByIdAndInstructor(courseId, instructorId)
 	if err != nil {
 		return nil, utils.NewError("Course not found or you don't have permission", utils.ErrCodeNotFound)
 	}
 
+	if err := is.ensureLiveEditable(course); err != nil {
+		return nil, err
+	}
+
 	// 3. Kiểm tra tất cả lessons có thuộc về cùng một course không
 	lessonCourseMap := make(map[uint]uint) // lessonId -> courseId
 	for _, lesson := range lessons {
@@ -736,3 +996,54 @@ func (is *instructorService) ReorderLessons(instructorId, lessonId uint, req *dt
 	}, nil
 
 }
+
+// ensureLiveEditable chặn sửa trực tiếp bản live: course đã published chỉ được sửa qua draft revision
+// (mỗi lần publish được ghi lại để rollback), course khác thì không được có draft đang mở
+func (is *instructorService) ensureLiveEditable(course *models.Course) error {
+	if course.Status == "published" {
+		return utils.NewError("Published courses are edited through draft revisions. Create a draft, edit it and publish it", utils.ErrCodeConflict)
+	}
+
+	draft, err := is.revisionRepo.FindDraftByCourse(course.Id)
+	if err != nil {
+		return utils.WrapError(err, "Failed to check draft revision", utils.ErrCodeInternal)
+	}
+	if draft != nil {
+		return utils.NewError("This course has an open draft revision. Edit the draft or discard it first", utils.ErrCodeConflict)
+	}
+	return nil
+}
+
+// validateSchedule kiểm tra publish_at/unpublish_at phải ở tương lai và đúng thứ tự
+func validateSchedule(publishAt, unpublishAt *time.Time) error {
+	now := time.Now()
+
+	if publishAt != nil && !publishAt.After(now) {
+		return utils.NewError("publish_at must be in the future", utils.ErrCodeBadRequest)
+	}
+
+	if unpublishAt != nil {
+		if !unpublishAt.After(now) {
+			return utils.NewError("unpublish_at must be in the future", utils.ErrCodeBadRequest)
+		}
+		if publishAt != nil && !unpublishAt.After(*publishAt) {
+			return utils.NewError("unpublish_at must be after publish_at", utils.ErrCodeBadRequest)
+		}
+	}
+
+	return nil
+}
+
+func hasCourseContentChanges(req *dto.UpdateCourseRequest) bool {
+	return req.Title != "" ||
+		req.Description != "" ||
+		req.ShortDesc != "" ||
+		req.CategoryId != 0 ||
+		req.Level != "" ||
+		req.Language != "" ||
+		req.Price > 0 ||
+		req.DiscountPrice != nil ||
+		req.Requirements != "" ||
+		req.WhatYouLearn != "" ||
+		req.DurationHours > 0
+}
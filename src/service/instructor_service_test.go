@@ -0,0 +1,44 @@
+package service
+
+import (
+	"lms/src/models"
+	"lms/src/repository"
+	"lms/src/utils"
+	"testing"
+)
+
+// fakeDraftRevisionRepo trả về draft đang mở theo course
+type fakeDraftRevisionRepo struct {
+	repository.CourseRevisionRepository
+	drafts map[uint]*models.CourseRevision
+}
+
+func (r *fakeDraftRevisionRepo) FindDraftByCourse(courseId uint) (*models.CourseRevision, error) {
+	return r.drafts[courseId], nil
+}
+
+func TestEnsureLiveEditable(t *testing.T) {
+	revisionRepo := &fakeDraftRevisionRepo{drafts: map[uint]*models.CourseRevision{
+		3: {Id: 30, CourseId: 3, Status: "draft"},
+	}}
+	is := &instructorService{revisionRepo: revisionRepo}
+
+	tests := []struct {
+		name     string
+		course   models.Course
+		wantCode utils.ErrorCode
+	}{
+		{"draft course", models.Course{Id: 1, Status: "draft"}, ""},
+		{"published course without draft", models.Course{Id: 2, Status: "published"}, utils.ErrCodeConflict},
+		{"published course with draft", models.Course{Id: 3, Status: "published"}, utils.ErrCodeConflict},
+		{"unpublished course with draft", models.Course{Id: 3, Status: "draft"}, utils.ErrCodeConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errorCode(is.ensureLiveEditable(&tt.course)); got != tt.wantCode {
+				t.Errorf("error code = %q, want %q", got, tt.wantCode)
+			}
+		})
+	}
+}
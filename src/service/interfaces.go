@@ -1,6 +1,8 @@
 package service
 
 import (
+	"context"
+	"io"
 	"lms/src/dto"
 	"lms/src/models"
 	"mime/multipart"
@@ -19,6 +21,8 @@ type AuthService interface {
 type EmailService interface {
 	SendPasswordResetEmail(email, resetToken, resetCode string) error
 	SendWelcomeEmail(email, fullName string) error
+	SendCourseReviewResultEmail(email, fullName, courseTitle string, approved bool, comment string) error
+	SendLearningReminderEmail(email, fullName string, courseTitles []string, inactiveDays int) error
 }
 
 type UserService interface {
@@ -69,27 +73,184 @@ type EnrollmentService interface {
 	EnrollCourse(userId, courseId uint, req *dto.EnrollCourseRequest) (*dto.EnrollCourseResponse, error)
 	CheckEnrollment(userId, courseId uint) (*dto.CheckEnrollmentResponse, error)
 	GetMyEnrollments(userId uint, req *dto.GetMyEnrollmentsQueryRequest) (*dto.GetMyEnrollmentsResponse, error)
+	DropEnrollment(userId, courseId uint) (*dto.DropEnrollmentResponse, error)
+	ExpireEnrollments(ctx context.Context) error
+}
+
+type EnrollmentManagementService interface {
+	AdminEnrollUser(adminId uint, req *dto.AdminEnrollUserRequest) (*dto.ManagedEnrollmentItem, error)
+	BulkEnrollUsers(adminId, courseId uint, req *dto.BulkEnrollRequest, file *multipart.FileHeader) (*dto.BulkEnrollResponse, error)
+	RevokeEnrollment(adminId, enrollmentId uint, req *dto.RevokeEnrollmentRequest) (*dto.ManagedEnrollmentItem, error)
+	TransferEnrollment(adminId, enrollmentId uint, req *dto.TransferEnrollmentRequest) (*dto.TransferEnrollmentResponse, error)
+	GetComplimentaryAccess(instructorId, courseId uint) (*dto.ComplimentaryAccessResponse, error)
+	GrantComplimentaryAccess(instructorId, courseId uint, req *dto.GrantComplimentaryAccessRequest) (*dto.ManagedEnrollmentItem, error)
+	RevokeComplimentaryAccess(instructorId, courseId, enrollmentId uint, req *dto.RevokeComplimentaryAccessRequest) (*dto.ManagedEnrollmentItem, error)
 }
 
 type InstructorService interface {
 	CreateCourse(instructorId uint, req *dto.CreateCourseRequest) (*dto.CreateCourseResponse, error)
 	GetInstructorCourses(instructorId uint, req *dto.GetInstructorCoursesQueryRequest) (*dto.GetInstructorCoursesResponse, error)
 	UpdateCourse(instructorId, courseId uint, req *dto.UpdateCourseRequest) (*dto.UpdateCourseResponse, error)
+	UploadCourseThumbnail(instructorId, courseId uint, file *multipart.FileHeader) (*dto.UploadCourseThumbnailResponse, error)
+	UploadContentImage(instructorId, courseId uint, file *multipart.FileHeader) (*dto.UploadContentImageResponse, error)
 	DeleteCourse(instructorId, courseId uint) (*dto.DeleteCourseResponse, error)
 	GetCourseStudents(instructorId, courseId uint, req *dto.GetCourseStudentsQueryRequest) (*dto.GetCourseStudentsResponse, error)
 	CreateLesson(instructorId, courseId uint, req *dto.CreateLessonRequest) (*dto.CreateLessonResponse, error)
 	UpdateLesson(instructorId, courseId, lessonId uint, req *dto.UpdateLessonRequest) (*dto.UpdateLessonResponse, error)
 	DeleteLesson(instructorId, courseId, lessonId uint) (*dto.DeleteLessonResponse, error)
 	ReorderLessons(instructorId, lessonId uint, req *dto.ReorderLessonsRequest) (*dto.ReorderLessonsResponse, error)
+	CloneCourse(instructorId, courseId uint, req *dto.CloneCourseRequest) (*dto.CloneCourseResponse, error)
+	DuplicateLesson(instructorId, courseId, lessonId uint) (*dto.DuplicateLessonResponse, error)
+}
+
+type CourseRevisionService interface {
+	GetCourseRevisions(instructorId, courseId uint, req *dto.GetCourseRevisionsQueryRequest) (*dto.GetCourseRevisionsResponse, error)
+	CreateDraft(instructorId, courseId uint, req *dto.CreateCourseRevisionRequest) (*dto.CourseRevisionDetail, error)
+	GetDraft(instructorId, courseId uint) (*dto.CourseRevisionDetail, error)
+	UpdateDraft(instructorId, courseId uint, req *dto.UpdateCourseRevisionRequest) (*dto.CourseRevisionDetail, error)
+	DiscardDraft(instructorId, courseId uint) (*dto.DeleteCourseRevisionResponse, error)
+	GetDraftDiff(instructorId, courseId uint) (*dto.CourseRevisionDiffResponse, error)
+	PublishDraft(instructorId, courseId uint) (*dto.PublishCourseRevisionResponse, error)
+	RollbackToRevision(instructorId, courseId, revisionId uint) (*dto.PublishCourseRevisionResponse, error)
+}
+
+type CoursePrerequisiteService interface {
+	GetCoursePrerequisites(instructorId, courseId uint) (*dto.GetCoursePrerequisitesResponse, error)
+	UpdateCoursePrerequisites(instructorId, courseId uint, req *dto.UpdateCoursePrerequisitesRequest) (*dto.GetCoursePrerequisitesResponse, error)
+}
+
+type LearningPathService interface {
+	GetLearningPaths(req *dto.GetLearningPathsQueryRequest) (*dto.GetLearningPathsResponse, error)
+	GetLearningPathBySlug(slug string) (*dto.LearningPathDetail, error)
+	FollowLearningPath(userId uint, slug string) (*dto.LearningPathProgressResponse, error)
+	UnfollowLearningPath(userId uint, slug string) (*dto.UnfollowLearningPathResponse, error)
+	GetLearningPathProgress(userId uint, slug string) (*dto.LearningPathProgressResponse, error)
+	GetMyLearningPaths(userId uint) (*dto.GetMyLearningPathsResponse, error)
+	GetAdminLearningPaths(req *dto.GetAdminLearningPathsQueryRequest) (*dto.GetLearningPathsResponse, error)
+	GetAdminLearningPathDetail(pathId uint) (*dto.LearningPathDetail, error)
+	CreateLearningPath(adminId uint, req *dto.CreateLearningPathRequest) (*dto.LearningPathDetail, error)
+	UpdateLearningPath(pathId uint, req *dto.UpdateLearningPathRequest) (*dto.LearningPathDetail, error)
+	UpdateLearningPathCourses(pathId uint, req *dto.UpdateLearningPathCoursesRequest) (*dto.LearningPathDetail, error)
+	DeleteLearningPath(pathId uint) (*dto.DeleteLearningPathResponse, error)
+}
+
+type QuizService interface {
+	GetLessonQuiz(instructorId, courseId, lessonId uint) (*dto.InstructorQuizResponse, error)
+	UpsertLessonQuiz(instructorId, courseId, lessonId uint, req *dto.UpsertQuizRequest) (*dto.InstructorQuizResponse, error)
+	CreateQuestion(instructorId, courseId, lessonId uint, req *dto.SaveQuizQuestionRequest) (*dto.InstructorQuizResponse, error)
+	UpdateQuestion(instructorId, courseId, lessonId, questionId uint, req *dto.SaveQuizQuestionRequest) (*dto.InstructorQuizResponse, error)
+	DeleteQuestion(instructorId, courseId, lessonId, questionId uint) (*dto.DeleteQuizQuestionResponse, error)
+	GetQuizInfo(userId, lessonId uint) (*dto.QuizInfoResponse, error)
+	StartAttempt(userId, lessonId uint) (*dto.StartQuizAttemptResponse, error)
+	SubmitAttempt(userId, attemptId uint, req *dto.SubmitQuizAttemptRequest) (*dto.QuizAttemptResult, error)
+	GetAttempt(userId, attemptId uint) (*dto.QuizAttemptResult, error)
+	GetMyAttempts(userId, lessonId uint) (*dto.GetQuizAttemptsResponse, error)
+}
+
+type AssignmentService interface {
+	GetLessonAssignment(instructorId, courseId, lessonId uint) (*dto.InstructorAssignmentResponse, error)
+	UpsertLessonAssignment(instructorId, courseId, lessonId uint, req *dto.UpsertAssignmentRequest) (*dto.InstructorAssignmentResponse, error)
+	GetGradingQueue(instructorId uint, req *dto.GetGradingQueueQueryRequest) (*dto.GetGradingQueueResponse, error)
+	GetSubmissionForGrading(instructorId, submissionId uint) (*dto.AssignmentSubmissionDetail, error)
+	GradeSubmission(instructorId, submissionId uint, req *dto.GradeSubmissionRequest) (*dto.AssignmentSubmissionDetail, error)
+	GetInstructorSubmissionFile(instructorId, submissionId uint, fileId string) (*PrivateFile, error)
+	GetAssignmentInfo(userId, lessonId uint) (*dto.AssignmentInfoResponse, error)
+	SubmitAssignment(userId, lessonId uint, req *dto.SubmitAssignmentRequest, files []*multipart.FileHeader) (*dto.AssignmentSubmissionDetail, error)
+	GetMySubmissions(userId, lessonId uint) (*dto.GetMySubmissionsResponse, error)
+	GetMySubmission(userId, submissionId uint) (*dto.AssignmentSubmissionDetail, error)
+	GetMySubmissionFile(userId, submissionId uint, fileId string) (*PrivateFile, error)
+}
+
+type LessonResourceService interface {
+	GetInstructorResources(instructorId, courseId, lessonId uint) (*dto.GetLessonResourcesResponse, error)
+	UploadResource(instructorId, courseId, lessonId uint, req *dto.UploadLessonResourceRequest, file *multipart.FileHeader) (*dto.LessonResourceItem, error)
+	UpdateResource(instructorId, courseId, lessonId, resourceId uint, req *dto.UpdateLessonResourceRequest) (*dto.LessonResourceItem, error)
+	DeleteResource(instructorId, courseId, lessonId, resourceId uint) (*dto.DeleteLessonResourceResponse, error)
+	GetLessonResources(userId, lessonId uint) (*dto.GetLessonResourcesResponse, error)
+	GetResourceFile(userId, resourceId uint) (*PrivateFile, error)
+}
+
+type MediaService interface {
+	GetLessonMediaURL(userId, lessonId uint) (*dto.LessonMediaResponse, error)
+	ResolveLessonMedia(lessonId uint, query *dto.MediaStreamQuery) (*LessonMediaSource, error)
+}
+
+type VideoUploadService interface {
+	CreateUpload(instructorId, courseId, lessonId uint, req *dto.CreateVideoUploadRequest) (*dto.VideoUploadResponse, error)
+	GetUpload(instructorId uint, uploadId string) (*dto.VideoUploadResponse, error)
+	GetLessonUploads(instructorId, courseId, lessonId uint) (*dto.GetLessonVideoUploadsResponse, error)
+	UploadChunk(instructorId uint, uploadId string, req *dto.UploadVideoChunkRequest, body io.Reader) (*dto.VideoUploadResponse, error)
+	AbortUpload(instructorId uint, uploadId string) (*dto.VideoUploadResponse, error)
+	CleanupAbandonedUploads(ctx context.Context) error
+}
+
+type LessonSubtitleService interface {
+	GetInstructorSubtitles(instructorId, courseId, lessonId uint) (*dto.GetSubtitlesResponse, error)
+	UploadSubtitle(instructorId, courseId, lessonId uint, req *dto.UploadSubtitleRequest, file *multipart.FileHeader) (*dto.SubtitleItem, error)
+	UpdateSubtitle(instructorId, courseId, lessonId, subtitleId uint, req *dto.UpdateSubtitleRequest) (*dto.SubtitleItem, error)
+	DeleteSubtitle(instructorId, courseId, lessonId, subtitleId uint) (*dto.DeleteSubtitleResponse, error)
+	GetSubtitleTracks(userId, lessonId uint) (*dto.GetSubtitleTracksResponse, error)
+	GetTranscript(userId, lessonId uint, req *dto.TranscriptQueryRequest) (*dto.TranscriptResponse, error)
+	GetSubtitleFile(subtitleId uint, query *dto.MediaStreamQuery) ([]byte, error)
+}
+
+type ScormService interface {
+	GetInstructorPackage(instructorId, courseId, lessonId uint) (*dto.ScormPackageResponse, error)
+	UploadPackage(instructorId, courseId, lessonId uint, file *multipart.FileHeader) (*dto.ScormPackageResponse, error)
+	DeletePackage(instructorId, courseId, lessonId uint) (*dto.DeleteScormPackageResponse, error)
+	LaunchLesson(userId, lessonId uint) (*dto.ScormLaunchResponse, error)
+	GetPlayer(lessonId uint, query *dto.MediaStreamQuery) (*dto.ScormPlayer, error)
+	GetRuntime(lessonId uint, query *dto.MediaStreamQuery) (*dto.ScormRuntimeResponse, error)
+	CommitRuntime(lessonId uint, query *dto.MediaStreamQuery, req *dto.CommitScormRuntimeRequest) (*dto.ScormRuntimeResponse, error)
+	ResolveContent(lessonId uint, query *dto.MediaStreamQuery, filePath string) (*ScormContent, error)
+}
+
+type CertificateService interface {
+	GetMyCertificates(userId uint) (*dto.GetMyCertificatesResponse, error)
+	RenderCertificate(userId uint, serial string, w io.Writer) (string, error)
+	VerifyCertificate(serial string) (*dto.CertificateVerificationResponse, error)
+}
+
+type CoursePackageService interface {
+	ExportCourse(instructorId, courseId uint, w io.Writer) (string, error)
+	ImportCourse(instructorId uint, req *dto.ImportCoursePackageRequest, r io.ReaderAt, size int64) (*dto.ImportCoursePackageResponse, error)
+}
+
+type CourseModerationService interface {
+	SubmitForReview(instructorId, courseId uint, req *dto.SubmitCourseReviewRequest) (*dto.SubmitCourseReviewResponse, error)
+	GetCourseModerations(instructorId, courseId uint) (*dto.GetCourseModerationsResponse, error)
+	GetModerationQueue(req *dto.GetModerationQueueQueryRequest) (*dto.GetModerationQueueResponse, error)
+	GetModerationDetail(moderationId uint) (*dto.CourseModerationItem, error)
+	ApproveCourse(adminId, moderationId uint, req *dto.ApproveCourseRequest) (*dto.ReviewCourseResponse, error)
+	RejectCourse(adminId, moderationId uint, req *dto.RejectCourseRequest) (*dto.ReviewCourseResponse, error)
+}
+
+type PublishScheduleService interface {
+	RunDueSchedules(ctx context.Context) error
 }
 
 type ProgressService interface {
 	GetCourseProgress(userId, courseId uint) (*dto.GetCourseProgressResponse, error)
 	CompleteLesson(userId, lessonId uint, req *dto.CompleteLessonRequest) (*dto.CompleteLessonResponse, error)
 	UpdateLessonPosition(userId, lessonId uint, req *dto.UpdateLessonPositionRequest) (*dto.UpdateLessonPositionResponse, error)
+	RecordHeartbeat(userId, lessonId uint, req *dto.WatchHeartbeatRequest) (*dto.WatchHeartbeatResponse, error)
+	SyncProgress(userId uint, req *dto.SyncProgressRequest) (*dto.SyncProgressResponse, error)
 	updateEnrollmentProgress(userId, courseId uint) error
 }
 
+type LearningStatsService interface {
+	GetLearningStats(userId uint, req *dto.GetLearningStatsQueryRequest) (*dto.LearningStatsResponse, error)
+	UpdateLearningGoal(userId uint, req *dto.UpdateLearningGoalRequest) (*dto.LearningGoalResponse, error)
+	DeleteLearningGoal(userId uint) (*dto.DeleteLearningGoalResponse, error)
+	GetNotificationPreferences(userId uint) (*dto.NotificationPreferencesResponse, error)
+	UpdateNotificationPreferences(userId uint, req *dto.UpdateNotificationPreferencesRequest) (*dto.NotificationPreferencesResponse, error)
+	SendInactivityReminders(ctx context.Context) error
+}
+
+type ProgressRecalculationService interface {
+	RunPending(ctx context.Context) error
+}
+
 type OrderService interface {
 	CreateOrder(userId uint, req *dto.CreateOrderRequest) (*dto.CreateOrderResponse, error)
 	GetOrderHistory(userId uint, req *dto.GetOrderHistoryQueryRequest) (*dto.GetOrderHistoryResponse, error)
@@ -113,6 +274,7 @@ type AnalyticsService interface {
 	GetInstructorOverview(instructorId uint) (*dto.InstructorOverviewResponse, error)
 	GetRevenueAnalytics(instructorId uint, req *dto.RevenueAnalyticsRequest) (*dto.RevenueAnalyticsResponse, error)
 	GetStudentAnalytics(instructorId uint, req *dto.StudentAnalyticsRequest) (*dto.StudentAnalyticsResponse, error)
+	GetLessonEngagement(instructorId, courseId, lessonId uint) (*dto.LessonEngagementResponse, error)
 }
 
 type AdminAnalyticsService interface {
@@ -128,3 +290,15 @@ type PaymentService interface {
 	HandleZaloPayCallback(data map[string]interface{}) (*dto.PaymentCallbackResponse, error)
 	CheckPaymentStatus(userId uint, req *dto.CheckPaymentStatusRequest) (*dto.CheckPaymentStatusResponse, error)
 }
+
+type LessonNoteService interface {
+	GetLessonNotes(userId, lessonId uint) (*dto.GetLessonNotesResponse, error)
+	CreateNote(userId, lessonId uint, req *dto.CreateLessonNoteRequest) (*dto.LessonNoteItem, error)
+	UpdateNote(userId, noteId uint, req *dto.UpdateLessonNoteRequest) (*dto.LessonNoteItem, error)
+	DeleteNote(userId, noteId uint) (*dto.DeleteLessonNoteResponse, error)
+	SearchNotes(userId uint, req *dto.SearchNotesQueryRequest) (*dto.SearchNotesResponse, error)
+	ExportCourseNotes(userId, courseId uint) (string, []byte, error)
+	AddBookmark(userId, lessonId uint) (*dto.LessonBookmarkResponse, error)
+	RemoveBookmark(userId, lessonId uint) (*dto.LessonBookmarkResponse, error)
+	GetBookmarks(userId uint, req *dto.GetBookmarksQueryRequest) (*dto.GetBookmarksResponse, error)
+}
@@ -0,0 +1,519 @@
+package service
+
+import (
+	"context"
+	"lms/src/cache"
+	"lms/src/dto"
+	"lms/src/models"
+	"lms/src/repository"
+	"lms/src/utils"
+	"log"
+	"math"
+	"time"
+)
+
+type learningPathService struct {
+	learningPathRepo repository.LearningPathRepository
+	courseRepo       repository.CourseRepository
+}
+
+func NewLearningPathService(learningPathRepo repository.LearningPathRepository, courseRepo repository.CourseRepository) LearningPathService {
+	return &learningPathService{
+		learningPathRepo: learningPathRepo,
+		courseRepo:       courseRepo,
+	}
+}
+
+func (lps *learningPathService) GetLearningPaths(req *dto.GetLearningPathsQueryRequest) (*dto.GetLearningPathsResponse, error) {
+	// Public chỉ hiển thị learning paths đã published
+	filters := map[string]interface{}{
+		"is_published": true,
+	}
+	if req.Search != "" {
+		filters["search"] = req.Search
+	}
+
+	return lps.getLearningPaths(req.Page, req.Limit, filters)
+}
+
+func (lps *learningPathService) GetLearningPathBySlug(slug string) (*dto.LearningPathDetail, error) {
+	path, err := lps.learningPathRepo.FindBySlug(slug)
+	if err != nil || !path.IsPublished {
+		return nil, utils.NewError("Learning path not found", utils.ErrCodeNotFound)
+	}
+
+	return toLearningPathDetail(path, true), nil
+}
+
+func (lps *learningPathService) FollowLearningPath(userId uint, slug string) (*dto.LearningPathProgressResponse, error) {
+	// 1. Kiểm tra learning path có tồn tại và đã published không
+	path, err := lps.learningPathRepo.FindBySlug(slug)
+	if err != nil || !path.IsPublished {
+		return nil, utils.NewError("Learning path not found", utils.ErrCodeNotFound)
+	}
+
+	// 2. Kiểm tra user đã theo learning path chưa
+	userPath, err := lps.learningPathRepo.FindUserLearningPath(userId, path.Id)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to check learning path", utils.ErrCodeInternal)
+	}
+	if userPath != nil {
+		return nil, utils.NewError("You are already following this learning path", utils.ErrCodeConflict)
+	}
+
+	// 3. Tạo bản ghi theo dõi
+	userPath = &models.UserLearningPath{
+		UserId:         userId,
+		LearningPathId: path.Id,
+		StartedAt:      time.Now(),
+	}
+	if err := lps.learningPathRepo.CreateUserLearningPath(userPath); err != nil {
+		return nil, utils.WrapError(err, "Failed to follow learning path", utils.ErrCodeInternal)
+	}
+
+	// 4. Trả về tiến độ hiện tại (user có thể đã hoàn thành một số course trước đó)
+	return lps.buildProgress(userId, path, userPath)
+}
+
+func (lps *learningPathService) UnfollowLearningPath(userId uint, slug string) (*dto.UnfollowLearningPathResponse, error) {
+	path, err := lps.learningPathRepo.FindBySlug(slug)
+	if err != nil {
+		return nil, utils.NewError("Learning path not found", utils.ErrCodeNotFound)
+	}
+
+	userPath, err := lps.learningPathRepo.FindUserLearningPath(userId, path.Id)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to check learning path", utils.ErrCodeInternal)
+	}
+	if userPath == nil {
+		return nil, utils.NewError("You are not following this learning path", utils.ErrCodeNotFound)
+	}
+
+	if err := lps.learningPathRepo.DeleteUserLearningPath(userPath.Id); err != nil {
+		return nil, utils.WrapError(err, "Failed to unfollow learning path", utils.ErrCodeInternal)
+	}
+
+	return &dto.UnfollowLearningPathResponse{
+		Message:        "Learning path unfollowed successfully",
+		LearningPathId: path.Id,
+	}, nil
+}
+
+func (lps *learningPathService) GetLearningPathProgress(userId uint, slug string) (*dto.LearningPathProgressResponse, error) {
+	path, err := lps.learningPathRepo.FindBySlug(slug)
+	if err != nil || !path.IsPublished {
+		return nil, utils.NewError("Learning path not found", utils.ErrCodeNotFound)
+	}
+
+	// User chưa theo learning path vẫn xem được tiến độ (IsFollowing = false)
+	userPath, err := lps.learningPathRepo.FindUserLearningPath(userId, path.Id)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to check learning path", utils.ErrCodeInternal)
+	}
+
+	return lps.buildProgress(userId, path, userPath)
+}
+
+func (lps *learningPathService) GetMyLearningPaths(userId uint) (*dto.GetMyLearningPathsResponse, error) {
+	userPaths, err := lps.learningPathRepo.GetUserLearningPaths(userId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get learning paths", utils.ErrCodeInternal)
+	}
+
+	items := make([]dto.LearningPathProgressResponse, 0, len(userPaths))
+	for i := range userPaths {
+		userPath := &userPaths[i]
+
+		// Bỏ qua learning path đã bị xóa
+		if userPath.LearningPath.Id == 0 {
+			continue
+		}
+
+		progress, err := lps.buildProgress(userId, &userPath.LearningPath, userPath)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, *progress)
+	}
+
+	return &dto.GetMyLearningPathsResponse{
+		LearningPaths: items,
+		Total:         len(items),
+	}, nil
+}
+
+func (lps *learningPathService) GetAdminLearningPaths(req *dto.GetAdminLearningPathsQueryRequest) (*dto.GetLearningPathsResponse, error) {
+	filters := make(map[string]interface{})
+	if req.Search != "" {
+		filters["search"] = req.Search
+	}
+	if req.IsPublished != nil {
+		filters["is_published"] = *req.IsPublished
+	}
+
+	return lps.getLearningPaths(req.Page, req.Limit, filters)
+}
+
+func (lps *learningPathService) GetAdminLearningPathDetail(pathId uint) (*dto.LearningPathDetail, error) {
+	path, err := lps.learningPathRepo.FindById(pathId)
+	if err != nil {
+		return nil, utils.NewError("Learning path not found", utils.ErrCodeNotFound)
+	}
+
+	return toLearningPathDetail(path, false), nil
+}
+
+func (lps *learningPathService) CreateLearningPath(adminId uint, req *dto.CreateLearningPathRequest) (*dto.LearningPathDetail, error) {
+	// 1. Validate danh sách courses
+	if err := lps.validatePathCourses(req.CourseIds); err != nil {
+		return nil, err
+	}
+
+	// 2. Generate unique slug
+	baseSlug := utils.GenerateSlug(req.Title)
+	uniqueSlug := utils.GenerateUniqueSlug(baseSlug, func(slug string) bool {
+		return lps.learningPathRepo.ExistsBySlug(slug, 0)
+	})
+
+	// 3. Tạo learning path
+	path := &models.LearningPath{
+		Title:        req.Title,
+		Slug:         uniqueSlug,
+		Description:  req.Description,
+		ShortDesc:    req.ShortDesc,
+		ThumbnailURL: req.ThumbnailURL,
+		IsPublished:  req.IsPublished,
+		CreatedBy:    adminId,
+	}
+	if err := lps.learningPathRepo.Create(path); err != nil {
+		return nil, utils.WrapError(err, "Failed to create learning path", utils.ErrCodeInternal)
+	}
+
+	// 4. Gắn courses theo thứ tự
+	if len(req.CourseIds) > 0 {
+		if err := lps.learningPathRepo.ReplaceCourses(path.Id, req.CourseIds); err != nil {
+			return nil, utils.WrapError(err, "Failed to add courses to learning path", utils.ErrCodeInternal)
+		}
+	}
+
+	invalidateLearningPathCache(context.Background())
+
+	return lps.GetAdminLearningPathDetail(path.Id)
+}
+
+func (lps *learningPathService) UpdateLearningPath(pathId uint, req *dto.UpdateLearningPathRequest) (*dto.LearningPathDetail, error) {
+	// 1. Kiểm tra learning path có tồn tại không
+	path, err := lps.learningPathRepo.FindById(pathId)
+	if err != nil {
+		return nil, utils.NewError("Learning path not found", utils.ErrCodeNotFound)
+	}
+
+	// 2. Chuẩn bị dữ liệu update
+	updates := make(map[string]interface{})
+
+	if req.Title != nil && *req.Title != path.Title {
+		updates["title"] = *req.Title
+
+		// Title thay đổi thì tạo lại slug
+		baseSlug := utils.GenerateSlug(*req.Title)
+		updates["slug"] = utils.GenerateUniqueSlug(baseSlug, func(slug string) bool {
+			return lps.learningPathRepo.ExistsBySlug(slug, pathId)
+		})
+	}
+
+	if req.Description != nil {
+		updates["description"] = *req.Description
+	}
+
+	if req.ShortDesc != nil {
+		updates["short_desc"] = *req.ShortDesc
+	}
+
+	if req.ThumbnailURL != nil {
+		updates["thumbnail_url"] = *req.ThumbnailURL
+	}
+
+	if req.IsPublished != nil {
+		updates["is_published"] = *req.IsPublished
+	}
+
+	// 3. Thực hiện update
+	if len(updates) > 0 {
+		if err := lps.learningPathRepo.Update(pathId, updates); err != nil {
+			return nil, utils.WrapError(err, "Failed to update learning path", utils.ErrCodeInternal)
+		}
+		invalidateLearningPathCache(context.Background())
+	}
+
+	return lps.GetAdminLearningPathDetail(pathId)
+}
+
+func (lps *learningPathService) UpdateLearningPathCourses(pathId uint, req *dto.UpdateLearningPathCoursesRequest) (*dto.LearningPathDetail, error) {
+	// 1. Kiểm tra learning path có tồn tại không
+	if _, err := lps.learningPathRepo.FindById(pathId); err != nil {
+		return nil, utils.NewError("Learning path not found", utils.ErrCodeNotFound)
+	}
+
+	// 2. Validate danh sách courses
+	if err := lps.validatePathCourses(req.CourseIds); err != nil {
+		return nil, err
+	}
+
+	// 3. Thay toàn bộ danh sách courses theo thứ tự mới
+	if err := lps.learningPathRepo.ReplaceCourses(pathId, req.CourseIds); err != nil {
+		return nil, utils.WrapError(err, "Failed to update learning path courses", utils.ErrCodeInternal)
+	}
+
+	invalidateLearningPathCache(context.Background())
+
+	return lps.GetAdminLearningPathDetail(pathId)
+}
+
+func (lps *learningPathService) DeleteLearningPath(pathId uint) (*dto.DeleteLearningPathResponse, error) {
+	if _, err := lps.learningPathRepo.FindById(pathId); err != nil {
+		return nil, utils.NewError("Learning path not found", utils.ErrCodeNotFound)
+	}
+
+	if err := lps.learningPathRepo.Delete(pathId); err != nil {
+		return nil, utils.WrapError(err, "Failed to delete learning path", utils.ErrCodeInternal)
+	}
+
+	invalidateLearningPathCache(context.Background())
+
+	return &dto.DeleteLearningPathResponse{
+		Message:        "Learning path deleted successfully",
+		LearningPathId: pathId,
+	}, nil
+}
+
+func (lps *learningPathService) getLearningPaths(page, limit int, filters map[string]interface{}) (*dto.GetLearningPathsResponse, error) {
+	// Set default values
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 {
+		limit = 12
+	}
+
+	offset := (page - 1) * limit
+
+	paths, total, err := lps.learningPathRepo.GetLearningPaths(offset, limit, filters)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get learning paths", utils.ErrCodeInternal)
+	}
+
+	items := make([]dto.LearningPathItem, len(paths))
+	for i := range paths {
+		items[i] = toLearningPathItem(&paths[i])
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+
+	return &dto.GetLearningPathsResponse{
+		LearningPaths: items,
+		Pagination: dto.PaginationInfo{
+			Page:       page,
+			Limit:      limit,
+			Total:      total,
+			TotalPages: totalPages,
+			HasNext:    page < totalPages,
+			HasPrev:    page > 1,
+		},
+	}, nil
+}
+
+// validatePathCourses kiểm tra courses tồn tại, đã published và không bị trùng
+func (lps *learningPathService) validatePathCourses(courseIds []uint) error {
+	seen := make(map[uint]bool)
+	for _, courseId := range courseIds {
+		if seen[courseId] {
+			return utils.NewError("A course can only appear once in a learning path", utils.ErrCodeBadRequest)
+		}
+		seen[courseId] = true
+
+		course, err := lps.courseRepo.FindById(courseId)
+		if err != nil {
+			return utils.NewError("Course not found", utils.ErrCodeNotFound)
+		}
+
+		if course.Status != "published" {
+			return utils.NewError("Course \""+course.Title+"\" is not published", utils.ErrCodeBadRequest)
+		}
+	}
+
+	return nil
+}
+
+// buildProgress tính bundle progress từ enrollments của user trên các course published trong path
+func (lps *learningPathService) buildProgress(userId uint, path *models.LearningPath, userPath *models.UserLearningPath) (*dto.LearningPathProgressResponse, error) {
+	pathCourses := publishedPathCourses(path)
+
+	courseIds := make([]uint, len(pathCourses))
+	for i, pathCourse := range pathCourses {
+		courseIds[i] = pathCourse.CourseId
+	}
+
+	enrollments, err := lps.learningPathRepo.GetUserEnrollments(userId, courseIds)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get learning path progress", utils.ErrCodeInternal)
+	}
+
+	enrollmentMap := make(map[uint]models.Enrollment)
+	for _, enrollment := range enrollments {
+		enrollmentMap[enrollment.CourseId] = enrollment
+	}
+
+	// 1. Tiến độ từng course, course đã completed tính 100%
+	courses := make([]dto.LearningPathCourseProgress, len(pathCourses))
+	completedCourses := 0
+	totalProgress := 0.0
+	var nextCourse *dto.LearningPathCourseProgress
+
+	for i, pathCourse := range pathCourses {
+		item := dto.LearningPathCourseProgress{
+			CourseId:     pathCourse.CourseId,
+			CourseOrder:  pathCourse.CourseOrder,
+			Title:        pathCourse.Course.Title,
+			Slug:         pathCourse.Course.Slug,
+			ThumbnailURL: pathCourse.Course.ThumbnailURL,
+		}
+
+		if enrollment, ok := enrollmentMap[pathCourse.CourseId]; ok {
+			item.IsEnrolled = true
+			item.EnrollmentStatus = enrollment.Status
+			item.ProgressPercentage = enrollment.ProgressPercentage
+			item.CompletedAt = enrollment.CompletedAt
+
+			if enrollment.Status == "completed" {
+				item.ProgressPercentage = 100
+				completedCourses++
+			}
+		}
+
+		totalProgress += item.ProgressPercentage
+		courses[i] = item
+
+		if nextCourse == nil && item.EnrollmentStatus != "completed" {
+			nextCourse = &courses[i]
+		}
+	}
+
+	progressPercentage := 0.0
+	if len(pathCourses) > 0 {
+		progressPercentage = math.Round(totalProgress/float64(len(pathCourses))*100) / 100
+	}
+
+	response := &dto.LearningPathProgressResponse{
+		LearningPathId:     path.Id,
+		Title:              path.Title,
+		Slug:               path.Slug,
+		TotalCourses:       len(pathCourses),
+		CompletedCourses:   completedCourses,
+		ProgressPercentage: progressPercentage,
+		Courses:            courses,
+	}
+
+	if nextCourse != nil {
+		next := *nextCourse
+		response.NextCourse = &next
+	}
+
+	// 2. Đồng bộ completed_at của user path (path có thể được thêm course mới sau khi hoàn thành)
+	if userPath != nil {
+		isCompleted := len(pathCourses) > 0 && completedCourses == len(pathCourses)
+
+		if isCompleted && userPath.CompletedAt == nil {
+			now := time.Now()
+			if err := lps.learningPathRepo.UpdateUserLearningPath(userPath.Id, map[string]interface{}{"completed_at": now}); err != nil {
+				return nil, utils.WrapError(err, "Failed to update learning path progress", utils.ErrCodeInternal)
+			}
+			userPath.CompletedAt = &now
+		} else if !isCompleted && userPath.CompletedAt != nil {
+			if err := lps.learningPathRepo.UpdateUserLearningPath(userPath.Id, map[string]interface{}{"completed_at": nil}); err != nil {
+				return nil, utils.WrapError(err, "Failed to update learning path progress", utils.ErrCodeInternal)
+			}
+			userPath.CompletedAt = nil
+		}
+
+		response.IsFollowing = true
+		response.StartedAt = &userPath.StartedAt
+		response.CompletedAt = userPath.CompletedAt
+	}
+
+	return response, nil
+}
+
+// publishedPathCourses chỉ giữ lại các course còn published (course có thể bị archive sau khi thêm vào path)
+func publishedPathCourses(path *models.LearningPath) []models.LearningPathCourse {
+	pathCourses := make([]models.LearningPathCourse, 0, len(path.Courses))
+	for _, pathCourse := range path.Courses {
+		if pathCourse.Course.Status == "published" {
+			pathCourses = append(pathCourses, pathCourse)
+		}
+	}
+	return pathCourses
+}
+
+func toLearningPathItem(path *models.LearningPath) dto.LearningPathItem {
+	return dto.LearningPathItem{
+		Id:           path.Id,
+		Title:        path.Title,
+		Slug:         path.Slug,
+		ShortDesc:    path.ShortDesc,
+		ThumbnailURL: path.ThumbnailURL,
+		IsPublished:  path.IsPublished,
+		TotalCourses: len(path.Courses),
+		CreatedAt:    path.CreatedAt,
+		UpdatedAt:    path.UpdatedAt,
+	}
+}
+
+func toLearningPathDetail(path *models.LearningPath, publishedOnly bool) *dto.LearningPathDetail {
+	pathCourses := path.Courses
+	if publishedOnly {
+		pathCourses = publishedPathCourses(path)
+	}
+
+	totalDuration := 0
+	courses := make([]dto.LearningPathCourseItem, len(pathCourses))
+	for i, pathCourse := range pathCourses {
+		course := pathCourse.Course
+		totalDuration += course.DurationHours
+
+		courses[i] = dto.LearningPathCourseItem{
+			CourseId:       pathCourse.CourseId,
+			CourseOrder:    pathCourse.CourseOrder,
+			Title:          course.Title,
+			Slug:           course.Slug,
+			ShortDesc:      course.ShortDesc,
+			ThumbnailURL:   course.ThumbnailURL,
+			Level:          course.Level,
+			Price:          course.Price,
+			DiscountPrice:  course.DiscountPrice,
+			DurationHours:  course.DurationHours,
+			InstructorName: course.Instructor.FullName,
+			Status:         course.Status,
+		}
+	}
+
+	item := toLearningPathItem(path)
+	item.TotalCourses = len(courses)
+
+	return &dto.LearningPathDetail{
+		LearningPathItem:   item,
+		Description:        path.Description,
+		TotalDurationHours: totalDuration,
+		Courses:            courses,
+	}
+}
+
+// invalidateLearningPathCache xóa các response learning path đã cache bởi CacheMiddleware
+func invalidateLearningPathCache(ctx context.Context) {
+	if cache.RedisClient == nil {
+		return
+	}
+
+	if err := cache.DeletePattern(ctx, "cache:/api/v1/learning-paths*"); err != nil {
+		log.Printf("⚠️ Không thể xóa cache pattern cache:/api/v1/learning-paths*: %v", err)
+	}
+}
@@ -0,0 +1,330 @@
+package service
+
+import (
+	"context"
+	"lms/src/dto"
+	"lms/src/models"
+	"lms/src/repository"
+	"lms/src/utils"
+	"log"
+	"time"
+)
+
+const (
+	learningDateLayout     = "2006-01-02"
+	learningStatsDays      = 30
+	reminderBatchSize      = 500
+	learningGoalLessons    = "lessons"
+	defaultReminderEnabled = true // User chưa lưu tùy chọn thì nhận email nhắc học
+)
+
+type learningStatsService struct {
+	activityRepo repository.LearningActivityRepository
+	emailService EmailService
+}
+
+func NewLearningStatsService(activityRepo repository.LearningActivityRepository, emailService EmailService) LearningStatsService {
+	return &learningStatsService{
+		activityRepo: activityRepo,
+		emailService: emailService,
+	}
+}
+
+// learningLocation - múi giờ dùng để chia ngày học (streak, mục tiêu tuần)
+func learningLocation() *time.Location {
+	loc, err := time.LoadLocation(utils.GetEnv("LEARNING_TIMEZONE", "Asia/Ho_Chi_Minh"))
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// learningDate - ngày (theo LEARNING_TIMEZONE) của thời điểm t, biểu diễn bằng 00:00 UTC để lưu vào cột date
+func learningDate(t time.Time) time.Time {
+	year, month, day := t.In(learningLocation()).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// reminderInactiveDays - số ngày không truy cập enrollment trước khi gửi email nhắc (0 = tắt)
+func reminderInactiveDays() int {
+	return max(utils.GetEnvInt("LEARNING_REMINDER_INACTIVE_DAYS", 3), 0)
+}
+
+// learningActivityLog gom hoạt động theo ngày trước khi ghi (sync offline có thể trải trên nhiều ngày)
+type learningActivityLog map[time.Time]*models.LearningActivity
+
+func (l learningActivityLog) add(at time.Time, watchSeconds, lessonsCompleted int) {
+	if watchSeconds <= 0 && lessonsCompleted <= 0 {
+		return
+	}
+
+	date := learningDate(at)
+	activity, exists := l[date]
+	if !exists {
+		activity = &models.LearningActivity{ActivityDate: date}
+		l[date] = activity
+	}
+	activity.WatchSeconds += watchSeconds
+	activity.LessonsCompleted += lessonsCompleted
+}
+
+// recordLearningActivity ghi hoạt động học, lỗi chỉ ghi log vì thống kê không được làm hỏng thao tác học
+func recordLearningActivity(activityRepo repository.LearningActivityRepository, userId uint, activities learningActivityLog) {
+	for date, activity := range activities {
+		if err := activityRepo.RecordActivity(userId, date, activity.WatchSeconds, activity.LessonsCompleted); err != nil {
+			log.Printf("Failed to record learning activity for user %d: %v", userId, err)
+		}
+	}
+}
+
+func (ls *learningStatsService) GetLearningStats(userId uint, req *dto.GetLearningStatsQueryRequest) (*dto.LearningStatsResponse, error) {
+	days := learningStatsDays
+	if req.Days > 0 {
+		days = req.Days
+	}
+
+	// 1. Khoảng ngày cần lấy: daily_activity và tuần hiện tại (tuần bắt đầu từ thứ Hai)
+	today := learningDate(time.Now())
+	from := today.AddDate(0, 0, -(days - 1))
+	weekStart := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+	queryFrom := from
+	if weekStart.Before(queryFrom) {
+		queryFrom = weekStart
+	}
+
+	activities, err := ls.activityRepo.GetActivities(userId, queryFrom, today)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get learning activity", utils.ErrCodeInternal)
+	}
+	byDate := make(map[string]models.LearningActivity, len(activities))
+	for _, activity := range activities {
+		byDate[activity.ActivityDate.Format(learningDateLayout)] = activity
+	}
+
+	// 2. Hoạt động từng ngày
+	daily := make([]dto.DailyLearningActivity, 0, days)
+	for date := from; !date.After(today); date = date.AddDate(0, 0, 1) {
+		key := date.Format(learningDateLayout)
+		activity := byDate[key]
+		daily = append(daily, dto.DailyLearningActivity{
+			Date:             key,
+			Minutes:          activity.WatchSeconds / 60,
+			LessonsCompleted: activity.LessonsCompleted,
+		})
+	}
+
+	// 3. Streak tính trên toàn bộ các ngày có hoạt động
+	activeDates, err := ls.activityRepo.GetActiveDates(userId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get learning activity", utils.ErrCodeInternal)
+	}
+	currentStreak, longestStreak := learningStreaks(activeDates, today)
+
+	totalSeconds, totalLessons, err := ls.activityRepo.GetTotals(userId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get learning activity", utils.ErrCodeInternal)
+	}
+
+	response := &dto.LearningStatsResponse{
+		Timezone:              learningLocation().String(),
+		CurrentStreak:         currentStreak,
+		LongestStreak:         longestStreak,
+		ActiveDays:            len(activeDates),
+		TotalMinutes:          totalSeconds / 60,
+		TotalLessonsCompleted: totalLessons,
+		DailyActivity:         daily,
+	}
+	if len(activeDates) > 0 {
+		lastActive := activeDates[0].Format(learningDateLayout)
+		response.LastActiveDate = &lastActive
+	}
+
+	// 4. Tiến độ mục tiêu tuần
+	goal, err := ls.activityRepo.FindGoal(userId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get learning goal", utils.ErrCodeInternal)
+	}
+	if goal != nil {
+		weekSeconds, weekLessons := 0, 0
+		for _, activity := range activities {
+			if !activity.ActivityDate.Before(weekStart) {
+				weekSeconds += activity.WatchSeconds
+				weekLessons += activity.LessonsCompleted
+			}
+		}
+
+		progress := weekSeconds / 60
+		if goal.GoalType == learningGoalLessons {
+			progress = weekLessons
+		}
+		response.WeeklyGoal = &dto.WeeklyGoalProgress{
+			GoalType:   goal.GoalType,
+			Target:     goal.Target,
+			Progress:   progress,
+			Percentage: min(float64(progress)/float64(goal.Target)*100, 100),
+			Achieved:   progress >= goal.Target,
+			WeekStart:  weekStart.Format(learningDateLayout),
+			WeekEnd:    weekStart.AddDate(0, 0, 6).Format(learningDateLayout),
+		}
+	}
+
+	return response, nil
+}
+
+// learningStreaks - dates sắp xếp mới nhất trước. Streak hiện tại vẫn giữ nếu hôm nay chưa học nhưng hôm qua có học
+func learningStreaks(dates []time.Time, today time.Time) (int, int) {
+	current, longest, run := 0, 0, 0
+	for i, date := range dates {
+		if i > 0 && dateOnly(dates[i-1]).AddDate(0, 0, -1).Equal(dateOnly(date)) {
+			run++
+		} else {
+			run = 1
+		}
+		longest = max(longest, run)
+
+		// Chuỗi mới nhất là các ngày liên tiếp tính từ dates[0]
+		if run == i+1 {
+			current = run
+		}
+	}
+
+	if len(dates) == 0 || dateOnly(dates[0]).Before(today.AddDate(0, 0, -1)) {
+		current = 0
+	}
+	return current, longest
+}
+
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func (ls *learningStatsService) UpdateLearningGoal(userId uint, req *dto.UpdateLearningGoalRequest) (*dto.LearningGoalResponse, error) {
+	goal := &models.LearningGoal{
+		UserId:   userId,
+		GoalType: req.GoalType,
+		Target:   req.Target,
+	}
+	if err := ls.activityRepo.SaveGoal(goal); err != nil {
+		return nil, utils.WrapError(err, "Failed to save learning goal", utils.ErrCodeInternal)
+	}
+
+	return &dto.LearningGoalResponse{
+		GoalType:  goal.GoalType,
+		Target:    goal.Target,
+		UpdatedAt: goal.UpdatedAt,
+	}, nil
+}
+
+func (ls *learningStatsService) DeleteLearningGoal(userId uint) (*dto.DeleteLearningGoalResponse, error) {
+	deleted, err := ls.activityRepo.DeleteGoal(userId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to delete learning goal", utils.ErrCodeInternal)
+	}
+	if !deleted {
+		return nil, utils.NewError("Learning goal not found", utils.ErrCodeNotFound)
+	}
+
+	return &dto.DeleteLearningGoalResponse{
+		Message: "Learning goal deleted successfully",
+	}, nil
+}
+
+func (ls *learningStatsService) GetNotificationPreferences(userId uint) (*dto.NotificationPreferencesResponse, error) {
+	preference, err := ls.activityRepo.FindNotificationPreference(userId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get notification preferences", utils.ErrCodeInternal)
+	}
+
+	learningReminders := defaultReminderEnabled
+	if preference != nil {
+		learningReminders = preference.LearningReminders
+	}
+
+	return &dto.NotificationPreferencesResponse{
+		LearningReminders: learningReminders,
+		ReminderAfterDays: reminderInactiveDays(),
+	}, nil
+}
+
+func (ls *learningStatsService) UpdateNotificationPreferences(userId uint, req *dto.UpdateNotificationPreferencesRequest) (*dto.NotificationPreferencesResponse, error) {
+	preference := &models.NotificationPreference{
+		UserId:            userId,
+		LearningReminders: *req.LearningReminders,
+	}
+	if err := ls.activityRepo.SaveNotificationPreference(preference); err != nil {
+		return nil, utils.WrapError(err, "Failed to save notification preferences", utils.ErrCodeInternal)
+	}
+
+	return &dto.NotificationPreferencesResponse{
+		LearningReminders: preference.LearningReminders,
+		ReminderAfterDays: reminderInactiveDays(),
+	}, nil
+}
+
+// SendInactivityReminders gửi email nhắc học cho enrollment active không truy cập trong N ngày.
+// Mỗi đợt vắng mặt chỉ nhắc một lần, các khóa học của cùng user gộp vào một email
+func (ls *learningStatsService) SendInactivityReminders(ctx context.Context) error {
+	inactiveDays := reminderInactiveDays()
+	if inactiveDays == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	cutoff := now.AddDate(0, 0, -inactiveDays)
+	sent := 0
+
+	for ctx.Err() == nil {
+		// 1. Lấy enrollment cần nhắc
+		enrollments, err := ls.activityRepo.GetInactiveEnrollments(cutoff, reminderBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(enrollments) == 0 {
+			break
+		}
+
+		// 2. Đánh dấu đã nhắc trước khi gửi để replica khác không gửi trùng
+		ids := make([]uint, len(enrollments))
+		for i, enrollment := range enrollments {
+			ids[i] = enrollment.Id
+		}
+		claimedIds, err := ls.activityRepo.ClaimReminders(ids, cutoff, now)
+		if err != nil {
+			return err
+		}
+		claimed := make(map[uint]bool, len(claimedIds))
+		for _, id := range claimedIds {
+			claimed[id] = true
+		}
+
+		// 3. Gộp theo user (enrollments đã sắp xếp theo user_id)
+		for start := 0; start < len(enrollments); {
+			end := start
+			var courseTitles []string
+			for ; end < len(enrollments) && enrollments[end].UserId == enrollments[start].UserId; end++ {
+				if claimed[enrollments[end].Id] {
+					courseTitles = append(courseTitles, enrollments[end].Course.Title)
+				}
+			}
+
+			user := enrollments[start].User
+			if len(courseTitles) > 0 {
+				if err := ls.emailService.SendLearningReminderEmail(user.Email, user.FullName, courseTitles, inactiveDays); err != nil {
+					log.Printf("Failed to send learning reminder to user %d: %v", user.Id, err)
+				} else {
+					sent++
+				}
+			}
+			start = end
+		}
+
+		if len(enrollments) < reminderBatchSize {
+			break
+		}
+	}
+
+	if sent > 0 {
+		log.Printf("📧 Sent %d learning reminder email(s)", sent)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata" // Không phụ thuộc tz database của máy chạy test
+)
+
+func TestLearningDate(t *testing.T) {
+	t.Setenv("LEARNING_TIMEZONE", "Asia/Ho_Chi_Minh")
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want time.Time
+	}{
+		{"UTC evening is next day in Vietnam", time.Date(2024, 5, 1, 17, 0, 0, 0, time.UTC), time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)},
+		{"one second before Vietnam midnight", time.Date(2024, 5, 1, 16, 59, 59, 0, time.UTC), time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)},
+		{"UTC early morning", time.Date(2024, 5, 1, 2, 0, 0, 0, time.UTC), time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)},
+		{"other offset", time.Date(2024, 5, 1, 23, 30, 0, 0, time.FixedZone("EST", -5*3600)), time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)},
+		{"new year boundary", time.Date(2023, 12, 31, 17, 0, 0, 0, time.UTC), time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := learningDate(tt.at); !got.Equal(tt.want) || got.Location() != time.UTC {
+				t.Errorf("learningDate(%v) = %v, want %v", tt.at, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLearningStreaks(t *testing.T) {
+	today := time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC)
+	day := func(offset int) time.Time {
+		return today.AddDate(0, 0, offset)
+	}
+
+	tests := []struct {
+		name        string
+		dates       []time.Time
+		wantCurrent int
+		wantLongest int
+	}{
+		{"no activity", nil, 0, 0},
+		{"today only", []time.Time{day(0)}, 1, 1},
+		{"yesterday only keeps streak", []time.Time{day(-1)}, 1, 1},
+		{"two days ago breaks streak", []time.Time{day(-2)}, 0, 1},
+		{"consecutive days up to today", []time.Time{day(0), day(-1), day(-2)}, 3, 3},
+		{"consecutive days up to yesterday", []time.Time{day(-1), day(-2), day(-3)}, 3, 3},
+		{"gap breaks current streak", []time.Time{day(0), day(-1), day(-3), day(-4), day(-5)}, 2, 3},
+		{"older longer streak", []time.Time{day(-1), day(-10), day(-11), day(-12), day(-13)}, 1, 4},
+		{"old streak only", []time.Time{day(-5), day(-6), day(-7)}, 0, 3},
+		{"dates with time part", []time.Time{day(0).Add(10 * time.Hour), day(-1).Add(23 * time.Hour)}, 2, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current, longest := learningStreaks(tt.dates, today)
+			if current != tt.wantCurrent || longest != tt.wantLongest {
+				t.Errorf("learningStreaks = (%d, %d), want (%d, %d)", current, longest, tt.wantCurrent, tt.wantLongest)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"lms/src/models"
+	"lms/src/repository"
+	"time"
+)
+
+const (
+	lockReasonUnlockDate      = "unlock_date"
+	lockReasonPreviousLesson  = "previous_lesson_incomplete"
+	lockMessageUnlockDate     = "This lesson is not available yet"
+	lockMessagePreviousLesson = "Complete the previous lesson to unlock this lesson"
+)
+
+// lessonLockState là trạng thái khóa của một lesson đối với một học viên
+type lessonLockState struct {
+	IsLocked bool
+	UnlockAt *time.Time
+	Reason   string
+}
+
+// evaluateLessonLock áp dụng unlock rules của lesson (drip content).
+// Lesson preview không bao giờ bị khóa.
+func evaluateLessonLock(lesson *models.Lesson, enrolledAt time.Time, previousCompleted bool, now time.Time) lessonLockState {
+	if lesson.IsPreview {
+		return lessonLockState{}
+	}
+
+	// 1. Mở khóa sau N ngày kể từ ngày enroll
+	if lesson.UnlockAfterDays > 0 {
+		unlockAt := enrolledAt.AddDate(0, 0, lesson.UnlockAfterDays)
+		if now.Before(unlockAt) {
+			return lessonLockState{
+				IsLocked: true,
+				UnlockAt: &unlockAt,
+				Reason:   lockReasonUnlockDate,
+			}
+		}
+	}
+
+	// 2. Phải hoàn thành lesson trước đó
+	if lesson.RequirePreviousCompletion && !previousCompleted {
+		return lessonLockState{
+			IsLocked: true,
+			Reason:   lockReasonPreviousLesson,
+		}
+	}
+
+	return lessonLockState{}
+}
+
+// resolveLessonLock tính trạng thái khóa cho một lesson, tự lấy progress của lesson trước nếu cần
+func resolveLessonLock(lessonRepo repository.LessonRepository, userId uint, lesson *models.Lesson, enrolledAt time.Time) (lessonLockState, error) {
+	previousCompleted := true
+
+	if lesson.RequirePreviousCompletion && !lesson.IsPreview {
+		previousLesson, err := lessonRepo.GetPreviousLesson(lesson.CourseId, lesson.LessonOrder)
+		if err != nil {
+			return lessonLockState{}, err
+		}
+
+		if previousLesson != nil {
+			progress, err := lessonRepo.GetLessonProgressDetail(userId, previousLesson.Id)
+			if err != nil {
+				return lessonLockState{}, err
+			}
+			previousCompleted = progress.IsCompleted
+		}
+	}
+
+	return evaluateLessonLock(lesson, enrolledAt, previousCompleted, time.Now()), nil
+}
+
+func lessonLockMessage(reason string) string {
+	if reason == lockReasonPreviousLesson {
+		return lockMessagePreviousLesson
+	}
+	return lockMessageUnlockDate
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"lms/src/models"
+	"testing"
+	"time"
+)
+
+func TestEvaluateLessonLock(t *testing.T) {
+	enrolledAt := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	unlockAt := enrolledAt.AddDate(0, 0, 7)
+
+	tests := []struct {
+		name              string
+		lesson            models.Lesson
+		previousCompleted bool
+		now               time.Time
+		wantLocked        bool
+		wantReason        string
+		wantUnlockAt      *time.Time
+	}{
+		{"no unlock rules", models.Lesson{}, false, enrolledAt, false, "", nil},
+		{"before unlock date", models.Lesson{UnlockAfterDays: 7}, true, unlockAt.Add(-time.Second), true, lockReasonUnlockDate, &unlockAt},
+		{"exactly at unlock date", models.Lesson{UnlockAfterDays: 7}, true, unlockAt, false, "", nil},
+		{"after unlock date", models.Lesson{UnlockAfterDays: 7}, true, unlockAt.AddDate(0, 0, 1), false, "", nil},
+		{"previous lesson incomplete", models.Lesson{RequirePreviousCompletion: true}, false, enrolledAt, true, lockReasonPreviousLesson, nil},
+		{"previous lesson completed", models.Lesson{RequirePreviousCompletion: true}, true, enrolledAt, false, "", nil},
+		{"unlock date checked first", models.Lesson{UnlockAfterDays: 7, RequirePreviousCompletion: true}, false, enrolledAt, true, lockReasonUnlockDate, &unlockAt},
+		{"date passed but previous incomplete", models.Lesson{UnlockAfterDays: 7, RequirePreviousCompletion: true}, false, unlockAt, true, lockReasonPreviousLesson, nil},
+		{"preview never locked", models.Lesson{IsPreview: true, UnlockAfterDays: 7, RequirePreviousCompletion: true}, false, enrolledAt, false, "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := evaluateLessonLock(&tt.lesson, enrolledAt, tt.previousCompleted, tt.now)
+			if got.IsLocked != tt.wantLocked || got.Reason != tt.wantReason {
+				t.Errorf("lock = %v (%q), want %v (%q)", got.IsLocked, got.Reason, tt.wantLocked, tt.wantReason)
+			}
+			if (got.UnlockAt == nil) != (tt.wantUnlockAt == nil) || (got.UnlockAt != nil && !got.UnlockAt.Equal(*tt.wantUnlockAt)) {
+				t.Errorf("unlock at = %v, want %v", got.UnlockAt, tt.wantUnlockAt)
+			}
+		})
+	}
+}
+
+func TestResolveLessonLockUsesPreviousPublishedLesson(t *testing.T) {
+	lesson := func(id uint, order int, published bool) models.Lesson {
+		return models.Lesson{Id: id, CourseId: 1, LessonOrder: order, IsPublished: published, RequirePreviousCompletion: true}
+	}
+	enrolledAt := time.Now().AddDate(0, 0, -1)
+
+	tests := []struct {
+		name       string
+		lessons    []models.Lesson
+		completed  map[uint]bool
+		target     models.Lesson
+		wantLocked bool
+	}{
+		{"first lesson has no previous", []models.Lesson{lesson(1, 1, true)}, nil, lesson(1, 1, true), false},
+		{"previous incomplete", []models.Lesson{lesson(1, 1, true), lesson(2, 2, true)}, nil, lesson(2, 2, true), true},
+		{"previous completed", []models.Lesson{lesson(1, 1, true), lesson(2, 2, true)}, map[uint]bool{1: true}, lesson(2, 2, true), false},
+		{"unpublished lesson skipped", []models.Lesson{lesson(1, 1, true), lesson(2, 2, false), lesson(3, 3, true)}, map[uint]bool{1: true}, lesson(3, 3, true), false},
+		{"lesson in between still counts", []models.Lesson{lesson(1, 1, true), lesson(2, 2, true), lesson(3, 3, true)}, map[uint]bool{1: true}, lesson(3, 3, true), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeLessonRepo{lessons: tt.lessons, completed: tt.completed}
+			got, err := resolveLessonLock(repo, 7, &tt.target, enrolledAt)
+			if err != nil {
+				t.Fatalf("resolveLessonLock: %v", err)
+			}
+			if got.IsLocked != tt.wantLocked {
+				t.Errorf("locked = %v, want %v", got.IsLocked, tt.wantLocked)
+			}
+		})
+	}
+}
+
+func TestLessonLockMessage(t *testing.T) {
+	if got := lessonLockMessage(lockReasonPreviousLesson); got != lockMessagePreviousLesson {
+		t.Errorf("previous lesson message = %q", got)
+	}
+	if got := lessonLockMessage(lockReasonUnlockDate); got != lockMessageUnlockDate {
+		t.Errorf("unlock date message = %q", got)
+	}
+}
@@ -0,0 +1,374 @@
+package service
+
+import (
+	"fmt"
+	"lms/src/dto"
+	"lms/src/models"
+	"lms/src/repository"
+	"lms/src/utils"
+	"math"
+	"strings"
+	"time"
+)
+
+type lessonNoteService struct {
+	noteRepo       repository.LessonNoteRepository
+	lessonRepo     repository.LessonRepository
+	courseRepo     repository.CourseRepository
+	enrollmentRepo repository.EnrollmentRepository
+}
+
+func NewLessonNoteService(
+	noteRepo repository.LessonNoteRepository,
+	lessonRepo repository.LessonRepository,
+	courseRepo repository.CourseRepository,
+	enrollmentRepo repository.EnrollmentRepository,
+) LessonNoteService {
+	return &lessonNoteService{
+		noteRepo:       noteRepo,
+		lessonRepo:     lessonRepo,
+		courseRepo:     courseRepo,
+		enrollmentRepo: enrollmentRepo,
+	}
+}
+
+func (ns *lessonNoteService) GetLessonNotes(userId, lessonId uint) (*dto.GetLessonNotesResponse, error) {
+	// Ghi chú là dữ liệu riêng của user nên vẫn xem được khi enrollment đã hết hạn
+	notes, err := ns.noteRepo.GetLessonNotes(userId, lessonId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get lesson notes", utils.ErrCodeInternal)
+	}
+
+	isBookmarked, err := ns.noteRepo.IsBookmarked(userId, lessonId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get lesson bookmark", utils.ErrCodeInternal)
+	}
+
+	items := make([]dto.LessonNoteItem, len(notes))
+	for i := range notes {
+		items[i] = toLessonNoteItem(&notes[i])
+	}
+
+	return &dto.GetLessonNotesResponse{
+		LessonId:     lessonId,
+		IsBookmarked: isBookmarked,
+		Notes:        items,
+	}, nil
+}
+
+func (ns *lessonNoteService) CreateNote(userId, lessonId uint, req *dto.CreateLessonNoteRequest) (*dto.LessonNoteItem, error) {
+	// 1. Chỉ học viên đang có quyền học và lesson đã mở khóa mới ghi chú được
+	lesson, err := ns.checkStudentLesson(userId, lessonId)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Validate timestamp
+	if err := validateNoteTimestamp(lesson, req.VideoTimestamp); err != nil {
+		return nil, err
+	}
+
+	// 3. Tạo ghi chú
+	content := strings.TrimSpace(req.Content)
+	if content == "" {
+		return nil, utils.NewError("Note content is required", utils.ErrCodeBadRequest)
+	}
+
+	note := &models.LessonNote{
+		UserId:         userId,
+		LessonId:       lesson.Id,
+		CourseId:       lesson.CourseId,
+		Content:        content,
+		VideoTimestamp: req.VideoTimestamp,
+	}
+	if err := ns.noteRepo.CreateNote(note); err != nil {
+		return nil, utils.WrapError(err, "Failed to create note", utils.ErrCodeInternal)
+	}
+
+	item := toLessonNoteItem(note)
+	return &item, nil
+}
+
+func (ns *lessonNoteService) UpdateNote(userId, noteId uint, req *dto.UpdateLessonNoteRequest) (*dto.LessonNoteItem, error) {
+	// 1. Lấy ghi chú của user
+	note, err := ns.noteRepo.FindNote(noteId, userId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get note", utils.ErrCodeInternal)
+	}
+	if note == nil {
+		return nil, utils.NewError("Note not found", utils.ErrCodeNotFound)
+	}
+
+	// 2. Chuẩn bị dữ liệu cập nhật
+	updates := make(map[string]interface{})
+	if req.Content != "" {
+		content := strings.TrimSpace(req.Content)
+		if content == "" {
+			return nil, utils.NewError("Note content is required", utils.ErrCodeBadRequest)
+		}
+		updates["content"] = content
+		note.Content = content
+	}
+
+	if req.ClearTimestamp {
+		updates["video_timestamp"] = nil
+		note.VideoTimestamp = nil
+	} else if req.VideoTimestamp != nil {
+		lesson, err := ns.noteRepo.FindLessonById(note.LessonId)
+		if err != nil {
+			return nil, utils.NewError("Lesson not found", utils.ErrCodeNotFound)
+		}
+		if err := validateNoteTimestamp(lesson, req.VideoTimestamp); err != nil {
+			return nil, err
+		}
+		updates["video_timestamp"] = *req.VideoTimestamp
+		note.VideoTimestamp = req.VideoTimestamp
+	}
+
+	if len(updates) == 0 {
+		return nil, utils.NewError("No fields to update", utils.ErrCodeBadRequest)
+	}
+
+	// 3. Cập nhật
+	note.UpdatedAt = time.Now()
+	updates["updated_at"] = note.UpdatedAt
+	if err := ns.noteRepo.UpdateNote(note.Id, updates); err != nil {
+		return nil, utils.WrapError(err, "Failed to update note", utils.ErrCodeInternal)
+	}
+
+	item := toLessonNoteItem(note)
+	return &item, nil
+}
+
+func (ns *lessonNoteService) DeleteNote(userId, noteId uint) (*dto.DeleteLessonNoteResponse, error) {
+	note, err := ns.noteRepo.FindNote(noteId, userId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get note", utils.ErrCodeInternal)
+	}
+	if note == nil {
+		return nil, utils.NewError("Note not found", utils.ErrCodeNotFound)
+	}
+
+	if err := ns.noteRepo.DeleteNote(note.Id); err != nil {
+		return nil, utils.WrapError(err, "Failed to delete note", utils.ErrCodeInternal)
+	}
+
+	return &dto.DeleteLessonNoteResponse{
+		Message: "Note deleted successfully",
+	}, nil
+}
+
+// SearchNotes tìm ghi chú của user trên mọi course
+func (ns *lessonNoteService) SearchNotes(userId uint, req *dto.SearchNotesQueryRequest) (*dto.SearchNotesResponse, error) {
+	// Set defaults
+	page := 1
+	limit := 20
+
+	if req.Page > 0 {
+		page = req.Page
+	}
+	if req.Limit > 0 {
+		limit = req.Limit
+	}
+
+	offset := (page - 1) * limit
+
+	filters := make(map[string]interface{})
+	if req.CourseId > 0 {
+		filters["course_id"] = req.CourseId
+	}
+	if req.LessonId > 0 {
+		filters["lesson_id"] = req.LessonId
+	}
+
+	notes, total, err := ns.noteRepo.SearchNotes(userId, strings.TrimSpace(req.Search), filters, offset, limit)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to search notes", utils.ErrCodeInternal)
+	}
+
+	items := make([]dto.LessonNoteItem, len(notes))
+	for i := range notes {
+		items[i] = toLessonNoteItem(&notes[i])
+		items[i].CourseTitle = notes[i].Course.Title
+		items[i].LessonTitle = notes[i].Lesson.Title
+		items[i].LessonSlug = notes[i].Lesson.Slug
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+	return &dto.SearchNotesResponse{
+		Notes: items,
+		Pagination: dto.PaginationInfo{
+			Page:       page,
+			Limit:      limit,
+			Total:      total,
+			TotalPages: totalPages,
+			HasNext:    page < totalPages,
+			HasPrev:    page > 1,
+		},
+	}, nil
+}
+
+// ExportCourseNotes xuất toàn bộ ghi chú của user trong course thành Markdown, nhóm theo lesson
+func (ns *lessonNoteService) ExportCourseNotes(userId, courseId uint) (string, []byte, error) {
+	// 1. Kiểm tra course
+	course, err := ns.courseRepo.FindById(courseId)
+	if err != nil {
+		return "", nil, utils.NewError("Course not found", utils.ErrCodeNotFound)
+	}
+
+	// 2. Lấy ghi chú theo thứ tự lesson
+	notes, err := ns.noteRepo.GetCourseNotes(userId, courseId)
+	if err != nil {
+		return "", nil, utils.WrapError(err, "Failed to get notes", utils.ErrCodeInternal)
+	}
+
+	// 3. Render Markdown
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", course.Title)
+	fmt.Fprintf(&b, "_Notes exported on %s_\n", time.Now().In(learningLocation()).Format("2006-01-02 15:04"))
+
+	if len(notes) == 0 {
+		b.WriteString("\nNo notes yet.\n")
+	}
+
+	currentLesson := uint(0)
+	for _, note := range notes {
+		if note.LessonId != currentLesson {
+			currentLesson = note.LessonId
+			fmt.Fprintf(&b, "\n## %d. %s\n", note.Lesson.LessonOrder, note.Lesson.Title)
+		}
+
+		b.WriteString("\n")
+		if note.VideoTimestamp != nil {
+			fmt.Fprintf(&b, "**[%s]** ", formatVideoTimestamp(*note.VideoTimestamp))
+		}
+		b.WriteString(strings.TrimSpace(note.Content))
+		b.WriteString("\n")
+	}
+
+	fileName := fmt.Sprintf("notes-%s.md", course.Slug)
+	return fileName, []byte(b.String()), nil
+}
+
+func (ns *lessonNoteService) AddBookmark(userId, lessonId uint) (*dto.LessonBookmarkResponse, error) {
+	lesson, err := ns.checkStudentLesson(userId, lessonId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ns.noteRepo.AddBookmark(&models.LessonBookmark{
+		UserId:   userId,
+		LessonId: lesson.Id,
+		CourseId: lesson.CourseId,
+	}); err != nil {
+		return nil, utils.WrapError(err, "Failed to bookmark lesson", utils.ErrCodeInternal)
+	}
+
+	return &dto.LessonBookmarkResponse{
+		LessonId:     lesson.Id,
+		IsBookmarked: true,
+		Message:      "Lesson bookmarked successfully",
+	}, nil
+}
+
+func (ns *lessonNoteService) RemoveBookmark(userId, lessonId uint) (*dto.LessonBookmarkResponse, error) {
+	deleted, err := ns.noteRepo.DeleteBookmark(userId, lessonId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to remove bookmark", utils.ErrCodeInternal)
+	}
+	if !deleted {
+		return nil, utils.NewError("Bookmark not found", utils.ErrCodeNotFound)
+	}
+
+	return &dto.LessonBookmarkResponse{
+		LessonId:     lessonId,
+		IsBookmarked: false,
+		Message:      "Bookmark removed successfully",
+	}, nil
+}
+
+func (ns *lessonNoteService) GetBookmarks(userId uint, req *dto.GetBookmarksQueryRequest) (*dto.GetBookmarksResponse, error) {
+	filters := make(map[string]interface{})
+	if req.CourseId > 0 {
+		filters["course_id"] = req.CourseId
+	}
+
+	bookmarks, err := ns.noteRepo.GetBookmarks(userId, filters)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get bookmarks", utils.ErrCodeInternal)
+	}
+
+	items := make([]dto.LessonBookmarkItem, len(bookmarks))
+	for i, bookmark := range bookmarks {
+		items[i] = dto.LessonBookmarkItem{
+			LessonId:    bookmark.LessonId,
+			LessonTitle: bookmark.Lesson.Title,
+			LessonSlug:  bookmark.Lesson.Slug,
+			LessonOrder: bookmark.Lesson.LessonOrder,
+			CourseId:    bookmark.CourseId,
+			CourseTitle: bookmark.Course.Title,
+			CreatedAt:   bookmark.CreatedAt,
+		}
+	}
+
+	return &dto.GetBookmarksResponse{
+		Bookmarks: items,
+	}, nil
+}
+
+func (ns *lessonNoteService) checkStudentLesson(userId, lessonId uint) (*models.Lesson, error) {
+	lesson, err := ns.noteRepo.FindLessonById(lessonId)
+	if err != nil || !lesson.IsPublished {
+		return nil, utils.NewError("Lesson not found", utils.ErrCodeNotFound)
+	}
+
+	enrollment, isEnrolled := ns.enrollmentRepo.CheckEnrollment(userId, lesson.CourseId)
+	if !isEnrolled {
+		return nil, utils.NewError("You must enroll in this course to take notes", utils.ErrCodeForbidden)
+	}
+
+	lock, err := resolveLessonLock(ns.lessonRepo, userId, lesson, enrollment.EnrolledAt)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to check lesson lock", utils.ErrCodeInternal)
+	}
+	if lock.IsLocked {
+		return nil, utils.NewError(lessonLockMessage(lock.Reason), utils.ErrCodeForbidden)
+	}
+
+	return lesson, nil
+}
+
+// validateNoteTimestamp - timestamp chỉ dùng cho lesson video và không vượt quá độ dài video
+func validateNoteTimestamp(lesson *models.Lesson, timestamp *int) error {
+	if timestamp == nil {
+		return nil
+	}
+	if lesson.LessonType != "video" {
+		return utils.NewError("Video timestamp is only available for video lessons", utils.ErrCodeBadRequest)
+	}
+	if lesson.VideoDuration > 0 && *timestamp > lesson.VideoDuration {
+		return utils.NewError("Video timestamp exceeds the video duration", utils.ErrCodeBadRequest)
+	}
+	return nil
+}
+
+// formatVideoTimestamp - mm:ss hoặc h:mm:ss
+func formatVideoTimestamp(seconds int) string {
+	hours, minutes, secs := seconds/3600, seconds%3600/60, seconds%60
+	if hours > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", hours, minutes, secs)
+	}
+	return fmt.Sprintf("%02d:%02d", minutes, secs)
+}
+
+func toLessonNoteItem(note *models.LessonNote) dto.LessonNoteItem {
+	return dto.LessonNoteItem{
+		Id:             note.Id,
+		CourseId:       note.CourseId,
+		LessonId:       note.LessonId,
+		Content:        note.Content,
+		VideoTimestamp: note.VideoTimestamp,
+		CreatedAt:      note.CreatedAt,
+		UpdatedAt:      note.UpdatedAt,
+	}
+}
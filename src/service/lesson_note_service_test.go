@@ -0,0 +1,237 @@
+package service
+
+import (
+	"errors"
+	"lms/src/dto"
+	"lms/src/models"
+	"lms/src/repository"
+	"lms/src/utils"
+	"strings"
+	"testing"
+)
+
+// fakeNoteRepo lưu ghi chú trong bộ nhớ, FindNote chỉ trả ghi chú của đúng user
+type fakeNoteRepo struct {
+	repository.LessonNoteRepository
+	notes   map[uint]*models.LessonNote
+	lessons map[uint]*models.Lesson
+	updated map[uint]map[string]interface{}
+	deleted []uint
+}
+
+func (r *fakeNoteRepo) FindNote(noteId, userId uint) (*models.LessonNote, error) {
+	note, ok := r.notes[noteId]
+	if !ok || note.UserId != userId {
+		return nil, nil
+	}
+	n := *note
+	return &n, nil
+}
+
+func (r *fakeNoteRepo) FindLessonById(lessonId uint) (*models.Lesson, error) {
+	lesson, ok := r.lessons[lessonId]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	return lesson, nil
+}
+
+func (r *fakeNoteRepo) UpdateNote(noteId uint, updates map[string]interface{}) error {
+	r.updated[noteId] = updates
+	return nil
+}
+
+func (r *fakeNoteRepo) DeleteNote(noteId uint) error {
+	r.deleted = append(r.deleted, noteId)
+	return nil
+}
+
+func (r *fakeNoteRepo) GetCourseNotes(userId, courseId uint) ([]models.LessonNote, error) {
+	var notes []models.LessonNote
+	for id := uint(1); id <= uint(len(r.notes)); id++ {
+		if note := r.notes[id]; note.UserId == userId && note.CourseId == courseId {
+			notes = append(notes, *note)
+		}
+	}
+	return notes, nil
+}
+
+// fakeNoteCourseRepo trả course cho export
+type fakeNoteCourseRepo struct {
+	repository.CourseRepository
+	course *models.Course
+}
+
+func (r *fakeNoteCourseRepo) FindById(courseId uint) (*models.Course, error) {
+	if r.course == nil || r.course.Id != courseId {
+		return nil, errors.New("record not found")
+	}
+	return r.course, nil
+}
+
+func newFakeNoteRepo() *fakeNoteRepo {
+	videoLesson := &models.Lesson{Id: 10, CourseId: 1, Title: "Goroutines", LessonOrder: 1, LessonType: lessonTypeVideo, VideoDuration: 600, IsPublished: true}
+	textLesson := &models.Lesson{Id: 11, CourseId: 1, Title: "Channels", LessonOrder: 2, LessonType: "text", IsPublished: true}
+
+	return &fakeNoteRepo{
+		lessons: map[uint]*models.Lesson{10: videoLesson, 11: textLesson},
+		notes: map[uint]*models.LessonNote{
+			1: {Id: 1, UserId: 7, LessonId: 10, Lesson: *videoLesson, CourseId: 1, Content: "  go keyword  ", VideoTimestamp: ptrInt(75)},
+			2: {Id: 2, UserId: 7, LessonId: 10, Lesson: *videoLesson, CourseId: 1, Content: "whole lesson"},
+			3: {Id: 3, UserId: 7, LessonId: 11, Lesson: *textLesson, CourseId: 1, Content: "buffered"},
+			4: {Id: 4, UserId: 8, LessonId: 10, Lesson: *videoLesson, CourseId: 1, Content: "someone else"},
+		},
+		updated: make(map[uint]map[string]interface{}),
+	}
+}
+
+func TestValidateNoteTimestamp(t *testing.T) {
+	video := &models.Lesson{LessonType: lessonTypeVideo, VideoDuration: 600}
+	unknownDuration := &models.Lesson{LessonType: lessonTypeVideo}
+	text := &models.Lesson{LessonType: "text"}
+
+	tests := []struct {
+		name      string
+		lesson    *models.Lesson
+		timestamp *int
+		wantCode  utils.ErrorCode
+	}{
+		{"no timestamp on text lesson", text, nil, ""},
+		{"start of video", video, ptrInt(0), ""},
+		{"end of video", video, ptrInt(600), ""},
+		{"past end of video", video, ptrInt(601), utils.ErrCodeBadRequest},
+		{"duration not probed yet", unknownDuration, ptrInt(5000), ""},
+		{"timestamp on text lesson", text, ptrInt(10), utils.ErrCodeBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errorCode(validateNoteTimestamp(tt.lesson, tt.timestamp)); got != tt.wantCode {
+				t.Errorf("error code = %q, want %q", got, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestFormatVideoTimestamp(t *testing.T) {
+	tests := []struct {
+		seconds int
+		want    string
+	}{
+		{0, "00:00"},
+		{75, "01:15"},
+		{3599, "59:59"},
+		{3600, "1:00:00"},
+		{36061, "10:01:01"},
+	}
+
+	for _, tt := range tests {
+		if got := formatVideoTimestamp(tt.seconds); got != tt.want {
+			t.Errorf("formatVideoTimestamp(%d) = %q, want %q", tt.seconds, got, tt.want)
+		}
+	}
+}
+
+func TestUpdateNote(t *testing.T) {
+	tests := []struct {
+		name        string
+		userId      uint
+		noteId      uint
+		req         dto.UpdateLessonNoteRequest
+		wantCode    utils.ErrorCode
+		wantUpdates []string
+	}{
+		{"update content", 7, 1, dto.UpdateLessonNoteRequest{Content: " new text "}, "", []string{"content"}},
+		{"note of another user", 8, 1, dto.UpdateLessonNoteRequest{Content: "hijack"}, utils.ErrCodeNotFound, nil},
+		{"blank content", 7, 1, dto.UpdateLessonNoteRequest{Content: "   "}, utils.ErrCodeBadRequest, nil},
+		{"nothing to update", 7, 1, dto.UpdateLessonNoteRequest{}, utils.ErrCodeBadRequest, nil},
+		{"move timestamp", 7, 2, dto.UpdateLessonNoteRequest{VideoTimestamp: ptrInt(120)}, "", []string{"video_timestamp"}},
+		{"timestamp past video end", 7, 2, dto.UpdateLessonNoteRequest{VideoTimestamp: ptrInt(700)}, utils.ErrCodeBadRequest, nil},
+		{"timestamp on text lesson", 7, 3, dto.UpdateLessonNoteRequest{VideoTimestamp: ptrInt(5)}, utils.ErrCodeBadRequest, nil},
+		{"clear timestamp wins", 7, 1, dto.UpdateLessonNoteRequest{ClearTimestamp: true, VideoTimestamp: ptrInt(700)}, "", []string{"video_timestamp"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeNoteRepo()
+			ns := &lessonNoteService{noteRepo: repo}
+
+			item, err := ns.UpdateNote(tt.userId, tt.noteId, &tt.req)
+			if got := errorCode(err); got != tt.wantCode {
+				t.Fatalf("error code = %q, want %q (err: %v)", got, tt.wantCode, err)
+			}
+			if err != nil {
+				if len(repo.updated) != 0 {
+					t.Errorf("note was updated on error: %v", repo.updated)
+				}
+				return
+			}
+
+			updates := repo.updated[tt.noteId]
+			if len(updates) != len(tt.wantUpdates)+1 || updates["updated_at"] == nil {
+				t.Errorf("updates = %v, want %v and updated_at", updates, tt.wantUpdates)
+			}
+			for _, key := range tt.wantUpdates {
+				if _, ok := updates[key]; !ok {
+					t.Errorf("missing update %q in %v", key, updates)
+				}
+			}
+			if tt.req.ClearTimestamp && (item.VideoTimestamp != nil || updates["video_timestamp"] != nil) {
+				t.Errorf("timestamp not cleared: %v", updates)
+			}
+			if tt.req.Content != "" && item.Content != strings.TrimSpace(tt.req.Content) {
+				t.Errorf("content = %q, want trimmed %q", item.Content, tt.req.Content)
+			}
+		})
+	}
+}
+
+func TestDeleteNoteOnlyOwnNote(t *testing.T) {
+	repo := newFakeNoteRepo()
+	ns := &lessonNoteService{noteRepo: repo}
+
+	if _, err := ns.DeleteNote(8, 1); errorCode(err) != utils.ErrCodeNotFound {
+		t.Errorf("deleting another user's note: err = %v, want not found", err)
+	}
+	if _, err := ns.DeleteNote(7, 1); err != nil {
+		t.Fatalf("DeleteNote: %v", err)
+	}
+	if len(repo.deleted) != 1 || repo.deleted[0] != 1 {
+		t.Errorf("deleted = %v, want [1]", repo.deleted)
+	}
+}
+
+func TestExportCourseNotes(t *testing.T) {
+	repo := newFakeNoteRepo()
+	ns := &lessonNoteService{noteRepo: repo, courseRepo: &fakeNoteCourseRepo{course: &models.Course{Id: 1, Title: "Go Backend", Slug: "go-backend"}}}
+
+	fileName, content, err := ns.ExportCourseNotes(7, 1)
+	if err != nil {
+		t.Fatalf("ExportCourseNotes: %v", err)
+	}
+	if fileName != "notes-go-backend.md" {
+		t.Errorf("file name = %q", fileName)
+	}
+
+	markdown := string(content)
+	for _, want := range []string{
+		"# Go Backend\n",
+		"\n## 1. Goroutines\n\n**[01:15]** go keyword\n\nwhole lesson\n",
+		"\n## 2. Channels\n\nbuffered\n",
+	} {
+		if !strings.Contains(markdown, want) {
+			t.Errorf("export missing %q:\n%s", want, markdown)
+		}
+	}
+	if strings.Count(markdown, "## 1. Goroutines") != 1 || strings.Contains(markdown, "someone else") {
+		t.Errorf("export grouped notes wrongly or leaked another user's note:\n%s", markdown)
+	}
+
+	if _, _, err := ns.ExportCourseNotes(7, 2); errorCode(err) != utils.ErrCodeNotFound {
+		t.Errorf("missing course: err = %v, want not found", err)
+	}
+}
+
+func ptrInt(i int) *int {
+	return &i
+}
@@ -0,0 +1,296 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"lms/src/dto"
+	"lms/src/models"
+	"lms/src/repository"
+	"lms/src/storage"
+	"lms/src/utils"
+	"log"
+	"mime/multipart"
+	"path/filepath"
+	"strings"
+)
+
+type lessonResourceService struct {
+	resourceRepo   repository.LessonResourceRepository
+	instructorRepo repository.InstructorRepository
+	revisionRepo   repository.CourseRevisionRepository
+	lessonRepo     repository.LessonRepository
+	enrollmentRepo repository.EnrollmentRepository
+	storage        storage.Backend
+}
+
+func NewLessonResourceService(
+	resourceRepo repository.LessonResourceRepository,
+	instructorRepo repository.InstructorRepository,
+	revisionRepo repository.CourseRevisionRepository,
+	lessonRepo repository.LessonRepository,
+	enrollmentRepo repository.EnrollmentRepository,
+	storage storage.Backend,
+) LessonResourceService {
+	return &lessonResourceService{
+		resourceRepo:   resourceRepo,
+		instructorRepo: instructorRepo,
+		revisionRepo:   revisionRepo,
+		lessonRepo:     lessonRepo,
+		enrollmentRepo: enrollmentRepo,
+		storage:        storage,
+	}
+}
+
+func (rs *lessonResourceService) GetInstructorResources(instructorId, courseId, lessonId uint) (*dto.GetLessonResourcesResponse, error) {
+	// 1. Kiểm tra quyền trên course và lesson
+	if err := rs.checkInstructorLesson(instructorId, courseId, lessonId); err != nil {
+		return nil, err
+	}
+
+	// 2. Lấy danh sách resources
+	resources, err := rs.resourceRepo.GetLessonResources(lessonId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get lesson resources", utils.ErrCodeInternal)
+	}
+
+	return toLessonResourcesResponse(lessonId, resources, true), nil
+}
+
+func (rs *lessonResourceService) UploadResource(instructorId, courseId, lessonId uint, req *dto.UploadLessonResourceRequest, file *multipart.FileHeader) (*dto.LessonResourceItem, error) {
+	// 1. Kiểm tra quyền trên course và lesson
+	if err := rs.checkInstructorLesson(instructorId, courseId, lessonId); err != nil {
+		return nil, err
+	}
+
+	// 2. Validate và lưu file vào storage (prefix private)
+	ctx := context.Background()
+	storedName, mimeType, err := storeUploadedFile(ctx, rs.storage, lessonResourcePrefix(courseId, lessonId), file, utils.ResourceFileRules())
+	if err != nil {
+		return nil, utils.WrapError(err, "Invalid resource file", utils.ErrCodeBadRequest)
+	}
+	key := lessonResourcePrefix(courseId, lessonId) + storedName
+
+	// 3. Resource mới được đặt cuối danh sách
+	maxOrder, err := rs.resourceRepo.GetMaxResourceOrder(lessonId)
+	if err != nil {
+		deleteStorageKeys(ctx, rs.storage, []string{key})
+		return nil, utils.WrapError(err, "Failed to get resource order", utils.ErrCodeInternal)
+	}
+
+	originalName := filepath.Base(file.Filename)
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		title = originalName
+	}
+
+	resource := &models.LessonResource{
+		LessonId:      lessonId,
+		CourseId:      courseId,
+		Title:         title,
+		Description:   req.Description,
+		OriginalName:  originalName,
+		StoredName:    storedName,
+		Size:          file.Size,
+		MimeType:      mimeType,
+		ResourceOrder: maxOrder + 1,
+		UploadedBy:    instructorId,
+	}
+
+	// 4. Lưu vào database, lỗi thì xóa file đã lưu
+	if err := rs.resourceRepo.CreateResource(resource); err != nil {
+		deleteStorageKeys(ctx, rs.storage, []string{key})
+		return nil, utils.WrapError(err, "Failed to create lesson resource", utils.ErrCodeInternal)
+	}
+
+	item := toLessonResourceItem(resource, true)
+	return &item, nil
+}
+
+func (rs *lessonResourceService) UpdateResource(instructorId, courseId, lessonId, resourceId uint, req *dto.UpdateLessonResourceRequest) (*dto.LessonResourceItem, error) {
+	// 1. Kiểm tra quyền và resource thuộc lesson
+	resource, err := rs.findInstructorResource(instructorId, courseId, lessonId, resourceId)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Chuẩn bị dữ liệu update
+	updates := make(map[string]interface{})
+	if req.Title != nil {
+		updates["title"] = strings.TrimSpace(*req.Title)
+	}
+	if req.Description != nil {
+		updates["description"] = *req.Description
+	}
+	if req.ResourceOrder != nil {
+		updates["resource_order"] = *req.ResourceOrder
+	}
+
+	if len(updates) == 0 {
+		return nil, utils.NewError("No fields to update", utils.ErrCodeBadRequest)
+	}
+
+	// 3. Cập nhật
+	if err := rs.resourceRepo.UpdateResource(resource.Id, updates); err != nil {
+		return nil, utils.WrapError(err, "Failed to update lesson resource", utils.ErrCodeInternal)
+	}
+
+	updatedResource, err := rs.resourceRepo.FindById(resource.Id)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get updated resource", utils.ErrCodeInternal)
+	}
+
+	item := toLessonResourceItem(updatedResource, true)
+	return &item, nil
+}
+
+func (rs *lessonResourceService) DeleteResource(instructorId, courseId, lessonId, resourceId uint) (*dto.DeleteLessonResourceResponse, error) {
+	// 1. Kiểm tra quyền và resource thuộc lesson
+	resource, err := rs.findInstructorResource(instructorId, courseId, lessonId, resourceId)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Xóa record rồi xóa file (file còn trong revision của course thì giữ lại để rollback)
+	if err := rs.resourceRepo.DeleteResource(resource.Id); err != nil {
+		return nil, utils.WrapError(err, "Failed to delete lesson resource", utils.ErrCodeInternal)
+	}
+
+	if !isRetainedByRevision(rs.revisionRepo, resource.CourseId, resource.StoredName) {
+		deleteStorageKeys(context.Background(), rs.storage, []string{lessonResourceKey(resource)})
+	}
+
+	return &dto.DeleteLessonResourceResponse{
+		Message: "Lesson resource deleted successfully",
+	}, nil
+}
+
+func (rs *lessonResourceService) GetLessonResources(userId, lessonId uint) (*dto.GetLessonResourcesResponse, error) {
+	// 1. Chỉ học viên đã enroll và lesson đã mở khóa mới xem được resources
+	if _, err := rs.checkStudentLesson(userId, lessonId); err != nil {
+		return nil, err
+	}
+
+	// 2. Lấy danh sách resources
+	resources, err := rs.resourceRepo.GetLessonResources(lessonId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get lesson resources", utils.ErrCodeInternal)
+	}
+
+	return toLessonResourcesResponse(lessonId, resources, false), nil
+}
+
+func (rs *lessonResourceService) GetResourceFile(userId, resourceId uint) (*PrivateFile, error) {
+	// 1. Lấy resource
+	resource, err := rs.resourceRepo.FindById(resourceId)
+	if err != nil {
+		return nil, utils.NewError("Resource not found", utils.ErrCodeNotFound)
+	}
+
+	// 2. Instructor của course được tải trực tiếp, học viên phải enroll
+	if _, err := rs.instructorRepo.FindCourseByIdAndInstructor(resource.CourseId, userId); err != nil {
+		if _, err := rs.checkStudentLesson(userId, resource.LessonId); err != nil {
+			return nil, err
+		}
+	}
+
+	// 3. Mở file trong storage
+	file, err := openPrivateFile(context.Background(), rs.storage, lessonResourceKey(resource), resource.OriginalName, resource.MimeType)
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. Đếm lượt tải (lỗi không ảnh hưởng download)
+	if err := rs.resourceRepo.IncrementDownloadCount(resource.Id); err != nil {
+		log.Printf("Failed to increment download count for resource %d: %v", resource.Id, err)
+	}
+
+	return file, nil
+}
+
+func (rs *lessonResourceService) checkInstructorLesson(instructorId, courseId, lessonId uint) error {
+	if _, err := rs.instructorRepo.FindCourseByIdAndInstructor(courseId, instructorId); err != nil {
+		return utils.NewError("Course not found or you don't have permission", utils.ErrCodeNotFound)
+	}
+
+	if _, err := rs.instructorRepo.FindLessonByIdAndCourse(lessonId, courseId); err != nil {
+		return utils.NewError("Lesson not found", utils.ErrCodeNotFound)
+	}
+
+	return nil
+}
+
+func (rs *lessonResourceService) findInstructorResource(instructorId, courseId, lessonId, resourceId uint) (*models.LessonResource, error) {
+	if err := rs.checkInstructorLesson(instructorId, courseId, lessonId); err != nil {
+		return nil, err
+	}
+
+	resource, err := rs.resourceRepo.FindById(resourceId)
+	if err != nil || resource.LessonId != lessonId {
+		return nil, utils.NewError("Resource not found", utils.ErrCodeNotFound)
+	}
+
+	return resource, nil
+}
+
+func (rs *lessonResourceService) checkStudentLesson(userId, lessonId uint) (*models.Lesson, error) {
+	lesson, err := rs.resourceRepo.FindLessonById(lessonId)
+	if err != nil || !lesson.IsPublished {
+		return nil, utils.NewError("Lesson not found", utils.ErrCodeNotFound)
+	}
+
+	enrollment, isEnrolled := rs.enrollmentRepo.CheckEnrollment(userId, lesson.CourseId)
+	if !isEnrolled {
+		return nil, utils.NewError("You must enroll in this course to access lesson resources", utils.ErrCodeForbidden)
+	}
+
+	lock, err := resolveLessonLock(rs.lessonRepo, userId, lesson, enrollment.EnrolledAt)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to check lesson lock", utils.ErrCodeInternal)
+	}
+	if lock.IsLocked {
+		return nil, utils.NewError(lessonLockMessage(lock.Reason), utils.ErrCodeForbidden)
+	}
+
+	return lesson, nil
+}
+
+// lessonResourcePrefix - resources nằm ngoài storage.PublicPrefixes, chỉ tải qua API có kiểm tra enrollment
+func lessonResourcePrefix(courseId, lessonId uint) string {
+	return fmt.Sprintf("resources/%d/%d/", courseId, lessonId)
+}
+
+func lessonResourceKey(resource *models.LessonResource) string {
+	return lessonResourcePrefix(resource.CourseId, resource.LessonId) + resource.StoredName
+}
+
+func toLessonResourceItem(resource *models.LessonResource, includeStats bool) dto.LessonResourceItem {
+	item := dto.LessonResourceItem{
+		Id:            resource.Id,
+		LessonId:      resource.LessonId,
+		Title:         resource.Title,
+		Description:   resource.Description,
+		FileName:      resource.OriginalName,
+		Size:          resource.Size,
+		MimeType:      resource.MimeType,
+		ResourceOrder: resource.ResourceOrder,
+		DownloadURL:   fmt.Sprintf("/api/v1/resources/%d/download", resource.Id),
+		CreatedAt:     resource.CreatedAt,
+	}
+	if includeStats {
+		item.DownloadCount = resource.DownloadCount
+	}
+	return item
+}
+
+func toLessonResourcesResponse(lessonId uint, resources []models.LessonResource, includeStats bool) *dto.GetLessonResourcesResponse {
+	items := make([]dto.LessonResourceItem, len(resources))
+	for i := range resources {
+		items[i] = toLessonResourceItem(&resources[i], includeStats)
+	}
+
+	return &dto.GetLessonResourcesResponse{
+		LessonId:  lessonId,
+		Resources: items,
+		Total:     len(items),
+	}
+}
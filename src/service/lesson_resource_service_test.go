@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"io"
+	"lms/src/models"
+	"lms/src/storage"
+	"lms/src/utils"
+	"strings"
+	"testing"
+)
+
+func TestLessonResourceKey(t *testing.T) {
+	resource := &models.LessonResource{CourseId: 5, LessonId: 12, StoredName: "f1.pdf"}
+
+	key := lessonResourceKey(resource)
+	if key != "resources/5/12/f1.pdf" {
+		t.Errorf("lessonResourceKey = %q, want %q", key, "resources/5/12/f1.pdf")
+	}
+	if storage.IsPublicKey(key) {
+		t.Errorf("resource key %q must not be publicly served", key)
+	}
+}
+
+func TestToLessonResourceItemHidesStatsFromStudents(t *testing.T) {
+	resource := &models.LessonResource{Id: 3, LessonId: 12, OriginalName: "slides.pdf", StoredName: "f1.pdf", DownloadCount: 42}
+
+	tests := []struct {
+		name          string
+		includeStats  bool
+		wantDownloads int
+	}{
+		{"instructor", true, 42},
+		{"student", false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := toLessonResourceItem(resource, tt.includeStats)
+			if int(item.DownloadCount) != tt.wantDownloads {
+				t.Errorf("download count = %d, want %d", item.DownloadCount, tt.wantDownloads)
+			}
+			if item.FileName != "slides.pdf" || item.DownloadURL != "/api/v1/resources/3/download" {
+				t.Errorf("item = %+v, want original file name and API download URL", item)
+			}
+		})
+	}
+}
+
+func TestPrivateFileRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	backend := storage.NewLocalBackend(t.TempDir())
+	prefix := lessonResourcePrefix(5, 12)
+	content := "week 1 slides"
+
+	storedName, err := storeFileContent(ctx, backend, prefix, ".PDF", strings.NewReader(content), int64(len(content)), "application/pdf")
+	if err != nil {
+		t.Fatalf("storeFileContent: %v", err)
+	}
+	if !strings.HasSuffix(storedName, ".pdf") || strings.Contains(storedName, "/") {
+		t.Errorf("stored name = %q, want a bare file name with lowercase extension", storedName)
+	}
+
+	file, err := openPrivateFile(ctx, backend, prefix+storedName, "Slides tuần 1.pdf", "application/pdf")
+	if err != nil {
+		t.Fatalf("openPrivateFile: %v", err)
+	}
+	defer file.Content.Close()
+
+	data, err := io.ReadAll(file.Content)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if string(data) != content || file.Size != int64(len(content)) || file.Name != "Slides tuần 1.pdf" || file.ContentType != "application/pdf" {
+		t.Errorf("file = %+v (%q), want original name, type and content", file, data)
+	}
+
+	if _, err := openPrivateFile(ctx, backend, prefix+"missing.pdf", "x.pdf", "application/pdf"); errorCode(err) != utils.ErrCodeNotFound {
+		t.Errorf("missing file error = %v, want not found", err)
+	}
+}
@@ -4,17 +4,24 @@ import (
 	"lms/src/dto"
 	"lms/src/repository"
 	"lms/src/utils"
+	"time"
 )
 
 type lessonService struct {
-	lessonRepo repository.LessonRepository
-	courseRepo repository.CourseRepository
+	lessonRepo     repository.LessonRepository
+	courseRepo     repository.CourseRepository
+	enrollmentRepo repository.EnrollmentRepository
+	subtitleRepo   repository.LessonSubtitleRepository
+	noteRepo       repository.LessonNoteRepository
 }
 
-func NewLessonService(lessonRepo repository.LessonRepository, courseRepo repository.CourseRepository) LessonService {
+func NewLessonService(lessonRepo repository.LessonRepository, courseRepo repository.CourseRepository, enrollmentRepo repository.EnrollmentRepository, subtitleRepo repository.LessonSubtitleRepository, noteRepo repository.LessonNoteRepository) LessonService {
 	return &lessonService{
-		lessonRepo: lessonRepo,
-		courseRepo: courseRepo,
+		lessonRepo:     lessonRepo,
+		courseRepo:     courseRepo,
+		enrollmentRepo: enrollmentRepo,
+		subtitleRepo:   subtitleRepo,
+		noteRepo:       noteRepo,
 	}
 }
 
@@ -36,6 +43,11 @@ func (ls *lessonService) GetCourseLessons(userId, courseId uint) (*dto.GetCourse
 		return nil, utils.NewError("You must enroll in this course to access lessons", utils.ErrCodeForbidden)
 	}
 
+	enrollment, _ := ls.enrollmentRepo.CheckEnrollment(userId, courseId)
+	if enrollment == nil {
+		return nil, utils.NewError("You must enroll in this course to access lessons", utils.ErrCodeForbidden)
+	}
+
 	// 3. Lấy danh sách lessons
 	lessons, err := ls.lessonRepo.GetCourseLessons(courseId)
 	if err != nil {
@@ -53,20 +65,35 @@ func (ls *lessonService) GetCourseLessons(userId, courseId uint) (*dto.GetCourse
 		return nil, utils.WrapError(err, "Failed to get lesson progress", utils.ErrCodeInternal)
 	}
 
-	// 5. Convert sang DTO
+	// 5. Convert sang DTO kèm trạng thái khóa (lessons đã được sắp xếp theo lesson_order)
+	now := time.Now()
+	previousCompleted := true
 	lessonItems := make([]dto.LessonItem, len(lessons))
 	for i, lesson := range lessons {
+		lock := evaluateLessonLock(&lesson, enrollment.EnrolledAt, previousCompleted, now)
+		previousCompleted = progressMap[lesson.Id]
+
+		// Video local được trả về dạng signed URL có thời hạn
+		videoURL, _, _ := buildLessonVideoURL(userId, &lesson)
+		if lock.IsLocked {
+			videoURL = ""
+		}
+
 		lessonItems[i] = dto.LessonItem{
 			Id:            lesson.Id,
 			CourseId:      lesson.CourseId,
 			Title:         lesson.Title,
 			Slug:          lesson.Slug,
 			Description:   lesson.Description,
-			VideoURL:      lesson.VideoURL,
+			VideoURL:      videoURL,
 			VideoDuration: lesson.VideoDuration,
+			LessonType:    lesson.LessonType,
 			LessonOrder:   lesson.LessonOrder,
 			IsPreview:     lesson.IsPreview,
 			IsCompleted:   progressMap[lesson.Id],
+			IsLocked:      lock.IsLocked,
+			UnlockAt:      lock.UnlockAt,
+			LockReason:    lock.Reason,
 			CreatedAt:     lesson.CreatedAt,
 		}
 	}
@@ -96,6 +123,11 @@ func (ls *lessonService) GetLessonDetail(userId, courseId uint, slug string) (*d
 		return nil, utils.NewError("You must enroll in this course to access this lesson", utils.ErrCodeForbidden)
 	}
 
+	enrollment, _ := ls.enrollmentRepo.CheckEnrollment(userId, courseId)
+	if enrollment == nil {
+		return nil, utils.NewError("You must enroll in this course to access this lesson", utils.ErrCodeForbidden)
+	}
+
 	// 3. Tìm lesson theo slug và course_id
 	lesson, err := ls.lessonRepo.FindLessonBySlugAndCourse(slug, courseId)
 	if err != nil {
@@ -124,13 +156,44 @@ func (ls *lessonService) GetLessonDetail(userId, courseId uint, slug string) (*d
 	nxtLesson, err := ls.lessonRepo.GetNextLesson(courseId, lesson.LessonOrder)
 	if err == nil && nxtLesson != nil {
 		nextLesson = &dto.LessonNavigation{
-			Id:    nextLesson.Id,
-			Title: nextLesson.Title,
-			Slug:  nextLesson.Slug,
+			Id:    nxtLesson.Id,
+			Title: nxtLesson.Title,
+			Slug:  nxtLesson.Slug,
+		}
+	}
+
+	// 6. Kiểm tra unlock rules, lesson bị khóa thì không trả về nội dung
+	lock, err := resolveLessonLock(ls.lessonRepo, userId, lesson, enrollment.EnrolledAt)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to check lesson lock", utils.ErrCodeInternal)
+	}
+
+	content := lesson.Content
+	contentHTML := renderedHTML(lesson.Content, lesson.ContentHTML)
+	videoURL, _, _ := buildLessonVideoURL(userId, lesson)
+	subtitles := []dto.SubtitleTrack{}
+	if lock.IsLocked {
+		content = ""
+		contentHTML = ""
+		videoURL = ""
+	} else {
+		subtitles, err = buildSubtitleTracks(ls.subtitleRepo, userId, lesson)
+		if err != nil {
+			return nil, utils.WrapError(err, "Failed to get lesson subtitles", utils.ErrCodeInternal)
 		}
 	}
 
-	// 6. Convert sang DTO
+	// 7. Số ghi chú và bookmark của học viên
+	noteCount, err := ls.noteRepo.CountLessonNotes(userId, lesson.Id)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to count lesson notes", utils.ErrCodeInternal)
+	}
+	isBookmarked, err := ls.noteRepo.IsBookmarked(userId, lesson.Id)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get lesson bookmark", utils.ErrCodeInternal)
+	}
+
+	// 8. Convert sang DTO
 	return &dto.LessonDetail{
 		Id:             lesson.Id,
 		CourseId:       lesson.CourseId,
@@ -138,9 +201,12 @@ func (ls *lessonService) GetLessonDetail(userId, courseId uint, slug string) (*d
 		Title:          lesson.Title,
 		Slug:           lesson.Slug,
 		Description:    lesson.Description,
-		Content:        lesson.Content,
-		VideoURL:       lesson.VideoURL,
+		Content:        content,
+		ContentHTML:    contentHTML,
+		Subtitles:      subtitles,
+		VideoURL:       videoURL,
 		VideoDuration:  lesson.VideoDuration,
+		LessonType:     lesson.LessonType,
 		LessonOrder:    lesson.LessonOrder,
 		IsPreview:      lesson.IsPreview,
 		IsCompleted:    progress.IsCompleted,
@@ -148,6 +214,11 @@ func (ls *lessonService) GetLessonDetail(userId, courseId uint, slug string) (*d
 		WatchDuration:  progress.WatchDuration,
 		CreatedAt:      lesson.CreatedAt,
 		UpdatedAt:      lesson.UpdatedAt,
+		NoteCount:      noteCount,
+		IsBookmarked:   isBookmarked,
+		IsLocked:       lock.IsLocked,
+		UnlockAt:       lock.UnlockAt,
+		LockReason:     lock.Reason,
 		PreviousLesson: previousLesson,
 		NextLesson:     nextLesson,
 	}, nil
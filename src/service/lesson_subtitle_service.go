@@ -0,0 +1,471 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"lms/src/dto"
+	"lms/src/models"
+	"lms/src/repository"
+	"lms/src/storage"
+	"lms/src/subtitle"
+	"lms/src/utils"
+	"log"
+	"math"
+	"mime/multipart"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Nhãn mặc định của track theo ngôn ngữ (đồng bộ với validator language_code)
+var subtitleLanguageLabels = map[string]string{
+	"vi": "Tiếng Việt",
+	"en": "English",
+}
+
+type lessonSubtitleService struct {
+	subtitleRepo   repository.LessonSubtitleRepository
+	instructorRepo repository.InstructorRepository
+	revisionRepo   repository.CourseRevisionRepository
+	lessonRepo     repository.LessonRepository
+	enrollmentRepo repository.EnrollmentRepository
+	storage        storage.Backend
+}
+
+func NewLessonSubtitleService(
+	subtitleRepo repository.LessonSubtitleRepository,
+	instructorRepo repository.InstructorRepository,
+	revisionRepo repository.CourseRevisionRepository,
+	lessonRepo repository.LessonRepository,
+	enrollmentRepo repository.EnrollmentRepository,
+	subtitleStorage storage.Backend,
+) LessonSubtitleService {
+	return &lessonSubtitleService{
+		subtitleRepo:   subtitleRepo,
+		instructorRepo: instructorRepo,
+		revisionRepo:   revisionRepo,
+		lessonRepo:     lessonRepo,
+		enrollmentRepo: enrollmentRepo,
+		storage:        subtitleStorage,
+	}
+}
+
+func (ss *lessonSubtitleService) GetInstructorSubtitles(instructorId, courseId, lessonId uint) (*dto.GetSubtitlesResponse, error) {
+	// 1. Kiểm tra quyền trên course và lesson
+	if err := ss.checkInstructorLesson(instructorId, courseId, lessonId); err != nil {
+		return nil, err
+	}
+
+	// 2. Lấy danh sách track
+	subtitles, err := ss.subtitleRepo.GetLessonSubtitles(lessonId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get lesson subtitles", utils.ErrCodeInternal)
+	}
+
+	items := make([]dto.SubtitleItem, len(subtitles))
+	for i := range subtitles {
+		items[i] = toSubtitleItem(&subtitles[i])
+	}
+
+	return &dto.GetSubtitlesResponse{
+		LessonId:  lessonId,
+		Subtitles: items,
+	}, nil
+}
+
+func (ss *lessonSubtitleService) UploadSubtitle(instructorId, courseId, lessonId uint, req *dto.UploadSubtitleRequest, file *multipart.FileHeader) (*dto.SubtitleItem, error) {
+	// 1. Kiểm tra quyền trên course và lesson
+	if err := ss.checkInstructorLesson(instructorId, courseId, lessonId); err != nil {
+		return nil, err
+	}
+
+	// 2. Validate file (.srt/.vtt, text, dung lượng)
+	ext, _, err := utils.ValidateFile(file, utils.SubtitleFileRules)
+	if err != nil {
+		return nil, utils.WrapError(err, "Invalid subtitle file", utils.ErrCodeBadRequest)
+	}
+	format := strings.TrimPrefix(ext, ".")
+
+	src, err := file.Open()
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to read subtitle file", utils.ErrCodeBadRequest)
+	}
+	defer src.Close()
+
+	data, err := io.ReadAll(io.LimitReader(src, utils.SubtitleFileRules.MaxSize+1))
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to read subtitle file", utils.ErrCodeBadRequest)
+	}
+	if int64(len(data)) > utils.SubtitleFileRules.MaxSize {
+		return nil, utils.NewError("Subtitle file is too large", utils.ErrCodeBadRequest)
+	}
+
+	// 3. Parse cue, SRT được chuyển sang WebVTT
+	cues, err := subtitle.Parse(data, format)
+	if err != nil {
+		return nil, utils.WrapError(err, "Invalid subtitle file", utils.ErrCodeBadRequest)
+	}
+
+	ctx := context.Background()
+	vtt := subtitle.EncodeVTT(cues)
+	key := fmt.Sprintf("subtitles/%d/%d/%s.vtt", courseId, lessonId, uuid.NewString())
+	if err := ss.storage.Put(ctx, key, bytes.NewReader(vtt), int64(len(vtt)), "text/vtt"); err != nil {
+		return nil, utils.WrapError(err, "Failed to store subtitle file", utils.ErrCodeInternal)
+	}
+
+	// 4. Cùng ngôn ngữ thì thay thế track cũ, track đầu tiên của lesson là default
+	existing, err := ss.subtitleRepo.FindByLessonAndLanguage(lessonId, req.Language)
+	if err != nil {
+		ss.removeSubtitleFile(key)
+		return nil, utils.WrapError(err, "Failed to check existing subtitle", utils.ErrCodeInternal)
+	}
+
+	// Track thay thế là record mới, track cũ được giữ lại (soft delete) cho lịch sử revision của course
+	record := &models.LessonSubtitle{
+		LessonId: lessonId,
+		CourseId: courseId,
+		Language: req.Language,
+	}
+	oldKey := ""
+	if existing == nil {
+		subtitles, err := ss.subtitleRepo.GetLessonSubtitles(lessonId)
+		if err != nil {
+			ss.removeSubtitleFile(key)
+			return nil, utils.WrapError(err, "Failed to get lesson subtitles", utils.ErrCodeInternal)
+		}
+		record.IsDefault = len(subtitles) == 0
+	} else {
+		record.Label = existing.Label
+		record.IsDefault = existing.IsDefault
+		oldKey = existing.StorageKey
+	}
+
+	label := strings.TrimSpace(req.Label)
+	if label == "" && record.Label == "" {
+		label = subtitleLanguageLabels[req.Language]
+	}
+	if label != "" {
+		record.Label = label
+	}
+	if req.IsDefault {
+		record.IsDefault = true
+	}
+	record.SourceFormat = format
+	record.StorageKey = key
+	record.CueCount = len(cues)
+	record.UploadedBy = instructorId
+
+	cueModels := make([]models.LessonSubtitleCue, len(cues))
+	for i, cue := range cues {
+		cueModels[i] = models.LessonSubtitleCue{
+			CueIndex: i + 1,
+			StartMs:  cue.Start.Milliseconds(),
+			EndMs:    cue.End.Milliseconds(),
+			Text:     cue.PlainText(),
+		}
+	}
+
+	// 5. Lưu track và cue, lỗi thì xóa file vừa lưu
+	if err := ss.subtitleRepo.SaveSubtitle(record, cueModels); err != nil {
+		ss.removeSubtitleFile(key)
+		return nil, utils.WrapError(err, "Failed to save subtitle", utils.ErrCodeInternal)
+	}
+
+	if oldKey != "" {
+		ss.releaseSubtitleFile(courseId, oldKey)
+	}
+
+	item := toSubtitleItem(record)
+	return &item, nil
+}
+
+func (ss *lessonSubtitleService) UpdateSubtitle(instructorId, courseId, lessonId, subtitleId uint, req *dto.UpdateSubtitleRequest) (*dto.SubtitleItem, error) {
+	// 1. Kiểm tra quyền và track thuộc lesson
+	record, err := ss.findInstructorSubtitle(instructorId, courseId, lessonId, subtitleId)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Chuẩn bị dữ liệu update
+	updates := make(map[string]interface{})
+	if req.Label != nil {
+		updates["label"] = strings.TrimSpace(*req.Label)
+	}
+	if req.IsDefault != nil {
+		updates["is_default"] = *req.IsDefault
+	}
+
+	if len(updates) == 0 {
+		return nil, utils.NewError("No fields to update", utils.ErrCodeBadRequest)
+	}
+
+	// 3. Cập nhật (chọn default thì bỏ default của track khác)
+	if err := ss.subtitleRepo.UpdateSubtitle(record, updates); err != nil {
+		return nil, utils.WrapError(err, "Failed to update subtitle", utils.ErrCodeInternal)
+	}
+
+	updated, err := ss.subtitleRepo.FindById(record.Id)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get updated subtitle", utils.ErrCodeInternal)
+	}
+
+	item := toSubtitleItem(updated)
+	return &item, nil
+}
+
+func (ss *lessonSubtitleService) DeleteSubtitle(instructorId, courseId, lessonId, subtitleId uint) (*dto.DeleteSubtitleResponse, error) {
+	// 1. Kiểm tra quyền và track thuộc lesson
+	record, err := ss.findInstructorSubtitle(instructorId, courseId, lessonId, subtitleId)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Xóa record, cue rồi xóa file
+	if err := ss.subtitleRepo.DeleteSubtitle(record.Id); err != nil {
+		return nil, utils.WrapError(err, "Failed to delete subtitle", utils.ErrCodeInternal)
+	}
+
+	ss.releaseSubtitleFile(courseId, record.StorageKey)
+
+	return &dto.DeleteSubtitleResponse{
+		Message: "Subtitle deleted successfully",
+	}, nil
+}
+
+func (ss *lessonSubtitleService) GetSubtitleTracks(userId, lessonId uint) (*dto.GetSubtitleTracksResponse, error) {
+	// 1. Kiểm tra quyền xem lesson (preview lesson không cần enroll)
+	lesson, err := ss.findAccessibleLesson(userId, lessonId)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Lấy track kèm signed URL
+	tracks, err := buildSubtitleTracks(ss.subtitleRepo, userId, lesson)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get lesson subtitles", utils.ErrCodeInternal)
+	}
+
+	return &dto.GetSubtitleTracksResponse{
+		LessonId: lesson.Id,
+		Tracks:   tracks,
+	}, nil
+}
+
+func (ss *lessonSubtitleService) GetTranscript(userId, lessonId uint, req *dto.TranscriptQueryRequest) (*dto.TranscriptResponse, error) {
+	// 1. Kiểm tra quyền xem lesson
+	lesson, err := ss.findAccessibleLesson(userId, lessonId)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Chọn track: theo ngôn ngữ yêu cầu, mặc định là track default
+	var record *models.LessonSubtitle
+	if req.Language != "" {
+		record, err = ss.subtitleRepo.FindByLessonAndLanguage(lesson.Id, req.Language)
+		if err != nil {
+			return nil, utils.WrapError(err, "Failed to get lesson subtitles", utils.ErrCodeInternal)
+		}
+	} else {
+		subtitles, err := ss.subtitleRepo.GetLessonSubtitles(lesson.Id)
+		if err != nil {
+			return nil, utils.WrapError(err, "Failed to get lesson subtitles", utils.ErrCodeInternal)
+		}
+		if len(subtitles) > 0 {
+			record = &subtitles[0]
+		}
+	}
+	if record == nil {
+		return nil, utils.NewError("Transcript not found for this lesson", utils.ErrCodeNotFound)
+	}
+
+	// 3. Phân trang và tìm kiếm theo text
+	page := 1
+	if req.Page > 0 {
+		page = req.Page
+	}
+	limit := 100
+	if req.Limit > 0 {
+		limit = req.Limit
+	}
+	offset := (page - 1) * limit
+
+	query := strings.TrimSpace(req.Query)
+	cues, total, err := ss.subtitleRepo.SearchCues(record.Id, query, offset, limit)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to search transcript", utils.ErrCodeInternal)
+	}
+
+	items := make([]dto.TranscriptCue, len(cues))
+	for i, cue := range cues {
+		items[i] = dto.TranscriptCue{
+			Index:          cue.CueIndex,
+			Start:          float64(cue.StartMs) / 1000,
+			End:            float64(cue.EndMs) / 1000,
+			StartTimestamp: subtitle.FormatTimestamp(time.Duration(cue.StartMs) * time.Millisecond),
+			Text:           cue.Text,
+		}
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+
+	return &dto.TranscriptResponse{
+		LessonId:   lesson.Id,
+		SubtitleId: record.Id,
+		Language:   record.Language,
+		Query:      query,
+		Cues:       items,
+		Pagination: dto.PaginationInfo{
+			Page:       page,
+			Limit:      limit,
+			Total:      total,
+			TotalPages: totalPages,
+			HasNext:    page < totalPages,
+			HasPrev:    page > 1,
+		},
+	}, nil
+}
+
+func (ss *lessonSubtitleService) GetSubtitleFile(subtitleId uint, query *dto.MediaStreamQuery) ([]byte, error) {
+	// 1. Lấy track và lesson
+	record, err := ss.subtitleRepo.FindById(subtitleId)
+	if err != nil {
+		return nil, utils.NewError("Subtitle not found", utils.ErrCodeNotFound)
+	}
+
+	lesson, err := findPublishedLesson(ss.lessonRepo, record.LessonId)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Kiểm tra chữ ký như video stream (preview lesson được miễn)
+	if !lesson.IsPreview {
+		if !utils.VerifyMediaSignature(utils.MediaPurposeSubtitle, query.UserId, lesson.Id, query.ExpiresAt, query.Signature) {
+			return nil, utils.NewError("Invalid or expired subtitle URL", utils.ErrCodeForbidden)
+		}
+		if err := checkLessonAccess(ss.lessonRepo, ss.enrollmentRepo, query.UserId, lesson); err != nil {
+			return nil, err
+		}
+	}
+
+	// 3. Đọc file WebVTT từ storage
+	reader, err := ss.storage.Get(context.Background(), record.StorageKey)
+	if err != nil {
+		return nil, utils.NewError("Subtitle file not found", utils.ErrCodeNotFound)
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to read subtitle file", utils.ErrCodeInternal)
+	}
+
+	return data, nil
+}
+
+func (ss *lessonSubtitleService) findAccessibleLesson(userId, lessonId uint) (*models.Lesson, error) {
+	lesson, err := findPublishedLesson(ss.lessonRepo, lessonId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !lesson.IsPreview {
+		if err := checkLessonAccess(ss.lessonRepo, ss.enrollmentRepo, userId, lesson); err != nil {
+			return nil, err
+		}
+	}
+
+	return lesson, nil
+}
+
+func (ss *lessonSubtitleService) checkInstructorLesson(instructorId, courseId, lessonId uint) error {
+	if _, err := ss.instructorRepo.FindCourseByIdAndInstructor(courseId, instructorId); err != nil {
+		return utils.NewError("Course not found or you don't have permission", utils.ErrCodeNotFound)
+	}
+
+	if _, err := ss.instructorRepo.FindLessonByIdAndCourse(lessonId, courseId); err != nil {
+		return utils.NewError("Lesson not found", utils.ErrCodeNotFound)
+	}
+
+	return nil
+}
+
+func (ss *lessonSubtitleService) findInstructorSubtitle(instructorId, courseId, lessonId, subtitleId uint) (*models.LessonSubtitle, error) {
+	if err := ss.checkInstructorLesson(instructorId, courseId, lessonId); err != nil {
+		return nil, err
+	}
+
+	record, err := ss.subtitleRepo.FindById(subtitleId)
+	if err != nil || record.LessonId != lessonId {
+		return nil, utils.NewError("Subtitle not found", utils.ErrCodeNotFound)
+	}
+
+	return record, nil
+}
+
+// releaseSubtitleFile xóa file của track đã bị thay/xóa, trừ khi revision của course còn dùng để rollback
+func (ss *lessonSubtitleService) releaseSubtitleFile(courseId uint, key string) {
+	if !isRetainedByRevision(ss.revisionRepo, courseId, key) {
+		ss.removeSubtitleFile(key)
+	}
+}
+
+func (ss *lessonSubtitleService) removeSubtitleFile(key string) {
+	if err := ss.storage.Delete(context.Background(), key); err != nil {
+		log.Printf("Failed to remove subtitle file %s: %v", key, err)
+	}
+}
+
+// buildSubtitleTracks trả về danh sách track của lesson với URL file WebVTT đã ký cho user
+func buildSubtitleTracks(subtitleRepo repository.LessonSubtitleRepository, userId uint, lesson *models.Lesson) ([]dto.SubtitleTrack, error) {
+	subtitles, err := subtitleRepo.GetLessonSubtitles(lesson.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	baseURL := utils.GetEnv("BASE_URL", "http://localhost:8080")
+
+	var expiresAt *time.Time
+	query := ""
+	if !lesson.IsPreview {
+		expiry := time.Now().Add(utils.MediaURLTTL())
+		expiresAt = &expiry
+
+		values := url.Values{}
+		values.Set("uid", fmt.Sprint(userId))
+		values.Set("exp", fmt.Sprint(expiry.Unix()))
+		values.Set("sig", utils.SignMediaURL(utils.MediaPurposeSubtitle, userId, lesson.Id, expiry.Unix()))
+		query = "?" + values.Encode()
+	}
+
+	tracks := make([]dto.SubtitleTrack, len(subtitles))
+	for i, record := range subtitles {
+		tracks[i] = dto.SubtitleTrack{
+			Id:        record.Id,
+			Language:  record.Language,
+			Label:     record.Label,
+			IsDefault: record.IsDefault,
+			URL:       fmt.Sprintf("%s/api/v1/subtitles/%d/vtt%s", baseURL, record.Id, query),
+			ExpiresAt: expiresAt,
+		}
+	}
+
+	return tracks, nil
+}
+
+func toSubtitleItem(record *models.LessonSubtitle) dto.SubtitleItem {
+	return dto.SubtitleItem{
+		Id:           record.Id,
+		LessonId:     record.LessonId,
+		Language:     record.Language,
+		Label:        record.Label,
+		SourceFormat: record.SourceFormat,
+		CueCount:     record.CueCount,
+		IsDefault:    record.IsDefault,
+		CreatedAt:    record.CreatedAt,
+		UpdatedAt:    record.UpdatedAt,
+	}
+}
@@ -0,0 +1,32 @@
+package service
+
+import (
+	"lms/src/markdown"
+	"lms/src/storage"
+	"strings"
+)
+
+// renderMarkdown render Markdown sang HTML đã sanitize, chỉ cho nhúng ảnh nằm trên storage của hệ thống
+func renderMarkdown(source string) string {
+	if strings.TrimSpace(source) == "" {
+		return ""
+	}
+
+	return markdown.Render(source, markdown.Options{
+		AllowImage: isPublicStorageImage,
+	})
+}
+
+// isPublicStorageImage chỉ nhận URL ảnh do storage cấp và nằm trong prefix public (avatars, thumbnails...)
+func isPublicStorageImage(url string) bool {
+	_, ok := storage.KeyFromPublicURL(url)
+	return ok
+}
+
+// renderedHTML trả về HTML đã lưu, bản ghi tạo trước khi có cột HTML thì render tại chỗ
+func renderedHTML(source, rendered string) string {
+	if rendered == "" && source != "" {
+		return renderMarkdown(source)
+	}
+	return rendered
+}
@@ -0,0 +1,30 @@
+package service
+
+import "testing"
+
+func TestIsPublicStorageImage(t *testing.T) {
+	t.Setenv("BASE_URL", "https://lms.example.com")
+	t.Setenv("STORAGE_PUBLIC_BASE_URL", "")
+
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{"https://lms.example.com/uploads/content-images/a.png", true},
+		{"https://lms.example.com/uploads/avatars/a.png?v=2", true},
+		{"https://lms.example.com/uploads/../anything.png", false},
+		{"https://lms.example.com/uploads/avatars/../../x", false},
+		{"https://lms.example.com/uploads/avatars/../videos/1/a.mp4", false},
+		{"https://lms.example.com/uploads/avatars/%2e%2e/submissions/a.png", false},
+		{"https://lms.example.com/uploads/avatars/..%5c..%5cx.png", false},
+		{"https://lms.example.com/uploads/avatars//a.png", false},
+		{"https://lms.example.com/uploads/submissions/1/a.png", false},
+		{"https://lms.example.com/uploads/avatarsx/a.png", false},
+		{"https://evil.com/uploads/avatars/a.png", false},
+	}
+	for _, tt := range tests {
+		if got := isPublicStorageImage(tt.url); got != tt.want {
+			t.Errorf("isPublicStorageImage(%q) = %v, want %v", tt.url, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"lms/src/dto"
+	"lms/src/models"
+	"lms/src/repository"
+	"lms/src/storage"
+	"lms/src/utils"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+// LessonMediaSource là nơi handler lấy video: redirect tới signed URL của storage (S3)
+// hoặc stream trực tiếp file qua app (local storage)
+type LessonMediaSource struct {
+	RedirectURL string
+	Content     io.ReadSeekCloser
+	Name        string
+	ModTime     time.Time
+}
+
+type mediaService struct {
+	lessonRepo     repository.LessonRepository
+	enrollmentRepo repository.EnrollmentRepository
+	storage        storage.Backend
+}
+
+func NewMediaService(lessonRepo repository.LessonRepository, enrollmentRepo repository.EnrollmentRepository, mediaStorage storage.Backend) MediaService {
+	return &mediaService{
+		lessonRepo:     lessonRepo,
+		enrollmentRepo: enrollmentRepo,
+		storage:        mediaStorage,
+	}
+}
+
+func (ms *mediaService) GetLessonMediaURL(userId, lessonId uint) (*dto.LessonMediaResponse, error) {
+	// 1. Lấy lesson
+	lesson, err := findPublishedLesson(ms.lessonRepo, lessonId)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Preview lesson không cần enroll, còn lại phải enroll và lesson đã mở khóa
+	if !lesson.IsPreview {
+		if err := checkLessonAccess(ms.lessonRepo, ms.enrollmentRepo, userId, lesson); err != nil {
+			return nil, err
+		}
+	}
+
+	if lesson.VideoURL == "" {
+		return nil, utils.NewError("This lesson has no video", utils.ErrCodeNotFound)
+	}
+
+	// 3. Tạo signed URL
+	mediaURL, expiresAt, isExternal := buildLessonVideoURL(userId, lesson)
+
+	return &dto.LessonMediaResponse{
+		LessonId:   lesson.Id,
+		URL:        mediaURL,
+		ExpiresAt:  expiresAt,
+		IsExternal: isExternal,
+	}, nil
+}
+
+func (ms *mediaService) ResolveLessonMedia(lessonId uint, query *dto.MediaStreamQuery) (*LessonMediaSource, error) {
+	// 1. Lấy lesson
+	lesson, err := findPublishedLesson(ms.lessonRepo, lessonId)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Kiểm tra chữ ký và enrollment (preview lesson được miễn)
+	if !lesson.IsPreview {
+		if !utils.VerifyMediaSignature(utils.MediaPurposeVideo, query.UserId, lesson.Id, query.ExpiresAt, query.Signature) {
+			return nil, utils.NewError("Invalid or expired media URL", utils.ErrCodeForbidden)
+		}
+
+		// Kiểm tra lại enrollment vì có thể đã bị hủy sau khi URL được tạo
+		if err := checkLessonAccess(ms.lessonRepo, ms.enrollmentRepo, query.UserId, lesson); err != nil {
+			return nil, err
+		}
+	}
+
+	// 3. Chỉ stream được video nằm trong storage
+	key, isStored := lessonMediaKey(lesson)
+	if !isStored || key == "" {
+		return nil, utils.NewError("Media not found", utils.ErrCodeNotFound)
+	}
+
+	ctx := context.Background()
+	info, err := ms.storage.Stat(ctx, key)
+	if errors.Is(err, storage.ErrObjectNotFound) {
+		return nil, utils.NewError("Media not found", utils.ErrCodeNotFound)
+	}
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get media", utils.ErrCodeInternal)
+	}
+
+	// 4. Storage cấp được signed URL thì redirect, client tải trực tiếp (Range do storage xử lý)
+	signedURL, err := ms.storage.SignedURL(ctx, key, utils.MediaURLTTL())
+	if err == nil {
+		return &LessonMediaSource{RedirectURL: signedURL}, nil
+	}
+	if !errors.Is(err, storage.ErrSignedURLUnsupported) {
+		return nil, utils.WrapError(err, "Failed to sign media URL", utils.ErrCodeInternal)
+	}
+
+	// 5. Local storage: stream qua app
+	reader, err := ms.storage.Get(ctx, key)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to open media", utils.ErrCodeInternal)
+	}
+
+	content, ok := reader.(io.ReadSeekCloser)
+	if !ok {
+		reader.Close()
+		return nil, utils.NewError("Media storage does not support streaming", utils.ErrCodeInternal)
+	}
+
+	return &LessonMediaSource{
+		Content: content,
+		Name:    path.Base(key),
+		ModTime: info.LastModified,
+	}, nil
+}
+
+func findPublishedLesson(lessonRepo repository.LessonRepository, lessonId uint) (*models.Lesson, error) {
+	lessons, err := lessonRepo.FindLessonByIds([]uint{lessonId})
+	if err != nil || len(lessons) == 0 || !lessons[0].IsPublished {
+		return nil, utils.NewError("Lesson not found", utils.ErrCodeNotFound)
+	}
+	return &lessons[0], nil
+}
+
+// checkLessonAccess - học viên phải enroll course và lesson đã mở khóa
+func checkLessonAccess(lessonRepo repository.LessonRepository, enrollmentRepo repository.EnrollmentRepository, userId uint, lesson *models.Lesson) error {
+	enrollment, isEnrolled := enrollmentRepo.CheckEnrollment(userId, lesson.CourseId)
+	if !isEnrolled {
+		return utils.NewError("You must enroll in this course to access this lesson", utils.ErrCodeForbidden)
+	}
+
+	lock, err := resolveLessonLock(lessonRepo, userId, lesson, enrollment.EnrolledAt)
+	if err != nil {
+		return utils.WrapError(err, "Failed to check lesson lock", utils.ErrCodeInternal)
+	}
+	if lock.IsLocked {
+		return utils.NewError(lessonLockMessage(lock.Reason), utils.ErrCodeForbidden)
+	}
+
+	return nil
+}
+
+// buildLessonVideoURL trả về URL video cho user:
+// video trong storage -> signed URL qua media stream (preview lesson không cần chữ ký), video bên ngoài -> trả nguyên bản
+func buildLessonVideoURL(userId uint, lesson *models.Lesson) (string, *time.Time, bool) {
+	if lesson.VideoURL == "" {
+		return "", nil, false
+	}
+
+	if _, isStored := lessonMediaKey(lesson); !isStored {
+		return lesson.VideoURL, nil, true
+	}
+
+	baseURL := utils.GetEnv("BASE_URL", "http://localhost:8080")
+	streamURL := fmt.Sprintf("%s/api/v1/media/lessons/%d/stream", baseURL, lesson.Id)
+	if lesson.IsPreview {
+		return streamURL, nil, false
+	}
+
+	expiresAt := time.Now().Add(utils.MediaURLTTL())
+	query := url.Values{}
+	query.Set("uid", fmt.Sprint(userId))
+	query.Set("exp", fmt.Sprint(expiresAt.Unix()))
+	query.Set("sig", utils.SignMediaURL(utils.MediaPurposeVideo, userId, lesson.Id, expiresAt.Unix()))
+
+	return streamURL + "?" + query.Encode(), &expiresAt, false
+}
+
+// lessonMediaKey map VideoURL của lesson sang storage key:
+// key tương đối ("videos/1/2/abc.mp4") hoặc URL cũ dạng "{BASE_URL}/uploads/..." -> key, URL http(s) khác -> video bên ngoài.
+// Chỉ key nằm trong "videos/{course_id}/" của chính course mới hợp lệ, key khác (resource, bài nộp, video course khác)
+// trả về key rỗng để signed URL không stream được file ngoài phạm vi lesson
+func lessonMediaKey(lesson *models.Lesson) (string, bool) {
+	baseURL := utils.GetEnv("BASE_URL", "http://localhost:8080")
+	videoURL := strings.TrimPrefix(lesson.VideoURL, baseURL)
+
+	if strings.HasPrefix(videoURL, "http://") || strings.HasPrefix(videoURL, "https://") {
+		return "", false
+	}
+
+	videoURL = strings.TrimPrefix(videoURL, "/uploads/")
+
+	// Clean với "/" ở đầu để loại bỏ "../"
+	key := strings.TrimPrefix(path.Clean("/"+videoURL), "/")
+	if !strings.HasPrefix(key, lessonVideoPrefix(lesson.CourseId)) {
+		return "", true
+	}
+	return key, true
+}
+
+// lessonVideoPrefix - prefix storage chứa video của các lesson trong course
+func lessonVideoPrefix(courseId uint) string {
+	return fmt.Sprintf("videos/%d/", courseId)
+}
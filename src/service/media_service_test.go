@@ -0,0 +1,34 @@
+package service
+
+import (
+	"lms/src/models"
+	"testing"
+)
+
+func TestLessonMediaKey(t *testing.T) {
+	t.Setenv("BASE_URL", "https://lms.example.com")
+
+	tests := []struct {
+		name     string
+		videoURL string
+		wantKey  string
+		isStored bool
+	}{
+		{"stored key", "videos/5/12/a.mp4", "videos/5/12/a.mp4", true},
+		{"uploads url", "https://lms.example.com/uploads/videos/5/12/a.mp4", "videos/5/12/a.mp4", true},
+		{"external video", "https://youtube.com/watch?v=x", "", false},
+		{"other course", "videos/6/12/a.mp4", "", true},
+		{"traversal out of course", "videos/5/../6/12/a.mp4", "", true},
+		{"traversal out of storage", "/uploads/../../etc/passwd", "", true},
+		{"prefix lookalike", "videos/55/12/a.mp4", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, isStored := lessonMediaKey(&models.Lesson{CourseId: 5, VideoURL: tt.videoURL})
+			if key != tt.wantKey || isStored != tt.isStored {
+				t.Errorf("got (%q, %v), want (%q, %v)", key, isStored, tt.wantKey, tt.isStored)
+			}
+		})
+	}
+}
@@ -13,10 +13,11 @@ import (
 )
 
 type orderService struct {
-	orderRepo      repository.OrderRepository
-	courseRepo     repository.CourseRepository
-	couponRepo     repository.CouponRepository
-	enrollmentRepo repository.EnrollmentRepository
+	orderRepo        repository.OrderRepository
+	courseRepo       repository.CourseRepository
+	couponRepo       repository.CouponRepository
+	enrollmentRepo   repository.EnrollmentRepository
+	prerequisiteRepo repository.CoursePrerequisiteRepository
 }
 
 func NewOrderService(
@@ -24,13 +25,14 @@ func NewOrderService(
 	courseRepo repository.CourseRepository,
 	couponRepo repository.CouponRepository,
 	enrollmentRepo repository.EnrollmentRepository,
-
+	prerequisiteRepo repository.CoursePrerequisiteRepository,
 ) OrderService {
 	return &orderService{
-		orderRepo:      orderRepo,
-		courseRepo:     courseRepo,
-		enrollmentRepo: enrollmentRepo,
-		couponRepo:     couponRepo,
+		orderRepo:        orderRepo,
+		courseRepo:       courseRepo,
+		enrollmentRepo:   enrollmentRepo,
+		couponRepo:       couponRepo,
+		prerequisiteRepo: prerequisiteRepo,
 	}
 }
 
@@ -46,11 +48,14 @@ func (os *orderService) CreateOrder(userId uint, req *dto.CreateOrderRequest) (*
 		return nil, utils.NewError("Course is not available for purchase", utils.ErrCodeBadRequest)
 	}
 
-	// 3. Kiểm tra user đã mua course chưa
-	if existingEnrollment, exists := os.enrollmentRepo.CheckEnrollment(userId, req.CourseId); exists {
-		if existingEnrollment.Status == "active" {
-			return nil, utils.NewError("You already own this course", utils.ErrCodeConflict)
-		}
+	// 3. Kiểm tra user đã mua course chưa (enrollment đã drop/hết hạn hoặc có thời hạn thì được mua lại để gia hạn)
+	if existingEnrollment, exists := os.enrollmentRepo.FindEnrollment(userId, req.CourseId); exists && !canRenewEnrollment(existingEnrollment) {
+		return nil, utils.NewError("You already own this course", utils.ErrCodeConflict)
+	}
+
+	// Kiểm tra user đã hoàn thành các course prerequisites chưa
+	if err := checkCoursePrerequisites(os.prerequisiteRepo, userId, req.CourseId); err != nil {
+		return nil, err
 	}
 
 	// 4. Kiểm tra đã có order pending chưa
@@ -115,14 +120,15 @@ func (os *orderService) CreateOrder(userId uint, req *dto.CreateOrderRequest) (*
 
 	// 9. Tạo order
 	order := &models.Order{
-		UserId:         userId,
-		CourseId:       req.CourseId,
-		OrderCode:      orderCode,
-		OriginalPrice:  originalPrice,
-		DiscountAmount: discountAmount,
-		FinalPrice:     finalPrice,
-		CouponId:       couponId,
-		PaymentStatus:  "pending",
+		UserId:             userId,
+		CourseId:           req.CourseId,
+		OrderCode:          orderCode,
+		OriginalPrice:      originalPrice,
+		DiscountAmount:     discountAmount,
+		FinalPrice:         finalPrice,
+		CouponId:           couponId,
+		PaymentStatus:      "pending",
+		AccessDurationDays: course.AccessDurationDays,
 	}
 
 	if err := os.orderRepo.Create(order); err != nil {
@@ -249,16 +255,8 @@ func (os *orderService) completeOrder(order *models.Order, paymentMethod string)
 		return utils.WrapError(err, "Failed to update order", utils.ErrCodeInternal)
 	}
 
-	// Create enrollment
-	enrollment := &models.Enrollment{
-		UserId:             order.UserId,
-		CourseId:           order.CourseId,
-		EnrolledAt:         now,
-		ProgressPercentage: 0,
-		Status:             "active",
-	}
-
-	if err := os.enrollmentRepo.Create(enrollment); err != nil {
+	// Create enrollment (hoặc kích hoạt lại/gia hạn enrollment cũ)
+	if _, err := grantEnrollment(os.enrollmentRepo, orderEnrollmentGrant(order), now); err != nil {
 		return utils.WrapError(err, "Failed to create enrollment", utils.ErrCodeInternal)
 	}
 
@@ -524,19 +522,9 @@ func (os *orderService) UpdateOrderStatus(orderId uint, req *dto.UpdateOrderStat
 
 	// 4. Handle status change to 'paid'
 	if req.Status == "paid" {
-		// Create enrollment if not exists
-		if _, exists := os.enrollmentRepo.CheckEnrollment(order.UserId, order.CourseId); !exists {
-			enrollment := &models.Enrollment{
-				UserId:             order.UserId,
-				CourseId:           order.CourseId,
-				EnrolledAt:         time.Now(),
-				ProgressPercentage: 0,
-				Status:             "active",
-			}
-
-			if err := os.enrollmentRepo.Create(enrollment); err != nil {
-				return nil, utils.WrapError(err, "Failed to create enrollment", utils.ErrCodeInternal)
-			}
+		// Create enrollment (hoặc kích hoạt lại/gia hạn enrollment cũ)
+		if _, err := grantEnrollment(os.enrollmentRepo, orderEnrollmentGrant(order), time.Now()); err != nil {
+			return nil, utils.WrapError(err, "Failed to create enrollment", utils.ErrCodeInternal)
 		}
 
 		// Increment coupon used count
@@ -286,6 +286,11 @@ func (ps *paymentService) CheckPaymentStatus(userId uint, req *dto.CheckPaymentS
 
 // Helper function to complete payment
 func (ps *paymentService) completePayment(order *models.Order) error {
+	// Callback gửi lại cho order đã thanh toán: không gia hạn enrollment lần nữa
+	if order.PaymentStatus == dto.PaymentStatusPaid {
+		return nil
+	}
+
 	// 1. Update order status
 	now := time.Now()
 	order.PaymentStatus = dto.PaymentStatusPaid
@@ -295,19 +300,9 @@ func (ps *paymentService) completePayment(order *models.Order) error {
 		return utils.WrapError(err, "Failed to update order", utils.ErrCodeInternal)
 	}
 
-	// 2. Create enrollment if not exists
-	if _, exists := ps.enrollmentRepo.CheckEnrollment(order.UserId, order.CourseId); !exists {
-		enrollment := &models.Enrollment{
-			UserId:             order.UserId,
-			CourseId:           order.CourseId,
-			EnrolledAt:         now,
-			ProgressPercentage: 0,
-			Status:             "active",
-		}
-
-		if err := ps.enrollmentRepo.Create(enrollment); err != nil {
-			return utils.WrapError(err, "Failed to create enrollment", utils.ErrCodeInternal)
-		}
+	// 2. Create enrollment (hoặc kích hoạt lại/gia hạn enrollment cũ)
+	if _, err := grantEnrollment(ps.enrollmentRepo, orderEnrollmentGrant(order), now); err != nil {
+		return utils.WrapError(err, "Failed to create enrollment", utils.ErrCodeInternal)
 	}
 
 	// 3. Update coupon used count
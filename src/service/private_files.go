@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"lms/src/storage"
+	"lms/src/utils"
+	"mime/multipart"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PrivateFile là file private (resource, bài nộp) đọc từ storage, handler stream về client với tên file gốc
+type PrivateFile struct {
+	Content     io.ReadCloser
+	Name        string
+	Size        int64
+	ContentType string
+	ModTime     time.Time
+}
+
+// storeUploadedFile validate file upload theo rules rồi lưu vào storage dưới keyPrefix với tên mới (uuid + ext),
+// trả về tên file đã lưu và MIME type
+func storeUploadedFile(ctx context.Context, backend storage.Backend, keyPrefix string, file *multipart.FileHeader, rules utils.FileRules) (string, string, error) {
+	ext, mimeType, err := utils.ValidateFile(file, rules)
+	if err != nil {
+		return "", "", err
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return "", "", errors.New("cannot open file")
+	}
+	defer src.Close()
+
+	storedName, err := storeFileContent(ctx, backend, keyPrefix, ext, src, file.Size, mimeType)
+	if err != nil {
+		return "", "", err
+	}
+	return storedName, mimeType, nil
+}
+
+// storeFileContent ghi nội dung r vào storage dưới keyPrefix với tên mới (uuid + ext), trả về tên file đã lưu
+func storeFileContent(ctx context.Context, backend storage.Backend, keyPrefix, ext string, r io.Reader, size int64, contentType string) (string, error) {
+	storedName := uuid.NewString() + strings.ToLower(ext)
+	if err := backend.Put(ctx, keyPrefix+storedName, r, size, contentType); err != nil {
+		return "", fmt.Errorf("write %s: %w", keyPrefix+storedName, err)
+	}
+	return storedName, nil
+}
+
+// openPrivateFile mở file trong storage để tải về, file không còn thì trả về NotFound
+func openPrivateFile(ctx context.Context, backend storage.Backend, key, name, contentType string) (*PrivateFile, error) {
+	info, err := backend.Stat(ctx, key)
+	if errors.Is(err, storage.ErrObjectNotFound) {
+		return nil, utils.NewError("File not found", utils.ErrCodeNotFound)
+	}
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get file", utils.ErrCodeInternal)
+	}
+
+	content, err := backend.Get(ctx, key)
+	if errors.Is(err, storage.ErrObjectNotFound) {
+		return nil, utils.NewError("File not found", utils.ErrCodeNotFound)
+	}
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to open file", utils.ErrCodeInternal)
+	}
+
+	if contentType == "" {
+		contentType = info.ContentType
+	}
+	return &PrivateFile{
+		Content:     content,
+		Name:        name,
+		Size:        info.Size,
+		ContentType: contentType,
+		ModTime:     info.LastModified,
+	}, nil
+}
@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"lms/src/models"
+	"lms/src/repository"
+	"lms/src/utils"
+	"log"
+	"math"
+	"strconv"
+	"time"
+)
+
+// Lý do tạo job tính lại progress (lưu để tra cứu)
+const (
+	recalcReasonLessonCreated     = "lesson_created"
+	recalcReasonLessonUpdated     = "lesson_updated"
+	recalcReasonLessonDeleted     = "lesson_deleted"
+	recalcReasonLessonDuplicated  = "lesson_duplicated"
+	recalcReasonLessonSchedule    = "lesson_schedule"
+	recalcReasonRevisionPublished = "revision_published"
+)
+
+const (
+	recalcStaleAfter   = 10 * time.Minute // Job running không cập nhật quá lâu coi như process đã chết
+	recalcMaxAttempts  = 5
+	recalcRetention    = 7 * 24 * time.Hour // Giữ job đã xong để tra cứu
+	progressEpsilon    = 0.01
+	enrollmentActive   = "active"
+	enrollmentComplete = "completed"
+)
+
+type progressRecalculationService struct {
+	recalcRepo      repository.ProgressRecalculationRepository
+	certificateRepo repository.CertificateRepository
+}
+
+func NewProgressRecalculationService(recalcRepo repository.ProgressRecalculationRepository, certificateRepo repository.CertificateRepository) ProgressRecalculationService {
+	return &progressRecalculationService{
+		recalcRepo:      recalcRepo,
+		certificateRepo: certificateRepo,
+	}
+}
+
+// progressRecalcBatchSize - số enrollment xử lý mỗi batch
+func progressRecalcBatchSize() int {
+	return max(utils.GetEnvInt("PROGRESS_RECALC_BATCH_SIZE", 200), 1)
+}
+
+// revertCompletedEnrollments - policy: enrollment đã hoàn thành có bị chuyển lại active khi course thêm lesson mới không.
+// Mặc định false: học viên đã hoàn thành giữ nguyên trạng thái (và chứng chỉ). Chứng chỉ không bị thu hồi trong cả hai trường hợp
+func revertCompletedEnrollments() bool {
+	revert, err := strconv.ParseBool(utils.GetEnv("PROGRESS_RECALC_REVERT_COMPLETED", "false"))
+	return err == nil && revert
+}
+
+// RunPending xử lý lần lượt các job đang chờ cho tới khi hết job hoặc ctx bị hủy
+func (rs *progressRecalculationService) RunPending(ctx context.Context) error {
+	for ctx.Err() == nil {
+		job, err := rs.recalcRepo.ClaimNext(recalcStaleAfter)
+		if err != nil {
+			return err
+		}
+		if job == nil {
+			break
+		}
+
+		if err := rs.processJob(ctx, job); err != nil {
+			rs.failJob(job, err)
+		}
+	}
+
+	if deleted, err := rs.recalcRepo.DeleteFinishedBefore(time.Now().Add(-recalcRetention)); err != nil {
+		log.Printf("Failed to clean up progress recalculation jobs: %v", err)
+	} else if deleted > 0 {
+		log.Printf("🧹 Deleted %d finished progress recalculation job(s)", deleted)
+	}
+
+	return nil
+}
+
+// processJob tính lại progress theo batch, cursor được lưu sau mỗi batch để job chạy tiếp nếu bị gián đoạn
+func (rs *progressRecalculationService) processJob(ctx context.Context, job *models.ProgressRecalculation) error {
+	// 1. Tổng số lessons hiện tại của course
+	totalLessons, err := rs.recalcRepo.CountCourseLessons(job.CourseId)
+	if err != nil {
+		return err
+	}
+
+	revert := revertCompletedEnrollments()
+	batchSize := progressRecalcBatchSize()
+
+	// 2. Tính lại từng batch enrollment. Course không còn lesson nào (đã xóa/ẩn hết) vẫn được xử lý để đưa progress về 0
+	for {
+		if ctx.Err() != nil {
+			// Trả job về pending, lần chạy sau tiếp tục từ cursor
+			return rs.recalcRepo.UpdateJob(job.Id, map[string]interface{}{"status": "pending"})
+		}
+
+		enrollments, err := rs.recalcRepo.GetEnrollmentBatch(job.CourseId, job.LastEnrollmentId, batchSize)
+		if err != nil {
+			return err
+		}
+		if len(enrollments) == 0 {
+			break
+		}
+
+		userIds := make([]uint, len(enrollments))
+		for i, enrollment := range enrollments {
+			userIds[i] = enrollment.UserId
+		}
+		completedCounts, err := rs.recalcRepo.CountCompletedLessonsByUsers(job.CourseId, userIds)
+		if err != nil {
+			return err
+		}
+
+		for i := range enrollments {
+			updated, err := rs.recalculateEnrollment(&enrollments[i], completedCounts[enrollments[i].UserId], totalLessons, revert)
+			if err != nil {
+				return err
+			}
+			if updated {
+				job.UpdatedCount++
+			}
+			job.ProcessedCount++
+		}
+
+		job.LastEnrollmentId = enrollments[len(enrollments)-1].Id
+		if err := rs.recalcRepo.UpdateJob(job.Id, map[string]interface{}{
+			"last_enrollment_id": job.LastEnrollmentId,
+			"processed_count":    job.ProcessedCount,
+			"updated_count":      job.UpdatedCount,
+			"updated_at":         time.Now(),
+		}); err != nil {
+			return err
+		}
+	}
+
+	// 3. Đánh dấu hoàn thành
+	if job.UpdatedCount > 0 {
+		log.Printf("📊 Recalculated progress for course %d: %d/%d enrollment(s) updated", job.CourseId, job.UpdatedCount, job.ProcessedCount)
+	}
+	return rs.recalcRepo.UpdateJob(job.Id, map[string]interface{}{
+		"status":      "completed",
+		"last_error":  "",
+		"finished_at": time.Now(),
+	})
+}
+
+// recalculateEnrollment cập nhật progress_percentage và status theo số lessons hiện tại, trả về true nếu có thay đổi.
+// Course không còn lesson: progress = 0, enrollment active không được tự hoàn thành, enrollment đã hoàn thành theo policy revert
+func (rs *progressRecalculationService) recalculateEnrollment(enrollment *models.Enrollment, completedCount, totalLessons int, revert bool) (bool, error) {
+	percentage := 0.0
+	if totalLessons > 0 {
+		percentage = math.Min(float64(completedCount)/float64(totalLessons)*100, 100)
+	}
+	updates := make(map[string]interface{})
+
+	switch {
+	case enrollment.Status == enrollmentComplete && percentage < 100:
+		// Course có thêm lesson (hoặc bị gỡ hết lesson) sau khi học viên hoàn thành
+		if !revert {
+			return false, nil
+		}
+		updates["status"] = enrollmentActive
+		updates["completed_at"] = nil
+	case enrollment.Status == enrollmentActive && percentage >= 100:
+		// Lesson còn thiếu bị xóa/ẩn: học viên đã hoàn thành mọi lesson còn lại
+		updates["status"] = enrollmentComplete
+		updates["completed_at"] = time.Now()
+	}
+
+	if math.Abs(enrollment.ProgressPercentage-percentage) > progressEpsilon {
+		updates["progress_percentage"] = percentage
+	}
+	if len(updates) == 0 {
+		return false, nil
+	}
+
+	if err := rs.recalcRepo.UpdateEnrollment(enrollment.Id, updates); err != nil {
+		return false, err
+	}
+
+	if updates["status"] == enrollmentComplete {
+		if _, err := issueCertificate(rs.certificateRepo, enrollment.Id); err != nil {
+			log.Printf("Failed to issue certificate for enrollment %d: %v", enrollment.Id, err)
+		}
+	}
+
+	return true, nil
+}
+
+// failJob trả job về pending để thử lại ở tick sau (giữ cursor), quá số lần thử thì đánh dấu failed
+func (rs *progressRecalculationService) failJob(job *models.ProgressRecalculation, jobErr error) {
+	updates := map[string]interface{}{
+		"status":     "pending",
+		"last_error": jobErr.Error(),
+	}
+	if job.Attempts >= recalcMaxAttempts {
+		updates["status"] = "failed"
+		updates["finished_at"] = time.Now()
+	}
+
+	if err := rs.recalcRepo.UpdateJob(job.Id, updates); err != nil {
+		log.Printf("Failed to update progress recalculation job %d: %v", job.Id, err)
+	}
+	log.Printf("❌ Progress recalculation job %d for course %d failed (attempt %d): %v", job.Id, job.CourseId, job.Attempts, jobErr)
+}
+
+// enqueueProgressRecalculation tạo job tính lại progress sau khi nội dung course thay đổi.
+// Lỗi chỉ ghi log để không chặn thao tác của giảng viên
+func enqueueProgressRecalculation(recalcRepo repository.ProgressRecalculationRepository, courseId uint, reason string) {
+	if err := recalcRepo.Enqueue(courseId, reason); err != nil {
+		log.Printf("Failed to enqueue progress recalculation for course %d (%s): %v", courseId, reason, err)
+	}
+}
+
+// newProgressRecalculation tạo job trong transaction của thao tác thay đổi nội dung (commit cùng nhau)
+func newProgressRecalculation(courseId uint, reason string) *models.ProgressRecalculation {
+	return &models.ProgressRecalculation{
+		CourseId: courseId,
+		Reason:   reason,
+		Status:   "pending",
+	}
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"lms/src/models"
+	"lms/src/repository"
+	"testing"
+	"time"
+)
+
+type fakeRecalcRepo struct {
+	repository.ProgressRecalculationRepository
+	totalLessons int
+	enrollments  []models.Enrollment
+	completed    map[uint]int
+	updates      map[uint]map[string]interface{}
+	jobUpdates   []map[string]interface{}
+}
+
+func (r *fakeRecalcRepo) CountCourseLessons(courseId uint) (int, error) {
+	return r.totalLessons, nil
+}
+
+func (r *fakeRecalcRepo) GetEnrollmentBatch(courseId, afterId uint, limit int) ([]models.Enrollment, error) {
+	var batch []models.Enrollment
+	for _, enrollment := range r.enrollments {
+		if enrollment.Id > afterId && len(batch) < limit {
+			batch = append(batch, enrollment)
+		}
+	}
+	return batch, nil
+}
+
+func (r *fakeRecalcRepo) CountCompletedLessonsByUsers(courseId uint, userIds []uint) (map[uint]int, error) {
+	return r.completed, nil
+}
+
+func (r *fakeRecalcRepo) UpdateEnrollment(enrollmentId uint, updates map[string]interface{}) error {
+	r.updates[enrollmentId] = updates
+	return nil
+}
+
+func (r *fakeRecalcRepo) UpdateJob(jobId uint, updates map[string]interface{}) error {
+	r.jobUpdates = append(r.jobUpdates, updates)
+	return nil
+}
+
+func TestProcessJobRecalculatesEnrollments(t *testing.T) {
+	completedAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	enrollments := []models.Enrollment{
+		{Id: 1, UserId: 11, Status: enrollmentActive, ProgressPercentage: 50},
+		{Id: 2, UserId: 12, Status: enrollmentComplete, ProgressPercentage: 100, CompletedAt: &completedAt},
+		{Id: 3, UserId: 13, Status: enrollmentActive, ProgressPercentage: 0},
+	}
+
+	tests := []struct {
+		name         string
+		totalLessons int
+		completed    map[uint]int
+		revert       string
+		want         map[uint]map[string]interface{}
+	}{
+		{
+			name:         "lesson added",
+			totalLessons: 4,
+			completed:    map[uint]int{11: 2, 12: 3},
+			revert:       "false",
+			want:         map[uint]map[string]interface{}{},
+		},
+		{
+			name:         "lesson added with revert",
+			totalLessons: 4,
+			completed:    map[uint]int{11: 2, 12: 3},
+			revert:       "true",
+			want: map[uint]map[string]interface{}{
+				2: {"status": enrollmentActive, "completed_at": nil, "progress_percentage": 75.0},
+			},
+		},
+		{
+			name:         "all lessons removed",
+			totalLessons: 0,
+			completed:    map[uint]int{},
+			revert:       "false",
+			want: map[uint]map[string]interface{}{
+				1: {"progress_percentage": 0.0},
+			},
+		},
+		{
+			name:         "all lessons removed with revert",
+			totalLessons: 0,
+			completed:    map[uint]int{},
+			revert:       "true",
+			want: map[uint]map[string]interface{}{
+				1: {"progress_percentage": 0.0},
+				2: {"status": enrollmentActive, "completed_at": nil, "progress_percentage": 0.0},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("PROGRESS_RECALC_REVERT_COMPLETED", tt.revert)
+			t.Setenv("PROGRESS_RECALC_BATCH_SIZE", "2")
+
+			repo := &fakeRecalcRepo{
+				totalLessons: tt.totalLessons,
+				enrollments:  enrollments,
+				completed:    tt.completed,
+				updates:      make(map[uint]map[string]interface{}),
+			}
+			rs := &progressRecalculationService{recalcRepo: repo}
+			job := &models.ProgressRecalculation{Id: 1, CourseId: 5}
+
+			if err := rs.processJob(context.Background(), job); err != nil {
+				t.Fatal(err)
+			}
+
+			if job.ProcessedCount != len(enrollments) || job.UpdatedCount != len(tt.want) {
+				t.Errorf("processed %d updated %d, want %d and %d", job.ProcessedCount, job.UpdatedCount, len(enrollments), len(tt.want))
+			}
+			if len(repo.updates) != len(tt.want) {
+				t.Errorf("updated enrollments %v, want %v", repo.updates, tt.want)
+			}
+			for id, want := range tt.want {
+				got := repo.updates[id]
+				if len(got) != len(want) {
+					t.Errorf("enrollment %d: got %v, want %v", id, got, want)
+					continue
+				}
+				for key, value := range want {
+					if got[key] != value {
+						t.Errorf("enrollment %d: %s = %v, want %v", id, key, got[key], value)
+					}
+				}
+			}
+
+			last := repo.jobUpdates[len(repo.jobUpdates)-1]
+			if last["status"] != "completed" {
+				t.Errorf("job status %v, want completed", last["status"])
+			}
+		})
+	}
+}
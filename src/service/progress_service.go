@@ -6,14 +6,20 @@ import (
 	"lms/src/models"
 	"lms/src/repository"
 	"lms/src/utils"
+	"log"
 	"time"
 )
 
 type progressService struct {
-	progressRepo   repository.ProgressRepository
-	enrollmentRepo repository.EnrollmentRepository
-	courseRepo     repository.CourseRepository
-	lessonRepo     repository.LessonRepository
+	progressRepo    repository.ProgressRepository
+	enrollmentRepo  repository.EnrollmentRepository
+	courseRepo      repository.CourseRepository
+	lessonRepo      repository.LessonRepository
+	quizRepo        repository.QuizRepository
+	assignmentRepo  repository.AssignmentRepository
+	scormRepo       repository.ScormRepository
+	certificateRepo repository.CertificateRepository
+	activityRepo    repository.LearningActivityRepository
 }
 
 func NewProgressService(
@@ -21,15 +27,28 @@ func NewProgressService(
 	enrollmentRepo repository.EnrollmentRepository,
 	courseRepo repository.CourseRepository,
 	lessonRepo repository.LessonRepository,
+	quizRepo repository.QuizRepository,
+	assignmentRepo repository.AssignmentRepository,
+	scormRepo repository.ScormRepository,
+	certificateRepo repository.CertificateRepository,
+	activityRepo repository.LearningActivityRepository,
 ) ProgressService {
 	return &progressService{
-		progressRepo:   progressRepo,
-		enrollmentRepo: enrollmentRepo,
-		courseRepo:     courseRepo,
-		lessonRepo:     lessonRepo,
+		progressRepo:    progressRepo,
+		enrollmentRepo:  enrollmentRepo,
+		courseRepo:      courseRepo,
+		lessonRepo:      lessonRepo,
+		quizRepo:        quizRepo,
+		assignmentRepo:  assignmentRepo,
+		scormRepo:       scormRepo,
+		certificateRepo: certificateRepo,
+		activityRepo:    activityRepo,
 	}
 }
 
+// enrollmentTouchPeriod - heartbeat gửi liên tục nên last_accessed_at chỉ được cập nhật mỗi 5 phút
+const enrollmentTouchPeriod = 5 * time.Minute
+
 func (ps *progressService) GetCourseProgress(userId, courseId uint) (*dto.GetCourseProgressResponse, error) {
 	// 1. Kiểm tra course có tồn tại không
 	course, err := ps.courseRepo.FindById(courseId)
@@ -78,6 +97,12 @@ func (ps *progressService) GetCourseProgress(userId, courseId uint) (*dto.GetCou
 		}
 	}
 
+	// Điểm quiz/assignment hiển thị cùng progress
+	gradeMap, err := ps.getLessonGrades(userId, courseId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get lesson grades", utils.ErrCodeInternal)
+	}
+
 	// 5. Tính toán progress cho từng lesson
 	totalDuration := 0   // tổng thời lượng video của tất cả lessons.
 	watchedDuration := 0 // tổng thời lượng mà user đã xem.
@@ -117,6 +142,7 @@ func (ps *progressService) GetCourseProgress(userId, courseId uint) (*dto.GetCou
 			LessonId:        lesson.Id,
 			Title:           lesson.Title,
 			Slug:            lesson.Slug,
+			LessonType:      lesson.LessonType,
 			LessonOrder:     lesson.LessonOrder,
 			VideoDuration:   lesson.VideoDuration,
 			IsCompleted:     isCompleted,
@@ -124,6 +150,7 @@ func (ps *progressService) GetCourseProgress(userId, courseId uint) (*dto.GetCou
 			WatchDuration:   watchDuration,
 			LastPosition:    lastPosition,
 			ProgressPercent: progressPercent,
+			Grade:           gradeMap[lesson.Id],
 		})
 
 		progressMap[lesson.Id] = &lessonItems[len(lessonItems)-1]
@@ -161,17 +188,51 @@ func (ps *progressService) CompleteLesson(userId, lessonId uint, req *dto.Comple
 	lesson := lessons[0]
 
 	// 2. Kiểm tra user đã enroll course chưa
-	_, isEnrolled := ps.enrollmentRepo.CheckEnrollment(userId, lesson.CourseId)
+	enrollment, isEnrolled := ps.enrollmentRepo.CheckEnrollment(userId, lesson.CourseId)
 	if !isEnrolled {
 		return nil, utils.NewError("You are not enrolled in this course", utils.ErrCodeForbidden)
 	}
 
-	// 3. Lấy hoặc tạo progress record
-	progress, err := ps.progressRepo.GetLessonProgress(userId, lessonId)
+	// Lesson bị khóa (drip content) thì chưa được hoàn thành
+	lock, err := resolveLessonLock(ps.lessonRepo, userId, &lesson, enrollment.EnrolledAt)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to check lesson lock", utils.ErrCodeInternal)
+	}
+	if lock.IsLocked {
+		return nil, utils.NewError(lessonLockMessage(lock.Reason), utils.ErrCodeForbidden)
+	}
+
+	// 3. Lấy hoặc tạo progress record, khóa để không ghi đè các đoạn đã xem của heartbeat chạy song song
+	tx := ps.progressRepo.BeginTransaction()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	progress, err := ps.progressRepo.LockLessonProgress(tx, userId, lesson.CourseId, lessonId)
 	if err != nil {
+		tx.Rollback()
 		return nil, utils.WrapError(err, "Failed to get lesson progress", utils.ErrCodeInternal)
 	}
 
+	// Kiểm tra điều kiện hoàn thành theo loại lesson
+	if err := ps.checkCompletionRequirements(userId, &lesson, progress); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	wasCompleted := progress != nil && progress.IsCompleted
+
+	// Lesson video dùng thời lượng server tính từ heartbeat, không lấy số client gửi lên
+	watchDuration := req.WatchDuration
+	if lesson.LessonType == lessonTypeVideo {
+		watchDuration = 0
+		if progress != nil {
+			watchDuration = watchedSeconds(progress.WatchedIntervals)
+		}
+	}
+
 	// Nếu chưa có progress, tạo mới
 	if progress == nil {
 		now := time.Now()
@@ -181,7 +242,7 @@ func (ps *progressService) CompleteLesson(userId, lessonId uint, req *dto.Comple
 			CourseId:      lesson.CourseId,
 			IsCompleted:   true,
 			CompletedAt:   &now,
-			WatchDuration: req.WatchDuration,
+			WatchDuration: watchDuration,
 			LastPosition:  lesson.VideoDuration, // Set to end
 		}
 	} else {
@@ -189,14 +250,18 @@ func (ps *progressService) CompleteLesson(userId, lessonId uint, req *dto.Comple
 		now := time.Now()
 		progress.IsCompleted = true
 		progress.CompletedAt = &now
-		progress.WatchDuration = req.WatchDuration
+		progress.WatchDuration = watchDuration
 		progress.LastPosition = lesson.VideoDuration
 	}
 
 	// 4. Lưu progress
-	if err := ps.progressRepo.UpdateProgress(progress); err != nil {
+	if err := tx.Save(progress).Error; err != nil {
+		tx.Rollback()
 		return nil, utils.WrapError(err, "Failed to update progress", utils.ErrCodeInternal)
 	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, utils.WrapError(err, "Failed to commit transaction", utils.ErrCodeInternal)
+	}
 
 	// 5. Cập nhật enrollment progress percentage
 	if err := ps.updateEnrollmentProgress(userId, lesson.CourseId); err != nil {
@@ -204,6 +269,13 @@ func (ps *progressService) CompleteLesson(userId, lessonId uint, req *dto.Comple
 		fmt.Printf("Failed to update enrollment progress: %v\n", err)
 	}
 
+	// Hoàn thành lại lesson đã học không tính thêm vào thống kê
+	if !wasCompleted {
+		activity := learningActivityLog{}
+		activity.add(*progress.CompletedAt, 0, 1)
+		recordLearningActivity(ps.activityRepo, userId, activity)
+	}
+
 	return &dto.CompleteLessonResponse{
 		LessonId:      lessonId,
 		CourseId:      lesson.CourseId,
@@ -223,7 +295,7 @@ func (ps *progressService) UpdateLessonPosition(userId, lessonId uint, req *dto.
 	lesson := lessons[0]
 
 	// 2. Kiểm tra user đã enroll course chưa
-	_, isEnrolled := ps.enrollmentRepo.CheckEnrollment(userId, lesson.CourseId)
+	enrollment, isEnrolled := ps.enrollmentRepo.CheckEnrollment(userId, lesson.CourseId)
 	if !isEnrolled {
 		return nil, utils.NewError("You are not enrolled in this course", utils.ErrCodeForbidden)
 	}
@@ -233,32 +305,44 @@ func (ps *progressService) UpdateLessonPosition(userId, lessonId uint, req *dto.
 		req.LastPosition = lesson.VideoDuration
 	}
 
-	// 4. Lấy hoặc tạo progress record
-	progress, err := ps.progressRepo.GetLessonProgress(userId, lessonId)
+	// 4. Lấy hoặc tạo progress record, khóa để không ghi đè các đoạn đã xem của heartbeat chạy song song
+	tx := ps.progressRepo.BeginTransaction()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	progress, err := ps.progressRepo.LockLessonProgress(tx, userId, lesson.CourseId, lessonId)
 	if err != nil {
+		tx.Rollback()
 		return nil, utils.WrapError(err, "Failed to get lesson progress", utils.ErrCodeInternal)
 	}
 
+	// Chỉ lưu vị trí resume, thời lượng xem chỉ tăng qua heartbeat
 	if progress == nil {
 		// Tạo mới progress
 		progress = &models.Progress{
-			UserId:        userId,
-			LessonId:      lessonId,
-			CourseId:      lesson.CourseId,
-			IsCompleted:   false,
-			WatchDuration: req.WatchDuration,
-			LastPosition:  req.LastPosition,
+			UserId:       userId,
+			LessonId:     lessonId,
+			CourseId:     lesson.CourseId,
+			IsCompleted:  false,
+			LastPosition: req.LastPosition,
 		}
 	} else {
 		// Cập nhật progress hiện tại
-		progress.WatchDuration = req.WatchDuration
 		progress.LastPosition = req.LastPosition
 	}
 
 	// 5. Lưu progress
-	if err := ps.progressRepo.UpdateProgress(progress); err != nil {
+	if err := tx.Save(progress).Error; err != nil {
+		tx.Rollback()
 		return nil, utils.WrapError(err, "Failed to update progress", utils.ErrCodeInternal)
 	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, utils.WrapError(err, "Failed to commit transaction", utils.ErrCodeInternal)
+	}
+	ps.touchEnrollment(enrollment, time.Now())
 
 	return &dto.UpdateLessonPositionResponse{
 		LessonId:      lessonId,
@@ -268,7 +352,211 @@ func (ps *progressService) UpdateLessonPosition(userId, lessonId uint, req *dto.
 	}, nil
 }
 
+func (ps *progressService) RecordHeartbeat(userId, lessonId uint, req *dto.WatchHeartbeatRequest) (*dto.WatchHeartbeatResponse, error) {
+	// 1. Lấy thông tin lesson, lesson chưa publish coi như không tồn tại
+	lesson, err := findPublishedLesson(ps.lessonRepo, lessonId)
+	if err != nil {
+		return nil, err
+	}
+
+	if lesson.LessonType != lessonTypeVideo || lesson.VideoDuration <= 0 {
+		return nil, utils.NewError("This lesson has no video to track", utils.ErrCodeBadRequest)
+	}
+
+	// 2. Kiểm tra user đã enroll course chưa
+	enrollment, isEnrolled := ps.enrollmentRepo.CheckEnrollment(userId, lesson.CourseId)
+	if !isEnrolled {
+		return nil, utils.NewError("You are not enrolled in this course", utils.ErrCodeForbidden)
+	}
+
+	// Lesson bị khóa (drip content, prerequisite) thì chưa được tính thời gian xem
+	lock, err := resolveLessonLock(ps.lessonRepo, userId, lesson, enrollment.EnrolledAt)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to check lesson lock", utils.ErrCodeInternal)
+	}
+	if lock.IsLocked {
+		return nil, utils.NewError(lessonLockMessage(lock.Reason), utils.ErrCodeForbidden)
+	}
+
+	// 3. Lấy hoặc tạo progress record, khóa trong transaction để heartbeat gửi song song không cùng nhận trọn
+	// khoảng thời gian từ heartbeat trước và không ghi đè các đoạn đã xem của nhau
+	tx := ps.progressRepo.BeginTransaction()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	progress, err := ps.progressRepo.LockLessonProgress(tx, userId, lesson.CourseId, lessonId)
+	if err != nil {
+		tx.Rollback()
+		return nil, utils.WrapError(err, "Failed to get lesson progress", utils.ErrCodeInternal)
+	}
+	if progress == nil {
+		progress = &models.Progress{
+			UserId:   userId,
+			LessonId: lessonId,
+			CourseId: lesson.CourseId,
+		}
+	}
+
+	// 4. Giới hạn đoạn trong thời lượng video và theo thời gian thực từ heartbeat trước (chặn tua/gửi số lớn)
+	from := min(req.From, lesson.VideoDuration)
+	to := min(req.To, lesson.VideoDuration)
+	now := time.Now()
+
+	recorded := 0
+	if to > from {
+		recorded = min(to-from, allowedWatchSpan(progress.LastHeartbeatAt, now))
+		progress.WatchedIntervals = mergeWatchIntervals(progress.WatchedIntervals, models.WatchInterval{Start: from, End: from + recorded})
+	}
+
+	progress.WatchDuration = watchedSeconds(progress.WatchedIntervals)
+	progress.LastPosition = to
+	progress.LastHeartbeatAt = &now
+
+	// 5. Lưu progress
+	if err := tx.Save(progress).Error; err != nil {
+		tx.Rollback()
+		return nil, utils.WrapError(err, "Failed to update progress", utils.ErrCodeInternal)
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, utils.WrapError(err, "Failed to commit transaction", utils.ErrCodeInternal)
+	}
+
+	// 6. Thời gian học trong ngày và thời điểm truy cập enrollment
+	activity := learningActivityLog{}
+	activity.add(now, recorded, 0)
+	recordLearningActivity(ps.activityRepo, userId, activity)
+	ps.touchEnrollment(enrollment, now)
+
+	return &dto.WatchHeartbeatResponse{
+		LessonId:        lessonId,
+		LastPosition:    progress.LastPosition,
+		VideoDuration:   lesson.VideoDuration,
+		WatchedSeconds:  progress.WatchDuration,
+		WatchedPercent:  float64(progress.WatchDuration) / float64(lesson.VideoDuration) * 100,
+		RecordedSeconds: recorded,
+		RequiredPercent: watchMinPercent(),
+		CanComplete:     progress.IsCompleted || progress.WatchDuration >= requiredWatchSeconds(lesson),
+	}, nil
+}
+
+// checkCompletionRequirements - quiz/assignment/SCORM phải đạt, video phải xem đủ % thời lượng.
+// progress có thể nil (chưa học lần nào)
+func (ps *progressService) checkCompletionRequirements(userId uint, lesson *models.Lesson, progress *models.Progress) error {
+	lessonId := lesson.Id
+
+	// Lesson dạng quiz chỉ hoàn thành khi đã có attempt đạt điểm pass
+	if lesson.LessonType == lessonTypeQuiz {
+		passed, err := ps.quizRepo.HasPassedAttempt(userId, lessonId)
+		if err != nil {
+			return utils.WrapError(err, "Failed to check quiz result", utils.ErrCodeInternal)
+		}
+		if !passed {
+			return utils.NewError("You must pass the quiz to complete this lesson", utils.ErrCodeForbidden)
+		}
+	}
+
+	// Lesson dạng assignment chỉ hoàn thành khi bài nộp đã được chấm đạt
+	if lesson.LessonType == lessonTypeAssignment {
+		passed, err := ps.assignmentRepo.HasPassedSubmission(userId, lessonId)
+		if err != nil {
+			return utils.WrapError(err, "Failed to check assignment result", utils.ErrCodeInternal)
+		}
+		if !passed {
+			return utils.NewError("Your assignment must be graded as passed to complete this lesson", utils.ErrCodeForbidden)
+		}
+	}
+
+	// Lesson dạng SCORM chỉ hoàn thành khi nội dung báo lesson_status passed/completed
+	if lesson.LessonType == lessonTypeScorm {
+		completed, err := ps.scormRepo.HasCompletedAttempt(userId, lessonId)
+		if err != nil {
+			return utils.WrapError(err, "Failed to check SCORM status", utils.ErrCodeInternal)
+		}
+		if !completed {
+			return utils.NewError("You must complete the SCORM content to complete this lesson", utils.ErrCodeForbidden)
+		}
+	}
+
+	// Lesson video phải xem đủ % thời lượng (ghi nhận qua heartbeat) mới được hoàn thành, lesson đã hoàn thành thì bỏ qua
+	if required := requiredWatchSeconds(lesson); required > 0 && (progress == nil || !progress.IsCompleted) {
+		watched := 0
+		if progress != nil {
+			watched = watchedSeconds(progress.WatchedIntervals)
+		}
+		if watched < required {
+			return utils.NewError(fmt.Sprintf("You must watch at least %d%% of the video to complete this lesson", watchMinPercent()), utils.ErrCodeForbidden)
+		}
+	}
+
+	return nil
+}
+
+// getLessonGrades - quiz lấy attempt cao điểm nhất, assignment lấy bài nộp mới nhất (chưa chấm = pending)
+func (ps *progressService) getLessonGrades(userId, courseId uint) (map[uint]*dto.LessonGrade, error) {
+	grades := make(map[uint]*dto.LessonGrade)
+
+	attempts, err := ps.quizRepo.GetCourseAttempts(userId, courseId)
+	if err != nil {
+		return nil, err
+	}
+	for _, attempt := range attempts {
+		if best, exists := grades[attempt.LessonId]; exists && *best.Percentage >= attempt.ScorePercentage {
+			continue
+		}
+		score := attempt.Score
+		percentage := attempt.ScorePercentage
+		grades[attempt.LessonId] = &dto.LessonGrade{
+			Status:     "graded",
+			Score:      &score,
+			MaxScore:   attempt.MaxScore,
+			Percentage: &percentage,
+			Passed:     attempt.Passed,
+		}
+	}
+
+	// Submissions đã sắp xếp submission_number DESC nên bài đầu tiên là mới nhất
+	submissions, err := ps.assignmentRepo.GetCourseSubmissions(userId, courseId)
+	if err != nil {
+		return nil, err
+	}
+	for _, submission := range submissions {
+		if _, exists := grades[submission.LessonId]; exists {
+			continue
+		}
+		grade := &dto.LessonGrade{
+			Status:   "pending",
+			MaxScore: submission.MaxPoints,
+		}
+		if submission.Status == submissionStatusGraded && submission.FinalScore != nil {
+			grade.Status = "graded"
+			grade.Score = submission.FinalScore
+			grade.Passed = submission.Passed
+			if submission.MaxPoints > 0 {
+				percentage := *submission.FinalScore / submission.MaxPoints * 100
+				grade.Percentage = &percentage
+			}
+		}
+		grades[submission.LessonId] = grade
+	}
+
+	return grades, nil
+}
+
 // Tiếp theo hàm updateEnrollmentProgress
+// touchEnrollment cập nhật last_accessed_at (dùng cho email nhắc học), lỗi chỉ ghi log
+func (ps *progressService) touchEnrollment(enrollment *models.Enrollment, now time.Time) {
+	if enrollment.LastAccessedAt != nil && now.Sub(*enrollment.LastAccessedAt) < enrollmentTouchPeriod {
+		return
+	}
+
+	if err := ps.enrollmentRepo.UpdateEnrollmentProgress(enrollment.Id, map[string]interface{}{"last_accessed_at": now}); err != nil {
+		log.Printf("Failed to update enrollment %d last access: %v", enrollment.Id, err)
+	}
+}
+
 func (ps *progressService) updateEnrollmentProgress(userId, courseId uint) error {
 	// Đếm số lessons đã hoàn thành
 	completedCount, err := ps.progressRepo.CountCompletedLessons(userId, courseId)
@@ -303,11 +591,25 @@ func (ps *progressService) updateEnrollmentProgress(userId, courseId uint) error
 	}
 
 	// Nếu hoàn thành 100%, cập nhật status
-	if progressPercentage >= 100 {
+	completed := progressPercentage >= 100
+	if completed {
 		updates["status"] = "completed"
-		now := time.Now()
-		updates["completed_at"] = now
+		// Giữ ngày hoàn thành đầu tiên (in trên chứng chỉ) khi học viên học lại lesson
+		if enrollment.CompletedAt == nil {
+			updates["completed_at"] = time.Now()
+		}
+	}
+
+	if err := ps.enrollmentRepo.UpdateEnrollmentProgress(enrollment.Id, updates); err != nil {
+		return err
+	}
+
+	// Cấp chứng chỉ khi hoàn thành khóa học, lỗi được cấp bù khi học viên xem danh sách chứng chỉ
+	if completed {
+		if _, err := issueCertificate(ps.certificateRepo, enrollment.Id); err != nil {
+			log.Printf("Failed to issue certificate for enrollment %d: %v", enrollment.Id, err)
+		}
 	}
 
-	return ps.enrollmentRepo.UpdateEnrollmentProgress(enrollment.Id, updates)
+	return nil
 }
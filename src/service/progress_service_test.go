@@ -0,0 +1,86 @@
+package service
+
+import (
+	"lms/src/dto"
+	"lms/src/models"
+	"lms/src/repository"
+	"lms/src/utils"
+	"testing"
+	"time"
+)
+
+// fakeLessonRepo trả lesson và progress của lesson trong bộ nhớ
+type fakeLessonRepo struct {
+	repository.LessonRepository
+	lessons   []models.Lesson
+	completed map[uint]bool // lessonId -> đã hoàn thành
+}
+
+func (r *fakeLessonRepo) FindLessonByIds(lessonIds []uint) ([]models.Lesson, error) {
+	var found []models.Lesson
+	for _, l := range r.lessons {
+		for _, id := range lessonIds {
+			if l.Id == id {
+				found = append(found, l)
+			}
+		}
+	}
+	return found, nil
+}
+
+func (r *fakeLessonRepo) GetPreviousLesson(courseId uint, currentOrder int) (*models.Lesson, error) {
+	var previous *models.Lesson
+	for i, l := range r.lessons {
+		if l.CourseId == courseId && l.IsPublished && l.LessonOrder < currentOrder && (previous == nil || l.LessonOrder > previous.LessonOrder) {
+			previous = &r.lessons[i]
+		}
+	}
+	return previous, nil
+}
+
+func (r *fakeLessonRepo) GetLessonProgressDetail(userId, lessonId uint) (*models.Progress, error) {
+	return &models.Progress{UserId: userId, LessonId: lessonId, IsCompleted: r.completed[lessonId]}, nil
+}
+
+// Heartbeat của lesson không được truy cập phải bị từ chối trước khi mở transaction:
+// progressRepo nil nên nếu service đi tới bước ghi progress thì test panic
+func TestRecordHeartbeatRequiresLessonAccess(t *testing.T) {
+	video := func(id uint, order int) models.Lesson {
+		return models.Lesson{Id: id, CourseId: 1, LessonOrder: order, LessonType: lessonTypeVideo, VideoDuration: 600, IsPublished: true}
+	}
+
+	first := video(1, 1)
+	unpublished := video(2, 2)
+	unpublished.IsPublished = false
+	dripped := video(3, 3)
+	dripped.UnlockAfterDays = 7
+	gated := video(4, 4)
+	gated.RequirePreviousCompletion = true
+	otherCourse := video(5, 1)
+	otherCourse.CourseId = 2
+
+	lessonRepo := &fakeLessonRepo{lessons: []models.Lesson{first, unpublished, dripped, gated, otherCourse}}
+	enrollmentRepo := newFakeEnrollmentRepo(models.Enrollment{Id: 1, UserId: 9, CourseId: 1, Status: "active", EnrolledAt: time.Now()})
+	ps := NewProgressService(nil, enrollmentRepo, nil, lessonRepo, nil, nil, nil, nil, nil)
+
+	tests := []struct {
+		name     string
+		lessonId uint
+		wantCode utils.ErrorCode
+	}{
+		{"missing lesson", 99, utils.ErrCodeNotFound},
+		{"unpublished lesson", unpublished.Id, utils.ErrCodeNotFound},
+		{"not enrolled", otherCourse.Id, utils.ErrCodeForbidden},
+		{"drip locked", dripped.Id, utils.ErrCodeForbidden},
+		{"previous lesson incomplete", gated.Id, utils.ErrCodeForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ps.RecordHeartbeat(9, tt.lessonId, &dto.WatchHeartbeatRequest{From: 0, To: 10})
+			if got := errorCode(err); got != tt.wantCode {
+				t.Errorf("error code = %v, want %v (err: %v)", got, tt.wantCode, err)
+			}
+		})
+	}
+}
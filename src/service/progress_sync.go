@@ -0,0 +1,414 @@
+package service
+
+import (
+	"lms/src/dto"
+	"lms/src/models"
+	"lms/src/utils"
+	"log"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	syncStatusApplied   = "applied"
+	syncStatusDuplicate = "duplicate"
+	syncStatusRejected  = "rejected"
+
+	syncEventPosition = "position"
+	syncEventComplete = "complete"
+
+	syncClockSkew = 5 * time.Minute // Đồng hồ thiết bị được phép nhanh hơn server
+)
+
+// progressSyncMaxAge - sự kiện offline cũ hơn ngưỡng này bị từ chối
+func progressSyncMaxAge() time.Duration {
+	days := max(utils.GetEnvInt("PROGRESS_SYNC_MAX_AGE_DAYS", 30), 1)
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// progressSyncMaxWatchSeconds - tổng số giây xem offline tối đa được ghi nhận trong một lần sync (mọi lesson)
+func progressSyncMaxWatchSeconds() int {
+	return max(utils.GetEnvInt("PROGRESS_SYNC_MAX_WATCH_SECONDS", 4*3600), 0)
+}
+
+// syncWatchBudget - số giây xem offline tối đa của một lesson, tính theo thời gian thực server đã thấy
+// từ heartbeat trước (hoặc từ lúc enroll nếu chưa có heartbeat). Thời điểm trên thiết bị không làm tăng được giới hạn này
+func syncWatchBudget(lastHeartbeatAt *time.Time, enrolledAt, now time.Time) int {
+	since := enrolledAt
+	if lastHeartbeatAt != nil {
+		since = *lastHeartbeatAt
+	}
+
+	elapsed := now.Sub(since).Seconds()
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	return int(elapsed*watchMaxPlaybackRate()) + watchHeartbeatTolerance
+}
+
+// syncLessonState là progress của một lesson trong lúc áp dụng batch, chỉ ghi DB khi commit
+type syncLessonState struct {
+	lesson      models.Lesson
+	enrollment  *models.Enrollment
+	progress    *models.Progress
+	lastEventAt *time.Time // Thời điểm sự kiện trước đó, dùng cho giới hạn chống tua như heartbeat
+	watchBudget int        // Số giây xem offline còn được ghi nhận cho lesson trong lần sync này
+	dirty       bool
+}
+
+// SyncProgress áp dụng batch sự kiện offline theo thứ tự thời gian trên thiết bị.
+// Xung đột được giải quyết theo vị trí lớn nhất và thời điểm hoàn thành sớm nhất; các thay đổi lưu trong một transaction
+func (ps *progressService) SyncProgress(userId uint, req *dto.SyncProgressRequest) (*dto.SyncProgressResponse, error) {
+	// 1. Idempotency key đã áp dụng ở lần sync trước
+	keys := make([]string, 0, len(req.Items))
+	for _, item := range req.Items {
+		keys = append(keys, item.IdempotencyKey)
+	}
+	appliedKeys, err := ps.progressRepo.FindSyncEventKeys(userId, keys)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to check synced events", utils.ErrCodeInternal)
+	}
+	seen := make(map[string]bool, len(req.Items))
+	for _, key := range appliedKeys {
+		seen[key] = true
+	}
+
+	// 2. Lấy lessons, enrollment và progress hiện tại. Progress được khóa trong transaction đến khi lưu xong
+	// để heartbeat và các lần sync khác của học viên không ghi đè lẫn nhau
+	now := time.Now()
+	tx := ps.progressRepo.BeginTransaction()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	states, rejectReasons, err := ps.loadSyncStates(tx, userId, req.Items, now)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// 3. Áp dụng theo thứ tự thời gian trên thiết bị (giữ thứ tự gửi lên nếu trùng thời điểm)
+	order := make([]int, len(req.Items))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return req.Items[order[a]].OccurredAt.Before(req.Items[order[b]].OccurredAt)
+	})
+
+	results := make([]dto.SyncProgressItemResult, len(req.Items))
+	var events []models.ProgressSyncEvent
+	activity := learningActivityLog{}
+	watchBudget := progressSyncMaxWatchSeconds()
+
+	for _, i := range order {
+		item := req.Items[i]
+		result := &results[i]
+		result.IdempotencyKey = item.IdempotencyKey
+		result.LessonId = item.LessonId
+
+		if seen[item.IdempotencyKey] {
+			result.Status = syncStatusDuplicate
+			continue
+		}
+		seen[item.IdempotencyKey] = true
+
+		state, ok := states[item.LessonId]
+		if !ok {
+			result.Status = syncStatusRejected
+			result.Error = rejectReasons[item.LessonId]
+			continue
+		}
+
+		occurredAt, err := ps.applySyncItem(userId, state, states, &item, now, &watchBudget, activity)
+		if err != nil {
+			appErr, ok := err.(*utils.AppError)
+			if !ok || appErr.Code == utils.ErrCodeInternal {
+				tx.Rollback()
+				return nil, err
+			}
+			result.Status = syncStatusRejected
+			result.Error = appErr.Message
+			continue
+		}
+
+		result.Status = syncStatusApplied
+		events = append(events, models.ProgressSyncEvent{
+			UserId:         userId,
+			IdempotencyKey: item.IdempotencyKey,
+			LessonId:       item.LessonId,
+			EventType:      item.Type,
+			OccurredAt:     occurredAt,
+		})
+	}
+
+	// 4. Lưu progress và idempotency key trong cùng transaction (theo thứ tự lesson id để tránh deadlock)
+	lessonIds := make([]uint, 0, len(states))
+	for lessonId := range states {
+		lessonIds = append(lessonIds, lessonId)
+	}
+	sort.Slice(lessonIds, func(a, b int) bool { return lessonIds[a] < lessonIds[b] })
+
+	if len(events) == 0 {
+		tx.Rollback()
+	} else {
+		for _, lessonId := range lessonIds {
+			if state := states[lessonId]; state.dirty {
+				if err := tx.Save(state.progress).Error; err != nil {
+					tx.Rollback()
+					return nil, utils.WrapError(err, "Failed to save progress", utils.ErrCodeInternal)
+				}
+			}
+		}
+
+		// Unique (user_id, idempotency_key): hai request sync cùng batch chạy song song thì request sau bị rollback toàn bộ
+		if err := tx.Create(&events).Error; err != nil {
+			tx.Rollback()
+			return nil, utils.WrapError(err, "Failed to save synced events, please retry", utils.ErrCodeConflict)
+		}
+
+		if err := tx.Commit().Error; err != nil {
+			return nil, utils.WrapError(err, "Failed to commit transaction", utils.ErrCodeInternal)
+		}
+
+		// Hoạt động học offline tính vào ngày xảy ra trên thiết bị
+		recordLearningActivity(ps.activityRepo, userId, activity)
+	}
+
+	// 5. Tính lại progress của các khóa học có thay đổi
+	response := &dto.SyncProgressResponse{
+		Results:     results,
+		Lessons:     make([]dto.SyncedLessonProgress, 0, len(lessonIds)),
+		Enrollments: []dto.SyncedEnrollmentProgress{},
+	}
+
+	changedCourses := make(map[uint]bool)
+	var courseIds []uint
+	for _, lessonId := range lessonIds {
+		state := states[lessonId]
+		courseId := state.lesson.CourseId
+		if _, exists := changedCourses[courseId]; !exists {
+			changedCourses[courseId] = false
+			courseIds = append(courseIds, courseId)
+		}
+		if state.dirty {
+			changedCourses[courseId] = true
+		}
+
+		response.Lessons = append(response.Lessons, dto.SyncedLessonProgress{
+			LessonId:      lessonId,
+			CourseId:      courseId,
+			IsCompleted:   state.progress.IsCompleted,
+			CompletedAt:   state.progress.CompletedAt,
+			LastPosition:  state.progress.LastPosition,
+			WatchDuration: state.progress.WatchDuration,
+		})
+	}
+
+	// 6. Trạng thái enrollment sau khi tính lại
+	for _, courseId := range courseIds {
+		if changedCourses[courseId] {
+			if err := ps.updateEnrollmentProgress(userId, courseId); err != nil {
+				log.Printf("Failed to update enrollment progress for course %d: %v", courseId, err)
+			}
+		}
+
+		enrollment, exists := ps.enrollmentRepo.CheckEnrollment(userId, courseId)
+		if !exists {
+			continue
+		}
+		response.Enrollments = append(response.Enrollments, dto.SyncedEnrollmentProgress{
+			CourseId:           courseId,
+			ProgressPercentage: enrollment.ProgressPercentage,
+			Status:             enrollment.Status,
+			CompletedAt:        enrollment.CompletedAt,
+		})
+	}
+
+	return response, nil
+}
+
+// loadSyncStates lấy lesson, enrollment và progress (khóa trong tx) cho các lesson trong batch.
+// Lesson không tồn tại hoặc chưa enroll không có state, lý do nằm trong map thứ hai
+func (ps *progressService) loadSyncStates(tx *gorm.DB, userId uint, items []dto.SyncProgressItem, now time.Time) (map[uint]*syncLessonState, map[uint]string, error) {
+	lessonIds := make([]uint, 0, len(items))
+	seen := make(map[uint]bool)
+	for _, item := range items {
+		if !seen[item.LessonId] {
+			seen[item.LessonId] = true
+			lessonIds = append(lessonIds, item.LessonId)
+		}
+	}
+
+	lessons, err := ps.lessonRepo.FindLessonByIds(lessonIds)
+	if err != nil {
+		return nil, nil, utils.WrapError(err, "Failed to get lessons", utils.ErrCodeInternal)
+	}
+
+	states := make(map[uint]*syncLessonState, len(lessons))
+	rejectReasons := make(map[uint]string)
+	for _, lessonId := range lessonIds {
+		rejectReasons[lessonId] = "Lesson not found"
+	}
+
+	// Khóa theo thứ tự course id để hai request song song không deadlock
+	sort.Slice(lessons, func(a, b int) bool {
+		if lessons[a].CourseId != lessons[b].CourseId {
+			return lessons[a].CourseId < lessons[b].CourseId
+		}
+		return lessons[a].Id < lessons[b].Id
+	})
+
+	enrollments := make(map[uint]*models.Enrollment)
+	for _, lesson := range lessons {
+		enrollment, checked := enrollments[lesson.CourseId]
+		if !checked {
+			enrollment, _ = ps.enrollmentRepo.CheckEnrollment(userId, lesson.CourseId)
+			enrollments[lesson.CourseId] = enrollment
+		}
+		if enrollment == nil {
+			rejectReasons[lesson.Id] = "You are not enrolled in this course"
+			continue
+		}
+
+		progress, err := ps.progressRepo.LockLessonProgress(tx, userId, lesson.CourseId, lesson.Id)
+		if err != nil {
+			return nil, nil, utils.WrapError(err, "Failed to get lesson progress", utils.ErrCodeInternal)
+		}
+		if progress == nil {
+			progress = &models.Progress{
+				UserId:   userId,
+				LessonId: lesson.Id,
+				CourseId: lesson.CourseId,
+			}
+		}
+
+		states[lesson.Id] = &syncLessonState{
+			lesson:      lesson,
+			enrollment:  enrollment,
+			progress:    progress,
+			lastEventAt: progress.LastHeartbeatAt,
+			watchBudget: syncWatchBudget(progress.LastHeartbeatAt, enrollment.EnrolledAt, now),
+		}
+		delete(rejectReasons, lesson.Id)
+	}
+
+	return states, rejectReasons, nil
+}
+
+// applySyncItem áp dụng một sự kiện vào state trong bộ nhớ, trả về thời điểm sự kiện đã chuẩn hóa.
+// watchBudget là số giây xem còn được ghi nhận trong lần sync này (chung cho mọi lesson)
+func (ps *progressService) applySyncItem(userId uint, state *syncLessonState, states map[uint]*syncLessonState, item *dto.SyncProgressItem, now time.Time, watchBudget *int, activity learningActivityLog) (time.Time, error) {
+	// 1. Thời điểm trên thiết bị phải hợp lý
+	if item.OccurredAt.After(now.Add(syncClockSkew)) {
+		return time.Time{}, utils.NewError("Event time is in the future", utils.ErrCodeBadRequest)
+	}
+	if item.OccurredAt.Before(now.Add(-progressSyncMaxAge())) {
+		return time.Time{}, utils.NewError("Event is too old to sync", utils.ErrCodeBadRequest)
+	}
+	if item.OccurredAt.Before(state.enrollment.EnrolledAt) {
+		return time.Time{}, utils.NewError("Event occurred before enrollment", utils.ErrCodeBadRequest)
+	}
+	occurredAt := item.OccurredAt
+	if occurredAt.After(now) {
+		occurredAt = now
+	}
+
+	lesson := &state.lesson
+	progress := state.progress
+
+	switch item.Type {
+	case syncEventPosition:
+		// Đoạn đã xem offline được giới hạn như heartbeat theo thời gian giữa các sự kiện trên thiết bị,
+		// đồng thời không vượt thời gian thực server đã thấy từ heartbeat trước và tổng giới hạn của lần sync
+		if item.WatchedFrom != nil && item.WatchedTo != nil && lesson.LessonType == lessonTypeVideo && lesson.VideoDuration > 0 {
+			from := min(*item.WatchedFrom, lesson.VideoDuration)
+			to := min(*item.WatchedTo, lesson.VideoDuration)
+			if to > from {
+				if state.lastEventAt != nil && state.lastEventAt.After(occurredAt) {
+					return time.Time{}, utils.NewError("Event is older than the last recorded watch activity", utils.ErrCodeBadRequest)
+				}
+
+				recorded := min(to-from, allowedWatchSpan(state.lastEventAt, occurredAt), state.watchBudget, *watchBudget)
+				if recorded > 0 {
+					progress.WatchedIntervals = mergeWatchIntervals(progress.WatchedIntervals, models.WatchInterval{Start: from, End: from + recorded})
+					progress.WatchDuration = watchedSeconds(progress.WatchedIntervals)
+					progress.LastHeartbeatAt = &now
+					state.watchBudget -= recorded
+					*watchBudget -= recorded
+					activity.add(occurredAt, recorded, 0)
+				}
+			}
+		}
+
+		// Giữ vị trí lớn nhất giữa server và các thiết bị
+		position := item.Position
+		if lesson.VideoDuration > 0 {
+			position = min(position, lesson.VideoDuration)
+		}
+		progress.LastPosition = max(progress.LastPosition, position)
+		state.lastEventAt = &occurredAt
+
+	case syncEventComplete:
+		// Đã hoàn thành: giữ thời điểm hoàn thành sớm nhất
+		if progress.IsCompleted {
+			if progress.CompletedAt == nil || occurredAt.Before(*progress.CompletedAt) {
+				progress.CompletedAt = &occurredAt
+			}
+			break
+		}
+
+		if err := ps.checkSyncLessonLock(userId, state, states, occurredAt); err != nil {
+			return time.Time{}, err
+		}
+		if err := ps.checkCompletionRequirements(userId, lesson, progress); err != nil {
+			return time.Time{}, err
+		}
+
+		progress.IsCompleted = true
+		progress.CompletedAt = &occurredAt
+		progress.LastPosition = max(progress.LastPosition, lesson.VideoDuration)
+		activity.add(occurredAt, 0, 1)
+		if lesson.LessonType == lessonTypeVideo {
+			progress.WatchDuration = watchedSeconds(progress.WatchedIntervals)
+		}
+	}
+
+	state.dirty = true
+	return occurredAt, nil
+}
+
+// checkSyncLessonLock giống resolveLessonLock nhưng lesson trước hoàn thành trong cùng batch cũng được tính
+func (ps *progressService) checkSyncLessonLock(userId uint, state *syncLessonState, states map[uint]*syncLessonState, occurredAt time.Time) error {
+	lesson := &state.lesson
+	previousCompleted := true
+
+	if lesson.RequirePreviousCompletion && !lesson.IsPreview {
+		previousLesson, err := ps.lessonRepo.GetPreviousLesson(lesson.CourseId, lesson.LessonOrder)
+		if err != nil {
+			return utils.WrapError(err, "Failed to check lesson lock", utils.ErrCodeInternal)
+		}
+
+		if previousLesson != nil {
+			if previousState, ok := states[previousLesson.Id]; ok {
+				previousCompleted = previousState.progress.IsCompleted
+			} else {
+				progress, err := ps.lessonRepo.GetLessonProgressDetail(userId, previousLesson.Id)
+				if err != nil {
+					return utils.WrapError(err, "Failed to check lesson lock", utils.ErrCodeInternal)
+				}
+				previousCompleted = progress.IsCompleted
+			}
+		}
+	}
+
+	lock := evaluateLessonLock(lesson, state.enrollment.EnrolledAt, previousCompleted, occurredAt)
+	if lock.IsLocked {
+		return utils.NewError(lessonLockMessage(lock.Reason), utils.ErrCodeForbidden)
+	}
+	return nil
+}
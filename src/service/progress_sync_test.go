@@ -0,0 +1,112 @@
+package service
+
+import (
+	"lms/src/dto"
+	"lms/src/models"
+	"lms/src/utils"
+	"testing"
+	"time"
+)
+
+func TestSyncWatchBudget(t *testing.T) {
+	t.Setenv("WATCH_MAX_PLAYBACK_RATE", "2")
+
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	lastHeartbeat := now.Add(-10 * time.Minute)
+	enrolledAt := now.Add(-48 * time.Hour)
+
+	tests := []struct {
+		name string
+		last *time.Time
+		want int
+	}{
+		{"since last heartbeat", &lastHeartbeat, 1200 + watchHeartbeatTolerance},
+		{"since enrollment", nil, 48*3600*2 + watchHeartbeatTolerance},
+		{"heartbeat in the future", &now, watchHeartbeatTolerance},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := syncWatchBudget(tt.last, enrolledAt, now); got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplySyncItemWatchCredit(t *testing.T) {
+	t.Setenv("WATCH_HEARTBEAT_MAX_SECONDS", "60")
+	t.Setenv("WATCH_MAX_PLAYBACK_RATE", "2")
+	t.Setenv("PROGRESS_SYNC_MAX_AGE_DAYS", "30")
+
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	ptr := func(v int) *int { return &v }
+	position := func(from, to int, at time.Time) dto.SyncProgressItem {
+		return dto.SyncProgressItem{Type: syncEventPosition, Position: to, WatchedFrom: ptr(from), WatchedTo: ptr(to), OccurredAt: at}
+	}
+
+	newState := func(lastHeartbeatAt *time.Time) *syncLessonState {
+		enrollment := &models.Enrollment{EnrolledAt: now.Add(-24 * time.Hour)}
+		progress := &models.Progress{LastHeartbeatAt: lastHeartbeatAt}
+		return &syncLessonState{
+			lesson:      models.Lesson{Id: 1, LessonType: lessonTypeVideo, VideoDuration: 3600},
+			enrollment:  enrollment,
+			progress:    progress,
+			lastEventAt: lastHeartbeatAt,
+			watchBudget: syncWatchBudget(lastHeartbeatAt, enrollment.EnrolledAt, now),
+		}
+	}
+
+	t.Run("event older than last heartbeat is rejected", func(t *testing.T) {
+		lastHeartbeat := now.Add(-time.Minute)
+		state := newState(&lastHeartbeat)
+		budget := 10000
+		item := position(0, 30, now.Add(-time.Hour))
+
+		_, err := (&progressService{}).applySyncItem(1, state, nil, &item, now, &budget, learningActivityLog{})
+		if appErr, ok := err.(*utils.AppError); !ok || appErr.Code != utils.ErrCodeBadRequest {
+			t.Fatalf("expected bad request, got %v", err)
+		}
+		if state.progress.WatchDuration != 0 || state.dirty {
+			t.Error("rejected event must not change progress")
+		}
+	})
+
+	t.Run("credit limited by server time since last heartbeat", func(t *testing.T) {
+		// Server thấy heartbeat 20 giây trước: thiết bị khai báo các sự kiện cách nhau 1 phút vẫn chỉ được 20 giây x2 + dung sai
+		lastHeartbeat := now.Add(-20 * time.Second)
+		state := newState(&lastHeartbeat)
+		budget := 10000
+		for i := 0; i < 5; i++ {
+			item := position(i*60, i*60+60, lastHeartbeat.Add(time.Duration(i+1)*time.Minute))
+			if _, err := (&progressService{}).applySyncItem(1, state, nil, &item, now, &budget, learningActivityLog{}); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if want := 40 + watchHeartbeatTolerance; state.progress.WatchDuration != want {
+			t.Errorf("watched %d seconds, want %d", state.progress.WatchDuration, want)
+		}
+		if state.progress.LastHeartbeatAt == nil || !state.progress.LastHeartbeatAt.Equal(now) {
+			t.Error("last heartbeat should move to server time after credit is recorded")
+		}
+	})
+
+	t.Run("credit limited by total budget of the sync call", func(t *testing.T) {
+		state := newState(nil)
+		budget := 45
+		at := now.Add(-10 * time.Minute)
+		for i := 0; i < 3; i++ {
+			item := position(i*60, i*60+60, at.Add(time.Duration(i)*time.Minute))
+			if _, err := (&progressService{}).applySyncItem(1, state, nil, &item, now, &budget, learningActivityLog{}); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if state.progress.WatchDuration != 45 || budget != 0 {
+			t.Errorf("watched %d seconds with %d budget left, want 45 and 0", state.progress.WatchDuration, budget)
+		}
+		if state.progress.LastPosition != 180 {
+			t.Errorf("position %d, want 180", state.progress.LastPosition)
+		}
+	})
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"lms/src/cache"
+	"lms/src/repository"
+	"log"
+	"time"
+)
+
+type publishScheduleService struct {
+	scheduleRepo repository.PublishScheduleRepository
+	recalcRepo   repository.ProgressRecalculationRepository
+}
+
+func NewPublishScheduleService(scheduleRepo repository.PublishScheduleRepository, recalcRepo repository.ProgressRecalculationRepository) PublishScheduleService {
+	return &publishScheduleService{
+		scheduleRepo: scheduleRepo,
+		recalcRepo:   recalcRepo,
+	}
+}
+
+// RunDueSchedules publish/unpublish các course và lesson đã tới giờ.
+// Publish chạy trước unpublish để item có cả hai mốc đã qua (vd: server tắt lâu) kết thúc ở trạng thái ẩn.
+func (ss *publishScheduleService) RunDueSchedules(ctx context.Context) error {
+	now := time.Now()
+
+	// Lesson đổi trạng thái làm thay đổi tổng số lessons của course -> cần tính lại progress
+	lessonCourseIds, err := ss.scheduleRepo.DueLessonCourseIds(now)
+	if err != nil {
+		return err
+	}
+
+	steps := []struct {
+		name string
+		run  func(now time.Time) (int64, error)
+	}{
+		{"publish courses", ss.scheduleRepo.PublishDueCourses},
+		{"unpublish courses", ss.scheduleRepo.UnpublishDueCourses},
+		{"publish lessons", ss.scheduleRepo.PublishDueLessons},
+		{"unpublish lessons", ss.scheduleRepo.UnpublishDueLessons},
+	}
+
+	var changed int64
+	for _, step := range steps {
+		affected, err := step.run(now)
+		if err != nil {
+			return err
+		}
+		if affected > 0 {
+			log.Printf("⏰ Scheduled %s: %d row(s)", step.name, affected)
+		}
+		changed += affected
+	}
+
+	for _, courseId := range lessonCourseIds {
+		enqueueProgressRecalculation(ss.recalcRepo, courseId, recalcReasonLessonSchedule)
+	}
+
+	// Xóa cache danh sách/chi tiết course để học viên thấy thay đổi ngay
+	if changed > 0 {
+		invalidateCourseCache(ctx)
+	}
+
+	return nil
+}
+
+// invalidateCourseCache xóa các response course đã cache bởi CacheMiddleware
+func invalidateCourseCache(ctx context.Context) {
+	if cache.RedisClient == nil {
+		return
+	}
+
+	if err := cache.DeletePattern(ctx, "cache:/api/v1/courses*"); err != nil {
+		log.Printf("⚠️ Không thể xóa cache pattern cache:/api/v1/courses*: %v", err)
+	}
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"lms/src/repository"
+	"lms/src/utils"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// fakeScheduleRepo ghi lại thứ tự các bước đã chạy, failAt làm bước đó trả lỗi
+type fakeScheduleRepo struct {
+	affected        map[string]int64
+	lessonCourseIds []uint
+	failAt          string
+	calls           []string
+	times           []time.Time
+}
+
+func (r *fakeScheduleRepo) run(step string, now time.Time) (int64, error) {
+	r.calls = append(r.calls, step)
+	r.times = append(r.times, now)
+	if step == r.failAt {
+		return 0, errors.New("db down")
+	}
+	return r.affected[step], nil
+}
+
+func (r *fakeScheduleRepo) PublishDueCourses(now time.Time) (int64, error) {
+	return r.run("publish courses", now)
+}
+
+func (r *fakeScheduleRepo) UnpublishDueCourses(now time.Time) (int64, error) {
+	return r.run("unpublish courses", now)
+}
+
+func (r *fakeScheduleRepo) PublishDueLessons(now time.Time) (int64, error) {
+	return r.run("publish lessons", now)
+}
+
+func (r *fakeScheduleRepo) UnpublishDueLessons(now time.Time) (int64, error) {
+	return r.run("unpublish lessons", now)
+}
+
+func (r *fakeScheduleRepo) DueLessonCourseIds(now time.Time) ([]uint, error) {
+	if _, err := r.run("lesson course ids", now); err != nil {
+		return nil, err
+	}
+	return r.lessonCourseIds, nil
+}
+
+// fakeRecalcQueue ghi lại các course được enqueue tính lại progress
+type fakeRecalcQueue struct {
+	repository.ProgressRecalculationRepository
+	enqueued []uint
+	reasons  []string
+}
+
+func (r *fakeRecalcQueue) Enqueue(courseId uint, reason string) error {
+	r.enqueued = append(r.enqueued, courseId)
+	r.reasons = append(r.reasons, reason)
+	return nil
+}
+
+func TestRunDueSchedules(t *testing.T) {
+	allSteps := []string{"lesson course ids", "publish courses", "unpublish courses", "publish lessons", "unpublish lessons"}
+
+	tests := []struct {
+		name            string
+		repo            *fakeScheduleRepo
+		wantErr         bool
+		wantCalls       []string
+		wantRecalculate []uint
+	}{
+		{"nothing due", &fakeScheduleRepo{}, false, allSteps, nil},
+		{
+			"due lessons recalculate their courses",
+			&fakeScheduleRepo{affected: map[string]int64{"publish lessons": 2, "unpublish lessons": 1}, lessonCourseIds: []uint{4, 9}},
+			false, allSteps, []uint{4, 9},
+		},
+		{"course only changes skip recalculation", &fakeScheduleRepo{affected: map[string]int64{"publish courses": 3}}, false, allSteps, nil},
+		{"lookup failure stops the run", &fakeScheduleRepo{failAt: "lesson course ids", lessonCourseIds: []uint{4}}, true, allSteps[:1], nil},
+		{"step failure stops later steps", &fakeScheduleRepo{failAt: "unpublish courses", lessonCourseIds: []uint{4}}, true, allSteps[:3], nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queue := &fakeRecalcQueue{}
+			ss := &publishScheduleService{scheduleRepo: tt.repo, recalcRepo: queue}
+
+			err := ss.RunDueSchedules(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RunDueSchedules error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(tt.repo.calls, tt.wantCalls) {
+				t.Errorf("steps = %v, want %v", tt.repo.calls, tt.wantCalls)
+			}
+			if !reflect.DeepEqual(queue.enqueued, tt.wantRecalculate) {
+				t.Errorf("recalculated courses = %v, want %v", queue.enqueued, tt.wantRecalculate)
+			}
+			for _, reason := range queue.reasons {
+				if reason != recalcReasonLessonSchedule {
+					t.Errorf("recalculation reason = %q, want %q", reason, recalcReasonLessonSchedule)
+				}
+			}
+
+			// Mọi bước dùng cùng một mốc thời gian để publish và unpublish nhất quán
+			for _, at := range tt.repo.times {
+				if !at.Equal(tt.repo.times[0]) {
+					t.Errorf("steps ran with different times: %v", tt.repo.times)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestValidateSchedule(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	soon := now.Add(time.Hour)
+	later := now.Add(2 * time.Hour)
+
+	tests := []struct {
+		name        string
+		publishAt   *time.Time
+		unpublishAt *time.Time
+		wantCode    utils.ErrorCode
+	}{
+		{"no schedule", nil, nil, ""},
+		{"publish in future", &soon, nil, ""},
+		{"unpublish in future", nil, &soon, ""},
+		{"publish before unpublish", &soon, &later, ""},
+		{"publish in past", &past, nil, utils.ErrCodeBadRequest},
+		{"unpublish in past", nil, &past, utils.ErrCodeBadRequest},
+		{"unpublish before publish", &later, &soon, utils.ErrCodeBadRequest},
+		{"unpublish equal to publish", &soon, &soon, utils.ErrCodeBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errorCode(validateSchedule(tt.publishAt, tt.unpublishAt)); got != tt.wantCode {
+				t.Errorf("error code = %q, want %q", got, tt.wantCode)
+			}
+		})
+	}
+}
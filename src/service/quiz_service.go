@@ -0,0 +1,963 @@
+package service
+
+import (
+	"lms/src/dto"
+	"lms/src/models"
+	"lms/src/repository"
+	"lms/src/utils"
+	"log"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	lessonTypeVideo = "video"
+	lessonTypeQuiz  = "quiz"
+
+	questionTypeSingleChoice   = "single_choice"
+	questionTypeMultipleChoice = "multiple_choice"
+	questionTypeTrueFalse      = "true_false"
+	questionTypeShortAnswer    = "short_answer"
+
+	quizAttemptInProgress = "in_progress"
+	quizAttemptSubmitted  = "submitted"
+	quizAttemptExpired    = "expired"
+
+	// Thời gian cho phép nộp trễ do độ trễ mạng
+	quizSubmitGracePeriod = 30 * time.Second
+)
+
+type quizService struct {
+	quizRepo        repository.QuizRepository
+	instructorRepo  repository.InstructorRepository
+	lessonRepo      repository.LessonRepository
+	enrollmentRepo  repository.EnrollmentRepository
+	progressService ProgressService
+}
+
+func NewQuizService(
+	quizRepo repository.QuizRepository,
+	instructorRepo repository.InstructorRepository,
+	lessonRepo repository.LessonRepository,
+	enrollmentRepo repository.EnrollmentRepository,
+	progressService ProgressService,
+) QuizService {
+	return &quizService{
+		quizRepo:        quizRepo,
+		instructorRepo:  instructorRepo,
+		lessonRepo:      lessonRepo,
+		enrollmentRepo:  enrollmentRepo,
+		progressService: progressService,
+	}
+}
+
+func (qs *quizService) GetLessonQuiz(instructorId, courseId, lessonId uint) (*dto.InstructorQuizResponse, error) {
+	// 1. Kiểm tra quyền trên course và lesson
+	if _, err := qs.findInstructorLesson(instructorId, courseId, lessonId); err != nil {
+		return nil, err
+	}
+
+	// 2. Lấy quiz của lesson
+	quiz, err := qs.quizRepo.FindByLessonId(lessonId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get quiz", utils.ErrCodeInternal)
+	}
+	if quiz == nil {
+		return nil, utils.NewError("This lesson has no quiz yet", utils.ErrCodeNotFound)
+	}
+
+	return toInstructorQuizResponse(quiz), nil
+}
+
+func (qs *quizService) UpsertLessonQuiz(instructorId, courseId, lessonId uint, req *dto.UpsertQuizRequest) (*dto.InstructorQuizResponse, error) {
+	// 1. Kiểm tra quyền trên course và lesson
+	lesson, err := qs.findInstructorLesson(instructorId, courseId, lessonId)
+	if err != nil {
+		return nil, err
+	}
+
+	quiz, err := qs.quizRepo.FindByLessonId(lessonId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get quiz", utils.ErrCodeInternal)
+	}
+
+	// 2. Chưa có quiz thì tạo mới với giá trị mặc định
+	if quiz == nil {
+		quiz = &models.Quiz{
+			LessonId:       lessonId,
+			CourseId:       courseId,
+			PassPercentage: 70,
+		}
+		applyQuizSettings(quiz, req)
+
+		if err := qs.quizRepo.CreateQuiz(quiz); err != nil {
+			return nil, utils.WrapError(err, "Failed to create quiz", utils.ErrCodeInternal)
+		}
+	} else {
+		// 3. Đã có quiz thì cập nhật các field được gửi lên
+		updates := make(map[string]interface{})
+		if req.TimeLimitSeconds != nil {
+			updates["time_limit_seconds"] = *req.TimeLimitSeconds
+		}
+		if req.MaxAttempts != nil {
+			updates["max_attempts"] = *req.MaxAttempts
+		}
+		if req.PassPercentage != nil {
+			updates["pass_percentage"] = *req.PassPercentage
+		}
+		if req.QuestionsPerAttempt != nil {
+			updates["questions_per_attempt"] = *req.QuestionsPerAttempt
+		}
+		if req.ShuffleQuestions != nil {
+			updates["shuffle_questions"] = *req.ShuffleQuestions
+		}
+		if req.ShuffleOptions != nil {
+			updates["shuffle_options"] = *req.ShuffleOptions
+		}
+		if req.ShowCorrectAnswers != nil {
+			updates["show_correct_answers"] = *req.ShowCorrectAnswers
+		}
+
+		if len(updates) > 0 {
+			if err := qs.quizRepo.UpdateQuiz(quiz.Id, updates); err != nil {
+				return nil, utils.WrapError(err, "Failed to update quiz", utils.ErrCodeInternal)
+			}
+		}
+	}
+
+	// 4. Lesson có quiz thì chuyển sang lesson_type = quiz
+	if lesson.LessonType != lessonTypeQuiz {
+		if err := qs.instructorRepo.UpdateLesson(lessonId, map[string]interface{}{"lesson_type": lessonTypeQuiz}); err != nil {
+			return nil, utils.WrapError(err, "Failed to update lesson type", utils.ErrCodeInternal)
+		}
+	}
+
+	return qs.GetLessonQuiz(instructorId, courseId, lessonId)
+}
+
+func (qs *quizService) CreateQuestion(instructorId, courseId, lessonId uint, req *dto.SaveQuizQuestionRequest) (*dto.InstructorQuizResponse, error) {
+	// 1. Kiểm tra quyền và quiz đã được tạo chưa
+	quiz, err := qs.findInstructorQuiz(instructorId, courseId, lessonId)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Validate nội dung question theo từng loại
+	question := &models.QuizQuestion{QuizId: quiz.Id}
+	if err := applyQuestionInput(question, req); err != nil {
+		return nil, err
+	}
+
+	// 3. Không truyền question_order thì thêm vào cuối
+	if question.QuestionOrder == 0 {
+		maxOrder, err := qs.quizRepo.GetMaxQuestionOrder(quiz.Id)
+		if err != nil {
+			return nil, utils.WrapError(err, "Failed to get question order", utils.ErrCodeInternal)
+		}
+		question.QuestionOrder = maxOrder + 1
+	}
+
+	// 4. Lưu question
+	if err := qs.quizRepo.CreateQuestion(question); err != nil {
+		return nil, utils.WrapError(err, "Failed to create question", utils.ErrCodeInternal)
+	}
+
+	return qs.GetLessonQuiz(instructorId, courseId, lessonId)
+}
+
+func (qs *quizService) UpdateQuestion(instructorId, courseId, lessonId, questionId uint, req *dto.SaveQuizQuestionRequest) (*dto.InstructorQuizResponse, error) {
+	// 1. Kiểm tra quyền và quiz đã được tạo chưa
+	quiz, err := qs.findInstructorQuiz(instructorId, courseId, lessonId)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Tìm question
+	question, err := qs.quizRepo.FindQuestionByIdAndQuiz(questionId, quiz.Id)
+	if err != nil {
+		return nil, utils.NewError("Question not found", utils.ErrCodeNotFound)
+	}
+
+	// 3. Thay toàn bộ nội dung question, giữ nguyên thứ tự nếu không truyền
+	currentOrder := question.QuestionOrder
+	if err := applyQuestionInput(question, req); err != nil {
+		return nil, err
+	}
+	if question.QuestionOrder == 0 {
+		question.QuestionOrder = currentOrder
+	}
+
+	if err := qs.quizRepo.SaveQuestion(question); err != nil {
+		return nil, utils.WrapError(err, "Failed to update question", utils.ErrCodeInternal)
+	}
+
+	return qs.GetLessonQuiz(instructorId, courseId, lessonId)
+}
+
+func (qs *quizService) DeleteQuestion(instructorId, courseId, lessonId, questionId uint) (*dto.DeleteQuizQuestionResponse, error) {
+	quiz, err := qs.findInstructorQuiz(instructorId, courseId, lessonId)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := qs.quizRepo.FindQuestionByIdAndQuiz(questionId, quiz.Id); err != nil {
+		return nil, utils.NewError("Question not found", utils.ErrCodeNotFound)
+	}
+
+	if err := qs.quizRepo.DeleteQuestion(questionId); err != nil {
+		return nil, utils.WrapError(err, "Failed to delete question", utils.ErrCodeInternal)
+	}
+
+	return &dto.DeleteQuizQuestionResponse{
+		Message: "Question deleted successfully",
+		Id:      questionId,
+	}, nil
+}
+
+func (qs *quizService) GetQuizInfo(userId, lessonId uint) (*dto.QuizInfoResponse, error) {
+	// 1. Kiểm tra lesson, enrollment và quiz
+	lesson, quiz, err := qs.findStudentQuiz(userId, lessonId)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Tổng hợp các attempts của user
+	attempts, err := qs.quizRepo.GetUserAttempts(userId, quiz.Id)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get quiz attempts", utils.ErrCodeInternal)
+	}
+
+	response := &dto.QuizInfoResponse{
+		QuizId:           quiz.Id,
+		LessonId:         lesson.Id,
+		LessonTitle:      lesson.Title,
+		CourseId:         lesson.CourseId,
+		TimeLimitSeconds: quiz.TimeLimitSeconds,
+		MaxAttempts:      quiz.MaxAttempts,
+		PassPercentage:   quiz.PassPercentage,
+		TotalQuestions:   questionsPerAttempt(quiz),
+		AttemptsUsed:     len(attempts),
+	}
+
+	if quiz.MaxAttempts > 0 {
+		remaining := quiz.MaxAttempts - len(attempts)
+		if remaining < 0 {
+			remaining = 0
+		}
+		response.AttemptsRemaining = &remaining
+	}
+
+	for i := range attempts {
+		attempt := &attempts[i]
+		if attempt.Status == quizAttemptInProgress && !isAttemptOverdue(attempt, time.Now()) {
+			attemptId := attempt.Id
+			response.InProgressAttemptId = &attemptId
+		}
+		if attempt.Status != quizAttemptSubmitted {
+			continue
+		}
+		if response.BestScorePercentage == nil || attempt.ScorePercentage > *response.BestScorePercentage {
+			best := attempt.ScorePercentage
+			response.BestScorePercentage = &best
+		}
+		if attempt.Passed {
+			response.Passed = true
+		}
+	}
+
+	return response, nil
+}
+
+func (qs *quizService) StartAttempt(userId, lessonId uint) (*dto.StartQuizAttemptResponse, error) {
+	// 1. Kiểm tra lesson, enrollment và quiz
+	_, quiz, err := qs.findStudentQuiz(userId, lessonId)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(quiz.Questions) == 0 {
+		return nil, utils.NewError("This quiz has no questions yet", utils.ErrCodeBadRequest)
+	}
+
+	// 2. Attempt đang làm dở và còn hạn thì trả lại để làm tiếp, hết hạn thì đóng lại
+	now := time.Now()
+	inProgress, err := qs.quizRepo.FindInProgressAttempt(userId, quiz.Id)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to check quiz attempt", utils.ErrCodeInternal)
+	}
+	if inProgress != nil {
+		if !isAttemptOverdue(inProgress, now) {
+			return toStartQuizAttemptResponse(inProgress, quiz), nil
+		}
+		if err := qs.expireAttempt(inProgress, now); err != nil {
+			return nil, err
+		}
+	}
+
+	// 3. Kiểm tra giới hạn số lần làm
+	attemptCount, err := qs.quizRepo.CountUserAttempts(userId, quiz.Id)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to count quiz attempts", utils.ErrCodeInternal)
+	}
+	if quiz.MaxAttempts > 0 && attemptCount >= quiz.MaxAttempts {
+		return nil, utils.NewError("You have used all attempts for this quiz", utils.ErrCodeForbidden)
+	}
+
+	// 4. Phát đề: chọn câu hỏi và thứ tự đáp án
+	attempt := &models.QuizAttempt{
+		QuizId:        quiz.Id,
+		LessonId:      quiz.LessonId,
+		CourseId:      quiz.CourseId,
+		UserId:        userId,
+		AttemptNumber: attemptCount + 1,
+		Status:        quizAttemptInProgress,
+		Questions:     buildAttemptQuestions(quiz),
+		Answers:       []models.QuizAttemptAnswer{},
+		StartedAt:     now,
+	}
+
+	if quiz.TimeLimitSeconds > 0 {
+		expiresAt := now.Add(time.Duration(quiz.TimeLimitSeconds) * time.Second)
+		attempt.ExpiresAt = &expiresAt
+	}
+
+	if err := qs.quizRepo.CreateAttempt(attempt); err != nil {
+		return nil, utils.WrapError(err, "Failed to start quiz attempt", utils.ErrCodeInternal)
+	}
+
+	return toStartQuizAttemptResponse(attempt, quiz), nil
+}
+
+func (qs *quizService) SubmitAttempt(userId, attemptId uint, req *dto.SubmitQuizAttemptRequest) (*dto.QuizAttemptResult, error) {
+	// 1. Kiểm tra attempt thuộc về user và còn đang làm
+	attempt, err := qs.quizRepo.FindAttemptById(attemptId)
+	if err != nil || attempt.UserId != userId {
+		return nil, utils.NewError("Quiz attempt not found", utils.ErrCodeNotFound)
+	}
+
+	if attempt.Status != quizAttemptInProgress {
+		return nil, utils.NewError("This quiz attempt has already been submitted", utils.ErrCodeConflict)
+	}
+
+	quiz, err := qs.quizRepo.FindByLessonId(attempt.LessonId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get quiz", utils.ErrCodeInternal)
+	}
+	if quiz == nil {
+		return nil, utils.NewError("Quiz not found", utils.ErrCodeNotFound)
+	}
+
+	// 2. Quá thời gian làm bài thì attempt bị hủy
+	now := time.Now()
+	if isAttemptOverdue(attempt, now) {
+		if err := qs.expireAttempt(attempt, now); err != nil {
+			return nil, err
+		}
+		return nil, utils.NewError("Time limit exceeded. This quiz attempt has expired", utils.ErrCodeBadRequest)
+	}
+
+	// 3. Chấm điểm phía server
+	gradeAttempt(attempt, quiz, req.Answers)
+	attempt.Status = quizAttemptSubmitted
+	attempt.SubmittedAt = &now
+
+	updated, err := qs.quizRepo.SubmitAttempt(attempt)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to submit quiz attempt", utils.ErrCodeInternal)
+	}
+	if !updated {
+		return nil, utils.NewError("This quiz attempt has already been submitted", utils.ErrCodeConflict)
+	}
+
+	// 4. Pass quiz thì hoàn thành lesson (cập nhật enrollment progress)
+	lessonCompleted := false
+	if attempt.Passed {
+		lessonCompleted = qs.completeQuizLesson(userId, attempt.LessonId)
+	}
+
+	result := toQuizAttemptResult(attempt, quiz)
+	result.LessonCompleted = lessonCompleted
+
+	return result, nil
+}
+
+func (qs *quizService) GetAttempt(userId, attemptId uint) (*dto.QuizAttemptResult, error) {
+	attempt, err := qs.quizRepo.FindAttemptById(attemptId)
+	if err != nil || attempt.UserId != userId {
+		return nil, utils.NewError("Quiz attempt not found", utils.ErrCodeNotFound)
+	}
+
+	quiz, err := qs.quizRepo.FindByLessonId(attempt.LessonId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get quiz", utils.ErrCodeInternal)
+	}
+	if quiz == nil {
+		return nil, utils.NewError("Quiz not found", utils.ErrCodeNotFound)
+	}
+
+	// Attempt đang làm dở chỉ trả về trạng thái, không trả kết quả
+	if attempt.Status == quizAttemptInProgress {
+		return &dto.QuizAttemptResult{
+			AttemptId:      attempt.Id,
+			QuizId:         attempt.QuizId,
+			LessonId:       attempt.LessonId,
+			AttemptNumber:  attempt.AttemptNumber,
+			Status:         attempt.Status,
+			PassPercentage: quiz.PassPercentage,
+			StartedAt:      attempt.StartedAt,
+			Questions:      []dto.QuizResultQuestion{},
+		}, nil
+	}
+
+	result := toQuizAttemptResult(attempt, quiz)
+	if attempt.Passed {
+		progress, err := qs.lessonRepo.GetLessonProgressDetail(userId, attempt.LessonId)
+		if err == nil {
+			result.LessonCompleted = progress.IsCompleted
+		}
+	}
+
+	return result, nil
+}
+
+func (qs *quizService) GetMyAttempts(userId, lessonId uint) (*dto.GetQuizAttemptsResponse, error) {
+	_, quiz, err := qs.findStudentQuiz(userId, lessonId)
+	if err != nil {
+		return nil, err
+	}
+
+	attempts, err := qs.quizRepo.GetUserAttempts(userId, quiz.Id)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get quiz attempts", utils.ErrCodeInternal)
+	}
+
+	items := make([]dto.QuizAttemptSummary, len(attempts))
+	for i, attempt := range attempts {
+		items[i] = dto.QuizAttemptSummary{
+			AttemptId:       attempt.Id,
+			AttemptNumber:   attempt.AttemptNumber,
+			Status:          attempt.Status,
+			Score:           attempt.Score,
+			MaxScore:        attempt.MaxScore,
+			ScorePercentage: attempt.ScorePercentage,
+			Passed:          attempt.Passed,
+			StartedAt:       attempt.StartedAt,
+			SubmittedAt:     attempt.SubmittedAt,
+		}
+	}
+
+	return &dto.GetQuizAttemptsResponse{
+		QuizId:   quiz.Id,
+		LessonId: lessonId,
+		Attempts: items,
+		Total:    len(items),
+	}, nil
+}
+
+func (qs *quizService) findInstructorLesson(instructorId, courseId, lessonId uint) (*models.Lesson, error) {
+	if _, err := qs.instructorRepo.FindCourseByIdAndInstructor(courseId, instructorId); err != nil {
+		return nil, utils.NewError("Course not found or you don't have permission", utils.ErrCodeNotFound)
+	}
+
+	lesson, err := qs.instructorRepo.FindLessonByIdAndCourse(lessonId, courseId)
+	if err != nil {
+		return nil, utils.NewError("Lesson not found", utils.ErrCodeNotFound)
+	}
+
+	return lesson, nil
+}
+
+func (qs *quizService) findInstructorQuiz(instructorId, courseId, lessonId uint) (*models.Quiz, error) {
+	if _, err := qs.findInstructorLesson(instructorId, courseId, lessonId); err != nil {
+		return nil, err
+	}
+
+	quiz, err := qs.quizRepo.FindByLessonId(lessonId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get quiz", utils.ErrCodeInternal)
+	}
+	if quiz == nil {
+		return nil, utils.NewError("Create the quiz settings for this lesson first", utils.ErrCodeNotFound)
+	}
+
+	return quiz, nil
+}
+
+// findStudentQuiz kiểm tra lesson là quiz, user đã enroll và lesson không bị khóa
+func (qs *quizService) findStudentQuiz(userId, lessonId uint) (*models.Lesson, *models.Quiz, error) {
+	lesson, err := qs.quizRepo.FindLessonById(lessonId)
+	if err != nil || !lesson.IsPublished {
+		return nil, nil, utils.NewError("Lesson not found", utils.ErrCodeNotFound)
+	}
+
+	if lesson.LessonType != lessonTypeQuiz {
+		return nil, nil, utils.NewError("This lesson is not a quiz", utils.ErrCodeBadRequest)
+	}
+
+	enrollment, isEnrolled := qs.enrollmentRepo.CheckEnrollment(userId, lesson.CourseId)
+	if !isEnrolled {
+		return nil, nil, utils.NewError("You must enroll in this course to take this quiz", utils.ErrCodeForbidden)
+	}
+
+	lock, err := resolveLessonLock(qs.lessonRepo, userId, lesson, enrollment.EnrolledAt)
+	if err != nil {
+		return nil, nil, utils.WrapError(err, "Failed to check lesson lock", utils.ErrCodeInternal)
+	}
+	if lock.IsLocked {
+		return nil, nil, utils.NewError(lessonLockMessage(lock.Reason), utils.ErrCodeForbidden)
+	}
+
+	quiz, err := qs.quizRepo.FindByLessonId(lessonId)
+	if err != nil {
+		return nil, nil, utils.WrapError(err, "Failed to get quiz", utils.ErrCodeInternal)
+	}
+	if quiz == nil {
+		return nil, nil, utils.NewError("Quiz not found", utils.ErrCodeNotFound)
+	}
+
+	return lesson, quiz, nil
+}
+
+func (qs *quizService) expireAttempt(attempt *models.QuizAttempt, now time.Time) error {
+	attempt.Status = quizAttemptExpired
+	attempt.SubmittedAt = &now
+	attempt.Score = 0
+	attempt.ScorePercentage = 0
+	attempt.Passed = false
+
+	if _, err := qs.quizRepo.SubmitAttempt(attempt); err != nil {
+		return utils.WrapError(err, "Failed to close expired quiz attempt", utils.ErrCodeInternal)
+	}
+	return nil
+}
+
+// completeQuizLesson đánh dấu lesson hoàn thành qua ProgressService, lỗi chỉ log vì attempt đã được lưu
+func (qs *quizService) completeQuizLesson(userId, lessonId uint) bool {
+	progress, err := qs.lessonRepo.GetLessonProgressDetail(userId, lessonId)
+	if err == nil && progress.IsCompleted {
+		return true
+	}
+
+	if _, err := qs.progressService.CompleteLesson(userId, lessonId, &dto.CompleteLessonRequest{}); err != nil {
+		log.Printf("Failed to complete quiz lesson %d for user %d: %v", lessonId, userId, err)
+		return false
+	}
+	return true
+}
+
+func applyQuizSettings(quiz *models.Quiz, req *dto.UpsertQuizRequest) {
+	if req.TimeLimitSeconds != nil {
+		quiz.TimeLimitSeconds = *req.TimeLimitSeconds
+	}
+	if req.MaxAttempts != nil {
+		quiz.MaxAttempts = *req.MaxAttempts
+	}
+	if req.PassPercentage != nil {
+		quiz.PassPercentage = *req.PassPercentage
+	}
+	if req.QuestionsPerAttempt != nil {
+		quiz.QuestionsPerAttempt = *req.QuestionsPerAttempt
+	}
+	if req.ShuffleQuestions != nil {
+		quiz.ShuffleQuestions = *req.ShuffleQuestions
+	}
+	if req.ShuffleOptions != nil {
+		quiz.ShuffleOptions = *req.ShuffleOptions
+	}
+	if req.ShowCorrectAnswers != nil {
+		quiz.ShowCorrectAnswers = *req.ShowCorrectAnswers
+	}
+}
+
+// applyQuestionInput validate và gán nội dung question theo từng loại câu hỏi.
+// Option gửi kèm key hiện có được giữ nguyên key để attempt đã phát/đã nộp vẫn chấm đúng sau khi sửa question
+func applyQuestionInput(question *models.QuizQuestion, req *dto.SaveQuizQuestionRequest) error {
+	existingKeys := make(map[string]bool, len(question.Options))
+	for _, option := range question.Options {
+		existingKeys[option.Key] = true
+	}
+
+	question.QuestionType = req.QuestionType
+	question.Text = strings.TrimSpace(req.Text)
+	question.Explanation = req.Explanation
+	question.QuestionOrder = req.QuestionOrder
+	question.CaseSensitive = false
+	question.Options = []models.QuizOption{}
+	question.AcceptedAnswers = []string{}
+
+	question.Points = req.Points
+	if question.Points == 0 {
+		question.Points = 1
+	}
+
+	if question.Text == "" {
+		return utils.NewError("Question text is required", utils.ErrCodeBadRequest)
+	}
+
+	switch req.QuestionType {
+	case questionTypeSingleChoice, questionTypeMultipleChoice:
+		if len(req.Options) < 2 {
+			return utils.NewError("Choice questions need at least 2 options", utils.ErrCodeBadRequest)
+		}
+
+		correctCount := 0
+		for _, option := range req.Options {
+			if option.IsCorrect {
+				correctCount++
+			}
+			key := option.Key
+			if !existingKeys[key] {
+				key = uuid.NewString()[:8]
+			}
+			delete(existingKeys, key) // Mỗi key chỉ dùng cho một option
+
+			question.Options = append(question.Options, models.QuizOption{
+				Key:       key,
+				Text:      option.Text,
+				IsCorrect: option.IsCorrect,
+			})
+		}
+
+		if req.QuestionType == questionTypeSingleChoice && correctCount != 1 {
+			return utils.NewError("Single choice questions must have exactly 1 correct option", utils.ErrCodeBadRequest)
+		}
+		if req.QuestionType == questionTypeMultipleChoice && correctCount == 0 {
+			return utils.NewError("Multiple choice questions must have at least 1 correct option", utils.ErrCodeBadRequest)
+		}
+
+	case questionTypeTrueFalse:
+		if req.CorrectAnswer == nil {
+			return utils.NewError("correct_answer is required for true/false questions", utils.ErrCodeBadRequest)
+		}
+		question.Options = []models.QuizOption{
+			{Key: "true", Text: "True", IsCorrect: *req.CorrectAnswer},
+			{Key: "false", Text: "False", IsCorrect: !*req.CorrectAnswer},
+		}
+
+	case questionTypeShortAnswer:
+		for _, answer := range req.AcceptedAnswers {
+			if normalized := strings.TrimSpace(answer); normalized != "" {
+				question.AcceptedAnswers = append(question.AcceptedAnswers, normalized)
+			}
+		}
+		if len(question.AcceptedAnswers) == 0 {
+			return utils.NewError("Short answer questions need at least 1 accepted answer", utils.ErrCodeBadRequest)
+		}
+		question.CaseSensitive = req.CaseSensitive
+	}
+
+	return nil
+}
+
+func questionsPerAttempt(quiz *models.Quiz) int {
+	if quiz.QuestionsPerAttempt > 0 && quiz.QuestionsPerAttempt < len(quiz.Questions) {
+		return quiz.QuestionsPerAttempt
+	}
+	return len(quiz.Questions)
+}
+
+// buildAttemptQuestions chọn câu hỏi từ question bank và xáo trộn theo cấu hình quiz
+func buildAttemptQuestions(quiz *models.Quiz) []models.QuizAttemptItem {
+	questions := make([]models.QuizQuestion, len(quiz.Questions))
+	copy(questions, quiz.Questions)
+
+	count := questionsPerAttempt(quiz)
+	if count < len(questions) {
+		// Rút ngẫu nhiên từ bank, nếu không shuffle thì giữ thứ tự gốc
+		rand.Shuffle(len(questions), func(i, j int) { questions[i], questions[j] = questions[j], questions[i] })
+		questions = questions[:count]
+		if !quiz.ShuffleQuestions {
+			sort.SliceStable(questions, func(i, j int) bool {
+				return questions[i].QuestionOrder < questions[j].QuestionOrder
+			})
+		}
+	} else if quiz.ShuffleQuestions {
+		rand.Shuffle(len(questions), func(i, j int) { questions[i], questions[j] = questions[j], questions[i] })
+	}
+
+	items := make([]models.QuizAttemptItem, len(questions))
+	for i, question := range questions {
+		keys := make([]string, len(question.Options))
+		for j, option := range question.Options {
+			keys[j] = option.Key
+		}
+
+		if quiz.ShuffleOptions && question.QuestionType != questionTypeTrueFalse {
+			rand.Shuffle(len(keys), func(a, b int) { keys[a], keys[b] = keys[b], keys[a] })
+		}
+
+		items[i] = models.QuizAttemptItem{
+			QuestionId: question.Id,
+			OptionKeys: keys,
+		}
+	}
+
+	return items
+}
+
+func isAttemptOverdue(attempt *models.QuizAttempt, now time.Time) bool {
+	return attempt.ExpiresAt != nil && now.After(attempt.ExpiresAt.Add(quizSubmitGracePeriod))
+}
+
+// gradeAttempt chấm từng câu theo đề đã phát, câu hỏi đã bị xóa khỏi bank thì bỏ qua
+func gradeAttempt(attempt *models.QuizAttempt, quiz *models.Quiz, answers []dto.QuizAnswerInput) {
+	questionMap := make(map[uint]*models.QuizQuestion)
+	for i := range quiz.Questions {
+		questionMap[quiz.Questions[i].Id] = &quiz.Questions[i]
+	}
+
+	answerMap := make(map[uint]dto.QuizAnswerInput)
+	for _, answer := range answers {
+		answerMap[answer.QuestionId] = answer
+	}
+
+	score := 0.0
+	maxScore := 0.0
+	gradedAnswers := make([]models.QuizAttemptAnswer, 0, len(attempt.Questions))
+
+	for _, item := range attempt.Questions {
+		question, ok := questionMap[item.QuestionId]
+		if !ok {
+			continue
+		}
+
+		maxScore += question.Points
+		answer := answerMap[item.QuestionId]
+
+		graded := models.QuizAttemptAnswer{
+			QuestionId:      item.QuestionId,
+			SelectedOptions: answer.SelectedOptions,
+			TextAnswer:      answer.TextAnswer,
+		}
+		if graded.SelectedOptions == nil {
+			graded.SelectedOptions = []string{}
+		}
+
+		if isAnswerCorrect(question, answer) {
+			graded.IsCorrect = true
+			graded.PointsAwarded = question.Points
+			score += question.Points
+		}
+
+		gradedAnswers = append(gradedAnswers, graded)
+	}
+
+	attempt.Answers = gradedAnswers
+	attempt.Score = score
+	attempt.MaxScore = maxScore
+	attempt.ScorePercentage = 0
+	if maxScore > 0 {
+		attempt.ScorePercentage = math.Round(score/maxScore*10000) / 100
+	}
+	attempt.Passed = attempt.ScorePercentage >= quiz.PassPercentage
+}
+
+func isAnswerCorrect(question *models.QuizQuestion, answer dto.QuizAnswerInput) bool {
+	switch question.QuestionType {
+	case questionTypeShortAnswer:
+		given := normalizeShortAnswer(answer.TextAnswer, question.CaseSensitive)
+		if given == "" {
+			return false
+		}
+		for _, accepted := range question.AcceptedAnswers {
+			if given == normalizeShortAnswer(accepted, question.CaseSensitive) {
+				return true
+			}
+		}
+		return false
+
+	default:
+		// Choice questions: tập đáp án chọn phải trùng khớp tập đáp án đúng (all-or-nothing)
+		correct := make(map[string]bool)
+		for _, option := range question.Options {
+			if option.IsCorrect {
+				correct[option.Key] = true
+			}
+		}
+
+		selected := make(map[string]bool)
+		for _, key := range answer.SelectedOptions {
+			selected[key] = true
+		}
+
+		if len(selected) != len(correct) {
+			return false
+		}
+		for key := range selected {
+			if !correct[key] {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+func normalizeShortAnswer(value string, caseSensitive bool) string {
+	normalized := strings.Join(strings.Fields(value), " ")
+	if !caseSensitive {
+		normalized = strings.ToLower(normalized)
+	}
+	return normalized
+}
+
+func toInstructorQuizResponse(quiz *models.Quiz) *dto.InstructorQuizResponse {
+	totalPoints := 0.0
+	questions := make([]dto.InstructorQuizQuestion, len(quiz.Questions))
+	for i, question := range quiz.Questions {
+		totalPoints += question.Points
+
+		options := make([]dto.QuizOptionItem, len(question.Options))
+		for j, option := range question.Options {
+			options[j] = dto.QuizOptionItem{
+				Key:       option.Key,
+				Text:      option.Text,
+				IsCorrect: option.IsCorrect,
+			}
+		}
+
+		questions[i] = dto.InstructorQuizQuestion{
+			Id:              question.Id,
+			QuestionType:    question.QuestionType,
+			Text:            question.Text,
+			Explanation:     question.Explanation,
+			Points:          question.Points,
+			QuestionOrder:   question.QuestionOrder,
+			Options:         options,
+			AcceptedAnswers: question.AcceptedAnswers,
+			CaseSensitive:   question.CaseSensitive,
+		}
+	}
+
+	return &dto.InstructorQuizResponse{
+		Id:                  quiz.Id,
+		LessonId:            quiz.LessonId,
+		CourseId:            quiz.CourseId,
+		TimeLimitSeconds:    quiz.TimeLimitSeconds,
+		MaxAttempts:         quiz.MaxAttempts,
+		PassPercentage:      quiz.PassPercentage,
+		QuestionsPerAttempt: quiz.QuestionsPerAttempt,
+		ShuffleQuestions:    quiz.ShuffleQuestions,
+		ShuffleOptions:      quiz.ShuffleOptions,
+		ShowCorrectAnswers:  quiz.ShowCorrectAnswers,
+		TotalQuestions:      len(questions),
+		TotalPoints:         totalPoints,
+		Questions:           questions,
+		UpdatedAt:           quiz.UpdatedAt,
+	}
+}
+
+// attemptOptions trả về options của question theo thứ tự đã phát trong attempt, không kèm đáp án đúng
+func attemptOptions(question *models.QuizQuestion, optionKeys []string) []dto.QuizAttemptOption {
+	optionMap := make(map[string]models.QuizOption)
+	for _, option := range question.Options {
+		optionMap[option.Key] = option
+	}
+
+	options := make([]dto.QuizAttemptOption, 0, len(optionKeys))
+	for _, key := range optionKeys {
+		if option, ok := optionMap[key]; ok {
+			options = append(options, dto.QuizAttemptOption{Key: option.Key, Text: option.Text})
+		}
+	}
+	return options
+}
+
+func toStartQuizAttemptResponse(attempt *models.QuizAttempt, quiz *models.Quiz) *dto.StartQuizAttemptResponse {
+	questionMap := make(map[uint]*models.QuizQuestion)
+	for i := range quiz.Questions {
+		questionMap[quiz.Questions[i].Id] = &quiz.Questions[i]
+	}
+
+	questions := make([]dto.QuizAttemptQuestion, 0, len(attempt.Questions))
+	for _, item := range attempt.Questions {
+		question, ok := questionMap[item.QuestionId]
+		if !ok {
+			continue
+		}
+
+		questions = append(questions, dto.QuizAttemptQuestion{
+			QuestionId:   question.Id,
+			QuestionType: question.QuestionType,
+			Text:         question.Text,
+			Points:       question.Points,
+			Options:      attemptOptions(question, item.OptionKeys),
+		})
+	}
+
+	return &dto.StartQuizAttemptResponse{
+		AttemptId:        attempt.Id,
+		QuizId:           attempt.QuizId,
+		LessonId:         attempt.LessonId,
+		AttemptNumber:    attempt.AttemptNumber,
+		TimeLimitSeconds: quiz.TimeLimitSeconds,
+		StartedAt:        attempt.StartedAt,
+		ExpiresAt:        attempt.ExpiresAt,
+		Questions:        questions,
+	}
+}
+
+func toQuizAttemptResult(attempt *models.QuizAttempt, quiz *models.Quiz) *dto.QuizAttemptResult {
+	questionMap := make(map[uint]*models.QuizQuestion)
+	for i := range quiz.Questions {
+		questionMap[quiz.Questions[i].Id] = &quiz.Questions[i]
+	}
+
+	answerMap := make(map[uint]models.QuizAttemptAnswer)
+	for _, answer := range attempt.Answers {
+		answerMap[answer.QuestionId] = answer
+	}
+
+	questions := make([]dto.QuizResultQuestion, 0, len(attempt.Questions))
+	for _, item := range attempt.Questions {
+		question, ok := questionMap[item.QuestionId]
+		if !ok {
+			continue
+		}
+
+		answer := answerMap[item.QuestionId]
+		resultQuestion := dto.QuizResultQuestion{
+			QuestionId:      question.Id,
+			QuestionType:    question.QuestionType,
+			Text:            question.Text,
+			Points:          question.Points,
+			PointsAwarded:   answer.PointsAwarded,
+			IsCorrect:       answer.IsCorrect,
+			Options:         attemptOptions(question, item.OptionKeys),
+			SelectedOptions: answer.SelectedOptions,
+			TextAnswer:      answer.TextAnswer,
+		}
+
+		if quiz.ShowCorrectAnswers && attempt.Status == quizAttemptSubmitted {
+			for _, option := range question.Options {
+				if option.IsCorrect {
+					resultQuestion.CorrectOptions = append(resultQuestion.CorrectOptions, option.Key)
+				}
+			}
+			resultQuestion.AcceptedAnswers = question.AcceptedAnswers
+			resultQuestion.Explanation = question.Explanation
+		}
+
+		questions = append(questions, resultQuestion)
+	}
+
+	return &dto.QuizAttemptResult{
+		AttemptId:       attempt.Id,
+		QuizId:          attempt.QuizId,
+		LessonId:        attempt.LessonId,
+		AttemptNumber:   attempt.AttemptNumber,
+		Status:          attempt.Status,
+		Score:           attempt.Score,
+		MaxScore:        attempt.MaxScore,
+		ScorePercentage: attempt.ScorePercentage,
+		PassPercentage:  quiz.PassPercentage,
+		Passed:          attempt.Passed,
+		StartedAt:       attempt.StartedAt,
+		SubmittedAt:     attempt.SubmittedAt,
+		Questions:       questions,
+	}
+}
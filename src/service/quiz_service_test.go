@@ -0,0 +1,224 @@
+package service
+
+import (
+	"lms/src/dto"
+	"lms/src/models"
+	"sort"
+	"testing"
+)
+
+func choiceQuestion(id uint, questionType string, correct ...string) models.QuizQuestion {
+	question := models.QuizQuestion{Id: id, QuestionType: questionType, Points: 1, QuestionOrder: int(id)}
+	for _, key := range []string{"a", "b", "c", "d"} {
+		isCorrect := false
+		for _, c := range correct {
+			isCorrect = isCorrect || c == key
+		}
+		question.Options = append(question.Options, models.QuizOption{Key: key, Text: key, IsCorrect: isCorrect})
+	}
+	return question
+}
+
+func TestNormalizeShortAnswer(t *testing.T) {
+	tests := []struct {
+		value         string
+		caseSensitive bool
+		want          string
+	}{
+		{"  Hà   Nội ", false, "hà nội"},
+		{"Hà\tNội\n", true, "Hà Nội"},
+		{"GO", false, "go"},
+		{"GO", true, "GO"},
+		{"   ", false, ""},
+	}
+
+	for _, tt := range tests {
+		if got := normalizeShortAnswer(tt.value, tt.caseSensitive); got != tt.want {
+			t.Errorf("normalizeShortAnswer(%q, %v) = %q, want %q", tt.value, tt.caseSensitive, got, tt.want)
+		}
+	}
+}
+
+func TestIsAnswerCorrect(t *testing.T) {
+	single := choiceQuestion(1, questionTypeSingleChoice, "b")
+	multiple := choiceQuestion(2, questionTypeMultipleChoice, "a", "c")
+	shortAnswer := models.QuizQuestion{Id: 3, QuestionType: questionTypeShortAnswer, AcceptedAnswers: []string{"Ho Chi Minh", "Sài Gòn"}}
+	caseSensitive := models.QuizQuestion{Id: 4, QuestionType: questionTypeShortAnswer, AcceptedAnswers: []string{"GET"}, CaseSensitive: true}
+
+	tests := []struct {
+		name     string
+		question *models.QuizQuestion
+		answer   dto.QuizAnswerInput
+		want     bool
+	}{
+		{"single correct", &single, dto.QuizAnswerInput{SelectedOptions: []string{"b"}}, true},
+		{"single wrong", &single, dto.QuizAnswerInput{SelectedOptions: []string{"a"}}, false},
+		{"single unanswered", &single, dto.QuizAnswerInput{}, false},
+		{"single extra option", &single, dto.QuizAnswerInput{SelectedOptions: []string{"b", "c"}}, false},
+		{"multiple exact", &multiple, dto.QuizAnswerInput{SelectedOptions: []string{"c", "a"}}, true},
+		{"multiple partial", &multiple, dto.QuizAnswerInput{SelectedOptions: []string{"a"}}, false},
+		{"multiple duplicate keys", &multiple, dto.QuizAnswerInput{SelectedOptions: []string{"a", "a"}}, false},
+		{"multiple with wrong option", &multiple, dto.QuizAnswerInput{SelectedOptions: []string{"a", "b"}}, false},
+		{"short answer normalized", &shortAnswer, dto.QuizAnswerInput{TextAnswer: "  ho chi   MINH "}, true},
+		{"short answer alternative", &shortAnswer, dto.QuizAnswerInput{TextAnswer: "sài gòn"}, true},
+		{"short answer wrong", &shortAnswer, dto.QuizAnswerInput{TextAnswer: "Hà Nội"}, false},
+		{"short answer empty", &shortAnswer, dto.QuizAnswerInput{TextAnswer: "   "}, false},
+		{"short answer case sensitive", &caseSensitive, dto.QuizAnswerInput{TextAnswer: "get"}, false},
+		{"short answer case sensitive exact", &caseSensitive, dto.QuizAnswerInput{TextAnswer: "GET"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isAnswerCorrect(tt.question, tt.answer); got != tt.want {
+				t.Errorf("isAnswerCorrect = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGradeAttempt(t *testing.T) {
+	first := choiceQuestion(1, questionTypeSingleChoice, "a")
+	second := choiceQuestion(2, questionTypeMultipleChoice, "b", "d")
+	second.Points = 3
+	quiz := &models.Quiz{PassPercentage: 70, Questions: []models.QuizQuestion{first, second}}
+
+	// Question 99 đã bị xóa khỏi bank sau khi phát đề
+	attemptItems := []models.QuizAttemptItem{{QuestionId: 1}, {QuestionId: 2}, {QuestionId: 99}}
+
+	tests := []struct {
+		name           string
+		answers        []dto.QuizAnswerInput
+		wantScore      float64
+		wantPercentage float64
+		wantPassed     bool
+	}{
+		{"all correct", []dto.QuizAnswerInput{{QuestionId: 1, SelectedOptions: []string{"a"}}, {QuestionId: 2, SelectedOptions: []string{"b", "d"}}}, 4, 100, true},
+		{"weighted question only", []dto.QuizAnswerInput{{QuestionId: 2, SelectedOptions: []string{"d", "b"}}}, 3, 75, true},
+		{"light question only", []dto.QuizAnswerInput{{QuestionId: 1, SelectedOptions: []string{"a"}}}, 1, 25, false},
+		{"unanswered", nil, 0, 0, false},
+		{"answer for question not in attempt ignored", []dto.QuizAnswerInput{{QuestionId: 7, SelectedOptions: []string{"a"}}}, 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempt := &models.QuizAttempt{Questions: attemptItems}
+			gradeAttempt(attempt, quiz, tt.answers)
+
+			if attempt.Score != tt.wantScore || attempt.MaxScore != 4 || attempt.ScorePercentage != tt.wantPercentage || attempt.Passed != tt.wantPassed {
+				t.Errorf("score = %v/%v (%v%%), passed = %v; want %v/4 (%v%%), passed = %v",
+					attempt.Score, attempt.MaxScore, attempt.ScorePercentage, attempt.Passed, tt.wantScore, tt.wantPercentage, tt.wantPassed)
+			}
+			if len(attempt.Answers) != 2 {
+				t.Fatalf("graded %d answers, want 2", len(attempt.Answers))
+			}
+			for _, answer := range attempt.Answers {
+				if answer.SelectedOptions == nil {
+					t.Errorf("question %d: selected options should be empty, not nil", answer.QuestionId)
+				}
+			}
+		})
+	}
+}
+
+func TestGradeAttemptRoundsPercentage(t *testing.T) {
+	quiz := &models.Quiz{PassPercentage: 66.67, Questions: []models.QuizQuestion{
+		choiceQuestion(1, questionTypeSingleChoice, "a"),
+		choiceQuestion(2, questionTypeSingleChoice, "a"),
+		choiceQuestion(3, questionTypeSingleChoice, "a"),
+	}}
+	attempt := &models.QuizAttempt{Questions: []models.QuizAttemptItem{{QuestionId: 1}, {QuestionId: 2}, {QuestionId: 3}}}
+
+	gradeAttempt(attempt, quiz, []dto.QuizAnswerInput{
+		{QuestionId: 1, SelectedOptions: []string{"a"}},
+		{QuestionId: 2, SelectedOptions: []string{"a"}},
+	})
+
+	if attempt.ScorePercentage != 66.67 || !attempt.Passed {
+		t.Errorf("percentage = %v, passed = %v; want 66.67, true", attempt.ScorePercentage, attempt.Passed)
+	}
+}
+
+func TestBuildAttemptQuestions(t *testing.T) {
+	bank := []models.QuizQuestion{
+		choiceQuestion(1, questionTypeSingleChoice, "a"),
+		choiceQuestion(2, questionTypeTrueFalse, "a"),
+		choiceQuestion(3, questionTypeMultipleChoice, "a", "b"),
+		choiceQuestion(4, questionTypeSingleChoice, "c"),
+		choiceQuestion(5, questionTypeSingleChoice, "d"),
+	}
+
+	tests := []struct {
+		name                string
+		questionsPerAttempt int
+		shuffleQuestions    bool
+		shuffleOptions      bool
+		wantCount           int
+	}{
+		{"whole bank in order", 0, false, false, 5},
+		{"bank larger than draw keeps original order", 3, false, false, 3},
+		{"draw larger than bank uses whole bank", 10, false, false, 5},
+		{"shuffled draw", 3, true, true, 3},
+		{"shuffled whole bank", 0, true, true, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quiz := &models.Quiz{
+				QuestionsPerAttempt: tt.questionsPerAttempt,
+				ShuffleQuestions:    tt.shuffleQuestions,
+				ShuffleOptions:      tt.shuffleOptions,
+				Questions:           bank,
+			}
+
+			// Chạy nhiều lần vì câu hỏi và đáp án được rút ngẫu nhiên
+			for run := 0; run < 20; run++ {
+				items := buildAttemptQuestions(quiz)
+				if len(items) != tt.wantCount {
+					t.Fatalf("got %d questions, want %d", len(items), tt.wantCount)
+				}
+
+				seen := make(map[uint]bool)
+				for i, item := range items {
+					if seen[item.QuestionId] || item.QuestionId < 1 || item.QuestionId > 5 {
+						t.Fatalf("invalid or duplicate question %d in %v", item.QuestionId, items)
+					}
+					seen[item.QuestionId] = true
+
+					if !tt.shuffleQuestions && i > 0 && items[i-1].QuestionId > item.QuestionId {
+						t.Fatalf("questions out of order without shuffle: %v", items)
+					}
+
+					keys := append([]string(nil), item.OptionKeys...)
+					if !tt.shuffleOptions || item.QuestionId == 2 {
+						// Không shuffle (và true/false luôn giữ nguyên) thì đáp án theo thứ tự gốc
+						if !sort.StringsAreSorted(keys) {
+							t.Fatalf("question %d options out of order: %v", item.QuestionId, keys)
+						}
+					}
+					sort.Strings(keys)
+					if len(keys) != 4 || keys[0] != "a" || keys[3] != "d" {
+						t.Fatalf("question %d options = %v, want a permutation of a-d", item.QuestionId, item.OptionKeys)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestBuildAttemptQuestionsDoesNotReorderBank(t *testing.T) {
+	quiz := &models.Quiz{ShuffleQuestions: true, ShuffleOptions: true, Questions: []models.QuizQuestion{
+		choiceQuestion(1, questionTypeSingleChoice, "a"),
+		choiceQuestion(2, questionTypeSingleChoice, "a"),
+		choiceQuestion(3, questionTypeSingleChoice, "a"),
+	}}
+
+	for run := 0; run < 10; run++ {
+		buildAttemptQuestions(quiz)
+	}
+
+	for i, question := range quiz.Questions {
+		if question.Id != uint(i+1) || question.Options[0].Key != "a" {
+			t.Fatalf("question bank was modified: %+v", quiz.Questions)
+		}
+	}
+}
@@ -0,0 +1,737 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"lms/src/dto"
+	"lms/src/models"
+	"lms/src/repository"
+	"lms/src/scorm"
+	"lms/src/storage"
+	"lms/src/utils"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const lessonTypeScorm = "scorm"
+
+// ScormContent là file nội dung của package, luôn stream qua app để nội dung cùng origin với API adapter của player
+type ScormContent struct {
+	Content     io.ReadCloser
+	Name        string
+	Size        int64
+	ContentType string
+	ModTime     time.Time
+}
+
+type scormService struct {
+	scormRepo       repository.ScormRepository
+	instructorRepo  repository.InstructorRepository
+	revisionRepo    repository.CourseRevisionRepository
+	lessonRepo      repository.LessonRepository
+	enrollmentRepo  repository.EnrollmentRepository
+	userRepo        repository.UserRepository
+	progressService ProgressService
+	storage         storage.Backend
+}
+
+func NewScormService(
+	scormRepo repository.ScormRepository,
+	instructorRepo repository.InstructorRepository,
+	revisionRepo repository.CourseRevisionRepository,
+	lessonRepo repository.LessonRepository,
+	enrollmentRepo repository.EnrollmentRepository,
+	userRepo repository.UserRepository,
+	progressService ProgressService,
+	scormStorage storage.Backend,
+) ScormService {
+	return &scormService{
+		scormRepo:       scormRepo,
+		instructorRepo:  instructorRepo,
+		revisionRepo:    revisionRepo,
+		lessonRepo:      lessonRepo,
+		enrollmentRepo:  enrollmentRepo,
+		userRepo:        userRepo,
+		progressService: progressService,
+		storage:         scormStorage,
+	}
+}
+
+// scormPackageLimits giới hạn package khi giải nén (chặn ZIP bomb)
+func scormPackageLimits() scorm.Limits {
+	return scorm.Limits{
+		MaxFiles:     utils.GetEnvInt("SCORM_PACKAGE_MAX_FILES", 5000),
+		MaxFileSize:  utils.ScormPackageFileRules().MaxSize,
+		MaxTotalSize: int64(utils.GetEnvInt("SCORM_PACKAGE_MAX_UNCOMPRESSED_MB", 2048)) << 20,
+	}
+}
+
+func (ss *scormService) GetInstructorPackage(instructorId, courseId, lessonId uint) (*dto.ScormPackageResponse, error) {
+	// 1. Kiểm tra quyền trên course và lesson
+	if _, err := ss.findInstructorLesson(instructorId, courseId, lessonId); err != nil {
+		return nil, err
+	}
+
+	// 2. Lấy package
+	pkg, err := ss.scormRepo.FindPackageByLessonId(lessonId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get SCORM package", utils.ErrCodeInternal)
+	}
+	if pkg == nil {
+		return nil, utils.NewError("SCORM package not found", utils.ErrCodeNotFound)
+	}
+
+	return toScormPackageResponse(pkg), nil
+}
+
+func (ss *scormService) UploadPackage(instructorId, courseId, lessonId uint, file *multipart.FileHeader) (*dto.ScormPackageResponse, error) {
+	// 1. Kiểm tra quyền trên course và lesson
+	lesson, err := ss.findInstructorLesson(instructorId, courseId, lessonId)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Validate file ZIP
+	if _, _, err := utils.ValidateFile(file, utils.ScormPackageFileRules()); err != nil {
+		return nil, utils.WrapError(err, "Invalid SCORM package", utils.ErrCodeBadRequest)
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to read SCORM package", utils.ErrCodeBadRequest)
+	}
+	defer src.Close()
+
+	// 3. Parse imsmanifest.xml và giải nén vào storage
+	ctx := context.Background()
+	pkg, err := storeScormPackage(ctx, ss.storage, courseId, lessonId, src, file.Size)
+	if err != nil {
+		return nil, scormPackageError(err)
+	}
+	pkg.UploadedBy = instructorId
+
+	// 4. Upload lại thì thay package cũ (giữ nguyên record)
+	existing, err := ss.scormRepo.FindPackageByLessonId(lessonId)
+	if err != nil {
+		deleteScormFiles(ctx, ss.storage, pkg)
+		return nil, utils.WrapError(err, "Failed to check existing SCORM package", utils.ErrCodeInternal)
+	}
+	if existing != nil {
+		pkg.Id = existing.Id
+		pkg.CreatedAt = existing.CreatedAt
+	}
+
+	if err := ss.scormRepo.SavePackage(pkg); err != nil {
+		deleteScormFiles(ctx, ss.storage, pkg)
+		return nil, utils.WrapError(err, "Failed to save SCORM package", utils.ErrCodeInternal)
+	}
+
+	// 5. Lesson có package thì chuyển sang dạng scorm
+	if lesson.LessonType != lessonTypeScorm {
+		if err := ss.instructorRepo.UpdateLesson(lessonId, map[string]interface{}{"lesson_type": lessonTypeScorm}); err != nil {
+			return nil, utils.WrapError(err, "Failed to update lesson type", utils.ErrCodeInternal)
+		}
+	}
+
+	// 6. Nội dung cũ bị thay thì suspend_data/vị trí cũ không còn dùng được, status đã đạt được giữ lại
+	if existing != nil {
+		ss.releaseScormFiles(ctx, existing)
+		if err := ss.scormRepo.ResetAttemptData(lessonId); err != nil {
+			log.Printf("Failed to reset SCORM attempt data for lesson %d: %v", lessonId, err)
+		}
+	}
+
+	return toScormPackageResponse(pkg), nil
+}
+
+func (ss *scormService) DeletePackage(instructorId, courseId, lessonId uint) (*dto.DeleteScormPackageResponse, error) {
+	// 1. Kiểm tra quyền trên course và lesson
+	if _, err := ss.findInstructorLesson(instructorId, courseId, lessonId); err != nil {
+		return nil, err
+	}
+
+	pkg, err := ss.scormRepo.FindPackageByLessonId(lessonId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get SCORM package", utils.ErrCodeInternal)
+	}
+	if pkg == nil {
+		return nil, utils.NewError("SCORM package not found", utils.ErrCodeNotFound)
+	}
+
+	// 2. Xóa record rồi xóa file
+	if err := ss.scormRepo.DeletePackage(lessonId); err != nil {
+		return nil, utils.WrapError(err, "Failed to delete SCORM package", utils.ErrCodeInternal)
+	}
+
+	ss.releaseScormFiles(context.Background(), pkg)
+
+	return &dto.DeleteScormPackageResponse{
+		Message: "SCORM package deleted successfully",
+	}, nil
+}
+
+func (ss *scormService) LaunchLesson(userId, lessonId uint) (*dto.ScormLaunchResponse, error) {
+	// 1. Lấy lesson và kiểm tra quyền xem
+	lesson, err := findPublishedLesson(ss.lessonRepo, lessonId)
+	if err != nil {
+		return nil, err
+	}
+	if lesson.LessonType != lessonTypeScorm {
+		return nil, utils.NewError("This lesson is not a SCORM lesson", utils.ErrCodeBadRequest)
+	}
+	if _, err := ss.checkScormAccess(userId, lesson); err != nil {
+		return nil, err
+	}
+
+	// 2. Lesson phải có package
+	pkg, err := ss.scormRepo.FindPackageByLessonId(lesson.Id)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get SCORM package", utils.ErrCodeInternal)
+	}
+	if pkg == nil {
+		return nil, utils.NewError("This lesson has no SCORM package", utils.ErrCodeNotFound)
+	}
+
+	attempt, err := ss.scormRepo.FindAttempt(userId, lesson.Id)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get SCORM attempt", utils.ErrCodeInternal)
+	}
+	status := scorm.StatusNotAttempted
+	if attempt != nil {
+		status = attempt.LessonStatus
+	}
+
+	// 3. URL phiên học đã ký: player, nội dung và runtime API dùng chung prefix này
+	expiresAt := time.Now().Add(utils.ScormSessionTTL())
+
+	return &dto.ScormLaunchResponse{
+		LessonId:     lesson.Id,
+		Title:        pkg.Title,
+		Version:      pkg.Version,
+		PlayerURL:    scormSessionURL(userId, lesson.Id, expiresAt.Unix()) + "/player",
+		ExpiresAt:    expiresAt,
+		LessonStatus: status,
+	}, nil
+}
+
+func (ss *scormService) GetPlayer(lessonId uint, query *dto.MediaStreamQuery) (*dto.ScormPlayer, error) {
+	// 1. Kiểm tra chữ ký phiên học
+	_, pkg, _, err := ss.verifySession(lessonId, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Launch URL tương đối: "content/" + đường dẫn đã escape từng phần + parameters của item
+	segments := strings.Split(pkg.LaunchPath, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+
+	return &dto.ScormPlayer{
+		Title:     pkg.Title,
+		LaunchURL: "content/" + strings.Join(segments, "/") + pkg.LaunchQuery,
+	}, nil
+}
+
+func (ss *scormService) GetRuntime(lessonId uint, query *dto.MediaStreamQuery) (*dto.ScormRuntimeResponse, error) {
+	// 1. Kiểm tra chữ ký phiên học
+	lesson, pkg, enrolled, err := ss.verifySession(lessonId, query)
+	if err != nil {
+		return nil, err
+	}
+
+	attempt, err := ss.scormRepo.FindAttempt(query.UserId, lesson.Id)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get SCORM attempt", utils.ErrCodeInternal)
+	}
+
+	// 2. LMSInitialize mở phiên mới: entry tính theo phiên trước, session_time chưa cộng (phiên trước không LMSFinish) được cộng vào total_time
+	entry := scorm.EntryAbInitio
+	if attempt != nil && attempt.SessionCount > 0 {
+		entry = ""
+		if attempt.Exit == "suspend" {
+			entry = scorm.EntryResume
+		}
+	}
+
+	if enrolled {
+		if attempt == nil {
+			attempt = &models.ScormAttempt{
+				UserId:       query.UserId,
+				LessonId:     lesson.Id,
+				CourseId:     lesson.CourseId,
+				LessonStatus: scorm.StatusNotAttempted,
+			}
+		}
+		attempt.TotalTimeMs += attempt.SessionTimeMs
+		attempt.SessionTimeMs = 0
+		attempt.Exit = ""
+		attempt.SessionCount++
+
+		if err := ss.scormRepo.SaveAttempt(attempt); err != nil {
+			return nil, utils.WrapError(err, "Failed to start SCORM session", utils.ErrCodeInternal)
+		}
+	}
+
+	// 3. Data model cho LMSGetValue
+	studentName := ""
+	if user, err := ss.userRepo.FindById(query.UserId); err == nil {
+		studentName = user.FullName
+	}
+
+	values := scormRuntimeValues(attempt, pkg, enrolled)
+	values["cmi.core.student_id"] = strconv.FormatUint(uint64(query.UserId), 10)
+	values["cmi.core.student_name"] = studentName
+	values["cmi.core.entry"] = entry
+
+	return &dto.ScormRuntimeResponse{
+		LessonId:        lesson.Id,
+		Values:          values,
+		LessonCompleted: attempt != nil && scorm.IsCompleted(attempt.LessonStatus),
+	}, nil
+}
+
+func (ss *scormService) CommitRuntime(lessonId uint, query *dto.MediaStreamQuery, req *dto.CommitScormRuntimeRequest) (*dto.ScormRuntimeResponse, error) {
+	// 1. Kiểm tra chữ ký phiên học
+	lesson, pkg, enrolled, err := ss.verifySession(lessonId, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Validate từng element theo data model SCORM 1.2 (theo thứ tự tên để lỗi trả về ổn định)
+	elements := make([]string, 0, len(req.Values))
+	for element := range req.Values {
+		elements = append(elements, element)
+	}
+	sort.Strings(elements)
+
+	for _, element := range elements {
+		if err := scorm.ValidateValue(element, req.Values[element]); err != nil {
+			return nil, utils.WrapError(err, "Invalid SCORM runtime data", utils.ErrCodeBadRequest)
+		}
+	}
+
+	// Chưa enroll (preview lesson) thì không lưu gì (lesson_mode = browse)
+	if !enrolled {
+		return &dto.ScormRuntimeResponse{
+			LessonId: lesson.Id,
+			Values:   scormRuntimeValues(nil, pkg, false),
+		}, nil
+	}
+
+	// 3. Áp dữ liệu vào attempt
+	attempt, err := ss.scormRepo.FindAttempt(query.UserId, lesson.Id)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get SCORM attempt", utils.ErrCodeInternal)
+	}
+	if attempt == nil {
+		attempt = &models.ScormAttempt{
+			UserId:       query.UserId,
+			LessonId:     lesson.Id,
+			CourseId:     lesson.CourseId,
+			LessonStatus: scorm.StatusNotAttempted,
+			SessionCount: 1,
+		}
+	}
+
+	if err := applyScormValues(attempt, req.Values, pkg.MasteryScore); err != nil {
+		return nil, utils.WrapError(err, "Invalid SCORM runtime data", utils.ErrCodeBadRequest)
+	}
+
+	// LMSFinish: session_time được cộng vào total_time
+	if req.Finish {
+		attempt.TotalTimeMs += attempt.SessionTimeMs
+		attempt.SessionTimeMs = 0
+	}
+
+	// 4. Lưu attempt
+	if err := ss.scormRepo.SaveAttempt(attempt); err != nil {
+		return nil, utils.WrapError(err, "Failed to save SCORM runtime data", utils.ErrCodeInternal)
+	}
+
+	// 5. passed/completed thì hoàn thành lesson qua ProgressService (tính vào tiến độ course)
+	lessonCompleted := false
+	if scorm.IsCompleted(attempt.LessonStatus) {
+		lessonCompleted = ss.completeScormLesson(query.UserId, lesson.Id, attempt)
+	}
+
+	return &dto.ScormRuntimeResponse{
+		LessonId:        lesson.Id,
+		Values:          scormRuntimeValues(attempt, pkg, true),
+		LessonCompleted: lessonCompleted,
+	}, nil
+}
+
+func (ss *scormService) ResolveContent(lessonId uint, query *dto.MediaStreamQuery, filePath string) (*ScormContent, error) {
+	// 1. Kiểm tra chữ ký, enrollment và unlock rules như runtime API (lesson có thể bị khóa lại sau khi phiên được tạo)
+	_, pkg, _, err := ss.verifySession(lessonId, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Đường dẫn phải nằm trong package
+	name := strings.TrimPrefix(filePath, "/")
+	if !scorm.ValidEntryName(name) {
+		return nil, utils.NewError("File not found", utils.ErrCodeNotFound)
+	}
+
+	ctx := context.Background()
+	key := pkg.StoragePrefix + "/" + name
+	info, err := ss.storage.Stat(ctx, key)
+	if errors.Is(err, storage.ErrObjectNotFound) {
+		return nil, utils.NewError("File not found", utils.ErrCodeNotFound)
+	}
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get SCORM file", utils.ErrCodeInternal)
+	}
+
+	reader, err := ss.storage.Get(ctx, key)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to open SCORM file", utils.ErrCodeInternal)
+	}
+
+	return &ScormContent{
+		Content:     reader,
+		Name:        path.Base(name),
+		Size:        info.Size,
+		ContentType: scormContentType(name, info.ContentType),
+		ModTime:     info.LastModified,
+	}, nil
+}
+
+// verifySession kiểm tra chữ ký phiên học và quyền xem lesson, enrolled = false với preview lesson chưa enroll
+func (ss *scormService) verifySession(lessonId uint, query *dto.MediaStreamQuery) (*models.Lesson, *models.ScormPackage, bool, error) {
+	lesson, err := findPublishedLesson(ss.lessonRepo, lessonId)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	if !utils.VerifyMediaSignature(utils.MediaPurposeScorm, query.UserId, lesson.Id, query.ExpiresAt, query.Signature) {
+		return nil, nil, false, utils.NewError("Invalid or expired SCORM session", utils.ErrCodeForbidden)
+	}
+
+	// Kiểm tra lại enrollment vì có thể đã bị hủy sau khi phiên được tạo
+	enrolled, err := ss.checkScormAccess(query.UserId, lesson)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	pkg, err := ss.scormRepo.FindPackageByLessonId(lesson.Id)
+	if err != nil {
+		return nil, nil, false, utils.WrapError(err, "Failed to get SCORM package", utils.ErrCodeInternal)
+	}
+	if pkg == nil {
+		return nil, nil, false, utils.NewError("This lesson has no SCORM package", utils.ErrCodeNotFound)
+	}
+
+	return lesson, pkg, enrolled, nil
+}
+
+// checkScormAccess - preview lesson xem được không cần enroll (không lưu dữ liệu), còn lại phải enroll và lesson đã mở khóa
+func (ss *scormService) checkScormAccess(userId uint, lesson *models.Lesson) (bool, error) {
+	if lesson.IsPreview {
+		_, isEnrolled := ss.enrollmentRepo.CheckEnrollment(userId, lesson.CourseId)
+		return isEnrolled, nil
+	}
+
+	if err := checkLessonAccess(ss.lessonRepo, ss.enrollmentRepo, userId, lesson); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// completeScormLesson đánh dấu lesson hoàn thành qua ProgressService, lỗi chỉ log vì dữ liệu runtime đã được lưu
+func (ss *scormService) completeScormLesson(userId, lessonId uint, attempt *models.ScormAttempt) bool {
+	progress, err := ss.lessonRepo.GetLessonProgressDetail(userId, lessonId)
+	if err == nil && progress.IsCompleted {
+		return true
+	}
+
+	req := &dto.CompleteLessonRequest{
+		WatchDuration: int((attempt.TotalTimeMs + attempt.SessionTimeMs) / 1000),
+	}
+	if _, err := ss.progressService.CompleteLesson(userId, lessonId, req); err != nil {
+		log.Printf("Failed to complete SCORM lesson %d for user %d: %v", lessonId, userId, err)
+		return false
+	}
+	return true
+}
+
+func (ss *scormService) findInstructorLesson(instructorId, courseId, lessonId uint) (*models.Lesson, error) {
+	if _, err := ss.instructorRepo.FindCourseByIdAndInstructor(courseId, instructorId); err != nil {
+		return nil, utils.NewError("Course not found or you don't have permission", utils.ErrCodeNotFound)
+	}
+
+	lesson, err := ss.instructorRepo.FindLessonByIdAndCourse(lessonId, courseId)
+	if err != nil {
+		return nil, utils.NewError("Lesson not found", utils.ErrCodeNotFound)
+	}
+
+	return lesson, nil
+}
+
+// applyScormValues ghi các element đã validate vào attempt.
+// Status đã passed/completed không bị hạ xuống (nội dung thường gửi lại "incomplete" khi mở lại).
+func applyScormValues(attempt *models.ScormAttempt, values map[string]string, masteryScore *float64) error {
+	previousStatus := attempt.LessonStatus
+
+	for element, value := range values {
+		switch element {
+		case scorm.ElementLessonStatus:
+			attempt.LessonStatus = value
+		case scorm.ElementLessonLocation:
+			attempt.LessonLocation = value
+		case scorm.ElementSuspendData:
+			attempt.SuspendData = value
+		case scorm.ElementExit:
+			attempt.Exit = value
+		case scorm.ElementScoreRaw:
+			attempt.ScoreRaw = parseScormScore(value)
+		case scorm.ElementScoreMin:
+			attempt.ScoreMin = parseScormScore(value)
+		case scorm.ElementScoreMax:
+			attempt.ScoreMax = parseScormScore(value)
+		case scorm.ElementSessionTime:
+			duration, err := scorm.ParseTimespan(value)
+			if err != nil {
+				return err
+			}
+			attempt.SessionTimeMs = duration.Milliseconds()
+		}
+	}
+
+	attempt.LessonStatus = scorm.ApplyMasteryScore(attempt.LessonStatus, attempt.ScoreRaw, masteryScore)
+	if scorm.IsCompleted(previousStatus) && !scorm.IsCompleted(attempt.LessonStatus) {
+		attempt.LessonStatus = previousStatus
+	}
+	if scorm.IsCompleted(attempt.LessonStatus) && attempt.CompletedAt == nil {
+		now := time.Now()
+		attempt.CompletedAt = &now
+	}
+	return nil
+}
+
+// scormRuntimeValues trả về data model cmi.* (trừ thông tin học viên) từ attempt, attempt nil là lần đầu
+func scormRuntimeValues(attempt *models.ScormAttempt, pkg *models.ScormPackage, enrolled bool) map[string]string {
+	values := map[string]string{
+		"cmi.core._children":             "student_id,student_name,lesson_location,credit,lesson_status,entry,score,total_time,lesson_mode,exit,session_time",
+		"cmi.core.score._children":       "raw,min,max",
+		"cmi.core.credit":                "credit",
+		"cmi.core.lesson_mode":           "normal",
+		"cmi.core.lesson_status":         scorm.StatusNotAttempted,
+		"cmi.core.lesson_location":       "",
+		"cmi.core.score.raw":             "",
+		"cmi.core.score.min":             "",
+		"cmi.core.score.max":             "",
+		"cmi.core.total_time":            scorm.FormatTimespan(0),
+		"cmi.suspend_data":               "",
+		"cmi.launch_data":                "",
+		"cmi.student_data.mastery_score": "",
+	}
+
+	if !enrolled {
+		values["cmi.core.credit"] = "no-credit"
+		values["cmi.core.lesson_mode"] = "browse"
+	}
+	if pkg.MasteryScore != nil {
+		values["cmi.student_data.mastery_score"] = formatScormScore(pkg.MasteryScore)
+	}
+
+	if attempt != nil {
+		values["cmi.core.lesson_status"] = attempt.LessonStatus
+		values["cmi.core.lesson_location"] = attempt.LessonLocation
+		values["cmi.core.score.raw"] = formatScormScore(attempt.ScoreRaw)
+		values["cmi.core.score.min"] = formatScormScore(attempt.ScoreMin)
+		values["cmi.core.score.max"] = formatScormScore(attempt.ScoreMax)
+		values["cmi.core.total_time"] = scorm.FormatTimespan(time.Duration(attempt.TotalTimeMs) * time.Millisecond)
+		values["cmi.suspend_data"] = attempt.SuspendData
+	}
+
+	return values
+}
+
+func parseScormScore(value string) *float64 {
+	if value == "" {
+		return nil
+	}
+	score, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil
+	}
+	return &score
+}
+
+func formatScormScore(score *float64) string {
+	if score == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*score, 'f', -1, 64)
+}
+
+// scormSessionURL là prefix URL đã ký của phiên học: /api/v1/scorm/sessions/:lesson_id/:uid/:exp/:sig
+// Chữ ký nằm trong path để đường dẫn tương đối trong nội dung (js, css, ảnh) vẫn mang chữ ký.
+// Phiên học nằm trên SCORM_CONTENT_ORIGIN nếu có cấu hình, tách khỏi origin của API.
+func scormSessionURL(userId, lessonId uint, expiresAt int64) string {
+	baseURL := utils.GetEnv("BASE_URL", "http://localhost:8080")
+	if origin, ok := utils.ScormContentOrigin(); ok {
+		baseURL = origin
+	}
+	return fmt.Sprintf("%s/api/v1/scorm/sessions/%d/%d/%d/%s",
+		baseURL, lessonId, userId, expiresAt, utils.SignMediaURL(utils.MediaPurposeScorm, userId, lessonId, expiresAt))
+}
+
+// scormContentType ưu tiên MIME type theo extension (storage có thể trả application/octet-stream cho .js, .css)
+func scormContentType(name, storedType string) string {
+	if contentType := mime.TypeByExtension(path.Ext(name)); contentType != "" {
+		return contentType
+	}
+	if storedType != "" {
+		return storedType
+	}
+	return "application/octet-stream"
+}
+
+// scormPackageError map lỗi package sang lỗi 400, lỗi khác (storage) là 500
+func scormPackageError(err error) error {
+	if errors.Is(err, scorm.ErrInvalidPackage) || errors.Is(err, scorm.ErrUnsupportedVersion) || errors.Is(err, scorm.ErrPackageTooLarge) {
+		return utils.WrapError(err, "Invalid SCORM package", utils.ErrCodeBadRequest)
+	}
+	return utils.WrapError(err, "Failed to store SCORM package", utils.ErrCodeInternal)
+}
+
+// storeScormPackage mở package, giải nén toàn bộ file vào storage dưới prefix mới của lesson.
+// Lỗi giữa chừng thì xóa các file đã ghi.
+func storeScormPackage(ctx context.Context, backend storage.Backend, courseId, lessonId uint, r io.ReaderAt, size int64) (*models.ScormPackage, error) {
+	pkg, err := scorm.Open(r, size, scormPackageLimits())
+	if err != nil {
+		return nil, err
+	}
+
+	record := &models.ScormPackage{
+		LessonId:      lessonId,
+		CourseId:      courseId,
+		Version:       pkg.Manifest.Version,
+		Identifier:    pkg.Manifest.Identifier,
+		Title:         pkg.Manifest.Title,
+		LaunchPath:    pkg.Manifest.LaunchPath,
+		LaunchQuery:   pkg.Manifest.LaunchQuery,
+		MasteryScore:  pkg.Manifest.MasteryScore,
+		StoragePrefix: fmt.Sprintf("scorm/%d/%d/%s", courseId, lessonId, uuid.NewString()),
+	}
+
+	for _, name := range pkg.Files() {
+		if err := storeScormFile(ctx, backend, pkg, name, record.StoragePrefix+"/"+name, &record.TotalSize); err != nil {
+			deleteScormFiles(ctx, backend, record)
+			return nil, err
+		}
+		record.Files = append(record.Files, name)
+	}
+	record.FileCount = len(record.Files)
+
+	return record, nil
+}
+
+func storeScormFile(ctx context.Context, backend storage.Backend, pkg *scorm.Package, name, key string, totalSize *int64) error {
+	rc, size, err := pkg.OpenFile(name)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	if err := backend.Put(ctx, key, rc, size, scormContentType(name, "")); err != nil {
+		return fmt.Errorf("write %s: %w", key, err)
+	}
+	*totalSize += size
+	return nil
+}
+
+// copyScormPackage sao chép file của package sang prefix của lesson khác (clone course, duplicate lesson)
+func copyScormPackage(ctx context.Context, backend storage.Backend, source *models.ScormPackage, courseId, lessonId uint) (*models.ScormPackage, error) {
+	record := *source
+	record.Id = 0
+	record.LessonId = lessonId
+	record.CourseId = courseId
+	record.StoragePrefix = fmt.Sprintf("scorm/%d/%d/%s", courseId, lessonId, uuid.NewString())
+	record.Files = nil
+	record.CreatedAt = time.Time{}
+	record.UpdatedAt = time.Time{}
+
+	for _, name := range source.Files {
+		if err := copyStorageObject(ctx, backend, source.StoragePrefix+"/"+name, record.StoragePrefix+"/"+name); err != nil {
+			deleteScormFiles(ctx, backend, &record)
+			return nil, err
+		}
+		record.Files = append(record.Files, name)
+	}
+
+	return &record, nil
+}
+
+// releaseScormFiles xóa file của package đã bị thay/xóa, trừ khi revision của course còn dùng để rollback
+func (ss *scormService) releaseScormFiles(ctx context.Context, pkg *models.ScormPackage) {
+	if !isRetainedByRevision(ss.revisionRepo, pkg.CourseId, pkg.StoragePrefix) {
+		deleteScormFiles(ctx, ss.storage, pkg)
+	}
+}
+
+func deleteScormFiles(ctx context.Context, backend storage.Backend, pkg *models.ScormPackage) {
+	keys := make([]string, len(pkg.Files))
+	for i, name := range pkg.Files {
+		keys[i] = pkg.StoragePrefix + "/" + name
+	}
+	deleteStorageKeys(ctx, backend, keys)
+}
+
+func toScormPackageResponse(pkg *models.ScormPackage) *dto.ScormPackageResponse {
+	return &dto.ScormPackageResponse{
+		Id:           pkg.Id,
+		LessonId:     pkg.LessonId,
+		Version:      pkg.Version,
+		Identifier:   pkg.Identifier,
+		Title:        pkg.Title,
+		LaunchPath:   pkg.LaunchPath,
+		MasteryScore: pkg.MasteryScore,
+		FileCount:    pkg.FileCount,
+		TotalSize:    pkg.TotalSize,
+		CreatedAt:    pkg.CreatedAt,
+		UpdatedAt:    pkg.UpdatedAt,
+	}
+}
+
+// writeScormArchive nén lại các file của package trong storage thành ZIP (export course)
+func writeScormArchive(ctx context.Context, backend storage.Backend, pkg *models.ScormPackage, w io.Writer) error {
+	zw := zip.NewWriter(w)
+	for _, name := range pkg.Files {
+		if err := writeScormArchiveFile(ctx, backend, zw, pkg.StoragePrefix+"/"+name, name); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+func writeScormArchiveFile(ctx context.Context, backend storage.Backend, zw *zip.Writer, key, name string) error {
+	src, err := backend.Get(ctx, key)
+	if err != nil {
+		return fmt.Errorf("read %s: %w", key, err)
+	}
+	defer src.Close()
+
+	dst, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: time.Now()})
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(dst, src)
+	return err
+}
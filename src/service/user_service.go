@@ -1,9 +1,11 @@
 package service
 
 import (
-	"fmt"
+	"context"
 	"lms/src/dto"
+	"lms/src/imaging"
 	"lms/src/repository"
+	"lms/src/storage"
 	"lms/src/utils"
 	"mime/multipart"
 	"strings"
@@ -11,12 +13,16 @@ import (
 )
 
 type userService struct {
-	userRepo repository.UserRepository
+	userRepo        repository.UserRepository
+	certificateRepo repository.CertificateRepository
+	storage         storage.Backend
 }
 
-func NewUserService(userRepo repository.UserRepository) UserService {
+func NewUserService(userRepo repository.UserRepository, certificateRepo repository.CertificateRepository, fileStorage storage.Backend) UserService {
 	return &userService{
-		userRepo: userRepo,
+		userRepo:        userRepo,
+		certificateRepo: certificateRepo,
+		storage:         fileStorage,
 	}
 }
 
@@ -27,15 +33,17 @@ func (us *userService) GetProfile(userId uint) (*dto.UserProfile, error) {
 	}
 
 	return &dto.UserProfile{
-		Id:            user.Id,
-		Username:      user.Username,
-		Email:         user.Email,
-		FullName:      user.FullName,
-		Phone:         user.Phone,
-		Role:          user.Role,
-		Status:        user.Status,
-		EmailVerified: user.EmailVerified,
-		CreatedAt:     user.CreatedAt,
+		Id:             user.Id,
+		Username:       user.Username,
+		Email:          user.Email,
+		FullName:       user.FullName,
+		Phone:          user.Phone,
+		AvatarURL:      user.AvatarURL,
+		AvatarVariants: user.AvatarVariants,
+		Role:           user.Role,
+		Status:         user.Status,
+		EmailVerified:  user.EmailVerified,
+		CreatedAt:      user.CreatedAt,
 	}, nil
 }
 
@@ -84,7 +92,12 @@ func (us *userService) UpdateProfile(userId uint, req *dto.UpdateProfileRequest)
 		return nil, utils.WrapError(err, "Failed to get updated profile", utils.ErrCodeInternal)
 	}
 
-	// 5. Trả về response
+	// 5. Đổi họ tên thì cấp lại chứng chỉ với tên mới
+	if updatedUser.FullName != existingUser.FullName {
+		reissueCertificates(us.certificateRepo, userId, updatedUser.FullName)
+	}
+
+	// 6. Trả về response
 	return &dto.UpdateProfileResponse{
 		Id:            updatedUser.Id,
 		Username:      updatedUser.Username,
@@ -161,25 +174,28 @@ func (us *userService) UploadAvatar(userId uint, file *multipart.FileHeader) (*d
 		return nil, utils.WrapError(err, "Invalid avatar file", utils.ErrCodeBadRequest)
 	}
 
-	// 4. Validate và lưu file
-	uploadDir := "../../src/uploads/avatars"
-
-	fileName, err := utils.ValidateAndSaveFile(file, uploadDir)
+	// 4. Xử lý ảnh thành các variant (64/128/512px) và lưu lên storage
+	ctx := context.Background()
+	variants, err := storeImageVariants(ctx, us.storage, "avatars", file, imaging.AvatarSpecs)
 	if err != nil {
-		return nil, utils.WrapError(err, "Failed to upload avatar", utils.ErrCodeBadRequest)
+		return nil, err
 	}
 
-	// 5. Tạo URL cho avatar
-	baseURL := utils.GetEnv("BASE_URL", "http://localhost:8080")
-	avatarURL := fmt.Sprintf("%s/uploads/avatars/%s", baseURL, fileName)
+	// 5. avatar_url giữ variant lớn nhất để client cũ vẫn hoạt động
+	avatarURL := largestImageVariant(variants, imaging.AvatarSpecs)
 
-	// 6. Cập nhật avatar URL trong database
-	if err := us.userRepo.UpdateAvatar(userId, avatarURL); err != nil {
+	// 6. Cập nhật avatar trong database
+	if err := us.userRepo.UpdateAvatar(userId, avatarURL, variants); err != nil {
+		deleteImageVariants(ctx, us.storage, "", variants)
 		return nil, utils.WrapError(err, "Failed to update avatar URL", utils.ErrCodeInternal)
 	}
 
+	// 7. Xóa avatar cũ nếu do storage quản lý
+	deleteImageVariants(ctx, us.storage, existingUser.AvatarURL, existingUser.AvatarVariants)
+
 	return &dto.UploadAvatarResponse{
-		Message:   "Avatar uploaded successfully",
-		AvatarURL: avatarURL,
+		Message:        "Avatar uploaded successfully",
+		AvatarURL:      avatarURL,
+		AvatarVariants: variants,
 	}, nil
 }
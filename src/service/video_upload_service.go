@@ -0,0 +1,535 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"lms/src/dto"
+	"lms/src/models"
+	"lms/src/repository"
+	"lms/src/storage"
+	"lms/src/utils"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	videoUploadStatusUploading = "uploading"
+	videoUploadStatusCompleted = "completed"
+	videoUploadStatusFailed    = "failed"
+	videoUploadStatusAborted   = "aborted"
+	videoUploadStatusExpired   = "expired"
+)
+
+var allowedVideoExts = map[string]bool{
+	".mp4":  true,
+	".m4v":  true,
+	".mov":  true,
+	".webm": true,
+	".mkv":  true,
+}
+
+type videoUploadService struct {
+	uploadRepo     repository.VideoUploadRepository
+	instructorRepo repository.InstructorRepository
+	revisionRepo   repository.CourseRevisionRepository
+	storage        storage.Backend
+}
+
+func NewVideoUploadService(
+	uploadRepo repository.VideoUploadRepository,
+	instructorRepo repository.InstructorRepository,
+	revisionRepo repository.CourseRevisionRepository,
+	mediaStorage storage.Backend,
+) VideoUploadService {
+	return &videoUploadService{
+		uploadRepo:     uploadRepo,
+		instructorRepo: instructorRepo,
+		revisionRepo:   revisionRepo,
+		storage:        mediaStorage,
+	}
+}
+
+func (vs *videoUploadService) CreateUpload(instructorId, courseId, lessonId uint, req *dto.CreateVideoUploadRequest) (*dto.VideoUploadResponse, error) {
+	// 1. Kiểm tra quyền trên course và lesson
+	if _, err := vs.instructorRepo.FindCourseByIdAndInstructor(courseId, instructorId); err != nil {
+		return nil, utils.NewError("Course not found or you don't have permission", utils.ErrCodeNotFound)
+	}
+
+	if _, err := vs.instructorRepo.FindLessonByIdAndCourse(lessonId, courseId); err != nil {
+		return nil, utils.NewError("Lesson not found", utils.ErrCodeNotFound)
+	}
+
+	// Video gắn vào bản live sẽ bị snapshot ghi đè khi publish draft
+	draft, err := vs.revisionRepo.FindDraftByCourse(courseId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to check draft revision", utils.ErrCodeInternal)
+	}
+	if draft != nil {
+		return nil, utils.NewError("This course has an open draft revision. Edit the draft or discard it first", utils.ErrCodeConflict)
+	}
+
+	// 2. Validate file
+	fileName := filepath.Base(req.FileName)
+	ext := strings.ToLower(filepath.Ext(fileName))
+	if !allowedVideoExts[ext] {
+		return nil, utils.NewError("Unsupported video format. Allowed: mp4, m4v, mov, webm, mkv", utils.ErrCodeBadRequest)
+	}
+
+	if req.FileSize > maxVideoSize() {
+		return nil, utils.NewError(fmt.Sprintf("Video too large (max %dMB)", maxVideoSize()>>20), utils.ErrCodeBadRequest)
+	}
+
+	// 3. Lưu trạng thái upload (multipart upload trong storage được tạo khi nhận chunk đầu tiên)
+	upload := &models.VideoUpload{
+		Id:           uuid.NewString(),
+		LessonId:     lessonId,
+		CourseId:     courseId,
+		InstructorId: instructorId,
+		FileName:     fileName,
+		FileSize:     req.FileSize,
+		ContentType:  req.ContentType,
+		Checksum:     strings.ToLower(req.Checksum),
+		Status:       videoUploadStatusUploading,
+		ExpiresAt:    time.Now().Add(videoUploadExpiry()),
+	}
+
+	if err := vs.uploadRepo.CreateUpload(upload); err != nil {
+		return nil, utils.WrapError(err, "Failed to create upload", utils.ErrCodeInternal)
+	}
+
+	return toVideoUploadResponse(upload), nil
+}
+
+func (vs *videoUploadService) GetUpload(instructorId uint, uploadId string) (*dto.VideoUploadResponse, error) {
+	upload, err := vs.findInstructorUpload(instructorId, uploadId)
+	if err != nil {
+		return nil, err
+	}
+
+	return toVideoUploadResponse(upload), nil
+}
+
+func (vs *videoUploadService) GetLessonUploads(instructorId, courseId, lessonId uint) (*dto.GetLessonVideoUploadsResponse, error) {
+	if _, err := vs.instructorRepo.FindCourseByIdAndInstructor(courseId, instructorId); err != nil {
+		return nil, utils.NewError("Course not found or you don't have permission", utils.ErrCodeNotFound)
+	}
+
+	if _, err := vs.instructorRepo.FindLessonByIdAndCourse(lessonId, courseId); err != nil {
+		return nil, utils.NewError("Lesson not found", utils.ErrCodeNotFound)
+	}
+
+	uploads, err := vs.uploadRepo.GetLessonUploads(lessonId, 20)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get video uploads", utils.ErrCodeInternal)
+	}
+
+	items := make([]dto.VideoUploadResponse, len(uploads))
+	for i := range uploads {
+		items[i] = *toVideoUploadResponse(&uploads[i])
+	}
+
+	return &dto.GetLessonVideoUploadsResponse{
+		LessonId: lessonId,
+		Uploads:  items,
+		Total:    len(items),
+	}, nil
+}
+
+func (vs *videoUploadService) UploadChunk(instructorId uint, uploadId string, req *dto.UploadVideoChunkRequest, body io.Reader) (*dto.VideoUploadResponse, error) {
+	// 1. Đọc chunk và kiểm tra checksum trước khi khóa upload (client gửi chậm không giữ khóa)
+	chunk, err := io.ReadAll(body)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to read chunk (chunk too large?)", utils.ErrCodeBadRequest)
+	}
+	if len(chunk) == 0 {
+		return nil, utils.NewError("Chunk is empty", utils.ErrCodeBadRequest)
+	}
+
+	if req.Checksum != "" {
+		if err := verifyChunkChecksum(chunk, req.Checksum); err != nil {
+			return nil, err
+		}
+	}
+
+	var response *dto.VideoUploadResponse
+	err = vs.uploadRepo.Transaction(func(txRepo repository.VideoUploadRepository) error {
+		// 2. Khóa upload (SELECT ... FOR UPDATE), chunk gửi song song tới các replica được xử lý tuần tự
+		upload, err := lockInstructorUpload(txRepo, instructorId, uploadId)
+		if err != nil {
+			return err
+		}
+
+		if upload.Status != videoUploadStatusUploading {
+			return utils.NewError("Upload is not in progress (status: "+upload.Status+")", utils.ErrCodeConflict)
+		}
+
+		// 3. Offset phải khớp với số byte đã nhận, client dùng GET để lấy offset khi resume
+		if req.Offset != upload.UploadOffset {
+			return utils.NewError(fmt.Sprintf("Offset mismatch: expected %d", upload.UploadOffset), utils.ErrCodeConflict)
+		}
+
+		newOffset := upload.UploadOffset + int64(len(chunk))
+		if newOffset > upload.FileSize {
+			return utils.NewError("Chunk exceeds declared file size", utils.ErrCodeBadRequest)
+		}
+		// Part của multipart upload (trừ part cuối) phải đủ kích thước tối thiểu
+		if newOffset < upload.FileSize && int64(len(chunk)) < MinVideoChunkSize() {
+			return utils.NewError(fmt.Sprintf("Chunk too small (min %dMB except the last chunk)", MinVideoChunkSize()>>20), utils.ErrCodeBadRequest)
+		}
+
+		// 4. Chunk đầu tiên: kiểm tra nội dung là video rồi tạo multipart upload trong storage
+		ctx := context.Background()
+		if upload.UploadOffset == 0 {
+			if message := vs.startMultipartUpload(ctx, upload, chunk); message != "" {
+				vs.failUpload(ctx, txRepo, upload, message)
+				response = toVideoUploadResponse(upload)
+				return nil
+			}
+		}
+
+		// 5. Ghi chunk thành part tiếp theo và cộng dồn checksum toàn file
+		checksum, err := resumeChecksum(upload.ChecksumState)
+		if err != nil {
+			return utils.WrapError(err, "Failed to resume upload checksum", utils.ErrCodeInternal)
+		}
+		checksum.Write(chunk)
+
+		partNumber := len(upload.Parts) + 1
+		etag, err := vs.storage.UploadPart(ctx, videoUploadKey(upload), upload.MultipartUploadId, partNumber, bytes.NewReader(chunk), int64(len(chunk)))
+		if err != nil {
+			return utils.WrapError(err, "Failed to write chunk", utils.ErrCodeInternal)
+		}
+
+		state, err := checksum.(encoding.BinaryMarshaler).MarshalBinary()
+		if err != nil {
+			return utils.WrapError(err, "Failed to save upload checksum", utils.ErrCodeInternal)
+		}
+
+		upload.Parts = append(upload.Parts, models.VideoUploadPart{PartNumber: partNumber, ETag: etag})
+		upload.ChecksumState = state
+		upload.UploadOffset = newOffset
+		upload.ExpiresAt = time.Now().Add(videoUploadExpiry())
+		if err := txRepo.SaveUpload(upload); err != nil {
+			return utils.WrapError(err, "Failed to update upload offset", utils.ErrCodeInternal)
+		}
+
+		// 6. Nhận đủ file thì hoàn tất (vẫn giữ khóa để abort/cleanup không chen vào giữa)
+		if upload.UploadOffset == upload.FileSize {
+			vs.finalizeUpload(ctx, txRepo, upload, hex.EncodeToString(checksum.Sum(nil)))
+		}
+
+		response = toVideoUploadResponse(upload)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (vs *videoUploadService) AbortUpload(instructorId uint, uploadId string) (*dto.VideoUploadResponse, error) {
+	var response *dto.VideoUploadResponse
+	err := vs.uploadRepo.Transaction(func(txRepo repository.VideoUploadRepository) error {
+		upload, err := lockInstructorUpload(txRepo, instructorId, uploadId)
+		if err != nil {
+			return err
+		}
+
+		if upload.Status != videoUploadStatusUploading {
+			return utils.NewError("Only uploads in progress can be aborted", utils.ErrCodeConflict)
+		}
+
+		upload.Status = videoUploadStatusAborted
+		if err := txRepo.SaveUpload(upload); err != nil {
+			return utils.WrapError(err, "Failed to abort upload", utils.ErrCodeInternal)
+		}
+		vs.abortMultipartUpload(context.Background(), upload)
+
+		response = toVideoUploadResponse(upload)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// CleanupAbandonedUploads hủy multipart upload của các upload bỏ dở đã quá hạn (chạy định kỳ bởi scheduler)
+func (vs *videoUploadService) CleanupAbandonedUploads(ctx context.Context) error {
+	uploads, err := vs.uploadRepo.GetAbandonedUploads(time.Now(), 100)
+	if err != nil {
+		return err
+	}
+
+	cleaned := 0
+	for _, abandoned := range uploads {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		err := vs.uploadRepo.Transaction(func(txRepo repository.VideoUploadRepository) error {
+			// Khóa lại và kiểm tra lần nữa: chunk có thể vừa được nhận sau khi lấy danh sách
+			upload, err := txRepo.LockUpload(abandoned.Id)
+			if err != nil {
+				return err
+			}
+			if upload.Status != videoUploadStatusUploading || upload.ExpiresAt.After(time.Now()) {
+				return nil
+			}
+
+			upload.Status = videoUploadStatusExpired
+			if err := txRepo.SaveUpload(upload); err != nil {
+				return err
+			}
+			vs.abortMultipartUpload(ctx, upload)
+			cleaned++
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if cleaned > 0 {
+		log.Printf("🧹 Cleaned up %d abandoned video upload(s)", cleaned)
+	}
+
+	return nil
+}
+
+// startMultipartUpload kiểm tra chunk đầu tiên là video rồi tạo multipart upload,
+// trả về lý do thất bại (rỗng nếu thành công) để ghi vào upload
+func (vs *videoUploadService) startMultipartUpload(ctx context.Context, upload *models.VideoUpload, firstChunk []byte) string {
+	// Nội dung phải là video (.mov/.mkv có thể không được nhận diện nên dựa vào extension)
+	contentType := http.DetectContentType(firstChunk)
+	ext := strings.ToLower(filepath.Ext(upload.FileName))
+	if !strings.HasPrefix(contentType, "video/") && !(contentType == "application/octet-stream" && (ext == ".mov" || ext == ".mkv")) {
+		return "Uploaded file is not a video (" + contentType + ")"
+	}
+	if upload.ContentType == "" || !strings.HasPrefix(upload.ContentType, "video/") {
+		upload.ContentType = contentType
+	}
+
+	multipartUploadId, err := vs.storage.CreateMultipartUpload(ctx, videoUploadKey(upload), upload.ContentType)
+	if err != nil {
+		log.Printf("Failed to start multipart upload for video upload %s: %v", upload.Id, err)
+		return "Failed to store video"
+	}
+	upload.MultipartUploadId = multipartUploadId
+	upload.Parts = nil
+	upload.ChecksumState = nil
+	return ""
+}
+
+// finalizeUpload ghép các part trong storage, kiểm tra checksum, lấy thời lượng và gắn vào lesson.
+// Lỗi được ghi vào upload (status failed) thay vì trả về để client biết upload đã kết thúc.
+func (vs *videoUploadService) finalizeUpload(ctx context.Context, txRepo repository.VideoUploadRepository, upload *models.VideoUpload, checksum string) {
+	// 1. Kiểm tra checksum toàn bộ file (nếu client gửi lúc tạo upload)
+	if upload.Checksum != "" && upload.Checksum != checksum {
+		vs.failUpload(ctx, txRepo, upload, "Checksum mismatch, please upload the file again")
+		return
+	}
+
+	// 2. Ghép các part thành object video
+	storageKey := videoUploadKey(upload)
+	parts := make([]storage.CompletedPart, len(upload.Parts))
+	for i, part := range upload.Parts {
+		parts[i] = storage.CompletedPart{PartNumber: part.PartNumber, ETag: part.ETag}
+	}
+	if err := vs.storage.CompleteMultipartUpload(ctx, storageKey, upload.MultipartUploadId, parts); err != nil {
+		log.Printf("Failed to store video upload %s: %v", upload.Id, err)
+		vs.failUpload(ctx, txRepo, upload, "Failed to store video")
+		return
+	}
+
+	// 3. Lấy thời lượng bằng ffprobe nếu server có cài
+	duration, err := probeStoredVideoDuration(ctx, vs.storage, storageKey)
+	if err != nil && !errors.Is(err, utils.ErrFFprobeNotFound) {
+		log.Printf("ffprobe failed for video upload %s: %v", upload.Id, err)
+	}
+
+	// 4. Gắn video vào lesson
+	lessonUpdates := map[string]interface{}{"video_url": storageKey}
+	if duration > 0 {
+		lessonUpdates["video_duration"] = duration
+	}
+	if err := vs.instructorRepo.UpdateLesson(upload.LessonId, lessonUpdates); err != nil {
+		log.Printf("Failed to attach video upload %s to lesson %d: %v", upload.Id, upload.LessonId, err)
+		deleteStorageKeys(ctx, vs.storage, []string{storageKey})
+		vs.failUpload(ctx, txRepo, upload, "Failed to attach video to lesson")
+		return
+	}
+
+	// 5. Đánh dấu hoàn tất
+	now := time.Now()
+	upload.Status = videoUploadStatusCompleted
+	upload.StorageKey = storageKey
+	upload.VideoDuration = duration
+	upload.CompletedAt = &now
+	upload.ChecksumState = nil
+
+	if err := txRepo.SaveUpload(upload); err != nil {
+		log.Printf("Failed to mark video upload %s as completed: %v", upload.Id, err)
+	}
+}
+
+// failUpload ghi lỗi vào upload và hủy multipart upload (các part đã ghi bị xóa khỏi storage)
+func (vs *videoUploadService) failUpload(ctx context.Context, txRepo repository.VideoUploadRepository, upload *models.VideoUpload, message string) {
+	upload.Status = videoUploadStatusFailed
+	upload.ErrorMessage = message
+	if err := txRepo.SaveUpload(upload); err != nil {
+		log.Printf("Failed to mark video upload %s as failed: %v", upload.Id, err)
+	}
+	vs.abortMultipartUpload(ctx, upload)
+}
+
+func (vs *videoUploadService) abortMultipartUpload(ctx context.Context, upload *models.VideoUpload) {
+	if upload.MultipartUploadId == "" {
+		return
+	}
+	if err := vs.storage.AbortMultipartUpload(ctx, videoUploadKey(upload), upload.MultipartUploadId); err != nil {
+		log.Printf("Failed to abort multipart upload of video upload %s: %v", upload.Id, err)
+	}
+}
+
+func (vs *videoUploadService) findInstructorUpload(instructorId uint, uploadId string) (*models.VideoUpload, error) {
+	upload, err := vs.uploadRepo.FindById(uploadId)
+	if err != nil || upload.InstructorId != instructorId {
+		return nil, utils.NewError("Upload not found", utils.ErrCodeNotFound)
+	}
+	return upload, nil
+}
+
+func lockInstructorUpload(txRepo repository.VideoUploadRepository, instructorId uint, uploadId string) (*models.VideoUpload, error) {
+	upload, err := txRepo.LockUpload(uploadId)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && upload.InstructorId != instructorId) {
+		return nil, utils.NewError("Upload not found", utils.ErrCodeNotFound)
+	}
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get upload", utils.ErrCodeInternal)
+	}
+	return upload, nil
+}
+
+// verifyChunkChecksum kiểm tra header dạng tus "sha256 <base64>"
+func verifyChunkChecksum(chunk []byte, header string) error {
+	algorithm, encoded, found := strings.Cut(strings.TrimSpace(header), " ")
+	if !found || strings.ToLower(algorithm) != "sha256" {
+		return utils.NewError("Unsupported checksum algorithm, use \"sha256 <base64>\"", utils.ErrCodeBadRequest)
+	}
+
+	expected, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return utils.NewError("Invalid checksum encoding", utils.ErrCodeBadRequest)
+	}
+
+	actual := sha256.Sum256(chunk)
+	if string(actual[:]) != string(expected) {
+		// 460 Checksum Mismatch theo tus, ở đây dùng 400 để giữ format lỗi chung
+		return utils.NewError("Chunk checksum mismatch", utils.ErrCodeBadRequest)
+	}
+
+	return nil
+}
+
+// resumeChecksum khôi phục SHA-256 đã cộng dồn qua các chunk trước (state rỗng: bắt đầu từ đầu)
+func resumeChecksum(state []byte) (hash.Hash, error) {
+	checksum := sha256.New()
+	if len(state) > 0 {
+		if err := checksum.(encoding.BinaryUnmarshaler).UnmarshalBinary(state); err != nil {
+			return nil, err
+		}
+	}
+	return checksum, nil
+}
+
+// probeStoredVideoDuration chạy ffprobe trên video trong storage:
+// S3 qua signed URL (ffprobe đọc theo Range), local storage qua đường dẫn file
+func probeStoredVideoDuration(ctx context.Context, backend storage.Backend, key string) (int, error) {
+	signedURL, err := backend.SignedURL(ctx, key, 10*time.Minute)
+	if err == nil {
+		return utils.ProbeVideoDuration(signedURL)
+	}
+	if !errors.Is(err, storage.ErrSignedURLUnsupported) {
+		return 0, err
+	}
+
+	reader, err := backend.Get(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+	defer reader.Close()
+
+	file, ok := reader.(*os.File)
+	if !ok {
+		return 0, errors.New("storage provides neither a signed URL nor a local file")
+	}
+	return utils.ProbeVideoDuration(file.Name())
+}
+
+// videoUploadKey - key của video trong storage, các part được ghi vào key này qua multipart upload
+func videoUploadKey(upload *models.VideoUpload) string {
+	ext := strings.ToLower(filepath.Ext(upload.FileName))
+	return fmt.Sprintf("%s%d/%s%s", lessonVideoPrefix(upload.CourseId), upload.LessonId, upload.Id, ext)
+}
+
+func maxVideoSize() int64 {
+	return int64(utils.GetEnvInt("VIDEO_UPLOAD_MAX_SIZE_MB", 4096)) << 20
+}
+
+// MinVideoChunkSize - kích thước tối thiểu của một chunk (trừ chunk cuối), theo giới hạn part của multipart upload
+func MinVideoChunkSize() int64 {
+	return storage.MinPartSize
+}
+
+// MaxVideoChunkSize - kích thước tối đa của một chunk (handler dùng để giới hạn body)
+func MaxVideoChunkSize() int64 {
+	return max(int64(utils.GetEnvInt("VIDEO_UPLOAD_MAX_CHUNK_MB", 16))<<20, MinVideoChunkSize())
+}
+
+func videoUploadExpiry() time.Duration {
+	return time.Duration(utils.GetEnvInt("VIDEO_UPLOAD_EXPIRY_HOURS", 24)) * time.Hour
+}
+
+func toVideoUploadResponse(upload *models.VideoUpload) *dto.VideoUploadResponse {
+	progressPercent := 0.0
+	if upload.FileSize > 0 {
+		progressPercent = float64(upload.UploadOffset) / float64(upload.FileSize) * 100
+	}
+
+	return &dto.VideoUploadResponse{
+		UploadId:        upload.Id,
+		LessonId:        upload.LessonId,
+		CourseId:        upload.CourseId,
+		FileName:        upload.FileName,
+		FileSize:        upload.FileSize,
+		Offset:          upload.UploadOffset,
+		ProgressPercent: progressPercent,
+		Status:          upload.Status,
+		MinChunkSize:    MinVideoChunkSize(),
+		MaxChunkSize:    MaxVideoChunkSize(),
+		VideoURL:        upload.StorageKey,
+		VideoDuration:   upload.VideoDuration,
+		ErrorMessage:    upload.ErrorMessage,
+		ExpiresAt:       upload.ExpiresAt,
+		CompletedAt:     upload.CompletedAt,
+		CreatedAt:       upload.CreatedAt,
+	}
+}
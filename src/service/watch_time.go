@@ -0,0 +1,101 @@
+package service
+
+import (
+	"lms/src/models"
+	"lms/src/utils"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Heartbeat: player gửi định kỳ đoạn [from, to] vừa phát. Server chỉ ghi nhận phần hợp lý so với thời gian thực
+// trôi qua giữa hai heartbeat (nhân tốc độ phát tối đa) nên tua hoặc gửi số lớn không cộng được thời lượng xem.
+const (
+	watchHeartbeatTolerance = 2 // Giây, bù độ trễ mạng giữa hai heartbeat
+	retentionBucketCount    = 20
+)
+
+// watchMinPercent - % thời lượng video phải xem trước khi được hoàn thành lesson (0 = không giới hạn)
+func watchMinPercent() int {
+	percent := utils.GetEnvInt("LESSON_MIN_WATCH_PERCENT", 80)
+	return min(max(percent, 0), 100)
+}
+
+// watchHeartbeatMaxSeconds - số giây video tối đa được ghi nhận cho một heartbeat
+func watchHeartbeatMaxSeconds() int {
+	return max(utils.GetEnvInt("WATCH_HEARTBEAT_MAX_SECONDS", 60), 1)
+}
+
+// watchMaxPlaybackRate - tốc độ phát tối đa player cho phép (vd. 2 = x2)
+func watchMaxPlaybackRate() float64 {
+	rate, err := strconv.ParseFloat(utils.GetEnv("WATCH_MAX_PLAYBACK_RATE", "2"), 64)
+	if err != nil || rate < 1 {
+		return 2
+	}
+	return rate
+}
+
+// requiredWatchSeconds - số giây phải xem để hoàn thành lesson video, 0 nếu lesson không có video
+func requiredWatchSeconds(lesson *models.Lesson) int {
+	if lesson.LessonType != lessonTypeVideo || lesson.VideoDuration <= 0 {
+		return 0
+	}
+	return int(math.Ceil(float64(lesson.VideoDuration) * float64(watchMinPercent()) / 100))
+}
+
+// allowedWatchSpan - độ dài tối đa của đoạn được ghi nhận, dựa trên thời gian từ heartbeat trước
+func allowedWatchSpan(lastHeartbeatAt *time.Time, now time.Time) int {
+	maxSeconds := watchHeartbeatMaxSeconds()
+	if lastHeartbeatAt == nil {
+		return maxSeconds
+	}
+
+	elapsed := now.Sub(*lastHeartbeatAt).Seconds()
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	allowed := int(elapsed*watchMaxPlaybackRate()) + watchHeartbeatTolerance
+	return min(allowed, maxSeconds)
+}
+
+// mergeWatchIntervals thêm đoạn mới rồi gộp các đoạn chồng lấn hoặc liền kề, kết quả sắp xếp theo Start
+func mergeWatchIntervals(intervals []models.WatchInterval, added models.WatchInterval) []models.WatchInterval {
+	all := make([]models.WatchInterval, 0, len(intervals)+1)
+	all = append(all, intervals...)
+	if added.End > added.Start {
+		all = append(all, added)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Start < all[j].Start })
+
+	merged := make([]models.WatchInterval, 0, len(all))
+	for _, interval := range all {
+		if n := len(merged); n > 0 && interval.Start <= merged[n-1].End {
+			merged[n-1].End = max(merged[n-1].End, interval.End)
+			continue
+		}
+		merged = append(merged, interval)
+	}
+	return merged
+}
+
+// watchedSeconds - tổng số giây đã xem (các đoạn đã gộp nên không trùng nhau)
+func watchedSeconds(intervals []models.WatchInterval) int {
+	total := 0
+	for _, interval := range intervals {
+		total += interval.End - interval.Start
+	}
+	return total
+}
+
+// coveredSeconds - số giây của đoạn [start, end) nằm trong các đoạn đã xem
+func coveredSeconds(intervals []models.WatchInterval, start, end int) int {
+	covered := 0
+	for _, interval := range intervals {
+		if interval.Start >= end {
+			break
+		}
+		covered += max(0, min(interval.End, end)-max(interval.Start, start))
+	}
+	return covered
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"lms/src/models"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMergeWatchIntervals(t *testing.T) {
+	tests := []struct {
+		name      string
+		intervals []models.WatchInterval
+		added     models.WatchInterval
+		want      []models.WatchInterval
+	}{
+		{"first interval", nil, models.WatchInterval{Start: 10, End: 20}, []models.WatchInterval{{Start: 10, End: 20}}},
+		{"empty interval ignored", []models.WatchInterval{{Start: 0, End: 5}}, models.WatchInterval{Start: 8, End: 8}, []models.WatchInterval{{Start: 0, End: 5}}},
+		{"inverted interval ignored", nil, models.WatchInterval{Start: 9, End: 3}, []models.WatchInterval{}},
+		{"overlap", []models.WatchInterval{{Start: 0, End: 10}}, models.WatchInterval{Start: 5, End: 15}, []models.WatchInterval{{Start: 0, End: 15}}},
+		{"adjacent", []models.WatchInterval{{Start: 0, End: 10}}, models.WatchInterval{Start: 10, End: 12}, []models.WatchInterval{{Start: 0, End: 12}}},
+		{"contained", []models.WatchInterval{{Start: 0, End: 30}}, models.WatchInterval{Start: 5, End: 10}, []models.WatchInterval{{Start: 0, End: 30}}},
+		{"sorted gap", []models.WatchInterval{{Start: 40, End: 50}}, models.WatchInterval{Start: 0, End: 10}, []models.WatchInterval{{Start: 0, End: 10}, {Start: 40, End: 50}}},
+		{"bridges two", []models.WatchInterval{{Start: 0, End: 10}, {Start: 20, End: 30}}, models.WatchInterval{Start: 8, End: 22}, []models.WatchInterval{{Start: 0, End: 30}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mergeWatchIntervals(tt.intervals, tt.added)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWatchedAndCoveredSeconds(t *testing.T) {
+	intervals := []models.WatchInterval{{Start: 0, End: 10}, {Start: 20, End: 30}}
+
+	if got := watchedSeconds(intervals); got != 20 {
+		t.Errorf("watchedSeconds = %d, want 20", got)
+	}
+
+	tests := []struct {
+		start, end int
+		want       int
+	}{
+		{0, 10, 10},
+		{5, 25, 10},
+		{10, 20, 0},
+		{25, 100, 5},
+		{40, 50, 0},
+	}
+	for _, tt := range tests {
+		if got := coveredSeconds(intervals, tt.start, tt.end); got != tt.want {
+			t.Errorf("coveredSeconds(%d, %d) = %d, want %d", tt.start, tt.end, got, tt.want)
+		}
+	}
+}
+
+func TestAllowedWatchSpan(t *testing.T) {
+	t.Setenv("WATCH_HEARTBEAT_MAX_SECONDS", "60")
+	t.Setenv("WATCH_MAX_PLAYBACK_RATE", "2")
+
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time {
+		last := now.Add(-d)
+		return &last
+	}
+
+	tests := []struct {
+		name string
+		last *time.Time
+		want int
+	}{
+		{"first heartbeat", nil, 60},
+		{"immediately after", at(0), watchHeartbeatTolerance},
+		{"ten seconds at max rate", at(10 * time.Second), 20 + watchHeartbeatTolerance},
+		{"capped", at(time.Hour), 60},
+		{"clock went backwards", at(-time.Minute), watchHeartbeatTolerance},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := allowedWatchSpan(tt.last, now); got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
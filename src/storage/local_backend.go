@@ -0,0 +1,208 @@
+package storage
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LocalBackend lưu file trên disk dưới thư mục root, chỉ phù hợp khi chạy một instance
+type LocalBackend struct {
+	root string
+}
+
+func NewLocalBackend(root string) *LocalBackend {
+	return &LocalBackend{
+		root: root,
+	}
+}
+
+// Put ghi ra file tạm rồi rename để người đọc không thấy file ghi dở
+func (lb *LocalBackend) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	path := lb.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// Get trả về *os.File nên caller có thể dùng như io.ReadSeeker (HTTP Range)
+func (lb *LocalBackend) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	file, err := os.Open(lb.path(key))
+	if os.IsNotExist(err) {
+		return nil, ErrObjectNotFound
+	}
+	return file, err
+}
+
+func (lb *LocalBackend) Delete(ctx context.Context, key string) error {
+	err := os.Remove(lb.path(key))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+func (lb *LocalBackend) Stat(ctx context.Context, key string) (*ObjectInfo, error) {
+	info, err := os.Stat(lb.path(key))
+	if os.IsNotExist(err) || (err == nil && info.IsDir()) {
+		return nil, ErrObjectNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &ObjectInfo{
+		Key:          key,
+		Size:         info.Size(),
+		ContentType:  mime.TypeByExtension(filepath.Ext(key)),
+		LastModified: info.ModTime(),
+	}, nil
+}
+
+func (lb *LocalBackend) SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	return "", ErrSignedURLUnsupported
+}
+
+// CreateMultipartUpload tạo thư mục chứa các part dưới root/.multipart (ngoài các prefix được phục vụ public)
+func (lb *LocalBackend) CreateMultipartUpload(ctx context.Context, key, contentType string) (string, error) {
+	uploadId := uuid.NewString()
+	if err := os.MkdirAll(lb.multipartDir(uploadId), os.ModePerm); err != nil {
+		return "", err
+	}
+	return uploadId, nil
+}
+
+// UploadPart ghi part ra file riêng, gửi lại cùng partNumber thì ghi đè part cũ
+func (lb *LocalBackend) UploadPart(ctx context.Context, key, uploadId string, partNumber int, r io.Reader, size int64) (string, error) {
+	dir, err := lb.existingMultipartDir(uploadId)
+	if err != nil {
+		return "", err
+	}
+
+	tmp, err := os.CreateTemp(dir, ".part-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+
+	hash := md5.New()
+	if _, err := io.Copy(io.MultiWriter(tmp, hash), r); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+
+	if err := os.Rename(tmp.Name(), filepath.Join(dir, partFileName(partNumber))); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// CompleteMultipartUpload nối các part theo thứ tự vào file tạm rồi rename sang key
+func (lb *LocalBackend) CompleteMultipartUpload(ctx context.Context, key, uploadId string, parts []CompletedPart) error {
+	dir, err := lb.existingMultipartDir(uploadId)
+	if err != nil {
+		return err
+	}
+
+	path := lb.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	for _, part := range parts {
+		if err := appendPartFile(tmp, filepath.Join(dir, partFileName(part.PartNumber))); err != nil {
+			tmp.Close()
+			return fmt.Errorf("part %d: %w", part.PartNumber, err)
+		}
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+	return os.RemoveAll(dir)
+}
+
+func (lb *LocalBackend) AbortMultipartUpload(ctx context.Context, key, uploadId string) error {
+	dir, err := lb.existingMultipartDir(uploadId)
+	if errors.Is(err, ErrObjectNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(dir)
+}
+
+func (lb *LocalBackend) multipartDir(uploadId string) string {
+	return filepath.Join(lb.root, ".multipart", uploadId)
+}
+
+// existingMultipartDir - uploadId do CreateMultipartUpload cấp (uuid), giá trị khác không được dùng làm đường dẫn
+func (lb *LocalBackend) existingMultipartDir(uploadId string) (string, error) {
+	if _, err := uuid.Parse(uploadId); err != nil {
+		return "", ErrObjectNotFound
+	}
+
+	dir := lb.multipartDir(uploadId)
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return "", ErrObjectNotFound
+	}
+	return dir, nil
+}
+
+func partFileName(partNumber int) string {
+	return fmt.Sprintf("%05d.part", partNumber)
+}
+
+func appendPartFile(dst io.Writer, partPath string) error {
+	part, err := os.Open(partPath)
+	if err != nil {
+		return err
+	}
+	defer part.Close()
+
+	_, err = io.Copy(dst, part)
+	return err
+}
+
+// path Clean với "/" ở đầu để key không thoát được khỏi root
+func (lb *LocalBackend) path(key string) string {
+	return filepath.Join(lb.root, filepath.Clean("/"+key))
+}
@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocalBackendMultipartUpload(t *testing.T) {
+	root := t.TempDir()
+	backend := NewLocalBackend(root)
+	ctx := context.Background()
+	key := "videos/1/2/upload.mp4"
+
+	uploadId, err := backend.CreateMultipartUpload(ctx, key, "video/mp4")
+	if err != nil {
+		t.Fatalf("CreateMultipartUpload: %v", err)
+	}
+
+	// Part 2 gửi trước và part 1 gửi lại: kết quả vẫn theo thứ tự partNumber, part gửi sau ghi đè part cũ
+	uploads := []struct {
+		partNumber int
+		data       string
+	}{{2, "second"}, {1, "stale "}, {1, "first "}}
+	etags := make(map[int]string)
+	for _, upload := range uploads {
+		etag, err := backend.UploadPart(ctx, key, uploadId, upload.partNumber, strings.NewReader(upload.data), int64(len(upload.data)))
+		if err != nil {
+			t.Fatalf("UploadPart %d: %v", upload.partNumber, err)
+		}
+		etags[upload.partNumber] = etag
+	}
+
+	if _, err := backend.Stat(ctx, key); !errors.Is(err, ErrObjectNotFound) {
+		t.Errorf("Stat before complete = %v, want ErrObjectNotFound", err)
+	}
+
+	parts := []CompletedPart{{PartNumber: 1, ETag: etags[1]}, {PartNumber: 2, ETag: etags[2]}}
+	if err := backend.CompleteMultipartUpload(ctx, key, uploadId, parts); err != nil {
+		t.Fatalf("CompleteMultipartUpload: %v", err)
+	}
+
+	reader, err := backend.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	data, _ := io.ReadAll(reader)
+	reader.Close()
+	if string(data) != "first second" {
+		t.Errorf("Get = %q", data)
+	}
+
+	// Các part bị xóa sau khi hoàn tất
+	if _, err := os.Stat(filepath.Join(root, ".multipart", uploadId)); !os.IsNotExist(err) {
+		t.Errorf("multipart dir still exists: %v", err)
+	}
+	if err := backend.AbortMultipartUpload(ctx, key, uploadId); err != nil {
+		t.Errorf("AbortMultipartUpload after complete: %v", err)
+	}
+}
+
+func TestLocalBackendMultipartRejectsForeignUploadId(t *testing.T) {
+	backend := NewLocalBackend(t.TempDir())
+
+	for _, uploadId := range []string{"", "../../etc", "not-a-uuid"} {
+		if _, err := backend.UploadPart(context.Background(), "a.mp4", uploadId, 1, strings.NewReader("x"), 1); !errors.Is(err, ErrObjectNotFound) {
+			t.Errorf("UploadPart(%q) = %v, want ErrObjectNotFound", uploadId, err)
+		}
+	}
+}
@@ -0,0 +1,430 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	s3Algorithm       = "AWS4-HMAC-SHA256"
+	s3UnsignedPayload = "UNSIGNED-PAYLOAD"
+	s3MaxSignedExpiry = 7 * 24 * time.Hour
+)
+
+// S3Config cấu hình cho S3 hoặc dịch vụ tương thích S3 (MinIO, Cloudflare R2, DigitalOcean Spaces...)
+type S3Config struct {
+	Endpoint        string // vd: https://s3.amazonaws.com, http://localhost:9000
+	Region          string
+	Bucket          string
+	AccessKeyId     string
+	SecretAccessKey string
+	UsePathStyle    bool // endpoint/bucket/key thay vì bucket.endpoint/key (MinIO cần true)
+}
+
+// S3Backend gọi trực tiếp S3 REST API, ký request bằng AWS Signature Version 4
+type S3Backend struct {
+	config   S3Config
+	endpoint *url.URL
+	client   *http.Client
+}
+
+func NewS3Backend(config S3Config) (*S3Backend, error) {
+	if config.Bucket == "" || config.AccessKeyId == "" || config.SecretAccessKey == "" {
+		return nil, errors.New("s3 storage requires S3_BUCKET, S3_ACCESS_KEY_ID and S3_SECRET_ACCESS_KEY")
+	}
+
+	endpoint, err := url.Parse(strings.TrimSuffix(config.Endpoint, "/"))
+	if err != nil || endpoint.Scheme == "" || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid S3_ENDPOINT: %s", config.Endpoint)
+	}
+
+	return &S3Backend{
+		config:   config,
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: 30 * time.Minute}, // Upload video lớn có thể mất nhiều thời gian
+	}, nil
+}
+
+func (sb *S3Backend) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, sb.objectURL(key).String(), r)
+	if err != nil {
+		return err
+	}
+
+	// S3 yêu cầu Content-Length với PUT object
+	if size < 0 {
+		return errors.New("s3: object size is required")
+	}
+	req.ContentLength = size
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := sb.do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (sb *S3Backend) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, sb.objectURL(key).String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := sb.do(req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+func (sb *S3Backend) Delete(ctx context.Context, key string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, sb.objectURL(key).String(), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := sb.do(req)
+	if errors.Is(err, ErrObjectNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (sb *S3Backend) Stat(ctx context.Context, key string) (*ObjectInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, sb.objectURL(key).String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := sb.do(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	lastModified, _ := http.ParseTime(resp.Header.Get("Last-Modified"))
+
+	return &ObjectInfo{
+		Key:          key,
+		Size:         resp.ContentLength,
+		ContentType:  resp.Header.Get("Content-Type"),
+		LastModified: lastModified,
+	}, nil
+}
+
+// SignedURL tạo presigned GET URL, client tải trực tiếp từ S3 (hỗ trợ Range)
+func (sb *S3Backend) SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	return sb.presignedURL(key, expiry, time.Now().UTC()), nil
+}
+
+// CreateMultipartUpload - POST ?uploads, trả về UploadId do S3 cấp
+func (sb *S3Backend) CreateMultipartUpload(ctx context.Context, key, contentType string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sb.multipartURL(key, url.Values{"uploads": {""}}), nil)
+	if err != nil {
+		return "", err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := sb.do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		UploadId string `xml:"UploadId"`
+	}
+	if err := xml.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("s3: invalid CreateMultipartUpload response: %w", err)
+	}
+	if result.UploadId == "" {
+		return "", errors.New("s3: CreateMultipartUpload returned no UploadId")
+	}
+	return result.UploadId, nil
+}
+
+// UploadPart - PUT ?partNumber=&uploadId=, trả về ETag của part (cần khi hoàn tất)
+func (sb *S3Backend) UploadPart(ctx context.Context, key, uploadId string, partNumber int, r io.Reader, size int64) (string, error) {
+	query := url.Values{"partNumber": {strconv.Itoa(partNumber)}, "uploadId": {uploadId}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, sb.multipartURL(key, query), r)
+	if err != nil {
+		return "", err
+	}
+
+	if size < 0 {
+		return "", errors.New("s3: part size is required")
+	}
+	req.ContentLength = size
+
+	resp, err := sb.do(req)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+
+	etag := resp.Header.Get("ETag")
+	if etag == "" {
+		return "", errors.New("s3: UploadPart returned no ETag")
+	}
+	return etag, nil
+}
+
+// CompleteMultipartUpload - POST ?uploadId= với danh sách part theo thứ tự
+func (sb *S3Backend) CompleteMultipartUpload(ctx context.Context, key, uploadId string, parts []CompletedPart) error {
+	type xmlPart struct {
+		PartNumber int    `xml:"PartNumber"`
+		ETag       string `xml:"ETag"`
+	}
+	body := struct {
+		XMLName xml.Name  `xml:"CompleteMultipartUpload"`
+		Parts   []xmlPart `xml:"Part"`
+	}{}
+	for _, part := range parts {
+		body.Parts = append(body.Parts, xmlPart{PartNumber: part.PartNumber, ETag: part.ETag})
+	}
+
+	data, err := xml.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sb.multipartURL(key, url.Values{"uploadId": {uploadId}}), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/xml")
+
+	resp, err := sb.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// S3 có thể trả 200 nhưng body là <Error> khi lỗi xảy ra trong lúc ghép part
+	result, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if err != nil {
+		return err
+	}
+	if bytes.Contains(result, []byte("<Error>")) {
+		return fmt.Errorf("s3: CompleteMultipartUpload failed: %s", strings.TrimSpace(string(result)))
+	}
+	return nil
+}
+
+// AbortMultipartUpload - DELETE ?uploadId=, xóa các part đã upload (upload không còn tồn tại không phải lỗi)
+func (sb *S3Backend) AbortMultipartUpload(ctx context.Context, key, uploadId string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, sb.multipartURL(key, url.Values{"uploadId": {uploadId}}), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := sb.do(req)
+	if errors.Is(err, ErrObjectNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (sb *S3Backend) multipartURL(key string, query url.Values) string {
+	objectURL := sb.objectURL(key)
+	objectURL.RawQuery = canonicalQueryString(query)
+	return objectURL.String()
+}
+
+func (sb *S3Backend) presignedURL(key string, expiry time.Duration, now time.Time) string {
+	if expiry <= 0 || expiry > s3MaxSignedExpiry {
+		expiry = s3MaxSignedExpiry
+	}
+
+	objectURL := sb.objectURL(key)
+
+	query := objectURL.Query()
+	query.Set("X-Amz-Algorithm", s3Algorithm)
+	query.Set("X-Amz-Credential", sb.config.AccessKeyId+"/"+sb.credentialScope(now))
+	query.Set("X-Amz-Date", now.Format("20060102T150405Z"))
+	query.Set("X-Amz-Expires", strconv.Itoa(int(expiry.Seconds())))
+	query.Set("X-Amz-SignedHeaders", "host")
+	objectURL.RawQuery = canonicalQueryString(query)
+
+	headers := http.Header{}
+	headers.Set("Host", objectURL.Host)
+
+	signature := sb.signature(now, http.MethodGet, objectURL, headers, s3UnsignedPayload)
+	objectURL.RawQuery += "&X-Amz-Signature=" + signature
+
+	return objectURL.String()
+}
+
+// do ký request, gửi đi và chuyển status lỗi thành error
+func (sb *S3Backend) do(req *http.Request) (*http.Response, error) {
+	now := time.Now().UTC()
+
+	// Không ký body để có thể stream file lớn mà không phải đọc trước để tính hash
+	req.Header.Set("X-Amz-Content-Sha256", s3UnsignedPayload)
+	req.Header.Set("X-Amz-Date", now.Format("20060102T150405Z"))
+	req.Header.Set("Host", req.URL.Host)
+
+	signedHeaders, _ := canonicalHeaders(req.Header)
+	signature := sb.signature(now, req.Method, req.URL, req.Header, s3UnsignedPayload)
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3Algorithm, sb.config.AccessKeyId, sb.credentialScope(now), signedHeaders, signature,
+	))
+	req.Header.Del("Host") // net/http tự gửi Host từ req.Host/URL
+
+	resp, err := sb.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, ErrObjectNotFound
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		resp.Body.Close()
+		return nil, fmt.Errorf("s3: %s %s failed with status %d: %s", req.Method, req.URL.Path, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return resp, nil
+}
+
+func (sb *S3Backend) objectURL(key string) *url.URL {
+	objectURL := *sb.endpoint
+	key = strings.TrimPrefix(key, "/")
+
+	if sb.config.UsePathStyle {
+		objectURL.Path = strings.TrimSuffix(sb.endpoint.Path, "/") + "/" + sb.config.Bucket + "/" + key
+	} else {
+		objectURL.Host = sb.config.Bucket + "." + sb.endpoint.Host
+		objectURL.Path = strings.TrimSuffix(sb.endpoint.Path, "/") + "/" + key
+	}
+
+	// Gửi path đúng dạng đã encode khi ký (url.URL mặc định giữ nguyên một số ký tự như "+", "=")
+	objectURL.RawPath = uriEncode(objectURL.Path, false)
+
+	return &objectURL
+}
+
+func (sb *S3Backend) credentialScope(now time.Time) string {
+	return now.Format("20060102") + "/" + sb.config.Region + "/s3/aws4_request"
+}
+
+// signature tính chữ ký SigV4: canonical request -> string to sign -> HMAC với signing key
+func (sb *S3Backend) signature(now time.Time, method string, requestURL *url.URL, headers http.Header, payloadHash string) string {
+	signedHeaders, headerBlock := canonicalHeaders(headers)
+
+	canonicalRequest := strings.Join([]string{
+		method,
+		uriEncode(requestURL.Path, false),
+		canonicalQueryString(requestURL.Query()),
+		headerBlock,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	hashedRequest := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		s3Algorithm,
+		now.Format("20060102T150405Z"),
+		sb.credentialScope(now),
+		hex.EncodeToString(hashedRequest[:]),
+	}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+sb.config.SecretAccessKey), now.Format("20060102"))
+	signingKey = hmacSHA256(signingKey, sb.config.Region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+
+	return hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+}
+
+// canonicalHeaders ký Host, Content-Type và các header x-amz-*
+func canonicalHeaders(headers http.Header) (string, string) {
+	var names []string
+	for name := range headers {
+		lower := strings.ToLower(name)
+		if lower == "host" || lower == "content-type" || strings.HasPrefix(lower, "x-amz-") {
+			names = append(names, lower)
+		}
+	}
+	sort.Strings(names)
+
+	var block strings.Builder
+	for _, name := range names {
+		block.WriteString(name + ":" + strings.TrimSpace(headers.Get(name)) + "\n")
+	}
+
+	return strings.Join(names, ";"), block.String()
+}
+
+func canonicalQueryString(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var pairs []string
+	for _, key := range keys {
+		values := query[key]
+		sort.Strings(values)
+		for _, value := range values {
+			pairs = append(pairs, uriEncode(key, true)+"="+uriEncode(value, true))
+		}
+	}
+
+	return strings.Join(pairs, "&")
+}
+
+// uriEncode theo quy tắc của SigV4: chỉ giữ nguyên A-Z a-z 0-9 - _ . ~ (và "/" trong path)
+func uriEncode(value string, encodeSlash bool) string {
+	var encoded strings.Builder
+	for _, b := range []byte(value) {
+		switch {
+		case (b >= 'A' && b <= 'Z') || (b >= 'a' && b <= 'z') || (b >= '0' && b <= '9'),
+			b == '-', b == '_', b == '.', b == '~':
+			encoded.WriteByte(b)
+		case b == '/' && !encodeSlash:
+			encoded.WriteByte(b)
+		default:
+			encoded.WriteString(fmt.Sprintf("%%%02X", b))
+		}
+	}
+	return encoded.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
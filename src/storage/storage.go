@@ -0,0 +1,129 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"lms/src/utils"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrObjectNotFound được trả về khi key không tồn tại trong storage
+	ErrObjectNotFound = errors.New("storage: object not found")
+
+	// ErrSignedURLUnsupported - backend không tự cấp được signed URL (vd: local disk), caller tự phục vụ file qua app
+	ErrSignedURLUnsupported = errors.New("storage: signed URL not supported by this backend")
+)
+
+// ObjectInfo là metadata của một object trong storage
+type ObjectInfo struct {
+	Key          string
+	Size         int64
+	ContentType  string
+	LastModified time.Time
+}
+
+// PublicPrefixes là các prefix key được phục vụ công khai qua PublicURL (ảnh đã xử lý).
+// Video, resource, bài nộp... nằm ngoài các prefix này và chỉ tải được qua API có kiểm tra quyền
+var PublicPrefixes = []string{"avatars", "thumbnails", "content-images"}
+
+// MinPartSize là kích thước tối thiểu của mỗi part trong multipart upload (trừ part cuối), theo giới hạn của S3
+const MinPartSize = 5 << 20
+
+// CompletedPart là một part đã upload, cần khi hoàn tất multipart upload
+type CompletedPart struct {
+	PartNumber int    `json:"part_number"`
+	ETag       string `json:"etag"`
+}
+
+// Backend là nơi lưu file upload (avatar, video...), key là đường dẫn tương đối dạng "avatars/abc.png".
+// Dùng backend dùng chung (vd: S3) khi chạy nhiều replica.
+type Backend interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	Stat(ctx context.Context, key string) (*ObjectInfo, error)
+	SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error)
+
+	// Multipart upload: file lớn được ghi theo từng part (mỗi part có thể do replica khác nhận),
+	// object chỉ xuất hiện dưới key sau khi CompleteMultipartUpload
+	CreateMultipartUpload(ctx context.Context, key, contentType string) (string, error)
+	UploadPart(ctx context.Context, key, uploadId string, partNumber int, r io.Reader, size int64) (string, error)
+	CompleteMultipartUpload(ctx context.Context, key, uploadId string, parts []CompletedPart) error
+	AbortMultipartUpload(ctx context.Context, key, uploadId string) error
+}
+
+// NewBackendFromEnv tạo backend theo STORAGE_DRIVER (local | s3, mặc định: local)
+func NewBackendFromEnv() (Backend, error) {
+	driver := utils.GetEnv("STORAGE_DRIVER", "local")
+
+	switch driver {
+	case "local":
+		return NewLocalBackend(utils.GetEnv("STORAGE_LOCAL_DIR", "./uploads")), nil
+	case "s3":
+		return NewS3Backend(S3Config{
+			Endpoint:        utils.GetEnv("S3_ENDPOINT", "https://s3.amazonaws.com"),
+			Region:          utils.GetEnv("S3_REGION", "us-east-1"),
+			Bucket:          utils.GetEnv("S3_BUCKET", ""),
+			AccessKeyId:     utils.GetEnv("S3_ACCESS_KEY_ID", ""),
+			SecretAccessKey: utils.GetEnv("S3_SECRET_ACCESS_KEY", ""),
+			UsePathStyle:    utils.GetEnv("S3_USE_PATH_STYLE", "true") == "true",
+		})
+	default:
+		return nil, fmt.Errorf("unsupported storage driver: %s", driver)
+	}
+}
+
+// PublicURL trả về URL public của key (avatar...), base URL cấu hình qua STORAGE_PUBLIC_BASE_URL
+// (local: {BASE_URL}/uploads, S3: URL của bucket hoặc CDN)
+func PublicURL(key string) string {
+	return publicBaseURL() + "/" + strings.TrimPrefix(key, "/")
+}
+
+// KeyFromPublicURL là hàm ngược của PublicURL, trả về false nếu URL không do storage cấp
+// hoặc key không nằm trong PublicPrefixes. Path được giải mã trước khi kiểm tra vì trình duyệt coi "%2e%2e" là "..".
+func KeyFromPublicURL(rawURL string) (string, bool) {
+	prefix := publicBaseURL() + "/"
+	if !strings.HasPrefix(rawURL, prefix) {
+		return "", false
+	}
+
+	key := strings.TrimPrefix(rawURL, prefix)
+	if i := strings.IndexAny(key, "?#"); i >= 0 {
+		key = key[:i]
+	}
+	key, err := url.PathUnescape(key)
+	if err != nil || strings.Contains(key, `\`) || !IsPublicKey(key) {
+		return "", false
+	}
+	return key, true
+}
+
+// IsPublicKey kiểm tra key nằm trong một prefix của PublicPrefixes.
+// Key phải đã ở dạng chuẩn (không có "..", "." hay "//"), dạng khác có thể trỏ ra ngoài prefix khi trình duyệt chuẩn hóa URL.
+func IsPublicKey(key string) bool {
+	if key == "" || path.Clean("/"+key) != "/"+key {
+		return false
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == ".." || part == "." {
+			return false
+		}
+	}
+	for _, prefix := range PublicPrefixes {
+		if strings.HasPrefix(key, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func publicBaseURL() string {
+	baseURL := utils.GetEnv("BASE_URL", "http://localhost:8080")
+	return strings.TrimSuffix(utils.GetEnv("STORAGE_PUBLIC_BASE_URL", baseURL+"/uploads"), "/")
+}
@@ -0,0 +1,219 @@
+package subtitle
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	ErrInvalidEncoding = errors.New("subtitle file must be UTF-8 encoded")
+	ErrInvalidHeader   = errors.New("WebVTT file must start with WEBVTT")
+	ErrNoCues          = errors.New("subtitle file has no cues")
+	ErrTooManyCues     = errors.New("subtitle file has too many cues")
+)
+
+// MaxCues giới hạn số cue của một track (video 10 tiếng ~ 10.000 cue)
+const MaxCues = 20000
+
+// maxTimestampHours là số giờ lớn nhất chấp nhận trong timestamp của cue
+const maxTimestampHours = 999
+
+// Format của file nguồn
+const (
+	FormatSRT = "srt"
+	FormatVTT = "vtt"
+)
+
+type Cue struct {
+	Start time.Duration
+	End   time.Duration
+	Text  string // Giữ nguyên các thẻ định dạng (<i>, <b>...) để player hiển thị
+}
+
+// PlainText bỏ các thẻ định dạng, dùng cho transcript và tìm kiếm
+func (c Cue) PlainText() string {
+	text := formattingTagPattern.ReplaceAllString(c.Text, "")
+	text = html.UnescapeString(text)
+	return strings.Join(strings.Fields(text), " ")
+}
+
+var (
+	// <i>, </b>, <v Speaker>, <00:01.000>, <c.yellow>...
+	formattingTagPattern = regexp.MustCompile(`<[^>\n]*>`)
+	// Tag kiểu ASS "{\an8}" thường gặp trong SRT, WebVTT không hỗ trợ nên bị bỏ khi chuyển đổi
+	assTagPattern = regexp.MustCompile(`\{\\[^}\n]*\}`)
+	timingPattern = regexp.MustCompile(`^\s*((?:\d+:)?\d{1,2}:\d{2}[.,]\d{1,3})\s*-->\s*((?:\d+:)?\d{1,2}:\d{2}[.,]\d{1,3})`)
+)
+
+// Parse đọc file SRT hoặc WebVTT thành danh sách cue
+func Parse(data []byte, format string) ([]Cue, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	if !utf8.Valid(data) {
+		return nil, ErrInvalidEncoding
+	}
+
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+	blocks := splitBlocks(text)
+
+	if format == FormatVTT {
+		if len(blocks) == 0 || !isVTTHeader(blocks[0][0]) {
+			return nil, ErrInvalidHeader
+		}
+		blocks = blocks[1:]
+	}
+
+	var cues []Cue
+	for _, block := range blocks {
+		// WebVTT: bỏ qua comment, style và region
+		if format == FormatVTT && (strings.HasPrefix(block[0], "NOTE") || block[0] == "STYLE" || block[0] == "REGION") {
+			continue
+		}
+
+		cue, ok, err := parseCueBlock(block)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+
+		cues = append(cues, cue)
+		if len(cues) > MaxCues {
+			return nil, ErrTooManyCues
+		}
+	}
+
+	if len(cues) == 0 {
+		return nil, ErrNoCues
+	}
+	return cues, nil
+}
+
+// EncodeVTT ghi danh sách cue thành file WebVTT (định dạng <track> của trình duyệt hỗ trợ)
+func EncodeVTT(cues []Cue) []byte {
+	var b bytes.Buffer
+	b.WriteString("WEBVTT\n")
+	for i, cue := range cues {
+		fmt.Fprintf(&b, "\n%d\n%s --> %s\n%s\n", i+1, FormatTimestamp(cue.Start), FormatTimestamp(cue.End), cue.Text)
+	}
+	return b.Bytes()
+}
+
+// FormatTimestamp định dạng thời gian theo WebVTT: hh:mm:ss.mmm
+func FormatTimestamp(d time.Duration) string {
+	ms := d.Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", ms/3600000, ms/60000%60, ms/1000%60, ms%1000)
+}
+
+func parseCueBlock(block []string) (Cue, bool, error) {
+	// Dòng đầu có thể là số thứ tự (SRT) hoặc cue identifier (WebVTT)
+	timingLine := 0
+	if !strings.Contains(block[0], "-->") {
+		timingLine = 1
+	}
+	if timingLine >= len(block) {
+		return Cue{}, false, nil
+	}
+
+	match := timingPattern.FindStringSubmatch(block[timingLine])
+	if match == nil {
+		return Cue{}, false, fmt.Errorf("invalid cue timing: %q", block[timingLine])
+	}
+
+	start, err := parseTimestamp(match[1])
+	if err != nil {
+		return Cue{}, false, err
+	}
+	end, err := parseTimestamp(match[2])
+	if err != nil {
+		return Cue{}, false, err
+	}
+	if end <= start {
+		return Cue{}, false, fmt.Errorf("cue end time must be after start time: %q", block[timingLine])
+	}
+
+	// "-->" hoặc dòng trống trong nội dung sẽ làm hỏng file WebVTT khi ghi lại
+	lines := make([]string, 0, len(block)-timingLine-1)
+	for _, line := range block[timingLine+1:] {
+		line = assTagPattern.ReplaceAllString(line, "")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		lines = append(lines, strings.ReplaceAll(line, "-->", "->"))
+	}
+
+	text := strings.Join(lines, "\n")
+	if strings.TrimSpace(text) == "" {
+		return Cue{}, false, nil
+	}
+
+	return Cue{Start: start, End: end, Text: text}, true, nil
+}
+
+// parseTimestamp đọc "hh:mm:ss,mmm" (SRT) hoặc "hh:mm:ss.mmm" / "mm:ss.mmm" (WebVTT)
+func parseTimestamp(value string) (time.Duration, error) {
+	value = strings.Replace(value, ",", ".", 1)
+	dot := strings.IndexByte(value, '.')
+	clock, fraction := value[:dot], value[dot+1:]
+
+	// Chuẩn hóa phần mili giây về 3 chữ số ("5" -> 500ms)
+	for len(fraction) < 3 {
+		fraction += "0"
+	}
+	ms, err := strconv.Atoi(fraction)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timestamp: %q", value)
+	}
+
+	parts := strings.Split(clock, ":")
+	if len(parts) == 2 {
+		parts = append([]string{"0"}, parts...)
+	}
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("invalid timestamp: %q", value)
+	}
+	hours, hoursErr := strconv.Atoi(parts[0])
+	minutes, minutesErr := strconv.Atoi(parts[1])
+	seconds, secondsErr := strconv.Atoi(parts[2])
+	// Giới hạn số giờ để time.Duration không bị tràn thành giá trị âm
+	if hoursErr != nil || minutesErr != nil || secondsErr != nil || hours > maxTimestampHours || minutes > 59 || seconds > 59 {
+		return 0, fmt.Errorf("invalid timestamp: %q", value)
+	}
+
+	return time.Duration(hours)*time.Hour +
+		time.Duration(minutes)*time.Minute +
+		time.Duration(seconds)*time.Second +
+		time.Duration(ms)*time.Millisecond, nil
+}
+
+// splitBlocks tách nội dung thành các block ngăn cách bởi dòng trống
+func splitBlocks(text string) [][]string {
+	var blocks [][]string
+	var current []string
+	for _, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) == "" {
+			if len(current) > 0 {
+				blocks = append(blocks, current)
+				current = nil
+			}
+			continue
+		}
+		current = append(current, strings.TrimRight(line, " \t"))
+	}
+	if len(current) > 0 {
+		blocks = append(blocks, current)
+	}
+	return blocks
+}
+
+func isVTTHeader(line string) bool {
+	return line == "WEBVTT" || strings.HasPrefix(line, "WEBVTT ") || strings.HasPrefix(line, "WEBVTT\t")
+}
@@ -0,0 +1,127 @@
+package subtitle
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	ms := time.Millisecond
+	tests := []struct {
+		name   string
+		data   string
+		format string
+		want   []Cue
+	}{
+		{
+			name:   "srt with BOM and CRLF",
+			data:   "\xef\xbb\xbf1\r\n00:00:01,000 --> 00:00:02,500\r\nXin chào\r\n\r\n2\r\n00:00:03,000 --> 00:00:04,000\r\n<i>Bài 1</i>\r\ndòng 2\r\n",
+			format: FormatSRT,
+			want: []Cue{
+				{Start: 1000 * ms, End: 2500 * ms, Text: "Xin chào"},
+				{Start: 3000 * ms, End: 4000 * ms, Text: "<i>Bài 1</i>\ndòng 2"},
+			},
+		},
+		{
+			name:   "vtt skips notes and styles",
+			data:   "WEBVTT - lesson\n\nNOTE comment\n\nSTYLE\n::cue { color: red }\n\nintro\n01:02.5 --> 01:03.25 align:start\nHello\n\n1:00:00.000 --> 1:00:01.000\nEnd",
+			format: FormatVTT,
+			want: []Cue{
+				{Start: 62500 * ms, End: 63250 * ms, Text: "Hello"},
+				{Start: time.Hour, End: time.Hour + time.Second, Text: "End"},
+			},
+		},
+		{
+			name:   "ass tags, arrows and empty cues",
+			data:   "1\n00:00:01,000 --> 00:00:02,000\n{\\an8}Top --> text\n\n2\n00:00:03,000 --> 00:00:04,000\n{\\an8}\n\n3\n",
+			format: FormatSRT,
+			want:   []Cue{{Start: time.Second, End: 2 * time.Second, Text: "Top -> text"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse([]byte(tt.data), tt.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRejectsMalformedInput(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		format  string
+		wantErr error
+	}{
+		{"invalid utf-8", "1\n00:00:01,000 --> 00:00:02,000\nXin ch\xe0o", FormatSRT, ErrInvalidEncoding},
+		{"missing vtt header", "00:01.000 --> 00:02.000\nHello", FormatVTT, ErrInvalidHeader},
+		{"empty vtt", "", FormatVTT, ErrInvalidHeader},
+		{"header only", "WEBVTT\n\nNOTE nothing here", FormatVTT, ErrNoCues},
+		{"empty srt", "\n\n", FormatSRT, ErrNoCues},
+		{"binary file", "\x00\x01\x02", FormatSRT, nil},
+		{"end before start", "1\n00:00:05,000 --> 00:00:04,000\nx", FormatSRT, nil},
+		{"minutes out of range", "1\n00:61:00,000 --> 00:62:00,000\nx", FormatSRT, nil},
+		{"garbage timing", "1\nnot a timing\nx", FormatSRT, nil},
+		{"hours overflow", "1\n99999999999999999999:00:00,000 --> 99999999999999999999:00:01,000\nx", FormatSRT, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cues, err := Parse([]byte(tt.data), tt.format)
+			if err == nil {
+				t.Fatalf("expected error, got %+v", cues)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("error %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseTooManyCues(t *testing.T) {
+	var b strings.Builder
+	for i := 0; i <= MaxCues; i++ {
+		start := time.Duration(i) * time.Second
+		b.WriteString(FormatTimestamp(start) + " --> " + FormatTimestamp(start+time.Second) + "\nx\n\n")
+	}
+
+	if _, err := Parse([]byte(b.String()), FormatSRT); !errors.Is(err, ErrTooManyCues) {
+		t.Errorf("error %v, want %v", err, ErrTooManyCues)
+	}
+}
+
+func TestEncodeVTTRoundTrip(t *testing.T) {
+	cues := []Cue{
+		{Start: 1500 * time.Millisecond, End: 3 * time.Second, Text: "<b>Một</b>"},
+		{Start: 2*time.Hour + 5*time.Millisecond, End: 2*time.Hour + time.Second, Text: "Hai\nba"},
+	}
+
+	encoded := EncodeVTT(cues)
+	if !strings.HasPrefix(string(encoded), "WEBVTT\n\n1\n00:00:01.500 --> 00:00:03.000\n") {
+		t.Errorf("unexpected output %q", encoded)
+	}
+
+	parsed, err := Parse(encoded, FormatVTT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(parsed, cues) {
+		t.Errorf("got %+v, want %+v", parsed, cues)
+	}
+}
+
+func TestCuePlainText(t *testing.T) {
+	cue := Cue{Text: "<v Lan><i>Xin</i>   chào &amp; <00:01.000>tạm\nbiệt</v>"}
+	if got := cue.PlainText(); got != "Xin chào & tạm biệt" {
+		t.Errorf("got %q", got)
+	}
+}
@@ -6,88 +6,201 @@ import (
 	"io"
 	"mime/multipart"
 	"net/http"
-	"os"
 	"path/filepath"
 	"strings"
-
-	"github.com/google/uuid"
 )
 
-var allowExts = map[string]bool{
-	".jpg":  true,
-	".jpeg": true,
-	".png":  true,
+// FileRules mô tả các điều kiện một file upload phải thỏa mãn
+type FileRules struct {
+	AllowedExts      map[string]bool
+	AllowedMimeTypes map[string]bool // So khớp MIME type đã bỏ tham số (vd: "text/plain")
+	MaxSize          int64
+}
+
+// ImageFileRules dùng cho ảnh (avatar, thumbnail...), ảnh động bị chặn khi xử lý bởi package imaging
+var ImageFileRules = FileRules{
+	AllowedExts: map[string]bool{
+		".jpg":  true,
+		".jpeg": true,
+		".png":  true,
+		".gif":  true,
+	},
+	AllowedMimeTypes: map[string]bool{
+		"image/jpeg": true,
+		"image/png":  true,
+		"image/gif":  true,
+	},
+	MaxSize: 5 << 20,
+}
+
+// AssignmentFileRules dùng cho file bài nộp assignment (tài liệu, source code, archive, ảnh chụp)
+var AssignmentFileRules = FileRules{
+	AllowedExts: map[string]bool{
+		".pdf":  true,
+		".zip":  true,
+		".txt":  true,
+		".md":   true,
+		".docx": true,
+		".png":  true,
+		".jpg":  true,
+		".jpeg": true,
+		".go":   true,
+		".py":   true,
+		".js":   true,
+		".ts":   true,
+		".java": true,
+		".c":    true,
+		".cpp":  true,
+		".h":    true,
+		".json": true,
+		".sql":  true,
+	},
+	AllowedMimeTypes: map[string]bool{
+		"application/pdf": true,
+		"application/zip": true, // zip và docx
+		"text/plain":      true, // source code và text
+		"image/jpeg":      true,
+		"image/png":       true,
+	},
+	MaxSize: 20 << 20,
+}
+
+// SubtitleFileRules dùng cho phụ đề SRT/WebVTT (file text UTF-8)
+var SubtitleFileRules = FileRules{
+	AllowedExts: map[string]bool{
+		".srt": true,
+		".vtt": true,
+	},
+	AllowedMimeTypes: map[string]bool{
+		"text/plain": true,
+	},
+	MaxSize: 5 << 20,
+}
+
+// ResourceFileRules dùng cho tài liệu đính kèm lesson (slides, PDF, code ZIP, dataset)
+// Có thể cấu hình qua env: RESOURCE_ALLOWED_EXTENSIONS, RESOURCE_ALLOWED_MIME_TYPES (phân tách bằng dấu phẩy), RESOURCE_MAX_SIZE_MB
+func ResourceFileRules() FileRules {
+	return FileRules{
+		AllowedExts: parseListEnv(
+			"RESOURCE_ALLOWED_EXTENSIONS",
+			".pdf,.zip,.gz,.pptx,.docx,.xlsx,.csv,.txt,.md,.json,.ipynb,.png,.jpg,.jpeg",
+		),
+		// pptx, docx, xlsx đều là định dạng zip nên được nhận diện là application/zip
+		AllowedMimeTypes: parseListEnv(
+			"RESOURCE_ALLOWED_MIME_TYPES",
+			"application/pdf,application/zip,application/x-gzip,text/plain,image/png,image/jpeg",
+		),
+		MaxSize: int64(GetEnvInt("RESOURCE_MAX_SIZE_MB", 100)) << 20,
+	}
+}
+
+// CoursePackageFileRules dùng cho package import course (ZIP chứa manifest và file đính kèm)
+// Có thể cấu hình qua env: COURSE_PACKAGE_MAX_SIZE_MB
+func CoursePackageFileRules() FileRules {
+	return FileRules{
+		AllowedExts:      map[string]bool{".zip": true},
+		AllowedMimeTypes: map[string]bool{"application/zip": true},
+		MaxSize:          int64(GetEnvInt("COURSE_PACKAGE_MAX_SIZE_MB", 1024)) << 20,
+	}
 }
-var allowMimeTypes = map[string]bool{
-	"image/jpeg": true,
-	"image/png":  true,
+
+// ScormPackageFileRules dùng cho package SCORM 1.2 upload vào lesson dạng scorm
+// Có thể cấu hình qua env: SCORM_PACKAGE_MAX_SIZE_MB
+func ScormPackageFileRules() FileRules {
+	return FileRules{
+		AllowedExts:      map[string]bool{".zip": true},
+		AllowedMimeTypes: map[string]bool{"application/zip": true},
+		MaxSize:          int64(GetEnvInt("SCORM_PACKAGE_MAX_SIZE_MB", 500)) << 20,
+	}
 }
 
-const maxSize = 5 << 20
+func parseListEnv(key, defaultValue string) map[string]bool {
+	values := make(map[string]bool)
+	for _, value := range strings.Split(GetEnv(key, defaultValue), ",") {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if value != "" {
+			values[value] = true
+		}
+	}
+	return values
+}
 
-func ValidateAndSaveFile(fileHeader *multipart.FileHeader, uploadDir string) (string, error) {
+// ValidateFile kiểm tra extension, kích thước và MIME type, trả về extension (lowercase) và MIME type
+func ValidateFile(fileHeader *multipart.FileHeader, rules FileRules) (string, string, error) {
 	// Check extension in filename
 	ext := strings.ToLower(filepath.Ext(fileHeader.Filename))
-	if !allowExts[ext] {
-		return "", errors.New("unsupported file extension")
+	if !rules.AllowedExts[ext] {
+		return "", "", errors.New("unsupported file extension")
 	}
 
 	// Check size
-	if fileHeader.Size > maxSize {
-		return "", errors.New("file too large (max 5MB)")
+	if fileHeader.Size > rules.MaxSize {
+		return "", "", fmt.Errorf("file too large (max %dMB)", rules.MaxSize>>20)
 	}
 
 	// Check file type
-	file, err := fileHeader.Open()
+	mimeType, err := DetectFileMimeType(fileHeader)
 	if err != nil {
-		return "", errors.New("cannot open file")
+		return "", "", err
 	}
-	defer file.Close()
 
-	buffer := make([]byte, 512)
-	_, err = file.Read(buffer)
-	if err != nil {
-		return "", errors.New("cannot read file")
+	if !rules.AllowedMimeTypes[mimeType] {
+		return "", "", fmt.Errorf("invalid MIME type: %s", mimeType)
 	}
 
-	mimeType := http.DetectContentType(buffer)
-	if !allowMimeTypes[mimeType] {
-		return "", fmt.Errorf("invalid MIME type: %s", mimeType)
+	return ext, mimeType, nil
+}
+
+// ValidateFileContent giống ValidateFile nhưng cho file không đến từ multipart (vd: file trong ZIP),
+// head là các byte đầu của file (tối đa 512 bytes) dùng để nhận diện MIME type
+func ValidateFileContent(filename string, size int64, head []byte, rules FileRules) (string, string, error) {
+	ext := strings.ToLower(filepath.Ext(filename))
+	if !rules.AllowedExts[ext] {
+		return "", "", errors.New("unsupported file extension")
 	}
 
-	// Change file name
-	fileName := fmt.Sprintf("%s%s", uuid.New().String(), ext)
+	if size > rules.MaxSize {
+		return "", "", fmt.Errorf("file too large (max %dMB)", rules.MaxSize>>20)
+	}
 
-	// Create folder if not exist
-	if err := os.MkdirAll(uploadDir, os.ModePerm); err != nil {
-		return "", errors.New("cannot create upload folder")
+	if len(head) == 0 {
+		return "", "", errors.New("file is empty")
 	}
 
-	// uploadDir "./upload" + filename "abc.jpg"
-	savePath := filepath.Join(uploadDir, fileName)
-	if err := saveFile(fileHeader, savePath); err != nil {
-		return "", err
+	mimeType := detectMimeType(head)
+	if !rules.AllowedMimeTypes[mimeType] {
+		return "", "", fmt.Errorf("invalid MIME type: %s", mimeType)
 	}
 
-	return fileName, nil
+	return ext, mimeType, nil
 }
 
-func saveFile(fileHeader *multipart.FileHeader, destination string) error {
-	src, err := fileHeader.Open()
+// DetectFileMimeType đọc 512 bytes đầu của file và trả về MIME type (không kèm tham số charset)
+func DetectFileMimeType(fileHeader *multipart.FileHeader) (string, error) {
+	file, err := fileHeader.Open()
 	if err != nil {
-		return err
+		return "", errors.New("cannot open file")
 	}
-	defer src.Close()
+	defer file.Close()
 
-	out, err := os.Create(destination)
-	if err != nil {
-		return err
+	buffer := make([]byte, 512)
+	n, err := file.Read(buffer)
+	if err != nil && err != io.EOF {
+		return "", errors.New("cannot read file")
+	}
+	if n == 0 {
+		return "", errors.New("file is empty")
 	}
-	defer out.Close()
 
-	_, err = io.Copy(out, src)
+	return detectMimeType(buffer[:n]), nil
+}
 
-	return err
+func detectMimeType(head []byte) string {
+	mimeType := http.DetectContentType(head)
+	if idx := strings.Index(mimeType, ";"); idx != -1 {
+		mimeType = strings.TrimSpace(mimeType[:idx])
+	}
+	return mimeType
 }
 
 func ValidateAvatarFile(fileHeader *multipart.FileHeader) error {
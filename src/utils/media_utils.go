@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MediaSigningSecret dùng để ký URL media, mặc định dùng chung JWT secret
+var MediaSigningSecret = []byte(GetEnv("MEDIA_SIGNING_SECRET", string(JWTSecret)))
+
+// MediaURLTTL - thời gian sống của signed URL (phút)
+func MediaURLTTL() time.Duration {
+	return time.Duration(GetEnvInt("MEDIA_URL_TTL_MINUTES", 60)) * time.Minute
+}
+
+// ScormSessionTTL - thời gian sống của URL phiên học SCORM (giờ), đủ dài để học viên commit dữ liệu cuối phiên
+func ScormSessionTTL() time.Duration {
+	return time.Duration(GetEnvInt("SCORM_SESSION_TTL_HOURS", 8)) * time.Hour
+}
+
+// ScormContentOrigin - origin riêng (vd: https://scorm.example-content.com) phục vụ phiên học SCORM:
+// player, runtime API và nội dung package. Script trong package do instructor upload chạy trên origin này
+// nên không đọc được token lưu trong trình duyệt của ứng dụng. Trả về false khi chưa cấu hình.
+func ScormContentOrigin() (string, bool) {
+	origin := strings.TrimSuffix(GetEnv("SCORM_CONTENT_ORIGIN", ""), "/")
+	return origin, origin != ""
+}
+
+// Mục đích của signed URL, nằm trong nội dung ký để chữ ký cấp cho mục đích này
+// không dùng được cho mục đích khác (vd: URL stream video không mở được nội dung SCORM)
+const (
+	MediaPurposeVideo    = "media"
+	MediaPurposeSubtitle = "subtitle"
+	MediaPurposeScorm    = "scorm"
+)
+
+// SignMediaURL tạo chữ ký HMAC-SHA256 gắn với mục đích, user, lesson và thời điểm hết hạn
+func SignMediaURL(purpose string, userId, lessonId uint, expiresAt int64) string {
+	mac := hmac.New(sha256.New, MediaSigningSecret)
+	mac.Write([]byte(fmt.Sprintf("%s:%d:%d:%d", purpose, userId, lessonId, expiresAt)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyMediaSignature kiểm tra chữ ký, mục đích và thời hạn của signed URL
+func VerifyMediaSignature(purpose string, userId, lessonId uint, expiresAt int64, signature string) bool {
+	if time.Now().Unix() > expiresAt {
+		return false
+	}
+
+	expected := SignMediaURL(purpose, userId, lessonId, expiresAt)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
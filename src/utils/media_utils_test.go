@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVerifyMediaSignature(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour).Unix()
+	signature := SignMediaURL(MediaPurposeVideo, 7, 42, expiresAt)
+
+	if len(signature) != 64 || SignMediaURL(MediaPurposeVideo, 7, 42, expiresAt) != signature {
+		t.Fatalf("signature %q must be a stable hex HMAC-SHA256", signature)
+	}
+	if !VerifyMediaSignature(MediaPurposeVideo, 7, 42, expiresAt, signature) {
+		t.Fatal("valid signature rejected")
+	}
+
+	expired := time.Now().Add(-time.Second).Unix()
+	tests := []struct {
+		name      string
+		purpose   string
+		userId    uint
+		lessonId  uint
+		expiresAt int64
+		signature string
+	}{
+		{"other user", MediaPurposeVideo, 8, 42, expiresAt, signature},
+		{"other lesson", MediaPurposeVideo, 7, 43, expiresAt, signature},
+		{"extended expiry", MediaPurposeVideo, 7, 42, expiresAt + 3600, signature},
+		{"expired", MediaPurposeVideo, 7, 42, expired, SignMediaURL(MediaPurposeVideo, 7, 42, expired)},
+		{"tampered", MediaPurposeVideo, 7, 42, expiresAt, strings.Repeat("0", 64)},
+		{"uppercase", MediaPurposeVideo, 7, 42, expiresAt, strings.ToUpper(signature)},
+		{"truncated", MediaPurposeVideo, 7, 42, expiresAt, signature[:32]},
+		{"empty", MediaPurposeVideo, 7, 42, expiresAt, ""},
+		{"scorm session", MediaPurposeScorm, 7, 42, expiresAt, signature},
+		{"subtitle", MediaPurposeSubtitle, 7, 42, expiresAt, signature},
+	}
+	for _, tt := range tests {
+		if VerifyMediaSignature(tt.purpose, tt.userId, tt.lessonId, tt.expiresAt, tt.signature) {
+			t.Errorf("%s: signature accepted", tt.name)
+		}
+	}
+}
+
+func TestSignMediaURLUsesSecret(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour).Unix()
+	signature := SignMediaURL(MediaPurposeVideo, 7, 42, expiresAt)
+
+	original := MediaSigningSecret
+	defer func() { MediaSigningSecret = original }()
+	MediaSigningSecret = []byte("rotated-secret")
+
+	if VerifyMediaSignature(MediaPurposeVideo, 7, 42, expiresAt, signature) {
+		t.Error("signature made with the old secret accepted after rotation")
+	}
+}
+
+// "1:23:4" và "12:3:4" không được trùng nội dung ký
+func TestSignMediaURLFieldsAreDelimited(t *testing.T) {
+	if SignMediaURL(MediaPurposeVideo, 1, 23, 4) == SignMediaURL(MediaPurposeVideo, 12, 3, 4) {
+		t.Error("different user/lesson pairs produced the same signature")
+	}
+}
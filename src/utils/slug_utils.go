@@ -2,6 +2,7 @@ package utils
 
 import (
 	"regexp"
+	"strconv"
 	"strings"
 	"unicode"
 
@@ -74,7 +75,7 @@ func GenerateUniqueSlug(baseSlug string, existingSlugCheck func(string) bool) st
 	counter := 1
 
 	for existingSlugCheck(slug) {
-		slug = baseSlug + "-" + string(rune(counter))
+		slug = baseSlug + "-" + strconv.Itoa(counter)
 		counter++
 	}
 
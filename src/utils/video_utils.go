@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"math"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrFFprobeNotFound - server không cài ffprobe, bỏ qua việc tự động lấy thời lượng video
+var ErrFFprobeNotFound = errors.New("ffprobe not found in PATH")
+
+// ProbeVideoDuration dùng ffprobe lấy thời lượng video (giây), input là đường dẫn file hoặc URL (signed URL của storage)
+func ProbeVideoDuration(input string) (int, error) {
+	ffprobePath, err := exec.LookPath(GetEnv("FFPROBE_PATH", "ffprobe"))
+	if err != nil {
+		return 0, ErrFFprobeNotFound
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, ffprobePath,
+		"-v", "error",
+		"-show_entries", "format=duration",
+		"-of", "default=noprint_wrappers=1:nokey=1",
+		input,
+	).Output()
+	if err != nil {
+		return 0, err
+	}
+
+	duration, err := strconv.ParseFloat(strings.TrimSpace(string(output)), 64)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(math.Round(duration)), nil
+}
@@ -117,9 +117,12 @@ func RegisterCustomValidation(v *validator.Validate) {
 	v.RegisterValidation("course_status", func(fl validator.FieldLevel) bool {
 		status := fl.Field().String()
 		validStatuses := map[string]bool{
-			"draft":     true,
-			"published": true,
-			"archived":  true,
+			"draft":          true,
+			"pending_review": true,
+			"scheduled":      true,
+			"published":      true,
+			"rejected":       true,
+			"archived":       true,
 		}
 		return validStatuses[status]
 	})
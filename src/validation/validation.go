@@ -86,7 +86,7 @@ func HandlerValidationErrors(err error) gin.H {
 			case "course_level":
 				errors[fieldPath] = fmt.Sprintf("%s must be one of: beginner, intermediate, advanced", fieldPath)
 			case "course_status":
-				errors[fieldPath] = fmt.Sprintf("%s must be one of: draft, published, archived", fieldPath)
+				errors[fieldPath] = fmt.Sprintf("%s must be one of: draft, pending_review, scheduled, published, rejected, archived", fieldPath)
 			case "language_code":
 				errors[fieldPath] = fmt.Sprintf("%s must be a valid language code (vi, en)", fieldPath)
 			case "positive_float":